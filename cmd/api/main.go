@@ -8,13 +8,16 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata" // FACILITY_TIMEZONE must load on hosts without a zoneinfo database
 
 	"github.com/gin-gonic/gin"
 	"github.com/minhtran/his/internal/config"
 	"github.com/minhtran/his/internal/handler"
 	"github.com/minhtran/his/internal/middleware"
+	"github.com/minhtran/his/internal/pkg/clock"
 	"github.com/minhtran/his/internal/pkg/jwt"
 	"github.com/minhtran/his/internal/pkg/logger"
+	"github.com/minhtran/his/internal/pkg/notify"
 	"github.com/minhtran/his/internal/repository"
 	"github.com/minhtran/his/internal/service"
 	"go.uber.org/zap"
@@ -40,8 +43,11 @@ func main() {
 		zap.String("mode", cfg.Server.Mode),
 	)
 
+	// Dates, times and record codes follow the facility's time zone
+	facilityClock := clock.New(cfg.Facility.Location)
+
 	// Initialize database
-	db, err := config.InitDatabase(&cfg.Database, cfg.Log.Level)
+	db, err := config.InitDatabase(&cfg.Database, cfg.Log.Level, facilityClock)
 	if err != nil {
 		logger.Fatal("Failed to connect to database", zap.Error(err))
 	}
@@ -80,36 +86,108 @@ func main() {
 	invoiceRepo := repository.NewInvoiceRepository(db)
 	paymentRepo := repository.NewPaymentRepository(db)
 	insuranceClaimRepo := repository.NewInsuranceClaimRepository(db)
+	insurancePayerRepo := repository.NewInsurancePayerRepository(db)
+	coverageRepo := repository.NewPatientCoverageRepository(db)
+	patientImportRepo := repository.NewPatientImportRepository(db)
 	departmentRepo := repository.NewDepartmentRepository(db)
 	medicalServiceRepo := repository.NewMedicalServiceRepository(db)
 	auditLogRepo := repository.NewAuditLogRepository(db)
+	deathRecordRepo := repository.NewDeathRecordRepository(db)
+	labelTemplateRepo := repository.NewLabelTemplateRepository(db)
+	portalAccountRepo := repository.NewPatientPortalAccountRepository(db)
+	doctorScheduleRepo := repository.NewDoctorScheduleRepository(db)
+	appointmentSeriesRepo := repository.NewAppointmentSeriesRepository(db)
+	waitlistRepo := repository.NewWaitlistRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	resourceRepo := repository.NewResourceRepository(db)
+	calendarFeedRepo := repository.NewCalendarFeedRepository(db)
+	bookingHoldRepo := repository.NewBookingHoldRepository(db)
+	queueRepo := repository.NewQueueRepository(db)
+	triageRepo := repository.NewTriageRepository(db)
+	clinicalNoteRepo := repository.NewClinicalNoteRepository(db)
+	referralRepo := repository.NewReferralRepository(db)
+	followUpRepo := repository.NewFollowUpRepository(db)
 
 	// Initialize services
 	authService := service.NewAuthService(userRepo, jwtManager)
 	userService := service.NewUserService(userRepo, db)
-	patientService := service.NewPatientService(patientRepo)
+	patientService := service.NewPatientService(patientRepo, facilityClock)
 	allergyService := service.NewPatientAllergyService(allergyRepo, patientRepo)
 	historyService := service.NewPatientMedicalHistoryService(historyRepo, patientRepo)
-	appointmentService := service.NewAppointmentService(appointmentRepo, patientRepo, userRepo)
-	visitService := service.NewVisitService(visitRepo, patientRepo, userRepo, appointmentRepo)
+	noShowService := service.NewNoShowService(appointmentRepo, patientRepo, userRepo, service.NoShowPolicy{
+		GracePeriod:          cfg.NoShow.GracePeriod,
+		RestrictThreshold:    cfg.NoShow.RestrictThreshold,
+		RestrictWindowDays:   cfg.NoShow.RestrictWindowDays,
+		RestrictPortalOnly:   cfg.NoShow.RestrictPortalOnly,
+		OverbookLookbackDays: cfg.NoShow.OverbookLookbackDays,
+		OverbookMinSample:    cfg.NoShow.OverbookMinSample,
+		OverbookMaxPercent:   cfg.NoShow.OverbookMaxPercent,
+		OverbookMaxPerSlot:   cfg.NoShow.OverbookMaxPerSlot,
+	}, facilityClock)
+	appointmentService := service.NewAppointmentService(appointmentRepo, patientRepo, userRepo, doctorScheduleRepo, resourceRepo, departmentRepo, noShowService, facilityClock)
+	visitService := service.NewVisitService(visitRepo, patientRepo, userRepo, appointmentRepo, coverageRepo, appointmentService, facilityClock)
 	icd10Service := service.NewICD10CodeService(icd10Repo)
-	diagnosisService := service.NewDiagnosisService(diagnosisRepo, icd10Repo, visitRepo, patientRepo)
+	diagnosisService := service.NewDiagnosisService(diagnosisRepo, icd10Repo, visitRepo, patientRepo, facilityClock)
 	medicationService := service.NewMedicationService(medicationRepo)
-	prescriptionService := service.NewPrescriptionService(prescriptionRepo, prescriptionItemRepo, medicationRepo, visitRepo)
+	prescriptionService := service.NewPrescriptionService(prescriptionRepo, prescriptionItemRepo, medicationRepo, visitRepo, facilityClock)
 	labTestTemplateService := service.NewLabTestTemplateService(labTestTemplateRepo)
-	labTestRequestService := service.NewLabTestRequestService(labTestRequestRepo, labTestResultRepo, labTestTemplateRepo, visitRepo)
+	labTestRequestService := service.NewLabTestRequestService(labTestRequestRepo, labTestResultRepo, labTestTemplateRepo, visitRepo, facilityClock)
 	imagingTemplateService := service.NewImagingTemplateService(imagingTemplateRepo)
-	imagingRequestService := service.NewImagingRequestService(imagingRequestRepo, imagingResultRepo, imagingTemplateRepo, visitRepo)
+	imagingRequestService := service.NewImagingRequestService(imagingRequestRepo, imagingResultRepo, imagingTemplateRepo, visitRepo, resourceRepo, facilityClock)
 	bedService := service.NewBedService(bedRepo)
-	admissionService := service.NewAdmissionService(admissionRepo, bedAllocationRepo, bedRepo, visitRepo, nursingNoteRepo)
-	inventoryService := service.NewInventoryService(inventoryRepo)
-	dispensingService := service.NewDispensingService(dispensingRepo, inventoryRepo, prescriptionRepo, db)
-	invoiceService := service.NewInvoiceService(invoiceRepo)
-	paymentService := service.NewPaymentService(paymentRepo, invoiceRepo)
-	insuranceClaimService := service.NewInsuranceClaimService(insuranceClaimRepo, invoiceRepo)
+	admissionService := service.NewAdmissionService(admissionRepo, bedAllocationRepo, bedRepo, visitRepo, nursingNoteRepo, service.EarlyWarningPolicy{
+		UrgentScore:    cfg.EarlyWarning.UrgentScore,
+		EmergencyScore: cfg.EarlyWarning.EmergencyScore,
+		EscalateOnRed:  cfg.EarlyWarning.EscalateOnRed,
+	}, facilityClock)
+	inventoryService := service.NewInventoryService(inventoryRepo, facilityClock)
+	dispensingService := service.NewDispensingService(dispensingRepo, inventoryRepo, prescriptionRepo, db, facilityClock)
+	invoiceService := service.NewInvoiceService(invoiceRepo, facilityClock)
+	paymentService := service.NewPaymentService(paymentRepo, invoiceRepo, facilityClock)
+	insuranceClaimService := service.NewInsuranceClaimService(insuranceClaimRepo, invoiceRepo, coverageRepo, facilityClock)
+	insurancePayerService := service.NewInsurancePayerService(insurancePayerRepo, auditLogRepo)
+	coverageService := service.NewPatientCoverageService(coverageRepo, patientRepo, insurancePayerRepo, facilityClock)
+	patientImportService := service.NewPatientImportService(patientImportRepo, patientRepo, patientService, facilityClock)
 	auditLogService := service.NewAuditLogService(auditLogRepo)
 	departmentService := service.NewDepartmentService(departmentRepo, auditLogRepo)
 	medicalServiceService := service.NewMedicalServiceService(medicalServiceRepo, auditLogRepo)
+	deathRecordService := service.NewDeathRecordService(deathRecordRepo, patientRepo, userRepo, icd10Repo, admissionRepo, appointmentService, db, cfg.Facility.Name, facilityClock)
+	labelService := service.NewLabelService(labelTemplateRepo, patientRepo, allergyRepo, admissionRepo, labTestRequestRepo, auditLogRepo, cfg.Facility.Name)
+	notificationDispatcher, err := newNotificationDispatcher(cfg.Notification)
+	if err != nil {
+		logger.Fatal("Failed to set up notification providers", zap.Error(err))
+	}
+	otpSender := service.NewSMSOTPSender(notificationDispatcher, cfg.Facility.Name)
+	portalAccountService := service.NewPortalAccountService(portalAccountRepo, patientRepo, auditLogRepo, jwtManager, otpSender, facilityClock)
+	appointmentSeriesService := service.NewAppointmentSeriesService(appointmentSeriesRepo, appointmentRepo, patientRepo, userRepo, appointmentService, facilityClock)
+	waitlistService := service.NewWaitlistService(waitlistRepo, appointmentRepo, patientRepo, userRepo, departmentRepo, appointmentService, cfg.Waitlist.OfferHold, facilityClock)
+	doctorScheduleService := service.NewDoctorScheduleService(doctorScheduleRepo, userRepo, appointmentRepo, auditLogRepo, facilityClock)
+	notificationService := service.NewNotificationService(notificationRepo, appointmentRepo, auditLogRepo, appointmentService, admissionService, notificationDispatcher, cfg.Facility.Name, cfg.Notification.ReminderOffsets, cfg.Notification.MaxAttempts, facilityClock)
+	resourceService := service.NewResourceService(resourceRepo, departmentRepo, auditLogRepo, facilityClock)
+	calendarFeedService := service.NewCalendarFeedService(calendarFeedRepo, appointmentRepo, userRepo, auditLogRepo, service.CalendarFeedSettings{
+		BaseURL:       cfg.Calendar.BaseURL,
+		FacilityName:  cfg.Facility.Name,
+		PatientDetail: cfg.Calendar.PatientDetail,
+		PastDays:      cfg.Calendar.PastDays,
+		FutureDays:    cfg.Calendar.FutureDays,
+	}, facilityClock)
+	triageService := service.NewTriageService(triageRepo, visitRepo, auditLogRepo, cfg.Triage.Scale, facilityClock)
+	clinicalNoteService := service.NewClinicalNoteService(clinicalNoteRepo, visitRepo, userRepo, auditLogRepo, facilityClock)
+	referralService := service.NewReferralService(referralRepo, visitRepo, userRepo, departmentRepo, diagnosisRepo, labTestRequestRepo, imagingRequestRepo, auditLogRepo, appointmentService, visitService, cfg.Facility.Name, facilityClock)
+	followUpService := service.NewFollowUpService(followUpRepo, visitRepo, patientRepo, userRepo, auditLogRepo, appointmentService, visitService, service.FollowUpPolicy{
+		SearchDays:      cfg.FollowUp.SearchDays,
+		MissedAfterDays: cfg.FollowUp.MissedAfterDays,
+	}, facilityClock)
+	visitCompletionService := service.NewVisitCompletionService(visitRepo, diagnosisRepo, labTestRequestRepo, imagingRequestRepo, prescriptionRepo, clinicalNoteRepo, medicalServiceRepo, invoiceRepo, auditLogRepo, invoiceService, visitService, service.VisitCompletionPolicy{
+		RequirePrimaryDiagnosis:   cfg.VisitCompletion.RequirePrimaryDiagnosis,
+		RequireVitals:             cfg.VisitCompletion.RequireVitals,
+		RequireOrdersAcknowledged: cfg.VisitCompletion.RequireOrdersAcknowledged,
+		RequireNotesSigned:        cfg.VisitCompletion.RequireNotesSigned,
+		ConsultationServiceCode:   cfg.VisitCompletion.ConsultationServiceCode,
+	})
+	queueService := service.NewQueueService(queueRepo, visitRepo, userRepo, resourceRepo, departmentRepo, auditLogRepo, visitService, triageService, facilityClock)
+	publicBookingService := service.NewPublicBookingService(bookingHoldRepo, appointmentRepo, patientRepo, userRepo, departmentRepo, auditLogRepo, appointmentService, otpSender, facilityClock)
+	portalService := service.NewPortalService(portalAccountRepo, appointmentRepo, appointmentService, labTestRequestService, imagingRequestService, prescriptionService, invoiceService, facilityClock)
 
 	// Initialize handlers
 	authHandler := handler.NewAuthHandler(authService)
@@ -134,9 +212,30 @@ func main() {
 	invoiceHandler := handler.NewInvoiceHandler(invoiceService)
 	paymentHandler := handler.NewPaymentHandler(paymentService)
 	insuranceClaimHandler := handler.NewInsuranceClaimHandler(insuranceClaimService)
+	insurancePayerHandler := handler.NewInsurancePayerHandler(insurancePayerService)
+	coverageHandler := handler.NewPatientCoverageHandler(coverageService)
+	patientImportHandler := handler.NewPatientImportHandler(patientImportService)
 	departmentHandler := handler.NewDepartmentHandler(departmentService)
 	medicalServiceHandler := handler.NewMedicalServiceHandler(medicalServiceService)
 	auditLogHandler := handler.NewAuditLogHandler(auditLogService)
+	deathRecordHandler := handler.NewDeathRecordHandler(deathRecordService)
+	labelHandler := handler.NewLabelHandler(labelService)
+	portalAccountHandler := handler.NewPortalAccountHandler(portalAccountService)
+	portalHandler := handler.NewPortalHandler(portalAccountService, portalService)
+	doctorScheduleHandler := handler.NewDoctorScheduleHandler(doctorScheduleService)
+	appointmentSeriesHandler := handler.NewAppointmentSeriesHandler(appointmentSeriesService)
+	waitlistHandler := handler.NewWaitlistHandler(waitlistService)
+	notificationHandler := handler.NewNotificationHandler(notificationService)
+	resourceHandler := handler.NewResourceHandler(resourceService)
+	noShowHandler := handler.NewNoShowHandler(noShowService)
+	calendarFeedHandler := handler.NewCalendarFeedHandler(calendarFeedService)
+	publicBookingHandler := handler.NewPublicBookingHandler(publicBookingService)
+	queueHandler := handler.NewQueueHandler(queueService)
+	triageHandler := handler.NewTriageHandler(triageService)
+	clinicalNoteHandler := handler.NewClinicalNoteHandler(clinicalNoteService)
+	referralHandler := handler.NewReferralHandler(referralService)
+	followUpHandler := handler.NewFollowUpHandler(followUpService)
+	visitCompletionHandler := handler.NewVisitCompletionHandler(visitCompletionService)
 
 	// Initialize middleware
 	rbacMiddleware := middleware.NewRBACMiddleware(userRepo)
@@ -148,7 +247,7 @@ func main() {
 	router := gin.New()
 
 	// Setup routes
-	handler.SetupRoutes(router, authHandler, userHandler, patientHandler, allergyHandler, historyHandler, appointmentHandler, visitHandler, icd10Handler, diagnosisHandler, medicationHandler, prescriptionHandler, labTestTemplateHandler, labTestRequestHandler, imagingTemplateHandler, imagingRequestHandler, bedHandler, admissionHandler, inventoryHandler, dispensingHandler, invoiceHandler, paymentHandler, insuranceClaimHandler, departmentHandler, medicalServiceHandler, auditLogHandler, jwtManager, rbacMiddleware, cfg.Server.AllowedOrigins)
+	handler.SetupRoutes(router, authHandler, userHandler, patientHandler, allergyHandler, historyHandler, appointmentHandler, visitHandler, icd10Handler, diagnosisHandler, medicationHandler, prescriptionHandler, labTestTemplateHandler, labTestRequestHandler, imagingTemplateHandler, imagingRequestHandler, bedHandler, admissionHandler, inventoryHandler, dispensingHandler, invoiceHandler, paymentHandler, insuranceClaimHandler, departmentHandler, medicalServiceHandler, auditLogHandler, deathRecordHandler, insurancePayerHandler, coverageHandler, patientImportHandler, labelHandler, portalAccountHandler, portalHandler, doctorScheduleHandler, appointmentSeriesHandler, waitlistHandler, notificationHandler, resourceHandler, noShowHandler, calendarFeedHandler, publicBookingHandler, queueHandler, triageHandler, clinicalNoteHandler, referralHandler, followUpHandler, visitCompletionHandler, jwtManager, rbacMiddleware, cfg.Server.AllowedOrigins)
 
 	// Create HTTP server
 	srv := &http.Server{
@@ -159,6 +258,14 @@ func main() {
 		MaxHeaderBytes: 1 << 20, // 1 MB
 	}
 
+	// Move expired waitlist offers on, deliver notifications and settle follow-ups in the background
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	go waitlistService.Run(workerCtx, cfg.Waitlist.SweepInterval)
+	go notificationService.Run(workerCtx, cfg.Notification.DispatchInterval)
+	go noShowService.Run(workerCtx, cfg.NoShow.SweepInterval)
+	go followUpService.Run(workerCtx, cfg.FollowUp.SweepInterval)
+
 	// Start server in a goroutine
 	go func() {
 		logger.Info("Server starting", zap.String("port", cfg.Server.Port))
@@ -173,6 +280,7 @@ func main() {
 	<-quit
 
 	logger.Info("Shutting down server...")
+	stopWorkers()
 
 	// Graceful shutdown with 5 second timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -184,3 +292,29 @@ func main() {
 
 	logger.Info("Server exited")
 }
+
+// newNotificationDispatcher registers a provider for each notification channel:
+// the real provider when it is configured, otherwise a file outbox when
+// NOTIFY_OUTBOX_DIR is set, otherwise the log
+func newNotificationDispatcher(cfg config.NotificationConfig) (*notify.Dispatcher, error) {
+	var providers []notify.Provider
+	for _, channel := range []notify.Channel{notify.ChannelSMS, notify.ChannelEmail, notify.ChannelZalo} {
+		switch {
+		case channel == notify.ChannelSMS && cfg.SMS.GatewayURL != "":
+			providers = append(providers, notify.NewSMSProvider(cfg.SMS.GatewayURL, cfg.SMS.APIKey, cfg.SMS.Sender))
+		case channel == notify.ChannelEmail && cfg.SMTP.Host != "":
+			providers = append(providers, notify.NewEmailProvider(cfg.SMTP.Host, cfg.SMTP.Port, cfg.SMTP.Username, cfg.SMTP.Password, cfg.SMTP.From))
+		case channel == notify.ChannelZalo && cfg.Zalo.AccessToken != "":
+			providers = append(providers, notify.NewZaloProvider(cfg.Zalo.AccessToken, cfg.Zalo.TemplateID))
+		case cfg.OutboxDir != "":
+			p, err := notify.NewFileProvider(channel, cfg.OutboxDir)
+			if err != nil {
+				return nil, err
+			}
+			providers = append(providers, p)
+		default:
+			providers = append(providers, notify.NewLogProvider(channel))
+		}
+	}
+	return notify.NewDispatcher(providers...), nil
+}
@@ -2,6 +2,8 @@ package config
 
 import (
 	"fmt"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -13,6 +15,15 @@ type Config struct {
 	JWT      JWTConfig
 	Server   ServerConfig
 	Log      LogConfig
+	Facility FacilityConfig
+	Waitlist WaitlistConfig
+	Notification NotificationConfig
+	NoShow   NoShowConfig
+	Calendar CalendarConfig
+	Triage   TriageConfig
+	EarlyWarning EarlyWarningConfig
+	FollowUp FollowUpConfig
+	VisitCompletion VisitCompletionConfig
 }
 
 type DatabaseConfig struct {
@@ -47,6 +58,88 @@ type LogConfig struct {
 	Format string
 }
 
+type FacilityConfig struct {
+	Name     string
+	Location *time.Location // Time zone appointment dates and times are kept in
+}
+
+type WaitlistConfig struct {
+	OfferHold     time.Duration // How long a freed slot is held for a waitlisted patient
+	SweepInterval time.Duration // How often expired offers are moved on
+}
+
+type NoShowConfig struct {
+	GracePeriod          time.Duration // How long after its start an unattended appointment becomes a no-show
+	SweepInterval        time.Duration
+	RestrictThreshold    int  // No-shows within the window that restrict booking; 0 disables
+	RestrictWindowDays   int
+	RestrictPortalOnly   bool // Restrict only self-service portal and online bookings, leaving staff able to book
+	OverbookLookbackDays int
+	OverbookMinSample    int // Past appointments a doctor needs before overbooking is allowed
+	OverbookMaxPercent   int // Cap on extra bookings per session as a percentage of its limit; 0 disables
+	OverbookMaxPerSlot   int // Bookings one time slot may hold while its session is overbooked
+}
+
+type CalendarConfig struct {
+	BaseURL       string // Public URL feed links are built on
+	PatientDetail string // none, initials or full: how much of the patient calendar events show
+	PastDays      int
+	FutureDays    int
+}
+
+type TriageConfig struct {
+	Scale string // ESI or MTS: the ruleset acuity levels are computed with
+}
+
+type EarlyWarningConfig struct {
+	UrgentScore    int  // NEWS2 aggregate score that escalates to an urgent review
+	EmergencyScore int  // NEWS2 aggregate score that escalates to an emergency assessment
+	EscalateOnRed  bool // A single parameter scoring 3 escalates to an urgent review
+}
+
+type FollowUpConfig struct {
+	SearchDays      int // Days from the next visit date searched for a free slot with the same doctor
+	MissedAfterDays int // Days after its due date an unbooked follow-up counts as missed
+	SweepInterval   time.Duration
+}
+
+type VisitCompletionConfig struct {
+	RequirePrimaryDiagnosis   bool   // A visit cannot be completed without a primary diagnosis
+	RequireVitals             bool   // A visit cannot be completed without vital signs
+	RequireOrdersAcknowledged bool   // Lab and imaging orders still pending must be acknowledged when completing
+	RequireNotesSigned        bool   // A visit cannot be completed with draft or uncosigned clinical notes
+	ConsultationServiceCode   string // Service charged when the doctor's department has no consultation service
+}
+
+type NotificationConfig struct {
+	ReminderOffsets  []time.Duration // Lead times before an appointment at which reminders are sent
+	DispatchInterval time.Duration
+	MaxAttempts      int
+	OutboxDir        string // Channels without a provider write here when set, otherwise to the log
+	SMTP             SMTPConfig
+	SMS              SMSConfig
+	Zalo             ZaloConfig
+}
+
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+type SMSConfig struct {
+	GatewayURL string
+	APIKey     string
+	Sender     string
+}
+
+type ZaloConfig struct {
+	AccessToken string
+	TemplateID  string
+}
+
 // Load loads configuration from environment variables
 func Load() (*Config, error) {
 	viper.SetConfigFile(".env")
@@ -67,6 +160,122 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("invalid JWT_REFRESH_TOKEN_EXPIRY: %w", err)
 	}
 
+	// Parse waitlist durations
+	viper.SetDefault("WAITLIST_OFFER_HOLD", "2h")
+	viper.SetDefault("WAITLIST_SWEEP_INTERVAL", "1m")
+
+	offerHold, err := time.ParseDuration(viper.GetString("WAITLIST_OFFER_HOLD"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid WAITLIST_OFFER_HOLD: %w", err)
+	}
+
+	sweepInterval, err := time.ParseDuration(viper.GetString("WAITLIST_SWEEP_INTERVAL"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid WAITLIST_SWEEP_INTERVAL: %w", err)
+	}
+
+	// Parse notification settings
+	viper.SetDefault("NOTIFY_REMINDER_OFFSETS", "24h,2h")
+	viper.SetDefault("NOTIFY_DISPATCH_INTERVAL", "30s")
+	viper.SetDefault("NOTIFY_MAX_ATTEMPTS", 5)
+	viper.SetDefault("SMTP_PORT", 587)
+
+	var reminderOffsets []time.Duration
+	for _, v := range strings.Split(viper.GetString("NOTIFY_REMINDER_OFFSETS"), ",") {
+		if v = strings.TrimSpace(v); v == "" {
+			continue
+		}
+		offset, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid NOTIFY_REMINDER_OFFSETS: %w", err)
+		}
+		reminderOffsets = append(reminderOffsets, offset)
+	}
+
+	dispatchInterval, err := time.ParseDuration(viper.GetString("NOTIFY_DISPATCH_INTERVAL"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid NOTIFY_DISPATCH_INTERVAL: %w", err)
+	}
+
+	// Parse no-show settings
+	viper.SetDefault("NOSHOW_GRACE_PERIOD", "30m")
+	viper.SetDefault("NOSHOW_SWEEP_INTERVAL", "5m")
+	viper.SetDefault("NOSHOW_RESTRICT_THRESHOLD", 3)
+	viper.SetDefault("NOSHOW_RESTRICT_WINDOW_DAYS", 180)
+	viper.SetDefault("NOSHOW_RESTRICT_PORTAL_ONLY", true)
+	viper.SetDefault("NOSHOW_OVERBOOK_LOOKBACK_DAYS", 90)
+	viper.SetDefault("NOSHOW_OVERBOOK_MIN_SAMPLE", 30)
+	viper.SetDefault("NOSHOW_OVERBOOK_MAX_PERCENT", 0)
+	viper.SetDefault("NOSHOW_OVERBOOK_MAX_PER_SLOT", 2)
+
+	noShowGrace, err := time.ParseDuration(viper.GetString("NOSHOW_GRACE_PERIOD"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid NOSHOW_GRACE_PERIOD: %w", err)
+	}
+
+	noShowSweepInterval, err := time.ParseDuration(viper.GetString("NOSHOW_SWEEP_INTERVAL"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid NOSHOW_SWEEP_INTERVAL: %w", err)
+	}
+
+	// Parse facility time zone
+	viper.SetDefault("FACILITY_TIMEZONE", "Asia/Ho_Chi_Minh")
+
+	facilityLocation, err := time.LoadLocation(viper.GetString("FACILITY_TIMEZONE"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid FACILITY_TIMEZONE: %w", err)
+	}
+
+	// Parse calendar feed settings
+	viper.SetDefault("CALENDAR_PATIENT_DETAIL", "none")
+	viper.SetDefault("CALENDAR_PAST_DAYS", 30)
+	viper.SetDefault("CALENDAR_FUTURE_DAYS", 90)
+
+	calendarPatientDetail := strings.ToLower(viper.GetString("CALENDAR_PATIENT_DETAIL"))
+	switch calendarPatientDetail {
+	case "none", "initials", "full":
+	default:
+		return nil, fmt.Errorf("invalid CALENDAR_PATIENT_DETAIL: %q, use none, initials or full", calendarPatientDetail)
+	}
+
+	// Parse triage settings
+	viper.SetDefault("TRIAGE_SCALE", "ESI")
+
+	triageScale := strings.ToUpper(viper.GetString("TRIAGE_SCALE"))
+	switch triageScale {
+	case "ESI", "MTS":
+	default:
+		return nil, fmt.Errorf("invalid TRIAGE_SCALE: %q, use ESI or MTS", triageScale)
+	}
+
+	// Parse early warning escalation thresholds
+	viper.SetDefault("NEWS2_URGENT_SCORE", 5)
+	viper.SetDefault("NEWS2_EMERGENCY_SCORE", 7)
+	viper.SetDefault("NEWS2_ESCALATE_ON_RED", true)
+
+	news2Urgent := viper.GetInt("NEWS2_URGENT_SCORE")
+	news2Emergency := viper.GetInt("NEWS2_EMERGENCY_SCORE")
+	if news2Urgent < 1 || news2Emergency <= news2Urgent {
+		return nil, fmt.Errorf("invalid NEWS2 thresholds: urgent %d, emergency %d; emergency must exceed urgent", news2Urgent, news2Emergency)
+	}
+
+	// Parse follow-up settings
+	viper.SetDefault("FOLLOWUP_SEARCH_DAYS", 14)
+	viper.SetDefault("FOLLOWUP_MISSED_AFTER_DAYS", 30)
+	viper.SetDefault("FOLLOWUP_SWEEP_INTERVAL", "15m")
+
+	followUpSweepInterval, err := time.ParseDuration(viper.GetString("FOLLOWUP_SWEEP_INTERVAL"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid FOLLOWUP_SWEEP_INTERVAL: %w", err)
+	}
+
+	// Parse visit completion rules
+	viper.SetDefault("VISIT_REQUIRE_PRIMARY_DIAGNOSIS", true)
+	viper.SetDefault("VISIT_REQUIRE_VITALS", true)
+	viper.SetDefault("VISIT_REQUIRE_ORDERS_ACKNOWLEDGED", true)
+	viper.SetDefault("VISIT_REQUIRE_NOTES_SIGNED", true)
+	viper.SetDefault("VISIT_CONSULTATION_SERVICE_CODE", "")
+
 	config := &Config{
 		Database: DatabaseConfig{
 			Host:     viper.GetString("DB_HOST"),
@@ -95,6 +304,73 @@ func Load() (*Config, error) {
 			Level:  viper.GetString("LOG_LEVEL"),
 			Format: viper.GetString("LOG_FORMAT"),
 		},
+		Facility: FacilityConfig{
+			Name:     viper.GetString("FACILITY_NAME"),
+			Location: facilityLocation,
+		},
+		Waitlist: WaitlistConfig{
+			OfferHold:     offerHold,
+			SweepInterval: sweepInterval,
+		},
+		Notification: NotificationConfig{
+			ReminderOffsets:  reminderOffsets,
+			DispatchInterval: dispatchInterval,
+			MaxAttempts:      viper.GetInt("NOTIFY_MAX_ATTEMPTS"),
+			OutboxDir:        viper.GetString("NOTIFY_OUTBOX_DIR"),
+			SMTP: SMTPConfig{
+				Host:     viper.GetString("SMTP_HOST"),
+				Port:     viper.GetInt("SMTP_PORT"),
+				Username: viper.GetString("SMTP_USERNAME"),
+				Password: viper.GetString("SMTP_PASSWORD"),
+				From:     viper.GetString("SMTP_FROM"),
+			},
+			SMS: SMSConfig{
+				GatewayURL: viper.GetString("SMS_GATEWAY_URL"),
+				APIKey:     viper.GetString("SMS_API_KEY"),
+				Sender:     viper.GetString("SMS_SENDER"),
+			},
+			Zalo: ZaloConfig{
+				AccessToken: viper.GetString("ZALO_OA_ACCESS_TOKEN"),
+				TemplateID:  viper.GetString("ZALO_ZNS_TEMPLATE_ID"),
+			},
+		},
+		NoShow: NoShowConfig{
+			GracePeriod:          noShowGrace,
+			SweepInterval:        noShowSweepInterval,
+			RestrictThreshold:    viper.GetInt("NOSHOW_RESTRICT_THRESHOLD"),
+			RestrictWindowDays:   viper.GetInt("NOSHOW_RESTRICT_WINDOW_DAYS"),
+			RestrictPortalOnly:   viper.GetBool("NOSHOW_RESTRICT_PORTAL_ONLY"),
+			OverbookLookbackDays: viper.GetInt("NOSHOW_OVERBOOK_LOOKBACK_DAYS"),
+			OverbookMinSample:    viper.GetInt("NOSHOW_OVERBOOK_MIN_SAMPLE"),
+			OverbookMaxPercent:   viper.GetInt("NOSHOW_OVERBOOK_MAX_PERCENT"),
+			OverbookMaxPerSlot:   viper.GetInt("NOSHOW_OVERBOOK_MAX_PER_SLOT"),
+		},
+		Calendar: CalendarConfig{
+			BaseURL:       strings.TrimRight(viper.GetString("CALENDAR_BASE_URL"), "/"),
+			PatientDetail: calendarPatientDetail,
+			PastDays:      viper.GetInt("CALENDAR_PAST_DAYS"),
+			FutureDays:    viper.GetInt("CALENDAR_FUTURE_DAYS"),
+		},
+		Triage: TriageConfig{
+			Scale: triageScale,
+		},
+		EarlyWarning: EarlyWarningConfig{
+			UrgentScore:    news2Urgent,
+			EmergencyScore: news2Emergency,
+			EscalateOnRed:  viper.GetBool("NEWS2_ESCALATE_ON_RED"),
+		},
+		FollowUp: FollowUpConfig{
+			SearchDays:      viper.GetInt("FOLLOWUP_SEARCH_DAYS"),
+			MissedAfterDays: viper.GetInt("FOLLOWUP_MISSED_AFTER_DAYS"),
+			SweepInterval:   followUpSweepInterval,
+		},
+		VisitCompletion: VisitCompletionConfig{
+			RequirePrimaryDiagnosis:   viper.GetBool("VISIT_REQUIRE_PRIMARY_DIAGNOSIS"),
+			RequireVitals:             viper.GetBool("VISIT_REQUIRE_VITALS"),
+			RequireOrdersAcknowledged: viper.GetBool("VISIT_REQUIRE_ORDERS_ACKNOWLEDGED"),
+			RequireNotesSigned:        viper.GetBool("VISIT_REQUIRE_NOTES_SIGNED"),
+			ConsultationServiceCode:   viper.GetString("VISIT_CONSULTATION_SERVICE_CODE"),
+		},
 	}
 
 	// Validate required fields
@@ -125,13 +401,15 @@ func (c *Config) Validate() error {
 	return nil
 }
 
-// GetDSN returns the database connection string
-func (c *DatabaseConfig) GetDSN() string {
-	return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+// GetDSN returns the database connection string, reading and writing DATETIME
+// values in time zone loc
+func (c *DatabaseConfig) GetDSN(loc *time.Location) string {
+	return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=%s",
 		c.User,
 		c.Password,
 		c.Host,
 		c.Port,
 		c.DBName,
+		url.QueryEscape(loc.String()),
 	)
 }
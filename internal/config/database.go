@@ -5,14 +5,17 @@ import (
 	"log"
 	"time"
 
+	"github.com/minhtran/his/internal/pkg/clock"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
-// InitDatabase initializes the database connection
-func InitDatabase(cfg *DatabaseConfig, logLevel string) (*gorm.DB, error) {
-	dsn := cfg.GetDSN()
+// InitDatabase initializes the database connection. DATETIME values are read
+// and written in the clock's time zone, and the clock stamps records and the
+// record codes repositories generate.
+func InitDatabase(cfg *DatabaseConfig, logLevel string, clk *clock.Clock) (*gorm.DB, error) {
+	dsn := cfg.GetDSN(clk.Location())
 
 	// Configure GORM logger
 	var gormLogger logger.Interface
@@ -27,6 +30,7 @@ func InitDatabase(cfg *DatabaseConfig, logLevel string) (*gorm.DB, error) {
 		Logger:                 gormLogger,
 		SkipDefaultTransaction: true,
 		PrepareStmt:            true,
+		NowFunc:                clk.Now,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
@@ -13,6 +13,7 @@ const (
 	AdmissionStatusAdmitted    AdmissionStatus = "ADMITTED"
 	AdmissionStatusDischarged  AdmissionStatus = "DISCHARGED"
 	AdmissionStatusTransferred AdmissionStatus = "TRANSFERRED"
+	AdmissionStatusDeceased    AdmissionStatus = "DECEASED"
 )
 
 // Admission represents a patient admission
@@ -16,6 +16,15 @@ const (
 	AppointmentTypeCheckup      AppointmentType = "CHECKUP"
 )
 
+// BookingSource represents where an appointment was booked
+type BookingSource string
+
+const (
+	BookingSourceStaff  BookingSource = "STAFF"
+	BookingSourcePortal BookingSource = "PORTAL"
+	BookingSourceOnline BookingSource = "ONLINE" // Public booking site, without a portal account
+)
+
 // AppointmentStatus represents the status of an appointment
 type AppointmentStatus string
 
@@ -28,6 +37,35 @@ const (
 	AppointmentStatusNoShow     AppointmentStatus = "NO_SHOW"
 )
 
+// appointmentTransitions lists the statuses each status may move to.
+// COMPLETED, CANCELLED and NO_SHOW are final.
+var appointmentTransitions = map[AppointmentStatus][]AppointmentStatus{
+	AppointmentStatusScheduled: {
+		AppointmentStatusConfirmed,
+		AppointmentStatusInProgress,
+		AppointmentStatusCancelled,
+		AppointmentStatusNoShow,
+	},
+	AppointmentStatusConfirmed: {
+		AppointmentStatusInProgress,
+		AppointmentStatusCancelled,
+		AppointmentStatusNoShow,
+	},
+	AppointmentStatusInProgress: {
+		AppointmentStatusCompleted,
+	},
+}
+
+// CanTransitionTo reports whether an appointment may move from this status to next
+func (s AppointmentStatus) CanTransitionTo(next AppointmentStatus) bool {
+	for _, allowed := range appointmentTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
 // Appointment represents a patient appointment
 type Appointment struct {
 	ID        uint           `gorm:"primarykey" json:"id"`
@@ -61,12 +99,40 @@ type Appointment struct {
 	CancelledAt     *time.Time `json:"cancelled_at,omitempty"`
 	CancelledBy     *uint      `json:"cancelled_by,omitempty"`
 
+	// Booking Source
+	BookingSource   BookingSource `gorm:"size:20;not null;default:'STAFF'" json:"booking_source"`
+	PortalAccountID *uint         `gorm:"index" json:"portal_account_id,omitempty"` // Set for portal bookings
+
+	// Recurring Series
+	SeriesID    *uint `gorm:"index" json:"series_id,omitempty"`
+	SeriesIndex int   `gorm:"not null;default:0" json:"series_index,omitempty"` // 1-based occurrence number
+
 	// Audit fields
-	CreatedBy uint `gorm:"not null" json:"created_by"`
-	UpdatedBy uint `json:"updated_by"`
+	CreatedBy *uint `json:"created_by"` // Nil for portal bookings
+	UpdatedBy uint  `json:"updated_by"`
 }
 
 // TableName specifies the table name for Appointment model
 func (Appointment) TableName() string {
 	return "appointments"
 }
+
+// AppointmentStatusHistory records one status change of an appointment
+type AppointmentStatusHistory struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	AppointmentID uint              `gorm:"not null;index" json:"appointment_id"`
+	FromStatus    AppointmentStatus `gorm:"size:20;not null" json:"from_status"`
+	ToStatus      AppointmentStatus `gorm:"size:20;not null" json:"to_status"`
+	Reason        string            `gorm:"type:text" json:"reason,omitempty"`
+
+	ChangedAt time.Time `gorm:"not null" json:"changed_at"`
+	ChangedBy *uint     `json:"changed_by"` // Nil when the patient or the system made the change
+	User      *User     `gorm:"foreignKey:ChangedBy" json:"user,omitempty"`
+}
+
+// TableName specifies the table name for AppointmentStatusHistory model
+func (AppointmentStatusHistory) TableName() string {
+	return "appointment_status_history"
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SeriesStatus represents the status of an appointment series
+type SeriesStatus string
+
+const (
+	SeriesStatusActive    SeriesStatus = "ACTIVE"
+	SeriesStatusCancelled SeriesStatus = "CANCELLED"
+)
+
+// AppointmentSeries represents recurring appointments booked from one recurrence rule
+type AppointmentSeries struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+
+	// Foreign Keys
+	PatientID uint     `gorm:"not null;index" json:"patient_id"`
+	Patient   *Patient `gorm:"foreignKey:PatientID" json:"patient,omitempty"`
+
+	DoctorID uint  `gorm:"not null;index" json:"doctor_id"`
+	Doctor   *User `gorm:"foreignKey:DoctorID" json:"doctor,omitempty"`
+
+	// Recurrence (RRULE subset, e.g. FREQ=WEEKLY;BYDAY=MO,TH;COUNT=12)
+	RecurrenceRule  string    `gorm:"size:255;not null" json:"recurrence_rule"`
+	StartDate       time.Time `gorm:"type:date;not null" json:"start_date"`
+	AppointmentTime string    `gorm:"size:5;not null" json:"appointment_time"` // HH:MM
+	DurationMinutes int       `gorm:"not null;default:30" json:"duration_minutes"`
+
+	// Appointment Details copied to each occurrence
+	AppointmentType AppointmentType `gorm:"size:20;not null" json:"appointment_type"`
+	Reason          string          `gorm:"type:text" json:"reason"`
+	Notes           string          `gorm:"type:text" json:"notes"`
+
+	Status SeriesStatus `gorm:"size:20;not null;index;default:'ACTIVE'" json:"status"`
+
+	Appointments []*Appointment `gorm:"foreignKey:SeriesID" json:"appointments,omitempty"`
+
+	// Audit fields
+	CreatedBy uint `json:"created_by"`
+	UpdatedBy uint `json:"updated_by"`
+}
+
+// TableName specifies the table name for AppointmentSeries model
+func (AppointmentSeries) TableName() string {
+	return "appointment_series"
+}
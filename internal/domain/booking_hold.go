@@ -0,0 +1,62 @@
+package domain
+
+import "time"
+
+// BookingHoldStatus represents the status of a slot held during public online booking
+type BookingHoldStatus string
+
+const (
+	BookingHoldStatusPending   BookingHoldStatus = "PENDING"  // Waiting for the phone to be verified
+	BookingHoldStatusVerified  BookingHoldStatus = "VERIFIED" // Phone verified, waiting for confirmation
+	BookingHoldStatusConfirmed BookingHoldStatus = "CONFIRMED"
+	BookingHoldStatusReleased  BookingHoldStatus = "RELEASED" // Given up or replaced by a newer hold
+)
+
+// BookingHold represents a slot held for a few minutes while a visitor of the
+// public booking site verifies their phone number and confirms the booking.
+// Pending and verified holds block the slot until they expire.
+type BookingHold struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Token (only its SHA-256 is stored)
+	TokenHash string `gorm:"size:64;not null;uniqueIndex" json:"-"`
+
+	// Held slot
+	DoctorID        uint      `gorm:"not null;index" json:"doctor_id"`
+	Doctor          *User     `gorm:"foreignKey:DoctorID" json:"doctor,omitempty"`
+	AppointmentDate time.Time `gorm:"type:date;not null" json:"appointment_date"`
+	AppointmentTime time.Time `gorm:"type:time;not null" json:"appointment_time"`
+	DurationMinutes int       `gorm:"not null" json:"duration_minutes"`
+
+	// Requester
+	PhoneNumber string `gorm:"size:20;not null;index" json:"phone_number"`
+	ClientIP    string `gorm:"size:45;not null;index" json:"client_ip"`
+
+	// Phone verification
+	CodeHash   string     `gorm:"size:255" json:"-"`
+	CodeSentAt *time.Time `json:"code_sent_at,omitempty"`
+	CodesSent  int        `gorm:"default:0" json:"codes_sent"`
+	Attempts   int        `gorm:"default:0" json:"attempts"`
+	VerifiedAt *time.Time `json:"verified_at,omitempty"`
+
+	Status    BookingHoldStatus `gorm:"size:20;not null;index" json:"status"`
+	ExpiresAt time.Time         `gorm:"not null;index" json:"expires_at"`
+
+	// Result
+	PatientID     *uint        `json:"patient_id,omitempty"`
+	AppointmentID *uint        `gorm:"index" json:"appointment_id,omitempty"`
+	Appointment   *Appointment `gorm:"foreignKey:AppointmentID" json:"appointment,omitempty"`
+	ConfirmedAt   *time.Time   `json:"confirmed_at,omitempty"`
+}
+
+// TableName specifies the table name for BookingHold model
+func (BookingHold) TableName() string {
+	return "booking_holds"
+}
+
+// IsActive reports whether the hold still blocks its slot
+func (h *BookingHold) IsActive(now time.Time) bool {
+	return (h.Status == BookingHoldStatusPending || h.Status == BookingHoldStatusVerified) && now.Before(h.ExpiresAt)
+}
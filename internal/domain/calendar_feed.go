@@ -0,0 +1,33 @@
+package domain
+
+import "time"
+
+// CalendarFeed represents a user's secret iCalendar feed URL. Only a hash of
+// the token in the URL is kept; rotating a feed revokes it and issues a new one.
+type CalendarFeed struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Foreign Keys
+	UserID uint  `gorm:"not null;index" json:"user_id"`
+	User   *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+
+	// Token
+	TokenHash string `gorm:"uniqueIndex;size:64;not null" json:"-"` // Hex SHA-256 of the token
+
+	// Usage
+	LastAccessedAt *time.Time `json:"last_accessed_at,omitempty"`
+
+	// Revocation
+	RevokedAt *time.Time `gorm:"index" json:"revoked_at,omitempty"`
+	RevokedBy *uint      `json:"revoked_by,omitempty"`
+
+	// Audit fields
+	CreatedBy uint `json:"created_by"`
+}
+
+// TableName specifies the table name for CalendarFeed model
+func (CalendarFeed) TableName() string {
+	return "calendar_feeds"
+}
@@ -0,0 +1,146 @@
+package domain
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RoleCodeResident is the role of doctors in training, whose notes a
+// supervising doctor co-signs
+const RoleCodeResident = "RESIDENT"
+
+// ClinicalNoteType represents the kind of clinical note
+type ClinicalNoteType string
+
+const (
+	ClinicalNoteTypeConsultation ClinicalNoteType = "CONSULTATION" // Initial assessment
+	ClinicalNoteTypeProgress     ClinicalNoteType = "PROGRESS"
+	ClinicalNoteTypeProcedure    ClinicalNoteType = "PROCEDURE"
+	ClinicalNoteTypeDischarge    ClinicalNoteType = "DISCHARGE"
+)
+
+// ClinicalNoteStatus represents the signing status of a clinical note
+type ClinicalNoteStatus string
+
+const (
+	ClinicalNoteStatusDraft         ClinicalNoteStatus = "DRAFT"
+	ClinicalNoteStatusPendingCosign ClinicalNoteStatus = "PENDING_COSIGN" // Signed by a resident, awaiting the supervisor
+	ClinicalNoteStatusSigned        ClinicalNoteStatus = "SIGNED"
+)
+
+// IsLocked reports whether the note's sections can no longer be edited.
+// Only addenda can be added once a note is signed.
+func (s ClinicalNoteStatus) IsLocked() bool {
+	return s != ClinicalNoteStatusDraft
+}
+
+// SOAPSections holds the sections of a SOAP note
+type SOAPSections struct {
+	Subjective string `gorm:"type:text" json:"subjective"` // History and symptoms as reported
+	Objective  string `gorm:"type:text" json:"objective"`  // Examination and results
+	Assessment string `gorm:"type:text" json:"assessment"`
+	Plan       string `gorm:"type:text" json:"plan"`
+}
+
+// ClinicalNoteTemplate represents the default sections of a note type,
+// for one department or, without a department, for all
+type ClinicalNoteTemplate struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+
+	Code         string           `gorm:"uniqueIndex;size:50;not null" json:"code"`
+	Name         string           `gorm:"size:200;not null" json:"name"`
+	DepartmentID *uint            `gorm:"index" json:"department_id,omitempty"`
+	Department   *Department      `gorm:"foreignKey:DepartmentID" json:"department,omitempty"`
+	NoteType     ClinicalNoteType `gorm:"size:20;not null" json:"note_type"`
+
+	SOAPSections `gorm:"embedded"`
+
+	IsActive bool `gorm:"default:true" json:"is_active"`
+
+	// Audit fields
+	CreatedBy uint `json:"created_by"`
+	UpdatedBy uint `json:"updated_by"`
+}
+
+// TableName specifies the table name for ClinicalNoteTemplate model
+func (ClinicalNoteTemplate) TableName() string {
+	return "clinical_note_templates"
+}
+
+// ClinicalNote represents a SOAP note written for a visit. Drafts are edited
+// by their author, each save adding a version; signing locks the note.
+type ClinicalNote struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"` // Only drafts are deleted
+
+	VisitID   uint   `gorm:"not null;index" json:"visit_id"`
+	Visit     *Visit `gorm:"foreignKey:VisitID" json:"visit,omitempty"`
+	PatientID uint   `gorm:"not null;index" json:"patient_id"`
+
+	AuthorID     uint                  `gorm:"not null;index" json:"author_id"`
+	Author       *User                 `gorm:"foreignKey:AuthorID" json:"author,omitempty"`
+	DepartmentID *uint                 `gorm:"index" json:"department_id,omitempty"`
+	TemplateID   *uint                 `json:"template_id,omitempty"`
+	Template     *ClinicalNoteTemplate `gorm:"foreignKey:TemplateID" json:"template,omitempty"`
+
+	NoteType ClinicalNoteType   `gorm:"size:20;not null" json:"note_type"`
+	Status   ClinicalNoteStatus `gorm:"size:20;not null;index;default:'DRAFT'" json:"status"`
+	Version  int                `gorm:"not null;default:1" json:"version"` // Latest version number
+
+	SOAPSections `gorm:"embedded"`
+
+	// Signing
+	SignedAt   *time.Time `json:"signed_at,omitempty"`
+	CosignerID *uint      `gorm:"index" json:"cosigner_id,omitempty"`
+	Cosigner   *User      `gorm:"foreignKey:CosignerID" json:"cosigner,omitempty"`
+	CosignedAt *time.Time `json:"cosigned_at,omitempty"`
+
+	Addenda []*ClinicalNoteAddendum `gorm:"foreignKey:NoteID" json:"addenda,omitempty"`
+}
+
+// TableName specifies the table name for ClinicalNote model
+func (ClinicalNote) TableName() string {
+	return "clinical_notes"
+}
+
+// ClinicalNoteVersion represents the sections of a note as saved at one version
+type ClinicalNoteVersion struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	NoteID  uint `gorm:"not null;uniqueIndex:idx_clinical_note_versions_version" json:"note_id"`
+	Version int  `gorm:"not null;uniqueIndex:idx_clinical_note_versions_version" json:"version"`
+
+	SOAPSections `gorm:"embedded"`
+
+	EditedBy uint  `gorm:"not null" json:"edited_by"`
+	Editor   *User `gorm:"foreignKey:EditedBy" json:"editor,omitempty"`
+}
+
+// TableName specifies the table name for ClinicalNoteVersion model
+func (ClinicalNoteVersion) TableName() string {
+	return "clinical_note_versions"
+}
+
+// ClinicalNoteAddendum represents text appended to a signed note. Addenda
+// are never edited or removed.
+type ClinicalNoteAddendum struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	NoteID   uint   `gorm:"not null;index" json:"note_id"`
+	AuthorID uint   `gorm:"not null" json:"author_id"`
+	Author   *User  `gorm:"foreignKey:AuthorID" json:"author,omitempty"`
+	Content  string `gorm:"type:text;not null" json:"content"`
+}
+
+// TableName specifies the table name for ClinicalNoteAddendum model
+func (ClinicalNoteAddendum) TableName() string {
+	return "clinical_note_addenda"
+}
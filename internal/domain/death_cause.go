@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DeathCauseLine represents the line of the cause-of-death chain on the certificate
+type DeathCauseLine string
+
+const (
+	DeathCauseLineA            DeathCauseLine = "A"            // Immediate cause
+	DeathCauseLineB            DeathCauseLine = "B"            // Due to (or as a consequence of) A
+	DeathCauseLineC            DeathCauseLine = "C"            // Due to (or as a consequence of) B
+	DeathCauseLineD            DeathCauseLine = "D"            // Due to (or as a consequence of) C
+	DeathCauseLineContributing DeathCauseLine = "CONTRIBUTING" // Part II: other significant conditions
+)
+
+// DeathCause represents one entry of the cause-of-death chain
+type DeathCause struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+
+	// Foreign Keys
+	DeathRecordID uint         `gorm:"not null;index" json:"death_record_id"`
+	DeathRecord   *DeathRecord `gorm:"foreignKey:DeathRecordID" json:"death_record,omitempty"`
+
+	ICD10CodeID uint       `gorm:"not null" json:"icd10_code_id"`
+	ICD10Code   *ICD10Code `gorm:"foreignKey:ICD10CodeID" json:"icd10_code,omitempty"`
+
+	// Cause Details
+	Line          DeathCauseLine `gorm:"size:20;not null" json:"line"`
+	Description   string         `gorm:"size:255" json:"description"`   // Free-text wording as written by the doctor
+	OnsetInterval string         `gorm:"size:50" json:"onset_interval"` // e.g., "2 giờ", "5 năm"
+}
+
+// TableName specifies the table name for DeathCause model
+func (DeathCause) TableName() string {
+	return "death_causes"
+}
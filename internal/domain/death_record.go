@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PlaceOfDeath represents where the death occurred
+type PlaceOfDeath string
+
+const (
+	PlaceOfDeathHospital PlaceOfDeath = "HOSPITAL"
+	PlaceOfDeathHome     PlaceOfDeath = "HOME"
+	PlaceOfDeathTransit  PlaceOfDeath = "TRANSIT"
+	PlaceOfDeathOther    PlaceOfDeath = "OTHER"
+)
+
+// MannerOfDeath represents the manner of death
+type MannerOfDeath string
+
+const (
+	MannerOfDeathNatural      MannerOfDeath = "NATURAL"
+	MannerOfDeathAccident     MannerOfDeath = "ACCIDENT"
+	MannerOfDeathSuicide      MannerOfDeath = "SUICIDE"
+	MannerOfDeathHomicide     MannerOfDeath = "HOMICIDE"
+	MannerOfDeathUndetermined MannerOfDeath = "UNDETERMINED"
+)
+
+// DeathRecord represents the registered death of a patient
+type DeathRecord struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+
+	// Certificate Number (auto-generated: DC-YYYYMMDD-XXXX)
+	CertificateNumber string `gorm:"uniqueIndex;size:20;not null" json:"certificate_number"`
+
+	// Foreign Keys
+	PatientID uint     `gorm:"not null;uniqueIndex" json:"patient_id"` // One death record per patient
+	Patient   *Patient `gorm:"foreignKey:PatientID" json:"patient,omitempty"`
+
+	AdmissionID *uint      `gorm:"index" json:"admission_id,omitempty"` // Set when death occurred during an admission
+	Admission   *Admission `gorm:"foreignKey:AdmissionID" json:"admission,omitempty"`
+
+	CertifyingDoctorID uint  `gorm:"not null;index" json:"certifying_doctor_id"`
+	CertifyingDoctor   *User `gorm:"foreignKey:CertifyingDoctorID" json:"certifying_doctor,omitempty"`
+
+	UnderlyingCauseID uint       `gorm:"not null" json:"underlying_cause_id"` // Lowest line of Part I
+	UnderlyingCause   *ICD10Code `gorm:"foreignKey:UnderlyingCauseID" json:"underlying_cause,omitempty"`
+
+	// Death Details
+	DeathDateTime time.Time     `gorm:"not null;index" json:"death_date_time"`
+	PlaceOfDeath  PlaceOfDeath  `gorm:"size:20;not null" json:"place_of_death"`
+	PlaceDetails  string        `gorm:"size:255" json:"place_details"`
+	MannerOfDeath MannerOfDeath `gorm:"size:20;not null;default:'NATURAL'" json:"manner_of_death"`
+	Notes         string        `gorm:"type:text" json:"notes"`
+
+	// Cause-of-death chain (one-to-many)
+	Causes []*DeathCause `gorm:"foreignKey:DeathRecordID" json:"causes,omitempty"`
+
+	// Audit fields
+	CreatedBy uint `gorm:"not null" json:"created_by"`
+	UpdatedBy uint `json:"updated_by"`
+}
+
+// TableName specifies the table name for DeathRecord model
+func (DeathRecord) TableName() string {
+	return "death_records"
+}
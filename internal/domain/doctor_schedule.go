@@ -0,0 +1,136 @@
+package domain
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// LeaveType represents why a doctor is away
+type LeaveType string
+
+const (
+	LeaveTypeAnnual   LeaveType = "ANNUAL"
+	LeaveTypeSick     LeaveType = "SICK"
+	LeaveTypeTraining LeaveType = "TRAINING"
+	LeaveTypeOther    LeaveType = "OTHER"
+)
+
+// DoctorSchedule represents a recurring weekly clinic session of a doctor
+type DoctorSchedule struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+
+	// Foreign Keys
+	DoctorID uint  `gorm:"not null;index" json:"doctor_id"`
+	Doctor   *User `gorm:"foreignKey:DoctorID" json:"doctor,omitempty"`
+
+	// Session Details (times are HH:MM in facility local time)
+	DayOfWeek   int    `gorm:"not null" json:"day_of_week"` // 0 = Sunday ... 6 = Saturday
+	StartTime   string `gorm:"size:5;not null" json:"start_time"`
+	EndTime     string `gorm:"size:5;not null" json:"end_time"`
+	BreakStart  string `gorm:"size:5" json:"break_start,omitempty"`
+	BreakEnd    string `gorm:"size:5" json:"break_end,omitempty"`
+	Room        string `gorm:"size:50" json:"room"`
+	SlotMinutes int    `gorm:"not null;default:30" json:"slot_minutes"`
+	MaxPatients int    `gorm:"not null;default:0" json:"max_patients"` // 0 means no limit
+
+	// Validity
+	EffectiveFrom time.Time  `gorm:"type:date;not null" json:"effective_from"`
+	EffectiveTo   *time.Time `gorm:"type:date" json:"effective_to,omitempty"`
+	IsActive      bool       `gorm:"default:true;index" json:"is_active"`
+
+	// Audit fields
+	CreatedBy uint `json:"created_by"`
+	UpdatedBy uint `json:"updated_by"`
+}
+
+// TableName specifies the table name for DoctorSchedule model
+func (DoctorSchedule) TableName() string {
+	return "doctor_schedules"
+}
+
+// DoctorScheduleOverride replaces a doctor's weekly sessions on a single date.
+// An override with IsAvailable false marks the whole day as not working.
+type DoctorScheduleOverride struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+
+	// Foreign Keys
+	DoctorID uint  `gorm:"not null;index" json:"doctor_id"`
+	Doctor   *User `gorm:"foreignKey:DoctorID" json:"doctor,omitempty"`
+
+	OverrideDate time.Time `gorm:"type:date;not null;index" json:"override_date"`
+	IsAvailable  bool      `gorm:"not null" json:"is_available"`
+
+	// Session Details (only used when IsAvailable is true)
+	StartTime   string `gorm:"size:5" json:"start_time,omitempty"`
+	EndTime     string `gorm:"size:5" json:"end_time,omitempty"`
+	BreakStart  string `gorm:"size:5" json:"break_start,omitempty"`
+	BreakEnd    string `gorm:"size:5" json:"break_end,omitempty"`
+	Room        string `gorm:"size:50" json:"room"`
+	SlotMinutes int    `gorm:"not null;default:30" json:"slot_minutes"`
+	MaxPatients int    `gorm:"not null;default:0" json:"max_patients"`
+
+	Reason string `gorm:"size:255" json:"reason"`
+
+	// Audit fields
+	CreatedBy uint `json:"created_by"`
+	UpdatedBy uint `json:"updated_by"`
+}
+
+// TableName specifies the table name for DoctorScheduleOverride model
+func (DoctorScheduleOverride) TableName() string {
+	return "doctor_schedule_overrides"
+}
+
+// DoctorLeave represents a period during which a doctor takes no appointments
+type DoctorLeave struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+
+	// Foreign Keys
+	DoctorID uint  `gorm:"not null;index" json:"doctor_id"`
+	Doctor   *User `gorm:"foreignKey:DoctorID" json:"doctor,omitempty"`
+
+	StartDate time.Time `gorm:"type:date;not null" json:"start_date"`
+	EndDate   time.Time `gorm:"type:date;not null" json:"end_date"` // Inclusive
+	LeaveType LeaveType `gorm:"size:20;not null" json:"leave_type"`
+	Reason    string    `gorm:"size:255" json:"reason"`
+
+	// Audit fields
+	CreatedBy uint `json:"created_by"`
+	UpdatedBy uint `json:"updated_by"`
+}
+
+// TableName specifies the table name for DoctorLeave model
+func (DoctorLeave) TableName() string {
+	return "doctor_leaves"
+}
+
+// Holiday represents a facility-wide closure date
+type Holiday struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+
+	HolidayDate time.Time `gorm:"type:date;not null;index" json:"holiday_date"`
+	Name        string    `gorm:"size:200;not null" json:"name"`
+	IsRecurring bool      `gorm:"not null;default:false" json:"is_recurring"` // Repeats every year on the same day and month
+
+	// Audit fields
+	CreatedBy uint `json:"created_by"`
+	UpdatedBy uint `json:"updated_by"`
+}
+
+// TableName specifies the table name for Holiday model
+func (Holiday) TableName() string {
+	return "holidays"
+}
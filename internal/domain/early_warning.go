@@ -0,0 +1,220 @@
+package domain
+
+import "time"
+
+// NEWS2Risk represents the clinical risk band of a NEWS2 aggregate score
+type NEWS2Risk string
+
+const (
+	NEWS2RiskLow       NEWS2Risk = "LOW"        // Aggregate 0-4
+	NEWS2RiskLowMedium NEWS2Risk = "LOW_MEDIUM" // Aggregate 0-4 with 3 in a single parameter
+	NEWS2RiskMedium    NEWS2Risk = "MEDIUM"     // Aggregate 5-6
+	NEWS2RiskHigh      NEWS2Risk = "HIGH"       // Aggregate 7 or more
+)
+
+// NEWS2RedScore is the points a single parameter scores in its most abnormal band
+const NEWS2RedScore = 3
+
+// EscalationLevel represents how urgently a deteriorating patient is escalated
+type EscalationLevel string
+
+const (
+	EscalationNone      EscalationLevel = "NONE"
+	EscalationUrgent    EscalationLevel = "URGENT"    // Urgent review by the ward doctor
+	EscalationEmergency EscalationLevel = "EMERGENCY" // Emergency assessment by a critical care team
+)
+
+// Rank orders escalation levels from none (0) to emergency (2)
+func (l EscalationLevel) Rank() int {
+	switch l {
+	case EscalationUrgent:
+		return 1
+	case EscalationEmergency:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// NEWS2Score holds the points each parameter of the National Early Warning
+// Score 2 scores, nil when the parameter was not measured. Oxygen saturation
+// is scored on scale 1.
+type NEWS2Score struct {
+	RespiratoryRatePoints    *int `json:"respiratory_rate_points,omitempty"`
+	OxygenSaturationPoints   *int `json:"oxygen_saturation_points,omitempty"`
+	SupplementalOxygenPoints *int `json:"supplemental_oxygen_points,omitempty"`
+	SystolicBPPoints         *int `gorm:"column:systolic_bp_points" json:"systolic_bp_points,omitempty"`
+	HeartRatePoints          *int `json:"heart_rate_points,omitempty"`
+	ConsciousnessPoints      *int `json:"consciousness_points,omitempty"`
+	TemperaturePoints        *int `json:"temperature_points,omitempty"`
+
+	Total    int       `gorm:"not null" json:"total"`
+	Complete bool      `gorm:"not null" json:"complete"`  // All seven parameters were measured
+	RedScore bool      `gorm:"not null" json:"red_score"` // A single parameter scored 3
+	Risk     NEWS2Risk `gorm:"size:20;not null" json:"risk"`
+}
+
+// ScoreNEWS2 scores vital signs on NEWS2. Zero values, an unrecorded level of
+// consciousness and an unrecorded choice between supplemental oxygen and room
+// air count as not measured.
+func ScoreNEWS2(v VitalSigns) NEWS2Score {
+	var score NEWS2Score
+
+	if v.RespiratoryRate > 0 {
+		score.RespiratoryRatePoints = news2Points(respiratoryRatePoints(v.RespiratoryRate))
+	}
+	if v.OxygenSaturation > 0 {
+		score.OxygenSaturationPoints = news2Points(oxygenSaturationPoints(v.OxygenSaturation))
+	}
+	if v.SupplementalOxygen != nil {
+		if *v.SupplementalOxygen {
+			score.SupplementalOxygenPoints = news2Points(2)
+		} else {
+			score.SupplementalOxygenPoints = news2Points(0)
+		}
+	}
+	if v.BloodPressureSystolic > 0 {
+		score.SystolicBPPoints = news2Points(systolicBPPoints(v.BloodPressureSystolic))
+	}
+	if v.HeartRate > 0 {
+		score.HeartRatePoints = news2Points(heartRatePoints(v.HeartRate))
+	}
+	if rank := v.Consciousness.Rank(); rank == 0 {
+		score.ConsciousnessPoints = news2Points(0)
+	} else if rank > 0 {
+		score.ConsciousnessPoints = news2Points(NEWS2RedScore) // New confusion scores as V, P or U
+	}
+	if v.Temperature > 0 {
+		score.TemperaturePoints = news2Points(temperaturePoints(v.Temperature))
+	}
+
+	score.Complete = true
+	for _, points := range []*int{score.RespiratoryRatePoints, score.OxygenSaturationPoints, score.SupplementalOxygenPoints,
+		score.SystolicBPPoints, score.HeartRatePoints, score.ConsciousnessPoints, score.TemperaturePoints} {
+		if points == nil {
+			score.Complete = false
+			continue
+		}
+		score.Total += *points
+		if *points == NEWS2RedScore {
+			score.RedScore = true
+		}
+	}
+
+	switch {
+	case score.Total >= 7:
+		score.Risk = NEWS2RiskHigh
+	case score.Total >= 5:
+		score.Risk = NEWS2RiskMedium
+	case score.RedScore:
+		score.Risk = NEWS2RiskLowMedium
+	default:
+		score.Risk = NEWS2RiskLow
+	}
+	return score
+}
+
+func news2Points(points int) *int {
+	return &points
+}
+
+func respiratoryRatePoints(rate int) int {
+	switch {
+	case rate <= 8:
+		return 3
+	case rate <= 11:
+		return 1
+	case rate <= 20:
+		return 0
+	case rate <= 24:
+		return 2
+	default:
+		return 3
+	}
+}
+
+func oxygenSaturationPoints(spo2 int) int {
+	switch {
+	case spo2 <= 91:
+		return 3
+	case spo2 <= 93:
+		return 2
+	case spo2 <= 95:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func systolicBPPoints(systolic int) int {
+	switch {
+	case systolic <= 90:
+		return 3
+	case systolic <= 100:
+		return 2
+	case systolic <= 110:
+		return 1
+	case systolic <= 219:
+		return 0
+	default:
+		return 3
+	}
+}
+
+func heartRatePoints(rate int) int {
+	switch {
+	case rate <= 40:
+		return 3
+	case rate <= 50:
+		return 1
+	case rate <= 90:
+		return 0
+	case rate <= 110:
+		return 1
+	case rate <= 130:
+		return 2
+	default:
+		return 3
+	}
+}
+
+func temperaturePoints(temperature float64) int {
+	switch {
+	case temperature <= 35.0:
+		return 3
+	case temperature <= 36.0:
+		return 1
+	case temperature <= 38.0:
+		return 0
+	case temperature <= 39.0:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// EarlyWarningScore represents a NEWS2 score recorded for an admitted patient,
+// one per nursing note with vital signs
+type EarlyWarningScore struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	AdmissionID   uint       `gorm:"not null;index:idx_early_warning_scores_admission" json:"admission_id"`
+	Admission     *Admission `gorm:"foreignKey:AdmissionID" json:"admission,omitempty"`
+	PatientID     uint       `gorm:"not null;index" json:"patient_id"`
+	NursingNoteID *uint      `gorm:"uniqueIndex" json:"nursing_note_id,omitempty"`
+
+	RecordedAt time.Time `gorm:"not null;index:idx_early_warning_scores_admission" json:"recorded_at"`
+	RecordedBy uint      `gorm:"not null" json:"recorded_by"`
+	Recorder   *User     `gorm:"foreignKey:RecordedBy" json:"recorder,omitempty"`
+
+	NEWS2Score `gorm:"embedded"`
+
+	EscalationLevel EscalationLevel `gorm:"size:20;not null" json:"escalation_level"`
+	Escalated       bool            `gorm:"not null" json:"escalated"` // The level rose above the previous score's and staff were notified
+}
+
+// TableName specifies the table name for EarlyWarningScore model
+func (EarlyWarningScore) TableName() string {
+	return "early_warning_scores"
+}
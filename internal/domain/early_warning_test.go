@@ -0,0 +1,45 @@
+package domain
+
+import "testing"
+
+func TestScoreNEWS2SupplementalOxygen(t *testing.T) {
+	onOxygen, roomAir := true, false
+	vitals := func(oxygen *bool) VitalSigns {
+		return VitalSigns{
+			Temperature:           37.0,
+			BloodPressureSystolic: 120,
+			HeartRate:             75,
+			RespiratoryRate:       16,
+			OxygenSaturation:      97,
+			SupplementalOxygen:    oxygen,
+			Consciousness:         ConsciousnessAlert,
+		}
+	}
+
+	tests := []struct {
+		name         string
+		oxygen       *bool
+		wantPoints   *int
+		wantTotal    int
+		wantComplete bool
+	}{
+		{"not recorded", nil, nil, 0, false},
+		{"room air", &roomAir, news2Points(0), 0, true},
+		{"supplemental oxygen", &onOxygen, news2Points(2), 2, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score := ScoreNEWS2(vitals(tt.oxygen))
+			if (score.SupplementalOxygenPoints == nil) != (tt.wantPoints == nil) ||
+				(tt.wantPoints != nil && *score.SupplementalOxygenPoints != *tt.wantPoints) {
+				t.Errorf("SupplementalOxygenPoints = %v, want %v", score.SupplementalOxygenPoints, tt.wantPoints)
+			}
+			if score.Total != tt.wantTotal {
+				t.Errorf("Total = %d, want %d", score.Total, tt.wantTotal)
+			}
+			if score.Complete != tt.wantComplete {
+				t.Errorf("Complete = %v, want %v", score.Complete, tt.wantComplete)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package domain
+
+import "time"
+
+// FollowUpStatus represents the status of a follow-up ordered at the end of a visit
+type FollowUpStatus string
+
+const (
+	FollowUpStatusPending     FollowUpStatus = "PENDING"     // Appointment booked, waiting for reception to confirm it with the patient
+	FollowUpStatusUnscheduled FollowUpStatus = "UNSCHEDULED" // No free slot was found or the appointment was cancelled; reception must book one
+	FollowUpStatusConfirmed   FollowUpStatus = "CONFIRMED"
+	FollowUpStatusAttended    FollowUpStatus = "ATTENDED"
+	FollowUpStatusMissed      FollowUpStatus = "MISSED"
+	FollowUpStatusCancelled   FollowUpStatus = "CANCELLED"
+)
+
+// IsOpen reports whether the follow-up is still waiting for the patient to attend
+func (s FollowUpStatus) IsOpen() bool {
+	return s == FollowUpStatusPending || s == FollowUpStatusUnscheduled || s == FollowUpStatusConfirmed
+}
+
+// FollowUp represents the return visit a doctor ordered when completing a
+// visit, with the appointment booked for it and whether the patient came
+type FollowUp struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Visit the follow-up was ordered in
+	VisitID uint   `gorm:"not null;uniqueIndex" json:"visit_id"`
+	Visit   *Visit `gorm:"foreignKey:VisitID" json:"visit,omitempty"`
+
+	PatientID uint     `gorm:"not null;index" json:"patient_id"`
+	Patient   *Patient `gorm:"foreignKey:PatientID" json:"patient,omitempty"`
+
+	DoctorID uint  `gorm:"not null;index" json:"doctor_id"`
+	Doctor   *User `gorm:"foreignKey:DoctorID" json:"doctor,omitempty"`
+
+	// Order
+	DueDate      time.Time `gorm:"type:date;not null;index" json:"due_date"` // The visit's next visit date
+	Instructions string    `gorm:"type:text" json:"instructions,omitempty"`
+
+	Status FollowUpStatus `gorm:"size:20;not null;index;default:'UNSCHEDULED'" json:"status"`
+
+	// Appointment booked for the follow-up
+	AppointmentID *uint        `gorm:"index" json:"appointment_id,omitempty"`
+	Appointment   *Appointment `gorm:"foreignKey:AppointmentID" json:"appointment,omitempty"`
+
+	ConfirmedBy *uint      `json:"confirmed_by,omitempty"`
+	ConfirmedAt *time.Time `json:"confirmed_at,omitempty"`
+
+	// Outcome
+	AttendedVisitID *uint      `json:"attended_visit_id,omitempty"`
+	ResolvedAt      *time.Time `json:"resolved_at,omitempty"` // When it was attended, missed or cancelled
+	CancelReason    string     `gorm:"type:text" json:"cancel_reason,omitempty"`
+
+	// Audit fields
+	CreatedBy uint `json:"created_by"`
+	UpdatedBy uint `json:"updated_by"`
+}
+
+// TableName specifies the table name for FollowUp model
+func (FollowUp) TableName() string {
+	return "follow_ups"
+}
@@ -54,10 +54,15 @@ type ImagingRequest struct {
 	Priority            ImagingPriority      `gorm:"size:20;not null;default:'ROUTINE'" json:"priority"`
 	RequestedDate       time.Time            `gorm:"not null;index" json:"requested_date"`
 	ScheduledDate       *time.Time           `json:"scheduled_date,omitempty"`
+	DurationMinutes     int                  `gorm:"default:30" json:"duration_minutes"` // Length of the scheduled slot
 	CompletedAt         *time.Time           `json:"completed_at,omitempty"`
 	ClinicalIndication  string               `gorm:"type:text" json:"clinical_indication"`
 	SpecialInstructions string               `gorm:"type:text" json:"special_instructions"`
 
+	// Patient Portal Release (results are hidden from the portal until released by staff)
+	PortalReleasedAt *time.Time `json:"portal_released_at,omitempty"`
+	PortalReleasedBy *uint      `json:"portal_released_by,omitempty"`
+
 	// Result (one-to-one)
 	Result *ImagingResult `gorm:"foreignKey:RequestID" json:"result,omitempty"`
 
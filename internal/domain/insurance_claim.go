@@ -33,6 +33,12 @@ type InsuranceClaim struct {
 	PatientID uint     `gorm:"not null;index" json:"patient_id"`
 	Patient   *Patient `gorm:"foreignKey:PatientID" json:"patient,omitempty"`
 
+	CoverageID *uint            `gorm:"index" json:"coverage_id,omitempty"`
+	Coverage   *PatientCoverage `gorm:"foreignKey:CoverageID" json:"coverage,omitempty"`
+
+	PayerID *uint           `gorm:"index" json:"payer_id,omitempty"`
+	Payer   *InsurancePayer `gorm:"foreignKey:PayerID" json:"payer,omitempty"`
+
 	// Claim Details
 	InsuranceProvider string      `gorm:"size:200;not null" json:"insurance_provider"`
 	PolicyNumber      string      `gorm:"size:100;not null" json:"policy_number"`
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PayerType represents the kind of insurance payer
+type PayerType string
+
+const (
+	PayerTypeSocial  PayerType = "SOCIAL"  // BHYT - national social health insurance
+	PayerTypePrivate PayerType = "PRIVATE" // Commercial health insurance
+)
+
+// InsurancePayer represents an insurance company or fund in the payer catalog
+type InsurancePayer struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+
+	Code        string    `gorm:"uniqueIndex;size:50;not null" json:"code"`
+	Name        string    `gorm:"size:200;not null" json:"name"`
+	PayerType   PayerType `gorm:"size:20;not null;index;default:'PRIVATE'" json:"payer_type"`
+	PhoneNumber string    `gorm:"size:20" json:"phone_number"`
+	Email       string    `gorm:"size:100" json:"email"`
+	Address     string    `gorm:"size:255" json:"address"`
+	IsActive    bool      `gorm:"default:true;index" json:"is_active"`
+
+	// Audit
+	CreatedBy uint `json:"created_by"`
+	UpdatedBy uint `json:"updated_by"`
+}
+
+// TableName specifies the table name for InsurancePayer model
+func (InsurancePayer) TableName() string {
+	return "insurance_payers"
+}
@@ -10,6 +10,7 @@ import (
 type InvoiceStatus string
 
 const (
+	InvoiceStatusDraft         InvoiceStatus = "DRAFT" // Generated when a visit is completed, not billed until issued
 	InvoiceStatusPending       InvoiceStatus = "PENDING"
 	InvoiceStatusPaid          InvoiceStatus = "PAID"
 	InvoiceStatusPartiallyPaid InvoiceStatus = "PARTIALLY_PAID"
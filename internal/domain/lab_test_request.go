@@ -57,6 +57,10 @@ type LabTestRequest struct {
 	CompletedAt       *time.Time           `json:"completed_at,omitempty"`
 	ClinicalNotes     string               `gorm:"type:text" json:"clinical_notes"`
 
+	// Patient Portal Release (results are hidden from the portal until released by staff)
+	PortalReleasedAt *time.Time `json:"portal_released_at,omitempty"`
+	PortalReleasedBy *uint      `json:"portal_released_by,omitempty"`
+
 	// Results (one-to-many)
 	Results []*LabTestResult `gorm:"foreignKey:RequestID" json:"results,omitempty"`
 
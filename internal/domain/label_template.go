@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// LabelTargetType represents the record a label is printed for
+type LabelTargetType string
+
+const (
+	LabelTargetPatient    LabelTargetType = "PATIENT"     // Wristbands and chart labels
+	LabelTargetAdmission  LabelTargetType = "ADMISSION"   // Inpatient wristbands with ward/bed
+	LabelTargetLabRequest LabelTargetType = "LAB_REQUEST" // Specimen tube labels
+)
+
+// LabelBarcodeType represents the barcode symbology printed on a label
+type LabelBarcodeType string
+
+const (
+	LabelBarcodeCode128 LabelBarcodeType = "CODE128"
+	LabelBarcodeQR      LabelBarcodeType = "QR"
+	LabelBarcodeNone    LabelBarcodeType = "NONE"
+)
+
+// LabelTemplate represents a configurable label layout (size, barcode and printed fields)
+type LabelTemplate struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+
+	Code        string           `gorm:"uniqueIndex;size:50;not null" json:"code"`
+	Name        string           `gorm:"size:200;not null" json:"name"`
+	TargetType  LabelTargetType  `gorm:"size:20;not null;index" json:"target_type"`
+	WidthMM     float64          `gorm:"type:decimal(6,2);not null" json:"width_mm"`
+	HeightMM    float64          `gorm:"type:decimal(6,2);not null" json:"height_mm"`
+	BarcodeType LabelBarcodeType `gorm:"size:20;not null;default:'CODE128'" json:"barcode_type"`
+	Fields      string           `gorm:"size:500;not null" json:"fields"`                 // Comma-separated field keys, printed in order
+	FontSizePt  float64          `gorm:"type:decimal(4,1);default:0" json:"font_size_pt"` // 0 = fit to label
+	Copies      int              `gorm:"default:1" json:"copies"`
+	IsDefault   bool             `gorm:"default:false" json:"is_default"`
+	IsActive    bool             `gorm:"default:true;index" json:"is_active"`
+
+	// Audit
+	CreatedBy uint `json:"created_by"`
+	UpdatedBy uint `json:"updated_by"`
+}
+
+// TableName specifies the table name for LabelTemplate model
+func (LabelTemplate) TableName() string {
+	return "label_templates"
+}
+
+// FieldList returns the template's field keys in print order
+func (t *LabelTemplate) FieldList() []string {
+	var fields []string
+	for _, f := range strings.Split(t.Fields, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
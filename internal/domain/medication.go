@@ -34,6 +34,7 @@ type Medication struct {
 	Strength     string     `gorm:"size:50" json:"strength"` // e.g., "500mg", "10ml"
 	Unit         string     `gorm:"size:20" json:"unit"`     // mg, ml, g, etc.
 	Manufacturer string     `gorm:"size:200" json:"manufacturer"`
+	UnitPrice    float64    `gorm:"type:decimal(10,2);default:0" json:"unit_price"` // Price charged per dispensed unit
 	IsActive     bool       `gorm:"default:true;index" json:"is_active"`
 }
 
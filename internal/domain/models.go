@@ -33,6 +33,16 @@ func (User) TableName() string {
 	return "users"
 }
 
+// HasRole reports whether the user, loaded with roles, has the role code
+func (u *User) HasRole(code string) bool {
+	for _, role := range u.Roles {
+		if role.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
 // Role represents a role in the system
 type Role struct {
 	BaseModel
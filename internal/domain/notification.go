@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// NotificationChannel represents how a notification is delivered
+type NotificationChannel string
+
+const (
+	NotificationChannelSMS   NotificationChannel = "SMS"
+	NotificationChannelEmail NotificationChannel = "EMAIL"
+	NotificationChannelZalo  NotificationChannel = "ZALO"
+)
+
+// NotificationStatus represents the delivery status of a notification
+type NotificationStatus string
+
+const (
+	NotificationStatusPending NotificationStatus = "PENDING"
+	NotificationStatusSent    NotificationStatus = "SENT"
+	NotificationStatusFailed  NotificationStatus = "FAILED" // Retries exhausted
+)
+
+// Notification template codes
+const (
+	NotificationTemplateAppointmentConfirmation = "APPOINTMENT_CONFIRMATION"
+	NotificationTemplateAppointmentReminder     = "APPOINTMENT_REMINDER"
+	NotificationTemplateEarlyWarningEscalation  = "EARLY_WARNING_ESCALATION" // To the attending doctor
+)
+
+// NotificationTemplate represents the text of a notification for one channel and language
+type NotificationTemplate struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+
+	Code     string              `gorm:"size:50;not null;uniqueIndex:idx_notification_templates_code_channel_language" json:"code"`
+	Channel  NotificationChannel `gorm:"size:10;not null;uniqueIndex:idx_notification_templates_code_channel_language" json:"channel"`
+	Language string              `gorm:"size:5;not null;uniqueIndex:idx_notification_templates_code_channel_language" json:"language"` // vi, en
+
+	Subject  string `gorm:"size:255" json:"subject"`        // Email only; Go text/template syntax
+	Body     string `gorm:"type:text;not null" json:"body"` // Go text/template syntax, e.g. {{.PatientName}}
+	IsActive bool   `gorm:"default:true" json:"is_active"`
+
+	// Audit fields
+	CreatedBy uint `json:"created_by"`
+	UpdatedBy uint `json:"updated_by"`
+}
+
+// TableName specifies the table name for NotificationTemplate model
+func (NotificationTemplate) TableName() string {
+	return "notification_templates"
+}
+
+// Notification represents a rendered message queued for delivery, with its delivery attempts
+type Notification struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Channel      NotificationChannel `gorm:"size:10;not null;index" json:"channel"`
+	Recipient    string              `gorm:"size:100;not null" json:"recipient"`
+	TemplateCode string              `gorm:"size:50;not null;index" json:"template_code"`
+	Language     string              `gorm:"size:5;not null" json:"language"`
+	Subject      string              `gorm:"size:255" json:"subject"`
+	Body         string              `gorm:"type:text;not null" json:"body"`
+	Data         string              `gorm:"type:text" json:"-"` // Template variables as JSON, for providers that render their own templates
+
+	// Related records
+	PatientID     *uint `gorm:"index" json:"patient_id,omitempty"`
+	AppointmentID *uint `gorm:"index" json:"appointment_id,omitempty"`
+
+	// Prevents the same reminder being queued twice, e.g. APPOINTMENT_REMINDER:42:24h:SMS
+	DedupeKey *string `gorm:"size:100;uniqueIndex" json:"dedupe_key,omitempty"`
+
+	// Delivery
+	Status        NotificationStatus `gorm:"size:20;not null;index;default:'PENDING'" json:"status"`
+	Attempts      int                `gorm:"not null;default:0" json:"attempts"`
+	MaxAttempts   int                `gorm:"not null;default:5" json:"max_attempts"`
+	NextAttemptAt time.Time          `gorm:"not null;index" json:"next_attempt_at"`
+	LastError     string             `gorm:"type:text" json:"last_error,omitempty"`
+	ProviderRef   string             `gorm:"size:100" json:"provider_ref,omitempty"`
+	SentAt        *time.Time         `json:"sent_at,omitempty"`
+}
+
+// TableName specifies the table name for Notification model
+func (Notification) TableName() string {
+	return "notifications"
+}
@@ -25,6 +25,8 @@ type NursingNote struct {
 	VitalSigns    VitalSigns `gorm:"type:json" json:"vital_signs"`
 	Observations  string     `gorm:"type:text;not null" json:"observations"`
 	Interventions string     `gorm:"type:text" json:"interventions"`
+
+	EarlyWarningScore *EarlyWarningScore `gorm:"foreignKey:NursingNoteID" json:"early_warning_score,omitempty"`
 }
 
 // TableName specifies the table name for NursingNote model
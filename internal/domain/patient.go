@@ -3,6 +3,7 @@ package domain
 import (
 	"time"
 
+	"github.com/minhtran/his/internal/pkg/clock"
 	"gorm.io/gorm"
 )
 
@@ -58,12 +59,15 @@ type Patient struct {
 	PostalCode  string `gorm:"size:20" json:"postal_code"`
 	Country     string `gorm:"size:100;default:'Vietnam'" json:"country"`
 
+	// Language for notifications (vi, en)
+	PreferredLanguage string `gorm:"size:5;not null;default:'vi'" json:"preferred_language"`
+
 	// Identification
 	NationalID string `gorm:"size:20;uniqueIndex" json:"national_id"` // CCCD/CMND
 
-	// Insurance Information
-	InsuranceNumber   string `gorm:"size:50" json:"insurance_number"`
-	InsuranceProvider string `gorm:"size:100" json:"insurance_provider"`
+	// Insurance Information (deprecated - use Coverages)
+	InsuranceNumber   string `gorm:"size:50" json:"insurance_number"`    // Deprecated: Use Coverages
+	InsuranceProvider string `gorm:"size:100" json:"insurance_provider"` // Deprecated: Use Coverages
 
 	// Emergency Contact
 	EmergencyContactName         string `gorm:"size:100" json:"emergency_contact_name"`
@@ -78,13 +82,20 @@ type Patient struct {
 	// Relationships
 	AllergiesRecords      []*PatientAllergy        `gorm:"foreignKey:PatientID" json:"allergies_records,omitempty"`
 	MedicalHistoryRecords []*PatientMedicalHistory `gorm:"foreignKey:PatientID" json:"medical_history_records,omitempty"`
+	Coverages             []*PatientCoverage       `gorm:"foreignKey:PatientID" json:"coverages,omitempty"`
 
 	// Status
-	IsActive bool `gorm:"default:true" json:"is_active"`
+	IsActive   bool       `gorm:"default:true" json:"is_active"`
+	IsDeceased bool       `gorm:"default:false;index" json:"is_deceased"`
+	DeceasedAt *time.Time `json:"deceased_at,omitempty"`
+
+	// PreRegistered marks a patient created by public online booking whose
+	// details staff have not yet checked
+	PreRegistered bool `gorm:"default:false;index" json:"pre_registered"`
 
 	// Audit fields
-	CreatedBy uint `gorm:"not null" json:"created_by"`
-	UpdatedBy uint `json:"updated_by"`
+	CreatedBy *uint `json:"created_by"` // Nil for patients pre-registered online
+	UpdatedBy uint  `json:"updated_by"`
 }
 
 // TableName specifies the table name for Patient model
@@ -95,29 +106,25 @@ func (Patient) TableName() string {
 // BeforeCreate hook to calculate age
 func (p *Patient) BeforeCreate(tx *gorm.DB) error {
 	p.FullName = p.FirstName + " " + p.LastName
-	p.Age = calculateAge(p.DateOfBirth)
+	p.Age = calculateAge(tx, p.DateOfBirth)
 	return nil
 }
 
 // BeforeUpdate hook to update age and full name
 func (p *Patient) BeforeUpdate(tx *gorm.DB) error {
 	p.FullName = p.FirstName + " " + p.LastName
-	p.Age = calculateAge(p.DateOfBirth)
+	p.Age = calculateAge(tx, p.DateOfBirth)
 	return nil
 }
 
 // AfterFind hook to calculate age when loading from database
 func (p *Patient) AfterFind(tx *gorm.DB) error {
-	p.Age = calculateAge(p.DateOfBirth)
+	p.Age = calculateAge(tx, p.DateOfBirth)
 	return nil
 }
 
-// calculateAge calculates age from date of birth
-func calculateAge(dob time.Time) int {
-	now := time.Now()
-	age := now.Year() - dob.Year()
-	if now.YearDay() < dob.YearDay() {
-		age--
-	}
-	return age
+// calculateAge calculates age from date of birth on the current date of the
+// database's clock, which runs in the facility's time zone
+func calculateAge(tx *gorm.DB, dob time.Time) int {
+	return clock.Age(clock.Day(dob), clock.Day(tx.NowFunc()))
 }
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CoveragePriority represents the order in which coverages are billed
+type CoveragePriority string
+
+const (
+	CoveragePriorityPrimary   CoveragePriority = "PRIMARY"
+	CoveragePrioritySecondary CoveragePriority = "SECONDARY"
+)
+
+// PatientCoverage represents an insurance policy held by a patient
+type PatientCoverage struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+
+	// Foreign Keys
+	PatientID uint     `gorm:"not null;index" json:"patient_id"`
+	Patient   *Patient `gorm:"foreignKey:PatientID" json:"patient,omitempty"`
+
+	PayerID uint            `gorm:"not null;index" json:"payer_id"`
+	Payer   *InsurancePayer `gorm:"foreignKey:PayerID" json:"payer,omitempty"`
+
+	// Coverage Details
+	PolicyNumber        string           `gorm:"size:100;not null;index" json:"policy_number"`
+	ValidFrom           time.Time        `gorm:"type:date;not null" json:"valid_from"`
+	ValidTo             *time.Time       `gorm:"type:date" json:"valid_to,omitempty"` // Nullable for open-ended policies
+	CoveragePercent     float64          `gorm:"type:decimal(5,2);not null;default:0" json:"coverage_percent"`
+	PrimaryCareFacility string           `gorm:"size:200" json:"primary_care_facility"` // Registered KCB ban đầu facility
+	Priority            CoveragePriority `gorm:"size:20;not null;default:'PRIMARY'" json:"priority"`
+	IsActive            bool             `gorm:"default:true" json:"is_active"`
+	Notes               string           `gorm:"type:text" json:"notes"`
+
+	// Audit fields
+	CreatedBy uint `gorm:"not null" json:"created_by"`
+	UpdatedBy uint `json:"updated_by"`
+}
+
+// TableName specifies the table name for PatientCoverage model
+func (PatientCoverage) TableName() string {
+	return "patient_coverages"
+}
+
+// IsValidOn reports whether the coverage is active and within its validity period on the given date
+func (c *PatientCoverage) IsValidOn(date time.Time) bool {
+	if !c.IsActive {
+		return false
+	}
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	from := time.Date(c.ValidFrom.Year(), c.ValidFrom.Month(), c.ValidFrom.Day(), 0, 0, 0, 0, time.UTC)
+	if day.Before(from) {
+		return false
+	}
+	if c.ValidTo != nil {
+		to := time.Date(c.ValidTo.Year(), c.ValidTo.Month(), c.ValidTo.Day(), 0, 0, 0, 0, time.UTC)
+		if day.After(to) {
+			return false
+		}
+	}
+	return true
+}
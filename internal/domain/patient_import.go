@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+// ImportJobStatus represents the status of a patient import job
+type ImportJobStatus string
+
+const (
+	ImportJobStatusProcessing ImportJobStatus = "PROCESSING" // Rows are being imported in the background
+	ImportJobStatusCompleted  ImportJobStatus = "COMPLETED"
+	ImportJobStatusFailed     ImportJobStatus = "FAILED" // Stopped part-way; rows reported so far were imported
+)
+
+// ImportRowStatus represents the outcome of a single imported row
+type ImportRowStatus string
+
+const (
+	ImportRowStatusCreated ImportRowStatus = "CREATED"
+	ImportRowStatusValid   ImportRowStatus = "VALID" // Dry-run: row would be created
+	ImportRowStatusSkipped ImportRowStatus = "SKIPPED"
+	ImportRowStatusError   ImportRowStatus = "ERROR"
+)
+
+// ImportColumnMapping maps patient fields to source column headers
+type ImportColumnMapping map[string]string
+
+// Scan implements the sql.Scanner interface
+func (m *ImportColumnMapping) Scan(value interface{}) error {
+	if value == nil {
+		*m = ImportColumnMapping{}
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, m)
+}
+
+// Value implements the driver.Valuer interface
+func (m ImportColumnMapping) Value() (driver.Value, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return json.Marshal(m)
+}
+
+// PatientImportJob represents a bulk patient import from a CSV/XLSX file
+type PatientImportJob struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Source File
+	FileName      string              `gorm:"size:255;not null" json:"file_name"`
+	ColumnMapping ImportColumnMapping `gorm:"type:json" json:"column_mapping"`
+	DryRun        bool                `gorm:"default:false" json:"dry_run"`
+
+	// Result Summary
+	Status       ImportJobStatus `gorm:"size:20;not null;index" json:"status"`
+	TotalRows    int             `gorm:"not null;default:0" json:"total_rows"`
+	CreatedCount int             `gorm:"not null;default:0" json:"created_count"` // Includes rows that would be created in a dry run
+	SkippedCount int             `gorm:"not null;default:0" json:"skipped_count"`
+	ErrorCount   int             `gorm:"not null;default:0" json:"error_count"`
+	CompletedAt  *time.Time      `json:"completed_at,omitempty"`
+
+	// Relationships
+	Rows []*PatientImportRow `gorm:"foreignKey:JobID" json:"rows,omitempty"`
+
+	// Audit fields
+	CreatedBy uint `gorm:"not null;index" json:"created_by"`
+}
+
+// TableName specifies the table name for PatientImportJob model
+func (PatientImportJob) TableName() string {
+	return "patient_import_jobs"
+}
+
+// PatientImportRow represents the per-row result of an import job
+type PatientImportRow struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Foreign Keys
+	JobID     uint  `gorm:"not null;index" json:"job_id"`
+	PatientID *uint `gorm:"index" json:"patient_id,omitempty"` // Created or matched duplicate
+
+	// Row Result
+	LineNumber int             `gorm:"not null" json:"line_number"` // 1-based, as shown in a spreadsheet
+	Status     ImportRowStatus `gorm:"size:20;not null;index" json:"status"`
+	FullName   string          `gorm:"size:100" json:"full_name"`
+	NationalID string          `gorm:"size:20" json:"national_id"`
+	Message    string          `gorm:"type:text" json:"message"`
+}
+
+// TableName specifies the table name for PatientImportRow model
+func (PatientImportRow) TableName() string {
+	return "patient_import_rows"
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PatientPortalAccount represents a patient's login to the patient portal.
+// Patients sign in with a one-time code sent to PhoneNumber or, once set, a password.
+type PatientPortalAccount struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+
+	// Foreign Keys
+	PatientID uint     `gorm:"uniqueIndex;not null" json:"patient_id"`
+	Patient   *Patient `gorm:"foreignKey:PatientID" json:"patient,omitempty"`
+
+	// Credentials
+	PhoneNumber  string `gorm:"uniqueIndex;size:20;not null" json:"phone_number"`
+	PasswordHash string `gorm:"size:255" json:"-"` // Empty until the patient sets a password
+
+	// Status
+	IsActive            bool       `gorm:"default:true;index" json:"is_active"`
+	FailedLoginAttempts int        `gorm:"default:0" json:"failed_login_attempts"`
+	LockedUntil         *time.Time `json:"locked_until,omitempty"`
+	LastLoginAt         *time.Time `json:"last_login_at,omitempty"`
+
+	// Audit fields
+	CreatedBy uint `json:"created_by"`
+	UpdatedBy uint `json:"updated_by"`
+}
+
+// TableName specifies the table name for PatientPortalAccount model
+func (PatientPortalAccount) TableName() string {
+	return "patient_portal_accounts"
+}
+
+// IsLocked reports whether the account is temporarily locked after failed logins
+func (a *PatientPortalAccount) IsLocked(now time.Time) bool {
+	return a.LockedUntil != nil && now.Before(*a.LockedUntil)
+}
+
+// PortalOTP represents a one-time login code sent to a portal account's phone
+type PortalOTP struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	AccountID uint                  `gorm:"not null;index" json:"account_id"`
+	Account   *PatientPortalAccount `gorm:"foreignKey:AccountID" json:"account,omitempty"`
+
+	CodeHash   string     `gorm:"size:255;not null" json:"-"`
+	ExpiresAt  time.Time  `gorm:"not null" json:"expires_at"`
+	Attempts   int        `gorm:"default:0" json:"attempts"`
+	ConsumedAt *time.Time `json:"consumed_at,omitempty"`
+}
+
+// TableName specifies the table name for PortalOTP model
+func (PortalOTP) TableName() string {
+	return "portal_otps"
+}
@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// QueueLane represents the priority lane of a queue ticket
+type QueueLane string
+
+const (
+	QueueLaneEmergency QueueLane = "EMERGENCY"
+	QueueLaneElderly   QueueLane = "ELDERLY"
+	QueueLaneChild     QueueLane = "CHILD"
+	QueueLaneNormal    QueueLane = "NORMAL"
+)
+
+// Rank returns the lane's calling order; lower ranks are called first.
+// Elderly patients and young children share a priority lane.
+func (l QueueLane) Rank() int {
+	switch l {
+	case QueueLaneEmergency:
+		return 0
+	case QueueLaneElderly, QueueLaneChild:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// QueueTicketStatus represents the status of a queue ticket
+type QueueTicketStatus string
+
+const (
+	QueueTicketStatusWaiting   QueueTicketStatus = "WAITING"
+	QueueTicketStatusCalled    QueueTicketStatus = "CALLED"  // Called to the room, not yet seen
+	QueueTicketStatusSkipped   QueueTicketStatus = "SKIPPED" // Did not answer the call
+	QueueTicketStatusServed    QueueTicketStatus = "SERVED"
+	QueueTicketStatusCancelled QueueTicketStatus = "CANCELLED"
+)
+
+// ClinicQueue represents the waiting line of an examination room or a doctor.
+// Checked-in patients get a ticket numbered from 1 each day.
+type ClinicQueue struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+
+	Code         string `gorm:"uniqueIndex;size:20;not null" json:"code"`
+	Name         string `gorm:"size:200;not null" json:"name"`
+	TicketPrefix string `gorm:"size:5" json:"ticket_prefix"` // Shown before ticket numbers, e.g. A012
+
+	// The doctor the queue serves, whose check-ins join it, and the room patients are called to
+	DoctorID     *uint       `gorm:"index" json:"doctor_id,omitempty"`
+	Doctor       *User       `gorm:"foreignKey:DoctorID" json:"doctor,omitempty"`
+	RoomID       *uint       `gorm:"index" json:"room_id,omitempty"`
+	Room         *Resource   `gorm:"foreignKey:RoomID" json:"room,omitempty"`
+	DepartmentID *uint       `gorm:"index" json:"department_id,omitempty"`
+	Department   *Department `gorm:"foreignKey:DepartmentID" json:"department,omitempty"`
+
+	IsActive bool `gorm:"default:true;index" json:"is_active"`
+
+	// Audit fields
+	CreatedBy uint `json:"created_by"`
+	UpdatedBy uint `json:"updated_by"`
+}
+
+// TableName specifies the table name for ClinicQueue model
+func (ClinicQueue) TableName() string {
+	return "clinic_queues"
+}
+
+// QueueTicket represents a checked-in patient's place in a queue for a day
+type QueueTicket struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	QueueID      uint         `gorm:"not null;uniqueIndex:idx_queue_tickets_number" json:"queue_id"`
+	Queue        *ClinicQueue `gorm:"foreignKey:QueueID" json:"queue,omitempty"`
+	QueueDate    time.Time    `gorm:"type:date;not null;uniqueIndex:idx_queue_tickets_number" json:"queue_date"`
+	TicketNumber int          `gorm:"not null;uniqueIndex:idx_queue_tickets_number" json:"ticket_number"`
+	TicketCode   string       `gorm:"size:10;not null" json:"ticket_code"`
+
+	VisitID   *uint    `gorm:"index" json:"visit_id,omitempty"`
+	Visit     *Visit   `gorm:"foreignKey:VisitID" json:"visit,omitempty"`
+	PatientID uint     `gorm:"not null;index" json:"patient_id"`
+	Patient   *Patient `gorm:"foreignKey:PatientID" json:"patient,omitempty"`
+
+	Lane        QueueLane         `gorm:"size:20;not null" json:"lane"`
+	AcuityLevel *int              `json:"acuity_level,omitempty"` // The visit's triage level
+	Status      QueueTicketStatus `gorm:"size:20;not null;index" json:"status"`
+
+	// Calls
+	CalledAt  *time.Time `json:"called_at,omitempty"`
+	CalledBy  *uint      `json:"called_by,omitempty"`
+	CallCount int        `gorm:"default:0" json:"call_count"`
+	SkippedAt *time.Time `json:"skipped_at,omitempty"`
+	ServedAt  *time.Time `json:"served_at,omitempty"`
+
+	// Audit fields
+	IssuedBy uint `json:"issued_by"`
+}
+
+// TableName specifies the table name for QueueTicket model
+func (QueueTicket) TableName() string {
+	return "queue_tickets"
+}
+
+// IsOpen reports whether the ticket is still in the queue
+func (t *QueueTicket) IsOpen() bool {
+	return t.Status == QueueTicketStatusWaiting || t.Status == QueueTicketStatusCalled || t.Status == QueueTicketStatusSkipped
+}
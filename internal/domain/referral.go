@@ -0,0 +1,126 @@
+package domain
+
+import "time"
+
+// ReferralType represents where a patient is referred
+type ReferralType string
+
+const (
+	ReferralTypeInternal ReferralType = "INTERNAL" // Another department or doctor of this hospital
+	ReferralTypeExternal ReferralType = "EXTERNAL" // Another facility
+)
+
+// ReferralUrgency represents how soon a referred patient should be seen
+type ReferralUrgency string
+
+const (
+	ReferralUrgencyRoutine   ReferralUrgency = "ROUTINE"
+	ReferralUrgencyUrgent    ReferralUrgency = "URGENT"
+	ReferralUrgencyEmergency ReferralUrgency = "EMERGENCY"
+)
+
+// ReferralStatus represents the status of a referral
+type ReferralStatus string
+
+const (
+	ReferralStatusSent      ReferralStatus = "SENT"
+	ReferralStatusAccepted  ReferralStatus = "ACCEPTED"
+	ReferralStatusDeclined  ReferralStatus = "DECLINED"
+	ReferralStatusCompleted ReferralStatus = "COMPLETED" // The patient was seen by the receiving side
+)
+
+// CanTransitionTo reports whether a referral can move from s to the target status
+func (s ReferralStatus) CanTransitionTo(target ReferralStatus) bool {
+	switch s {
+	case ReferralStatusSent:
+		return target == ReferralStatusAccepted || target == ReferralStatusDeclined
+	case ReferralStatusAccepted:
+		return target == ReferralStatusCompleted
+	default:
+		return false
+	}
+}
+
+// Referral represents a doctor sending a patient seen in a visit to another
+// department, doctor or facility. Internal referrals are answered from the
+// receiving department's inbox and booked as appointments.
+type Referral struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Referral Number (auto-generated: REF-YYYYMMDD-XXXX)
+	ReferralNumber string `gorm:"uniqueIndex;size:20;not null" json:"referral_number"`
+
+	// Foreign Keys
+	VisitID uint   `gorm:"not null;index" json:"visit_id"`
+	Visit   *Visit `gorm:"foreignKey:VisitID" json:"visit,omitempty"`
+
+	PatientID uint     `gorm:"not null;index" json:"patient_id"` // Denormalized
+	Patient   *Patient `gorm:"foreignKey:PatientID" json:"patient,omitempty"`
+
+	ReferringDoctorID uint  `gorm:"not null;index" json:"referring_doctor_id"`
+	ReferringDoctor   *User `gorm:"foreignKey:ReferringDoctorID" json:"referring_doctor,omitempty"`
+
+	// Target
+	ReferralType         ReferralType `gorm:"size:20;not null" json:"referral_type"`
+	TargetDepartmentID   *uint        `gorm:"index" json:"target_department_id,omitempty"` // Internal referrals
+	TargetDepartment     *Department  `gorm:"foreignKey:TargetDepartmentID" json:"target_department,omitempty"`
+	TargetDoctorID       *uint        `gorm:"index" json:"target_doctor_id,omitempty"` // Optional for internal referrals
+	TargetDoctor         *User        `gorm:"foreignKey:TargetDoctorID" json:"target_doctor,omitempty"`
+	ExternalFacility     string       `gorm:"size:200" json:"external_facility,omitempty"`     // External referrals
+	ExternalFacilityCode string       `gorm:"size:20" json:"external_facility_code,omitempty"` // Health facility code (mã cơ sở KCB)
+
+	// Referral Details
+	Urgency            ReferralUrgency `gorm:"size:20;not null;default:'ROUTINE'" json:"urgency"`
+	Reason             string          `gorm:"type:text;not null" json:"reason"`
+	ClinicalSummary    string          `gorm:"type:text" json:"clinical_summary"`    // Clinical signs
+	TreatmentGiven     string          `gorm:"type:text" json:"treatment_given"`     // Procedures and medication used
+	PatientCondition   string          `gorm:"type:text" json:"patient_condition"`   // Condition at referral
+	TreatmentDirection string          `gorm:"type:text" json:"treatment_direction"` // Suggested further treatment
+	Transport          string          `gorm:"size:100" json:"transport"`
+	Escort             string          `gorm:"size:200" json:"escort"` // Name and title of the escorting staff
+
+	Attachments []*ReferralAttachment `gorm:"foreignKey:ReferralID" json:"attachments,omitempty"`
+
+	// Status
+	Status        ReferralStatus `gorm:"size:20;not null;index;default:'SENT'" json:"status"`
+	SentAt        time.Time      `gorm:"not null;index" json:"sent_at"`
+	RespondedBy   *uint          `json:"responded_by,omitempty"`
+	Responder     *User          `gorm:"foreignKey:RespondedBy" json:"responder,omitempty"`
+	RespondedAt   *time.Time     `json:"responded_at,omitempty"`
+	ResponseNote  string         `gorm:"type:text" json:"response_note"` // Reason when declined
+	AppointmentID *uint          `gorm:"index" json:"appointment_id,omitempty"`
+	Appointment   *Appointment   `gorm:"foreignKey:AppointmentID" json:"appointment,omitempty"`
+	CompletedAt   *time.Time     `json:"completed_at,omitempty"`
+
+	// Audit fields
+	CreatedBy uint `gorm:"not null" json:"created_by"`
+	UpdatedBy uint `json:"updated_by"`
+}
+
+// TableName specifies the table name for Referral model
+func (Referral) TableName() string {
+	return "referrals"
+}
+
+// ReferralAttachment represents a diagnosis or test result sent with a
+// referral. Exactly one of the references is set.
+type ReferralAttachment struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	ReferralID uint `gorm:"not null;index" json:"referral_id"`
+
+	DiagnosisID      *uint           `json:"diagnosis_id,omitempty"`
+	Diagnosis        *Diagnosis      `gorm:"foreignKey:DiagnosisID" json:"diagnosis,omitempty"`
+	LabTestRequestID *uint           `json:"lab_test_request_id,omitempty"`
+	LabTestRequest   *LabTestRequest `gorm:"foreignKey:LabTestRequestID" json:"lab_test_request,omitempty"`
+	ImagingRequestID *uint           `json:"imaging_request_id,omitempty"`
+	ImagingRequest   *ImagingRequest `gorm:"foreignKey:ImagingRequestID" json:"imaging_request,omitempty"`
+}
+
+// TableName specifies the table name for ReferralAttachment model
+func (ReferralAttachment) TableName() string {
+	return "referral_attachments"
+}
@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ResourceType represents the kind of schedulable resource
+type ResourceType string
+
+const (
+	ResourceTypeRoom      ResourceType = "ROOM"
+	ResourceTypeEquipment ResourceType = "EQUIPMENT"
+)
+
+// Resource represents a room or piece of equipment that appointments and
+// imaging schedules reserve, such as a procedure room, an ultrasound machine
+// or an endoscopy suite
+type Resource struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+
+	Code     string       `gorm:"uniqueIndex;size:20;not null" json:"code"`
+	Name     string       `gorm:"size:200;not null" json:"name"`
+	Type     ResourceType `gorm:"size:20;not null;index" json:"type"`
+	Category string       `gorm:"size:50;index" json:"category"` // e.g. ULTRASOUND, ENDOSCOPY_SUITE
+	Location string       `gorm:"size:100" json:"location"`
+
+	DepartmentID *uint       `gorm:"index" json:"department_id,omitempty"`
+	Department   *Department `gorm:"foreignKey:DepartmentID" json:"department,omitempty"`
+
+	Notes    string `gorm:"type:text" json:"notes"`
+	IsActive bool   `gorm:"default:true;index" json:"is_active"`
+
+	// Weekly opening hours; a resource without hours is available at any time
+	Hours []ResourceHours `gorm:"foreignKey:ResourceID" json:"hours,omitempty"`
+
+	// Audit fields
+	CreatedBy uint `json:"created_by"`
+	UpdatedBy uint `json:"updated_by"`
+}
+
+// TableName specifies the table name for Resource model
+func (Resource) TableName() string {
+	return "resources"
+}
+
+// ResourceHours represents a weekly period during which a resource can be booked
+type ResourceHours struct {
+	ID         uint `gorm:"primarykey" json:"id"`
+	ResourceID uint `gorm:"not null;index" json:"resource_id"`
+
+	// Times are HH:MM in facility local time
+	DayOfWeek int    `gorm:"not null" json:"day_of_week"` // 0 = Sunday ... 6 = Saturday
+	StartTime string `gorm:"size:5;not null" json:"start_time"`
+	EndTime   string `gorm:"size:5;not null" json:"end_time"`
+}
+
+// TableName specifies the table name for ResourceHours model
+func (ResourceHours) TableName() string {
+	return "resource_hours"
+}
+
+// ResourceDowntime represents a period during which a resource cannot be
+// booked, e.g. maintenance or repair
+type ResourceDowntime struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+
+	ResourceID uint      `gorm:"not null;index" json:"resource_id"`
+	StartAt    time.Time `gorm:"not null" json:"start_at"`
+	EndAt      time.Time `gorm:"not null" json:"end_at"` // Exclusive
+	Reason     string    `gorm:"size:255" json:"reason"`
+
+	// Audit fields
+	CreatedBy uint `json:"created_by"`
+	UpdatedBy uint `json:"updated_by"`
+}
+
+// TableName specifies the table name for ResourceDowntime model
+func (ResourceDowntime) TableName() string {
+	return "resource_downtimes"
+}
+
+// ResourceBooking reserves a resource for an appointment or a scheduled
+// imaging request. A booking stops blocking the resource once its appointment
+// is cancelled or a no-show, or its imaging request is cancelled.
+type ResourceBooking struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	ResourceID uint      `gorm:"not null;index" json:"resource_id"`
+	Resource   *Resource `gorm:"foreignKey:ResourceID" json:"resource,omitempty"`
+
+	// Exactly one owner is set
+	AppointmentID    *uint `gorm:"index" json:"appointment_id,omitempty"`
+	ImagingRequestID *uint `gorm:"index" json:"imaging_request_id,omitempty"`
+
+	StartAt time.Time `gorm:"not null;index" json:"start_at"`
+	EndAt   time.Time `gorm:"not null" json:"end_at"` // Exclusive
+
+	// Audit fields
+	CreatedBy *uint `json:"created_by"` // Nil for portal bookings
+}
+
+// TableName specifies the table name for ResourceBooking model
+func (ResourceBooking) TableName() string {
+	return "resource_bookings"
+}
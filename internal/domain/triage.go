@@ -0,0 +1,237 @@
+package domain
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TriageScale represents the acuity scale triage levels are assigned on.
+// Both scales have five levels, 1 being the most urgent.
+type TriageScale string
+
+const (
+	TriageScaleESI TriageScale = "ESI" // Emergency Severity Index
+	TriageScaleMTS TriageScale = "MTS" // Manchester Triage System
+)
+
+// Acuity levels run from 1 (most urgent) to 5
+const (
+	AcuityLevelHighest = 1
+	AcuityLevelLowest  = 5
+)
+
+var triageLevelNames = map[TriageScale][AcuityLevelLowest]string{
+	TriageScaleESI: {"Resuscitation", "Emergent", "Urgent", "Less urgent", "Non-urgent"},
+	TriageScaleMTS: {"Red - Immediate", "Orange - Very urgent", "Yellow - Urgent", "Green - Standard", "Blue - Non-urgent"},
+}
+
+// triageTargetMinutes are the longest waits to be seen per level, from the
+// Manchester Triage System; ESI defines none, so both scales use them
+var triageTargetMinutes = [AcuityLevelLowest]int{0, 10, 60, 120, 240}
+
+// LevelName returns the name of an acuity level on the scale
+func (s TriageScale) LevelName(level int) string {
+	names, ok := triageLevelNames[s]
+	if !ok || level < AcuityLevelHighest || level > AcuityLevelLowest {
+		return ""
+	}
+	return names[level-1]
+}
+
+// TriageTargetWait returns the longest a patient of an acuity level should
+// wait to be seen
+func TriageTargetWait(level int) time.Duration {
+	if level < AcuityLevelHighest {
+		level = AcuityLevelHighest
+	}
+	if level > AcuityLevelLowest {
+		level = AcuityLevelLowest
+	}
+	return time.Duration(triageTargetMinutes[level-1]) * time.Minute
+}
+
+// ComplaintCategory represents the presenting complaint a patient is triaged under
+type ComplaintCategory string
+
+const (
+	ComplaintCardiacArrest        ComplaintCategory = "CARDIAC_ARREST"
+	ComplaintChestPain            ComplaintCategory = "CHEST_PAIN"
+	ComplaintShortnessOfBreath    ComplaintCategory = "SHORTNESS_OF_BREATH"
+	ComplaintAlteredConsciousness ComplaintCategory = "ALTERED_CONSCIOUSNESS"
+	ComplaintStrokeSymptoms       ComplaintCategory = "STROKE_SYMPTOMS"
+	ComplaintSeizure              ComplaintCategory = "SEIZURE"
+	ComplaintMajorTrauma          ComplaintCategory = "MAJOR_TRAUMA"
+	ComplaintHeadInjury           ComplaintCategory = "HEAD_INJURY"
+	ComplaintMinorInjury          ComplaintCategory = "MINOR_INJURY"
+	ComplaintBleeding             ComplaintCategory = "BLEEDING"
+	ComplaintAbdominalPain        ComplaintCategory = "ABDOMINAL_PAIN"
+	ComplaintAllergicReaction     ComplaintCategory = "ALLERGIC_REACTION"
+	ComplaintPoisoning            ComplaintCategory = "POISONING"
+	ComplaintBurn                 ComplaintCategory = "BURN"
+	ComplaintFever                ComplaintCategory = "FEVER"
+	ComplaintPregnancy            ComplaintCategory = "PREGNANCY"
+	ComplaintMentalHealth         ComplaintCategory = "MENTAL_HEALTH"
+	ComplaintOther                ComplaintCategory = "OTHER"
+)
+
+// IsValid reports whether the complaint category is known
+func (c ComplaintCategory) IsValid() bool {
+	switch c {
+	case ComplaintCardiacArrest, ComplaintChestPain, ComplaintShortnessOfBreath, ComplaintAlteredConsciousness,
+		ComplaintStrokeSymptoms, ComplaintSeizure, ComplaintMajorTrauma, ComplaintHeadInjury, ComplaintMinorInjury,
+		ComplaintBleeding, ComplaintAbdominalPain, ComplaintAllergicReaction, ComplaintPoisoning, ComplaintBurn,
+		ComplaintFever, ComplaintPregnancy, ComplaintMentalHealth, ComplaintOther:
+		return true
+	default:
+		return false
+	}
+}
+
+// Consciousness represents a patient's level of consciousness on the ACVPU scale
+type Consciousness string
+
+const (
+	ConsciousnessAlert        Consciousness = "ALERT"
+	ConsciousnessConfused     Consciousness = "CONFUSED" // New confusion
+	ConsciousnessVoice        Consciousness = "VOICE"    // Responds to voice
+	ConsciousnessPain         Consciousness = "PAIN"     // Responds to pain
+	ConsciousnessUnresponsive Consciousness = "UNRESPONSIVE"
+)
+
+// Rank returns how impaired the level of consciousness is, from 0 (alert) to 4
+// (unresponsive), or -1 when it is not recorded
+func (c Consciousness) Rank() int {
+	switch c {
+	case ConsciousnessAlert:
+		return 0
+	case ConsciousnessConfused:
+		return 1
+	case ConsciousnessVoice:
+		return 2
+	case ConsciousnessPain:
+		return 3
+	case ConsciousnessUnresponsive:
+		return 4
+	default:
+		return -1
+	}
+}
+
+// TriageParameter represents the finding a triage rule tests
+type TriageParameter string
+
+const (
+	TriageParamComplaint        TriageParameter = "COMPLAINT" // Matches on the complaint category alone
+	TriageParamTemperature      TriageParameter = "TEMPERATURE"
+	TriageParamHeartRate        TriageParameter = "HEART_RATE"
+	TriageParamRespiratoryRate  TriageParameter = "RESPIRATORY_RATE"
+	TriageParamOxygenSaturation TriageParameter = "OXYGEN_SATURATION"
+	TriageParamSystolicBP       TriageParameter = "SYSTOLIC_BP"
+	TriageParamPainScore        TriageParameter = "PAIN_SCORE"
+	TriageParamConsciousness    TriageParameter = "CONSCIOUSNESS" // Compared as the ACVPU rank
+	TriageParamAge              TriageParameter = "AGE"           // Years
+)
+
+// TriageOperator represents how a triage rule compares a finding to its threshold
+type TriageOperator string
+
+const (
+	TriageOpLT  TriageOperator = "LT"
+	TriageOpLTE TriageOperator = "LTE"
+	TriageOpGT  TriageOperator = "GT"
+	TriageOpGTE TriageOperator = "GTE"
+	TriageOpEQ  TriageOperator = "EQ"
+)
+
+// Compare reports whether value compares to threshold as the operator says
+func (o TriageOperator) Compare(value, threshold float64) bool {
+	switch o {
+	case TriageOpLT:
+		return value < threshold
+	case TriageOpLTE:
+		return value <= threshold
+	case TriageOpGT:
+		return value > threshold
+	case TriageOpGTE:
+		return value >= threshold
+	case TriageOpEQ:
+		return value == threshold
+	default:
+		return false
+	}
+}
+
+// TriageRule represents one discriminator of a triage ruleset: a patient
+// whose finding matches is at least as urgent as the rule's acuity level
+type TriageRule struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+
+	Scale TriageScale `gorm:"size:10;not null;index" json:"scale"`
+	Name  string      `gorm:"size:200;not null" json:"name"`
+
+	// Empty matches any complaint
+	ComplaintCategory ComplaintCategory `gorm:"size:30" json:"complaint_category,omitempty"`
+
+	Parameter   TriageParameter `gorm:"size:30;not null" json:"parameter"`
+	Operator    TriageOperator  `gorm:"size:5" json:"operator,omitempty"`
+	Threshold   float64         `json:"threshold"`
+	AcuityLevel int             `gorm:"not null" json:"acuity_level"`
+
+	IsActive bool `gorm:"default:true" json:"is_active"`
+
+	// Audit fields
+	CreatedBy *uint `json:"created_by,omitempty"`
+	UpdatedBy *uint `json:"updated_by,omitempty"`
+}
+
+// TableName specifies the table name for TriageRule model
+func (TriageRule) TableName() string {
+	return "triage_rules"
+}
+
+// VisitTriage represents one triage assessment of a visit. Re-triage adds a
+// new assessment; the latest one sets the visit's acuity level.
+type VisitTriage struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	VisitID   uint   `gorm:"not null;uniqueIndex:idx_visit_triages_sequence" json:"visit_id"`
+	Visit     *Visit `gorm:"foreignKey:VisitID" json:"visit,omitempty"`
+	PatientID uint   `gorm:"not null;index" json:"patient_id"`
+	Sequence  int    `gorm:"not null;uniqueIndex:idx_visit_triages_sequence" json:"sequence"` // 1 for the first assessment
+
+	TriagedBy     uint      `gorm:"not null" json:"triaged_by"`
+	TriagedByUser *User     `gorm:"foreignKey:TriagedBy" json:"triaged_by_user,omitempty"`
+	TriagedAt     time.Time `gorm:"not null" json:"triaged_at"`
+
+	// Presentation
+	ComplaintCategory ComplaintCategory `gorm:"size:30;not null" json:"complaint_category"`
+	ComplaintNotes    string            `gorm:"type:text" json:"complaint_notes"`
+	PainScore         *int              `json:"pain_score,omitempty"` // 0-10
+	Consciousness     Consciousness     `gorm:"size:20" json:"consciousness,omitempty"`
+
+	// Vital signs, nil when not measured
+	Temperature            *float64 `json:"temperature,omitempty"`
+	BloodPressureSystolic  *int     `json:"blood_pressure_systolic,omitempty"`
+	BloodPressureDiastolic *int     `json:"blood_pressure_diastolic,omitempty"`
+	HeartRate              *int     `json:"heart_rate,omitempty"`
+	RespiratoryRate        *int     `json:"respiratory_rate,omitempty"`
+	OxygenSaturation       *int     `json:"oxygen_saturation,omitempty"`
+
+	// Acuity
+	Scale          TriageScale `gorm:"size:10;not null" json:"scale"`
+	ComputedLevel  int         `gorm:"not null" json:"computed_level"` // From the ruleset
+	AcuityLevel    int         `gorm:"not null" json:"acuity_level"`   // Differs from the computed level when overridden
+	OverrideReason string      `gorm:"type:text" json:"override_reason,omitempty"`
+	MatchedRules   string      `gorm:"type:text" json:"matched_rules,omitempty"` // Names of the rules that set the computed level
+}
+
+// TableName specifies the table name for VisitTriage model
+func (VisitTriage) TableName() string {
+	return "visit_triages"
+}
@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"database/sql/driver"
+	"encoding/json"
 	"time"
 
 	"gorm.io/gorm"
@@ -22,10 +24,53 @@ type VisitStatus string
 const (
 	VisitStatusWaiting    VisitStatus = "WAITING"
 	VisitStatusInProgress VisitStatus = "IN_PROGRESS"
+	VisitStatusPaused     VisitStatus = "PAUSED" // Consultation on hold, e.g. waiting for results
 	VisitStatusCompleted  VisitStatus = "COMPLETED"
 	VisitStatusCancelled  VisitStatus = "CANCELLED"
 )
 
+// visitTransitions lists the statuses each status may move to.
+// COMPLETED and CANCELLED are final.
+var visitTransitions = map[VisitStatus][]VisitStatus{
+	VisitStatusWaiting: {
+		VisitStatusInProgress,
+		VisitStatusCancelled,
+	},
+	VisitStatusInProgress: {
+		VisitStatusPaused,
+		VisitStatusCompleted,
+		VisitStatusCancelled,
+	},
+	VisitStatusPaused: {
+		VisitStatusInProgress,
+		VisitStatusCancelled,
+	},
+}
+
+// CanTransitionTo reports whether a visit may move from this status to next
+func (s VisitStatus) CanTransitionTo(next VisitStatus) bool {
+	for _, allowed := range visitTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsClosed reports whether the visit is completed or cancelled
+func (s VisitStatus) IsClosed() bool {
+	return s == VisitStatusCompleted || s == VisitStatusCancelled
+}
+
+// VisitPauseReason represents why a consultation is on hold
+type VisitPauseReason string
+
+const (
+	VisitPauseAwaitingResults VisitPauseReason = "AWAITING_RESULTS" // Lab or imaging results
+	VisitPauseProcedure       VisitPauseReason = "PROCEDURE"        // Procedure or treatment elsewhere
+	VisitPauseOther           VisitPauseReason = "OTHER"
+)
+
 // VitalSigns represents patient vital signs
 type VitalSigns struct {
 	Temperature            float64 `json:"temperature"`              // °C
@@ -37,6 +82,27 @@ type VitalSigns struct {
 	Weight                 float64 `json:"weight"`                   // kg
 	Height                 float64 `json:"height"`                   // cm
 	BMI                    float64 `json:"bmi"`                      // calculated
+
+	SupplementalOxygen *bool         `json:"supplemental_oxygen,omitempty"` // On oxygen rather than room air, nil when not recorded
+	Consciousness      Consciousness `json:"consciousness,omitempty"`       // ACVPU
+}
+
+// Scan implements the sql.Scanner interface
+func (v *VitalSigns) Scan(value interface{}) error {
+	if value == nil {
+		*v = VitalSigns{}
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, v)
+}
+
+// Value implements the driver.Valuer interface
+func (v VitalSigns) Value() (driver.Value, error) {
+	return json.Marshal(v)
 }
 
 // Visit represents a patient visit/examination
@@ -59,6 +125,9 @@ type Visit struct {
 	DoctorID uint  `gorm:"not null;index" json:"doctor_id"`
 	Doctor   *User `gorm:"foreignKey:DoctorID" json:"doctor,omitempty"`
 
+	CoverageID *uint            `gorm:"index" json:"coverage_id,omitempty"` // Nullable for self-pay visits
+	Coverage   *PatientCoverage `gorm:"foreignKey:CoverageID" json:"coverage,omitempty"`
+
 	// Visit Details
 	VisitDate time.Time   `gorm:"not null;index" json:"visit_date"`
 	VisitTime time.Time   `gorm:"not null" json:"visit_time"`
@@ -69,6 +138,19 @@ type Visit struct {
 	ChiefComplaint string `gorm:"type:text;not null" json:"chief_complaint"`
 	Symptoms       string `gorm:"type:text" json:"symptoms"`
 
+	// Triage, from the latest assessment
+	AcuityLevel *int       `gorm:"index" json:"acuity_level,omitempty"`
+	TriagedAt   *time.Time `json:"triaged_at,omitempty"`
+
+	// Stage timestamps
+	CheckedInAt           time.Time        `gorm:"not null;index" json:"checked_in_at"`
+	ConsultationStartedAt *time.Time       `json:"consultation_started_at,omitempty"` // First start
+	PausedAt              *time.Time       `json:"paused_at,omitempty"`               // Start of the current or last hold
+	PauseReason           VisitPauseReason `gorm:"size:20" json:"pause_reason,omitempty"`
+	PausedSeconds         int              `gorm:"default:0" json:"paused_seconds"` // Total time on hold
+	ConsultationEndedAt   *time.Time       `json:"consultation_ended_at,omitempty"`
+	DischargedAt          *time.Time       `json:"discharged_at,omitempty"`
+
 	// Vital Signs (embedded)
 	Temperature            float64 `json:"temperature"`
 	BloodPressureSystolic  int     `json:"blood_pressure_systolic"`
@@ -80,6 +162,9 @@ type Visit struct {
 	Height                 float64 `json:"height"`
 	BMI                    float64 `json:"bmi"`
 
+	SupplementalOxygen *bool         `json:"supplemental_oxygen,omitempty"`          // Nil when not recorded
+	Consciousness      Consciousness `gorm:"size:20" json:"consciousness,omitempty"` // ACVPU
+
 	// Clinical Documentation
 	PhysicalExamination  string     `gorm:"type:text" json:"physical_examination"`
 	ClinicalNotes        string     `gorm:"type:text" json:"clinical_notes"`
@@ -97,6 +182,43 @@ func (Visit) TableName() string {
 	return "visits"
 }
 
+// VisitStatusHistory represents one status change of a visit
+type VisitStatusHistory struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	VisitID    uint        `gorm:"not null;index" json:"visit_id"`
+	FromStatus VisitStatus `gorm:"size:20;not null" json:"from_status"`
+	ToStatus   VisitStatus `gorm:"size:20;not null" json:"to_status"`
+	Reason     string      `gorm:"type:text" json:"reason,omitempty"`
+
+	ChangedAt time.Time `gorm:"not null" json:"changed_at"`
+	ChangedBy uint      `gorm:"not null" json:"changed_by"`
+	User      *User     `gorm:"foreignKey:ChangedBy" json:"user,omitempty"`
+}
+
+// TableName specifies the table name for VisitStatusHistory model
+func (VisitStatusHistory) TableName() string {
+	return "visit_status_history"
+}
+
+// VitalSigns returns the vital signs recorded on the visit
+func (v *Visit) VitalSigns() VitalSigns {
+	return VitalSigns{
+		Temperature:            v.Temperature,
+		BloodPressureSystolic:  v.BloodPressureSystolic,
+		BloodPressureDiastolic: v.BloodPressureDiastolic,
+		HeartRate:              v.HeartRate,
+		RespiratoryRate:        v.RespiratoryRate,
+		OxygenSaturation:       v.OxygenSaturation,
+		Weight:                 v.Weight,
+		Height:                 v.Height,
+		BMI:                    v.BMI,
+		SupplementalOxygen:     v.SupplementalOxygen,
+		Consciousness:          v.Consciousness,
+	}
+}
+
 // BeforeCreate hook to calculate BMI
 func (v *Visit) BeforeCreate(tx *gorm.DB) error {
 	if v.Weight > 0 && v.Height > 0 {
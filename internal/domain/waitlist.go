@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// WaitlistPriority represents how urgently a waitlisted patient should be seen
+type WaitlistPriority string
+
+const (
+	WaitlistPriorityUrgent WaitlistPriority = "URGENT"
+	WaitlistPriorityHigh   WaitlistPriority = "HIGH"
+	WaitlistPriorityNormal WaitlistPriority = "NORMAL"
+)
+
+// WaitlistStatus represents the status of a waitlist entry
+type WaitlistStatus string
+
+const (
+	WaitlistStatusWaiting   WaitlistStatus = "WAITING"
+	WaitlistStatusOffered   WaitlistStatus = "OFFERED"
+	WaitlistStatusBooked    WaitlistStatus = "BOOKED"
+	WaitlistStatusCancelled WaitlistStatus = "CANCELLED"
+	WaitlistStatusExpired   WaitlistStatus = "EXPIRED" // Preferred date range has passed
+)
+
+// WaitlistOfferStatus represents the status of a slot offered to a waitlisted patient
+type WaitlistOfferStatus string
+
+const (
+	WaitlistOfferStatusPending  WaitlistOfferStatus = "PENDING"
+	WaitlistOfferStatusAccepted WaitlistOfferStatus = "ACCEPTED"
+	WaitlistOfferStatusDeclined WaitlistOfferStatus = "DECLINED"
+	WaitlistOfferStatusExpired  WaitlistOfferStatus = "EXPIRED"
+)
+
+// WaitlistEntry represents a patient waiting for a slot with a doctor or in a department
+type WaitlistEntry struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+
+	// Foreign Keys
+	PatientID uint     `gorm:"not null;index" json:"patient_id"`
+	Patient   *Patient `gorm:"foreignKey:PatientID" json:"patient,omitempty"`
+
+	// Either a specific doctor or any doctor of a department
+	DoctorID     *uint       `gorm:"index" json:"doctor_id,omitempty"`
+	Doctor       *User       `gorm:"foreignKey:DoctorID" json:"doctor,omitempty"`
+	DepartmentID *uint       `gorm:"index" json:"department_id,omitempty"`
+	Department   *Department `gorm:"foreignKey:DepartmentID" json:"department,omitempty"`
+
+	// Preferences
+	PreferredFrom     time.Time `gorm:"type:date;not null" json:"preferred_from"`
+	PreferredTo       time.Time `gorm:"type:date;not null" json:"preferred_to"`
+	PreferredTimeFrom string    `gorm:"size:5" json:"preferred_time_from,omitempty"` // HH:MM, optional
+	PreferredTimeTo   string    `gorm:"size:5" json:"preferred_time_to,omitempty"`   // HH:MM, optional
+	DurationMinutes   int       `gorm:"not null;default:0" json:"duration_minutes"`  // 0 accepts any slot length
+
+	Priority        WaitlistPriority `gorm:"size:10;not null;default:'NORMAL'" json:"priority"`
+	AppointmentType AppointmentType  `gorm:"size:20;not null" json:"appointment_type"`
+	Reason          string           `gorm:"type:text" json:"reason"`
+	Notes           string           `gorm:"type:text" json:"notes"`
+
+	Status     WaitlistStatus `gorm:"size:20;not null;index;default:'WAITING'" json:"status"`
+	OfferCount int            `gorm:"not null;default:0" json:"offer_count"`
+
+	// Set once an offer is accepted
+	AppointmentID *uint        `json:"appointment_id,omitempty"`
+	Appointment   *Appointment `gorm:"foreignKey:AppointmentID" json:"appointment,omitempty"`
+
+	// Audit fields
+	CreatedBy uint `json:"created_by"`
+	UpdatedBy uint `json:"updated_by"`
+}
+
+// TableName specifies the table name for WaitlistEntry model
+func (WaitlistEntry) TableName() string {
+	return "waitlist_entries"
+}
+
+// WaitlistOffer represents a freed slot held for a waitlisted patient until it
+// is accepted, declined or expires
+type WaitlistOffer struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	EntryID uint           `gorm:"not null;index" json:"entry_id"`
+	Entry   *WaitlistEntry `gorm:"foreignKey:EntryID" json:"entry,omitempty"`
+
+	// Held slot
+	DoctorID        uint      `gorm:"not null;index" json:"doctor_id"`
+	Doctor          *User     `gorm:"foreignKey:DoctorID" json:"doctor,omitempty"`
+	AppointmentDate time.Time `gorm:"type:date;not null" json:"appointment_date"`
+	AppointmentTime time.Time `gorm:"type:time;not null" json:"appointment_time"`
+	DurationMinutes int       `gorm:"not null" json:"duration_minutes"`
+
+	// Appointment whose cancellation or reschedule freed the slot
+	SourceAppointmentID *uint `json:"source_appointment_id,omitempty"`
+
+	Status    WaitlistOfferStatus `gorm:"size:20;not null;index;default:'PENDING'" json:"status"`
+	ExpiresAt time.Time           `gorm:"not null;index" json:"expires_at"`
+
+	RespondedAt   *time.Time `json:"responded_at,omitempty"`
+	RespondedBy   *uint      `json:"responded_by,omitempty"`
+	AppointmentID *uint      `json:"appointment_id,omitempty"` // Set on acceptance
+}
+
+// TableName specifies the table name for WaitlistOffer model
+func (WaitlistOffer) TableName() string {
+	return "waitlist_offers"
+}
@@ -12,6 +12,7 @@ type CreateAppointmentRequest struct {
 	AppointmentType string `json:"appointment_type" binding:"required,oneof=CONSULTATION FOLLOW_UP EMERGENCY CHECKUP"`
 	Reason          string `json:"reason" binding:"required,min=5"`
 	Notes           string `json:"notes" binding:"omitempty"`
+	ResourceIDs     []uint `json:"resource_ids" binding:"omitempty,max=5,dive,gt=0"` // Rooms and equipment to reserve
 }
 
 // UpdateAppointmentRequest represents request to update/reschedule appointment
@@ -45,8 +46,24 @@ type AppointmentResponse struct {
 	Notes           string     `json:"notes"`
 	CancelledReason string     `json:"cancelled_reason,omitempty"`
 	CancelledAt     *time.Time `json:"cancelled_at,omitempty"`
+	BookingSource   string     `json:"booking_source"`
+	SeriesID        *uint      `json:"series_id,omitempty"`
+	SeriesIndex     int        `json:"series_index,omitempty"`
 	CreatedAt       time.Time  `json:"created_at"`
 	UpdatedAt       time.Time  `json:"updated_at"`
+
+	Resources     []*BookedResource          `json:"resources,omitempty"`
+	StatusHistory []*AppointmentStatusChange `json:"status_history,omitempty"` // Only on GET /appointments/:id
+}
+
+// AppointmentStatusChange represents one recorded status change of an appointment
+type AppointmentStatusChange struct {
+	FromStatus    string    `json:"from_status"`
+	ToStatus      string    `json:"to_status"`
+	Reason        string    `json:"reason,omitempty"`
+	ChangedAt     time.Time `json:"changed_at"`
+	ChangedBy     *uint     `json:"changed_by"`
+	ChangedByName string    `json:"changed_by_name,omitempty"` // Empty for patient and system changes
 }
 
 // AppointmentListItem represents simplified appointment for list view
@@ -64,5 +81,63 @@ type AppointmentListItem struct {
 // TimeSlot represents an available time slot
 type TimeSlot struct {
 	Time      string `json:"time"`
+	EndTime   string `json:"end_time"`
+	Room      string `json:"room,omitempty"`
 	Available bool   `json:"available"`
 }
+
+// DayAvailability represents a doctor's slots on one day; ClosedReason
+// explains a day without clinic sessions
+type DayAvailability struct {
+	Date           string      `json:"date"`
+	ClosedReason   string      `json:"closed_reason,omitempty"`
+	AvailableSlots int         `json:"available_slots"`
+	Slots          []*TimeSlot `json:"slots"`
+}
+
+// DoctorAvailability represents a doctor's slots over a date range
+type DoctorAvailability struct {
+	DoctorID       uint               `json:"doctor_id"`
+	DoctorName     string             `json:"doctor_name"`
+	DepartmentID   *uint              `json:"department_id,omitempty"`
+	DepartmentName string             `json:"department_name,omitempty"`
+	AvailableSlots int                `json:"available_slots"`
+	Days           []*DayAvailability `json:"days"`
+}
+
+// AvailabilityResponse represents the slots of doctors over a date range
+type AvailabilityResponse struct {
+	From            string                `json:"from"`
+	To              string                `json:"to"`
+	DurationMinutes int                   `json:"duration_minutes"` // 0 means each session's slot length
+	Doctors         []*DoctorAvailability `json:"doctors"`
+}
+
+// PatientNoShowStats represents a patient's attendance record over the no-show policy window
+type PatientNoShowStats struct {
+	PatientID            uint    `json:"patient_id"`
+	WindowDays           int     `json:"window_days"`
+	Attended             int64   `json:"attended"`
+	NoShows              int64   `json:"no_shows"`
+	Cancelled            int64   `json:"cancelled"`
+	NoShowRate           float64 `json:"no_show_rate"` // No-shows over attended plus no-shows
+	LifetimeNoShows      int64   `json:"lifetime_no_shows"`
+	LastNoShowDate       *string `json:"last_no_show_date,omitempty"`
+	RestrictionThreshold int     `json:"restriction_threshold"` // 0 when restrictions are disabled
+	BookingRestricted    bool    `json:"booking_restricted"`
+	PortalOnly           bool    `json:"portal_only"` // Restriction applies to portal bookings only
+}
+
+// DoctorOverbookingResponse represents a doctor's historical no-show rate and overbooking allowance
+type DoctorOverbookingResponse struct {
+	DoctorID        uint    `json:"doctor_id"`
+	DoctorName      string  `json:"doctor_name"`
+	LookbackDays    int     `json:"lookback_days"`
+	Attended        int64   `json:"attended"`
+	NoShows         int64   `json:"no_shows"`
+	NoShowRate      float64 `json:"no_show_rate"`
+	MinSample       int     `json:"min_sample"`
+	MaxPercent      int     `json:"max_percent"`      // 0 when overbooking is disabled
+	OverbookPercent float64 `json:"overbook_percent"` // Share of each session's patient limit that may be booked on top
+	MaxPerSlot      int     `json:"max_per_slot"`     // Bookings one time slot may hold while its session is overbooked
+}
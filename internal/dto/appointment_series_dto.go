@@ -0,0 +1,72 @@
+package dto
+
+import "time"
+
+// CreateAppointmentSeriesRequest represents request to book recurring appointments
+type CreateAppointmentSeriesRequest struct {
+	PatientID       uint   `json:"patient_id" binding:"required"`
+	DoctorID        uint   `json:"doctor_id" binding:"required"`
+	StartDate       string `json:"start_date" binding:"required"`                      // YYYY-MM-DD
+	AppointmentTime string `json:"appointment_time" binding:"required,datetime=15:04"` // HH:MM
+	DurationMinutes int    `json:"duration_minutes" binding:"omitempty,oneof=15 30 45 60"`
+	AppointmentType string `json:"appointment_type" binding:"required,oneof=CONSULTATION FOLLOW_UP CHECKUP"`
+	RecurrenceRule  string `json:"recurrence_rule" binding:"required,max=255"` // e.g. FREQ=WEEKLY;BYDAY=MO,TH;COUNT=12
+	Reason          string `json:"reason" binding:"required,min=5"`
+	Notes           string `json:"notes" binding:"omitempty"`
+	SkipConflicts   bool   `json:"skip_conflicts"` // Book the free occurrences and skip conflicting ones
+}
+
+// SeriesOccurrence represents one expanded occurrence and whether it can be booked
+type SeriesOccurrence struct {
+	Index           int    `json:"index"`
+	AppointmentID   uint   `json:"appointment_id,omitempty"`
+	AppointmentDate string `json:"appointment_date"`
+	AppointmentTime string `json:"appointment_time"`
+	Available       bool   `json:"available"`
+	Conflict        string `json:"conflict,omitempty"` // PAST_DATE, DOCTOR_NOT_AVAILABLE, OUTSIDE_SESSION, SESSION_FULL, TIME_SLOT_TAKEN
+}
+
+// SeriesPreviewResponse represents the expanded occurrences of a series before booking
+type SeriesPreviewResponse struct {
+	RecurrenceRule string              `json:"recurrence_rule"`
+	Occurrences    []*SeriesOccurrence `json:"occurrences"`
+	ConflictCount  int                 `json:"conflict_count"`
+}
+
+// RescheduleSeriesRequest represents request to move one, following or all occurrences
+type RescheduleSeriesRequest struct {
+	Scope           string `json:"scope" binding:"required,oneof=ONE FOLLOWING ALL"`
+	AppointmentID   uint   `json:"appointment_id" binding:"omitempty"`                  // Required for ONE and FOLLOWING
+	AppointmentDate string `json:"appointment_date" binding:"omitempty"`                // ONE only
+	AppointmentTime string `json:"appointment_time" binding:"omitempty,datetime=15:04"` // New time of day
+	ShiftDays       int    `json:"shift_days" binding:"omitempty,min=-365,max=365"`     // FOLLOWING and ALL only
+	DurationMinutes int    `json:"duration_minutes" binding:"omitempty,oneof=15 30 45 60"`
+}
+
+// CancelSeriesRequest represents request to cancel one, following or all occurrences
+type CancelSeriesRequest struct {
+	Scope         string `json:"scope" binding:"required,oneof=ONE FOLLOWING ALL"`
+	AppointmentID uint   `json:"appointment_id" binding:"omitempty"` // Required for ONE and FOLLOWING
+	Reason        string `json:"reason" binding:"required,min=5"`
+}
+
+// AppointmentSeriesResponse represents series details with its occurrences
+type AppointmentSeriesResponse struct {
+	ID              uint                   `json:"id"`
+	PatientID       uint                   `json:"patient_id"`
+	PatientName     string                 `json:"patient_name"`
+	DoctorID        uint                   `json:"doctor_id"`
+	DoctorName      string                 `json:"doctor_name"`
+	RecurrenceRule  string                 `json:"recurrence_rule"`
+	StartDate       string                 `json:"start_date"`
+	AppointmentTime string                 `json:"appointment_time"`
+	DurationMinutes int                    `json:"duration_minutes"`
+	AppointmentType string                 `json:"appointment_type"`
+	Status          string                 `json:"status"`
+	Reason          string                 `json:"reason"`
+	Notes           string                 `json:"notes"`
+	Appointments    []*AppointmentListItem `json:"appointments"`
+	Skipped         []*SeriesOccurrence    `json:"skipped,omitempty"` // Conflicting occurrences not booked
+	CreatedAt       time.Time              `json:"created_at"`
+	UpdatedAt       time.Time              `json:"updated_at"`
+}
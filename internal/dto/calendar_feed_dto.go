@@ -0,0 +1,13 @@
+package dto
+
+import "time"
+
+// CalendarFeedResponse represents a user's iCalendar feed
+type CalendarFeedResponse struct {
+	ID             uint       `json:"id"`
+	UserID         uint       `json:"user_id"`
+	URL            string     `json:"url,omitempty"` // Only returned when the feed is created; keep it secret
+	PatientDetail  string     `json:"patient_detail"`
+	CreatedAt      time.Time  `json:"created_at"`
+	LastAccessedAt *time.Time `json:"last_accessed_at,omitempty"`
+}
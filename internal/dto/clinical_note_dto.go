@@ -0,0 +1,120 @@
+package dto
+
+import "time"
+
+// CreateClinicalNoteTemplateRequest represents request to create a clinical note template
+type CreateClinicalNoteTemplateRequest struct {
+	Code         string `json:"code" binding:"required,max=50"`
+	Name         string `json:"name" binding:"required,max=200"`
+	DepartmentID *uint  `json:"department_id" binding:"omitempty"` // Empty for all departments
+	NoteType     string `json:"note_type" binding:"required,oneof=CONSULTATION PROGRESS PROCEDURE DISCHARGE"`
+	Subjective   string `json:"subjective" binding:"omitempty"`
+	Objective    string `json:"objective" binding:"omitempty"`
+	Assessment   string `json:"assessment" binding:"omitempty"`
+	Plan         string `json:"plan" binding:"omitempty"`
+}
+
+// UpdateClinicalNoteTemplateRequest represents request to update a clinical note template
+type UpdateClinicalNoteTemplateRequest struct {
+	Name       string  `json:"name" binding:"omitempty,max=200"`
+	Subjective *string `json:"subjective" binding:"omitempty"`
+	Objective  *string `json:"objective" binding:"omitempty"`
+	Assessment *string `json:"assessment" binding:"omitempty"`
+	Plan       *string `json:"plan" binding:"omitempty"`
+	IsActive   *bool   `json:"is_active" binding:"omitempty"`
+}
+
+// ClinicalNoteTemplateResponse represents a clinical note template
+type ClinicalNoteTemplateResponse struct {
+	ID             uint      `json:"id"`
+	Code           string    `json:"code"`
+	Name           string    `json:"name"`
+	DepartmentID   *uint     `json:"department_id,omitempty"`
+	DepartmentName string    `json:"department_name,omitempty"`
+	NoteType       string    `json:"note_type"`
+	Subjective     string    `json:"subjective"`
+	Objective      string    `json:"objective"`
+	Assessment     string    `json:"assessment"`
+	Plan           string    `json:"plan"`
+	IsActive       bool      `json:"is_active"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// CreateClinicalNoteRequest represents request to start a clinical note for a
+// visit. Sections left empty are prefilled from the template.
+type CreateClinicalNoteRequest struct {
+	NoteType   string `json:"note_type" binding:"required,oneof=CONSULTATION PROGRESS PROCEDURE DISCHARGE"`
+	TemplateID *uint  `json:"template_id" binding:"omitempty"`
+	Subjective string `json:"subjective" binding:"omitempty"`
+	Objective  string `json:"objective" binding:"omitempty"`
+	Assessment string `json:"assessment" binding:"omitempty"`
+	Plan       string `json:"plan" binding:"omitempty"`
+}
+
+// UpdateClinicalNoteRequest represents request to edit a draft clinical note
+type UpdateClinicalNoteRequest struct {
+	Version    int     `json:"version" binding:"required,min=1"` // The version the edit was made on
+	Subjective *string `json:"subjective" binding:"omitempty"`
+	Objective  *string `json:"objective" binding:"omitempty"`
+	Assessment *string `json:"assessment" binding:"omitempty"`
+	Plan       *string `json:"plan" binding:"omitempty"`
+}
+
+// SignClinicalNoteRequest represents request to sign a clinical note
+type SignClinicalNoteRequest struct {
+	Version    int   `json:"version" binding:"required,min=1"` // The version the author reviewed
+	CosignerID *uint `json:"cosigner_id" binding:"omitempty"`  // Required when the author is a resident
+}
+
+// CreateClinicalNoteAddendumRequest represents request to add an addendum to a signed note
+type CreateClinicalNoteAddendumRequest struct {
+	Content string `json:"content" binding:"required"`
+}
+
+// ClinicalNoteAddendumResponse represents an addendum to a clinical note
+type ClinicalNoteAddendumResponse struct {
+	ID         uint      `json:"id"`
+	AuthorID   uint      `json:"author_id"`
+	AuthorName string    `json:"author_name,omitempty"`
+	Content    string    `json:"content"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ClinicalNoteResponse represents a clinical note
+type ClinicalNoteResponse struct {
+	ID           uint       `json:"id"`
+	VisitID      uint       `json:"visit_id"`
+	PatientID    uint       `json:"patient_id"`
+	AuthorID     uint       `json:"author_id"`
+	AuthorName   string     `json:"author_name,omitempty"`
+	DepartmentID *uint      `json:"department_id,omitempty"`
+	TemplateID   *uint      `json:"template_id,omitempty"`
+	NoteType     string     `json:"note_type"`
+	Status       string     `json:"status"`
+	Version      int        `json:"version"`
+	Subjective   string     `json:"subjective"`
+	Objective    string     `json:"objective"`
+	Assessment   string     `json:"assessment"`
+	Plan         string     `json:"plan"`
+	SignedAt     *time.Time `json:"signed_at,omitempty"`
+	CosignerID   *uint      `json:"cosigner_id,omitempty"`
+	CosignerName string     `json:"cosigner_name,omitempty"`
+	CosignedAt   *time.Time `json:"cosigned_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+
+	Addenda []*ClinicalNoteAddendumResponse `json:"addenda"`
+}
+
+// ClinicalNoteVersionResponse represents the sections of a clinical note as saved at one version
+type ClinicalNoteVersionResponse struct {
+	Version    int       `json:"version"`
+	Subjective string    `json:"subjective"`
+	Objective  string    `json:"objective"`
+	Assessment string    `json:"assessment"`
+	Plan       string    `json:"plan"`
+	EditedBy   uint      `json:"edited_by"`
+	EditorName string    `json:"editor_name,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,56 @@
+package dto
+
+import "time"
+
+// DeathCauseRequest represents one line of the cause-of-death chain
+type DeathCauseRequest struct {
+	Line          string `json:"line" binding:"required,oneof=A B C D CONTRIBUTING"`
+	ICD10Code     string `json:"icd10_code" binding:"required"`
+	Description   string `json:"description" binding:"omitempty,max=255"`
+	OnsetInterval string `json:"onset_interval" binding:"omitempty,max=50"`
+}
+
+// RegisterDeathRequest represents request to register a patient's death
+type RegisterDeathRequest struct {
+	DeathDate          string              `json:"death_date" binding:"required"` // YYYY-MM-DD
+	DeathTime          string              `json:"death_time" binding:"required"` // HH:MM
+	PlaceOfDeath       string              `json:"place_of_death" binding:"required,oneof=HOSPITAL HOME TRANSIT OTHER"`
+	PlaceDetails       string              `json:"place_details" binding:"omitempty,max=255"`
+	MannerOfDeath      string              `json:"manner_of_death" binding:"omitempty,oneof=NATURAL ACCIDENT SUICIDE HOMICIDE UNDETERMINED"`
+	AdmissionID        *uint               `json:"admission_id" binding:"omitempty"`
+	CertifyingDoctorID uint                `json:"certifying_doctor_id" binding:"required"`
+	Causes             []DeathCauseRequest `json:"causes" binding:"required,min=1,dive"`
+	Notes              string              `json:"notes" binding:"omitempty"`
+}
+
+// DeathCauseResponse represents a cause-of-death line
+type DeathCauseResponse struct {
+	Line          string `json:"line"`
+	ICD10Code     string `json:"icd10_code"`
+	ICD10Name     string `json:"icd10_name"`
+	Description   string `json:"description"`
+	OnsetInterval string `json:"onset_interval"`
+}
+
+// DeathRecordResponse represents death record details
+type DeathRecordResponse struct {
+	ID                    uint                  `json:"id"`
+	CertificateNumber     string                `json:"certificate_number"`
+	PatientID             uint                  `json:"patient_id"`
+	PatientName           string                `json:"patient_name"`
+	PatientCode           string                `json:"patient_code"`
+	AdmissionID           *uint                 `json:"admission_id,omitempty"`
+	DeathDateTime         time.Time             `json:"death_date_time"`
+	PlaceOfDeath          string                `json:"place_of_death"`
+	PlaceDetails          string                `json:"place_details"`
+	MannerOfDeath         string                `json:"manner_of_death"`
+	CertifyingDoctorID    uint                  `json:"certifying_doctor_id"`
+	CertifyingDoctorName  string                `json:"certifying_doctor_name"`
+	UnderlyingCause       string                `json:"underlying_cause"`
+	UnderlyingCauseName   string                `json:"underlying_cause_name"`
+	Causes                []*DeathCauseResponse `json:"causes"`
+	Notes                 string                `json:"notes"`
+	CancelledAppointments int                   `json:"cancelled_appointments,omitempty"`
+	ClosedAdmissions      int                   `json:"closed_admissions,omitempty"`
+	CreatedAt             time.Time             `json:"created_at"`
+}
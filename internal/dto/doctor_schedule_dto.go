@@ -0,0 +1,140 @@
+package dto
+
+import "time"
+
+// CreateDoctorScheduleRequest represents request to add a weekly clinic session
+type CreateDoctorScheduleRequest struct {
+	DayOfWeek     *int   `json:"day_of_week" binding:"required,min=0,max=6"` // 0 = Sunday ... 6 = Saturday
+	StartTime     string `json:"start_time" binding:"required,datetime=15:04"`
+	EndTime       string `json:"end_time" binding:"required,datetime=15:04"`
+	BreakStart    string `json:"break_start" binding:"omitempty,datetime=15:04"`
+	BreakEnd      string `json:"break_end" binding:"omitempty,datetime=15:04"`
+	Room          string `json:"room" binding:"omitempty,max=50"`
+	SlotMinutes   int    `json:"slot_minutes" binding:"required,min=5,max=240"`
+	MaxPatients   int    `json:"max_patients" binding:"omitempty,min=0"`
+	EffectiveFrom string `json:"effective_from" binding:"omitempty"` // YYYY-MM-DD, defaults to today
+	EffectiveTo   string `json:"effective_to" binding:"omitempty"`   // YYYY-MM-DD
+}
+
+// UpdateDoctorScheduleRequest represents request to update a weekly clinic session
+type UpdateDoctorScheduleRequest struct {
+	StartTime     string `json:"start_time" binding:"omitempty,datetime=15:04"`
+	EndTime       string `json:"end_time" binding:"omitempty,datetime=15:04"`
+	BreakStart    string `json:"break_start" binding:"omitempty,datetime=15:04"`
+	BreakEnd      string `json:"break_end" binding:"omitempty,datetime=15:04"`
+	ClearBreak    bool   `json:"clear_break"`
+	Room          string `json:"room" binding:"omitempty,max=50"`
+	SlotMinutes   int    `json:"slot_minutes" binding:"omitempty,min=5,max=240"`
+	MaxPatients   *int   `json:"max_patients" binding:"omitempty,min=0"`
+	EffectiveFrom string `json:"effective_from" binding:"omitempty"`
+	EffectiveTo   string `json:"effective_to" binding:"omitempty"`
+	IsActive      *bool  `json:"is_active" binding:"omitempty"`
+}
+
+// DoctorScheduleResponse represents a weekly clinic session
+type DoctorScheduleResponse struct {
+	ID            uint      `json:"id"`
+	DoctorID      uint      `json:"doctor_id"`
+	DayOfWeek     int       `json:"day_of_week"`
+	StartTime     string    `json:"start_time"`
+	EndTime       string    `json:"end_time"`
+	BreakStart    string    `json:"break_start,omitempty"`
+	BreakEnd      string    `json:"break_end,omitempty"`
+	Room          string    `json:"room"`
+	SlotMinutes   int       `json:"slot_minutes"`
+	MaxPatients   int       `json:"max_patients"`
+	EffectiveFrom string    `json:"effective_from"`
+	EffectiveTo   string    `json:"effective_to,omitempty"`
+	IsActive      bool      `json:"is_active"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// CreateScheduleOverrideRequest represents request to replace a doctor's sessions on one date
+type CreateScheduleOverrideRequest struct {
+	OverrideDate string `json:"override_date" binding:"required"` // YYYY-MM-DD
+	IsAvailable  *bool  `json:"is_available" binding:"required"`
+	StartTime    string `json:"start_time" binding:"omitempty,datetime=15:04"`
+	EndTime      string `json:"end_time" binding:"omitempty,datetime=15:04"`
+	BreakStart   string `json:"break_start" binding:"omitempty,datetime=15:04"`
+	BreakEnd     string `json:"break_end" binding:"omitempty,datetime=15:04"`
+	Room         string `json:"room" binding:"omitempty,max=50"`
+	SlotMinutes  int    `json:"slot_minutes" binding:"omitempty,min=5,max=240"`
+	MaxPatients  int    `json:"max_patients" binding:"omitempty,min=0"`
+	Reason       string `json:"reason" binding:"omitempty,max=255"`
+}
+
+// ScheduleOverrideResponse represents a date-specific schedule override
+type ScheduleOverrideResponse struct {
+	ID           uint      `json:"id"`
+	DoctorID     uint      `json:"doctor_id"`
+	OverrideDate string    `json:"override_date"`
+	IsAvailable  bool      `json:"is_available"`
+	StartTime    string    `json:"start_time,omitempty"`
+	EndTime      string    `json:"end_time,omitempty"`
+	BreakStart   string    `json:"break_start,omitempty"`
+	BreakEnd     string    `json:"break_end,omitempty"`
+	Room         string    `json:"room"`
+	SlotMinutes  int       `json:"slot_minutes"`
+	MaxPatients  int       `json:"max_patients"`
+	Reason       string    `json:"reason"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// CreateDoctorLeaveRequest represents request to record a doctor's leave
+type CreateDoctorLeaveRequest struct {
+	StartDate string `json:"start_date" binding:"required"` // YYYY-MM-DD
+	EndDate   string `json:"end_date" binding:"required"`   // YYYY-MM-DD, inclusive
+	LeaveType string `json:"leave_type" binding:"required,oneof=ANNUAL SICK TRAINING OTHER"`
+	Reason    string `json:"reason" binding:"omitempty,max=255"`
+}
+
+// DoctorLeaveResponse represents a doctor's leave period
+type DoctorLeaveResponse struct {
+	ID                   uint      `json:"id"`
+	DoctorID             uint      `json:"doctor_id"`
+	StartDate            string    `json:"start_date"`
+	EndDate              string    `json:"end_date"`
+	LeaveType            string    `json:"leave_type"`
+	Reason               string    `json:"reason"`
+	AffectedAppointments int64     `json:"affected_appointments"` // Booked appointments that need rescheduling
+	CreatedAt            time.Time `json:"created_at"`
+}
+
+// CreateHolidayRequest represents request to add a facility holiday
+type CreateHolidayRequest struct {
+	HolidayDate string `json:"holiday_date" binding:"required"` // YYYY-MM-DD
+	Name        string `json:"name" binding:"required,max=200"`
+	IsRecurring bool   `json:"is_recurring"`
+}
+
+// HolidayResponse represents a facility holiday
+type HolidayResponse struct {
+	ID          uint      `json:"id"`
+	HolidayDate string    `json:"holiday_date"`
+	Name        string    `json:"name"`
+	IsRecurring bool      `json:"is_recurring"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ClinicSessionResponse represents a doctor's resolved session on a date
+type ClinicSessionResponse struct {
+	StartTime   string `json:"start_time"`
+	EndTime     string `json:"end_time"`
+	BreakStart  string `json:"break_start,omitempty"`
+	BreakEnd    string `json:"break_end,omitempty"`
+	Room        string `json:"room"`
+	SlotMinutes int    `json:"slot_minutes"`
+	MaxPatients int    `json:"max_patients"`
+	BookedCount int64  `json:"booked_count"`
+	Source      string `json:"source"` // TEMPLATE or OVERRIDE
+}
+
+// DoctorDayScheduleResponse represents a doctor's working sessions on a date
+type DoctorDayScheduleResponse struct {
+	DoctorID  uint                     `json:"doctor_id"`
+	Date      string                   `json:"date"`
+	IsWorking bool                     `json:"is_working"`
+	Reason    string                   `json:"reason,omitempty"` // Why the doctor is not working (leave, holiday, override)
+	Sessions  []*ClinicSessionResponse `json:"sessions"`
+}
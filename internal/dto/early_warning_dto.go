@@ -0,0 +1,39 @@
+package dto
+
+import "time"
+
+// NEWS2Response represents a NEWS2 score with the points of each parameter,
+// which are omitted when the parameter was not measured
+type NEWS2Response struct {
+	Total                    int    `json:"total"`
+	Risk                     string `json:"risk"`
+	Complete                 bool   `json:"complete"`  // All seven parameters were measured
+	RedScore                 bool   `json:"red_score"` // A single parameter scored 3
+	RespiratoryRatePoints    *int   `json:"respiratory_rate_points,omitempty"`
+	OxygenSaturationPoints   *int   `json:"oxygen_saturation_points,omitempty"`
+	SupplementalOxygenPoints *int   `json:"supplemental_oxygen_points,omitempty"`
+	SystolicBPPoints         *int   `json:"systolic_bp_points,omitempty"`
+	HeartRatePoints          *int   `json:"heart_rate_points,omitempty"`
+	ConsciousnessPoints      *int   `json:"consciousness_points,omitempty"`
+	TemperaturePoints        *int   `json:"temperature_points,omitempty"`
+}
+
+// EarlyWarningScoreResponse represents a NEWS2 score recorded for an admission
+type EarlyWarningScoreResponse struct {
+	ID             uint      `json:"id"`
+	AdmissionID    uint      `json:"admission_id"`
+	NursingNoteID  *uint     `json:"nursing_note_id,omitempty"`
+	RecordedAt     time.Time `json:"recorded_at"`
+	RecordedBy     uint      `json:"recorded_by"`
+	RecordedByName string    `json:"recorded_by_name,omitempty"`
+	NEWS2Response
+	EscalationLevel string `json:"escalation_level"`
+	Escalated       bool   `json:"escalated"` // Staff were notified of a rise in escalation level
+}
+
+// EarlyWarningTrendResponse represents an admission's NEWS2 scores over time
+type EarlyWarningTrendResponse struct {
+	AdmissionID uint                         `json:"admission_id"`
+	Latest      *EarlyWarningScoreResponse   `json:"latest,omitempty"`
+	Scores      []*EarlyWarningScoreResponse `json:"scores"` // Oldest first
+}
@@ -0,0 +1,58 @@
+package dto
+
+import "time"
+
+// ScheduleFollowUpRequest represents request to book an unscheduled follow-up
+type ScheduleFollowUpRequest struct {
+	DoctorID        *uint  `json:"doctor_id" binding:"omitempty"`       // Defaults to the doctor who ordered the follow-up
+	AppointmentDate string `json:"appointment_date" binding:"required"` // YYYY-MM-DD
+	AppointmentTime string `json:"appointment_time" binding:"required"` // HH:MM
+	DurationMinutes int    `json:"duration_minutes" binding:"omitempty,oneof=15 30 45 60"`
+}
+
+// CancelFollowUpRequest represents request to cancel a follow-up
+type CancelFollowUpRequest struct {
+	Reason string `json:"reason" binding:"required,min=5"`
+}
+
+// FollowUpResponse represents a follow-up ordered at the end of a visit
+type FollowUpResponse struct {
+	ID                uint       `json:"id"`
+	VisitID           uint       `json:"visit_id"`
+	VisitCode         string     `json:"visit_code,omitempty"`
+	PatientID         uint       `json:"patient_id"`
+	PatientName       string     `json:"patient_name,omitempty"`
+	PatientPhone      string     `json:"patient_phone,omitempty"`
+	DoctorID          uint       `json:"doctor_id"`
+	DoctorName        string     `json:"doctor_name,omitempty"`
+	DueDate           string     `json:"due_date"`
+	Instructions      string     `json:"instructions,omitempty"`
+	Status            string     `json:"status"`
+	AppointmentID     *uint      `json:"appointment_id,omitempty"`
+	AppointmentCode   string     `json:"appointment_code,omitempty"`
+	AppointmentDate   string     `json:"appointment_date,omitempty"`
+	AppointmentTime   string     `json:"appointment_time,omitempty"`
+	AppointmentStatus string     `json:"appointment_status,omitempty"`
+	ConfirmedBy       *uint      `json:"confirmed_by,omitempty"`
+	ConfirmedAt       *time.Time `json:"confirmed_at,omitempty"`
+	AttendedVisitID   *uint      `json:"attended_visit_id,omitempty"`
+	ResolvedAt        *time.Time `json:"resolved_at,omitempty"`
+	CancelReason      string     `json:"cancel_reason,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+// FollowUpAdherence represents how many follow-ups due within a period a
+// patient attended or missed, or the patients of a doctor did
+type FollowUpAdherence struct {
+	PatientID     *uint   `json:"patient_id,omitempty"`
+	DoctorID      *uint   `json:"doctor_id,omitempty"`
+	Name          string  `json:"name"`
+	From          string  `json:"from"`
+	To            string  `json:"to"`
+	Total         int64   `json:"total"`
+	Attended      int64   `json:"attended"`
+	Missed        int64   `json:"missed"`
+	Open          int64   `json:"open"` // Pending, unscheduled or confirmed
+	Cancelled     int64   `json:"cancelled"`
+	AdherenceRate float64 `json:"adherence_rate"` // Attended over attended plus missed
+}
@@ -19,7 +19,9 @@ type UpdateImagingRequestRequest struct {
 
 // ScheduleImagingRequest represents request to schedule imaging
 type ScheduleImagingRequest struct {
-	ScheduledDate string `json:"scheduled_date" binding:"required"` // ISO 8601 format
+	ScheduledDate   string `json:"scheduled_date" binding:"required"` // ISO 8601 format
+	DurationMinutes int    `json:"duration_minutes" binding:"omitempty,min=5,max=480"`
+	ResourceIDs     []uint `json:"resource_ids" binding:"omitempty,max=5,dive,gt=0"` // Omit to keep the booked resources, empty to release them
 }
 
 // ImagingResultResponse represents imaging result details
@@ -52,10 +54,13 @@ type ImagingRequestResponse struct {
 	Priority            string                 `json:"priority"`
 	RequestedDate       time.Time              `json:"requested_date"`
 	ScheduledDate       *time.Time             `json:"scheduled_date,omitempty"`
+	DurationMinutes     int                    `json:"duration_minutes"`
+	Resources           []*BookedResource      `json:"resources,omitempty"`
 	CompletedAt         *time.Time             `json:"completed_at,omitempty"`
 	ClinicalIndication  string                 `json:"clinical_indication"`
 	SpecialInstructions string                 `json:"special_instructions"`
 	Result              *ImagingResultResponse `json:"result,omitempty"`
+	PortalReleasedAt    *time.Time             `json:"portal_released_at,omitempty"`
 	CreatedAt           time.Time              `json:"created_at"`
 	UpdatedAt           time.Time              `json:"updated_at"`
 }
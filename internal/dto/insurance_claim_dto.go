@@ -4,11 +4,14 @@ import "time"
 
 // CreateInsuranceClaimRequest represents request to create insurance claim
 type CreateInsuranceClaimRequest struct {
-	InvoiceID         uint    `json:"invoice_id" binding:"required"`
-	InsuranceProvider string  `json:"insurance_provider" binding:"required"`
-	PolicyNumber      string  `json:"policy_number" binding:"required"`
-	ClaimAmount       float64 `json:"claim_amount" binding:"required,min=0"`
-	Notes             string  `json:"notes" binding:"omitempty"`
+	InvoiceID   uint    `json:"invoice_id" binding:"required"`
+	CoverageID  *uint   `json:"coverage_id" binding:"omitempty"`        // Defaults to the visit's or the patient's active coverage
+	ClaimAmount float64 `json:"claim_amount" binding:"omitempty,min=0"` // Defaults to invoice total x coverage percent
+	Notes       string  `json:"notes" binding:"omitempty"`
+
+	// Free-text insurance used when the patient has no active coverage, as before coverages were recorded
+	InsuranceProvider string `json:"insurance_provider" binding:"omitempty"`
+	PolicyNumber      string `json:"policy_number" binding:"omitempty"`
 }
 
 // ApproveClaimRequest represents request to approve claim
@@ -28,6 +31,8 @@ type InsuranceClaimResponse struct {
 	InvoiceID         uint       `json:"invoice_id"`
 	PatientID         uint       `json:"patient_id"`
 	PatientName       string     `json:"patient_name"`
+	CoverageID        *uint      `json:"coverage_id,omitempty"`
+	PayerID           *uint      `json:"payer_id,omitempty"`
 	InsuranceProvider string     `json:"insurance_provider"`
 	PolicyNumber      string     `json:"policy_number"`
 	ClaimAmount       float64    `json:"claim_amount"`
@@ -0,0 +1,37 @@
+package dto
+
+import "time"
+
+// CreateInsurancePayerRequest represents request to add a payer to the catalog
+type CreateInsurancePayerRequest struct {
+	Code        string `json:"code" binding:"required,max=50"`
+	Name        string `json:"name" binding:"required,max=200"`
+	PayerType   string `json:"payer_type" binding:"required,oneof=SOCIAL PRIVATE"`
+	PhoneNumber string `json:"phone_number" binding:"omitempty,max=20"`
+	Email       string `json:"email" binding:"omitempty,email,max=100"`
+	Address     string `json:"address" binding:"omitempty,max=255"`
+}
+
+// UpdateInsurancePayerRequest represents request to update a payer
+type UpdateInsurancePayerRequest struct {
+	Name        string `json:"name" binding:"omitempty,max=200"`
+	PayerType   string `json:"payer_type" binding:"omitempty,oneof=SOCIAL PRIVATE"`
+	PhoneNumber string `json:"phone_number" binding:"omitempty,max=20"`
+	Email       string `json:"email" binding:"omitempty,email,max=100"`
+	Address     string `json:"address" binding:"omitempty,max=255"`
+	IsActive    *bool  `json:"is_active" binding:"omitempty"`
+}
+
+// InsurancePayerResponse represents payer details
+type InsurancePayerResponse struct {
+	ID          uint      `json:"id"`
+	Code        string    `json:"code"`
+	Name        string    `json:"name"`
+	PayerType   string    `json:"payer_type"`
+	PhoneNumber string    `json:"phone_number"`
+	Email       string    `json:"email"`
+	Address     string    `json:"address"`
+	IsActive    bool      `json:"is_active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
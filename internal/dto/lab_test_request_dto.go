@@ -45,6 +45,7 @@ type LabTestRequestResponse struct {
 	CompletedAt       *time.Time               `json:"completed_at,omitempty"`
 	ClinicalNotes     string                   `json:"clinical_notes"`
 	Results           []*LabTestResultResponse `json:"results"`
+	PortalReleasedAt  *time.Time               `json:"portal_released_at,omitempty"`
 	CreatedAt         time.Time                `json:"created_at"`
 	UpdatedAt         time.Time                `json:"updated_at"`
 }
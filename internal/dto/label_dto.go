@@ -0,0 +1,56 @@
+package dto
+
+import "time"
+
+// CreateLabelTemplateRequest represents request to create a label template
+type CreateLabelTemplateRequest struct {
+	Code        string   `json:"code" binding:"required,max=50"`
+	Name        string   `json:"name" binding:"required,max=200"`
+	TargetType  string   `json:"target_type" binding:"required,oneof=PATIENT ADMISSION LAB_REQUEST"`
+	WidthMM     float64  `json:"width_mm" binding:"required,min=10,max=500"`
+	HeightMM    float64  `json:"height_mm" binding:"required,min=10,max=500"`
+	BarcodeType string   `json:"barcode_type" binding:"required,oneof=CODE128 QR NONE"`
+	Fields      []string `json:"fields" binding:"required,min=1,dive,required"`
+	FontSizePt  float64  `json:"font_size_pt" binding:"omitempty,min=4,max=72"`
+	Copies      int      `json:"copies" binding:"omitempty,min=1,max=50"`
+	IsDefault   bool     `json:"is_default"`
+}
+
+// UpdateLabelTemplateRequest represents request to update a label template
+type UpdateLabelTemplateRequest struct {
+	Name        string   `json:"name" binding:"omitempty,max=200"`
+	WidthMM     float64  `json:"width_mm" binding:"omitempty,min=10,max=500"`
+	HeightMM    float64  `json:"height_mm" binding:"omitempty,min=10,max=500"`
+	BarcodeType string   `json:"barcode_type" binding:"omitempty,oneof=CODE128 QR NONE"`
+	Fields      []string `json:"fields" binding:"omitempty,min=1,dive,required"`
+	FontSizePt  *float64 `json:"font_size_pt" binding:"omitempty,min=0,max=72"`
+	Copies      int      `json:"copies" binding:"omitempty,min=1,max=50"`
+	IsDefault   *bool    `json:"is_default" binding:"omitempty"`
+	IsActive    *bool    `json:"is_active" binding:"omitempty"`
+}
+
+// LabelTemplateResponse represents label template details
+type LabelTemplateResponse struct {
+	ID          uint      `json:"id"`
+	Code        string    `json:"code"`
+	Name        string    `json:"name"`
+	TargetType  string    `json:"target_type"`
+	WidthMM     float64   `json:"width_mm"`
+	HeightMM    float64   `json:"height_mm"`
+	BarcodeType string    `json:"barcode_type"`
+	Fields      []string  `json:"fields"`
+	FontSizePt  float64   `json:"font_size_pt"`
+	Copies      int       `json:"copies"`
+	IsDefault   bool      `json:"is_default"`
+	IsActive    bool      `json:"is_active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// PrintLabelRequest represents query parameters for printing labels
+type PrintLabelRequest struct {
+	Template string `form:"template" binding:"omitempty,max=50"` // Template code; defaults to the target's default template
+	Format   string `form:"format" binding:"omitempty,oneof=pdf zpl"`
+	Copies   int    `form:"copies" binding:"omitempty,min=1,max=50"`
+	DPI      int    `form:"dpi" binding:"omitempty,oneof=203 300 600"` // ZPL printer resolution
+}
@@ -2,13 +2,14 @@ package dto
 
 // MedicationResponse represents medication details
 type MedicationResponse struct {
-	ID           uint   `json:"id"`
-	Name         string `json:"name"`
-	GenericName  string `json:"generic_name"`
-	DosageForm   string `json:"dosage_form"`
-	Strength     string `json:"strength"`
-	Unit         string `json:"unit"`
-	Manufacturer string `json:"manufacturer"`
+	ID           uint    `json:"id"`
+	Name         string  `json:"name"`
+	GenericName  string  `json:"generic_name"`
+	DosageForm   string  `json:"dosage_form"`
+	Strength     string  `json:"strength"`
+	Unit         string  `json:"unit"`
+	Manufacturer string  `json:"manufacturer"`
+	UnitPrice    float64 `json:"unit_price"`
 }
 
 // MedicationListItem represents simplified medication for search results
@@ -0,0 +1,52 @@
+package dto
+
+import "time"
+
+// CreateNotificationTemplateRequest represents request to add a notification template
+type CreateNotificationTemplateRequest struct {
+	Code     string `json:"code" binding:"required,max=50"`
+	Channel  string `json:"channel" binding:"required,oneof=SMS EMAIL ZALO"`
+	Language string `json:"language" binding:"required,oneof=vi en"`
+	Subject  string `json:"subject" binding:"omitempty,max=255"` // Email only
+	Body     string `json:"body" binding:"required"`             // Go text/template, e.g. {{.PatientName}}
+}
+
+// UpdateNotificationTemplateRequest represents request to update a notification template
+type UpdateNotificationTemplateRequest struct {
+	Subject  *string `json:"subject" binding:"omitempty,max=255"`
+	Body     string  `json:"body" binding:"omitempty"`
+	IsActive *bool   `json:"is_active" binding:"omitempty"`
+}
+
+// NotificationTemplateResponse represents notification template details
+type NotificationTemplateResponse struct {
+	ID        uint      `json:"id"`
+	Code      string    `json:"code"`
+	Channel   string    `json:"channel"`
+	Language  string    `json:"language"`
+	Subject   string    `json:"subject,omitempty"`
+	Body      string    `json:"body"`
+	IsActive  bool      `json:"is_active"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NotificationResponse represents a queued or delivered notification
+type NotificationResponse struct {
+	ID            uint       `json:"id"`
+	Channel       string     `json:"channel"`
+	Recipient     string     `json:"recipient"`
+	TemplateCode  string     `json:"template_code"`
+	Language      string     `json:"language"`
+	Subject       string     `json:"subject,omitempty"`
+	Body          string     `json:"body"`
+	PatientID     *uint      `json:"patient_id,omitempty"`
+	AppointmentID *uint      `json:"appointment_id,omitempty"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	MaxAttempts   int        `json:"max_attempts"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	LastError     string     `json:"last_error,omitempty"`
+	ProviderRef   string     `json:"provider_ref,omitempty"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
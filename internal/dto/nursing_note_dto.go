@@ -8,7 +8,7 @@ import (
 
 // CreateNursingNoteRequest represents request to create a nursing note
 type CreateNursingNoteRequest struct {
-	VitalSigns    domain.VitalSigns `json:"vital_signs" binding:"required"`
+	VitalSigns    VitalSignsRequest `json:"vital_signs" binding:"required"`
 	Observations  string            `json:"observations" binding:"required"`
 	Interventions string            `json:"interventions" binding:"omitempty"`
 }
@@ -23,4 +23,6 @@ type NursingNoteResponse struct {
 	Observations  string            `json:"observations"`
 	Interventions string            `json:"interventions"`
 	CreatedAt     time.Time         `json:"created_at"`
+
+	EarlyWarningScore *EarlyWarningScoreResponse `json:"early_warning_score,omitempty"`
 }
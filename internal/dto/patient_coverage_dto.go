@@ -0,0 +1,47 @@
+package dto
+
+import "time"
+
+// CreateCoverageRequest represents request to add an insurance coverage to a patient
+type CreateCoverageRequest struct {
+	PayerID             uint    `json:"payer_id" binding:"required"`
+	PolicyNumber        string  `json:"policy_number" binding:"required,max=100"`
+	ValidFrom           string  `json:"valid_from" binding:"required"`            // Format: YYYY-MM-DD
+	ValidTo             string  `json:"valid_to" binding:"omitempty"`             // Format: YYYY-MM-DD
+	CoveragePercent     float64 `json:"coverage_percent" binding:"min=0,max=100"` // Share of the bill paid by the payer
+	PrimaryCareFacility string  `json:"primary_care_facility" binding:"omitempty,max=200"`
+	Priority            string  `json:"priority" binding:"required,oneof=PRIMARY SECONDARY"`
+	Notes               string  `json:"notes" binding:"omitempty"`
+}
+
+// UpdateCoverageRequest represents request to update a coverage
+type UpdateCoverageRequest struct {
+	PolicyNumber        string   `json:"policy_number" binding:"omitempty,max=100"`
+	ValidFrom           string   `json:"valid_from" binding:"omitempty"` // Format: YYYY-MM-DD
+	ValidTo             string   `json:"valid_to" binding:"omitempty"`   // Format: YYYY-MM-DD
+	CoveragePercent     *float64 `json:"coverage_percent" binding:"omitempty,min=0,max=100"`
+	PrimaryCareFacility string   `json:"primary_care_facility" binding:"omitempty,max=200"`
+	Priority            string   `json:"priority" binding:"omitempty,oneof=PRIMARY SECONDARY"`
+	Notes               string   `json:"notes" binding:"omitempty"`
+	IsActive            *bool    `json:"is_active" binding:"omitempty"`
+}
+
+// CoverageResponse represents coverage details
+type CoverageResponse struct {
+	ID                  uint       `json:"id"`
+	PatientID           uint       `json:"patient_id"`
+	PayerID             uint       `json:"payer_id"`
+	PayerCode           string     `json:"payer_code"`
+	PayerName           string     `json:"payer_name"`
+	PolicyNumber        string     `json:"policy_number"`
+	ValidFrom           time.Time  `json:"valid_from"`
+	ValidTo             *time.Time `json:"valid_to,omitempty"`
+	CoveragePercent     float64    `json:"coverage_percent"`
+	PrimaryCareFacility string     `json:"primary_care_facility"`
+	Priority            string     `json:"priority"`
+	IsActive            bool       `json:"is_active"`
+	IsCurrentlyValid    bool       `json:"is_currently_valid"`
+	Notes               string     `json:"notes"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
@@ -19,6 +19,8 @@ type CreatePatientRequest struct {
 	PostalCode  string `json:"postal_code" binding:"omitempty,max=20"`
 	Country     string `json:"country" binding:"omitempty,max=100"`
 
+	PreferredLanguage string `json:"preferred_language" binding:"omitempty,oneof=vi en"` // Notification language, defaults to vi
+
 	// Identification
 	NationalID string `json:"national_id" binding:"omitempty,max=20"`
 
@@ -53,6 +55,8 @@ type UpdatePatientRequest struct {
 	PostalCode  string `json:"postal_code" binding:"omitempty,max=20"`
 	Country     string `json:"country" binding:"omitempty,max=100"`
 
+	PreferredLanguage string `json:"preferred_language" binding:"omitempty,oneof=vi en"` // Notification language, defaults to vi
+
 	NationalID string `json:"national_id" binding:"omitempty,max=20"`
 
 	InsuranceNumber   string `json:"insurance_number" binding:"omitempty,max=50"`
@@ -88,6 +92,8 @@ type PatientResponse struct {
 	Country     string `json:"country"`
 	NationalID  string `json:"national_id"`
 
+	PreferredLanguage string `json:"preferred_language"`
+
 	InsuranceNumber   string `json:"insurance_number"`
 	InsuranceProvider string `json:"insurance_provider"`
 
@@ -99,22 +105,27 @@ type PatientResponse struct {
 	ChronicConditions string `json:"chronic_conditions"`
 	Notes             string `json:"notes"`
 
-	IsActive  bool      `json:"is_active"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	IsActive      bool       `json:"is_active"`
+	IsDeceased    bool       `json:"is_deceased"`
+	DeceasedAt    *time.Time `json:"deceased_at,omitempty"`
+	PreRegistered bool       `json:"pre_registered"` // Created by online booking; details not yet checked by staff
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
 }
 
 // PatientListItem represents patient in list view
 type PatientListItem struct {
-	ID          uint   `json:"id"`
-	PatientCode string `json:"patient_code"`
-	FullName    string `json:"full_name"`
-	Age         int    `json:"age"`
-	Gender      string `json:"gender"`
-	PhoneNumber string `json:"phone_number"`
-	City        string `json:"city"`
-	IsActive    bool   `json:"is_active"`
-	CreatedAt   string `json:"created_at"`
+	ID            uint   `json:"id"`
+	PatientCode   string `json:"patient_code"`
+	FullName      string `json:"full_name"`
+	Age           int    `json:"age"`
+	Gender        string `json:"gender"`
+	PhoneNumber   string `json:"phone_number"`
+	City          string `json:"city"`
+	IsActive      bool   `json:"is_active"`
+	IsDeceased    bool   `json:"is_deceased"`
+	PreRegistered bool   `json:"pre_registered"`
+	CreatedAt     string `json:"created_at"`
 }
 
 // PatientSearchRequest represents search filters
@@ -0,0 +1,29 @@
+package dto
+
+import "time"
+
+// PatientImportRowResponse represents the result of one imported row
+type PatientImportRowResponse struct {
+	LineNumber int    `json:"line_number"`
+	Status     string `json:"status"`
+	FullName   string `json:"full_name"`
+	NationalID string `json:"national_id"`
+	PatientID  *uint  `json:"patient_id,omitempty"`
+	Message    string `json:"message"`
+}
+
+// PatientImportResponse represents a patient import job and its summary
+type PatientImportResponse struct {
+	ID            uint                        `json:"id"`
+	FileName      string                      `json:"file_name"`
+	DryRun        bool                        `json:"dry_run"`
+	Status        string                      `json:"status"` // PROCESSING, COMPLETED or FAILED
+	ColumnMapping map[string]string           `json:"column_mapping"`
+	TotalRows     int                         `json:"total_rows"`
+	CreatedCount  int                         `json:"created_count"`
+	SkippedCount  int                         `json:"skipped_count"`
+	ErrorCount    int                         `json:"error_count"`
+	Rows          []*PatientImportRowResponse `json:"rows,omitempty"`
+	CreatedAt     time.Time                   `json:"created_at"`
+	CompletedAt   *time.Time                  `json:"completed_at,omitempty"`
+}
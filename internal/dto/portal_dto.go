@@ -0,0 +1,73 @@
+package dto
+
+import "time"
+
+// CreatePortalAccountRequest represents staff request to open a patient portal account
+type CreatePortalAccountRequest struct {
+	PhoneNumber string `json:"phone_number" binding:"omitempty,min=9,max=20"` // Defaults to the patient's phone number
+}
+
+// PortalAccountResponse represents portal account details shown to staff
+type PortalAccountResponse struct {
+	ID          uint       `json:"id"`
+	PatientID   uint       `json:"patient_id"`
+	PhoneNumber string     `json:"phone_number"`
+	HasPassword bool       `json:"has_password"`
+	IsActive    bool       `json:"is_active"`
+	LockedUntil *time.Time `json:"locked_until,omitempty"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// PortalOTPRequest represents request to send a one-time login code
+type PortalOTPRequest struct {
+	PhoneNumber string `json:"phone_number" binding:"required,min=9,max=20"`
+}
+
+// PortalOTPVerifyRequest represents request to log in with a one-time code
+type PortalOTPVerifyRequest struct {
+	PhoneNumber string `json:"phone_number" binding:"required,min=9,max=20"`
+	Code        string `json:"code" binding:"required,len=6,numeric"`
+}
+
+// PortalLoginRequest represents request to log in with a password
+type PortalLoginRequest struct {
+	PhoneNumber string `json:"phone_number" binding:"required,min=9,max=20"`
+	Password    string `json:"password" binding:"required"`
+}
+
+// PortalSetPasswordRequest represents request to set or change the portal password
+type PortalSetPasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"omitempty"` // Required once a password is set
+	NewPassword     string `json:"new_password" binding:"required,min=8,max=72"`
+}
+
+// PortalProfileResponse represents the signed-in patient's profile
+type PortalProfileResponse struct {
+	PatientID   uint   `json:"patient_id"`
+	PatientCode string `json:"patient_code"`
+	FullName    string `json:"full_name"`
+	DateOfBirth string `json:"date_of_birth"`
+	Gender      string `json:"gender"`
+	PhoneNumber string `json:"phone_number"`
+	Email       string `json:"email"`
+	HasPassword bool   `json:"has_password"`
+}
+
+// PortalAuthResponse represents portal login response
+type PortalAuthResponse struct {
+	Patient      *PortalProfileResponse `json:"patient"`
+	AccessToken  string                 `json:"access_token"`
+	RefreshToken string                 `json:"refresh_token"`
+	ExpiresIn    int64                  `json:"expires_in"`
+}
+
+// PortalBookAppointmentRequest represents a patient's appointment booking through the portal
+type PortalBookAppointmentRequest struct {
+	DoctorID        uint   `json:"doctor_id" binding:"required"`
+	AppointmentDate string `json:"appointment_date" binding:"required"` // YYYY-MM-DD
+	AppointmentTime string `json:"appointment_time" binding:"required"` // HH:MM
+	AppointmentType string `json:"appointment_type" binding:"required,oneof=CONSULTATION FOLLOW_UP CHECKUP"`
+	Reason          string `json:"reason" binding:"required,min=5"`
+}
@@ -0,0 +1,67 @@
+package dto
+
+import "time"
+
+// PublicDepartmentResponse represents a department shown on the public booking site
+type PublicDepartmentResponse struct {
+	ID   uint   `json:"id"`
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+// PublicDoctorResponse represents a doctor shown on the public booking site
+type PublicDoctorResponse struct {
+	ID             uint   `json:"id"`
+	FullName       string `json:"full_name"`
+	DepartmentID   *uint  `json:"department_id,omitempty"`
+	DepartmentName string `json:"department_name,omitempty"`
+}
+
+// CreateBookingHoldRequest represents request to hold a slot while the visitor verifies their phone
+type CreateBookingHoldRequest struct {
+	DoctorID        uint   `json:"doctor_id" binding:"required"`
+	AppointmentDate string `json:"appointment_date" binding:"required"` // YYYY-MM-DD
+	AppointmentTime string `json:"appointment_time" binding:"required"` // HH:MM
+	PhoneNumber     string `json:"phone_number" binding:"required,min=9,max=20"`
+}
+
+// VerifyBookingHoldRequest represents request to verify the phone of a hold with its one-time code
+type VerifyBookingHoldRequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+// ConfirmBookingRequest represents the patient details that confirm a verified hold
+type ConfirmBookingRequest struct {
+	FirstName       string `json:"first_name" binding:"required,max=50"`
+	LastName        string `json:"last_name" binding:"required,max=50"`
+	DateOfBirth     string `json:"date_of_birth" binding:"required"` // YYYY-MM-DD
+	Gender          string `json:"gender" binding:"required,oneof=MALE FEMALE OTHER"`
+	Email           string `json:"email" binding:"omitempty,email,max=100"`
+	AppointmentType string `json:"appointment_type" binding:"omitempty,oneof=CONSULTATION FOLLOW_UP CHECKUP"` // Defaults to CONSULTATION
+	Reason          string `json:"reason" binding:"required,min=5,max=500"`
+}
+
+// BookingHoldResponse represents a held slot
+type BookingHoldResponse struct {
+	HoldToken       string    `json:"hold_token,omitempty"` // Only returned when the hold is placed
+	DoctorID        uint      `json:"doctor_id"`
+	DoctorName      string    `json:"doctor_name"`
+	DepartmentName  string    `json:"department_name,omitempty"`
+	AppointmentDate string    `json:"appointment_date"`
+	AppointmentTime string    `json:"appointment_time"`
+	DurationMinutes int       `json:"duration_minutes"`
+	PhoneNumber     string    `json:"phone_number"` // Masked
+	Status          string    `json:"status"`
+	ExpiresAt       time.Time `json:"expires_at"`
+}
+
+// PublicBookingResponse represents an appointment booked through the public booking site
+type PublicBookingResponse struct {
+	AppointmentCode string `json:"appointment_code"`
+	DoctorName      string `json:"doctor_name"`
+	DepartmentName  string `json:"department_name,omitempty"`
+	AppointmentDate string `json:"appointment_date"`
+	AppointmentTime string `json:"appointment_time"`
+	DurationMinutes int    `json:"duration_minutes"`
+	Status          string `json:"status"`
+}
@@ -0,0 +1,97 @@
+package dto
+
+import "time"
+
+// CreateQueueRequest represents request to open a queue for a room or doctor
+type CreateQueueRequest struct {
+	Code         string `json:"code" binding:"required,max=20"`
+	Name         string `json:"name" binding:"required,max=200"`
+	TicketPrefix string `json:"ticket_prefix" binding:"omitempty,max=5,alphanum"` // e.g. A, shown as A012
+	DoctorID     *uint  `json:"doctor_id" binding:"omitempty"`                    // Check-ins with the doctor join the queue
+	RoomID       *uint  `json:"room_id" binding:"omitempty"`                      // Room resource patients are called to
+	DepartmentID *uint  `json:"department_id" binding:"omitempty"`
+}
+
+// UpdateQueueRequest represents request to update a queue
+type UpdateQueueRequest struct {
+	Name         string `json:"name" binding:"omitempty,max=200"`
+	TicketPrefix string `json:"ticket_prefix" binding:"omitempty,max=5,alphanum"`
+	DoctorID     *uint  `json:"doctor_id" binding:"omitempty"`
+	RoomID       *uint  `json:"room_id" binding:"omitempty"`
+	DepartmentID *uint  `json:"department_id" binding:"omitempty"`
+	IsActive     *bool  `json:"is_active" binding:"omitempty"`
+}
+
+// IssueQueueTicketRequest represents request to put a checked-in visit in a queue
+type IssueQueueTicketRequest struct {
+	VisitID uint   `json:"visit_id" binding:"required"`
+	Lane    string `json:"lane" binding:"omitempty,oneof=EMERGENCY ELDERLY CHILD NORMAL"` // Defaults from the visit type and the patient's age
+}
+
+// QueueResponse represents queue details
+type QueueResponse struct {
+	ID             uint      `json:"id"`
+	Code           string    `json:"code"`
+	Name           string    `json:"name"`
+	TicketPrefix   string    `json:"ticket_prefix,omitempty"`
+	DoctorID       *uint     `json:"doctor_id,omitempty"`
+	DoctorName     string    `json:"doctor_name,omitempty"`
+	RoomID         *uint     `json:"room_id,omitempty"`
+	RoomName       string    `json:"room_name,omitempty"`
+	DepartmentID   *uint     `json:"department_id,omitempty"`
+	DepartmentName string    `json:"department_name,omitempty"`
+	IsActive       bool      `json:"is_active"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// QueueTicketResponse represents a patient's ticket in a queue
+type QueueTicketResponse struct {
+	ID           uint       `json:"id"`
+	QueueID      uint       `json:"queue_id"`
+	QueueDate    string     `json:"queue_date"`
+	TicketNumber int        `json:"ticket_number"`
+	TicketCode   string     `json:"ticket_code"`
+	VisitID      *uint      `json:"visit_id,omitempty"`
+	PatientID    uint       `json:"patient_id"`
+	PatientName  string     `json:"patient_name,omitempty"`
+	Lane         string     `json:"lane"`
+	Status       string     `json:"status"`
+	CalledAt     *time.Time `json:"called_at,omitempty"`
+	CallCount    int        `json:"call_count"`
+	SkippedAt    *time.Time `json:"skipped_at,omitempty"`
+	ServedAt     *time.Time `json:"served_at,omitempty"`
+	IssuedAt     time.Time  `json:"issued_at"`
+	AcuityLevel  *int       `json:"acuity_level,omitempty"` // The visit's triage level
+}
+
+// QueueBoardResponse represents the state of a queue on a day for staff:
+// who has been called, who waits in calling order and who did not answer
+type QueueBoardResponse struct {
+	Queue   *QueueResponse         `json:"queue"`
+	Date    string                 `json:"date"`
+	Called  []*QueueTicketResponse `json:"called"`
+	Waiting []*QueueTicketResponse `json:"waiting"`
+	Skipped []*QueueTicketResponse `json:"skipped"`
+	Served  int                    `json:"served"`
+}
+
+// QueueDisplayTicket represents a ticket on a waiting-room display. It
+// carries no patient details.
+type QueueDisplayTicket struct {
+	TicketCode string     `json:"ticket_code"`
+	Lane       string     `json:"lane"`
+	CalledAt   *time.Time `json:"called_at,omitempty"`
+	CallCount  int        `json:"call_count,omitempty"`
+}
+
+// QueueDisplayResponse represents what waiting-room displays and kiosks show for a queue
+type QueueDisplayResponse struct {
+	QueueID      uint                  `json:"queue_id"`
+	QueueName    string                `json:"queue_name"`
+	RoomName     string                `json:"room_name,omitempty"`
+	Called       []*QueueDisplayTicket `json:"called"`
+	Next         []*QueueDisplayTicket `json:"next"`
+	WaitingCount int                   `json:"waiting_count"`
+	UpdatedAt    time.Time             `json:"updated_at"`
+}
@@ -0,0 +1,86 @@
+package dto
+
+import "time"
+
+// CreateReferralRequest represents request to refer the patient of a visit.
+// Internal referrals name a department, a doctor or both; external referrals
+// name a facility.
+type CreateReferralRequest struct {
+	ReferralType         string `json:"referral_type" binding:"required,oneof=INTERNAL EXTERNAL"`
+	TargetDepartmentID   *uint  `json:"target_department_id" binding:"omitempty"`
+	TargetDoctorID       *uint  `json:"target_doctor_id" binding:"omitempty"`
+	ExternalFacility     string `json:"external_facility" binding:"omitempty,max=200"`
+	ExternalFacilityCode string `json:"external_facility_code" binding:"omitempty,max=20"`
+	Urgency              string `json:"urgency" binding:"omitempty,oneof=ROUTINE URGENT EMERGENCY"`
+	Reason               string `json:"reason" binding:"required"`
+	ClinicalSummary      string `json:"clinical_summary" binding:"omitempty"`
+	TreatmentGiven       string `json:"treatment_given" binding:"omitempty"`
+	PatientCondition     string `json:"patient_condition" binding:"omitempty"`
+	TreatmentDirection   string `json:"treatment_direction" binding:"omitempty"`
+	Transport            string `json:"transport" binding:"omitempty,max=100"`
+	Escort               string `json:"escort" binding:"omitempty,max=200"`
+	DiagnosisIDs         []uint `json:"diagnosis_ids" binding:"omitempty,dive,gt=0"`
+	LabTestRequestIDs    []uint `json:"lab_test_request_ids" binding:"omitempty,dive,gt=0"`
+	ImagingRequestIDs    []uint `json:"imaging_request_ids" binding:"omitempty,dive,gt=0"`
+}
+
+// RespondReferralRequest represents request to accept or decline a referral
+type RespondReferralRequest struct {
+	Note string `json:"note" binding:"omitempty"` // Required when declining
+}
+
+// ScheduleReferralRequest represents request to book an accepted or newly
+// received internal referral as an appointment
+type ScheduleReferralRequest struct {
+	DoctorID        *uint  `json:"doctor_id" binding:"omitempty"`       // Defaults to the referral's target doctor
+	AppointmentDate string `json:"appointment_date" binding:"required"` // YYYY-MM-DD
+	AppointmentTime string `json:"appointment_time" binding:"required"` // HH:MM
+	DurationMinutes int    `json:"duration_minutes" binding:"omitempty,oneof=15 30 45 60"`
+}
+
+// ReferralAttachmentResponse represents a diagnosis or test sent with a referral
+type ReferralAttachmentResponse struct {
+	Type    string `json:"type"` // DIAGNOSIS, LAB_TEST or IMAGING
+	ID      uint   `json:"id"`   // ID of the diagnosis or request
+	Code    string `json:"code,omitempty"`
+	Name    string `json:"name"`
+	Status  string `json:"status,omitempty"`
+	Summary string `json:"summary,omitempty"` // Results or impression, when available
+}
+
+// ReferralResponse represents a referral
+type ReferralResponse struct {
+	ID                   uint       `json:"id"`
+	ReferralNumber       string     `json:"referral_number"`
+	VisitID              uint       `json:"visit_id"`
+	PatientID            uint       `json:"patient_id"`
+	PatientName          string     `json:"patient_name,omitempty"`
+	ReferringDoctorID    uint       `json:"referring_doctor_id"`
+	ReferringDoctorName  string     `json:"referring_doctor_name,omitempty"`
+	ReferralType         string     `json:"referral_type"`
+	TargetDepartmentID   *uint      `json:"target_department_id,omitempty"`
+	TargetDepartmentName string     `json:"target_department_name,omitempty"`
+	TargetDoctorID       *uint      `json:"target_doctor_id,omitempty"`
+	TargetDoctorName     string     `json:"target_doctor_name,omitempty"`
+	ExternalFacility     string     `json:"external_facility,omitempty"`
+	ExternalFacilityCode string     `json:"external_facility_code,omitempty"`
+	Urgency              string     `json:"urgency"`
+	Reason               string     `json:"reason"`
+	ClinicalSummary      string     `json:"clinical_summary,omitempty"`
+	TreatmentGiven       string     `json:"treatment_given,omitempty"`
+	PatientCondition     string     `json:"patient_condition,omitempty"`
+	TreatmentDirection   string     `json:"treatment_direction,omitempty"`
+	Transport            string     `json:"transport,omitempty"`
+	Escort               string     `json:"escort,omitempty"`
+	Status               string     `json:"status"`
+	SentAt               time.Time  `json:"sent_at"`
+	RespondedBy          *uint      `json:"responded_by,omitempty"`
+	RespondedByName      string     `json:"responded_by_name,omitempty"`
+	RespondedAt          *time.Time `json:"responded_at,omitempty"`
+	ResponseNote         string     `json:"response_note,omitempty"`
+	AppointmentID        *uint      `json:"appointment_id,omitempty"`
+	AppointmentCode      string     `json:"appointment_code,omitempty"`
+	CompletedAt          *time.Time `json:"completed_at,omitempty"`
+
+	Attachments []*ReferralAttachmentResponse `json:"attachments,omitempty"`
+}
@@ -0,0 +1,104 @@
+package dto
+
+import "time"
+
+// ResourceHoursRequest represents a weekly period during which a resource can be booked
+type ResourceHoursRequest struct {
+	DayOfWeek *int   `json:"day_of_week" binding:"required,min=0,max=6"` // 0 = Sunday ... 6 = Saturday
+	StartTime string `json:"start_time" binding:"required,datetime=15:04"`
+	EndTime   string `json:"end_time" binding:"required,datetime=15:04"`
+}
+
+// CreateResourceRequest represents request to add a room or piece of equipment
+type CreateResourceRequest struct {
+	Code         string                 `json:"code" binding:"required,max=20"`
+	Name         string                 `json:"name" binding:"required,max=200"`
+	Type         string                 `json:"type" binding:"required,oneof=ROOM EQUIPMENT"`
+	Category     string                 `json:"category" binding:"omitempty,max=50"` // e.g. ULTRASOUND, ENDOSCOPY_SUITE
+	Location     string                 `json:"location" binding:"omitempty,max=100"`
+	DepartmentID *uint                  `json:"department_id" binding:"omitempty"`
+	Notes        string                 `json:"notes" binding:"omitempty"`
+	Hours        []ResourceHoursRequest `json:"hours" binding:"omitempty,dive"` // Empty means bookable at any time
+}
+
+// UpdateResourceRequest represents request to update a resource
+type UpdateResourceRequest struct {
+	Name         string `json:"name" binding:"omitempty,max=200"`
+	Category     string `json:"category" binding:"omitempty,max=50"`
+	Location     string `json:"location" binding:"omitempty,max=100"`
+	DepartmentID *uint  `json:"department_id" binding:"omitempty"`
+	Notes        string `json:"notes" binding:"omitempty"`
+	IsActive     *bool  `json:"is_active" binding:"omitempty"`
+}
+
+// SetResourceHoursRequest represents request to replace a resource's weekly opening hours
+type SetResourceHoursRequest struct {
+	Hours []ResourceHoursRequest `json:"hours" binding:"omitempty,dive"` // Empty means bookable at any time
+}
+
+// CreateResourceDowntimeRequest represents request to take a resource out of service
+type CreateResourceDowntimeRequest struct {
+	StartAt string `json:"start_at" binding:"required"` // RFC 3339
+	EndAt   string `json:"end_at" binding:"required"`   // RFC 3339, exclusive
+	Reason  string `json:"reason" binding:"omitempty,max=255"`
+}
+
+// ResourceHoursResponse represents a weekly opening period of a resource
+type ResourceHoursResponse struct {
+	DayOfWeek int    `json:"day_of_week"`
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+}
+
+// ResourceResponse represents resource details
+type ResourceResponse struct {
+	ID             uint                     `json:"id"`
+	Code           string                   `json:"code"`
+	Name           string                   `json:"name"`
+	Type           string                   `json:"type"`
+	Category       string                   `json:"category,omitempty"`
+	Location       string                   `json:"location,omitempty"`
+	DepartmentID   *uint                    `json:"department_id,omitempty"`
+	DepartmentName string                   `json:"department_name,omitempty"`
+	Notes          string                   `json:"notes,omitempty"`
+	IsActive       bool                     `json:"is_active"`
+	Hours          []*ResourceHoursResponse `json:"hours"`
+	CreatedAt      time.Time                `json:"created_at"`
+	UpdatedAt      time.Time                `json:"updated_at"`
+}
+
+// ResourceDowntimeResponse represents a period during which a resource cannot be booked
+type ResourceDowntimeResponse struct {
+	ID         uint      `json:"id"`
+	ResourceID uint      `json:"resource_id"`
+	StartAt    time.Time `json:"start_at"`
+	EndAt      time.Time `json:"end_at"`
+	Reason     string    `json:"reason"`
+}
+
+// ResourceBookingResponse represents a reservation of a resource
+type ResourceBookingResponse struct {
+	ID               uint      `json:"id"`
+	ResourceID       uint      `json:"resource_id"`
+	AppointmentID    *uint     `json:"appointment_id,omitempty"`
+	ImagingRequestID *uint     `json:"imaging_request_id,omitempty"`
+	StartAt          time.Time `json:"start_at"`
+	EndAt            time.Time `json:"end_at"`
+}
+
+// ResourceScheduleResponse represents a resource's downtime and bookings over a period
+type ResourceScheduleResponse struct {
+	Resource  *ResourceResponse           `json:"resource"`
+	From      string                      `json:"from"`
+	To        string                      `json:"to"`
+	Downtimes []*ResourceDowntimeResponse `json:"downtimes"`
+	Bookings  []*ResourceBookingResponse  `json:"bookings"`
+}
+
+// BookedResource represents a resource reserved by an appointment or imaging request
+type BookedResource struct {
+	ID   uint   `json:"id"`
+	Code string `json:"code"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
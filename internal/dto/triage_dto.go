@@ -0,0 +1,101 @@
+package dto
+
+import "time"
+
+// CreateTriageRuleRequest represents request to add a rule to a triage ruleset
+type CreateTriageRuleRequest struct {
+	Scale             string  `json:"scale" binding:"required,oneof=ESI MTS"`
+	Name              string  `json:"name" binding:"required,max=200"`
+	ComplaintCategory string  `json:"complaint_category" binding:"omitempty,max=30"` // Empty matches any complaint
+	Parameter         string  `json:"parameter" binding:"required,oneof=COMPLAINT TEMPERATURE HEART_RATE RESPIRATORY_RATE OXYGEN_SATURATION SYSTOLIC_BP PAIN_SCORE CONSCIOUSNESS AGE"`
+	Operator          string  `json:"operator" binding:"omitempty,oneof=LT LTE GT GTE EQ"` // Required unless the parameter is COMPLAINT
+	Threshold         float64 `json:"threshold"`
+	AcuityLevel       int     `json:"acuity_level" binding:"required,min=1,max=5"`
+}
+
+// UpdateTriageRuleRequest represents request to update a triage rule
+type UpdateTriageRuleRequest struct {
+	Name              string   `json:"name" binding:"omitempty,max=200"`
+	ComplaintCategory *string  `json:"complaint_category" binding:"omitempty,max=30"` // Empty string matches any complaint
+	Operator          string   `json:"operator" binding:"omitempty,oneof=LT LTE GT GTE EQ"`
+	Threshold         *float64 `json:"threshold" binding:"omitempty"`
+	AcuityLevel       int      `json:"acuity_level" binding:"omitempty,min=1,max=5"`
+	IsActive          *bool    `json:"is_active" binding:"omitempty"`
+}
+
+// TriageRuleResponse represents a triage rule
+type TriageRuleResponse struct {
+	ID                uint      `json:"id"`
+	Scale             string    `json:"scale"`
+	Name              string    `json:"name"`
+	ComplaintCategory string    `json:"complaint_category,omitempty"`
+	Parameter         string    `json:"parameter"`
+	Operator          string    `json:"operator,omitempty"`
+	Threshold         float64   `json:"threshold"`
+	AcuityLevel       int       `json:"acuity_level"`
+	IsActive          bool      `json:"is_active"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// TriageVisitRequest represents request to triage or re-triage a visit.
+// Vital signs are optional; ranges are wide enough for critically ill patients.
+type TriageVisitRequest struct {
+	ComplaintCategory      string   `json:"complaint_category" binding:"required,oneof=CARDIAC_ARREST CHEST_PAIN SHORTNESS_OF_BREATH ALTERED_CONSCIOUSNESS STROKE_SYMPTOMS SEIZURE MAJOR_TRAUMA HEAD_INJURY MINOR_INJURY BLEEDING ABDOMINAL_PAIN ALLERGIC_REACTION POISONING BURN FEVER PREGNANCY MENTAL_HEALTH OTHER"`
+	ComplaintNotes         string   `json:"complaint_notes" binding:"omitempty"`
+	PainScore              *int     `json:"pain_score" binding:"omitempty,min=0,max=10"`
+	Consciousness          string   `json:"consciousness" binding:"omitempty,oneof=ALERT CONFUSED VOICE PAIN UNRESPONSIVE"`
+	Temperature            *float64 `json:"temperature" binding:"omitempty,min=25,max=45"`
+	BloodPressureSystolic  *int     `json:"blood_pressure_systolic" binding:"omitempty,min=0,max=300"`
+	BloodPressureDiastolic *int     `json:"blood_pressure_diastolic" binding:"omitempty,min=0,max=200"`
+	HeartRate              *int     `json:"heart_rate" binding:"omitempty,min=0,max=300"`
+	RespiratoryRate        *int     `json:"respiratory_rate" binding:"omitempty,min=0,max=80"`
+	OxygenSaturation       *int     `json:"oxygen_saturation" binding:"omitempty,min=0,max=100"`
+	AcuityLevel            *int     `json:"acuity_level" binding:"omitempty,min=1,max=5"` // Overrides the computed level
+	OverrideReason         string   `json:"override_reason" binding:"omitempty"`          // Required when the override differs from the computed level
+}
+
+// VisitTriageResponse represents a triage assessment of a visit
+type VisitTriageResponse struct {
+	ID                     uint      `json:"id"`
+	VisitID                uint      `json:"visit_id"`
+	PatientID              uint      `json:"patient_id"`
+	Sequence               int       `json:"sequence"`
+	TriagedBy              uint      `json:"triaged_by"`
+	TriagedByName          string    `json:"triaged_by_name,omitempty"`
+	TriagedAt              time.Time `json:"triaged_at"`
+	ComplaintCategory      string    `json:"complaint_category"`
+	ComplaintNotes         string    `json:"complaint_notes,omitempty"`
+	PainScore              *int      `json:"pain_score,omitempty"`
+	Consciousness          string    `json:"consciousness,omitempty"`
+	Temperature            *float64  `json:"temperature,omitempty"`
+	BloodPressureSystolic  *int      `json:"blood_pressure_systolic,omitempty"`
+	BloodPressureDiastolic *int      `json:"blood_pressure_diastolic,omitempty"`
+	HeartRate              *int      `json:"heart_rate,omitempty"`
+	RespiratoryRate        *int      `json:"respiratory_rate,omitempty"`
+	OxygenSaturation       *int      `json:"oxygen_saturation,omitempty"`
+	Scale                  string    `json:"scale"`
+	ComputedLevel          int       `json:"computed_level"`
+	AcuityLevel            int       `json:"acuity_level"`
+	AcuityName             string    `json:"acuity_name"`
+	Overridden             bool      `json:"overridden"`
+	OverrideReason         string    `json:"override_reason,omitempty"`
+	MatchedRules           []string  `json:"matched_rules"`
+	TargetWaitMinutes      int       `json:"target_wait_minutes"` // Longest the patient should wait to be seen
+}
+
+// TriageWorklistItem represents an emergency visit waiting to be seen
+type TriageWorklistItem struct {
+	VisitID        uint       `json:"visit_id"`
+	VisitCode      string     `json:"visit_code"`
+	PatientID      uint       `json:"patient_id"`
+	PatientName    string     `json:"patient_name"`
+	DoctorName     string     `json:"doctor_name"`
+	ChiefComplaint string     `json:"chief_complaint"`
+	ArrivedAt      time.Time  `json:"arrived_at"`
+	WaitingMinutes int        `json:"waiting_minutes"`
+	AcuityLevel    *int       `json:"acuity_level,omitempty"` // Empty until triaged
+	AcuityName     string     `json:"acuity_name,omitempty"`
+	TriagedAt      *time.Time `json:"triaged_at,omitempty"`
+	Overdue        bool       `json:"overdue"` // Waited longer than the level's target
+}
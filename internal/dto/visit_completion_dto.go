@@ -0,0 +1,30 @@
+package dto
+
+// VisitCompletionChecklist represents the rules checked before a visit is
+// completed and whether any of them blocks completing it
+type VisitCompletionChecklist struct {
+	VisitID       uint                   `json:"visit_id"`
+	VisitCode     string                 `json:"visit_code"`
+	Status        string                 `json:"status"`
+	CanComplete   bool                   `json:"can_complete"`
+	Items         []*VisitCompletionItem `json:"items"`
+	PendingOrders []*VisitPendingOrder   `json:"pending_orders"`
+}
+
+// VisitCompletionItem represents one completion rule and its outcome
+type VisitCompletionItem struct {
+	Rule     string `json:"rule"` // PRIMARY_DIAGNOSIS, VITAL_SIGNS, PENDING_ORDERS or NOTES_SIGNED
+	Passed   bool   `json:"passed"`
+	Required bool   `json:"required"` // Disabled rules are reported but do not block completion
+	Blocking bool   `json:"blocking"` // Required and not passed
+	Message  string `json:"message"`
+}
+
+// VisitPendingOrder represents a lab or imaging order of a visit that has no result yet
+type VisitPendingOrder struct {
+	OrderType   string `json:"order_type"` // LAB_TEST or IMAGING
+	ID          uint   `json:"id"`
+	RequestCode string `json:"request_code"`
+	Name        string `json:"name"`
+	Status      string `json:"status"`
+}
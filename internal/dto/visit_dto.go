@@ -2,16 +2,19 @@ package dto
 
 import "time"
 
-// VitalSignsRequest represents vital signs data
+// VitalSignsRequest represents vital signs data. Ranges reject values that
+// are not physiologically plausible rather than abnormal ones.
 type VitalSignsRequest struct {
-	Temperature            float64 `json:"temperature" binding:"omitempty,min=35,max=42"`
-	BloodPressureSystolic  int     `json:"blood_pressure_systolic" binding:"omitempty,min=70,max=200"`
-	BloodPressureDiastolic int     `json:"blood_pressure_diastolic" binding:"omitempty,min=40,max=130"`
-	HeartRate              int     `json:"heart_rate" binding:"omitempty,min=40,max=200"`
-	RespiratoryRate        int     `json:"respiratory_rate" binding:"omitempty,min=8,max=40"`
-	OxygenSaturation       int     `json:"oxygen_saturation" binding:"omitempty,min=70,max=100"`
-	Weight                 float64 `json:"weight" binding:"omitempty,min=1,max=300"`
-	Height                 float64 `json:"height" binding:"omitempty,min=30,max=250"`
+	Temperature            float64 `json:"temperature" binding:"omitempty,min=25,max=45"`
+	BloodPressureSystolic  int     `json:"blood_pressure_systolic" binding:"omitempty,min=40,max=300"`
+	BloodPressureDiastolic int     `json:"blood_pressure_diastolic" binding:"omitempty,min=20,max=200"`
+	HeartRate              int     `json:"heart_rate" binding:"omitempty,min=20,max=300"`
+	RespiratoryRate        int     `json:"respiratory_rate" binding:"omitempty,min=2,max=80"`
+	OxygenSaturation       int     `json:"oxygen_saturation" binding:"omitempty,min=50,max=100"`
+	Weight                 float64 `json:"weight" binding:"omitempty,min=0.3,max=300"`
+	Height                 float64 `json:"height" binding:"omitempty,min=20,max=250"`
+	SupplementalOxygen     *bool   `json:"supplemental_oxygen" binding:"omitempty"` // False for room air; the NEWS2 score is incomplete without it
+	Consciousness          string  `json:"consciousness" binding:"omitempty,oneof=ALERT CONFUSED VOICE PAIN UNRESPONSIVE"`
 }
 
 // CreateVisitRequest represents request to create a new visit
@@ -22,6 +25,8 @@ type CreateVisitRequest struct {
 	VisitType      string             `json:"visit_type" binding:"required,oneof=SCHEDULED WALK_IN EMERGENCY FOLLOW_UP"`
 	ChiefComplaint string             `json:"chief_complaint" binding:"required,min=5"`
 	VitalSigns     *VitalSignsRequest `json:"vital_signs" binding:"omitempty"`
+	CoverageID     *uint              `json:"coverage_id" binding:"omitempty"` // Defaults to the patient's active primary coverage
+	SelfPay        bool               `json:"self_pay" binding:"omitempty"`    // Skip insurance for this visit
 }
 
 // UpdateVisitRequest represents request to update visit details
@@ -35,6 +40,17 @@ type UpdateVisitRequest struct {
 	VitalSigns           *VitalSignsRequest `json:"vital_signs" binding:"omitempty"`
 }
 
+// PauseVisitRequest represents request to put a consultation on hold
+type PauseVisitRequest struct {
+	Reason string `json:"reason" binding:"omitempty,oneof=AWAITING_RESULTS PROCEDURE OTHER"` // Defaults to AWAITING_RESULTS
+	Note   string `json:"note" binding:"omitempty,max=500"`
+}
+
+// CompleteVisitRequest represents request to complete a visit
+type CompleteVisitRequest struct {
+	AcknowledgePendingOrders bool `json:"acknowledge_pending_orders"` // Complete while lab or imaging orders are still pending
+}
+
 // VisitResponse represents visit details
 type VisitResponse struct {
 	ID             uint   `json:"id"`
@@ -44,6 +60,7 @@ type VisitResponse struct {
 	PatientName    string `json:"patient_name"`
 	DoctorID       uint   `json:"doctor_id"`
 	DoctorName     string `json:"doctor_name"`
+	CoverageID     *uint  `json:"coverage_id,omitempty"`
 	VisitDate      string `json:"visit_date"`
 	VisitTime      string `json:"visit_time"`
 	VisitType      string `json:"visit_type"`
@@ -51,6 +68,19 @@ type VisitResponse struct {
 	ChiefComplaint string `json:"chief_complaint"`
 	Symptoms       string `json:"symptoms"`
 
+	// Triage
+	AcuityLevel *int       `json:"acuity_level,omitempty"`
+	TriagedAt   *time.Time `json:"triaged_at,omitempty"`
+
+	// Stage timestamps
+	CheckedInAt           time.Time  `json:"checked_in_at"`
+	ConsultationStartedAt *time.Time `json:"consultation_started_at,omitempty"`
+	PausedAt              *time.Time `json:"paused_at,omitempty"`
+	PauseReason           string     `json:"pause_reason,omitempty"`
+	PausedSeconds         int        `json:"paused_seconds"`
+	ConsultationEndedAt   *time.Time `json:"consultation_ended_at,omitempty"`
+	DischargedAt          *time.Time `json:"discharged_at,omitempty"`
+
 	// Vital Signs
 	Temperature            float64 `json:"temperature"`
 	BloodPressureSystolic  int     `json:"blood_pressure_systolic"`
@@ -61,6 +91,11 @@ type VisitResponse struct {
 	Weight                 float64 `json:"weight"`
 	Height                 float64 `json:"height"`
 	BMI                    float64 `json:"bmi"`
+	SupplementalOxygen     *bool   `json:"supplemental_oxygen,omitempty"`
+	Consciousness          string  `json:"consciousness,omitempty"`
+
+	// Early warning score, when vital signs are recorded
+	NEWS2 *NEWS2Response `json:"news2,omitempty"`
 
 	// Clinical Documentation
 	PhysicalExamination  string     `json:"physical_examination"`
@@ -69,10 +104,22 @@ type VisitResponse struct {
 	FollowUpInstructions string     `json:"follow_up_instructions"`
 	NextVisitDate        *time.Time `json:"next_visit_date,omitempty"`
 
+	StatusHistory []*VisitStatusChange `json:"status_history,omitempty"` // Only on GET /visits/:id
+
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// VisitStatusChange represents one entry of a visit's status history
+type VisitStatusChange struct {
+	FromStatus    string    `json:"from_status"`
+	ToStatus      string    `json:"to_status"`
+	Reason        string    `json:"reason,omitempty"`
+	ChangedAt     time.Time `json:"changed_at"`
+	ChangedBy     uint      `json:"changed_by"`
+	ChangedByName string    `json:"changed_by_name,omitempty"`
+}
+
 // VisitListItem represents simplified visit for list view
 type VisitListItem struct {
 	ID             uint   `json:"id"`
@@ -84,4 +131,28 @@ type VisitListItem struct {
 	VisitType      string `json:"visit_type"`
 	Status         string `json:"status"`
 	ChiefComplaint string `json:"chief_complaint"`
+	AcuityLevel    *int   `json:"acuity_level,omitempty"`
+}
+
+// VisitWaitStats represents the average minutes a group of visits spent in
+// each stage. Averages are empty when no visit reached the stage.
+type VisitWaitStats struct {
+	Key                    *int64   `json:"key,omitempty"` // Doctor ID, department ID or hour of check-in
+	Name                   string   `json:"name,omitempty"`
+	Visits                 int64    `json:"visits"`                   // Visits whose consultation started
+	Completed              int64    `json:"completed"`                // Visits whose consultation ended
+	AvgTriageMinutes       *float64 `json:"avg_triage_minutes"`       // Check-in to latest triage
+	AvgWaitMinutes         *float64 `json:"avg_wait_minutes"`         // Check-in to consultation start
+	AvgConsultationMinutes *float64 `json:"avg_consultation_minutes"` // Consultation start to end, less time on hold
+	AvgPausedMinutes       *float64 `json:"avg_paused_minutes"`       // Time on hold, e.g. waiting for results
+	AvgTotalMinutes        *float64 `json:"avg_total_minutes"`        // Check-in to consultation end
+}
+
+// VisitWaitAnalyticsResponse represents wait and consultation times over a period
+type VisitWaitAnalyticsResponse struct {
+	FromDate string            `json:"from_date"`
+	ToDate   string            `json:"to_date"`
+	GroupBy  string            `json:"group_by"`
+	Overall  *VisitWaitStats   `json:"overall"`
+	Groups   []*VisitWaitStats `json:"groups"`
 }
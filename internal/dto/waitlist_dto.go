@@ -0,0 +1,65 @@
+package dto
+
+import "time"
+
+// CreateWaitlistEntryRequest represents request to put a patient on the waitlist
+// of a doctor or, when no doctor is given, of a department
+type CreateWaitlistEntryRequest struct {
+	PatientID         uint   `json:"patient_id" binding:"required"`
+	DoctorID          *uint  `json:"doctor_id" binding:"omitempty"`
+	DepartmentID      *uint  `json:"department_id" binding:"omitempty"`
+	PreferredFrom     string `json:"preferred_from" binding:"required"`                      // YYYY-MM-DD
+	PreferredTo       string `json:"preferred_to" binding:"required"`                        // YYYY-MM-DD
+	PreferredTimeFrom string `json:"preferred_time_from" binding:"omitempty,datetime=15:04"` // HH:MM
+	PreferredTimeTo   string `json:"preferred_time_to" binding:"omitempty,datetime=15:04"`   // HH:MM
+	DurationMinutes   int    `json:"duration_minutes" binding:"omitempty,oneof=15 30 45 60"` // Defaults to the freed slot's length
+	Priority          string `json:"priority" binding:"omitempty,oneof=URGENT HIGH NORMAL"`  // Defaults to NORMAL
+	AppointmentType   string `json:"appointment_type" binding:"required,oneof=CONSULTATION FOLLOW_UP CHECKUP"`
+	Reason            string `json:"reason" binding:"required,min=5"`
+	Notes             string `json:"notes" binding:"omitempty"`
+}
+
+// WaitlistOfferResponse represents a slot offered to a waitlisted patient
+type WaitlistOfferResponse struct {
+	ID                  uint       `json:"id"`
+	EntryID             uint       `json:"entry_id"`
+	PatientID           uint       `json:"patient_id,omitempty"`
+	PatientName         string     `json:"patient_name,omitempty"`
+	DoctorID            uint       `json:"doctor_id"`
+	DoctorName          string     `json:"doctor_name"`
+	AppointmentDate     string     `json:"appointment_date"`
+	AppointmentTime     string     `json:"appointment_time"`
+	DurationMinutes     int        `json:"duration_minutes"`
+	SourceAppointmentID *uint      `json:"source_appointment_id,omitempty"`
+	Status              string     `json:"status"`
+	ExpiresAt           time.Time  `json:"expires_at"`
+	RespondedAt         *time.Time `json:"responded_at,omitempty"`
+	AppointmentID       *uint      `json:"appointment_id,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+}
+
+// WaitlistEntryResponse represents waitlist entry details
+type WaitlistEntryResponse struct {
+	ID                uint                     `json:"id"`
+	PatientID         uint                     `json:"patient_id"`
+	PatientName       string                   `json:"patient_name"`
+	DoctorID          *uint                    `json:"doctor_id,omitempty"`
+	DoctorName        string                   `json:"doctor_name,omitempty"`
+	DepartmentID      *uint                    `json:"department_id,omitempty"`
+	DepartmentName    string                   `json:"department_name,omitempty"`
+	PreferredFrom     string                   `json:"preferred_from"`
+	PreferredTo       string                   `json:"preferred_to"`
+	PreferredTimeFrom string                   `json:"preferred_time_from,omitempty"`
+	PreferredTimeTo   string                   `json:"preferred_time_to,omitempty"`
+	DurationMinutes   int                      `json:"duration_minutes"`
+	Priority          string                   `json:"priority"`
+	AppointmentType   string                   `json:"appointment_type"`
+	Reason            string                   `json:"reason"`
+	Notes             string                   `json:"notes"`
+	Status            string                   `json:"status"`
+	OfferCount        int                      `json:"offer_count"`
+	AppointmentID     *uint                    `json:"appointment_id,omitempty"`
+	Offers            []*WaitlistOfferResponse `json:"offers,omitempty"`
+	CreatedAt         time.Time                `json:"created_at"`
+	UpdatedAt         time.Time                `json:"updated_at"`
+}
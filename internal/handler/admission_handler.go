@@ -37,6 +37,10 @@ func (h *AdmissionHandler) CreateAdmission(c *gin.Context) {
 			response.NotFound(c, "Visit not found")
 			return
 		}
+		if errors.Is(err, service.ErrPatientDeceased) {
+			response.BadRequest(c, "Patient is deceased", nil)
+			return
+		}
 		if errors.Is(err, service.ErrBedNotFound) {
 			response.NotFound(c, "Bed not found")
 			return
@@ -198,12 +202,21 @@ func (h *AdmissionHandler) CreateNursingNote(c *gin.Context) {
 
 	userID, _ := middleware.GetUserID(c)
 
-	if err := h.admissionService.CreateNursingNote(uint(id), &req, userID); err != nil {
+	note, err := h.admissionService.CreateNursingNote(uint(id), &req, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrAdmissionNotFound) {
+			response.NotFound(c, "Admission not found")
+			return
+		}
+		if errors.Is(err, service.ErrImplausibleVitalSigns) {
+			response.BadRequest(c, err.Error(), nil)
+			return
+		}
 		response.InternalServerError(c, "Failed to create nursing note")
 		return
 	}
 
-	response.Created(c, "Nursing note created successfully", nil)
+	response.Created(c, "Nursing note created successfully", note)
 }
 
 // GetAdmissionNursingNotes handles getting admission's nursing notes
@@ -222,3 +235,24 @@ func (h *AdmissionHandler) GetAdmissionNursingNotes(c *gin.Context) {
 
 	response.Success(c, "Nursing notes retrieved successfully", notes)
 }
+
+// GetEarlyWarningTrend handles getting an admission's NEWS2 scores over time
+func (h *AdmissionHandler) GetEarlyWarningTrend(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid admission ID", nil)
+		return
+	}
+
+	trend, err := h.admissionService.GetEarlyWarningTrend(uint(id))
+	if err != nil {
+		if errors.Is(err, service.ErrAdmissionNotFound) {
+			response.NotFound(c, "Admission not found")
+			return
+		}
+		response.InternalServerError(c, "Failed to get early warning scores")
+		return
+	}
+
+	response.Success(c, "Early warning scores retrieved successfully", trend)
+}
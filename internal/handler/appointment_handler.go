@@ -3,6 +3,7 @@ package handler
 import (
 	"errors"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/minhtran/his/internal/dto"
@@ -39,14 +40,30 @@ func (h *AppointmentHandler) ScheduleAppointment(c *gin.Context) {
 			response.NotFound(c, "Patient not found")
 			return
 		}
+		if errors.Is(err, service.ErrPatientDeceased) {
+			response.BadRequest(c, "Patient is deceased", nil)
+			return
+		}
+		if errors.Is(err, service.ErrPatientBookingRestricted) {
+			response.Forbidden(c, err.Error())
+			return
+		}
 		if errors.Is(err, service.ErrTimeSlotNotAvailable) {
 			response.BadRequest(c, "Time slot not available", nil)
 			return
 		}
-		if errors.Is(err, service.ErrInvalidAppointmentTime) {
+		if errors.Is(err, service.ErrInvalidAppointmentTime) ||
+			errors.Is(err, service.ErrDoctorNotAvailable) ||
+			errors.Is(err, service.ErrClinicSessionFull) ||
+			errors.Is(err, service.ErrResourceInactive) ||
+			errors.Is(err, service.ErrResourceUnavailable) {
 			response.BadRequest(c, err.Error(), nil)
 			return
 		}
+		if errors.Is(err, service.ErrResourceNotFound) {
+			response.NotFound(c, "Resource not found")
+			return
+		}
 		if errors.Is(err, service.ErrPastAppointmentDate) {
 			response.BadRequest(c, err.Error(), nil)
 			return
@@ -193,11 +210,20 @@ func (h *AppointmentHandler) RescheduleAppointment(c *gin.Context) {
 			response.NotFound(c, "Appointment not found")
 			return
 		}
+		if errors.Is(err, service.ErrInvalidStatusTransition) {
+			response.Conflict(c, err.Error())
+			return
+		}
 		if errors.Is(err, service.ErrTimeSlotNotAvailable) {
 			response.BadRequest(c, "Time slot not available", nil)
 			return
 		}
-		if errors.Is(err, service.ErrInvalidAppointmentTime) || errors.Is(err, service.ErrPastAppointmentDate) {
+		if errors.Is(err, service.ErrInvalidAppointmentTime) ||
+			errors.Is(err, service.ErrDoctorNotAvailable) ||
+			errors.Is(err, service.ErrClinicSessionFull) ||
+			errors.Is(err, service.ErrPastAppointmentDate) ||
+			errors.Is(err, service.ErrResourceInactive) ||
+			errors.Is(err, service.ErrResourceUnavailable) {
 			response.BadRequest(c, err.Error(), nil)
 			return
 		}
@@ -230,6 +256,10 @@ func (h *AppointmentHandler) CancelAppointment(c *gin.Context) {
 			response.NotFound(c, "Appointment not found")
 			return
 		}
+		if errors.Is(err, service.ErrInvalidStatusTransition) {
+			response.Conflict(c, err.Error())
+			return
+		}
 		response.InternalServerError(c, "Failed to cancel appointment")
 		return
 	}
@@ -252,6 +282,10 @@ func (h *AppointmentHandler) ConfirmAppointment(c *gin.Context) {
 			response.NotFound(c, "Appointment not found")
 			return
 		}
+		if errors.Is(err, service.ErrInvalidStatusTransition) {
+			response.Conflict(c, err.Error())
+			return
+		}
 		response.InternalServerError(c, "Failed to confirm appointment")
 		return
 	}
@@ -274,6 +308,10 @@ func (h *AppointmentHandler) StartAppointment(c *gin.Context) {
 			response.NotFound(c, "Appointment not found")
 			return
 		}
+		if errors.Is(err, service.ErrInvalidStatusTransition) {
+			response.Conflict(c, err.Error())
+			return
+		}
 		response.InternalServerError(c, "Failed to start appointment")
 		return
 	}
@@ -296,6 +334,10 @@ func (h *AppointmentHandler) CompleteAppointment(c *gin.Context) {
 			response.NotFound(c, "Appointment not found")
 			return
 		}
+		if errors.Is(err, service.ErrInvalidStatusTransition) {
+			response.Conflict(c, err.Error())
+			return
+		}
 		response.InternalServerError(c, "Failed to complete appointment")
 		return
 	}
@@ -318,6 +360,10 @@ func (h *AppointmentHandler) MarkNoShow(c *gin.Context) {
 			response.NotFound(c, "Appointment not found")
 			return
 		}
+		if errors.Is(err, service.ErrInvalidStatusTransition) {
+			response.Conflict(c, err.Error())
+			return
+		}
 		response.InternalServerError(c, "Failed to mark as no-show")
 		return
 	}
@@ -394,17 +440,108 @@ func (h *AppointmentHandler) GetAvailableTimeSlots(c *gin.Context) {
 		return
 	}
 
-	duration, _ := strconv.Atoi(c.DefaultQuery("duration", "30"))
+	duration, ok := parseSlotDuration(c)
+	if !ok {
+		return
+	}
 
-	slots, err := h.appointmentService.GetAvailableTimeSlots(uint(doctorID), date, duration)
+	// Optional comma-separated rooms and equipment that must also be free
+	resourceIDs, ok := parseIDList(c, "resource_ids")
+	if !ok {
+		return
+	}
+
+	slots, err := h.appointmentService.GetAvailableTimeSlots(uint(doctorID), date, duration, resourceIDs)
 	if err != nil {
-		if errors.Is(err, service.ErrInvalidDateFormat) {
-			response.BadRequest(c, "Invalid date format, use YYYY-MM-DD", nil)
+		h.handleAvailabilityError(c, err, "Failed to get available time slots")
+		return
+	}
+
+	response.Success(c, "Available time slots retrieved successfully", slots)
+}
+
+// GetAvailability handles getting the slots of a department's doctors, or of
+// given doctors, over a date range for the booking screen
+func (h *AppointmentHandler) GetAvailability(c *gin.Context) {
+	var departmentID *uint
+	if value := c.Query("department_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil || id == 0 {
+			response.BadRequest(c, "Invalid department_id", nil)
 			return
 		}
-		response.InternalServerError(c, "Failed to get available time slots")
+		dept := uint(id)
+		departmentID = &dept
+	}
+
+	doctorIDs, ok := parseIDList(c, "doctor_ids")
+	if !ok {
 		return
 	}
 
-	response.Success(c, "Available time slots retrieved successfully", slots)
+	duration, ok := parseSlotDuration(c)
+	if !ok {
+		return
+	}
+
+	resourceIDs, ok := parseIDList(c, "resource_ids")
+	if !ok {
+		return
+	}
+
+	availability, err := h.appointmentService.GetAvailability(departmentID, doctorIDs, c.Query("from"), c.Query("to"), duration, resourceIDs)
+	if err != nil {
+		h.handleAvailabilityError(c, err, "Failed to get availability")
+		return
+	}
+
+	response.Success(c, "Availability retrieved successfully", availability)
+}
+
+// handleAvailabilityError maps slot availability errors to responses
+func (h *AppointmentHandler) handleAvailabilityError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidDateFormat):
+		response.BadRequest(c, "Invalid date format, use YYYY-MM-DD", nil)
+	case errors.Is(err, service.ErrInvalidDateRange),
+		errors.Is(err, service.ErrAvailabilityRangeTooLong),
+		errors.Is(err, service.ErrResourceInactive):
+		response.BadRequest(c, err.Error(), nil)
+	case errors.Is(err, service.ErrDepartmentNotFound):
+		response.NotFound(c, "Department not found")
+	case errors.Is(err, service.ErrResourceNotFound):
+		response.NotFound(c, "Resource not found")
+	default:
+		response.InternalServerError(c, fallback)
+	}
+}
+
+// parseSlotDuration reads the optional duration query in minutes; zero, the
+// default, means each session's slot length. It responds on invalid input.
+func parseSlotDuration(c *gin.Context) (int, bool) {
+	duration, err := strconv.Atoi(c.DefaultQuery("duration", "0"))
+	if err != nil || duration < 0 || duration > 480 {
+		response.BadRequest(c, "Invalid duration, use minutes between 0 and 480", nil)
+		return 0, false
+	}
+	return duration, true
+}
+
+// parseIDList reads an optional comma-separated list of IDs from a query
+// parameter. It responds on invalid input.
+func parseIDList(c *gin.Context, param string) ([]uint, bool) {
+	var ids []uint
+	value := c.Query(param)
+	if value == "" {
+		return ids, true
+	}
+	for _, part := range strings.Split(value, ",") {
+		id, err := strconv.ParseUint(strings.TrimSpace(part), 10, 32)
+		if err != nil || id == 0 {
+			response.BadRequest(c, "Invalid "+param+", use a comma-separated list of IDs", nil)
+			return nil, false
+		}
+		ids = append(ids, uint(id))
+	}
+	return ids, true
 }
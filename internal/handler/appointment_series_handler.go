@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/middleware"
+	"github.com/minhtran/his/internal/pkg/response"
+	"github.com/minhtran/his/internal/service"
+)
+
+// AppointmentSeriesHandler handles recurring appointment series HTTP requests
+type AppointmentSeriesHandler struct {
+	seriesService *service.AppointmentSeriesService
+}
+
+// NewAppointmentSeriesHandler creates a new appointment series handler
+func NewAppointmentSeriesHandler(seriesService *service.AppointmentSeriesService) *AppointmentSeriesHandler {
+	return &AppointmentSeriesHandler{seriesService: seriesService}
+}
+
+// PreviewSeries handles listing the occurrences of a series and their conflicts
+func (h *AppointmentSeriesHandler) PreviewSeries(c *gin.Context) {
+	var req dto.CreateAppointmentSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	preview, err := h.seriesService.PreviewSeries(&req)
+	if err != nil {
+		h.handleError(c, err, nil, "Failed to preview appointment series")
+		return
+	}
+
+	response.Success(c, "Appointment series preview generated successfully", preview)
+}
+
+// CreateSeries handles booking a recurring appointment series
+func (h *AppointmentSeriesHandler) CreateSeries(c *gin.Context) {
+	var req dto.CreateAppointmentSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	series, conflicts, err := h.seriesService.CreateSeries(&req, userID)
+	if err != nil {
+		h.handleError(c, err, conflicts, "Failed to create appointment series")
+		return
+	}
+
+	response.Created(c, "Appointment series created successfully", series)
+}
+
+// GetSeries handles getting a series with its occurrences
+func (h *AppointmentSeriesHandler) GetSeries(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid series ID", nil)
+		return
+	}
+
+	series, err := h.seriesService.GetSeries(uint(id))
+	if err != nil {
+		h.handleError(c, err, nil, "Failed to get appointment series")
+		return
+	}
+
+	response.Success(c, "Appointment series retrieved successfully", series)
+}
+
+// RescheduleSeries handles moving one, following or all occurrences
+func (h *AppointmentSeriesHandler) RescheduleSeries(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid series ID", nil)
+		return
+	}
+
+	var req dto.RescheduleSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	series, conflicts, err := h.seriesService.RescheduleSeries(uint(id), &req, userID)
+	if err != nil {
+		h.handleError(c, err, conflicts, "Failed to reschedule appointment series")
+		return
+	}
+
+	response.Success(c, "Appointment series rescheduled successfully", series)
+}
+
+// CancelSeries handles cancelling one, following or all occurrences
+func (h *AppointmentSeriesHandler) CancelSeries(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid series ID", nil)
+		return
+	}
+
+	var req dto.CancelSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	series, err := h.seriesService.CancelSeries(uint(id), &req, userID)
+	if err != nil {
+		h.handleError(c, err, nil, "Failed to cancel appointment series")
+		return
+	}
+
+	response.Success(c, "Appointment series cancelled successfully", series)
+}
+
+// handleError maps series service errors to HTTP responses; conflicts are
+// returned in the error details
+func (h *AppointmentSeriesHandler) handleError(c *gin.Context, err error, conflicts []*dto.SeriesOccurrence, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrSeriesConflicts):
+		response.BadRequest(c, err.Error(), map[string]interface{}{"conflicts": conflicts})
+	case errors.Is(err, service.ErrAppointmentSeriesNotFound):
+		response.NotFound(c, "Appointment series not found")
+	case errors.Is(err, service.ErrAppointmentNotFound):
+		response.NotFound(c, "Appointment not found")
+	case errors.Is(err, service.ErrPatientNotFound):
+		response.NotFound(c, "Patient not found")
+	case errors.Is(err, service.ErrDoctorNotFound):
+		response.NotFound(c, "Doctor not found")
+	case errors.Is(err, service.ErrPatientDeceased):
+		response.BadRequest(c, "Patient is deceased", nil)
+	case errors.Is(err, service.ErrPatientBookingRestricted):
+		response.Forbidden(c, err.Error())
+	case errors.Is(err, service.ErrInvalidRecurrenceRule),
+		errors.Is(err, service.ErrSeriesCancelled),
+		errors.Is(err, service.ErrSeriesAppointmentRequired),
+		errors.Is(err, service.ErrNoSeriesChange),
+		errors.Is(err, service.ErrInvalidAppointmentTime),
+		errors.Is(err, service.ErrDoctorNotAvailable),
+		errors.Is(err, service.ErrClinicSessionFull),
+		errors.Is(err, service.ErrPastAppointmentDate):
+		response.BadRequest(c, err.Error(), nil)
+	case errors.Is(err, service.ErrInvalidStatusTransition):
+		response.Conflict(c, err.Error())
+	case errors.Is(err, service.ErrTimeSlotNotAvailable):
+		response.BadRequest(c, "Time slot not available", nil)
+	case errors.Is(err, service.ErrInvalidDateFormat):
+		response.BadRequest(c, "Invalid date format, use YYYY-MM-DD", nil)
+	default:
+		response.InternalServerError(c, fallback)
+	}
+}
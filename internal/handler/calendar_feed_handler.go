@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minhtran/his/internal/middleware"
+	"github.com/minhtran/his/internal/pkg/response"
+	"github.com/minhtran/his/internal/service"
+)
+
+// CalendarFeedHandler handles iCalendar feed HTTP requests
+type CalendarFeedHandler struct {
+	calendarFeedService *service.CalendarFeedService
+}
+
+// NewCalendarFeedHandler creates a new calendar feed handler
+func NewCalendarFeedHandler(calendarFeedService *service.CalendarFeedService) *CalendarFeedHandler {
+	return &CalendarFeedHandler{calendarFeedService: calendarFeedService}
+}
+
+// GetMyFeed handles getting the current user's calendar feed
+func (h *CalendarFeedHandler) GetMyFeed(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	feed, err := h.calendarFeedService.GetFeed(userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to get calendar feed")
+		return
+	}
+
+	response.Success(c, "Calendar feed retrieved successfully", feed)
+}
+
+// RotateMyFeed handles issuing a new calendar feed URL for the current user,
+// revoking the previous one
+func (h *CalendarFeedHandler) RotateMyFeed(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	feed, err := h.calendarFeedService.RotateFeed(userID, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to create calendar feed")
+		return
+	}
+
+	response.Created(c, "Calendar feed created successfully", feed)
+}
+
+// RevokeMyFeed handles revoking the current user's calendar feed
+func (h *CalendarFeedHandler) RevokeMyFeed(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	if err := h.calendarFeedService.RevokeFeed(userID, userID); err != nil {
+		h.handleError(c, err, "Failed to revoke calendar feed")
+		return
+	}
+
+	response.Success(c, "Calendar feed revoked successfully", nil)
+}
+
+// RevokeUserFeed handles an administrator revoking a user's calendar feed
+func (h *CalendarFeedHandler) RevokeUserFeed(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", nil)
+		return
+	}
+
+	actorID, _ := middleware.GetUserID(c)
+
+	if err := h.calendarFeedService.RevokeFeed(uint(id), actorID); err != nil {
+		h.handleError(c, err, "Failed to revoke calendar feed")
+		return
+	}
+
+	response.Success(c, "Calendar feed revoked successfully", nil)
+}
+
+// GetFeed handles fetching an iCalendar feed by its secret token. The token is
+// the only credential, so unknown and revoked tokens both get a plain 404.
+func (h *CalendarFeedHandler) GetFeed(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+
+	body, err := h.calendarFeedService.RenderFeed(token)
+	if err != nil {
+		if errors.Is(err, service.ErrCalendarFeedNotFound) {
+			c.String(http.StatusNotFound, "Calendar not found")
+			return
+		}
+		c.String(http.StatusInternalServerError, "Failed to render calendar")
+		return
+	}
+
+	c.Header("Cache-Control", "private, no-store")
+	c.Header("Content-Disposition", `inline; filename="calendar.ics"`)
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", body)
+}
+
+// handleError maps calendar feed service errors to responses
+func (h *CalendarFeedHandler) handleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrCalendarFeedNotFound):
+		response.NotFound(c, "Calendar feed not found")
+	case errors.Is(err, service.ErrUserNotFound):
+		response.NotFound(c, "User not found")
+	default:
+		response.InternalServerError(c, fallback)
+	}
+}
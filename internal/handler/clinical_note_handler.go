@@ -0,0 +1,323 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/middleware"
+	"github.com/minhtran/his/internal/pkg/response"
+	"github.com/minhtran/his/internal/service"
+)
+
+// ClinicalNoteHandler handles clinical note HTTP requests
+type ClinicalNoteHandler struct {
+	noteService *service.ClinicalNoteService
+}
+
+// NewClinicalNoteHandler creates a new clinical note handler
+func NewClinicalNoteHandler(noteService *service.ClinicalNoteService) *ClinicalNoteHandler {
+	return &ClinicalNoteHandler{noteService: noteService}
+}
+
+// CreateNote handles starting a clinical note for a visit
+func (h *ClinicalNoteHandler) CreateNote(c *gin.Context) {
+	visitID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid visit ID", nil)
+		return
+	}
+
+	var req dto.CreateClinicalNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	note, err := h.noteService.CreateNote(uint(visitID), &req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to create clinical note")
+		return
+	}
+
+	response.Created(c, "Clinical note created successfully", note)
+}
+
+// GetVisitNotes handles getting a visit's clinical notes
+func (h *ClinicalNoteHandler) GetVisitNotes(c *gin.Context) {
+	visitID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid visit ID", nil)
+		return
+	}
+
+	notes, err := h.noteService.GetVisitNotes(uint(visitID))
+	if err != nil {
+		h.handleError(c, err, "Failed to get clinical notes")
+		return
+	}
+
+	response.Success(c, "Clinical notes retrieved successfully", notes)
+}
+
+// GetNote handles getting a clinical note
+func (h *ClinicalNoteHandler) GetNote(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid note ID", nil)
+		return
+	}
+
+	note, err := h.noteService.GetNote(uint(id))
+	if err != nil {
+		h.handleError(c, err, "Failed to get clinical note")
+		return
+	}
+
+	response.Success(c, "Clinical note retrieved successfully", note)
+}
+
+// UpdateNote handles editing a draft clinical note
+func (h *ClinicalNoteHandler) UpdateNote(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid note ID", nil)
+		return
+	}
+
+	var req dto.UpdateClinicalNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	note, err := h.noteService.UpdateNote(uint(id), &req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to update clinical note")
+		return
+	}
+
+	response.Success(c, "Clinical note updated successfully", note)
+}
+
+// DeleteNote handles discarding a draft clinical note
+func (h *ClinicalNoteHandler) DeleteNote(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid note ID", nil)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	if err := h.noteService.DeleteNote(uint(id), userID); err != nil {
+		h.handleError(c, err, "Failed to delete clinical note")
+		return
+	}
+
+	response.Success(c, "Clinical note deleted successfully", nil)
+}
+
+// SignNote handles signing a clinical note
+func (h *ClinicalNoteHandler) SignNote(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid note ID", nil)
+		return
+	}
+
+	var req dto.SignClinicalNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	note, err := h.noteService.SignNote(uint(id), &req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to sign clinical note")
+		return
+	}
+
+	response.Success(c, "Clinical note signed successfully", note)
+}
+
+// CosignNote handles co-signing a resident's clinical note
+func (h *ClinicalNoteHandler) CosignNote(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid note ID", nil)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	note, err := h.noteService.CosignNote(uint(id), userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to co-sign clinical note")
+		return
+	}
+
+	response.Success(c, "Clinical note co-signed successfully", note)
+}
+
+// AddAddendum handles adding an addendum to a signed clinical note
+func (h *ClinicalNoteHandler) AddAddendum(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid note ID", nil)
+		return
+	}
+
+	var req dto.CreateClinicalNoteAddendumRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	note, err := h.noteService.AddAddendum(uint(id), &req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to add addendum")
+		return
+	}
+
+	response.Created(c, "Addendum added successfully", note)
+}
+
+// GetNoteVersions handles getting the saved versions of a clinical note
+func (h *ClinicalNoteHandler) GetNoteVersions(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid note ID", nil)
+		return
+	}
+
+	versions, err := h.noteService.GetNoteVersions(uint(id))
+	if err != nil {
+		h.handleError(c, err, "Failed to get note versions")
+		return
+	}
+
+	response.Success(c, "Note versions retrieved successfully", versions)
+}
+
+// ListTemplates handles listing clinical note templates
+func (h *ClinicalNoteHandler) ListTemplates(c *gin.Context) {
+	filters := make(map[string]interface{})
+	if departmentID := c.Query("department_id"); departmentID != "" {
+		if id, err := strconv.ParseUint(departmentID, 10, 32); err == nil {
+			filters["department_id"] = uint(id)
+		}
+	}
+	if noteType := c.Query("note_type"); noteType != "" {
+		filters["note_type"] = noteType
+	}
+	if isActive := c.Query("is_active"); isActive != "" {
+		filters["is_active"] = isActive == "true"
+	}
+
+	templates, err := h.noteService.ListTemplates(filters)
+	if err != nil {
+		response.InternalServerError(c, "Failed to list clinical note templates")
+		return
+	}
+
+	response.Success(c, "Clinical note templates retrieved successfully", templates)
+}
+
+// CreateTemplate handles creating a clinical note template
+func (h *ClinicalNoteHandler) CreateTemplate(c *gin.Context) {
+	var req dto.CreateClinicalNoteTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	template, err := h.noteService.CreateTemplate(&req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to create clinical note template")
+		return
+	}
+
+	response.Created(c, "Clinical note template created successfully", template)
+}
+
+// UpdateTemplate handles updating a clinical note template
+func (h *ClinicalNoteHandler) UpdateTemplate(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid template ID", nil)
+		return
+	}
+
+	var req dto.UpdateClinicalNoteTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	template, err := h.noteService.UpdateTemplate(uint(id), &req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to update clinical note template")
+		return
+	}
+
+	response.Success(c, "Clinical note template updated successfully", template)
+}
+
+// DeleteTemplate handles deleting a clinical note template
+func (h *ClinicalNoteHandler) DeleteTemplate(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid template ID", nil)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	if err := h.noteService.DeleteTemplate(uint(id), userID); err != nil {
+		h.handleError(c, err, "Failed to delete clinical note template")
+		return
+	}
+
+	response.Success(c, "Clinical note template deleted successfully", nil)
+}
+
+// handleError maps clinical note errors to HTTP responses
+func (h *ClinicalNoteHandler) handleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrVisitNotFound):
+		response.NotFound(c, "Visit not found")
+	case errors.Is(err, service.ErrClinicalNoteNotFound):
+		response.NotFound(c, "Clinical note not found")
+	case errors.Is(err, service.ErrClinicalNoteTemplateNotFound):
+		response.NotFound(c, "Clinical note template not found")
+	case errors.Is(err, service.ErrNotNoteAuthor),
+		errors.Is(err, service.ErrNotCosigner):
+		response.Forbidden(c, err.Error())
+	case errors.Is(err, service.ErrClinicalNoteTemplateExists),
+		errors.Is(err, service.ErrClinicalNoteLocked),
+		errors.Is(err, service.ErrClinicalNoteVersionConflict),
+		errors.Is(err, service.ErrClinicalNoteNotPendingCosign):
+		response.Conflict(c, err.Error())
+	case errors.Is(err, service.ErrClinicalNoteTemplateMismatch),
+		errors.Is(err, service.ErrClinicalNoteNotSigned),
+		errors.Is(err, service.ErrCosignerRequired),
+		errors.Is(err, service.ErrInvalidCosigner):
+		response.BadRequest(c, err.Error(), nil)
+	default:
+		response.InternalServerError(c, fallback)
+	}
+}
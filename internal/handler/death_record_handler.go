@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/middleware"
+	"github.com/minhtran/his/internal/pkg/response"
+	"github.com/minhtran/his/internal/service"
+)
+
+// DeathRecordHandler handles death registration HTTP requests
+type DeathRecordHandler struct {
+	deathRecordService *service.DeathRecordService
+}
+
+// NewDeathRecordHandler creates a new death record handler
+func NewDeathRecordHandler(deathRecordService *service.DeathRecordService) *DeathRecordHandler {
+	return &DeathRecordHandler{deathRecordService: deathRecordService}
+}
+
+// RegisterDeath handles registering a patient's death
+func (h *DeathRecordHandler) RegisterDeath(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid patient ID", nil)
+		return
+	}
+
+	var req dto.RegisterDeathRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	record, err := h.deathRecordService.RegisterDeath(uint(patientID), &req, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrPatientNotFound) {
+			response.NotFound(c, "Patient not found")
+			return
+		}
+		if errors.Is(err, service.ErrAdmissionNotFound) {
+			response.NotFound(c, "Admission not found")
+			return
+		}
+		if errors.Is(err, service.ErrICD10CodeNotFound) {
+			response.NotFound(c, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrPatientAlreadyDeceased) ||
+			errors.Is(err, service.ErrInvalidDeathDateTime) ||
+			errors.Is(err, service.ErrInvalidCauseChain) {
+			response.BadRequest(c, err.Error(), nil)
+			return
+		}
+		if errors.Is(err, service.ErrInvalidDateFormat) {
+			response.BadRequest(c, "Invalid date/time format, use YYYY-MM-DD and HH:MM", nil)
+			return
+		}
+		response.InternalServerError(c, "Failed to register death")
+		return
+	}
+
+	response.Created(c, "Death registered successfully", record)
+}
+
+// GetDeathRecord handles getting a patient's death record
+func (h *DeathRecordHandler) GetDeathRecord(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid patient ID", nil)
+		return
+	}
+
+	record, err := h.deathRecordService.GetDeathRecord(uint(patientID))
+	if err != nil {
+		if errors.Is(err, service.ErrDeathRecordNotFound) {
+			response.NotFound(c, "Death record not found")
+			return
+		}
+		response.InternalServerError(c, "Failed to get death record")
+		return
+	}
+
+	response.Success(c, "Death record retrieved successfully", record)
+}
+
+// GetDeathCertificate handles rendering the printable death certificate
+func (h *DeathRecordHandler) GetDeathCertificate(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid patient ID", nil)
+		return
+	}
+
+	certificate, err := h.deathRecordService.GenerateDeathCertificate(uint(patientID))
+	if err != nil {
+		if errors.Is(err, service.ErrDeathRecordNotFound) {
+			response.NotFound(c, "Death record not found")
+			return
+		}
+		response.InternalServerError(c, "Failed to generate death certificate")
+		return
+	}
+
+	c.Data(http.StatusOK, "text/html; charset=utf-8", certificate)
+}
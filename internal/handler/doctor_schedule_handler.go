@@ -0,0 +1,352 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/middleware"
+	"github.com/minhtran/his/internal/pkg/response"
+	"github.com/minhtran/his/internal/service"
+)
+
+// DoctorScheduleHandler handles doctor schedule, leave and holiday HTTP requests
+type DoctorScheduleHandler struct {
+	scheduleService *service.DoctorScheduleService
+}
+
+// NewDoctorScheduleHandler creates a new doctor schedule handler
+func NewDoctorScheduleHandler(scheduleService *service.DoctorScheduleService) *DoctorScheduleHandler {
+	return &DoctorScheduleHandler{scheduleService: scheduleService}
+}
+
+// CreateSchedule handles adding a weekly clinic session
+func (h *DoctorScheduleHandler) CreateSchedule(c *gin.Context) {
+	doctorID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid doctor ID", nil)
+		return
+	}
+
+	var req dto.CreateDoctorScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	schedule, err := h.scheduleService.CreateSchedule(uint(doctorID), &req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to create doctor schedule")
+		return
+	}
+
+	response.Created(c, "Doctor schedule created successfully", schedule)
+}
+
+// GetSchedules handles listing a doctor's weekly clinic sessions
+func (h *DoctorScheduleHandler) GetSchedules(c *gin.Context) {
+	doctorID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid doctor ID", nil)
+		return
+	}
+
+	schedules, err := h.scheduleService.GetSchedules(uint(doctorID))
+	if err != nil {
+		response.InternalServerError(c, "Failed to get doctor schedules")
+		return
+	}
+
+	response.Success(c, "Doctor schedules retrieved successfully", schedules)
+}
+
+// UpdateSchedule handles updating a weekly clinic session
+func (h *DoctorScheduleHandler) UpdateSchedule(c *gin.Context) {
+	doctorID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid doctor ID", nil)
+		return
+	}
+
+	scheduleID, err := strconv.ParseUint(c.Param("scheduleId"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid schedule ID", nil)
+		return
+	}
+
+	var req dto.UpdateDoctorScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	schedule, err := h.scheduleService.UpdateSchedule(uint(doctorID), uint(scheduleID), &req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to update doctor schedule")
+		return
+	}
+
+	response.Success(c, "Doctor schedule updated successfully", schedule)
+}
+
+// DeleteSchedule handles removing a weekly clinic session
+func (h *DoctorScheduleHandler) DeleteSchedule(c *gin.Context) {
+	doctorID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid doctor ID", nil)
+		return
+	}
+
+	scheduleID, err := strconv.ParseUint(c.Param("scheduleId"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid schedule ID", nil)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	if err := h.scheduleService.DeleteSchedule(uint(doctorID), uint(scheduleID), userID); err != nil {
+		h.handleError(c, err, "Failed to delete doctor schedule")
+		return
+	}
+
+	response.Success(c, "Doctor schedule deleted successfully", nil)
+}
+
+// CreateOverride handles replacing a doctor's sessions on one date
+func (h *DoctorScheduleHandler) CreateOverride(c *gin.Context) {
+	doctorID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid doctor ID", nil)
+		return
+	}
+
+	var req dto.CreateScheduleOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	override, err := h.scheduleService.CreateOverride(uint(doctorID), &req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to create schedule override")
+		return
+	}
+
+	response.Created(c, "Schedule override created successfully", override)
+}
+
+// GetOverrides handles listing a doctor's schedule overrides
+func (h *DoctorScheduleHandler) GetOverrides(c *gin.Context) {
+	doctorID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid doctor ID", nil)
+		return
+	}
+
+	overrides, err := h.scheduleService.GetOverrides(uint(doctorID), c.Query("from_date"), c.Query("to_date"))
+	if err != nil {
+		h.handleError(c, err, "Failed to get schedule overrides")
+		return
+	}
+
+	response.Success(c, "Schedule overrides retrieved successfully", overrides)
+}
+
+// DeleteOverride handles removing a schedule override
+func (h *DoctorScheduleHandler) DeleteOverride(c *gin.Context) {
+	doctorID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid doctor ID", nil)
+		return
+	}
+
+	overrideID, err := strconv.ParseUint(c.Param("overrideId"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid override ID", nil)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	if err := h.scheduleService.DeleteOverride(uint(doctorID), uint(overrideID), userID); err != nil {
+		h.handleError(c, err, "Failed to delete schedule override")
+		return
+	}
+
+	response.Success(c, "Schedule override deleted successfully", nil)
+}
+
+// CreateLeave handles recording a doctor's leave
+func (h *DoctorScheduleHandler) CreateLeave(c *gin.Context) {
+	doctorID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid doctor ID", nil)
+		return
+	}
+
+	var req dto.CreateDoctorLeaveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	leave, err := h.scheduleService.CreateLeave(uint(doctorID), &req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to create doctor leave")
+		return
+	}
+
+	response.Created(c, "Doctor leave created successfully", leave)
+}
+
+// GetLeaves handles listing a doctor's leave periods
+func (h *DoctorScheduleHandler) GetLeaves(c *gin.Context) {
+	doctorID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid doctor ID", nil)
+		return
+	}
+
+	leaves, err := h.scheduleService.GetLeaves(uint(doctorID), c.Query("from_date"), c.Query("to_date"))
+	if err != nil {
+		h.handleError(c, err, "Failed to get doctor leaves")
+		return
+	}
+
+	response.Success(c, "Doctor leaves retrieved successfully", leaves)
+}
+
+// DeleteLeave handles removing a leave period
+func (h *DoctorScheduleHandler) DeleteLeave(c *gin.Context) {
+	doctorID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid doctor ID", nil)
+		return
+	}
+
+	leaveID, err := strconv.ParseUint(c.Param("leaveId"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid leave ID", nil)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	if err := h.scheduleService.DeleteLeave(uint(doctorID), uint(leaveID), userID); err != nil {
+		h.handleError(c, err, "Failed to delete doctor leave")
+		return
+	}
+
+	response.Success(c, "Doctor leave deleted successfully", nil)
+}
+
+// GetDaySchedule handles resolving a doctor's clinic sessions on a date
+func (h *DoctorScheduleHandler) GetDaySchedule(c *gin.Context) {
+	doctorID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid doctor ID", nil)
+		return
+	}
+
+	date := c.Query("date")
+	if date == "" {
+		response.BadRequest(c, "Date parameter is required (YYYY-MM-DD)", nil)
+		return
+	}
+
+	day, err := h.scheduleService.GetDaySchedule(uint(doctorID), date)
+	if err != nil {
+		h.handleError(c, err, "Failed to get clinic sessions")
+		return
+	}
+
+	response.Success(c, "Clinic sessions retrieved successfully", day)
+}
+
+// CreateHoliday handles adding a facility holiday
+func (h *DoctorScheduleHandler) CreateHoliday(c *gin.Context) {
+	var req dto.CreateHolidayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	holiday, err := h.scheduleService.CreateHoliday(&req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to create holiday")
+		return
+	}
+
+	response.Created(c, "Holiday created successfully", holiday)
+}
+
+// ListHolidays handles listing the holidays of a year
+func (h *DoctorScheduleHandler) ListHolidays(c *gin.Context) {
+	year, err := strconv.Atoi(c.DefaultQuery("year", "0"))
+	if err != nil {
+		response.BadRequest(c, "Invalid year", nil)
+		return
+	}
+
+	holidays, err := h.scheduleService.ListHolidays(year)
+	if err != nil {
+		response.InternalServerError(c, "Failed to list holidays")
+		return
+	}
+
+	response.Success(c, "Holidays retrieved successfully", holidays)
+}
+
+// DeleteHoliday handles removing a holiday
+func (h *DoctorScheduleHandler) DeleteHoliday(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid ID", nil)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	if err := h.scheduleService.DeleteHoliday(uint(id), userID); err != nil {
+		h.handleError(c, err, "Failed to delete holiday")
+		return
+	}
+
+	response.Success(c, "Holiday deleted successfully", nil)
+}
+
+// handleError maps schedule service errors to HTTP responses
+func (h *DoctorScheduleHandler) handleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrDoctorNotFound):
+		response.NotFound(c, "Doctor not found")
+	case errors.Is(err, service.ErrDoctorScheduleNotFound):
+		response.NotFound(c, "Doctor schedule not found")
+	case errors.Is(err, service.ErrScheduleOverrideNotFound):
+		response.NotFound(c, "Schedule override not found")
+	case errors.Is(err, service.ErrDoctorLeaveNotFound):
+		response.NotFound(c, "Doctor leave not found")
+	case errors.Is(err, service.ErrHolidayNotFound):
+		response.NotFound(c, "Holiday not found")
+	case errors.Is(err, service.ErrInvalidSessionTimes),
+		errors.Is(err, service.ErrScheduleConflict),
+		errors.Is(err, service.ErrInvalidDateRange):
+		response.BadRequest(c, err.Error(), nil)
+	case errors.Is(err, service.ErrInvalidDateFormat):
+		response.BadRequest(c, "Invalid date format, use YYYY-MM-DD", nil)
+	default:
+		response.InternalServerError(c, fallback)
+	}
+}
@@ -0,0 +1,253 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/middleware"
+	"github.com/minhtran/his/internal/pkg/response"
+	"github.com/minhtran/his/internal/service"
+)
+
+// FollowUpHandler handles follow-up HTTP requests
+type FollowUpHandler struct {
+	followUpService *service.FollowUpService
+}
+
+// NewFollowUpHandler creates a new follow-up handler
+func NewFollowUpHandler(followUpService *service.FollowUpService) *FollowUpHandler {
+	return &FollowUpHandler{followUpService: followUpService}
+}
+
+// ListFollowUps handles listing follow-ups with filters
+func (h *FollowUpHandler) ListFollowUps(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	filters := make(map[string]interface{})
+	if patientID := c.Query("patient_id"); patientID != "" {
+		filters["patient_id"] = patientID
+	}
+	if doctorID := c.Query("doctor_id"); doctorID != "" {
+		filters["doctor_id"] = doctorID
+	}
+	if status := c.Query("status"); status != "" {
+		filters["status"] = status
+	}
+	if dueFrom := c.Query("due_from"); dueFrom != "" {
+		filters["due_from"] = dueFrom
+	}
+	if dueTo := c.Query("due_to"); dueTo != "" {
+		filters["due_to"] = dueTo
+	}
+
+	followUps, total, err := h.followUpService.ListFollowUps(filters, page, pageSize)
+	if err != nil {
+		response.InternalServerError(c, "Failed to list follow-ups")
+		return
+	}
+
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
+		totalPages++
+	}
+
+	response.SuccessPaginated(c, "Follow-ups retrieved successfully", followUps, response.Pagination{
+		Page:       page,
+		PageSize:   pageSize,
+		TotalItems: total,
+		TotalPages: totalPages,
+	})
+}
+
+// GetReceptionWorklist handles getting the follow-ups reception has to confirm or book
+func (h *FollowUpHandler) GetReceptionWorklist(c *gin.Context) {
+	followUps, err := h.followUpService.GetReceptionWorklist()
+	if err != nil {
+		response.InternalServerError(c, "Failed to get follow-up worklist")
+		return
+	}
+
+	response.Success(c, "Follow-up worklist retrieved successfully", followUps)
+}
+
+// GetFollowUp handles getting a follow-up
+func (h *FollowUpHandler) GetFollowUp(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid follow-up ID", nil)
+		return
+	}
+
+	followUp, err := h.followUpService.GetFollowUp(uint(id))
+	if err != nil {
+		h.handleError(c, err, "Failed to get follow-up")
+		return
+	}
+
+	response.Success(c, "Follow-up retrieved successfully", followUp)
+}
+
+// GetVisitFollowUp handles getting the follow-up ordered in a visit
+func (h *FollowUpHandler) GetVisitFollowUp(c *gin.Context) {
+	visitID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid visit ID", nil)
+		return
+	}
+
+	followUp, err := h.followUpService.GetVisitFollowUp(uint(visitID))
+	if err != nil {
+		h.handleError(c, err, "Failed to get follow-up")
+		return
+	}
+
+	response.Success(c, "Follow-up retrieved successfully", followUp)
+}
+
+// ConfirmFollowUp handles reception confirming a booked follow-up with the patient
+func (h *FollowUpHandler) ConfirmFollowUp(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid follow-up ID", nil)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	followUp, err := h.followUpService.ConfirmFollowUp(uint(id), userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to confirm follow-up")
+		return
+	}
+
+	response.Success(c, "Follow-up confirmed successfully", followUp)
+}
+
+// ScheduleFollowUp handles booking an unscheduled follow-up
+func (h *FollowUpHandler) ScheduleFollowUp(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid follow-up ID", nil)
+		return
+	}
+
+	var req dto.ScheduleFollowUpRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	followUp, err := h.followUpService.ScheduleFollowUp(uint(id), &req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to book follow-up")
+		return
+	}
+
+	response.Success(c, "Follow-up booked successfully", followUp)
+}
+
+// CancelFollowUp handles cancelling a follow-up
+func (h *FollowUpHandler) CancelFollowUp(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid follow-up ID", nil)
+		return
+	}
+
+	var req dto.CancelFollowUpRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	followUp, err := h.followUpService.CancelFollowUp(uint(id), req.Reason, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to cancel follow-up")
+		return
+	}
+
+	response.Success(c, "Follow-up cancelled successfully", followUp)
+}
+
+// GetPatientAdherence handles getting how many follow-ups a patient attended and missed
+func (h *FollowUpHandler) GetPatientAdherence(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid patient ID", nil)
+		return
+	}
+
+	adherence, err := h.followUpService.GetPatientAdherence(uint(patientID), c.Query("from"), c.Query("to"))
+	if err != nil {
+		h.handleError(c, err, "Failed to get follow-up adherence")
+		return
+	}
+
+	response.Success(c, "Follow-up adherence retrieved successfully", adherence)
+}
+
+// GetDoctorAdherence handles getting how many of a doctor's follow-ups patients attended and missed
+func (h *FollowUpHandler) GetDoctorAdherence(c *gin.Context) {
+	doctorID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid doctor ID", nil)
+		return
+	}
+
+	adherence, err := h.followUpService.GetDoctorAdherence(uint(doctorID), c.Query("from"), c.Query("to"))
+	if err != nil {
+		h.handleError(c, err, "Failed to get follow-up adherence")
+		return
+	}
+
+	response.Success(c, "Follow-up adherence retrieved successfully", adherence)
+}
+
+// handleError maps follow-up and appointment booking errors to HTTP responses
+func (h *FollowUpHandler) handleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrFollowUpNotFound):
+		response.NotFound(c, "Follow-up not found")
+	case errors.Is(err, service.ErrPatientNotFound):
+		response.NotFound(c, "Patient not found")
+	case errors.Is(err, service.ErrDoctorNotFound):
+		response.NotFound(c, "Doctor not found")
+	case errors.Is(err, service.ErrAppointmentNotFound):
+		response.NotFound(c, "Appointment not found")
+	case errors.Is(err, service.ErrFollowUpNotPending),
+		errors.Is(err, service.ErrFollowUpNotUnscheduled),
+		errors.Is(err, service.ErrFollowUpClosed),
+		errors.Is(err, service.ErrInvalidStatusTransition):
+		response.Conflict(c, err.Error())
+	case errors.Is(err, service.ErrPatientDeceased):
+		response.BadRequest(c, "Patient is deceased", nil)
+	case errors.Is(err, service.ErrPatientBookingRestricted):
+		response.Forbidden(c, err.Error())
+	case errors.Is(err, service.ErrInvalidAppointmentTime),
+		errors.Is(err, service.ErrDoctorNotAvailable),
+		errors.Is(err, service.ErrClinicSessionFull),
+		errors.Is(err, service.ErrPastAppointmentDate),
+		errors.Is(err, service.ErrInvalidDateRange):
+		response.BadRequest(c, err.Error(), nil)
+	case errors.Is(err, service.ErrTimeSlotNotAvailable):
+		response.BadRequest(c, "Time slot not available", nil)
+	case errors.Is(err, service.ErrInvalidDateFormat):
+		response.BadRequest(c, "Invalid date format, use YYYY-MM-DD", nil)
+	default:
+		response.InternalServerError(c, fallback)
+	}
+}
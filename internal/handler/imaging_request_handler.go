@@ -38,6 +38,10 @@ func (h *ImagingRequestHandler) CreateImagingRequest(c *gin.Context) {
 			response.NotFound(c, "Visit not found")
 			return
 		}
+		if errors.Is(err, service.ErrPatientDeceased) {
+			response.BadRequest(c, "Patient is deceased", nil)
+			return
+		}
 		if errors.Is(err, service.ErrImagingTemplateNotFound) {
 			response.NotFound(c, "Imaging template not found")
 			return
@@ -113,11 +117,19 @@ func (h *ImagingRequestHandler) ScheduleImaging(c *gin.Context) {
 
 	userID, _ := middleware.GetUserID(c)
 
-	if err := h.requestService.ScheduleImaging(uint(id), scheduledDate, userID); err != nil {
+	if err := h.requestService.ScheduleImaging(uint(id), scheduledDate, req.DurationMinutes, req.ResourceIDs, userID); err != nil {
 		if errors.Is(err, service.ErrImagingRequestNotFound) {
 			response.NotFound(c, "Imaging request not found")
 			return
 		}
+		if errors.Is(err, service.ErrResourceNotFound) {
+			response.NotFound(c, "Resource not found")
+			return
+		}
+		if errors.Is(err, service.ErrResourceInactive) || errors.Is(err, service.ErrResourceUnavailable) {
+			response.BadRequest(c, err.Error(), nil)
+			return
+		}
 		response.InternalServerError(c, "Failed to schedule imaging")
 		return
 	}
@@ -263,3 +275,53 @@ func (h *ImagingRequestHandler) GetPatientImagingRequests(c *gin.Context) {
 
 	response.Success(c, "Patient imaging requests retrieved successfully", imagingRequests)
 }
+
+// ReleaseToPortal handles releasing results to the patient portal
+func (h *ImagingRequestHandler) ReleaseToPortal(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid imaging request ID", nil)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	request, err := h.requestService.ReleaseToPortal(uint(id), userID)
+	if err != nil {
+		if errors.Is(err, service.ErrImagingRequestNotFound) {
+			response.NotFound(c, "Imaging request not found")
+			return
+		}
+		if errors.Is(err, service.ErrImagingReportNotReady) {
+			response.BadRequest(c, err.Error(), nil)
+			return
+		}
+		response.InternalServerError(c, "Failed to release imaging report to portal")
+		return
+	}
+
+	response.Success(c, "Imaging report released to patient portal", request)
+}
+
+// WithdrawFromPortal handles hiding results from the patient portal
+func (h *ImagingRequestHandler) WithdrawFromPortal(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid imaging request ID", nil)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	request, err := h.requestService.WithdrawFromPortal(uint(id), userID)
+	if err != nil {
+		if errors.Is(err, service.ErrImagingRequestNotFound) {
+			response.NotFound(c, "Imaging request not found")
+			return
+		}
+		response.InternalServerError(c, "Failed to withdraw imaging report from portal")
+		return
+	}
+
+	response.Success(c, "Imaging report withdrawn from patient portal", request)
+}
@@ -37,6 +37,22 @@ func (h *InsuranceClaimHandler) CreateInsuranceClaim(c *gin.Context) {
 			response.NotFound(c, "Invoice not found")
 			return
 		}
+		if errors.Is(err, service.ErrNoActiveCoverage) {
+			response.BadRequest(c, "Patient has no active insurance coverage", nil)
+			return
+		}
+		if errors.Is(err, service.ErrClaimAmountRequired) {
+			response.BadRequest(c, err.Error(), nil)
+			return
+		}
+		if errors.Is(err, service.ErrCoverageNotFound) {
+			response.BadRequest(c, "Coverage not found for this patient", nil)
+			return
+		}
+		if errors.Is(err, service.ErrCoverageNotValid) {
+			response.BadRequest(c, "Insurance coverage is not valid on the service date", nil)
+			return
+		}
 		response.InternalServerError(c, "Failed to create insurance claim")
 		return
 	}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/middleware"
+	"github.com/minhtran/his/internal/pkg/response"
+	"github.com/minhtran/his/internal/service"
+)
+
+// InsurancePayerHandler handles insurance payer catalog HTTP requests
+type InsurancePayerHandler struct {
+	payerService *service.InsurancePayerService
+}
+
+// NewInsurancePayerHandler creates a new insurance payer handler
+func NewInsurancePayerHandler(payerService *service.InsurancePayerService) *InsurancePayerHandler {
+	return &InsurancePayerHandler{payerService: payerService}
+}
+
+// CreatePayer handles adding a payer to the catalog
+func (h *InsurancePayerHandler) CreatePayer(c *gin.Context) {
+	var req dto.CreateInsurancePayerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	payer, err := h.payerService.CreatePayer(&req, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrInsurancePayerCodeExists) {
+			response.BadRequest(c, "Insurance payer code already exists", nil)
+			return
+		}
+		response.InternalServerError(c, "Failed to create insurance payer")
+		return
+	}
+
+	response.Created(c, "Insurance payer created successfully", payer)
+}
+
+// GetPayer handles getting payer details
+func (h *InsurancePayerHandler) GetPayer(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid ID", nil)
+		return
+	}
+
+	payer, err := h.payerService.GetPayer(uint(id))
+	if err != nil {
+		if errors.Is(err, service.ErrInsurancePayerNotFound) {
+			response.NotFound(c, "Insurance payer not found")
+			return
+		}
+		response.InternalServerError(c, "Failed to get insurance payer")
+		return
+	}
+
+	response.Success(c, "Insurance payer retrieved successfully", payer)
+}
+
+// UpdatePayer handles updating a payer
+func (h *InsurancePayerHandler) UpdatePayer(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid ID", nil)
+		return
+	}
+
+	var req dto.UpdateInsurancePayerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	payer, err := h.payerService.UpdatePayer(uint(id), &req, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrInsurancePayerNotFound) {
+			response.NotFound(c, "Insurance payer not found")
+			return
+		}
+		response.InternalServerError(c, "Failed to update insurance payer")
+		return
+	}
+
+	response.Success(c, "Insurance payer updated successfully", payer)
+}
+
+// DeletePayer handles deleting a payer
+func (h *InsurancePayerHandler) DeletePayer(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid ID", nil)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	if err := h.payerService.DeletePayer(uint(id), userID); err != nil {
+		if errors.Is(err, service.ErrInsurancePayerNotFound) {
+			response.NotFound(c, "Insurance payer not found")
+			return
+		}
+		response.InternalServerError(c, "Failed to delete insurance payer")
+		return
+	}
+
+	response.Success(c, "Insurance payer deleted successfully", nil)
+}
+
+// ListPayers handles listing payers
+func (h *InsurancePayerHandler) ListPayers(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	payers, total, err := h.payerService.ListPayers(page, pageSize)
+	if err != nil {
+		response.InternalServerError(c, "Failed to list insurance payers")
+		return
+	}
+
+	response.SuccessPaginated(c, "Insurance payers retrieved successfully", payers, response.Pagination{
+		Page:       page,
+		PageSize:   pageSize,
+		TotalItems: total,
+		TotalPages: int((total + int64(pageSize) - 1) / int64(pageSize)),
+	})
+}
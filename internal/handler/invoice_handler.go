@@ -82,6 +82,32 @@ func (h *InvoiceHandler) GetInvoiceByCode(c *gin.Context) {
 	response.Success(c, "Invoice retrieved successfully", invoice)
 }
 
+// IssueInvoice handles issuing a draft invoice
+func (h *InvoiceHandler) IssueInvoice(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid invoice ID", nil)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	invoice, err := h.invoiceService.IssueInvoice(uint(id), userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvoiceNotFound):
+			response.NotFound(c, "Invoice not found")
+		case errors.Is(err, service.ErrInvoiceNotDraft):
+			response.Conflict(c, err.Error())
+		default:
+			response.InternalServerError(c, "Failed to issue invoice")
+		}
+		return
+	}
+
+	response.Success(c, "Invoice issued successfully", invoice)
+}
+
 // GetPatientInvoices handles getting patient's invoices
 func (h *InvoiceHandler) GetPatientInvoices(c *gin.Context) {
 	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -37,6 +37,10 @@ func (h *LabTestRequestHandler) CreateLabTestRequest(c *gin.Context) {
 			response.NotFound(c, "Visit not found")
 			return
 		}
+		if errors.Is(err, service.ErrPatientDeceased) {
+			response.BadRequest(c, "Patient is deceased", nil)
+			return
+		}
 		if errors.Is(err, service.ErrLabTestTemplateNotFound) {
 			response.NotFound(c, "Lab test template not found")
 			return
@@ -248,3 +252,53 @@ func (h *LabTestRequestHandler) GetPatientLabTests(c *gin.Context) {
 
 	response.Success(c, "Patient lab tests retrieved successfully", labTests)
 }
+
+// ReleaseToPortal handles releasing results to the patient portal
+func (h *LabTestRequestHandler) ReleaseToPortal(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid lab test request ID", nil)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	request, err := h.requestService.ReleaseToPortal(uint(id), userID)
+	if err != nil {
+		if errors.Is(err, service.ErrLabTestRequestNotFound) {
+			response.NotFound(c, "Lab test request not found")
+			return
+		}
+		if errors.Is(err, service.ErrLabTestNotCompleted) {
+			response.BadRequest(c, err.Error(), nil)
+			return
+		}
+		response.InternalServerError(c, "Failed to release lab results to portal")
+		return
+	}
+
+	response.Success(c, "Lab results released to patient portal", request)
+}
+
+// WithdrawFromPortal handles hiding results from the patient portal
+func (h *LabTestRequestHandler) WithdrawFromPortal(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid lab test request ID", nil)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	request, err := h.requestService.WithdrawFromPortal(uint(id), userID)
+	if err != nil {
+		if errors.Is(err, service.ErrLabTestRequestNotFound) {
+			response.NotFound(c, "Lab test request not found")
+			return
+		}
+		response.InternalServerError(c, "Failed to withdraw lab results from portal")
+		return
+	}
+
+	response.Success(c, "Lab results withdrawn from patient portal", request)
+}
@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/middleware"
+	"github.com/minhtran/his/internal/pkg/response"
+	"github.com/minhtran/his/internal/service"
+)
+
+// LabelHandler handles label template and label printing HTTP requests
+type LabelHandler struct {
+	labelService *service.LabelService
+}
+
+// NewLabelHandler creates a new label handler
+func NewLabelHandler(labelService *service.LabelService) *LabelHandler {
+	return &LabelHandler{labelService: labelService}
+}
+
+// CreateTemplate handles creating a label template
+func (h *LabelHandler) CreateTemplate(c *gin.Context) {
+	var req dto.CreateLabelTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	template, err := h.labelService.CreateTemplate(&req, userID)
+	if err != nil {
+		h.handleTemplateError(c, err, "Failed to create label template")
+		return
+	}
+
+	response.Created(c, "Label template created successfully", template)
+}
+
+// GetTemplate handles getting label template details
+func (h *LabelHandler) GetTemplate(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid ID", nil)
+		return
+	}
+
+	template, err := h.labelService.GetTemplate(uint(id))
+	if err != nil {
+		h.handleTemplateError(c, err, "Failed to get label template")
+		return
+	}
+
+	response.Success(c, "Label template retrieved successfully", template)
+}
+
+// UpdateTemplate handles updating a label template
+func (h *LabelHandler) UpdateTemplate(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid ID", nil)
+		return
+	}
+
+	var req dto.UpdateLabelTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	template, err := h.labelService.UpdateTemplate(uint(id), &req, userID)
+	if err != nil {
+		h.handleTemplateError(c, err, "Failed to update label template")
+		return
+	}
+
+	response.Success(c, "Label template updated successfully", template)
+}
+
+// DeleteTemplate handles deleting a label template
+func (h *LabelHandler) DeleteTemplate(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid ID", nil)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	if err := h.labelService.DeleteTemplate(uint(id), userID); err != nil {
+		h.handleTemplateError(c, err, "Failed to delete label template")
+		return
+	}
+
+	response.Success(c, "Label template deleted successfully", nil)
+}
+
+// ListTemplates handles listing label templates
+func (h *LabelHandler) ListTemplates(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	targetType := c.Query("target_type")
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	templates, total, err := h.labelService.ListTemplates(targetType, page, pageSize)
+	if err != nil {
+		response.InternalServerError(c, "Failed to list label templates")
+		return
+	}
+
+	response.SuccessPaginated(c, "Label templates retrieved successfully", templates, response.Pagination{
+		Page:       page,
+		PageSize:   pageSize,
+		TotalItems: total,
+		TotalPages: int((total + int64(pageSize) - 1) / int64(pageSize)),
+	})
+}
+
+// PrintPatientLabels handles rendering wristbands or chart labels for a patient
+func (h *LabelHandler) PrintPatientLabels(c *gin.Context) {
+	h.printLabels(c, "Invalid patient ID", h.labelService.PrintPatientLabels)
+}
+
+// PrintAdmissionLabels handles rendering inpatient wristbands for an admission
+func (h *LabelHandler) PrintAdmissionLabels(c *gin.Context) {
+	h.printLabels(c, "Invalid admission ID", h.labelService.PrintAdmissionLabels)
+}
+
+// PrintLabTestRequestLabels handles rendering specimen labels for a lab test request
+func (h *LabelHandler) PrintLabTestRequestLabels(c *gin.Context) {
+	h.printLabels(c, "Invalid lab test request ID", h.labelService.PrintLabTestRequestLabels)
+}
+
+// printLabels parses the target ID and print options, then writes the rendered labels
+func (h *LabelHandler) printLabels(c *gin.Context, invalidIDMessage string, print func(uint, *dto.PrintLabelRequest) ([]byte, string, error)) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, invalidIDMessage, nil)
+		return
+	}
+
+	var req dto.PrintLabelRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	data, fileName, err := print(uint(id), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPatientNotFound):
+			response.NotFound(c, "Patient not found")
+		case errors.Is(err, service.ErrAdmissionNotFound):
+			response.NotFound(c, "Admission not found")
+		case errors.Is(err, service.ErrLabTestRequestNotFound):
+			response.NotFound(c, "Lab test request not found")
+		case errors.Is(err, service.ErrLabelTemplateNotFound):
+			response.NotFound(c, "Label template not found")
+		case errors.Is(err, service.ErrLabelTemplateTargetMismatch):
+			response.BadRequest(c, "Label template is not for this record type", nil)
+		case errors.Is(err, service.ErrLabelRenderFailed):
+			response.BadRequest(c, "Label content does not fit the template", map[string]interface{}{"error": err.Error()})
+		default:
+			response.InternalServerError(c, "Failed to print labels")
+		}
+		return
+	}
+
+	if req.Format == "zpl" {
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", data)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", fileName))
+	c.Data(http.StatusOK, "application/pdf", data)
+}
+
+// handleTemplateError maps label template service errors to HTTP responses
+func (h *LabelHandler) handleTemplateError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrLabelTemplateNotFound):
+		response.NotFound(c, "Label template not found")
+	case errors.Is(err, service.ErrLabelTemplateCodeExists):
+		response.BadRequest(c, "Label template code already exists", nil)
+	case errors.Is(err, service.ErrInvalidLabelField):
+		response.BadRequest(c, "Invalid label field for target type", map[string]interface{}{"error": err.Error()})
+	default:
+		response.InternalServerError(c, fallback)
+	}
+}
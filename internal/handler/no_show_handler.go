@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minhtran/his/internal/pkg/response"
+	"github.com/minhtran/his/internal/service"
+)
+
+// NoShowHandler handles no-show statistics and overbooking HTTP requests
+type NoShowHandler struct {
+	noShowService *service.NoShowService
+}
+
+// NewNoShowHandler creates a new no-show handler
+func NewNoShowHandler(noShowService *service.NoShowService) *NoShowHandler {
+	return &NoShowHandler{noShowService: noShowService}
+}
+
+// GetPatientStats handles getting a patient's no-show statistics
+func (h *NoShowHandler) GetPatientStats(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid patient ID", nil)
+		return
+	}
+
+	stats, err := h.noShowService.GetPatientStats(uint(patientID))
+	if err != nil {
+		if errors.Is(err, service.ErrPatientNotFound) {
+			response.NotFound(c, "Patient not found")
+			return
+		}
+		response.InternalServerError(c, "Failed to get no-show statistics")
+		return
+	}
+
+	response.Success(c, "No-show statistics retrieved successfully", stats)
+}
+
+// GetDoctorOverbooking handles getting a doctor's no-show rate and overbooking allowance
+func (h *NoShowHandler) GetDoctorOverbooking(c *gin.Context) {
+	doctorID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid doctor ID", nil)
+		return
+	}
+
+	overbooking, err := h.noShowService.GetDoctorOverbooking(uint(doctorID))
+	if err != nil {
+		if errors.Is(err, service.ErrDoctorNotFound) {
+			response.NotFound(c, "Doctor not found")
+			return
+		}
+		response.InternalServerError(c, "Failed to get overbooking allowance")
+		return
+	}
+
+	response.Success(c, "Overbooking allowance retrieved successfully", overbooking)
+}
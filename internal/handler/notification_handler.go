@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/middleware"
+	"github.com/minhtran/his/internal/pkg/response"
+	"github.com/minhtran/his/internal/service"
+)
+
+// NotificationHandler handles patient notification and template HTTP requests
+type NotificationHandler struct {
+	notificationService *service.NotificationService
+}
+
+// NewNotificationHandler creates a new notification handler
+func NewNotificationHandler(notificationService *service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{notificationService: notificationService}
+}
+
+// ListNotifications handles listing notifications with filters
+func (h *NotificationHandler) ListNotifications(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	filters := make(map[string]interface{})
+	if status := c.Query("status"); status != "" {
+		filters["status"] = status
+	}
+	if channel := c.Query("channel"); channel != "" {
+		filters["channel"] = channel
+	}
+	if templateCode := c.Query("template_code"); templateCode != "" {
+		filters["template_code"] = templateCode
+	}
+	if patientID := c.Query("patient_id"); patientID != "" {
+		filters["patient_id"] = patientID
+	}
+	if appointmentID := c.Query("appointment_id"); appointmentID != "" {
+		filters["appointment_id"] = appointmentID
+	}
+
+	notifications, total, err := h.notificationService.ListNotifications(filters, page, pageSize)
+	if err != nil {
+		response.InternalServerError(c, "Failed to list notifications")
+		return
+	}
+
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
+		totalPages++
+	}
+
+	response.SuccessPaginated(c, "Notifications retrieved successfully", notifications, response.Pagination{
+		Page:       page,
+		PageSize:   pageSize,
+		TotalItems: total,
+		TotalPages: totalPages,
+	})
+}
+
+// GetNotification handles getting a notification
+func (h *NotificationHandler) GetNotification(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid notification ID", nil)
+		return
+	}
+
+	notification, err := h.notificationService.GetNotification(uint(id))
+	if err != nil {
+		h.handleError(c, err, "Failed to get notification")
+		return
+	}
+
+	response.Success(c, "Notification retrieved successfully", notification)
+}
+
+// RetryNotification handles queueing a failed notification again
+func (h *NotificationHandler) RetryNotification(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid notification ID", nil)
+		return
+	}
+
+	notification, err := h.notificationService.RetryNotification(uint(id))
+	if err != nil {
+		h.handleError(c, err, "Failed to retry notification")
+		return
+	}
+
+	response.Success(c, "Notification queued for retry", notification)
+}
+
+// ListTemplates handles listing notification templates
+func (h *NotificationHandler) ListTemplates(c *gin.Context) {
+	templates, err := h.notificationService.ListTemplates(c.Query("code"))
+	if err != nil {
+		response.InternalServerError(c, "Failed to list notification templates")
+		return
+	}
+
+	response.Success(c, "Notification templates retrieved successfully", templates)
+}
+
+// CreateTemplate handles adding a notification template
+func (h *NotificationHandler) CreateTemplate(c *gin.Context) {
+	var req dto.CreateNotificationTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	template, err := h.notificationService.CreateTemplate(&req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to create notification template")
+		return
+	}
+
+	response.Created(c, "Notification template created successfully", template)
+}
+
+// UpdateTemplate handles updating a notification template
+func (h *NotificationHandler) UpdateTemplate(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid notification template ID", nil)
+		return
+	}
+
+	var req dto.UpdateNotificationTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	template, err := h.notificationService.UpdateTemplate(uint(id), &req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to update notification template")
+		return
+	}
+
+	response.Success(c, "Notification template updated successfully", template)
+}
+
+// handleError maps notification service errors to HTTP responses
+func (h *NotificationHandler) handleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrNotificationNotFound):
+		response.NotFound(c, "Notification not found")
+	case errors.Is(err, service.ErrNotificationTemplateNotFound):
+		response.NotFound(c, "Notification template not found")
+	case errors.Is(err, service.ErrNotificationNotFailed),
+		errors.Is(err, service.ErrNotificationTemplateExists),
+		errors.Is(err, service.ErrInvalidNotificationTemplate):
+		response.BadRequest(c, err.Error(), nil)
+	default:
+		response.InternalServerError(c, fallback)
+	}
+}
@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/middleware"
+	"github.com/minhtran/his/internal/pkg/response"
+	"github.com/minhtran/his/internal/service"
+)
+
+// PatientCoverageHandler handles patient insurance coverage HTTP requests
+type PatientCoverageHandler struct {
+	coverageService *service.PatientCoverageService
+}
+
+// NewPatientCoverageHandler creates a new patient coverage handler
+func NewPatientCoverageHandler(coverageService *service.PatientCoverageService) *PatientCoverageHandler {
+	return &PatientCoverageHandler{coverageService: coverageService}
+}
+
+// AddCoverage handles adding an insurance coverage to a patient
+func (h *PatientCoverageHandler) AddCoverage(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid patient ID", nil)
+		return
+	}
+
+	var req dto.CreateCoverageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	coverage, err := h.coverageService.AddCoverage(uint(patientID), &req, userID)
+	if err != nil {
+		h.handleCoverageError(c, err, "Failed to add coverage")
+		return
+	}
+
+	response.Created(c, "Coverage added successfully", coverage)
+}
+
+// GetPatientCoverages handles getting all coverages for a patient
+func (h *PatientCoverageHandler) GetPatientCoverages(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid patient ID", nil)
+		return
+	}
+
+	coverages, err := h.coverageService.GetPatientCoverages(uint(patientID))
+	if err != nil {
+		response.InternalServerError(c, "Failed to get coverages")
+		return
+	}
+
+	response.Success(c, "Coverages retrieved successfully", coverages)
+}
+
+// GetActiveCoverages handles getting coverages valid today for a patient
+func (h *PatientCoverageHandler) GetActiveCoverages(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid patient ID", nil)
+		return
+	}
+
+	coverages, err := h.coverageService.GetActiveCoverages(uint(patientID))
+	if err != nil {
+		response.InternalServerError(c, "Failed to get active coverages")
+		return
+	}
+
+	response.Success(c, "Active coverages retrieved successfully", coverages)
+}
+
+// GetCoverage handles getting coverage details
+func (h *PatientCoverageHandler) GetCoverage(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("coverageId"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid coverage ID", nil)
+		return
+	}
+
+	coverage, err := h.coverageService.GetCoverageByID(uint(id))
+	if err != nil {
+		h.handleCoverageError(c, err, "Failed to get coverage")
+		return
+	}
+
+	response.Success(c, "Coverage retrieved successfully", coverage)
+}
+
+// UpdateCoverage handles updating a coverage
+func (h *PatientCoverageHandler) UpdateCoverage(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("coverageId"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid coverage ID", nil)
+		return
+	}
+
+	var req dto.UpdateCoverageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	coverage, err := h.coverageService.UpdateCoverage(uint(id), &req, userID)
+	if err != nil {
+		h.handleCoverageError(c, err, "Failed to update coverage")
+		return
+	}
+
+	response.Success(c, "Coverage updated successfully", coverage)
+}
+
+// DeleteCoverage handles deleting a coverage
+func (h *PatientCoverageHandler) DeleteCoverage(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("coverageId"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid coverage ID", nil)
+		return
+	}
+
+	if err := h.coverageService.DeleteCoverage(uint(id)); err != nil {
+		h.handleCoverageError(c, err, "Failed to delete coverage")
+		return
+	}
+
+	response.Success(c, "Coverage deleted successfully", nil)
+}
+
+func (h *PatientCoverageHandler) handleCoverageError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrPatientNotFound):
+		response.NotFound(c, "Patient not found")
+	case errors.Is(err, service.ErrCoverageNotFound):
+		response.NotFound(c, "Coverage not found")
+	case errors.Is(err, service.ErrInsurancePayerNotFound):
+		response.BadRequest(c, "Insurance payer not found", nil)
+	case errors.Is(err, service.ErrInsurancePayerInactive):
+		response.BadRequest(c, "Insurance payer is inactive", nil)
+	case errors.Is(err, service.ErrInvalidDateFormat):
+		response.BadRequest(c, "Invalid date format, use YYYY-MM-DD", nil)
+	case errors.Is(err, service.ErrInvalidCoveragePeriod):
+		response.BadRequest(c, "valid_to must not be before valid_from", nil)
+	case errors.Is(err, service.ErrCoverageOverlap):
+		response.BadRequest(c, "Patient already has an active coverage with this priority in the period", nil)
+	default:
+		response.InternalServerError(c, fallback)
+	}
+}
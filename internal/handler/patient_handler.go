@@ -72,6 +72,7 @@ func (h *PatientHandler) RegisterPatient(c *gin.Context) {
 // @Param blood_type query string false "Filter by blood type"
 // @Param city query string false "Filter by city"
 // @Param is_active query bool false "Filter by active status"
+// @Param pre_registered query bool false "Filter patients pre-registered through online booking"
 // @Success 200 {object} response.PaginatedResponse{data=[]dto.PatientListItem}
 // @Failure 401 {object} response.Response
 // @Failure 403 {object} response.Response
@@ -101,6 +102,10 @@ func (h *PatientHandler) ListPatients(c *gin.Context) {
 		active, _ := strconv.ParseBool(isActive)
 		filters["is_active"] = active
 	}
+	if preRegistered := c.Query("pre_registered"); preRegistered != "" {
+		pending, _ := strconv.ParseBool(preRegistered)
+		filters["pre_registered"] = pending
+	}
 
 	patients, total, err := h.patientService.ListPatients(page, pageSize, filters)
 	if err != nil {
@@ -266,6 +271,10 @@ func (h *PatientHandler) UpdatePatient(c *gin.Context) {
 			response.NotFound(c, "Patient not found")
 			return
 		}
+		if errors.Is(err, service.ErrPatientDeceased) {
+			response.BadRequest(c, "Patient is deceased", nil)
+			return
+		}
 		if errors.Is(err, service.ErrInvalidDateFormat) {
 			response.BadRequest(c, "Invalid date format, use YYYY-MM-DD", nil)
 			return
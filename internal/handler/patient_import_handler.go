@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minhtran/his/internal/middleware"
+	"github.com/minhtran/his/internal/pkg/response"
+	"github.com/minhtran/his/internal/service"
+)
+
+// maxImportFileSize limits uploaded patient import files (10 MB)
+const maxImportFileSize = 10 << 20
+
+// PatientImportHandler handles bulk patient import HTTP requests
+type PatientImportHandler struct {
+	importService *service.PatientImportService
+}
+
+// NewPatientImportHandler creates a new patient import handler
+func NewPatientImportHandler(importService *service.PatientImportService) *PatientImportHandler {
+	return &PatientImportHandler{importService: importService}
+}
+
+// ImportPatients handles a multipart upload of a CSV/XLSX patient file.
+// Form fields: file (required), mapping (JSON object field -> column header), dry_run (bool)
+func (h *PatientImportHandler) ImportPatients(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		response.BadRequest(c, "File is required", nil)
+		return
+	}
+	if fileHeader.Size > maxImportFileSize {
+		response.BadRequest(c, "File exceeds the 10 MB limit", nil)
+		return
+	}
+
+	var mapping map[string]string
+	if raw := c.PostForm("mapping"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &mapping); err != nil {
+			response.BadRequest(c, "Invalid column mapping, expected a JSON object of field to column header", nil)
+			return
+		}
+	}
+
+	dryRun, _ := strconv.ParseBool(c.DefaultPostForm("dry_run", "false"))
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		response.BadRequest(c, "Failed to read file", nil)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxImportFileSize))
+	if err != nil {
+		response.BadRequest(c, "Failed to read file", nil)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	job, err := h.importService.ImportPatients(fileHeader.Filename, data, mapping, dryRun, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrUnsupportedImportFormat) ||
+			errors.Is(err, service.ErrInvalidImportFile) ||
+			errors.Is(err, service.ErrImportEmptyFile) ||
+			errors.Is(err, service.ErrImportTooManyRows) ||
+			errors.Is(err, service.ErrInvalidColumnMapping) ||
+			errors.Is(err, service.ErrImportColumnsMissing) {
+			response.BadRequest(c, err.Error(), nil)
+			return
+		}
+		response.InternalServerError(c, "Failed to import patients")
+		return
+	}
+
+	if dryRun {
+		response.Accepted(c, "Dry run started, no patients will be created", job)
+		return
+	}
+	response.Accepted(c, "Patient import started", job)
+}
+
+// ListImportJobs handles listing patient import jobs
+func (h *PatientImportHandler) ListImportJobs(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	jobs, total, err := h.importService.ListImportJobs(page, pageSize)
+	if err != nil {
+		response.InternalServerError(c, "Failed to list import jobs")
+		return
+	}
+
+	response.SuccessPaginated(c, "Import jobs retrieved successfully", jobs, response.Pagination{
+		Page:       page,
+		PageSize:   pageSize,
+		TotalItems: total,
+		TotalPages: int((total + int64(pageSize) - 1) / int64(pageSize)),
+	})
+}
+
+// GetImportJob handles getting an import job with per-row results
+func (h *PatientImportHandler) GetImportJob(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("importId"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid import ID", nil)
+		return
+	}
+
+	job, err := h.importService.GetImportJob(uint(id))
+	if err != nil {
+		if errors.Is(err, service.ErrImportJobNotFound) {
+			response.NotFound(c, "Import job not found")
+			return
+		}
+		response.InternalServerError(c, "Failed to get import job")
+		return
+	}
+
+	response.Success(c, "Import job retrieved successfully", job)
+}
+
+// GetImportReport handles downloading the per-row import report as CSV
+func (h *PatientImportHandler) GetImportReport(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("importId"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid import ID", nil)
+		return
+	}
+
+	report, fileName, err := h.importService.GenerateImportReport(uint(id))
+	if err != nil {
+		if errors.Is(err, service.ErrImportJobNotFound) {
+			response.NotFound(c, "Import job not found")
+			return
+		}
+		response.InternalServerError(c, "Failed to generate import report")
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", report)
+}
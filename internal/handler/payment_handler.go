@@ -37,6 +37,10 @@ func (h *PaymentHandler) CreatePayment(c *gin.Context) {
 			response.NotFound(c, "Invoice not found")
 			return
 		}
+		if errors.Is(err, service.ErrInvoiceNotIssued) {
+			response.Conflict(c, err.Error())
+			return
+		}
 		if errors.Is(err, service.ErrPaymentExceedsBalance) {
 			response.BadRequest(c, "Payment amount exceeds invoice balance", nil)
 			return
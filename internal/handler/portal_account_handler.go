@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/middleware"
+	"github.com/minhtran/his/internal/pkg/response"
+	"github.com/minhtran/his/internal/service"
+)
+
+// PortalAccountHandler handles staff management of patient portal accounts
+type PortalAccountHandler struct {
+	accountService *service.PortalAccountService
+}
+
+// NewPortalAccountHandler creates a new portal account handler
+func NewPortalAccountHandler(accountService *service.PortalAccountService) *PortalAccountHandler {
+	return &PortalAccountHandler{accountService: accountService}
+}
+
+// CreateAccount handles opening or reactivating a patient's portal account
+func (h *PortalAccountHandler) CreateAccount(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid patient ID", nil)
+		return
+	}
+
+	var req dto.CreatePortalAccountRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+			return
+		}
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	account, err := h.accountService.CreateAccount(uint(patientID), &req, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPatientNotFound):
+			response.NotFound(c, "Patient not found")
+		case errors.Is(err, service.ErrPatientDeceased):
+			response.BadRequest(c, "Patient is deceased", nil)
+		case errors.Is(err, service.ErrPortalAccountExists),
+			errors.Is(err, service.ErrPortalPhoneInUse),
+			errors.Is(err, service.ErrPortalPhoneRequired):
+			response.BadRequest(c, err.Error(), nil)
+		default:
+			response.InternalServerError(c, "Failed to create portal account")
+		}
+		return
+	}
+
+	response.Created(c, "Portal account created successfully", account)
+}
+
+// GetAccount handles getting a patient's portal account
+func (h *PortalAccountHandler) GetAccount(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid patient ID", nil)
+		return
+	}
+
+	account, err := h.accountService.GetAccount(uint(patientID))
+	if err != nil {
+		if errors.Is(err, service.ErrPortalAccountNotFound) {
+			response.NotFound(c, "Portal account not found")
+			return
+		}
+		response.InternalServerError(c, "Failed to get portal account")
+		return
+	}
+
+	response.Success(c, "Portal account retrieved successfully", account)
+}
+
+// DeactivateAccount handles disabling a patient's portal access
+func (h *PortalAccountHandler) DeactivateAccount(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid patient ID", nil)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	if err := h.accountService.DeactivateAccount(uint(patientID), userID); err != nil {
+		if errors.Is(err, service.ErrPortalAccountNotFound) {
+			response.NotFound(c, "Portal account not found")
+			return
+		}
+		response.InternalServerError(c, "Failed to deactivate portal account")
+		return
+	}
+
+	response.Success(c, "Portal account deactivated successfully", nil)
+}
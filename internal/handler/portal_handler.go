@@ -0,0 +1,403 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/middleware"
+	"github.com/minhtran/his/internal/pkg/response"
+	"github.com/minhtran/his/internal/service"
+)
+
+// PortalHandler handles patient-facing portal HTTP requests
+type PortalHandler struct {
+	accountService *service.PortalAccountService
+	portalService  *service.PortalService
+}
+
+// NewPortalHandler creates a new portal handler
+func NewPortalHandler(accountService *service.PortalAccountService, portalService *service.PortalService) *PortalHandler {
+	return &PortalHandler{
+		accountService: accountService,
+		portalService:  portalService,
+	}
+}
+
+// RequestOTP handles sending a one-time login code
+func (h *PortalHandler) RequestOTP(c *gin.Context) {
+	var req dto.PortalOTPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	if err := h.accountService.RequestOTP(&req); err != nil {
+		if errors.Is(err, service.ErrOTPRateLimited) {
+			response.TooManyRequests(c, "Too many code requests, please try again later")
+			return
+		}
+		response.InternalServerError(c, "Failed to send one-time code")
+		return
+	}
+
+	response.Success(c, "If the phone number is registered for the portal, a one-time code has been sent", nil)
+}
+
+// VerifyOTP handles logging in with a one-time code
+func (h *PortalHandler) VerifyOTP(c *gin.Context) {
+	var req dto.PortalOTPVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	auth, err := h.accountService.VerifyOTP(&req)
+	if err != nil {
+		h.handleLoginError(c, err)
+		return
+	}
+
+	response.Success(c, "Login successful", auth)
+}
+
+// Login handles logging in with a password
+func (h *PortalHandler) Login(c *gin.Context) {
+	var req dto.PortalLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	auth, err := h.accountService.Login(&req)
+	if err != nil {
+		h.handleLoginError(c, err)
+		return
+	}
+
+	response.Success(c, "Login successful", auth)
+}
+
+// RefreshToken handles refreshing a portal access token
+func (h *PortalHandler) RefreshToken(c *gin.Context) {
+	var req dto.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	accessToken, err := h.accountService.RefreshToken(req.RefreshToken)
+	if err != nil {
+		response.Unauthorized(c, "Invalid or expired refresh token")
+		return
+	}
+
+	response.Success(c, "Token refreshed successfully", map[string]string{
+		"access_token": accessToken,
+	})
+}
+
+// GetProfile handles getting the signed-in patient's profile
+func (h *PortalHandler) GetProfile(c *gin.Context) {
+	accountID, _ := middleware.GetPortalAccountID(c)
+
+	profile, err := h.accountService.GetProfile(accountID)
+	if err != nil {
+		h.handlePortalError(c, err, "Failed to get profile")
+		return
+	}
+
+	response.Success(c, "Profile retrieved successfully", profile)
+}
+
+// SetPassword handles setting or changing the portal password
+func (h *PortalHandler) SetPassword(c *gin.Context) {
+	var req dto.PortalSetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	accountID, _ := middleware.GetPortalAccountID(c)
+
+	if err := h.accountService.SetPassword(accountID, &req); err != nil {
+		if errors.Is(err, service.ErrInvalidCredentials) {
+			response.BadRequest(c, "Current password is incorrect", nil)
+			return
+		}
+		h.handlePortalError(c, err, "Failed to set password")
+		return
+	}
+
+	response.Success(c, "Password updated successfully", nil)
+}
+
+// GetAppointments handles listing the patient's appointments
+func (h *PortalHandler) GetAppointments(c *gin.Context) {
+	accountID, _ := middleware.GetPortalAccountID(c)
+
+	filters := map[string]interface{}{
+		"status":    c.Query("status"),
+		"from_date": c.Query("from_date"),
+		"to_date":   c.Query("to_date"),
+	}
+
+	appointments, err := h.portalService.GetAppointments(accountID, filters)
+	if err != nil {
+		h.handlePortalError(c, err, "Failed to get appointments")
+		return
+	}
+
+	response.Success(c, "Appointments retrieved successfully", appointments)
+}
+
+// GetAppointment handles getting one of the patient's appointments
+func (h *PortalHandler) GetAppointment(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid appointment ID", nil)
+		return
+	}
+
+	accountID, _ := middleware.GetPortalAccountID(c)
+
+	appointment, err := h.portalService.GetAppointment(accountID, uint(id))
+	if err != nil {
+		h.handlePortalError(c, err, "Failed to get appointment")
+		return
+	}
+
+	response.Success(c, "Appointment retrieved successfully", appointment)
+}
+
+// BookAppointment handles booking an appointment through the portal
+func (h *PortalHandler) BookAppointment(c *gin.Context) {
+	var req dto.PortalBookAppointmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	accountID, _ := middleware.GetPortalAccountID(c)
+
+	appointment, err := h.portalService.BookAppointment(accountID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPortalBookingTooSoon),
+			errors.Is(err, service.ErrPortalBookingTooFar),
+			errors.Is(err, service.ErrPortalBookingLimit),
+			errors.Is(err, service.ErrInvalidAppointmentTime),
+			errors.Is(err, service.ErrDoctorNotAvailable),
+			errors.Is(err, service.ErrClinicSessionFull),
+			errors.Is(err, service.ErrPastAppointmentDate):
+			response.BadRequest(c, err.Error(), nil)
+		case errors.Is(err, service.ErrTimeSlotNotAvailable):
+			response.BadRequest(c, "Time slot not available", nil)
+		case errors.Is(err, service.ErrPatientBookingRestricted):
+			response.Forbidden(c, err.Error())
+		case errors.Is(err, service.ErrInvalidDateFormat):
+			response.BadRequest(c, "Invalid date format, use YYYY-MM-DD", nil)
+		default:
+			h.handlePortalError(c, err, "Failed to book appointment")
+		}
+		return
+	}
+
+	response.Created(c, "Appointment booked successfully", appointment)
+}
+
+// CancelAppointment handles cancelling an appointment through the portal
+func (h *PortalHandler) CancelAppointment(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid appointment ID", nil)
+		return
+	}
+
+	var req dto.CancelAppointmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	accountID, _ := middleware.GetPortalAccountID(c)
+
+	appointment, err := h.portalService.CancelAppointment(accountID, uint(id), req.Reason)
+	if err != nil {
+		if errors.Is(err, service.ErrPortalCancelTooLate) || errors.Is(err, service.ErrPortalAppointmentNotCancellable) {
+			response.BadRequest(c, err.Error(), nil)
+			return
+		}
+		h.handlePortalError(c, err, "Failed to cancel appointment")
+		return
+	}
+
+	response.Success(c, "Appointment cancelled successfully", appointment)
+}
+
+// GetLabResults handles listing released lab results
+func (h *PortalHandler) GetLabResults(c *gin.Context) {
+	accountID, _ := middleware.GetPortalAccountID(c)
+
+	results, err := h.portalService.GetLabResults(accountID)
+	if err != nil {
+		h.handlePortalError(c, err, "Failed to get lab results")
+		return
+	}
+
+	response.Success(c, "Lab results retrieved successfully", results)
+}
+
+// GetLabResult handles getting a released lab result
+func (h *PortalHandler) GetLabResult(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid lab result ID", nil)
+		return
+	}
+
+	accountID, _ := middleware.GetPortalAccountID(c)
+
+	result, err := h.portalService.GetLabResult(accountID, uint(id))
+	if err != nil {
+		h.handlePortalError(c, err, "Failed to get lab result")
+		return
+	}
+
+	response.Success(c, "Lab result retrieved successfully", result)
+}
+
+// GetImagingReports handles listing released imaging reports
+func (h *PortalHandler) GetImagingReports(c *gin.Context) {
+	accountID, _ := middleware.GetPortalAccountID(c)
+
+	reports, err := h.portalService.GetImagingReports(accountID)
+	if err != nil {
+		h.handlePortalError(c, err, "Failed to get imaging reports")
+		return
+	}
+
+	response.Success(c, "Imaging reports retrieved successfully", reports)
+}
+
+// GetImagingReport handles getting a released imaging report
+func (h *PortalHandler) GetImagingReport(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid imaging report ID", nil)
+		return
+	}
+
+	accountID, _ := middleware.GetPortalAccountID(c)
+
+	report, err := h.portalService.GetImagingReport(accountID, uint(id))
+	if err != nil {
+		h.handlePortalError(c, err, "Failed to get imaging report")
+		return
+	}
+
+	response.Success(c, "Imaging report retrieved successfully", report)
+}
+
+// GetPrescriptions handles listing the patient's prescriptions
+func (h *PortalHandler) GetPrescriptions(c *gin.Context) {
+	accountID, _ := middleware.GetPortalAccountID(c)
+
+	prescriptions, err := h.portalService.GetPrescriptions(accountID)
+	if err != nil {
+		h.handlePortalError(c, err, "Failed to get prescriptions")
+		return
+	}
+
+	response.Success(c, "Prescriptions retrieved successfully", prescriptions)
+}
+
+// GetPrescription handles getting one of the patient's prescriptions
+func (h *PortalHandler) GetPrescription(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid prescription ID", nil)
+		return
+	}
+
+	accountID, _ := middleware.GetPortalAccountID(c)
+
+	prescription, err := h.portalService.GetPrescription(accountID, uint(id))
+	if err != nil {
+		h.handlePortalError(c, err, "Failed to get prescription")
+		return
+	}
+
+	response.Success(c, "Prescription retrieved successfully", prescription)
+}
+
+// GetInvoices handles listing the patient's invoices
+func (h *PortalHandler) GetInvoices(c *gin.Context) {
+	accountID, _ := middleware.GetPortalAccountID(c)
+
+	invoices, err := h.portalService.GetInvoices(accountID)
+	if err != nil {
+		h.handlePortalError(c, err, "Failed to get invoices")
+		return
+	}
+
+	response.Success(c, "Invoices retrieved successfully", invoices)
+}
+
+// GetInvoice handles getting one of the patient's invoices
+func (h *PortalHandler) GetInvoice(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid invoice ID", nil)
+		return
+	}
+
+	accountID, _ := middleware.GetPortalAccountID(c)
+
+	invoice, err := h.portalService.GetInvoice(accountID, uint(id))
+	if err != nil {
+		h.handlePortalError(c, err, "Failed to get invoice")
+		return
+	}
+
+	response.Success(c, "Invoice retrieved successfully", invoice)
+}
+
+// handleLoginError maps portal login errors to HTTP responses
+func (h *PortalHandler) handleLoginError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidOTP):
+		response.Unauthorized(c, "Invalid or expired code")
+	case errors.Is(err, service.ErrInvalidCredentials):
+		response.Unauthorized(c, "Invalid phone number or password")
+	case errors.Is(err, service.ErrPortalAccountLocked):
+		response.Forbidden(c, "Account is temporarily locked, please try again later")
+	case errors.Is(err, service.ErrPortalAccountInactive):
+		response.Forbidden(c, "Portal account is inactive")
+	default:
+		response.InternalServerError(c, "Failed to log in")
+	}
+}
+
+// handlePortalError maps portal service errors to HTTP responses
+func (h *PortalHandler) handlePortalError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrPortalAccountInactive):
+		response.Forbidden(c, "Portal account is inactive")
+	case errors.Is(err, service.ErrAppointmentNotFound):
+		response.NotFound(c, "Appointment not found")
+	case errors.Is(err, service.ErrLabTestRequestNotFound):
+		response.NotFound(c, "Lab result not found")
+	case errors.Is(err, service.ErrImagingRequestNotFound):
+		response.NotFound(c, "Imaging report not found")
+	case errors.Is(err, service.ErrPrescriptionNotFound):
+		response.NotFound(c, "Prescription not found")
+	case errors.Is(err, service.ErrInvoiceNotFound):
+		response.NotFound(c, "Invoice not found")
+	case errors.Is(err, service.ErrPatientNotFound):
+		response.NotFound(c, "Patient not found")
+	default:
+		response.InternalServerError(c, fallback)
+	}
+}
@@ -37,6 +37,10 @@ func (h *PrescriptionHandler) CreatePrescription(c *gin.Context) {
 			response.NotFound(c, "Visit not found")
 			return
 		}
+		if errors.Is(err, service.ErrPatientDeceased) {
+			response.BadRequest(c, "Patient is deceased", nil)
+			return
+		}
 		if errors.Is(err, service.ErrMedicationNotFound) {
 			response.NotFound(c, "Medication not found")
 			return
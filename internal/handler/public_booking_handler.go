@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/pkg/response"
+	"github.com/minhtran/his/internal/service"
+)
+
+// PublicBookingHandler handles the unauthenticated public booking API
+type PublicBookingHandler struct {
+	bookingService *service.PublicBookingService
+}
+
+// NewPublicBookingHandler creates a new public booking handler
+func NewPublicBookingHandler(bookingService *service.PublicBookingService) *PublicBookingHandler {
+	return &PublicBookingHandler{bookingService: bookingService}
+}
+
+// ListDepartments handles listing the departments that can be booked
+func (h *PublicBookingHandler) ListDepartments(c *gin.Context) {
+	departments, err := h.bookingService.ListDepartments()
+	if err != nil {
+		response.InternalServerError(c, "Failed to list departments")
+		return
+	}
+
+	response.Success(c, "Departments retrieved successfully", departments)
+}
+
+// ListDoctors handles listing the doctors that can be booked
+func (h *PublicBookingHandler) ListDoctors(c *gin.Context) {
+	departmentID, ok := parseOptionalDepartmentID(c)
+	if !ok {
+		return
+	}
+
+	doctors, err := h.bookingService.ListDoctors(departmentID)
+	if err != nil {
+		response.InternalServerError(c, "Failed to list doctors")
+		return
+	}
+
+	response.Success(c, "Doctors retrieved successfully", doctors)
+}
+
+// GetAvailability handles getting the free slots of doctors over a date range
+func (h *PublicBookingHandler) GetAvailability(c *gin.Context) {
+	departmentID, ok := parseOptionalDepartmentID(c)
+	if !ok {
+		return
+	}
+
+	doctorIDs, ok := parseIDList(c, "doctor_ids")
+	if !ok {
+		return
+	}
+
+	availability, err := h.bookingService.GetAvailability(departmentID, doctorIDs, c.Query("from"), c.Query("to"))
+	if err != nil {
+		h.handleError(c, err, "Failed to get availability")
+		return
+	}
+
+	response.Success(c, "Availability retrieved successfully", availability)
+}
+
+// CreateHold handles holding a slot and sending a one-time code to the visitor's phone
+func (h *PublicBookingHandler) CreateHold(c *gin.Context) {
+	var req dto.CreateBookingHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	hold, err := h.bookingService.CreateHold(&req, c.ClientIP())
+	if err != nil {
+		h.handleError(c, err, "Failed to hold time slot")
+		return
+	}
+
+	response.Created(c, "Time slot held, a one-time code has been sent", hold)
+}
+
+// GetHold handles getting a hold
+func (h *PublicBookingHandler) GetHold(c *gin.Context) {
+	hold, err := h.bookingService.GetHold(c.Param("token"))
+	if err != nil {
+		h.handleError(c, err, "Failed to get booking hold")
+		return
+	}
+
+	response.Success(c, "Booking hold retrieved successfully", hold)
+}
+
+// ResendCode handles sending a new one-time code for a hold
+func (h *PublicBookingHandler) ResendCode(c *gin.Context) {
+	if err := h.bookingService.ResendCode(c.Param("token")); err != nil {
+		h.handleError(c, err, "Failed to send one-time code")
+		return
+	}
+
+	response.Success(c, "One-time code sent", nil)
+}
+
+// VerifyHold handles verifying a hold's phone number with its one-time code
+func (h *PublicBookingHandler) VerifyHold(c *gin.Context) {
+	var req dto.VerifyBookingHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	hold, err := h.bookingService.VerifyPhone(c.Param("token"), &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to verify phone number")
+		return
+	}
+
+	response.Success(c, "Phone number verified", hold)
+}
+
+// ConfirmBooking handles booking the slot of a verified hold
+func (h *PublicBookingHandler) ConfirmBooking(c *gin.Context) {
+	var req dto.ConfirmBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	booking, err := h.bookingService.ConfirmBooking(c.Param("token"), &req, c.ClientIP())
+	if err != nil {
+		h.handleError(c, err, "Failed to book appointment")
+		return
+	}
+
+	response.Created(c, "Appointment booked successfully", booking)
+}
+
+// ReleaseHold handles giving up a hold
+func (h *PublicBookingHandler) ReleaseHold(c *gin.Context) {
+	if err := h.bookingService.ReleaseHold(c.Param("token")); err != nil {
+		h.handleError(c, err, "Failed to release booking hold")
+		return
+	}
+
+	response.Success(c, "Booking hold released", nil)
+}
+
+// handleError maps public booking errors to HTTP responses
+func (h *PublicBookingHandler) handleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrPublicBookingRateLimited),
+		errors.Is(err, service.ErrOTPRateLimited):
+		response.TooManyRequests(c, err.Error())
+	case errors.Is(err, service.ErrInvalidOTP):
+		response.Unauthorized(c, "Invalid or expired code")
+	case errors.Is(err, service.ErrBookingHoldNotFound):
+		response.NotFound(c, "Booking hold not found or expired")
+	case errors.Is(err, service.ErrDoctorNotFound):
+		response.NotFound(c, "Doctor not found")
+	case errors.Is(err, service.ErrDepartmentNotFound):
+		response.NotFound(c, "Department not found")
+	case errors.Is(err, service.ErrPatientBookingRestricted),
+		errors.Is(err, service.ErrPatientDeceased):
+		response.Forbidden(c, "Online booking is not available, please contact the clinic")
+	case errors.Is(err, service.ErrTimeSlotNotAvailable):
+		response.BadRequest(c, "Time slot not available", nil)
+	case errors.Is(err, service.ErrInvalidDateFormat):
+		response.BadRequest(c, "Invalid date format, use YYYY-MM-DD", nil)
+	case errors.Is(err, service.ErrBookingHoldNotVerified),
+		errors.Is(err, service.ErrPublicBookingOutOfRange),
+		errors.Is(err, service.ErrPublicBookingTooSoon),
+		errors.Is(err, service.ErrPublicBookingLimit),
+		errors.Is(err, service.ErrInvalidAppointmentTime),
+		errors.Is(err, service.ErrDoctorNotAvailable),
+		errors.Is(err, service.ErrClinicSessionFull),
+		errors.Is(err, service.ErrPastAppointmentDate),
+		errors.Is(err, service.ErrInvalidDateRange),
+		errors.Is(err, service.ErrAvailabilityRangeTooLong):
+		response.BadRequest(c, err.Error(), nil)
+	default:
+		response.InternalServerError(c, fallback)
+	}
+}
+
+// parseOptionalDepartmentID reads the optional department_id query. It
+// responds on invalid input.
+func parseOptionalDepartmentID(c *gin.Context) (*uint, bool) {
+	value := c.Query("department_id")
+	if value == "" {
+		return nil, true
+	}
+	id, err := strconv.ParseUint(value, 10, 32)
+	if err != nil || id == 0 {
+		response.BadRequest(c, "Invalid department_id", nil)
+		return nil, false
+	}
+	departmentID := uint(id)
+	return &departmentID, true
+}
@@ -0,0 +1,305 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/middleware"
+	"github.com/minhtran/his/internal/pkg/response"
+	"github.com/minhtran/his/internal/service"
+)
+
+// queueStreamKeepAlive is how often an idle display stream sends a comment so
+// proxies do not close it
+const queueStreamKeepAlive = 25 * time.Second
+
+// QueueHandler handles outpatient queue HTTP requests
+type QueueHandler struct {
+	queueService *service.QueueService
+}
+
+// NewQueueHandler creates a new queue handler
+func NewQueueHandler(queueService *service.QueueService) *QueueHandler {
+	return &QueueHandler{queueService: queueService}
+}
+
+// CreateQueue handles opening a queue for a room or doctor
+func (h *QueueHandler) CreateQueue(c *gin.Context) {
+	var req dto.CreateQueueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	queue, err := h.queueService.CreateQueue(&req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to create queue")
+		return
+	}
+
+	response.Created(c, "Queue created successfully", queue)
+}
+
+// ListQueues handles listing queues with filters
+func (h *QueueHandler) ListQueues(c *gin.Context) {
+	filters := make(map[string]interface{})
+	if departmentID := c.Query("department_id"); departmentID != "" {
+		filters["department_id"] = departmentID
+	}
+	if doctorID := c.Query("doctor_id"); doctorID != "" {
+		filters["doctor_id"] = doctorID
+	}
+	if isActive := c.Query("is_active"); isActive != "" {
+		filters["is_active"] = isActive == "true"
+	}
+
+	queues, err := h.queueService.ListQueues(filters)
+	if err != nil {
+		response.InternalServerError(c, "Failed to list queues")
+		return
+	}
+
+	response.Success(c, "Queues retrieved successfully", queues)
+}
+
+// GetQueue handles getting a queue
+func (h *QueueHandler) GetQueue(c *gin.Context) {
+	id, ok := parseQueueID(c)
+	if !ok {
+		return
+	}
+
+	queue, err := h.queueService.GetQueue(id)
+	if err != nil {
+		h.handleError(c, err, "Failed to get queue")
+		return
+	}
+
+	response.Success(c, "Queue retrieved successfully", queue)
+}
+
+// UpdateQueue handles updating a queue
+func (h *QueueHandler) UpdateQueue(c *gin.Context) {
+	id, ok := parseQueueID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateQueueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	queue, err := h.queueService.UpdateQueue(id, &req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to update queue")
+		return
+	}
+
+	response.Success(c, "Queue updated successfully", queue)
+}
+
+// GetBoard handles getting the called, waiting and skipped tickets of a queue
+func (h *QueueHandler) GetBoard(c *gin.Context) {
+	id, ok := parseQueueID(c)
+	if !ok {
+		return
+	}
+
+	board, err := h.queueService.GetBoard(id, c.Query("date"))
+	if err != nil {
+		h.handleError(c, err, "Failed to get queue")
+		return
+	}
+
+	response.Success(c, "Queue retrieved successfully", board)
+}
+
+// IssueTicket handles putting a checked-in visit in a queue
+func (h *QueueHandler) IssueTicket(c *gin.Context) {
+	id, ok := parseQueueID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.IssueQueueTicketRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	ticket, err := h.queueService.IssueTicket(id, &req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to issue ticket")
+		return
+	}
+
+	response.Created(c, "Ticket issued successfully", ticket)
+}
+
+// CallNext handles calling the next patient of a queue
+func (h *QueueHandler) CallNext(c *gin.Context) {
+	id, ok := parseQueueID(c)
+	if !ok {
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	ticket, err := h.queueService.CallNext(id, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to call next patient")
+		return
+	}
+
+	response.Success(c, "Patient called", ticket)
+}
+
+// RecallTicket handles calling a called patient again
+func (h *QueueHandler) RecallTicket(c *gin.Context) {
+	h.ticketAction(c, h.queueService.RecallTicket, "Patient called again", "Failed to recall patient")
+}
+
+// SkipTicket handles setting aside a called patient who did not come
+func (h *QueueHandler) SkipTicket(c *gin.Context) {
+	h.ticketAction(c, h.queueService.SkipTicket, "Ticket skipped", "Failed to skip ticket")
+}
+
+// RequeueTicket handles putting a skipped patient back in line
+func (h *QueueHandler) RequeueTicket(c *gin.Context) {
+	h.ticketAction(c, h.queueService.RequeueTicket, "Ticket requeued", "Failed to requeue ticket")
+}
+
+// GetDisplay handles getting what waiting-room displays show for a queue
+func (h *QueueHandler) GetDisplay(c *gin.Context) {
+	id, ok := parseQueueID(c)
+	if !ok {
+		return
+	}
+
+	display, err := h.queueService.GetDisplay(id)
+	if err != nil {
+		h.handleError(c, err, "Failed to get queue display")
+		return
+	}
+
+	response.Success(c, "Queue display retrieved successfully", display)
+}
+
+// StreamDisplay handles a Server-Sent Events stream of a queue's display
+// state: a "queue" event with the current state, then one on every change
+func (h *QueueHandler) StreamDisplay(c *gin.Context) {
+	id, ok := parseQueueID(c)
+	if !ok {
+		return
+	}
+
+	display, err := h.queueService.GetDisplay(id)
+	if err != nil {
+		h.handleError(c, err, "Failed to get queue display")
+		return
+	}
+
+	events, unsubscribe := h.queueService.Subscribe(id)
+	defer unsubscribe()
+
+	// Streams outlive the server's write timeout
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.SSEvent("queue", display)
+	c.Writer.Flush()
+
+	keepAlive := time.NewTicker(queueStreamKeepAlive)
+	defer keepAlive.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			c.SSEvent("queue", event)
+		case <-keepAlive.C:
+			_, _ = io.WriteString(w, ": keep-alive\n\n")
+		}
+		return true
+	})
+}
+
+// ticketAction runs a call action on the ticket in the path
+func (h *QueueHandler) ticketAction(c *gin.Context, action func(ticketID, userID uint) (*dto.QueueTicketResponse, error), message, fallback string) {
+	ticketID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid ticket ID", nil)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	ticket, err := action(uint(ticketID), userID)
+	if err != nil {
+		h.handleError(c, err, fallback)
+		return
+	}
+
+	response.Success(c, message, ticket)
+}
+
+// handleError maps queue errors to HTTP responses
+func (h *QueueHandler) handleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrQueueNotFound):
+		response.NotFound(c, "Queue not found")
+	case errors.Is(err, service.ErrQueueTicketNotFound):
+		response.NotFound(c, "Queue ticket not found")
+	case errors.Is(err, service.ErrVisitNotFound):
+		response.NotFound(c, "Visit not found")
+	case errors.Is(err, service.ErrDoctorNotFound):
+		response.NotFound(c, "Doctor not found")
+	case errors.Is(err, service.ErrResourceNotFound):
+		response.NotFound(c, "Room not found")
+	case errors.Is(err, service.ErrDepartmentNotFound):
+		response.NotFound(c, "Department not found")
+	case errors.Is(err, service.ErrQueueCodeExists):
+		response.BadRequest(c, "Queue code already exists", nil)
+	case errors.Is(err, service.ErrVisitAlreadyQueued):
+		response.Conflict(c, err.Error())
+	case errors.Is(err, service.ErrQueueInactive),
+		errors.Is(err, service.ErrQueueEmpty),
+		errors.Is(err, service.ErrVisitNotWaiting),
+		errors.Is(err, service.ErrInvalidTicketTransition):
+		response.BadRequest(c, err.Error(), nil)
+	case errors.Is(err, service.ErrInvalidDateFormat):
+		response.BadRequest(c, "Invalid date format, use YYYY-MM-DD", nil)
+	default:
+		response.InternalServerError(c, fallback)
+	}
+}
+
+// parseQueueID reads the queue ID from the path. It responds on invalid input.
+func parseQueueID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid queue ID", nil)
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -0,0 +1,267 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minhtran/his/internal/domain"
+	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/middleware"
+	"github.com/minhtran/his/internal/pkg/response"
+	"github.com/minhtran/his/internal/service"
+)
+
+// ReferralHandler handles referral HTTP requests
+type ReferralHandler struct {
+	referralService *service.ReferralService
+}
+
+// NewReferralHandler creates a new referral handler
+func NewReferralHandler(referralService *service.ReferralService) *ReferralHandler {
+	return &ReferralHandler{referralService: referralService}
+}
+
+// CreateReferral handles referring the patient of a visit
+func (h *ReferralHandler) CreateReferral(c *gin.Context) {
+	visitID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid visit ID", nil)
+		return
+	}
+
+	var req dto.CreateReferralRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	referral, err := h.referralService.CreateReferral(uint(visitID), &req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to create referral")
+		return
+	}
+
+	response.Created(c, "Referral sent successfully", referral)
+}
+
+// GetVisitReferrals handles getting the referrals made in a visit
+func (h *ReferralHandler) GetVisitReferrals(c *gin.Context) {
+	visitID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid visit ID", nil)
+		return
+	}
+
+	referrals, err := h.referralService.GetVisitReferrals(uint(visitID))
+	if err != nil {
+		h.handleError(c, err, "Failed to get referrals")
+		return
+	}
+
+	response.Success(c, "Referrals retrieved successfully", referrals)
+}
+
+// GetInbox handles getting the referrals sent to a department or to the user
+func (h *ReferralHandler) GetInbox(c *gin.Context) {
+	var departmentID *uint
+	if value := c.Query("department_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			response.BadRequest(c, "Invalid department ID", nil)
+			return
+		}
+		deptID := uint(id)
+		departmentID = &deptID
+	}
+
+	status := c.Query("status")
+	switch domain.ReferralStatus(status) {
+	case "", domain.ReferralStatusSent, domain.ReferralStatusAccepted, domain.ReferralStatusDeclined, domain.ReferralStatusCompleted:
+	default:
+		response.BadRequest(c, "Invalid status, use SENT, ACCEPTED, DECLINED or COMPLETED", nil)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	referrals, err := h.referralService.GetInbox(userID, departmentID, status)
+	if err != nil {
+		response.InternalServerError(c, "Failed to get referral inbox")
+		return
+	}
+
+	response.Success(c, "Referral inbox retrieved successfully", referrals)
+}
+
+// GetReferral handles getting a referral
+func (h *ReferralHandler) GetReferral(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid referral ID", nil)
+		return
+	}
+
+	referral, err := h.referralService.GetReferral(uint(id))
+	if err != nil {
+		h.handleError(c, err, "Failed to get referral")
+		return
+	}
+
+	response.Success(c, "Referral retrieved successfully", referral)
+}
+
+// GetReferralLetter handles rendering the printable referral letter
+func (h *ReferralHandler) GetReferralLetter(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid referral ID", nil)
+		return
+	}
+
+	letter, err := h.referralService.GenerateReferralLetter(uint(id))
+	if err != nil {
+		h.handleError(c, err, "Failed to generate referral letter")
+		return
+	}
+
+	c.Data(http.StatusOK, "text/html; charset=utf-8", letter)
+}
+
+// AcceptReferral handles accepting a referral
+func (h *ReferralHandler) AcceptReferral(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid referral ID", nil)
+		return
+	}
+
+	var req dto.RespondReferralRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+			return
+		}
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	referral, err := h.referralService.AcceptReferral(uint(id), &req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to accept referral")
+		return
+	}
+
+	response.Success(c, "Referral accepted successfully", referral)
+}
+
+// DeclineReferral handles declining a referral
+func (h *ReferralHandler) DeclineReferral(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid referral ID", nil)
+		return
+	}
+
+	var req dto.RespondReferralRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	referral, err := h.referralService.DeclineReferral(uint(id), &req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to decline referral")
+		return
+	}
+
+	response.Success(c, "Referral declined successfully", referral)
+}
+
+// ScheduleReferral handles booking a referral as an appointment
+func (h *ReferralHandler) ScheduleReferral(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid referral ID", nil)
+		return
+	}
+
+	var req dto.ScheduleReferralRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	referral, err := h.referralService.ScheduleReferral(uint(id), &req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to book referral")
+		return
+	}
+
+	response.Success(c, "Referral booked successfully", referral)
+}
+
+// CompleteReferral handles recording that the referred patient was seen
+func (h *ReferralHandler) CompleteReferral(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid referral ID", nil)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	referral, err := h.referralService.CompleteReferral(uint(id), userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to complete referral")
+		return
+	}
+
+	response.Success(c, "Referral completed successfully", referral)
+}
+
+// handleError maps referral and appointment booking errors to HTTP responses
+func (h *ReferralHandler) handleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrReferralNotFound):
+		response.NotFound(c, "Referral not found")
+	case errors.Is(err, service.ErrVisitNotFound):
+		response.NotFound(c, "Visit not found")
+	case errors.Is(err, service.ErrDepartmentNotFound):
+		response.NotFound(c, "Department not found")
+	case errors.Is(err, service.ErrDoctorNotFound):
+		response.NotFound(c, "Doctor not found")
+	case errors.Is(err, service.ErrPatientNotFound):
+		response.NotFound(c, "Patient not found")
+	case errors.Is(err, service.ErrInvalidReferralTransition),
+		errors.Is(err, service.ErrReferralAlreadyScheduled):
+		response.Conflict(c, err.Error())
+	case errors.Is(err, service.ErrPatientDeceased):
+		response.BadRequest(c, "Patient is deceased", nil)
+	case errors.Is(err, service.ErrPatientBookingRestricted):
+		response.Forbidden(c, err.Error())
+	case errors.Is(err, service.ErrReferralTargetRequired),
+		errors.Is(err, service.ErrReferralAttachmentInvalid),
+		errors.Is(err, service.ErrReferralDeclineReason),
+		errors.Is(err, service.ErrReferralNotInternal),
+		errors.Is(err, service.ErrReferralDoctorRequired),
+		errors.Is(err, service.ErrInvalidAppointmentTime),
+		errors.Is(err, service.ErrDoctorNotAvailable),
+		errors.Is(err, service.ErrClinicSessionFull),
+		errors.Is(err, service.ErrPastAppointmentDate):
+		response.BadRequest(c, err.Error(), nil)
+	case errors.Is(err, service.ErrTimeSlotNotAvailable):
+		response.BadRequest(c, "Time slot not available", nil)
+	case errors.Is(err, service.ErrInvalidDateFormat):
+		response.BadRequest(c, "Invalid date format, use YYYY-MM-DD", nil)
+	default:
+		response.InternalServerError(c, fallback)
+	}
+}
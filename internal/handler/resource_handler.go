@@ -0,0 +1,248 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/middleware"
+	"github.com/minhtran/his/internal/pkg/response"
+	"github.com/minhtran/his/internal/service"
+)
+
+// ResourceHandler handles room and equipment resource HTTP requests
+type ResourceHandler struct {
+	resourceService *service.ResourceService
+}
+
+// NewResourceHandler creates a new resource handler
+func NewResourceHandler(resourceService *service.ResourceService) *ResourceHandler {
+	return &ResourceHandler{resourceService: resourceService}
+}
+
+// CreateResource handles adding a room or piece of equipment
+func (h *ResourceHandler) CreateResource(c *gin.Context) {
+	var req dto.CreateResourceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	resource, err := h.resourceService.CreateResource(&req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to create resource")
+		return
+	}
+
+	response.Created(c, "Resource created successfully", resource)
+}
+
+// ListResources handles listing resources with filters
+func (h *ResourceHandler) ListResources(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	filters := make(map[string]interface{})
+	if resourceType := c.Query("type"); resourceType != "" {
+		filters["type"] = resourceType
+	}
+	if category := c.Query("category"); category != "" {
+		filters["category"] = category
+	}
+	if departmentID := c.Query("department_id"); departmentID != "" {
+		filters["department_id"] = departmentID
+	}
+	if isActive := c.Query("is_active"); isActive != "" {
+		filters["is_active"] = isActive == "true"
+	}
+	if search := c.Query("search"); search != "" {
+		filters["search"] = search
+	}
+
+	resources, total, err := h.resourceService.ListResources(filters, page, pageSize)
+	if err != nil {
+		response.InternalServerError(c, "Failed to list resources")
+		return
+	}
+
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
+		totalPages++
+	}
+
+	response.SuccessPaginated(c, "Resources retrieved successfully", resources, response.Pagination{
+		Page:       page,
+		PageSize:   pageSize,
+		TotalItems: total,
+		TotalPages: totalPages,
+	})
+}
+
+// GetResource handles getting resource details
+func (h *ResourceHandler) GetResource(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid resource ID", nil)
+		return
+	}
+
+	resource, err := h.resourceService.GetResource(uint(id))
+	if err != nil {
+		h.handleError(c, err, "Failed to get resource")
+		return
+	}
+
+	response.Success(c, "Resource retrieved successfully", resource)
+}
+
+// UpdateResource handles updating a resource
+func (h *ResourceHandler) UpdateResource(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid resource ID", nil)
+		return
+	}
+
+	var req dto.UpdateResourceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	resource, err := h.resourceService.UpdateResource(uint(id), &req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to update resource")
+		return
+	}
+
+	response.Success(c, "Resource updated successfully", resource)
+}
+
+// SetHours handles replacing a resource's weekly opening hours
+func (h *ResourceHandler) SetHours(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid resource ID", nil)
+		return
+	}
+
+	var req dto.SetResourceHoursRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	resource, err := h.resourceService.SetHours(uint(id), &req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to update opening hours")
+		return
+	}
+
+	response.Success(c, "Opening hours updated successfully", resource)
+}
+
+// CreateDowntime handles taking a resource out of service for a period
+func (h *ResourceHandler) CreateDowntime(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid resource ID", nil)
+		return
+	}
+
+	var req dto.CreateResourceDowntimeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	downtime, err := h.resourceService.CreateDowntime(uint(id), &req, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidDateFormat) {
+			response.BadRequest(c, "Invalid date-time format, use RFC 3339", nil)
+			return
+		}
+		h.handleError(c, err, "Failed to create downtime")
+		return
+	}
+
+	response.Created(c, "Downtime created successfully", downtime)
+}
+
+// DeleteDowntime handles putting a resource back in service
+func (h *ResourceHandler) DeleteDowntime(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid resource ID", nil)
+		return
+	}
+
+	downtimeID, err := strconv.ParseUint(c.Param("downtimeId"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid downtime ID", nil)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	if err := h.resourceService.DeleteDowntime(uint(id), uint(downtimeID), userID); err != nil {
+		h.handleError(c, err, "Failed to delete downtime")
+		return
+	}
+
+	response.Success(c, "Downtime deleted successfully", nil)
+}
+
+// GetSchedule handles listing a resource's downtime and bookings over a date range
+func (h *ResourceHandler) GetSchedule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid resource ID", nil)
+		return
+	}
+
+	schedule, err := h.resourceService.GetSchedule(uint(id), c.Query("from_date"), c.Query("to_date"))
+	if err != nil {
+		h.handleError(c, err, "Failed to get resource schedule")
+		return
+	}
+
+	response.Success(c, "Resource schedule retrieved successfully", schedule)
+}
+
+// handleError maps resource service errors to responses
+func (h *ResourceHandler) handleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrResourceNotFound):
+		response.NotFound(c, "Resource not found")
+	case errors.Is(err, service.ErrResourceDowntimeNotFound):
+		response.NotFound(c, "Resource downtime not found")
+	case errors.Is(err, service.ErrDepartmentNotFound):
+		response.NotFound(c, "Department not found")
+	case errors.Is(err, service.ErrResourceCodeExists):
+		response.BadRequest(c, "Resource code already exists", nil)
+	case errors.Is(err, service.ErrInvalidResourceHours),
+		errors.Is(err, service.ErrInvalidDowntimePeriod),
+		errors.Is(err, service.ErrInvalidDateRange):
+		response.BadRequest(c, err.Error(), nil)
+	case errors.Is(err, service.ErrInvalidDateFormat):
+		response.BadRequest(c, "Invalid date format, use YYYY-MM-DD", nil)
+	default:
+		response.InternalServerError(c, fallback)
+	}
+}
@@ -35,6 +35,27 @@ func SetupRoutes(
 	departmentHandler *DepartmentHandler,
 	medicalServiceHandler *MedicalServiceHandler,
 	auditLogHandler *AuditLogHandler,
+	deathRecordHandler *DeathRecordHandler,
+	insurancePayerHandler *InsurancePayerHandler,
+	coverageHandler *PatientCoverageHandler,
+	patientImportHandler *PatientImportHandler,
+	labelHandler *LabelHandler,
+	portalAccountHandler *PortalAccountHandler,
+	portalHandler *PortalHandler,
+	doctorScheduleHandler *DoctorScheduleHandler,
+	appointmentSeriesHandler *AppointmentSeriesHandler,
+	waitlistHandler *WaitlistHandler,
+	notificationHandler *NotificationHandler,
+	resourceHandler *ResourceHandler,
+	noShowHandler *NoShowHandler,
+	calendarFeedHandler *CalendarFeedHandler,
+	publicBookingHandler *PublicBookingHandler,
+	queueHandler *QueueHandler,
+	triageHandler *TriageHandler,
+	clinicalNoteHandler *ClinicalNoteHandler,
+	referralHandler *ReferralHandler,
+	followUpHandler *FollowUpHandler,
+	visitCompletionHandler *VisitCompletionHandler,
 	jwtManager *jwt.Manager,
 	rbacMiddleware *middleware.RBACMiddleware,
 	allowedOrigins []string,
@@ -51,6 +72,16 @@ func SetupRoutes(
 		})
 	})
 
+	// iCalendar feeds, authenticated by the secret token in the URL
+	r.GET("/calendar/:token", calendarFeedHandler.GetFeed)
+
+	// Waiting-room displays and kiosks; queue displays carry no patient details
+	display := r.Group("/display/v1")
+	{
+		display.GET("/queues/:id", queueHandler.GetDisplay)
+		display.GET("/queues/:id/stream", queueHandler.StreamDisplay)
+	}
+
 	// API v1 routes
 	v1 := r.Group("/api/v1")
 	{
@@ -78,6 +109,7 @@ func SetupRoutes(
 				users.PUT("/:id", userHandler.UpdateUser)
 				users.DELETE("/:id", userHandler.DeleteUser)
 				users.POST("/:id/roles", userHandler.AssignRoles)
+				users.DELETE("/:id/calendar-feed", calendarFeedHandler.RevokeUserFeed)
 			}
 
 			// Patient management routes
@@ -92,6 +124,12 @@ func SetupRoutes(
 				// Get by code (requires view permission)
 				patients.GET("/code/:code", rbacMiddleware.RequirePermission("patients.view"), patientHandler.GetPatientByCode)
 
+				// Bulk import (CSV/XLSX) with dry-run and per-row report
+				patients.POST("/imports", rbacMiddleware.RequirePermission("patients.create"), patientImportHandler.ImportPatients)
+				patients.GET("/imports", rbacMiddleware.RequirePermission("patients.create"), patientImportHandler.ListImportJobs)
+				patients.GET("/imports/:importId", rbacMiddleware.RequirePermission("patients.create"), patientImportHandler.GetImportJob)
+				patients.GET("/imports/:importId/report", rbacMiddleware.RequirePermission("patients.create"), patientImportHandler.GetImportReport)
+
 				// Register patient (requires create permission)
 				patients.POST("", rbacMiddleware.RequirePermission("patients.create"), patientHandler.RegisterPatient)
 
@@ -116,6 +154,24 @@ func SetupRoutes(
 				patients.POST("/:id/medical-history", rbacMiddleware.RequirePermission("patients.create"), historyHandler.AddMedicalHistory)
 				patients.GET("/:id/medical-history", rbacMiddleware.RequirePermission("patients.view"), historyHandler.GetPatientHistory)
 				patients.GET("/:id/medical-history/active", rbacMiddleware.RequirePermission("patients.view"), historyHandler.GetActiveConditions)
+
+				// Patient insurance coverage sub-routes
+				patients.POST("/:id/coverages", rbacMiddleware.RequirePermission("patients.update"), coverageHandler.AddCoverage)
+				patients.GET("/:id/coverages", rbacMiddleware.RequirePermission("patients.view"), coverageHandler.GetPatientCoverages)
+				patients.GET("/:id/coverages/active", rbacMiddleware.RequirePermission("patients.view"), coverageHandler.GetActiveCoverages)
+
+				// Death registration sub-routes
+				patients.POST("/:id/death-record", rbacMiddleware.RequirePermission("patients.register_death"), deathRecordHandler.RegisterDeath)
+				patients.GET("/:id/death-record", rbacMiddleware.RequirePermission("patients.view"), deathRecordHandler.GetDeathRecord)
+				patients.GET("/:id/death-record/certificate", rbacMiddleware.RequirePermission("patients.view"), deathRecordHandler.GetDeathCertificate)
+
+				// Wristband and chart label printing
+				patients.GET("/:id/labels", rbacMiddleware.RequirePermission("labels.print"), labelHandler.PrintPatientLabels)
+
+				// Patient portal account
+				patients.POST("/:id/portal-account", rbacMiddleware.RequirePermission("patients.update"), portalAccountHandler.CreateAccount)
+				patients.GET("/:id/portal-account", rbacMiddleware.RequirePermission("patients.view"), portalAccountHandler.GetAccount)
+				patients.DELETE("/:id/portal-account", rbacMiddleware.RequirePermission("patients.update"), portalAccountHandler.DeactivateAccount)
 			}
 
 			// Allergy routes (standalone)
@@ -126,6 +182,14 @@ func SetupRoutes(
 				allergies.DELETE("/:allergyId", rbacMiddleware.RequirePermission("patients.delete"), allergyHandler.DeleteAllergy)
 			}
 
+			// Coverage routes (standalone)
+			coverages := protected.Group("/coverages")
+			{
+				coverages.GET("/:coverageId", rbacMiddleware.RequirePermission("patients.view"), coverageHandler.GetCoverage)
+				coverages.PUT("/:coverageId", rbacMiddleware.RequirePermission("patients.update"), coverageHandler.UpdateCoverage)
+				coverages.DELETE("/:coverageId", rbacMiddleware.RequirePermission("patients.delete"), coverageHandler.DeleteCoverage)
+			}
+
 			// Medical history routes (standalone)
 			medicalHistory := protected.Group("/medical-history")
 			{
@@ -159,12 +223,98 @@ func SetupRoutes(
 				appointments.POST("/:id/no-show", rbacMiddleware.RequirePermission("appointments.manage"), appointmentHandler.MarkNoShow)
 			}
 
+			// Recurring appointment series routes
+			appointmentSeries := protected.Group("/appointment-series")
+			{
+				appointmentSeries.POST("/preview", rbacMiddleware.RequirePermission("appointments.create"), appointmentSeriesHandler.PreviewSeries)
+				appointmentSeries.POST("", rbacMiddleware.RequirePermission("appointments.create"), appointmentSeriesHandler.CreateSeries)
+				appointmentSeries.GET("/:id", rbacMiddleware.RequirePermission("appointments.view"), appointmentSeriesHandler.GetSeries)
+				appointmentSeries.POST("/:id/reschedule", rbacMiddleware.RequirePermission("appointments.update"), appointmentSeriesHandler.RescheduleSeries)
+				appointmentSeries.POST("/:id/cancel", rbacMiddleware.RequirePermission("appointments.cancel"), appointmentSeriesHandler.CancelSeries)
+			}
+
+			// Appointment waitlist routes
+			waitlist := protected.Group("/waitlist")
+			{
+				waitlist.POST("", rbacMiddleware.RequirePermission("appointments.create"), waitlistHandler.CreateEntry)
+				waitlist.GET("", rbacMiddleware.RequirePermission("appointments.view"), waitlistHandler.ListEntries)
+				waitlist.GET("/:id", rbacMiddleware.RequirePermission("appointments.view"), waitlistHandler.GetEntry)
+				waitlist.POST("/:id/cancel", rbacMiddleware.RequirePermission("appointments.update"), waitlistHandler.CancelEntry)
+			}
+
+			// Waitlist slot offers
+			waitlistOffers := protected.Group("/waitlist-offers")
+			{
+				waitlistOffers.POST("/:id/accept", rbacMiddleware.RequirePermission("appointments.create"), waitlistHandler.AcceptOffer)
+				waitlistOffers.POST("/:id/decline", rbacMiddleware.RequirePermission("appointments.update"), waitlistHandler.DeclineOffer)
+			}
+
+			// Patient notification routes
+			notifications := protected.Group("/notifications")
+			{
+				notifications.GET("", rbacMiddleware.RequirePermission("notifications.view"), notificationHandler.ListNotifications)
+				notifications.GET("/:id", rbacMiddleware.RequirePermission("notifications.view"), notificationHandler.GetNotification)
+				notifications.POST("/:id/retry", rbacMiddleware.RequirePermission("notifications.manage"), notificationHandler.RetryNotification)
+			}
+
+			// Notification template routes
+			notificationTemplates := protected.Group("/notification-templates")
+			{
+				notificationTemplates.GET("", rbacMiddleware.RequirePermission("notifications.manage"), notificationHandler.ListTemplates)
+				notificationTemplates.POST("", rbacMiddleware.RequirePermission("notifications.manage"), notificationHandler.CreateTemplate)
+				notificationTemplates.PUT("/:id", rbacMiddleware.RequirePermission("notifications.manage"), notificationHandler.UpdateTemplate)
+			}
+
+			// Room and equipment resource routes
+			resources := protected.Group("/resources")
+			{
+				resources.POST("", rbacMiddleware.RequirePermission("resources.manage"), resourceHandler.CreateResource)
+				resources.GET("", rbacMiddleware.RequirePermission("resources.view"), resourceHandler.ListResources)
+				resources.GET("/:id", rbacMiddleware.RequirePermission("resources.view"), resourceHandler.GetResource)
+				resources.PUT("/:id", rbacMiddleware.RequirePermission("resources.manage"), resourceHandler.UpdateResource)
+				resources.PUT("/:id/hours", rbacMiddleware.RequirePermission("resources.manage"), resourceHandler.SetHours)
+				resources.POST("/:id/downtimes", rbacMiddleware.RequirePermission("resources.manage"), resourceHandler.CreateDowntime)
+				resources.DELETE("/:id/downtimes/:downtimeId", rbacMiddleware.RequirePermission("resources.manage"), resourceHandler.DeleteDowntime)
+				resources.GET("/:id/schedule", rbacMiddleware.RequirePermission("resources.view"), resourceHandler.GetSchedule)
+			}
+
 			// Patient appointments sub-routes
 			protected.GET("/patients/:id/appointments", rbacMiddleware.RequirePermission("appointments.view"), appointmentHandler.GetPatientAppointments)
+			protected.GET("/patients/:id/no-show-stats", rbacMiddleware.RequirePermission("appointments.view"), noShowHandler.GetPatientStats)
+			protected.GET("/patients/:id/follow-up-adherence", rbacMiddleware.RequirePermission("appointments.view"), followUpHandler.GetPatientAdherence)
 
 			// Doctor schedule routes
 			protected.GET("/doctors/:id/schedule", rbacMiddleware.RequirePermission("appointments.view"), appointmentHandler.GetDoctorSchedule)
 			protected.GET("/doctors/:id/available-slots", rbacMiddleware.RequirePermission("appointments.view"), appointmentHandler.GetAvailableTimeSlots)
+			protected.GET("/doctors/:id/overbooking", rbacMiddleware.RequirePermission("appointments.view"), noShowHandler.GetDoctorOverbooking)
+			protected.GET("/doctors/:id/follow-up-adherence", rbacMiddleware.RequirePermission("appointments.view"), followUpHandler.GetDoctorAdherence)
+
+			// Slot availability across doctors and days for the booking screen
+			protected.GET("/availability", rbacMiddleware.RequirePermission("appointments.view"), appointmentHandler.GetAvailability)
+
+			// Current user's iCalendar feed of their appointments
+			calendarFeed := protected.Group("/calendar-feed")
+			{
+				calendarFeed.GET("", rbacMiddleware.RequirePermission("appointments.view"), calendarFeedHandler.GetMyFeed)
+				calendarFeed.POST("", rbacMiddleware.RequirePermission("appointments.view"), calendarFeedHandler.RotateMyFeed)
+				calendarFeed.DELETE("", calendarFeedHandler.RevokeMyFeed)
+			}
+
+			// Doctor work schedule routes
+			doctors := protected.Group("/doctors/:id")
+			{
+				doctors.GET("/sessions", rbacMiddleware.RequirePermission("appointments.view"), doctorScheduleHandler.GetDaySchedule)
+				doctors.GET("/schedules", rbacMiddleware.RequirePermission("appointments.view"), doctorScheduleHandler.GetSchedules)
+				doctors.POST("/schedules", rbacMiddleware.RequirePermission("schedules.manage"), doctorScheduleHandler.CreateSchedule)
+				doctors.PUT("/schedules/:scheduleId", rbacMiddleware.RequirePermission("schedules.manage"), doctorScheduleHandler.UpdateSchedule)
+				doctors.DELETE("/schedules/:scheduleId", rbacMiddleware.RequirePermission("schedules.manage"), doctorScheduleHandler.DeleteSchedule)
+				doctors.GET("/schedule-overrides", rbacMiddleware.RequirePermission("appointments.view"), doctorScheduleHandler.GetOverrides)
+				doctors.POST("/schedule-overrides", rbacMiddleware.RequirePermission("schedules.manage"), doctorScheduleHandler.CreateOverride)
+				doctors.DELETE("/schedule-overrides/:overrideId", rbacMiddleware.RequirePermission("schedules.manage"), doctorScheduleHandler.DeleteOverride)
+				doctors.GET("/leaves", rbacMiddleware.RequirePermission("appointments.view"), doctorScheduleHandler.GetLeaves)
+				doctors.POST("/leaves", rbacMiddleware.RequirePermission("schedules.manage"), doctorScheduleHandler.CreateLeave)
+				doctors.DELETE("/leaves/:leaveId", rbacMiddleware.RequirePermission("schedules.manage"), doctorScheduleHandler.DeleteLeave)
+			}
 
 			// Visit routes
 			visits := protected.Group("/visits")
@@ -172,6 +322,7 @@ func SetupRoutes(
 				// List and search
 				visits.GET("", rbacMiddleware.RequirePermission("visits.view"), visitHandler.ListVisits)
 				visits.GET("/code/:code", rbacMiddleware.RequirePermission("visits.view"), visitHandler.GetVisitByCode)
+				visits.GET("/analytics/wait-times", rbacMiddleware.RequirePermission("visits.view"), visitHandler.GetWaitTimeAnalytics)
 
 				// Create
 				visits.POST("", rbacMiddleware.RequirePermission("visits.create"), visitHandler.CreateVisit)
@@ -183,8 +334,100 @@ func SetupRoutes(
 				visits.PUT("/:id", rbacMiddleware.RequirePermission("visits.update"), visitHandler.UpdateVisit)
 
 				// Status transitions
+				visits.POST("/:id/start", rbacMiddleware.RequirePermission("visits.update"), visitHandler.StartVisit)
+				visits.POST("/:id/pause", rbacMiddleware.RequirePermission("visits.update"), visitHandler.PauseVisit)
+				visits.GET("/:id/completion-checklist", rbacMiddleware.RequirePermission("visits.view"), visitCompletionHandler.GetChecklist)
 				visits.POST("/:id/complete", rbacMiddleware.RequirePermission("visits.complete"), visitHandler.CompleteVisit)
+				visits.POST("/:id/invoice", rbacMiddleware.RequirePermission("invoices.create"), visitCompletionHandler.DraftInvoice)
 				visits.POST("/:id/cancel", rbacMiddleware.RequirePermission("visits.delete"), visitHandler.CancelVisit)
+
+				// Triage
+				visits.POST("/:id/triage", rbacMiddleware.RequirePermission("triage.create"), triageHandler.TriageVisit)
+				visits.GET("/:id/triage", rbacMiddleware.RequirePermission("triage.view"), triageHandler.GetVisitTriages)
+
+				// Clinical notes
+				visits.POST("/:id/clinical-notes", rbacMiddleware.RequirePermission("clinical_notes.create"), clinicalNoteHandler.CreateNote)
+				visits.GET("/:id/clinical-notes", rbacMiddleware.RequirePermission("clinical_notes.view"), clinicalNoteHandler.GetVisitNotes)
+
+				// Referrals
+				visits.POST("/:id/referrals", rbacMiddleware.RequirePermission("referrals.create"), referralHandler.CreateReferral)
+				visits.GET("/:id/referrals", rbacMiddleware.RequirePermission("referrals.view"), referralHandler.GetVisitReferrals)
+
+				// Follow-up ordered with the next visit date
+				visits.GET("/:id/follow-up", rbacMiddleware.RequirePermission("appointments.view"), followUpHandler.GetVisitFollowUp)
+			}
+
+			// Referral routes
+			referrals := protected.Group("/referrals")
+			{
+				referrals.GET("/inbox", rbacMiddleware.RequirePermission("referrals.view"), referralHandler.GetInbox)
+				referrals.GET("/:id", rbacMiddleware.RequirePermission("referrals.view"), referralHandler.GetReferral)
+				referrals.GET("/:id/letter", rbacMiddleware.RequirePermission("referrals.view"), referralHandler.GetReferralLetter)
+				referrals.POST("/:id/accept", rbacMiddleware.RequirePermission("referrals.respond"), referralHandler.AcceptReferral)
+				referrals.POST("/:id/decline", rbacMiddleware.RequirePermission("referrals.respond"), referralHandler.DeclineReferral)
+				referrals.POST("/:id/appointment", rbacMiddleware.RequirePermission("referrals.respond"), referralHandler.ScheduleReferral)
+				referrals.POST("/:id/complete", rbacMiddleware.RequirePermission("referrals.respond"), referralHandler.CompleteReferral)
+			}
+
+			// Follow-up routes
+			followUps := protected.Group("/follow-ups")
+			{
+				followUps.GET("", rbacMiddleware.RequirePermission("appointments.view"), followUpHandler.ListFollowUps)
+				followUps.GET("/worklist", rbacMiddleware.RequirePermission("appointments.view"), followUpHandler.GetReceptionWorklist)
+				followUps.GET("/:id", rbacMiddleware.RequirePermission("appointments.view"), followUpHandler.GetFollowUp)
+				followUps.POST("/:id/confirm", rbacMiddleware.RequirePermission("appointments.update"), followUpHandler.ConfirmFollowUp)
+				followUps.POST("/:id/appointment", rbacMiddleware.RequirePermission("appointments.create"), followUpHandler.ScheduleFollowUp)
+				followUps.POST("/:id/cancel", rbacMiddleware.RequirePermission("appointments.update"), followUpHandler.CancelFollowUp)
+			}
+
+			// Clinical note routes
+			clinicalNotes := protected.Group("/clinical-notes")
+			{
+				clinicalNotes.GET("/:id", rbacMiddleware.RequirePermission("clinical_notes.view"), clinicalNoteHandler.GetNote)
+				clinicalNotes.PUT("/:id", rbacMiddleware.RequirePermission("clinical_notes.create"), clinicalNoteHandler.UpdateNote)
+				clinicalNotes.DELETE("/:id", rbacMiddleware.RequirePermission("clinical_notes.create"), clinicalNoteHandler.DeleteNote)
+				clinicalNotes.POST("/:id/sign", rbacMiddleware.RequirePermission("clinical_notes.sign"), clinicalNoteHandler.SignNote)
+				clinicalNotes.POST("/:id/cosign", rbacMiddleware.RequirePermission("clinical_notes.sign"), clinicalNoteHandler.CosignNote)
+				clinicalNotes.POST("/:id/addenda", rbacMiddleware.RequirePermission("clinical_notes.create"), clinicalNoteHandler.AddAddendum)
+				clinicalNotes.GET("/:id/versions", rbacMiddleware.RequirePermission("clinical_notes.view"), clinicalNoteHandler.GetNoteVersions)
+			}
+
+			// Clinical note template routes
+			noteTemplates := protected.Group("/clinical-note-templates")
+			{
+				noteTemplates.GET("", rbacMiddleware.RequirePermission("clinical_notes.view"), clinicalNoteHandler.ListTemplates)
+				noteTemplates.POST("", rbacMiddleware.RequirePermission("clinical_notes.manage"), clinicalNoteHandler.CreateTemplate)
+				noteTemplates.PUT("/:id", rbacMiddleware.RequirePermission("clinical_notes.manage"), clinicalNoteHandler.UpdateTemplate)
+				noteTemplates.DELETE("/:id", rbacMiddleware.RequirePermission("clinical_notes.manage"), clinicalNoteHandler.DeleteTemplate)
+			}
+
+			// Triage routes
+			triage := protected.Group("/triage")
+			{
+				triage.GET("/worklist", rbacMiddleware.RequirePermission("triage.view"), triageHandler.GetWorklist)
+				triage.GET("/rules", rbacMiddleware.RequirePermission("triage.view"), triageHandler.ListRules)
+				triage.POST("/rules", rbacMiddleware.RequirePermission("triage.manage"), triageHandler.CreateRule)
+				triage.PUT("/rules/:id", rbacMiddleware.RequirePermission("triage.manage"), triageHandler.UpdateRule)
+				triage.DELETE("/rules/:id", rbacMiddleware.RequirePermission("triage.manage"), triageHandler.DeleteRule)
+			}
+
+			// Outpatient queue routes
+			queues := protected.Group("/queues")
+			{
+				queues.POST("", rbacMiddleware.RequirePermission("queues.manage"), queueHandler.CreateQueue)
+				queues.GET("", rbacMiddleware.RequirePermission("queues.view"), queueHandler.ListQueues)
+				queues.GET("/:id", rbacMiddleware.RequirePermission("queues.view"), queueHandler.GetQueue)
+				queues.PUT("/:id", rbacMiddleware.RequirePermission("queues.manage"), queueHandler.UpdateQueue)
+				queues.GET("/:id/board", rbacMiddleware.RequirePermission("queues.view"), queueHandler.GetBoard)
+				queues.POST("/:id/tickets", rbacMiddleware.RequirePermission("visits.create"), queueHandler.IssueTicket)
+				queues.POST("/:id/call-next", rbacMiddleware.RequirePermission("queues.call"), queueHandler.CallNext)
+			}
+
+			queueTickets := protected.Group("/queue-tickets")
+			{
+				queueTickets.POST("/:id/recall", rbacMiddleware.RequirePermission("queues.call"), queueHandler.RecallTicket)
+				queueTickets.POST("/:id/skip", rbacMiddleware.RequirePermission("queues.call"), queueHandler.SkipTicket)
+				queueTickets.POST("/:id/requeue", rbacMiddleware.RequirePermission("queues.call"), queueHandler.RequeueTicket)
 			}
 
 			// Patient visits sub-routes
@@ -256,6 +499,9 @@ func SetupRoutes(
 				labTestRequests.POST("/:id/complete", rbacMiddleware.RequirePermission("lab_tests.enter_results"), labTestRequestHandler.CompleteTest)
 				labTestRequests.POST("/:id/cancel", rbacMiddleware.RequirePermission("lab_tests.delete"), labTestRequestHandler.CancelTest)
 				labTestRequests.POST("/:id/results", rbacMiddleware.RequirePermission("lab_tests.enter_results"), labTestRequestHandler.EnterResults)
+				labTestRequests.GET("/:id/labels", rbacMiddleware.RequirePermission("labels.print"), labelHandler.PrintLabTestRequestLabels)
+				labTestRequests.POST("/:id/portal-release", rbacMiddleware.RequirePermission("lab_tests.release"), labTestRequestHandler.ReleaseToPortal)
+				labTestRequests.DELETE("/:id/portal-release", rbacMiddleware.RequirePermission("lab_tests.release"), labTestRequestHandler.WithdrawFromPortal)
 			}
 
 			// Visit/Patient lab test sub-routes
@@ -281,6 +527,8 @@ func SetupRoutes(
 				imagingRequests.POST("/:id/complete", rbacMiddleware.RequirePermission("imaging.report"), imagingRequestHandler.CompleteImaging)
 				imagingRequests.POST("/:id/cancel", rbacMiddleware.RequirePermission("imaging.delete"), imagingRequestHandler.CancelImaging)
 				imagingRequests.POST("/:id/result", rbacMiddleware.RequirePermission("imaging.report"), imagingRequestHandler.CreateOrUpdateResult)
+				imagingRequests.POST("/:id/portal-release", rbacMiddleware.RequirePermission("imaging.release"), imagingRequestHandler.ReleaseToPortal)
+				imagingRequests.DELETE("/:id/portal-release", rbacMiddleware.RequirePermission("imaging.release"), imagingRequestHandler.WithdrawFromPortal)
 			}
 
 			// Visit/Patient imaging sub-routes
@@ -305,6 +553,8 @@ func SetupRoutes(
 				admissions.GET("/active", rbacMiddleware.RequirePermission("admissions.view"), admissionHandler.GetActiveAdmissions)
 				admissions.POST("/:id/nursing-notes", rbacMiddleware.RequirePermission("nursing_notes.create"), admissionHandler.CreateNursingNote)
 				admissions.GET("/:id/nursing-notes", rbacMiddleware.RequirePermission("nursing_notes.view"), admissionHandler.GetAdmissionNursingNotes)
+				admissions.GET("/:id/early-warning-scores", rbacMiddleware.RequirePermission("nursing_notes.view"), admissionHandler.GetEarlyWarningTrend)
+				admissions.GET("/:id/labels", rbacMiddleware.RequirePermission("labels.print"), labelHandler.PrintAdmissionLabels)
 			}
 
 			// Patient admission history sub-route
@@ -335,6 +585,7 @@ func SetupRoutes(
 				invoices.POST("", rbacMiddleware.RequirePermission("invoices.create"), invoiceHandler.CreateInvoice)
 				invoices.GET("/:id", rbacMiddleware.RequirePermission("invoices.view"), invoiceHandler.GetInvoice)
 				invoices.GET("/code/:code", rbacMiddleware.RequirePermission("invoices.view"), invoiceHandler.GetInvoiceByCode)
+				invoices.POST("/:id/issue", rbacMiddleware.RequirePermission("invoices.create"), invoiceHandler.IssueInvoice)
 			}
 
 			// Payment routes
@@ -378,6 +629,34 @@ func SetupRoutes(
 					services.PUT("/:id", rbacMiddleware.RequirePermission("services.update"), medicalServiceHandler.UpdateService)
 				}
 
+				// Insurance Payers
+				payers := system.Group("/insurance-payers")
+				{
+					payers.POST("", rbacMiddleware.RequirePermission("insurance_claims.manage"), insurancePayerHandler.CreatePayer)
+					payers.GET("", rbacMiddleware.RequirePermission("insurance_claims.view"), insurancePayerHandler.ListPayers)
+					payers.GET("/:id", rbacMiddleware.RequirePermission("insurance_claims.view"), insurancePayerHandler.GetPayer)
+					payers.PUT("/:id", rbacMiddleware.RequirePermission("insurance_claims.manage"), insurancePayerHandler.UpdatePayer)
+					payers.DELETE("/:id", rbacMiddleware.RequirePermission("insurance_claims.manage"), insurancePayerHandler.DeletePayer)
+				}
+
+				// Label Templates
+				labelTemplates := system.Group("/label-templates")
+				{
+					labelTemplates.POST("", rbacMiddleware.RequirePermission("labels.manage"), labelHandler.CreateTemplate)
+					labelTemplates.GET("", rbacMiddleware.RequirePermission("labels.print"), labelHandler.ListTemplates)
+					labelTemplates.GET("/:id", rbacMiddleware.RequirePermission("labels.print"), labelHandler.GetTemplate)
+					labelTemplates.PUT("/:id", rbacMiddleware.RequirePermission("labels.manage"), labelHandler.UpdateTemplate)
+					labelTemplates.DELETE("/:id", rbacMiddleware.RequirePermission("labels.manage"), labelHandler.DeleteTemplate)
+				}
+
+				// Holidays
+				holidays := system.Group("/holidays")
+				{
+					holidays.GET("", rbacMiddleware.RequirePermission("appointments.view"), doctorScheduleHandler.ListHolidays)
+					holidays.POST("", rbacMiddleware.RequirePermission("schedules.manage"), doctorScheduleHandler.CreateHoliday)
+					holidays.DELETE("/:id", rbacMiddleware.RequirePermission("schedules.manage"), doctorScheduleHandler.DeleteHoliday)
+				}
+
 				// Audit Logs
 				audit := system.Group("/audit-logs")
 				{
@@ -386,4 +665,57 @@ func SetupRoutes(
 			}
 		}
 	}
+
+	// Patient portal routes
+	portal := r.Group("/portal/v1")
+	{
+		// Public portal auth routes
+		portalAuth := portal.Group("/auth")
+		{
+			portalAuth.POST("/otp/request", portalHandler.RequestOTP)
+			portalAuth.POST("/otp/verify", portalHandler.VerifyOTP)
+			portalAuth.POST("/login", portalHandler.Login)
+			portalAuth.POST("/refresh", portalHandler.RefreshToken)
+		}
+
+		// Routes scoped to the signed-in patient
+		portalProtected := portal.Group("")
+		portalProtected.Use(middleware.PortalAuthMiddleware(jwtManager))
+		{
+			portalProtected.GET("/me", portalHandler.GetProfile)
+			portalProtected.PUT("/me/password", portalHandler.SetPassword)
+
+			portalProtected.GET("/appointments", portalHandler.GetAppointments)
+			portalProtected.POST("/appointments", portalHandler.BookAppointment)
+			portalProtected.GET("/appointments/:id", portalHandler.GetAppointment)
+			portalProtected.POST("/appointments/:id/cancel", portalHandler.CancelAppointment)
+
+			portalProtected.GET("/lab-results", portalHandler.GetLabResults)
+			portalProtected.GET("/lab-results/:id", portalHandler.GetLabResult)
+			portalProtected.GET("/imaging-reports", portalHandler.GetImagingReports)
+			portalProtected.GET("/imaging-reports/:id", portalHandler.GetImagingReport)
+			portalProtected.GET("/prescriptions", portalHandler.GetPrescriptions)
+			portalProtected.GET("/prescriptions/:id", portalHandler.GetPrescription)
+			portalProtected.GET("/invoices", portalHandler.GetInvoices)
+			portalProtected.GET("/invoices/:id", portalHandler.GetInvoice)
+		}
+	}
+
+	// Public online booking routes; holds are addressed by their secret token
+	public := r.Group("/public/v1")
+	{
+		public.GET("/departments", publicBookingHandler.ListDepartments)
+		public.GET("/doctors", publicBookingHandler.ListDoctors)
+		public.GET("/availability", publicBookingHandler.GetAvailability)
+
+		holds := public.Group("/holds")
+		{
+			holds.POST("", publicBookingHandler.CreateHold)
+			holds.GET("/:token", publicBookingHandler.GetHold)
+			holds.DELETE("/:token", publicBookingHandler.ReleaseHold)
+			holds.POST("/:token/resend-code", publicBookingHandler.ResendCode)
+			holds.POST("/:token/verify", publicBookingHandler.VerifyHold)
+			holds.POST("/:token/confirm", publicBookingHandler.ConfirmBooking)
+		}
+	}
 }
@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/middleware"
+	"github.com/minhtran/his/internal/pkg/response"
+	"github.com/minhtran/his/internal/service"
+)
+
+// TriageHandler handles triage HTTP requests
+type TriageHandler struct {
+	triageService *service.TriageService
+}
+
+// NewTriageHandler creates a new triage handler
+func NewTriageHandler(triageService *service.TriageService) *TriageHandler {
+	return &TriageHandler{triageService: triageService}
+}
+
+// TriageVisit handles triaging or re-triaging a visit
+func (h *TriageHandler) TriageVisit(c *gin.Context) {
+	visitID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid visit ID", nil)
+		return
+	}
+
+	var req dto.TriageVisitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	triage, err := h.triageService.TriageVisit(uint(visitID), &req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to triage visit")
+		return
+	}
+
+	response.Created(c, "Visit triaged successfully", triage)
+}
+
+// GetVisitTriages handles getting a visit's triage history
+func (h *TriageHandler) GetVisitTriages(c *gin.Context) {
+	visitID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid visit ID", nil)
+		return
+	}
+
+	triages, err := h.triageService.GetVisitTriages(uint(visitID))
+	if err != nil {
+		h.handleError(c, err, "Failed to get triage history")
+		return
+	}
+
+	response.Success(c, "Triage history retrieved successfully", triages)
+}
+
+// GetWorklist handles getting the emergency visits waiting to be seen
+func (h *TriageHandler) GetWorklist(c *gin.Context) {
+	items, err := h.triageService.GetWorklist()
+	if err != nil {
+		response.InternalServerError(c, "Failed to get triage worklist")
+		return
+	}
+
+	response.Success(c, "Triage worklist retrieved successfully", items)
+}
+
+// ListRules handles listing triage rules
+func (h *TriageHandler) ListRules(c *gin.Context) {
+	filters := make(map[string]interface{})
+	if scale := c.Query("scale"); scale != "" {
+		filters["scale"] = scale
+	}
+	if category := c.Query("complaint_category"); category != "" {
+		filters["complaint_category"] = category
+	}
+	if isActive := c.Query("is_active"); isActive != "" {
+		filters["is_active"] = isActive == "true"
+	}
+
+	rules, err := h.triageService.ListRules(filters)
+	if err != nil {
+		response.InternalServerError(c, "Failed to list triage rules")
+		return
+	}
+
+	response.Success(c, "Triage rules retrieved successfully", rules)
+}
+
+// CreateRule handles adding a rule to a triage ruleset
+func (h *TriageHandler) CreateRule(c *gin.Context) {
+	var req dto.CreateTriageRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	rule, err := h.triageService.CreateRule(&req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to create triage rule")
+		return
+	}
+
+	response.Created(c, "Triage rule created successfully", rule)
+}
+
+// UpdateRule handles updating a triage rule
+func (h *TriageHandler) UpdateRule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid rule ID", nil)
+		return
+	}
+
+	var req dto.UpdateTriageRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	rule, err := h.triageService.UpdateRule(uint(id), &req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to update triage rule")
+		return
+	}
+
+	response.Success(c, "Triage rule updated successfully", rule)
+}
+
+// DeleteRule handles removing a triage rule
+func (h *TriageHandler) DeleteRule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid rule ID", nil)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	if err := h.triageService.DeleteRule(uint(id), userID); err != nil {
+		h.handleError(c, err, "Failed to delete triage rule")
+		return
+	}
+
+	response.Success(c, "Triage rule deleted successfully", nil)
+}
+
+// handleError maps triage errors to HTTP responses
+func (h *TriageHandler) handleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrVisitNotFound):
+		response.NotFound(c, "Visit not found")
+	case errors.Is(err, service.ErrTriageRuleNotFound):
+		response.NotFound(c, "Triage rule not found")
+	case errors.Is(err, service.ErrVisitNotOpen),
+		errors.Is(err, service.ErrTriageOverrideReason),
+		errors.Is(err, service.ErrInvalidTriageRule):
+		response.BadRequest(c, err.Error(), nil)
+	default:
+		response.InternalServerError(c, fallback)
+	}
+}
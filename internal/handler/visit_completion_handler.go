@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minhtran/his/internal/middleware"
+	"github.com/minhtran/his/internal/pkg/response"
+	"github.com/minhtran/his/internal/service"
+)
+
+// VisitCompletionHandler handles visit completion checklist HTTP requests
+type VisitCompletionHandler struct {
+	visitCompletionService *service.VisitCompletionService
+}
+
+// NewVisitCompletionHandler creates a new visit completion handler
+func NewVisitCompletionHandler(visitCompletionService *service.VisitCompletionService) *VisitCompletionHandler {
+	return &VisitCompletionHandler{visitCompletionService: visitCompletionService}
+}
+
+// GetChecklist handles getting the rules checked before a visit is completed
+func (h *VisitCompletionHandler) GetChecklist(c *gin.Context) {
+	visitID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid visit ID", nil)
+		return
+	}
+
+	acknowledge, _ := strconv.ParseBool(c.DefaultQuery("acknowledge_pending_orders", "false"))
+
+	checklist, err := h.visitCompletionService.GetChecklist(uint(visitID), acknowledge)
+	if err != nil {
+		if errors.Is(err, service.ErrVisitNotFound) {
+			response.NotFound(c, "Visit not found")
+			return
+		}
+		response.InternalServerError(c, "Failed to get completion checklist")
+		return
+	}
+
+	response.Success(c, "Completion checklist retrieved successfully", checklist)
+}
+
+// DraftInvoice handles drafting the invoice of a completed visit that has none
+func (h *VisitCompletionHandler) DraftInvoice(c *gin.Context) {
+	visitID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid visit ID", nil)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	invoice, err := h.visitCompletionService.DraftInvoice(uint(visitID), userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrVisitNotFound):
+			response.NotFound(c, "Visit not found")
+		case errors.Is(err, service.ErrVisitNotCompleted),
+			errors.Is(err, service.ErrVisitAlreadyInvoiced),
+			errors.Is(err, service.ErrVisitNothingToInvoice):
+			response.Conflict(c, err.Error())
+		default:
+			response.InternalServerError(c, "Failed to draft visit invoice")
+		}
+		return
+	}
+
+	response.Created(c, "Visit invoice drafted successfully", invoice)
+}
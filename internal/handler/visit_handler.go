@@ -37,6 +37,26 @@ func (h *VisitHandler) CreateVisit(c *gin.Context) {
 			response.NotFound(c, "Patient not found")
 			return
 		}
+		if errors.Is(err, service.ErrPatientDeceased) {
+			response.BadRequest(c, "Patient is deceased", nil)
+			return
+		}
+		if errors.Is(err, service.ErrCoverageNotFound) {
+			response.BadRequest(c, "Coverage not found for this patient", nil)
+			return
+		}
+		if errors.Is(err, service.ErrCoverageNotValid) {
+			response.BadRequest(c, "Insurance coverage is not valid on the visit date", nil)
+			return
+		}
+		if errors.Is(err, service.ErrInvalidStatusTransition) {
+			response.Conflict(c, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrImplausibleVitalSigns) {
+			response.BadRequest(c, err.Error(), nil)
+			return
+		}
 		response.InternalServerError(c, "Failed to create visit")
 		return
 	}
@@ -159,6 +179,18 @@ func (h *VisitHandler) UpdateVisit(c *gin.Context) {
 			response.NotFound(c, "Visit not found")
 			return
 		}
+		if errors.Is(err, service.ErrInvalidDateFormat) {
+			response.BadRequest(c, "Invalid date format, use YYYY-MM-DD", nil)
+			return
+		}
+		if errors.Is(err, service.ErrImplausibleVitalSigns) {
+			response.BadRequest(c, err.Error(), nil)
+			return
+		}
+		if errors.Is(err, service.ErrVisitDocumentationLocked) {
+			response.Conflict(c, err.Error())
+			return
+		}
 		response.InternalServerError(c, "Failed to update visit")
 		return
 	}
@@ -166,8 +198,8 @@ func (h *VisitHandler) UpdateVisit(c *gin.Context) {
 	response.Success(c, "Visit updated successfully", visit)
 }
 
-// CompleteVisit handles completing a visit
-func (h *VisitHandler) CompleteVisit(c *gin.Context) {
+// StartVisit handles starting or resuming a visit's consultation
+func (h *VisitHandler) StartVisit(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
 		response.BadRequest(c, "Invalid visit ID", nil)
@@ -176,12 +208,60 @@ func (h *VisitHandler) CompleteVisit(c *gin.Context) {
 
 	userID, _ := middleware.GetUserID(c)
 
-	if err := h.visitService.CompleteVisit(uint(id), userID); err != nil {
-		if errors.Is(err, service.ErrVisitNotFound) {
-			response.NotFound(c, "Visit not found")
+	visit, err := h.visitService.StartVisit(uint(id), userID)
+	if err != nil {
+		h.handleTransitionError(c, err, "Failed to start visit")
+		return
+	}
+
+	response.Success(c, "Visit started successfully", visit)
+}
+
+// PauseVisit handles putting a visit's consultation on hold
+func (h *VisitHandler) PauseVisit(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid visit ID", nil)
+		return
+	}
+
+	var req dto.PauseVisitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	visit, err := h.visitService.PauseVisit(uint(id), &req, userID)
+	if err != nil {
+		h.handleTransitionError(c, err, "Failed to pause visit")
+		return
+	}
+
+	response.Success(c, "Visit paused successfully", visit)
+}
+
+// CompleteVisit handles completing a visit
+func (h *VisitHandler) CompleteVisit(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid visit ID", nil)
+		return
+	}
+
+	var req dto.CompleteVisitRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.ValidationError(c, map[string]interface{}{"error": err.Error()})
 			return
 		}
-		response.InternalServerError(c, "Failed to complete visit")
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	if err := h.visitService.CompleteVisit(uint(id), &req, userID); err != nil {
+		h.handleTransitionError(c, err, "Failed to complete visit")
 		return
 	}
 
@@ -199,11 +279,7 @@ func (h *VisitHandler) CancelVisit(c *gin.Context) {
 	userID, _ := middleware.GetUserID(c)
 
 	if err := h.visitService.CancelVisit(uint(id), userID); err != nil {
-		if errors.Is(err, service.ErrVisitNotFound) {
-			response.NotFound(c, "Visit not found")
-			return
-		}
-		response.InternalServerError(c, "Failed to cancel visit")
+		h.handleTransitionError(c, err, "Failed to cancel visit")
 		return
 	}
 
@@ -264,3 +340,46 @@ func (h *VisitHandler) GetDoctorVisits(c *gin.Context) {
 
 	response.Success(c, "Doctor visits retrieved successfully", visits)
 }
+
+// GetWaitTimeAnalytics handles getting average visit wait and consultation times
+func (h *VisitHandler) GetWaitTimeAnalytics(c *gin.Context) {
+	filters := make(map[string]interface{})
+	if doctorID := c.Query("doctor_id"); doctorID != "" {
+		filters["doctor_id"] = doctorID
+	}
+	if departmentID := c.Query("department_id"); departmentID != "" {
+		filters["department_id"] = departmentID
+	}
+	if visitType := c.Query("visit_type"); visitType != "" {
+		filters["visit_type"] = visitType
+	}
+
+	analytics, err := h.visitService.GetWaitTimeAnalytics(c.Query("from_date"), c.Query("to_date"), c.DefaultQuery("group_by", "doctor"), filters)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidDateFormat):
+			response.BadRequest(c, "Invalid date format, use YYYY-MM-DD", nil)
+		case errors.Is(err, service.ErrInvalidDateRange),
+			errors.Is(err, service.ErrInvalidGrouping):
+			response.BadRequest(c, err.Error(), nil)
+		default:
+			response.InternalServerError(c, "Failed to get wait time analytics")
+		}
+		return
+	}
+
+	response.Success(c, "Wait time analytics retrieved successfully", analytics)
+}
+
+// handleTransitionError maps visit status change errors to HTTP responses
+func (h *VisitHandler) handleTransitionError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrVisitNotFound):
+		response.NotFound(c, "Visit not found")
+	case errors.Is(err, service.ErrInvalidStatusTransition),
+		errors.Is(err, service.ErrVisitCompletionBlocked):
+		response.Conflict(c, err.Error())
+	default:
+		response.InternalServerError(c, fallback)
+	}
+}
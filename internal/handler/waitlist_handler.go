@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/middleware"
+	"github.com/minhtran/his/internal/pkg/response"
+	"github.com/minhtran/his/internal/service"
+)
+
+// WaitlistHandler handles appointment waitlist HTTP requests
+type WaitlistHandler struct {
+	waitlistService *service.WaitlistService
+}
+
+// NewWaitlistHandler creates a new waitlist handler
+func NewWaitlistHandler(waitlistService *service.WaitlistService) *WaitlistHandler {
+	return &WaitlistHandler{waitlistService: waitlistService}
+}
+
+// CreateEntry handles putting a patient on the waitlist
+func (h *WaitlistHandler) CreateEntry(c *gin.Context) {
+	var req dto.CreateWaitlistEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	entry, err := h.waitlistService.CreateEntry(&req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to create waitlist entry")
+		return
+	}
+
+	response.Created(c, "Waitlist entry created successfully", entry)
+}
+
+// ListEntries handles listing waitlist entries with filters
+func (h *WaitlistHandler) ListEntries(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	filters := make(map[string]interface{})
+	if patientID := c.Query("patient_id"); patientID != "" {
+		filters["patient_id"] = patientID
+	}
+	if doctorID := c.Query("doctor_id"); doctorID != "" {
+		filters["doctor_id"] = doctorID
+	}
+	if departmentID := c.Query("department_id"); departmentID != "" {
+		filters["department_id"] = departmentID
+	}
+	if status := c.Query("status"); status != "" {
+		filters["status"] = status
+	}
+	if priority := c.Query("priority"); priority != "" {
+		filters["priority"] = priority
+	}
+
+	entries, total, err := h.waitlistService.ListEntries(filters, page, pageSize)
+	if err != nil {
+		response.InternalServerError(c, "Failed to list waitlist entries")
+		return
+	}
+
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
+		totalPages++
+	}
+
+	response.SuccessPaginated(c, "Waitlist entries retrieved successfully", entries, response.Pagination{
+		Page:       page,
+		PageSize:   pageSize,
+		TotalItems: total,
+		TotalPages: totalPages,
+	})
+}
+
+// GetEntry handles getting a waitlist entry with its offers
+func (h *WaitlistHandler) GetEntry(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid waitlist entry ID", nil)
+		return
+	}
+
+	entry, err := h.waitlistService.GetEntry(uint(id))
+	if err != nil {
+		h.handleError(c, err, "Failed to get waitlist entry")
+		return
+	}
+
+	response.Success(c, "Waitlist entry retrieved successfully", entry)
+}
+
+// CancelEntry handles taking a patient off the waitlist
+func (h *WaitlistHandler) CancelEntry(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid waitlist entry ID", nil)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	entry, err := h.waitlistService.CancelEntry(uint(id), userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to cancel waitlist entry")
+		return
+	}
+
+	response.Success(c, "Waitlist entry cancelled successfully", entry)
+}
+
+// AcceptOffer handles booking an offered slot
+func (h *WaitlistHandler) AcceptOffer(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid offer ID", nil)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	appointment, err := h.waitlistService.AcceptOffer(uint(id), userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to accept waitlist offer")
+		return
+	}
+
+	response.Created(c, "Waitlist offer accepted and appointment scheduled", appointment)
+}
+
+// DeclineOffer handles turning an offered slot down
+func (h *WaitlistHandler) DeclineOffer(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid offer ID", nil)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	entry, err := h.waitlistService.DeclineOffer(uint(id), userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to decline waitlist offer")
+		return
+	}
+
+	response.Success(c, "Waitlist offer declined successfully", entry)
+}
+
+// handleError maps waitlist service errors to HTTP responses
+func (h *WaitlistHandler) handleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrWaitlistEntryNotFound):
+		response.NotFound(c, "Waitlist entry not found")
+	case errors.Is(err, service.ErrWaitlistOfferNotFound):
+		response.NotFound(c, "Waitlist offer not found")
+	case errors.Is(err, service.ErrPatientNotFound):
+		response.NotFound(c, "Patient not found")
+	case errors.Is(err, service.ErrDoctorNotFound):
+		response.NotFound(c, "Doctor not found")
+	case errors.Is(err, service.ErrDepartmentNotFound):
+		response.NotFound(c, "Department not found")
+	case errors.Is(err, service.ErrPatientDeceased):
+		response.BadRequest(c, "Patient is deceased", nil)
+	case errors.Is(err, service.ErrPatientBookingRestricted):
+		response.Forbidden(c, err.Error())
+	case errors.Is(err, service.ErrWaitlistTargetRequired),
+		errors.Is(err, service.ErrDuplicateWaitlistEntry),
+		errors.Is(err, service.ErrInvalidPreferredTime),
+		errors.Is(err, service.ErrInvalidDateRange),
+		errors.Is(err, service.ErrWaitlistEntryClosed),
+		errors.Is(err, service.ErrWaitlistOfferNotPending),
+		errors.Is(err, service.ErrWaitlistOfferExpired),
+		errors.Is(err, service.ErrInvalidAppointmentTime),
+		errors.Is(err, service.ErrDoctorNotAvailable),
+		errors.Is(err, service.ErrClinicSessionFull),
+		errors.Is(err, service.ErrPastAppointmentDate):
+		response.BadRequest(c, err.Error(), nil)
+	case errors.Is(err, service.ErrTimeSlotNotAvailable):
+		response.BadRequest(c, "Time slot not available", nil)
+	case errors.Is(err, service.ErrInvalidDateFormat):
+		response.BadRequest(c, "Invalid date format, use YYYY-MM-DD", nil)
+	default:
+		response.InternalServerError(c, fallback)
+	}
+}
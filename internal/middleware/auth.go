@@ -50,6 +50,43 @@ func AuthMiddleware(jwtManager *jwt.Manager) gin.HandlerFunc {
 	}
 }
 
+// PortalAuthMiddleware creates a JWT authentication middleware for patient portal tokens
+func PortalAuthMiddleware(jwtManager *jwt.Manager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" {
+			response.Unauthorized(c, "Authorization header is required")
+			c.Abort()
+			return
+		}
+
+		parts := strings.SplitN(authHeader, " ", 2)
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			response.Unauthorized(c, "Invalid authorization header format")
+			c.Abort()
+			return
+		}
+
+		// Validate token (staff tokens are rejected by audience)
+		claims, err := jwtManager.ValidatePortalToken(parts[1])
+		if err != nil {
+			if err == jwt.ErrExpiredToken {
+				response.Unauthorized(c, "Token has expired")
+			} else {
+				response.Unauthorized(c, "Invalid token")
+			}
+			c.Abort()
+			return
+		}
+
+		// Set portal account info in context
+		c.Set("portal_account_id", claims.AccountID)
+		c.Set("portal_patient_id", claims.PatientID)
+
+		c.Next()
+	}
+}
+
 // GetUserID retrieves user ID from context
 func GetUserID(c *gin.Context) (uint, bool) {
 	userID, exists := c.Get("user_id")
@@ -69,3 +106,13 @@ func GetUsername(c *gin.Context) (string, bool) {
 	name, ok := username.(string)
 	return name, ok
 }
+
+// GetPortalAccountID retrieves the patient portal account ID from context
+func GetPortalAccountID(c *gin.Context) (uint, bool) {
+	accountID, exists := c.Get("portal_account_id")
+	if !exists {
+		return 0, false
+	}
+	id, ok := accountID.(uint)
+	return id, ok
+}
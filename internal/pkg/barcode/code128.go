@@ -0,0 +1,76 @@
+// Package barcode generates Code 128 and QR Code symbols as module matrices
+// that label renderers can draw at any resolution.
+package barcode
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrInvalidCode128 is returned when data contains characters outside Code 128 set B
+var ErrInvalidCode128 = errors.New("code128: data must be printable ASCII")
+
+// code128Patterns holds bar/space widths for symbol values 0-106 (106 is the stop pattern)
+var code128Patterns = [...]string{
+	"212222", "222122", "222221", "121223", "121322", "131222", "122213", "122312", "132212", "221213",
+	"221312", "231212", "112232", "122132", "122231", "113222", "123122", "123221", "223211", "221132",
+	"221231", "213212", "223112", "312131", "311222", "321122", "321221", "312212", "322112", "322211",
+	"212123", "212321", "232121", "111323", "131123", "131321", "112313", "132113", "132311", "211313",
+	"231113", "231311", "112133", "112331", "132131", "113123", "113321", "133121", "313121", "211331",
+	"231131", "213113", "213311", "213131", "311123", "311321", "331121", "312113", "312311", "332111",
+	"314111", "221411", "431111", "111224", "111422", "121124", "121421", "141122", "141221", "112214",
+	"112412", "122114", "122411", "142112", "142211", "241211", "221114", "413111", "241112", "134111",
+	"111242", "121142", "121241", "114212", "124112", "124211", "411212", "421112", "421211", "212141",
+	"214121", "412121", "111143", "111341", "131141", "114113", "114311", "411113", "411311", "113141",
+	"114131", "311141", "411131", "211412", "211214", "211232", "2331112",
+}
+
+const (
+	code128StartB = 104
+	code128StartC = 105
+	code128Stop   = 106
+)
+
+// Code128 encodes data as a Code 128 symbol and returns its modules
+// (true = bar), excluding quiet zones. All-digit data of even length uses
+// the denser code set C; everything else uses code set B.
+func Code128(data string) ([]bool, error) {
+	if data == "" {
+		return nil, ErrInvalidCode128
+	}
+
+	var values []int
+	if len(data)%2 == 0 && strings.Trim(data, "0123456789") == "" {
+		values = append(values, code128StartC)
+		for i := 0; i < len(data); i += 2 {
+			values = append(values, int(data[i]-'0')*10+int(data[i+1]-'0'))
+		}
+	} else {
+		values = append(values, code128StartB)
+		for i := 0; i < len(data); i++ {
+			ch := data[i]
+			if ch < 32 || ch > 126 {
+				return nil, ErrInvalidCode128
+			}
+			values = append(values, int(ch)-32)
+		}
+	}
+
+	checksum := values[0]
+	for i := 1; i < len(values); i++ {
+		checksum += values[i] * i
+	}
+	values = append(values, checksum%103, code128Stop)
+
+	var modules []bool
+	for _, v := range values {
+		bar := true
+		for _, w := range code128Patterns[v] {
+			for n := 0; n < int(w-'0'); n++ {
+				modules = append(modules, bar)
+			}
+			bar = !bar
+		}
+	}
+	return modules, nil
+}
@@ -0,0 +1,60 @@
+// Package broadcast fans events out to in-process subscribers, such as the
+// Server-Sent Events streams of waiting-room displays. Subscribers that fall
+// behind miss events rather than block publishers, so events should carry the
+// full state a subscriber needs rather than a change to apply.
+package broadcast
+
+import "sync"
+
+// subscriberBuffer is how many events a subscriber may fall behind by
+const subscriberBuffer = 16
+
+// Hub delivers events published on a topic to the topic's subscribers
+type Hub struct {
+	mu     sync.Mutex
+	topics map[string]map[chan interface{}]struct{}
+}
+
+// NewHub creates a new hub
+func NewHub() *Hub {
+	return &Hub{topics: make(map[string]map[chan interface{}]struct{})}
+}
+
+// Subscribe subscribes to a topic. The returned function unsubscribes and
+// closes the channel; it must be called once the subscriber is done.
+func (h *Hub) Subscribe(topic string) (<-chan interface{}, func()) {
+	ch := make(chan interface{}, subscriberBuffer)
+
+	h.mu.Lock()
+	if h.topics[topic] == nil {
+		h.topics[topic] = make(map[chan interface{}]struct{})
+	}
+	h.topics[topic][ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.topics[topic], ch)
+			if len(h.topics[topic]) == 0 {
+				delete(h.topics, topic)
+			}
+			h.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// Publish delivers an event to the topic's subscribers, dropping it for
+// subscribers whose buffer is full
+func (h *Hub) Publish(topic string, event interface{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.topics[topic] {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
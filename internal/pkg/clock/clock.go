@@ -0,0 +1,102 @@
+// Package clock tells the time in the facility's time zone. Services take a
+// Clock rather than calling time.Now so that "today" follows the clinic's
+// calendar instead of UTC or the host's zone, and so that time can be fixed.
+//
+// Calendar dates (appointment dates, dates of birth, YYYY-MM-DD parameters) are
+// represented as midnight UTC of that date, the value time.Parse("2006-01-02")
+// gives; use Day to bring a date loaded from the database into that form.
+package clock
+
+import "time"
+
+// Clock reads the current time and converts between instants and the
+// facility's calendar days
+type Clock struct {
+	loc *time.Location
+	now func() time.Time
+}
+
+// New creates a clock reading the system time in loc
+func New(loc *time.Location) *Clock {
+	if loc == nil {
+		loc = time.Local
+	}
+	return &Clock{loc: loc, now: time.Now}
+}
+
+// Fixed creates a clock stopped at t, for replays and tests
+func Fixed(loc *time.Location, t time.Time) *Clock {
+	c := New(loc)
+	c.now = func() time.Time { return t }
+	return c
+}
+
+// Now returns the current instant in the facility's time zone
+func (c *Clock) Now() time.Time {
+	return c.now().In(c.loc)
+}
+
+// Location returns the facility's time zone
+func (c *Clock) Location() *time.Location {
+	return c.loc
+}
+
+// Today returns the facility's current calendar date
+func (c *Clock) Today() time.Time {
+	return c.DayOf(c.now())
+}
+
+// DayOf returns the facility's calendar date at instant t
+func (c *Clock) DayOf(t time.Time) time.Time {
+	return Day(t.In(c.loc))
+}
+
+// StartOfDay returns the instant the facility's calendar day date begins,
+// which is not always local midnight on days with a DST change
+func (c *Clock) StartOfDay(date time.Time) time.Time {
+	t := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, c.loc)
+	if t.Day() != date.Day() {
+		// Midnight was skipped when clocks went forward; the day begins at the change
+		_, offset := t.Zone()
+		midnight := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+		t = midnight.Add(-time.Duration(offset) * time.Second).In(c.loc)
+	}
+	return t
+}
+
+// StartOfMonth returns the instant the facility's current month began
+func (c *Clock) StartOfMonth() time.Time {
+	now := c.Now()
+	return c.StartOfDay(time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC))
+}
+
+// At returns the instant of wall-clock time clock on calendar date date in
+// the facility's time zone. A time skipped when clocks go forward is moved
+// forward by the gap, e.g. 02:30 becomes 03:30.
+func (c *Clock) At(date, clock time.Time) time.Time {
+	t := time.Date(date.Year(), date.Month(), date.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, c.loc)
+	if t.Hour() != clock.Hour() || t.Minute() != clock.Minute() {
+		// Read the wall time with the offset in force before the change
+		_, offset := t.Add(-24 * time.Hour).Zone()
+		wall := time.Date(date.Year(), date.Month(), date.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, time.UTC)
+		t = wall.Add(-time.Duration(offset) * time.Second).In(c.loc)
+	}
+	return t
+}
+
+// Day returns date as a calendar date, keeping its year, month and day
+// whatever zone it is in
+func Day(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Age returns the age in whole years on calendar date on of someone born on
+// calendar date dob. Someone born on 29 February turns a year older on
+// 1 March in common years.
+func Age(dob, on time.Time) int {
+	age := on.Year() - dob.Year()
+	if on.Month() < dob.Month() || (on.Month() == dob.Month() && on.Day() < dob.Day()) {
+		age--
+	}
+	return age
+}
@@ -0,0 +1,125 @@
+package clock
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("failed to load %s: %v", name, err)
+	}
+	return loc
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestToday(t *testing.T) {
+	newYork := mustLoad(t, "America/New_York")
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{"before midnight ahead of spring forward", time.Date(2026, 3, 8, 4, 59, 59, 0, time.UTC), date(2026, 3, 7)},
+		{"midnight ahead of spring forward", time.Date(2026, 3, 8, 5, 0, 0, 0, time.UTC), date(2026, 3, 8)},
+		{"before midnight after spring forward", time.Date(2026, 3, 9, 3, 59, 59, 0, time.UTC), date(2026, 3, 8)},
+		{"midnight after spring forward", time.Date(2026, 3, 9, 4, 0, 0, 0, time.UTC), date(2026, 3, 9)},
+		{"before midnight ahead of fall back", time.Date(2026, 11, 1, 3, 59, 59, 0, time.UTC), date(2026, 10, 31)},
+		{"before midnight after fall back", time.Date(2026, 11, 2, 4, 59, 59, 0, time.UTC), date(2026, 11, 1)},
+		{"midnight after fall back", time.Date(2026, 11, 2, 5, 0, 0, 0, time.UTC), date(2026, 11, 2)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Fixed(newYork, tt.now).Today(); !got.Equal(tt.want) {
+				t.Errorf("Today() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDay(t *testing.T) {
+	newYork := mustLoad(t, "America/New_York")
+	tokyo := mustLoad(t, "Asia/Tokyo")
+
+	tests := []struct {
+		name string
+		in   time.Time
+		want time.Time
+	}{
+		{"utc midnight", date(2026, 3, 8), date(2026, 3, 8)},
+		{"late evening west of utc", time.Date(2026, 3, 8, 23, 30, 0, 0, newYork), date(2026, 3, 8)},
+		{"early morning east of utc", time.Date(2026, 3, 8, 0, 30, 0, 0, tokyo), date(2026, 3, 8)},
+		{"skipped hour", time.Date(2026, 3, 8, 2, 30, 0, 0, newYork), date(2026, 3, 8)},
+		{"repeated hour", time.Date(2026, 11, 1, 1, 30, 0, 0, newYork), date(2026, 11, 1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Day(tt.in)
+			if !got.Equal(tt.want) || got.Location() != time.UTC {
+				t.Errorf("Day(%v) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAge(t *testing.T) {
+	newYork := mustLoad(t, "America/New_York")
+
+	tests := []struct {
+		name string
+		dob  time.Time
+		on   time.Time
+		want int
+	}{
+		{"day before birthday", date(2000, 3, 8), date(2026, 3, 7), 25},
+		{"on birthday", date(2000, 3, 8), date(2026, 3, 8), 26},
+		{"after birthday", date(2000, 3, 8), date(2026, 12, 31), 26},
+		{"month before birthday month", date(2000, 12, 1), date(2026, 11, 30), 25},
+		{"born today", date(2026, 3, 8), date(2026, 3, 8), 0},
+		{"leap day in common year before march", date(2000, 2, 29), date(2026, 2, 28), 25},
+		{"leap day in common year on march first", date(2000, 2, 29), date(2026, 3, 1), 26},
+		{"leap day in leap year", date(2000, 2, 29), date(2028, 2, 29), 28},
+		{"late in a leap year", date(2000, 3, 1), date(2028, 2, 29), 27},
+		{"facility day before birthday at utc midnight", date(2000, 3, 8), Fixed(newYork, time.Date(2026, 3, 8, 4, 59, 59, 0, time.UTC)).Today(), 25},
+		{"facility midnight on birthday", date(2000, 3, 8), Fixed(newYork, time.Date(2026, 3, 8, 5, 0, 0, 0, time.UTC)).Today(), 26},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Age(tt.dob, tt.on); got != tt.want {
+				t.Errorf("Age(%v, %v) = %d, want %d", tt.dob, tt.on, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStartOfDay(t *testing.T) {
+	newYork := mustLoad(t, "America/New_York")
+	saoPaulo := mustLoad(t, "America/Sao_Paulo")
+
+	tests := []struct {
+		name string
+		loc  *time.Location
+		date time.Time
+		want time.Time
+	}{
+		{"standard time", newYork, date(2026, 1, 15), time.Date(2026, 1, 15, 5, 0, 0, 0, time.UTC)},
+		{"spring forward after midnight", newYork, date(2026, 3, 8), time.Date(2026, 3, 8, 5, 0, 0, 0, time.UTC)},
+		{"day after spring forward", newYork, date(2026, 3, 9), time.Date(2026, 3, 9, 4, 0, 0, 0, time.UTC)},
+		{"fall back after midnight", newYork, date(2026, 11, 1), time.Date(2026, 11, 1, 4, 0, 0, 0, time.UTC)},
+		{"spring forward skips midnight", saoPaulo, date(2018, 11, 4), time.Date(2018, 11, 4, 3, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Fixed(tt.loc, tt.date).StartOfDay(tt.date); !got.Equal(tt.want) {
+				t.Errorf("StartOfDay(%v) = %v, want %v", tt.date, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package document
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	"time"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+var templates = template.Must(
+	template.New("").Funcs(template.FuncMap{
+		"date":     formatDate,
+		"datetime": formatDateTime,
+	}).ParseFS(templateFS, "templates/*.html"),
+)
+
+// Render renders a printable HTML document from an embedded template
+func Render(name string, data interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := templates.ExecuteTemplate(&buf, name+".html", data); err != nil {
+		return nil, fmt.Errorf("failed to render document %s: %w", name, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// formatDate formats a date as DD/MM/YYYY
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("02/01/2006")
+}
+
+// formatDateTime formats a timestamp as HH:MM DD/MM/YYYY
+func formatDateTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("15:04 02/01/2006")
+}
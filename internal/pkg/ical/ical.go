@@ -0,0 +1,269 @@
+// Package ical writes iCalendar (RFC 5545) feeds: a VCALENDAR holding a
+// VTIMEZONE for the calendar's location and VEVENTs whose start and end are
+// given as local times in that zone.
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Status is the status of an event
+type Status string
+
+const (
+	StatusTentative Status = "TENTATIVE"
+	StatusConfirmed Status = "CONFIRMED"
+	StatusCancelled Status = "CANCELLED"
+)
+
+const (
+	localFormat = "20060102T150405"
+	utcFormat   = "20060102T150405Z"
+
+	// maxLineOctets is the longest content line before it must be folded
+	maxLineOctets = 75
+)
+
+// Event is a calendar event
+type Event struct {
+	UID          string // Globally unique and stable across feed refreshes
+	Sequence     int    // Increases whenever the event changes
+	Start        time.Time
+	End          time.Time
+	Summary      string
+	Description  string
+	Location     string
+	Status       Status
+	LastModified time.Time
+}
+
+// Calendar is a feed of events in one time zone
+type Calendar struct {
+	ProdID   string // e.g. "-//Facility//HIS//EN"
+	Name     string // Display name suggested to calendar apps
+	Location *time.Location
+	Events   []Event
+}
+
+// Encode writes the calendar to w with CRLF line endings and long lines folded
+func (c *Calendar) Encode(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	e := &encoder{w: bw}
+
+	loc := c.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	e.line("BEGIN", "VCALENDAR")
+	e.line("VERSION", "2.0")
+	e.line("PRODID", c.ProdID)
+	e.line("CALSCALE", "GREGORIAN")
+	e.line("METHOD", "PUBLISH")
+	if c.Name != "" {
+		e.line("X-WR-CALNAME", escapeText(c.Name))
+	}
+	if loc != time.UTC {
+		e.line("X-WR-TIMEZONE", loc.String())
+		e.timezone(loc, time.Now().In(loc).Year())
+	}
+
+	stamp := time.Now().UTC().Format(utcFormat)
+	for _, ev := range c.Events {
+		e.line("BEGIN", "VEVENT")
+		e.line("UID", ev.UID)
+		e.line("DTSTAMP", stamp)
+		e.line("SEQUENCE", fmt.Sprintf("%d", ev.Sequence))
+		e.dateTime("DTSTART", ev.Start, loc)
+		e.dateTime("DTEND", ev.End, loc)
+		e.line("SUMMARY", escapeText(ev.Summary))
+		if ev.Description != "" {
+			e.line("DESCRIPTION", escapeText(ev.Description))
+		}
+		if ev.Location != "" {
+			e.line("LOCATION", escapeText(ev.Location))
+		}
+		if ev.Status != "" {
+			e.line("STATUS", string(ev.Status))
+		}
+		if !ev.LastModified.IsZero() {
+			e.line("LAST-MODIFIED", ev.LastModified.UTC().Format(utcFormat))
+		}
+		e.line("TRANSP", "OPAQUE")
+		e.line("END", "VEVENT")
+	}
+
+	e.line("END", "VCALENDAR")
+	if e.err != nil {
+		return e.err
+	}
+	return bw.Flush()
+}
+
+// encoder writes folded content lines, keeping the first write error
+type encoder struct {
+	w   *bufio.Writer
+	err error
+}
+
+// line writes "NAME:value", folding it into 75-octet lines without splitting
+// a UTF-8 character
+func (e *encoder) line(name, value string) {
+	if e.err != nil {
+		return
+	}
+	s := name + ":" + value
+
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		if _, e.err = e.w.WriteString(s[:cut] + "\r\n "); e.err != nil {
+			return
+		}
+		s = s[cut:]
+		limit = maxLineOctets - 1 // Continuation lines start with a space
+	}
+	_, e.err = e.w.WriteString(s + "\r\n")
+}
+
+// dateTime writes a date-time property as local time in loc, or in UTC when
+// the calendar has no time zone
+func (e *encoder) dateTime(name string, t time.Time, loc *time.Location) {
+	if loc == time.UTC {
+		e.line(name, t.UTC().Format(utcFormat))
+		return
+	}
+	e.line(name+";TZID="+loc.String(), t.In(loc).Format(localFormat))
+}
+
+// timezone writes a VTIMEZONE describing loc using the offset changes found
+// in the given year. Yearly transitions are expressed as BYDAY rules, which
+// covers zones whose daylight saving time starts and ends on the nth or last
+// weekday of a month; a zone without transitions gets a single STANDARD part.
+func (e *encoder) timezone(loc *time.Location, year int) {
+	e.line("BEGIN", "VTIMEZONE")
+	e.line("TZID", loc.String())
+
+	transitions := findTransitions(loc, year)
+	if len(transitions) == 0 {
+		name, offset := time.Date(year, time.January, 1, 0, 0, 0, 0, loc).Zone()
+		e.line("BEGIN", "STANDARD")
+		e.line("DTSTART", "19700101T000000")
+		e.line("TZOFFSETFROM", formatOffset(offset))
+		e.line("TZOFFSETTO", formatOffset(offset))
+		e.line("TZNAME", name)
+		e.line("END", "STANDARD")
+	}
+
+	for _, t := range transitions {
+		_, before := t.Add(-time.Second).Zone()
+		name, after := t.Zone()
+
+		component := "STANDARD"
+		if t.IsDST() {
+			component = "DAYLIGHT"
+		}
+
+		// DTSTART is the wall-clock time of the change under the old offset,
+		// moved back to its first occurrence in 1970 so the rule covers any event
+		onset := t.In(time.FixedZone("", before))
+		month, n, weekday := yearlyRule(onset)
+		first := nthWeekday(1970, month, n, weekday)
+		e.line("BEGIN", component)
+		e.line("DTSTART", time.Date(1970, month, first, onset.Hour(), onset.Minute(), onset.Second(), 0, time.UTC).Format(localFormat))
+		e.line("RRULE", fmt.Sprintf("FREQ=YEARLY;BYMONTH=%d;BYDAY=%d%s", int(month), n, weekdayCode(weekday)))
+		e.line("TZOFFSETFROM", formatOffset(before))
+		e.line("TZOFFSETTO", formatOffset(after))
+		e.line("TZNAME", name)
+		e.line("END", component)
+	}
+
+	e.line("END", "VTIMEZONE")
+}
+
+// findTransitions returns the instants in the year at which loc's UTC offset changes
+func findTransitions(loc *time.Location, year int) []time.Time {
+	var transitions []time.Time
+
+	day := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := day.AddDate(1, 0, 0)
+	_, prev := day.In(loc).Zone()
+	for day.Before(end) {
+		next := day.Add(24 * time.Hour)
+		if _, offset := next.In(loc).Zone(); offset != prev {
+			// Narrow the change down to the second
+			lo, hi := day, next
+			for hi.Sub(lo) > time.Second {
+				mid := lo.Add(hi.Sub(lo) / 2)
+				if _, o := mid.In(loc).Zone(); o == prev {
+					lo = mid
+				} else {
+					hi = mid
+				}
+			}
+			transitions = append(transitions, hi.In(loc))
+			prev = offset
+		}
+		day = next
+	}
+	return transitions
+}
+
+// yearlyRule describes a date as the nth weekday of its month, with n = -1
+// when the date falls in the final week of the month
+func yearlyRule(t time.Time) (time.Month, int, time.Weekday) {
+	n := (t.Day()-1)/7 + 1
+	if t.Day()+7 > daysInMonth(t.Year(), t.Month()) {
+		n = -1
+	}
+	return t.Month(), n, t.Weekday()
+}
+
+// nthWeekday returns the day of the month of the nth (or, for -1, last) weekday
+func nthWeekday(year int, month time.Month, n int, weekday time.Weekday) int {
+	if n < 0 {
+		last := daysInMonth(year, month)
+		lastWeekday := time.Date(year, month, last, 0, 0, 0, 0, time.UTC).Weekday()
+		return last - (int(lastWeekday)-int(weekday)+7)%7
+	}
+	firstWeekday := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC).Weekday()
+	return 1 + (int(weekday)-int(firstWeekday)+7)%7 + (n-1)*7
+}
+
+func daysInMonth(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// weekdayCode returns the two-letter iCalendar code of a weekday
+func weekdayCode(weekday time.Weekday) string {
+	return strings.ToUpper(weekday.String()[:2])
+}
+
+// formatOffset formats a UTC offset in seconds as +HHMM
+func formatOffset(seconds int) string {
+	sign := '+'
+	if seconds < 0 {
+		sign = '-'
+		seconds = -seconds
+	}
+	return fmt.Sprintf("%c%02d%02d", sign, seconds/3600, seconds%3600/60)
+}
+
+// escapeText escapes a TEXT value
+func escapeText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
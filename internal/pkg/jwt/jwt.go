@@ -13,6 +13,12 @@ var (
 	ErrExpiredToken = errors.New("token has expired")
 )
 
+// Token audiences separate staff tokens from patient portal tokens
+const (
+	AudienceStaff  = "his-staff"
+	AudiencePortal = "his-portal"
+)
+
 // Claims represents JWT claims
 type Claims struct {
 	UserID   uint   `json:"user_id"`
@@ -21,6 +27,13 @@ type Claims struct {
 	jwt.RegisteredClaims
 }
 
+// PortalClaims represents JWT claims for a patient portal account
+type PortalClaims struct {
+	AccountID uint `json:"account_id"`
+	PatientID uint `json:"patient_id"`
+	jwt.RegisteredClaims
+}
+
 // TokenPair represents access and refresh tokens
 type TokenPair struct {
 	AccessToken  string `json:"access_token"`
@@ -71,6 +84,7 @@ func (m *Manager) generateToken(userID uint, username, email string, duration ti
 		Username: username,
 		Email:    email,
 		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{AudienceStaff},
 			ExpiresAt: jwt.NewNumericDate(now.Add(duration)),
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now),
@@ -86,7 +100,7 @@ func (m *Manager) generateToken(userID uint, username, email string, duration ti
 	return tokenString, nil
 }
 
-// ValidateToken validates and parses a JWT token
+// ValidateToken validates and parses a staff JWT token. Portal tokens are rejected.
 func (m *Manager) ValidateToken(tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
@@ -107,6 +121,13 @@ func (m *Manager) ValidateToken(tokenString string) (*Claims, error) {
 		return nil, ErrInvalidToken
 	}
 
+	// Tokens issued before audiences were introduced carry none; accept them as staff
+	for _, aud := range claims.Audience {
+		if aud != AudienceStaff {
+			return nil, ErrInvalidToken
+		}
+	}
+
 	return claims, nil
 }
 
@@ -119,3 +140,79 @@ func (m *Manager) RefreshAccessToken(refreshToken string) (string, error) {
 
 	return m.generateToken(claims.UserID, claims.Username, claims.Email, m.accessTokenDuration)
 }
+
+// GeneratePortalTokenPair generates access and refresh tokens for a patient portal account
+func (m *Manager) GeneratePortalTokenPair(accountID, patientID uint) (*TokenPair, error) {
+	accessToken, err := m.generatePortalToken(accountID, patientID, m.accessTokenDuration)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate access token: %w", err)
+	}
+
+	refreshToken, err := m.generatePortalToken(accountID, patientID, m.refreshTokenDuration)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(m.accessTokenDuration.Seconds()),
+	}, nil
+}
+
+// generatePortalToken generates a JWT token with the portal audience
+func (m *Manager) generatePortalToken(accountID, patientID uint, duration time.Duration) (string, error) {
+	now := time.Now()
+	claims := &PortalClaims{
+		AccountID: accountID,
+		PatientID: patientID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{AudiencePortal},
+			ExpiresAt: jwt.NewNumericDate(now.Add(duration)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	tokenString, err := token.SignedString(m.secretKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+
+	return tokenString, nil
+}
+
+// ValidatePortalToken validates and parses a patient portal JWT token
+func (m *Manager) ValidatePortalToken(tokenString string) (*PortalClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &PortalClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return m.secretKey, nil
+	}, jwt.WithAudience(AudiencePortal))
+
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrExpiredToken
+		}
+		return nil, ErrInvalidToken
+	}
+
+	claims, ok := token.Claims.(*PortalClaims)
+	if !ok || !token.Valid || claims.AccountID == 0 {
+		return nil, ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
+// RefreshPortalAccessToken generates a new portal access token from a valid portal refresh token
+func (m *Manager) RefreshPortalAccessToken(refreshToken string) (string, error) {
+	claims, err := m.ValidatePortalToken(refreshToken)
+	if err != nil {
+		return "", err
+	}
+
+	return m.generatePortalToken(claims.AccountID, claims.PatientID, m.accessTokenDuration)
+}
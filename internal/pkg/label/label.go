@@ -0,0 +1,226 @@
+// Package label lays out printable labels (wristbands, chart and specimen
+// labels) and renders them to PDF or ZPL for Zebra printers.
+package label
+
+import (
+	"errors"
+	"math"
+	"strings"
+
+	"github.com/minhtran/his/internal/pkg/barcode"
+)
+
+// BarcodeType selects the symbology printed on a label
+type BarcodeType string
+
+const (
+	BarcodeCode128 BarcodeType = "CODE128"
+	BarcodeQR      BarcodeType = "QR"
+	BarcodeNone    BarcodeType = "NONE"
+)
+
+// Spec describes the physical label and its barcode
+type Spec struct {
+	WidthMM    float64
+	HeightMM   float64
+	Barcode    BarcodeType
+	FontSizePt float64 // 0 = fit to the label height
+}
+
+// Content is the data printed on one label
+type Content struct {
+	Lines       []string // First line is printed bold
+	Flag        string   // Printed inverted (e.g. allergy warning); empty for none
+	BarcodeData string
+}
+
+// ErrInvalidSpec is returned for labels too small to lay out
+var ErrInvalidSpec = errors.New("label: width and height must be at least 10 mm")
+
+type elementKind int
+
+const (
+	kindText elementKind = iota
+	kindCode128
+	kindQR
+)
+
+// element is a positioned item in millimetres, origin at the top-left corner
+type element struct {
+	kind    elementKind
+	x, y    float64
+	w, h    float64
+	text    string
+	sizePt  float64
+	bold    bool
+	inverse bool
+	data    string
+	modules []bool      // Code 128
+	qr      *barcode.QR // QR Code
+	module  float64     // Module width in mm
+}
+
+const (
+	ptToMM          = 25.4 / 72
+	lineSpacing     = 1.2
+	maxModuleMM     = 0.5
+	defaultFontPt   = 10
+	minFontPt       = 5
+	stripAspectRate = 3
+)
+
+// layout positions the barcode and text of a label within the spec
+func layout(spec Spec, content Content) ([]element, error) {
+	if spec.WidthMM < 10 || spec.HeightMM < 10 {
+		return nil, ErrInvalidSpec
+	}
+
+	margin := math.Min(2, spec.HeightMM*0.08)
+	innerW := spec.WidthMM - 2*margin
+	innerH := spec.HeightMM - 2*margin
+	strip := spec.WidthMM >= stripAspectRate*spec.HeightMM
+
+	var elements []element
+	textX, textY, textW, textH := margin, margin, innerW, innerH
+
+	if content.BarcodeData != "" {
+		switch spec.Barcode {
+		case BarcodeQR:
+			qr, err := barcode.QRCode(content.BarcodeData, barcode.QRLevelM)
+			if err != nil {
+				return nil, err
+			}
+			side := math.Min(innerH, innerW*0.4)
+			if !strip {
+				side = math.Min(innerH*0.6, innerW*0.45)
+			}
+			module := side / float64(qr.Size)
+			side = module * float64(qr.Size)
+			elements = append(elements, element{kind: kindQR, x: margin, y: margin + (innerH-side)/2, w: side, h: side, qr: qr, module: module, data: content.BarcodeData})
+			textX = margin*2 + side
+			textW = spec.WidthMM - textX - margin
+
+		case BarcodeCode128:
+			modules, err := barcode.Code128(content.BarcodeData)
+			if err != nil {
+				return nil, err
+			}
+			quiet := 10.0 // modules on each side
+			if strip {
+				maxW := innerW * 0.45
+				module := math.Min(maxModuleMM, maxW/(float64(len(modules))+2*quiet))
+				w := module * float64(len(modules))
+				elements = append(elements, element{kind: kindCode128, x: margin + quiet*module, y: margin, w: w, h: innerH, modules: modules, module: module, data: content.BarcodeData})
+				textX = margin + w + 2*quiet*module
+				textW = spec.WidthMM - textX - margin
+			} else {
+				h := innerH * 0.35
+				module := math.Min(maxModuleMM, innerW/(float64(len(modules))+2*quiet))
+				w := module * float64(len(modules))
+				elements = append(elements, element{kind: kindCode128, x: (spec.WidthMM - w) / 2, y: spec.HeightMM - margin - h, w: w, h: h, modules: modules, module: module, data: content.BarcodeData})
+				textH = innerH - h - margin
+			}
+		}
+	}
+
+	lines := make([]string, 0, len(content.Lines)+1)
+	for _, l := range content.Lines {
+		if l = strings.TrimSpace(Transliterate(l)); l != "" {
+			lines = append(lines, l)
+		}
+	}
+	flag := strings.TrimSpace(Transliterate(content.Flag))
+	count := len(lines)
+	if flag != "" {
+		count++
+	}
+	if count == 0 || textW <= 0 || textH <= 0 {
+		return elements, nil
+	}
+
+	fontPt := spec.FontSizePt
+	if fontPt <= 0 {
+		// Shrink the default size until the longest line fits the width
+		fontPt = defaultFontPt
+		for _, l := range append(lines, flag) {
+			for fontPt > minFontPt && textWidthMM(l, fontPt, true) > textW {
+				fontPt -= 0.5
+			}
+		}
+	}
+	lineH := math.Min(textH/float64(count), fontPt*ptToMM*lineSpacing)
+	sizePt := lineH / lineSpacing / ptToMM
+
+	y := textY
+	for i, l := range lines {
+		elements = append(elements, element{kind: kindText, x: textX, y: y, w: textW, h: lineH, text: fitText(l, textW, sizePt, i == 0), sizePt: sizePt, bold: i == 0})
+		y += lineH
+	}
+	if flag != "" {
+		text := fitText(flag, textW, sizePt, true)
+		w := math.Min(textW, textWidthMM(text, sizePt, true)+lineH*0.4)
+		elements = append(elements, element{kind: kindText, x: textX, y: y, w: w, h: lineH, text: text, sizePt: sizePt, bold: true, inverse: true})
+	}
+
+	return elements, nil
+}
+
+// textWidthMM approximates the rendered width of Helvetica text
+func textWidthMM(text string, sizePt float64, bold bool) float64 {
+	avg := 0.55
+	if bold {
+		avg = 0.6
+	}
+	return float64(len(text)) * avg * sizePt * ptToMM
+}
+
+// fitText truncates text so it fits the available width
+func fitText(text string, widthMM, sizePt float64, bold bool) string {
+	if textWidthMM(text, sizePt, bold) <= widthMM {
+		return text
+	}
+	for n := len(text) - 1; n > 0; n-- {
+		candidate := strings.TrimSpace(text[:n]) + ".."
+		if textWidthMM(candidate, sizePt, bold) <= widthMM {
+			return candidate
+		}
+	}
+	return ""
+}
+
+var vietnameseFolds = map[rune]string{}
+
+func init() {
+	groups := map[string]string{
+		"a": "àáạảãâầấậẩẫăằắặẳẵ", "A": "ÀÁẠẢÃÂẦẤẬẨẪĂẰẮẶẲẴ",
+		"e": "èéẹẻẽêềếệểễ", "E": "ÈÉẸẺẼÊỀẾỆỂỄ",
+		"i": "ìíịỉĩ", "I": "ÌÍỊỈĨ",
+		"o": "òóọỏõôồốộổỗơờớợởỡ", "O": "ÒÓỌỎÕÔỒỐỘỔỖƠỜỚỢỞỠ",
+		"u": "ùúụủũưừứựửữ", "U": "ÙÚỤỦŨƯỪỨỰỬỮ",
+		"y": "ỳýỵỷỹ", "Y": "ỲÝỴỶỸ",
+		"d": "đ", "D": "Đ",
+	}
+	for base, chars := range groups {
+		for _, r := range chars {
+			vietnameseFolds[r] = base
+		}
+	}
+}
+
+// Transliterate folds Vietnamese diacritics to ASCII and replaces other
+// non-ASCII characters, since label printers' resident fonts and the PDF
+// standard fonts cannot render them.
+func Transliterate(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		switch {
+		case r < 128:
+			sb.WriteRune(r)
+		case vietnameseFolds[r] != "":
+			sb.WriteString(vietnameseFolds[r])
+		default:
+			sb.WriteByte('?')
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,137 @@
+package label
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+const mmToPt = 72 / 25.4
+
+// RenderPDF renders one page per label copy as a PDF document
+func RenderPDF(spec Spec, contents []Content, copies int) ([]byte, error) {
+	if copies < 1 {
+		copies = 1
+	}
+
+	var pages []string
+	for _, content := range contents {
+		elements, err := layout(spec, content)
+		if err != nil {
+			return nil, err
+		}
+		stream := pdfPageStream(spec, elements)
+		for i := 0; i < copies; i++ {
+			pages = append(pages, stream)
+		}
+	}
+
+	return buildPDF(spec, pages), nil
+}
+
+// pdfPageStream draws the elements of a label; PDF coordinates start bottom-left
+func pdfPageStream(spec Spec, elements []element) string {
+	pageH := spec.HeightMM
+	var sb strings.Builder
+
+	rect := func(x, y, w, h float64) {
+		fmt.Fprintf(&sb, "%.3f %.3f %.3f %.3f re\n", x*mmToPt, (pageH-y-h)*mmToPt, w*mmToPt, h*mmToPt)
+	}
+
+	for _, e := range elements {
+		switch e.kind {
+		case kindCode128:
+			sb.WriteString("0 g\n")
+			for i := 0; i < len(e.modules); {
+				if !e.modules[i] {
+					i++
+					continue
+				}
+				start := i
+				for i < len(e.modules) && e.modules[i] {
+					i++
+				}
+				rect(e.x+float64(start)*e.module, e.y, float64(i-start)*e.module, e.h)
+			}
+			sb.WriteString("f\n")
+
+		case kindQR:
+			sb.WriteString("0 g\n")
+			for y := 0; y < e.qr.Size; y++ {
+				for x := 0; x < e.qr.Size; x++ {
+					if e.qr.Dark(x, y) {
+						rect(e.x+float64(x)*e.module, e.y+float64(y)*e.module, e.module, e.module)
+					}
+				}
+			}
+			sb.WriteString("f\n")
+
+		case kindText:
+			font := "F1"
+			if e.bold {
+				font = "F2"
+			}
+			if e.inverse {
+				sb.WriteString("0 g\n")
+				rect(e.x, e.y, e.w, e.h)
+				sb.WriteString("f\n1 g\n")
+			} else {
+				sb.WriteString("0 g\n")
+			}
+			pad := 0.0
+			if e.inverse {
+				pad = e.h * 0.2
+			}
+			baseline := pageH - e.y - e.h + e.h*0.25
+			fmt.Fprintf(&sb, "BT /%s %.2f Tf %.3f %.3f Td (%s) Tj ET\n",
+				font, e.sizePt, (e.x+pad)*mmToPt, baseline*mmToPt, pdfEscape(e.text))
+		}
+	}
+
+	return sb.String()
+}
+
+// buildPDF assembles the page content streams into a PDF file
+func buildPDF(spec Spec, pages []string) []byte {
+	var objects []string
+	// 1: catalog, 2: pages, 3-4: fonts, then page/content pairs
+	objects = append(objects, "<< /Type /Catalog /Pages 2 0 R >>")
+
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+i*2)
+	}
+	objects = append(objects, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	objects = append(objects, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	objects = append(objects, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	mediaBox := fmt.Sprintf("[0 0 %.3f %.3f]", spec.WidthMM*mmToPt, spec.HeightMM*mmToPt)
+	for i, stream := range pages {
+		objects = append(objects, fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox %s /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			mediaBox, 6+i*2))
+		objects = append(objects, fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(stream), stream))
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return buf.Bytes()
+}
+
+func pdfEscape(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`)
+	return r.Replace(s)
+}
@@ -0,0 +1,72 @@
+package label
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// DefaultDPI is the resolution of standard Zebra print heads (8 dots/mm)
+const DefaultDPI = 203
+
+// RenderZPL renders the labels as ZPL II for Zebra printers. Barcodes use the
+// printer's native symbologies so they are rasterized at full resolution.
+func RenderZPL(spec Spec, contents []Content, copies, dpi int) ([]byte, error) {
+	if copies < 1 {
+		copies = 1
+	}
+	if dpi <= 0 {
+		dpi = DefaultDPI
+	}
+	dots := func(mm float64) int {
+		return int(math.Round(mm * float64(dpi) / 25.4))
+	}
+	// Module sizes round down so symbols never outgrow their layout box
+	moduleDots := func(mm float64) int {
+		return max(1, int(math.Floor(mm*float64(dpi)/25.4)))
+	}
+
+	var sb strings.Builder
+	for _, content := range contents {
+		elements, err := layout(spec, content)
+		if err != nil {
+			return nil, err
+		}
+
+		sb.WriteString("^XA\n^CI28\n")
+		fmt.Fprintf(&sb, "^PW%d\n^LL%d\n", dots(spec.WidthMM), dots(spec.HeightMM))
+
+		for _, e := range elements {
+			switch e.kind {
+			case kindCode128:
+				module := moduleDots(e.module)
+				fmt.Fprintf(&sb, "^FO%d,%d^BY%d^BCN,%d,N,N,N,A^FD%s^FS\n",
+					dots(e.x), dots(e.y), module, dots(e.h), zplEscape(e.data))
+
+			case kindQR:
+				magnification := min(10, moduleDots(e.module))
+				fmt.Fprintf(&sb, "^FO%d,%d^BQN,2,%d^FDMA,%s^FS\n",
+					dots(e.x), dots(e.y), magnification, zplEscape(e.data))
+
+			case kindText:
+				height := dots(e.sizePt * ptToMM)
+				font := fmt.Sprintf("^A0N,%d,%d", height, height*4/5)
+				if e.inverse {
+					fmt.Fprintf(&sb, "^FO%d,%d^GB%d,%d,%d^FS\n", dots(e.x), dots(e.y), dots(e.w), dots(e.h), dots(e.h))
+					fmt.Fprintf(&sb, "^FO%d,%d%s^FR^FD%s^FS\n", dots(e.x+e.h*0.2), dots(e.y+e.h*0.1), font, zplEscape(e.text))
+				} else {
+					fmt.Fprintf(&sb, "^FO%d,%d%s^FD%s^FS\n", dots(e.x), dots(e.y+e.h*0.1), font, zplEscape(e.text))
+				}
+			}
+		}
+
+		fmt.Fprintf(&sb, "^PQ%d\n^XZ\n", copies)
+	}
+
+	return []byte(sb.String()), nil
+}
+
+// zplEscape strips the ZPL command prefixes from field data
+func zplEscape(s string) string {
+	return strings.NewReplacer("^", "", "~", "").Replace(s)
+}
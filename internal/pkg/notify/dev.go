@@ -0,0 +1,98 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/minhtran/his/internal/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// LogProvider writes messages to the application log. It is meant for
+// development and testing.
+type LogProvider struct {
+	channel Channel
+}
+
+// NewLogProvider creates a log provider for a channel
+func NewLogProvider(channel Channel) *LogProvider {
+	return &LogProvider{channel: channel}
+}
+
+// Channel returns the channel served by the provider
+func (p *LogProvider) Channel() Channel {
+	return p.channel
+}
+
+// Send logs the message
+func (p *LogProvider) Send(_ context.Context, msg *Message) (string, error) {
+	ref := fmt.Sprintf("log-%d", time.Now().UnixNano())
+	logger.Info("Notification sent",
+		zap.String("channel", string(msg.Channel)),
+		zap.String("recipient", msg.Recipient),
+		zap.String("subject", msg.Subject),
+		zap.String("body", msg.Body),
+		zap.String("ref", ref),
+	)
+	return ref, nil
+}
+
+// FileProvider appends messages as JSON lines to <dir>/<channel>.jsonl, a
+// local outbox for development
+type FileProvider struct {
+	channel Channel
+	path    string
+	mu      sync.Mutex
+}
+
+// NewFileProvider creates a file provider writing to dir, creating it if needed
+func NewFileProvider(channel Channel, dir string) (*FileProvider, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("notify: failed to create outbox directory: %w", err)
+	}
+	return &FileProvider{
+		channel: channel,
+		path:    filepath.Join(dir, string(channel)+".jsonl"),
+	}, nil
+}
+
+// Channel returns the channel served by the provider
+func (p *FileProvider) Channel() Channel {
+	return p.channel
+}
+
+// Send appends the message to the outbox file
+func (p *FileProvider) Send(_ context.Context, msg *Message) (string, error) {
+	ref := fmt.Sprintf("file-%d", time.Now().UnixNano())
+	line, err := json.Marshal(map[string]interface{}{
+		"ref":       ref,
+		"sent_at":   time.Now().Format(time.RFC3339),
+		"channel":   msg.Channel,
+		"recipient": msg.Recipient,
+		"subject":   msg.Subject,
+		"body":      msg.Body,
+		"data":      msg.Data,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	f, err := os.OpenFile(p.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return "", err
+	}
+	return ref, nil
+}
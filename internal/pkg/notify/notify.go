@@ -0,0 +1,75 @@
+// Package notify delivers rendered messages to patients over SMS, email and
+// Zalo OA. Each channel is served by a Provider; development providers write
+// messages to the log or to a local outbox file instead of sending them.
+package notify
+
+import (
+	"context"
+	"errors"
+)
+
+// Channel is a delivery channel
+type Channel string
+
+const (
+	ChannelSMS   Channel = "SMS"
+	ChannelEmail Channel = "EMAIL"
+	ChannelZalo  Channel = "ZALO"
+)
+
+// ErrNoProvider is returned when no provider is registered for a channel
+var ErrNoProvider = errors.New("notify: no provider for channel")
+
+// Message is a rendered message for one recipient
+type Message struct {
+	Channel   Channel
+	Recipient string // Phone number or email address
+	Subject   string // Email only
+	Body      string
+	// Data holds the template variables, for channels such as Zalo ZNS that
+	// render pre-approved templates themselves
+	Data map[string]string
+}
+
+// Provider sends messages over one channel
+type Provider interface {
+	// Channel returns the channel served by the provider
+	Channel() Channel
+	// Send delivers the message and returns the provider's message reference
+	Send(ctx context.Context, msg *Message) (string, error)
+}
+
+// Dispatcher routes messages to the provider registered for their channel
+type Dispatcher struct {
+	providers map[Channel]Provider
+}
+
+// NewDispatcher creates a dispatcher; a later provider for the same channel
+// replaces an earlier one
+func NewDispatcher(providers ...Provider) *Dispatcher {
+	d := &Dispatcher{providers: make(map[Channel]Provider)}
+	for _, p := range providers {
+		d.providers[p.Channel()] = p
+	}
+	return d
+}
+
+// Channels returns the channels that have a provider
+func (d *Dispatcher) Channels() []Channel {
+	channels := make([]Channel, 0, len(d.providers))
+	for _, c := range []Channel{ChannelSMS, ChannelEmail, ChannelZalo} {
+		if _, ok := d.providers[c]; ok {
+			channels = append(channels, c)
+		}
+	}
+	return channels
+}
+
+// Send delivers a message through the provider of its channel
+func (d *Dispatcher) Send(ctx context.Context, msg *Message) (string, error) {
+	p, ok := d.providers[msg.Channel]
+	if !ok {
+		return "", ErrNoProvider
+	}
+	return p.Send(ctx, msg)
+}
@@ -0,0 +1,191 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EmailProvider sends email through an SMTP server
+type EmailProvider struct {
+	host     string
+	port     int
+	username string
+	password string
+	from     string
+}
+
+// NewEmailProvider creates an SMTP email provider; authentication is skipped
+// when username is empty
+func NewEmailProvider(host string, port int, username, password, from string) *EmailProvider {
+	return &EmailProvider{host: host, port: port, username: username, password: password, from: from}
+}
+
+// Channel returns the channel served by the provider
+func (p *EmailProvider) Channel() Channel {
+	return ChannelEmail
+}
+
+// Send sends the message as a plain text UTF-8 email
+func (p *EmailProvider) Send(_ context.Context, msg *Message) (string, error) {
+	ref := fmt.Sprintf("%d.%s", time.Now().UnixNano(), p.from)
+
+	var b strings.Builder
+	b.WriteString("From: " + p.from + "\r\n")
+	b.WriteString("To: " + msg.Recipient + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", msg.Subject) + "\r\n")
+	b.WriteString("Message-ID: <" + ref + ">\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+
+	var auth smtp.Auth
+	if p.username != "" {
+		auth = smtp.PlainAuth("", p.username, p.password, p.host)
+	}
+
+	addr := net.JoinHostPort(p.host, strconv.Itoa(p.port))
+	if err := smtp.SendMail(addr, auth, p.from, []string{msg.Recipient}, []byte(b.String())); err != nil {
+		return "", fmt.Errorf("notify: smtp: %w", err)
+	}
+	return ref, nil
+}
+
+// SMSProvider sends SMS through an HTTP gateway that accepts
+// {"to", "from", "message"} as JSON and answers with {"message_id"}
+type SMSProvider struct {
+	url    string
+	apiKey string
+	sender string // Brandname registered with the gateway
+	client *http.Client
+}
+
+// NewSMSProvider creates an HTTP SMS gateway provider
+func NewSMSProvider(url, apiKey, sender string) *SMSProvider {
+	return &SMSProvider{url: url, apiKey: apiKey, sender: sender, client: &http.Client{Timeout: 15 * time.Second}}
+}
+
+// Channel returns the channel served by the provider
+func (p *SMSProvider) Channel() Channel {
+	return ChannelSMS
+}
+
+// Send posts the message to the gateway
+func (p *SMSProvider) Send(ctx context.Context, msg *Message) (string, error) {
+	payload := map[string]string{
+		"to":      msg.Recipient,
+		"from":    p.sender,
+		"message": msg.Body,
+	}
+
+	var result struct {
+		MessageID string `json:"message_id"`
+	}
+	if err := postJSON(ctx, p.client, p.url, map[string]string{"Authorization": "Bearer " + p.apiKey}, payload, &result); err != nil {
+		return "", fmt.Errorf("notify: sms gateway: %w", err)
+	}
+	return result.MessageID, nil
+}
+
+// ZaloProvider sends Zalo Notification Service (ZNS) template messages from a
+// Zalo Official Account. ZNS renders its own pre-approved template, so the
+// message's Data is sent as template_data and Body is not used.
+type ZaloProvider struct {
+	url         string
+	accessToken string
+	templateID  string
+	client      *http.Client
+}
+
+// ZaloZNSURL is the Zalo ZNS template message endpoint
+const ZaloZNSURL = "https://business.openapi.zalo.me/message/template"
+
+// NewZaloProvider creates a Zalo OA ZNS provider
+func NewZaloProvider(accessToken, templateID string) *ZaloProvider {
+	return &ZaloProvider{url: ZaloZNSURL, accessToken: accessToken, templateID: templateID, client: &http.Client{Timeout: 15 * time.Second}}
+}
+
+// Channel returns the channel served by the provider
+func (p *ZaloProvider) Channel() Channel {
+	return ChannelZalo
+}
+
+// Send posts a ZNS template message to the recipient's phone number
+func (p *ZaloProvider) Send(ctx context.Context, msg *Message) (string, error) {
+	payload := map[string]interface{}{
+		"phone":         zaloPhone(msg.Recipient),
+		"template_id":   p.templateID,
+		"template_data": msg.Data,
+		"tracking_id":   strconv.FormatInt(time.Now().UnixNano(), 10),
+	}
+
+	var result struct {
+		Error   int    `json:"error"`
+		Message string `json:"message"`
+		Data    struct {
+			MsgID string `json:"msg_id"`
+		} `json:"data"`
+	}
+	if err := postJSON(ctx, p.client, p.url, map[string]string{"access_token": p.accessToken}, payload, &result); err != nil {
+		return "", fmt.Errorf("notify: zalo: %w", err)
+	}
+	if result.Error != 0 {
+		return "", fmt.Errorf("notify: zalo: error %d: %s", result.Error, result.Message)
+	}
+	return result.Data.MsgID, nil
+}
+
+// zaloPhone converts a Vietnamese phone number to the 84xxxxxxxxx form ZNS expects
+func zaloPhone(phone string) string {
+	phone = strings.TrimPrefix(strings.ReplaceAll(phone, " ", ""), "+")
+	if strings.HasPrefix(phone, "0") {
+		return "84" + phone[1:]
+	}
+	return phone
+}
+
+// postJSON posts a JSON payload and decodes a JSON response, treating non-2xx
+// statuses as errors
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, payload, result interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	if result != nil && len(respBody) > 0 {
+		return json.Unmarshal(respBody, result)
+	}
+	return nil
+}
@@ -55,6 +55,15 @@ func Created(c *gin.Context, message string, data interface{}) {
 	})
 }
 
+// Accepted sends an accepted response for work that continues in the background
+func Accepted(c *gin.Context, message string, data interface{}) {
+	c.JSON(http.StatusAccepted, Response{
+		Success: true,
+		Message: message,
+		Data:    data,
+	})
+}
+
 // SuccessPaginated sends a successful paginated response
 func SuccessPaginated(c *gin.Context, message string, data interface{}, pagination Pagination) {
 	c.JSON(http.StatusOK, PaginatedResponse{
@@ -97,6 +106,16 @@ func NotFound(c *gin.Context, message string) {
 	Error(c, http.StatusNotFound, "NOT_FOUND", message, nil)
 }
 
+// Conflict sends a conflict error
+func Conflict(c *gin.Context, message string) {
+	Error(c, http.StatusConflict, "CONFLICT", message, nil)
+}
+
+// TooManyRequests sends a rate limit error
+func TooManyRequests(c *gin.Context, message string) {
+	Error(c, http.StatusTooManyRequests, "TOO_MANY_REQUESTS", message, nil)
+}
+
 // InternalServerError sends an internal server error
 func InternalServerError(c *gin.Context, message string) {
 	Error(c, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", message, nil)
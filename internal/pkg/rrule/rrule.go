@@ -0,0 +1,272 @@
+// Package rrule parses and expands the subset of iCalendar (RFC 5545)
+// recurrence rules used for appointment series: FREQ=DAILY|WEEKLY|MONTHLY with
+// INTERVAL, COUNT or UNTIL, BYDAY (weekly) and BYMONTHDAY (monthly).
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency is the recurrence frequency
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+)
+
+// MaxOccurrences caps the number of dates a rule may expand to
+const MaxOccurrences = 100
+
+// maxEmptyPeriods caps the consecutive periods without a date, so a rule
+// whose BYMONTHDAY never falls in the months it visits stops expanding
+const maxEmptyPeriods = 100
+
+var (
+	// ErrInvalidRule is returned when the rule cannot be parsed
+	ErrInvalidRule = errors.New("rrule: invalid recurrence rule")
+	// ErrUnbounded is returned when a rule has neither COUNT nor UNTIL
+	ErrUnbounded = errors.New("rrule: rule must set COUNT or UNTIL")
+	// ErrTooManyOccurrences is returned when a rule expands beyond MaxOccurrences
+	ErrTooManyOccurrences = fmt.Errorf("rrule: rule expands to more than %d occurrences", MaxOccurrences)
+)
+
+var weekdayCodes = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// Rule is a parsed recurrence rule
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	Count      int        // 0 when UNTIL is used
+	Until      *time.Time // Inclusive date; nil when COUNT is used
+	ByDay      []time.Weekday
+	ByMonthDay []int
+}
+
+// Parse parses a rule such as "FREQ=WEEKLY;INTERVAL=1;BYDAY=MO,TH;COUNT=10".
+// A leading "RRULE:" is accepted. UNTIL is a date (YYYYMMDD or YYYY-MM-DD).
+func Parse(value string) (*Rule, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	if value == "" {
+		return nil, ErrInvalidRule
+	}
+
+	rule := &Rule{Interval: 1}
+	for _, part := range strings.Split(value, ";") {
+		if part == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(part, "=")
+		if !ok || val == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidRule, part)
+		}
+
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			switch Frequency(strings.ToUpper(val)) {
+			case Daily, Weekly, Monthly:
+				rule.Freq = Frequency(strings.ToUpper(val))
+			default:
+				return nil, fmt.Errorf("%w: unsupported FREQ %s", ErrInvalidRule, val)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 || n > 99 {
+				return nil, fmt.Errorf("%w: INTERVAL must be 1-99", ErrInvalidRule)
+			}
+			rule.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("%w: COUNT must be positive", ErrInvalidRule)
+			}
+			if n > MaxOccurrences {
+				return nil, ErrTooManyOccurrences
+			}
+			rule.Count = n
+		case "UNTIL":
+			until, err := parseUntil(val)
+			if err != nil {
+				return nil, fmt.Errorf("%w: UNTIL must be a date", ErrInvalidRule)
+			}
+			rule.Until = &until
+		case "BYDAY":
+			for _, code := range strings.Split(val, ",") {
+				day, ok := weekdayCodes[strings.ToUpper(code)]
+				if !ok {
+					return nil, fmt.Errorf("%w: unsupported BYDAY %s", ErrInvalidRule, code)
+				}
+				rule.ByDay = append(rule.ByDay, day)
+			}
+		case "BYMONTHDAY":
+			for _, d := range strings.Split(val, ",") {
+				n, err := strconv.Atoi(d)
+				if err != nil || n < 1 || n > 31 {
+					return nil, fmt.Errorf("%w: BYMONTHDAY must be 1-31", ErrInvalidRule)
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, n)
+			}
+		default:
+			return nil, fmt.Errorf("%w: unsupported part %s", ErrInvalidRule, key)
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+	}
+	if rule.Count > 0 && rule.Until != nil {
+		return nil, fmt.Errorf("%w: COUNT and UNTIL are exclusive", ErrInvalidRule)
+	}
+	if rule.Count == 0 && rule.Until == nil {
+		return nil, ErrUnbounded
+	}
+	if len(rule.ByDay) > 0 && rule.Freq != Weekly {
+		return nil, fmt.Errorf("%w: BYDAY is only supported with FREQ=WEEKLY", ErrInvalidRule)
+	}
+	if len(rule.ByMonthDay) > 0 && rule.Freq != Monthly {
+		return nil, fmt.Errorf("%w: BYMONTHDAY is only supported with FREQ=MONTHLY", ErrInvalidRule)
+	}
+
+	sort.Slice(rule.ByDay, func(i, j int) bool { return rule.ByDay[i] < rule.ByDay[j] })
+	sort.Ints(rule.ByMonthDay)
+	return rule, nil
+}
+
+// String formats the rule in canonical form
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		codes := make([]string, len(r.ByDay))
+		for i, d := range r.ByDay {
+			codes[i] = strings.ToUpper(d.String()[:2])
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, d := range r.ByMonthDay {
+			days[i] = strconv.Itoa(d)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.Format("20060102"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Dates expands the rule from a start date (the first occurrence when it
+// matches the rule). Only the date part of start is used.
+func (r *Rule) Dates(start time.Time) ([]time.Time, error) {
+	start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+
+	var dates []time.Time
+	add := func(d time.Time) bool {
+		if d.Before(start) {
+			return true
+		}
+		if r.Until != nil && d.After(*r.Until) {
+			return false
+		}
+		dates = append(dates, d)
+		return r.Count == 0 || len(dates) < r.Count
+	}
+
+	// Each period is one day, week or month; the loop stops once the rule is
+	// exhausted or a cap is exceeded
+	emptyPeriods := 0
+	for period := 0; ; period++ {
+		found := len(dates)
+		for _, d := range r.periodDates(start, period) {
+			if !add(d) {
+				return dates, nil
+			}
+			if len(dates) > MaxOccurrences {
+				return nil, ErrTooManyOccurrences
+			}
+		}
+		if r.Until != nil && r.periodStart(start, period).After(*r.Until) {
+			return dates, nil
+		}
+
+		if len(dates) > found {
+			emptyPeriods = 0
+		} else if emptyPeriods++; emptyPeriods > maxEmptyPeriods {
+			return nil, fmt.Errorf("%w: rule yields no dates", ErrInvalidRule)
+		}
+	}
+}
+
+// periodStart returns the first day of the n-th period counted from start
+func (r *Rule) periodStart(start time.Time, n int) time.Time {
+	switch r.Freq {
+	case Weekly:
+		weekStart := start.AddDate(0, 0, -int(start.Weekday()))
+		return weekStart.AddDate(0, 0, 7*n*r.Interval)
+	case Monthly:
+		return time.Date(start.Year(), start.Month()+time.Month(n*r.Interval), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return start.AddDate(0, 0, n*r.Interval)
+	}
+}
+
+// periodDates returns the candidate dates of the n-th period in order
+func (r *Rule) periodDates(start time.Time, n int) []time.Time {
+	first := r.periodStart(start, n)
+
+	switch r.Freq {
+	case Weekly:
+		days := r.ByDay
+		if len(days) == 0 {
+			days = []time.Weekday{start.Weekday()}
+		}
+		dates := make([]time.Time, 0, len(days))
+		for _, d := range days {
+			dates = append(dates, first.AddDate(0, 0, int(d)))
+		}
+		return dates
+	case Monthly:
+		monthDays := r.ByMonthDay
+		if len(monthDays) == 0 {
+			monthDays = []int{start.Day()}
+		}
+		var dates []time.Time
+		for _, d := range monthDays {
+			date := time.Date(first.Year(), first.Month(), d, 0, 0, 0, 0, time.UTC)
+			// Months without that day are skipped, as in RFC 5545
+			if date.Month() == first.Month() {
+				dates = append(dates, date)
+			}
+		}
+		return dates
+	default:
+		return []time.Time{first}
+	}
+}
+
+func parseUntil(value string) (time.Time, error) {
+	if len(value) >= 8 && !strings.Contains(value, "-") {
+		return time.Parse("20060102", value[:8])
+	}
+	return time.Parse("2006-01-02", value)
+}
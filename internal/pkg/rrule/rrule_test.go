@@ -0,0 +1,185 @@
+package rrule
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr error
+	}{
+		{"weekly by day", "FREQ=WEEKLY;BYDAY=TH,MO;COUNT=10", "FREQ=WEEKLY;BYDAY=MO,TH;COUNT=10", nil},
+		{"rrule prefix", "RRULE:FREQ=DAILY;INTERVAL=2;COUNT=3", "FREQ=DAILY;INTERVAL=2;COUNT=3", nil},
+		{"lowercase keys", "freq=monthly;bymonthday=31,1;until=20270101", "FREQ=MONTHLY;BYMONTHDAY=1,31;UNTIL=20270101", nil},
+		{"dashed until", "FREQ=DAILY;UNTIL=2027-01-01", "FREQ=DAILY;UNTIL=20270101", nil},
+		{"count at cap", "FREQ=DAILY;COUNT=100", "FREQ=DAILY;COUNT=100", nil},
+		{"empty", "", "", ErrInvalidRule},
+		{"missing freq", "COUNT=3", "", ErrInvalidRule},
+		{"unsupported freq", "FREQ=YEARLY;COUNT=3", "", ErrInvalidRule},
+		{"unbounded", "FREQ=DAILY", "", ErrUnbounded},
+		{"count and until", "FREQ=DAILY;COUNT=3;UNTIL=20270101", "", ErrInvalidRule},
+		{"count over cap", "FREQ=DAILY;COUNT=101", "", ErrTooManyOccurrences},
+		{"zero count", "FREQ=DAILY;COUNT=0", "", ErrInvalidRule},
+		{"zero interval", "FREQ=DAILY;INTERVAL=0;COUNT=3", "", ErrInvalidRule},
+		{"interval over cap", "FREQ=DAILY;INTERVAL=100;COUNT=3", "", ErrInvalidRule},
+		{"month day zero", "FREQ=MONTHLY;BYMONTHDAY=0;COUNT=3", "", ErrInvalidRule},
+		{"month day over 31", "FREQ=MONTHLY;BYMONTHDAY=32;COUNT=3", "", ErrInvalidRule},
+		{"by day on daily", "FREQ=DAILY;BYDAY=MO;COUNT=3", "", ErrInvalidRule},
+		{"month day on weekly", "FREQ=WEEKLY;BYMONTHDAY=1;COUNT=3", "", ErrInvalidRule},
+		{"unknown weekday", "FREQ=WEEKLY;BYDAY=XX;COUNT=3", "", ErrInvalidRule},
+		{"bad until", "FREQ=DAILY;UNTIL=tomorrow", "", ErrInvalidRule},
+		{"unsupported part", "FREQ=DAILY;COUNT=3;BYHOUR=9", "", ErrInvalidRule},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.value)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Parse(%q) error = %v, want %v", tt.value, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.value, err)
+			}
+			if got := rule.String(); got != tt.want {
+				t.Errorf("Parse(%q) = %s, want %s", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDates(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		start   time.Time
+		want    []time.Time
+		wantErr error
+	}{
+		{
+			name:  "daily count",
+			rule:  "FREQ=DAILY;INTERVAL=2;COUNT=3",
+			start: date(2027, 1, 30),
+			want:  []time.Time{date(2027, 1, 30), date(2027, 2, 1), date(2027, 2, 3)},
+		},
+		{
+			name:  "weekly by day skips days before start",
+			rule:  "FREQ=WEEKLY;BYDAY=MO,TH;COUNT=3",
+			start: date(2027, 1, 6), // Wednesday
+			want:  []time.Time{date(2027, 1, 7), date(2027, 1, 11), date(2027, 1, 14)},
+		},
+		{
+			name:  "until is inclusive",
+			rule:  "FREQ=WEEKLY;UNTIL=20270115",
+			start: date(2027, 1, 1),
+			want:  []time.Time{date(2027, 1, 1), date(2027, 1, 8), date(2027, 1, 15)},
+		},
+		{
+			name:  "until before start",
+			rule:  "FREQ=DAILY;UNTIL=20261231",
+			start: date(2027, 1, 1),
+			want:  nil,
+		},
+		{
+			name:  "count of one",
+			rule:  "FREQ=MONTHLY;COUNT=1",
+			start: date(2027, 1, 15),
+			want:  []time.Time{date(2027, 1, 15)},
+		},
+		{
+			name:  "month day 29 skips common february",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=29;COUNT=3",
+			start: date(2027, 1, 1),
+			want:  []time.Time{date(2027, 1, 29), date(2027, 3, 29), date(2027, 4, 29)},
+		},
+		{
+			name:  "month day 29 in leap february",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=29;COUNT=2",
+			start: date(2028, 2, 1),
+			want:  []time.Time{date(2028, 2, 29), date(2028, 3, 29)},
+		},
+		{
+			name:  "month day 30 skips february",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=30;UNTIL=20270331",
+			start: date(2027, 1, 1),
+			want:  []time.Time{date(2027, 1, 30), date(2027, 3, 30)},
+		},
+		{
+			name:  "month day 31 skips short months",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=31;COUNT=4",
+			start: date(2027, 1, 31),
+			want:  []time.Time{date(2027, 1, 31), date(2027, 3, 31), date(2027, 5, 31), date(2027, 7, 31)},
+		},
+		{
+			name:  "yearly leap day",
+			rule:  "FREQ=MONTHLY;INTERVAL=12;BYMONTHDAY=29;COUNT=2",
+			start: date(2027, 2, 1),
+			want:  []time.Time{date(2028, 2, 29), date(2032, 2, 29)},
+		},
+		{
+			name:    "month day never reached",
+			rule:    "FREQ=MONTHLY;INTERVAL=12;BYMONTHDAY=30;COUNT=3",
+			start:   date(2027, 2, 1),
+			wantErr: ErrInvalidRule,
+		},
+		{
+			name:    "until beyond cap",
+			rule:    "FREQ=DAILY;UNTIL=20280101",
+			start:   date(2027, 1, 1),
+			wantErr: ErrTooManyOccurrences,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.rule, err)
+			}
+			got, err := rule.Dates(tt.start)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Dates() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Dates() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Dates() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("Dates()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDatesUntilAtCap(t *testing.T) {
+	rule, err := Parse("FREQ=DAILY;UNTIL=20270410")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	got, err := rule.Dates(date(2027, 1, 1))
+	if err != nil {
+		t.Fatalf("Dates() error = %v", err)
+	}
+	if len(got) != MaxOccurrences {
+		t.Fatalf("Dates() returned %d dates, want %d", len(got), MaxOccurrences)
+	}
+	if last := got[len(got)-1]; !last.Equal(date(2027, 4, 10)) {
+		t.Errorf("last date = %v, want 2027-04-10", last)
+	}
+}
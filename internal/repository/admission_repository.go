@@ -3,7 +3,6 @@ package repository
 import (
 	"errors"
 	"fmt"
-	"time"
 
 	"github.com/minhtran/his/internal/domain"
 	"gorm.io/gorm"
@@ -87,7 +86,7 @@ func (r *AdmissionRepository) Update(admission *domain.Admission) error {
 
 // GenerateAdmissionCode generates a unique admission code
 func (r *AdmissionRepository) GenerateAdmissionCode() (string, error) {
-	today := time.Now().Format("20060102") // YYYYMMDD
+	today := r.db.NowFunc().Format("20060102") // YYYYMMDD
 	prefix := fmt.Sprintf("ADM-%s-", today)
 
 	var lastAdmission domain.Admission
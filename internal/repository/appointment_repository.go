@@ -7,8 +7,12 @@ import (
 
 	"github.com/minhtran/his/internal/domain"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// activeBookingHoldStatuses are the online booking hold statuses that block a slot until the hold expires
+var activeBookingHoldStatuses = []domain.BookingHoldStatus{domain.BookingHoldStatusPending, domain.BookingHoldStatusVerified}
+
 // AppointmentRepository handles appointment data operations
 type AppointmentRepository struct {
 	db *gorm.DB
@@ -24,6 +28,24 @@ func (r *AppointmentRepository) Create(appointment *domain.Appointment) error {
 	return r.db.Create(appointment).Error
 }
 
+// WithDoctorLock runs fn in a transaction holding a row lock on the doctor and
+// then on each resource to reserve, so that checking a slot is free and
+// booking it cannot interleave with another booking for the same doctor or
+// resources. fn must run its queries through the repositories it is given,
+// which are bound to the transaction.
+func (r *AppointmentRepository) WithDoctorLock(doctorID uint, resourceIDs []uint, fn func(repo *AppointmentRepository, resources *ResourceRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var doctor domain.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&doctor, doctorID).Error; err != nil {
+			return err
+		}
+		if err := lockResources(tx, resourceIDs); err != nil {
+			return err
+		}
+		return fn(&AppointmentRepository{db: tx}, &ResourceRepository{db: tx})
+	})
+}
+
 // FindByID finds an appointment by ID
 func (r *AppointmentRepository) FindByID(id uint) (*domain.Appointment, error) {
 	var appointment domain.Appointment
@@ -69,6 +91,17 @@ func (r *AppointmentRepository) FindByPatientID(patientID uint, filters map[stri
 	return appointments, err
 }
 
+// CountActivePortalBookings counts a patient's scheduled or confirmed portal bookings from a date onwards
+func (r *AppointmentRepository) CountActivePortalBookings(patientID uint, from time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.Appointment{}).
+		Where("patient_id = ? AND booking_source = ?", patientID, domain.BookingSourcePortal).
+		Where("status IN ?", []domain.AppointmentStatus{domain.AppointmentStatusScheduled, domain.AppointmentStatusConfirmed}).
+		Where("appointment_date >= ?", from.Format("2006-01-02")).
+		Count(&count).Error
+	return count, err
+}
+
 // FindByDoctorID finds appointments for a doctor on a specific date
 func (r *AppointmentRepository) FindByDoctorID(doctorID uint, date time.Time) ([]*domain.Appointment, error) {
 	var appointments []*domain.Appointment
@@ -79,8 +112,37 @@ func (r *AppointmentRepository) FindByDoctorID(doctorID uint, date time.Time) ([
 	return appointments, err
 }
 
+// FindByDoctorIDBetween finds appointments for a doctor from one date through another, inclusive
+func (r *AppointmentRepository) FindByDoctorIDBetween(doctorID uint, from, to time.Time) ([]*domain.Appointment, error) {
+	var appointments []*domain.Appointment
+	err := r.db.Preload("Patient").
+		Where("doctor_id = ? AND appointment_date BETWEEN ? AND ?", doctorID, from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Order("appointment_date ASC, appointment_time ASC").
+		Find(&appointments).Error
+	return appointments, err
+}
+
 // CheckTimeSlotAvailable checks if a time slot is available for a doctor
 func (r *AppointmentRepository) CheckTimeSlotAvailable(doctorID uint, date time.Time, appointmentTime time.Time, duration int, excludeID *uint) (bool, error) {
+	var excludeIDs []uint
+	if excludeID != nil {
+		excludeIDs = []uint{*excludeID}
+	}
+	return r.CheckTimeSlotAvailableExcluding(doctorID, date, appointmentTime, duration, excludeIDs)
+}
+
+// CheckTimeSlotAvailableExcluding checks if a time slot is available for a doctor,
+// ignoring the given appointments (e.g. occurrences of a series being moved together).
+// Slots held for a pending waitlist offer or an online booking are not available.
+func (r *AppointmentRepository) CheckTimeSlotAvailableExcluding(doctorID uint, date time.Time, appointmentTime time.Time, duration int, excludeIDs []uint) (bool, error) {
+	return r.CheckTimeSlotCapacity(doctorID, date, appointmentTime, duration, 1, excludeIDs)
+}
+
+// CheckTimeSlotCapacity checks that fewer than capacity of a doctor's active
+// appointments, pending waitlist offers and online booking holds overlap a
+// time slot, ignoring the given appointments. A capacity above one lets an
+// overbooked clinic session double-book its slots.
+func (r *AppointmentRepository) CheckTimeSlotCapacity(doctorID uint, date time.Time, appointmentTime time.Time, duration, capacity int, excludeIDs []uint) (bool, error) {
 	dateStr := date.Format("2006-01-02")
 	timeStr := appointmentTime.Format("15:04:05")
 
@@ -95,8 +157,8 @@ func (r *AppointmentRepository) CheckTimeSlotAvailable(doctorID uint, date time.
 		Where("(appointment_time < ? AND ADDTIME(appointment_time, SEC_TO_TIME(duration_minutes * 60)) > ?) OR (appointment_time >= ? AND appointment_time < ?)",
 			endTimeStr, timeStr, timeStr, endTimeStr)
 
-	if excludeID != nil {
-		query = query.Where("id != ?", *excludeID)
+	if len(excludeIDs) > 0 {
+		query = query.Where("id NOT IN ?", excludeIDs)
 	}
 
 	var count int64
@@ -105,7 +167,246 @@ func (r *AppointmentRepository) CheckTimeSlotAvailable(doctorID uint, date time.
 		return false, err
 	}
 
-	return count == 0, nil
+	var held int64
+	err = r.db.Model(&domain.WaitlistOffer{}).
+		Where("doctor_id = ?", doctorID).
+		Where("appointment_date = ?", dateStr).
+		Where("status = ? AND expires_at > ?", domain.WaitlistOfferStatusPending, r.db.NowFunc()).
+		Where("(appointment_time < ? AND ADDTIME(appointment_time, SEC_TO_TIME(duration_minutes * 60)) > ?) OR (appointment_time >= ? AND appointment_time < ?)",
+			endTimeStr, timeStr, timeStr, endTimeStr).
+		Count(&held).Error
+	if err != nil {
+		return false, err
+	}
+
+	var onHold int64
+	err = r.db.Model(&domain.BookingHold{}).
+		Where("doctor_id = ?", doctorID).
+		Where("appointment_date = ?", dateStr).
+		Where("status IN ? AND expires_at > ?", activeBookingHoldStatuses, r.db.NowFunc()).
+		Where("(appointment_time < ? AND ADDTIME(appointment_time, SEC_TO_TIME(duration_minutes * 60)) > ?) OR (appointment_time >= ? AND appointment_time < ?)",
+			endTimeStr, timeStr, timeStr, endTimeStr).
+		Count(&onHold).Error
+	if err != nil {
+		return false, err
+	}
+
+	return count+held+onHold < int64(capacity), nil
+}
+
+// CountInTimeRange counts a doctor's active appointments, held waitlist offers
+// and online booking holds starting within [start, end) on a date
+func (r *AppointmentRepository) CountInTimeRange(doctorID uint, date time.Time, start, end time.Time, excludeIDs []uint) (int64, error) {
+	query := r.db.Model(&domain.Appointment{}).
+		Where("doctor_id = ?", doctorID).
+		Where("appointment_date = ?", date.Format("2006-01-02")).
+		Where("status NOT IN ?", []string{"CANCELLED", "NO_SHOW"}).
+		Where("appointment_time >= ? AND appointment_time < ?", start.Format("15:04:05"), end.Format("15:04:05"))
+
+	if len(excludeIDs) > 0 {
+		query = query.Where("id NOT IN ?", excludeIDs)
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	var held int64
+	err := r.db.Model(&domain.WaitlistOffer{}).
+		Where("doctor_id = ?", doctorID).
+		Where("appointment_date = ?", date.Format("2006-01-02")).
+		Where("status = ? AND expires_at > ?", domain.WaitlistOfferStatusPending, r.db.NowFunc()).
+		Where("appointment_time >= ? AND appointment_time < ?", start.Format("15:04:05"), end.Format("15:04:05")).
+		Count(&held).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var onHold int64
+	err = r.db.Model(&domain.BookingHold{}).
+		Where("doctor_id = ?", doctorID).
+		Where("appointment_date = ?", date.Format("2006-01-02")).
+		Where("status IN ? AND expires_at > ?", activeBookingHoldStatuses, r.db.NowFunc()).
+		Where("appointment_time >= ? AND appointment_time < ?", start.Format("15:04:05"), end.Format("15:04:05")).
+		Count(&onHold).Error
+	return count + held + onHold, err
+}
+
+// BookedInterval is a stretch of a doctor's day taken by an active
+// appointment or held for a pending waitlist offer or online booking
+type BookedInterval struct {
+	DoctorID        uint
+	AppointmentDate time.Time
+	StartMinute     int // Minutes since midnight
+	DurationMinutes int
+}
+
+// FindBookedIntervals loads, in one query, the intervals the doctors have
+// booked or held between two dates (inclusive)
+func (r *AppointmentRepository) FindBookedIntervals(doctorIDs []uint, from, to time.Time) ([]*BookedInterval, error) {
+	var intervals []*BookedInterval
+	if len(doctorIDs) == 0 {
+		return intervals, nil
+	}
+
+	fromStr, toStr := from.Format("2006-01-02"), to.Format("2006-01-02")
+	now := r.db.NowFunc()
+	err := r.db.Raw(`SELECT doctor_id, appointment_date, TIME_TO_SEC(appointment_time) DIV 60 AS start_minute, duration_minutes
+		FROM appointments
+		WHERE deleted_at IS NULL AND doctor_id IN ? AND appointment_date BETWEEN ? AND ? AND status NOT IN ?
+		UNION ALL
+		SELECT doctor_id, appointment_date, TIME_TO_SEC(appointment_time) DIV 60 AS start_minute, duration_minutes
+		FROM waitlist_offers
+		WHERE doctor_id IN ? AND appointment_date BETWEEN ? AND ? AND status = ? AND expires_at > ?
+		UNION ALL
+		SELECT doctor_id, appointment_date, TIME_TO_SEC(appointment_time) DIV 60 AS start_minute, duration_minutes
+		FROM booking_holds
+		WHERE doctor_id IN ? AND appointment_date BETWEEN ? AND ? AND status IN ? AND expires_at > ?
+		ORDER BY doctor_id, appointment_date, start_minute`,
+		doctorIDs, fromStr, toStr, []string{"CANCELLED", "NO_SHOW"},
+		doctorIDs, fromStr, toStr, domain.WaitlistOfferStatusPending, now,
+		doctorIDs, fromStr, toStr, activeBookingHoldStatuses, now).
+		Scan(&intervals).Error
+	return intervals, err
+}
+
+// CreateBookingHold holds a slot for an online booking; call it under
+// WithDoctorLock after checking the slot is free
+func (r *AppointmentRepository) CreateBookingHold(hold *domain.BookingHold) error {
+	return r.db.Omit("Doctor", "Appointment").Create(hold).Error
+}
+
+// ConfirmBookingHold marks a verified online booking hold that has not expired
+// as confirmed by its patient, reporting false when the hold is no longer
+// verified; call it under WithDoctorLock before booking the held slot
+func (r *AppointmentRepository) ConfirmBookingHold(hold *domain.BookingHold, now time.Time) (bool, error) {
+	result := r.db.Model(&domain.BookingHold{}).
+		Where("id = ? AND status = ? AND expires_at > ?", hold.ID, domain.BookingHoldStatusVerified, now).
+		Updates(map[string]interface{}{
+			"status":       domain.BookingHoldStatusConfirmed,
+			"patient_id":   hold.PatientID,
+			"confirmed_at": now,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	hold.Status = domain.BookingHoldStatusConfirmed
+	hold.ConfirmedAt = &now
+	return true, nil
+}
+
+// LinkBookingHold records the appointment a confirmed hold was booked as
+func (r *AppointmentRepository) LinkBookingHold(holdID, appointmentID uint) error {
+	return r.db.Model(&domain.BookingHold{}).Where("id = ?", holdID).Update("appointment_id", appointmentID).Error
+}
+
+// AcceptWaitlistOffer marks a pending, unexpired waitlist offer as accepted,
+// releasing its slot to the booking that accepts it; call it under
+// WithDoctorLock. It returns false, changing nothing, when the offer was
+// already answered or expired.
+func (r *AppointmentRepository) AcceptWaitlistOffer(offer *domain.WaitlistOffer, acceptedBy uint, now time.Time) (bool, error) {
+	result := r.db.Model(&domain.WaitlistOffer{}).
+		Where("id = ? AND status = ? AND expires_at > ?", offer.ID, domain.WaitlistOfferStatusPending, now).
+		Updates(map[string]interface{}{
+			"status":       domain.WaitlistOfferStatusAccepted,
+			"responded_at": now,
+			"responded_by": acceptedBy,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	offer.Status = domain.WaitlistOfferStatusAccepted
+	offer.RespondedAt = &now
+	offer.RespondedBy = &acceptedBy
+	return true, nil
+}
+
+// LinkWaitlistOffer records the appointment an accepted offer was booked as
+// and marks its waitlist entry as booked
+func (r *AppointmentRepository) LinkWaitlistOffer(offer *domain.WaitlistOffer, appointmentID, updatedBy uint) error {
+	if err := r.db.Model(&domain.WaitlistOffer{}).Where("id = ?", offer.ID).Update("appointment_id", appointmentID).Error; err != nil {
+		return err
+	}
+	return r.db.Model(&domain.WaitlistEntry{}).Where("id = ?", offer.EntryID).Updates(map[string]interface{}{
+		"status":         domain.WaitlistStatusBooked,
+		"appointment_id": appointmentID,
+		"updated_by":     updatedBy,
+	}).Error
+}
+
+// CreateWaitlistOffer holds a slot for a waitlisted patient and marks the
+// entry as offered; call it under WithDoctorLock after checking the slot is free
+func (r *AppointmentRepository) CreateWaitlistOffer(offer *domain.WaitlistOffer, entry *domain.WaitlistEntry) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Entry", "Doctor").Create(offer).Error; err != nil {
+			return err
+		}
+		return tx.Model(&domain.WaitlistEntry{}).Where("id = ?", entry.ID).Updates(map[string]interface{}{
+			"status":      domain.WaitlistStatusOffered,
+			"offer_count": gorm.Expr("offer_count + 1"),
+		}).Error
+	})
+}
+
+// CreateSeries creates a series and its appointments, assigning appointment
+// codes; call it under WithDoctorLock after checking each occurrence is free
+func (r *AppointmentRepository) CreateSeries(series *domain.AppointmentSeries, appointments []*domain.Appointment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Appointments").Create(series).Error; err != nil {
+			return err
+		}
+
+		for _, appointment := range appointments {
+			code, err := generateAppointmentCode(tx)
+			if err != nil {
+				return err
+			}
+			appointment.AppointmentCode = code
+			appointment.SeriesID = &series.ID
+
+			if err := tx.Create(appointment).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// UpdateSeries saves a series, changed occurrences and the history of any
+// status changes in one transaction; call it under WithDoctorLock after
+// checking the slots moved occurrences take are free
+func (r *AppointmentRepository) UpdateSeries(series *domain.AppointmentSeries, appointments []*domain.Appointment, history []*domain.AppointmentStatusHistory) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, appointment := range appointments {
+			if err := tx.Omit("Patient", "Doctor").Save(appointment).Error; err != nil {
+				return err
+			}
+		}
+		if len(history) > 0 {
+			if err := tx.Create(&history).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Omit("Appointments", "Patient", "Doctor").Save(series).Error
+	})
+}
+
+// CountActiveInDateRange counts a doctor's scheduled or confirmed appointments between two dates (inclusive)
+func (r *AppointmentRepository) CountActiveInDateRange(doctorID uint, from, to time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.Appointment{}).
+		Where("doctor_id = ?", doctorID).
+		Where("appointment_date BETWEEN ? AND ?", from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Where("status IN ?", []domain.AppointmentStatus{domain.AppointmentStatusScheduled, domain.AppointmentStatusConfirmed}).
+		Count(&count).Error
+	return count, err
 }
 
 // Update updates an appointment
@@ -113,6 +414,27 @@ func (r *AppointmentRepository) Update(appointment *domain.Appointment) error {
 	return r.db.Save(appointment).Error
 }
 
+// UpdateStatus saves an appointment together with the history record of its
+// status change in one transaction
+func (r *AppointmentRepository) UpdateStatus(appointment *domain.Appointment, history *domain.AppointmentStatusHistory) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Patient", "Doctor").Save(appointment).Error; err != nil {
+			return err
+		}
+		return tx.Create(history).Error
+	})
+}
+
+// FindStatusHistory finds the status changes of an appointment, oldest first
+func (r *AppointmentRepository) FindStatusHistory(appointmentID uint) ([]*domain.AppointmentStatusHistory, error) {
+	var history []*domain.AppointmentStatusHistory
+	err := r.db.Preload("User").
+		Where("appointment_id = ?", appointmentID).
+		Order("changed_at ASC, id ASC").
+		Find(&history).Error
+	return history, err
+}
+
 // Delete soft deletes an appointment
 func (r *AppointmentRepository) Delete(id uint) error {
 	return r.db.Delete(&domain.Appointment{}, id).Error
@@ -121,7 +443,7 @@ func (r *AppointmentRepository) Delete(id uint) error {
 // GetUpcomingAppointments gets upcoming appointments
 func (r *AppointmentRepository) GetUpcomingAppointments(limit int) ([]*domain.Appointment, error) {
 	var appointments []*domain.Appointment
-	now := time.Now()
+	now := r.db.NowFunc()
 	today := now.Format("2006-01-02")
 	currentTime := now.Format("15:04:05")
 
@@ -135,6 +457,109 @@ func (r *AppointmentRepository) GetUpcomingAppointments(limit int) ([]*domain.Ap
 	return appointments, err
 }
 
+// FindStartingBetween finds scheduled or confirmed appointments starting in (from, to]
+func (r *AppointmentRepository) FindStartingBetween(from, to time.Time) ([]*domain.Appointment, error) {
+	var appointments []*domain.Appointment
+	err := r.db.Preload("Patient").Preload("Doctor").
+		Where("appointment_date BETWEEN ? AND ?", from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Where("TIMESTAMP(appointment_date, appointment_time) > ? AND TIMESTAMP(appointment_date, appointment_time) <= ?",
+			from.Format("2006-01-02 15:04:05"), to.Format("2006-01-02 15:04:05")).
+		Where("status IN ?", []domain.AppointmentStatus{domain.AppointmentStatusScheduled, domain.AppointmentStatusConfirmed}).
+		Order("appointment_date ASC, appointment_time ASC").
+		Find(&appointments).Error
+	return appointments, err
+}
+
+// FindOverdue finds scheduled or confirmed appointments that started at or before cutoff
+func (r *AppointmentRepository) FindOverdue(cutoff time.Time, limit int) ([]*domain.Appointment, error) {
+	var appointments []*domain.Appointment
+	err := r.db.Where("appointment_date <= ?", cutoff.Format("2006-01-02")).
+		Where("TIMESTAMP(appointment_date, appointment_time) <= ?", cutoff.Format("2006-01-02 15:04:05")).
+		Where("status IN ?", []domain.AppointmentStatus{domain.AppointmentStatusScheduled, domain.AppointmentStatusConfirmed}).
+		Order("appointment_date ASC, appointment_time ASC").
+		Limit(limit).
+		Find(&appointments).Error
+	return appointments, err
+}
+
+// MarkNoShow moves an appointment that is still scheduled or confirmed to
+// NO_SHOW and records its status history in one transaction, reporting
+// whether it did; an appointment checked in meanwhile is left unchanged
+func (r *AppointmentRepository) MarkNoShow(appointment *domain.Appointment, history *domain.AppointmentStatusHistory) (bool, error) {
+	marked := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.Appointment{}).
+			Where("id = ?", appointment.ID).
+			Where("status IN ?", []domain.AppointmentStatus{domain.AppointmentStatusScheduled, domain.AppointmentStatusConfirmed}).
+			Update("status", domain.AppointmentStatusNoShow)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != 1 {
+			return nil
+		}
+		marked = true
+		return tx.Create(history).Error
+	})
+	if err != nil || !marked {
+		return false, err
+	}
+	appointment.Status = domain.AppointmentStatusNoShow
+	return true, nil
+}
+
+// CountPatientOutcomes counts a patient's appointments dated within [from, to]
+// by status; a zero from counts all appointments up to to
+func (r *AppointmentRepository) CountPatientOutcomes(patientID uint, from, to time.Time) (map[domain.AppointmentStatus]int64, error) {
+	return r.countOutcomes("patient_id", patientID, from, to)
+}
+
+// CountDoctorOutcomes counts a doctor's appointments dated within [from, to] by status
+func (r *AppointmentRepository) CountDoctorOutcomes(doctorID uint, from, to time.Time) (map[domain.AppointmentStatus]int64, error) {
+	return r.countOutcomes("doctor_id", doctorID, from, to)
+}
+
+// countOutcomes counts appointments of a patient or doctor by status
+func (r *AppointmentRepository) countOutcomes(column string, id uint, from, to time.Time) (map[domain.AppointmentStatus]int64, error) {
+	var rows []struct {
+		Status domain.AppointmentStatus
+		Count  int64
+	}
+	query := r.db.Model(&domain.Appointment{}).
+		Select("status, COUNT(*) AS count").
+		Where(column+" = ?", id).
+		Where("appointment_date <= ?", to.Format("2006-01-02"))
+	if !from.IsZero() {
+		query = query.Where("appointment_date >= ?", from.Format("2006-01-02"))
+	}
+
+	err := query.Group("status").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[domain.AppointmentStatus]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// FindLatestNoShow finds a patient's most recent no-show
+func (r *AppointmentRepository) FindLatestNoShow(patientID uint) (*domain.Appointment, error) {
+	var appointment domain.Appointment
+	err := r.db.Where("patient_id = ? AND status = ?", patientID, domain.AppointmentStatusNoShow).
+		Order("appointment_date DESC, appointment_time DESC").
+		First(&appointment).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &appointment, nil
+}
+
 // Search searches appointments with filters
 func (r *AppointmentRepository) Search(filters map[string]interface{}, page, pageSize int) ([]*domain.Appointment, int64, error) {
 	var appointments []*domain.Appointment
@@ -176,11 +601,17 @@ func (r *AppointmentRepository) Search(filters map[string]interface{}, page, pag
 
 // GenerateAppointmentCode generates a unique appointment code
 func (r *AppointmentRepository) GenerateAppointmentCode() (string, error) {
-	today := time.Now().Format("20060102") // YYYYMMDD
+	return generateAppointmentCode(r.db)
+}
+
+// generateAppointmentCode generates the next appointment code using db, which
+// may be a transaction when several appointments are created together
+func generateAppointmentCode(db *gorm.DB) (string, error) {
+	today := db.NowFunc().Format("20060102") // YYYYMMDD
 	prefix := fmt.Sprintf("APT-%s-", today)
 
 	var lastAppointment domain.Appointment
-	err := r.db.Where("appointment_code LIKE ?", prefix+"%").
+	err := db.Where("appointment_code LIKE ?", prefix+"%").
 		Order("appointment_code DESC").
 		First(&lastAppointment).Error
 
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/minhtran/his/internal/domain"
+	"gorm.io/gorm"
+)
+
+// AppointmentSeriesRepository handles appointment series data operations
+type AppointmentSeriesRepository struct {
+	db *gorm.DB
+}
+
+// NewAppointmentSeriesRepository creates a new appointment series repository
+func NewAppointmentSeriesRepository(db *gorm.DB) *AppointmentSeriesRepository {
+	return &AppointmentSeriesRepository{db: db}
+}
+
+// FindByID finds a series by ID with its appointments in occurrence order
+func (r *AppointmentSeriesRepository) FindByID(id uint) (*domain.AppointmentSeries, error) {
+	var series domain.AppointmentSeries
+	err := r.db.Preload("Patient").Preload("Doctor").
+		Preload("Appointments", func(db *gorm.DB) *gorm.DB {
+			return db.Order("series_index ASC")
+		}).
+		First(&series, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &series, nil
+}
+
+// FindByPatientID finds the series of a patient
+func (r *AppointmentSeriesRepository) FindByPatientID(patientID uint) ([]*domain.AppointmentSeries, error) {
+	var series []*domain.AppointmentSeries
+	err := r.db.Preload("Doctor").
+		Where("patient_id = ?", patientID).
+		Order("start_date DESC").
+		Find(&series).Error
+	return series, err
+}
+
+// Update updates a series
+func (r *AppointmentSeriesRepository) Update(series *domain.AppointmentSeries) error {
+	return r.db.Omit("Appointments", "Patient", "Doctor").Save(series).Error
+}
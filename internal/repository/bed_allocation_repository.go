@@ -1,8 +1,6 @@
 package repository
 
 import (
-	"time"
-
 	"github.com/minhtran/his/internal/domain"
 	"gorm.io/gorm"
 )
@@ -49,7 +47,7 @@ func (r *BedAllocationRepository) FindCurrentAllocation(admissionID uint) (*doma
 
 // ReleaseAllocation releases a bed allocation
 func (r *BedAllocationRepository) ReleaseAllocation(id uint) error {
-	now := time.Now()
+	now := r.db.NowFunc()
 	return r.db.Model(&domain.BedAllocation{}).
 		Where("id = ?", id).
 		Updates(map[string]interface{}{
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/minhtran/his/internal/domain"
+	"gorm.io/gorm"
+)
+
+// BookingHoldRepository handles online booking hold data operations
+type BookingHoldRepository struct {
+	db *gorm.DB
+}
+
+// NewBookingHoldRepository creates a new booking hold repository
+func NewBookingHoldRepository(db *gorm.DB) *BookingHoldRepository {
+	return &BookingHoldRepository{db: db}
+}
+
+// FindByTokenHash finds a hold by the hash of its token
+func (r *BookingHoldRepository) FindByTokenHash(tokenHash string) (*domain.BookingHold, error) {
+	var hold domain.BookingHold
+	err := r.db.Preload("Doctor.Department").
+		Where("token_hash = ?", tokenHash).
+		First(&hold).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &hold, nil
+}
+
+// Update updates a hold
+func (r *BookingHoldRepository) Update(hold *domain.BookingHold) error {
+	return r.db.Omit("Doctor", "Appointment").Save(hold).Error
+}
+
+// ReleaseActiveByPhone releases the holds a phone number still has open
+func (r *BookingHoldRepository) ReleaseActiveByPhone(phoneNumber string, now time.Time) error {
+	return r.db.Model(&domain.BookingHold{}).
+		Where("phone_number = ? AND status IN ? AND expires_at > ?", phoneNumber, activeBookingHoldStatuses, now).
+		Update("status", domain.BookingHoldStatusReleased).Error
+}
+
+// CountByPhoneSince counts the holds placed for a phone number since a time
+func (r *BookingHoldRepository) CountByPhoneSince(phoneNumber string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.BookingHold{}).
+		Where("phone_number = ? AND created_at >= ?", phoneNumber, since).
+		Count(&count).Error
+	return count, err
+}
+
+// CountByIPSince counts the holds placed from a client address since a time
+func (r *BookingHoldRepository) CountByIPSince(clientIP string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.BookingHold{}).
+		Where("client_ip = ? AND created_at >= ?", clientIP, since).
+		Count(&count).Error
+	return count, err
+}
+
+// CountUpcomingByPhone counts the scheduled or confirmed appointments on or
+// after a date that were booked online with a phone number
+func (r *BookingHoldRepository) CountUpcomingByPhone(phoneNumber string, from time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.BookingHold{}).
+		Joins("JOIN appointments ON appointments.id = booking_holds.appointment_id AND appointments.deleted_at IS NULL").
+		Where("booking_holds.phone_number = ? AND booking_holds.status = ?", phoneNumber, domain.BookingHoldStatusConfirmed).
+		Where("appointments.status IN ?", []domain.AppointmentStatus{domain.AppointmentStatusScheduled, domain.AppointmentStatusConfirmed}).
+		Where("appointments.appointment_date >= ?", from.Format("2006-01-02")).
+		Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/minhtran/his/internal/domain"
+	"gorm.io/gorm"
+)
+
+// CalendarFeedRepository handles calendar feed data operations
+type CalendarFeedRepository struct {
+	db *gorm.DB
+}
+
+// NewCalendarFeedRepository creates a new calendar feed repository
+func NewCalendarFeedRepository(db *gorm.DB) *CalendarFeedRepository {
+	return &CalendarFeedRepository{db: db}
+}
+
+// FindActiveByUserID finds the feed of a user that has not been revoked
+func (r *CalendarFeedRepository) FindActiveByUserID(userID uint) (*domain.CalendarFeed, error) {
+	var feed domain.CalendarFeed
+	err := r.db.Where("user_id = ? AND revoked_at IS NULL", userID).
+		Order("created_at DESC").
+		First(&feed).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &feed, nil
+}
+
+// FindActiveByTokenHash finds a feed that has not been revoked by the hash of its token
+func (r *CalendarFeedRepository) FindActiveByTokenHash(tokenHash string) (*domain.CalendarFeed, error) {
+	var feed domain.CalendarFeed
+	err := r.db.Preload("User").
+		Where("token_hash = ? AND revoked_at IS NULL", tokenHash).
+		First(&feed).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &feed, nil
+}
+
+// Rotate revokes the user's active feeds and creates the new one in a transaction
+func (r *CalendarFeedRepository) Rotate(feed *domain.CalendarFeed, revokedBy uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := revokeActiveFeeds(tx, feed.UserID, revokedBy).Error; err != nil {
+			return err
+		}
+		return tx.Create(feed).Error
+	})
+}
+
+// RevokeActive revokes the user's active feeds and returns how many were revoked
+func (r *CalendarFeedRepository) RevokeActive(userID, revokedBy uint) (int64, error) {
+	result := revokeActiveFeeds(r.db, userID, revokedBy)
+	return result.RowsAffected, result.Error
+}
+
+// TouchAccess records when a feed was last fetched
+func (r *CalendarFeedRepository) TouchAccess(id uint, at time.Time) error {
+	return r.db.Model(&domain.CalendarFeed{}).
+		Where("id = ?", id).
+		UpdateColumn("last_accessed_at", at).Error
+}
+
+// revokeActiveFeeds marks a user's feeds that have not been revoked as revoked
+func revokeActiveFeeds(tx *gorm.DB, userID, revokedBy uint) *gorm.DB {
+	return tx.Model(&domain.CalendarFeed{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Updates(map[string]interface{}{"revoked_at": tx.NowFunc(), "revoked_by": revokedBy})
+}
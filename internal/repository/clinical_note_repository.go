@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/minhtran/his/internal/domain"
+	"gorm.io/gorm"
+)
+
+// ClinicalNoteRepository handles clinical note and note template data operations
+type ClinicalNoteRepository struct {
+	db *gorm.DB
+}
+
+// NewClinicalNoteRepository creates a new clinical note repository
+func NewClinicalNoteRepository(db *gorm.DB) *ClinicalNoteRepository {
+	return &ClinicalNoteRepository{db: db}
+}
+
+// CreateTemplate creates a note template
+func (r *ClinicalNoteRepository) CreateTemplate(template *domain.ClinicalNoteTemplate) error {
+	return r.db.Omit("Department").Create(template).Error
+}
+
+// UpdateTemplate updates a note template
+func (r *ClinicalNoteRepository) UpdateTemplate(template *domain.ClinicalNoteTemplate) error {
+	return r.db.Omit("Department").Save(template).Error
+}
+
+// DeleteTemplate soft deletes a note template
+func (r *ClinicalNoteRepository) DeleteTemplate(id uint) error {
+	return r.db.Delete(&domain.ClinicalNoteTemplate{}, id).Error
+}
+
+// FindTemplateByID finds a note template by ID
+func (r *ClinicalNoteRepository) FindTemplateByID(id uint) (*domain.ClinicalNoteTemplate, error) {
+	var template domain.ClinicalNoteTemplate
+	err := r.db.Preload("Department").First(&template, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &template, nil
+}
+
+// FindTemplateByCode finds a note template by code
+func (r *ClinicalNoteRepository) FindTemplateByCode(code string) (*domain.ClinicalNoteTemplate, error) {
+	var template domain.ClinicalNoteTemplate
+	err := r.db.Where("code = ?", code).First(&template).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &template, nil
+}
+
+// ListTemplates lists note templates with filters. A department filter also
+// returns the templates for all departments.
+func (r *ClinicalNoteRepository) ListTemplates(filters map[string]interface{}) ([]*domain.ClinicalNoteTemplate, error) {
+	query := r.db.Model(&domain.ClinicalNoteTemplate{}).Preload("Department")
+
+	if departmentID, ok := filters["department_id"]; ok {
+		query = query.Where("department_id = ? OR department_id IS NULL", departmentID)
+	}
+	if noteType, ok := filters["note_type"]; ok {
+		query = query.Where("note_type = ?", noteType)
+	}
+	if isActive, ok := filters["is_active"]; ok {
+		query = query.Where("is_active = ?", isActive)
+	}
+
+	var templates []*domain.ClinicalNoteTemplate
+	err := query.Order("department_id IS NULL, name ASC").Find(&templates).Error
+	return templates, err
+}
+
+// Create creates a note with its first version in one transaction
+func (r *ClinicalNoteRepository) Create(note *domain.ClinicalNote) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Visit", "Author", "Template", "Cosigner", "Addenda").Create(note).Error; err != nil {
+			return err
+		}
+		return tx.Create(&domain.ClinicalNoteVersion{
+			NoteID:       note.ID,
+			Version:      note.Version,
+			SOAPSections: note.SOAPSections,
+			EditedBy:     note.AuthorID,
+		}).Error
+	})
+}
+
+// SaveVersion saves a draft's edited sections as its next version, in one
+// transaction. It returns false, saving nothing, when the note is no longer
+// at the version it was edited from.
+func (r *ClinicalNoteRepository) SaveVersion(note *domain.ClinicalNote, editedFrom int, editedBy uint) (bool, error) {
+	saved := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.ClinicalNote{}).
+			Where("id = ? AND version = ? AND status = ?", note.ID, editedFrom, domain.ClinicalNoteStatusDraft).
+			Updates(map[string]interface{}{
+				"version":    note.Version,
+				"subjective": note.Subjective,
+				"objective":  note.Objective,
+				"assessment": note.Assessment,
+				"plan":       note.Plan,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		saved = true
+		return tx.Create(&domain.ClinicalNoteVersion{
+			NoteID:       note.ID,
+			Version:      note.Version,
+			SOAPSections: note.SOAPSections,
+			EditedBy:     editedBy,
+		}).Error
+	})
+	return saved, err
+}
+
+// Sign saves a draft's signing fields. It returns false, signing nothing,
+// when the note is no longer a draft at the version that was signed.
+func (r *ClinicalNoteRepository) Sign(note *domain.ClinicalNote) (bool, error) {
+	result := r.db.Model(&domain.ClinicalNote{}).
+		Where("id = ? AND version = ? AND status = ?", note.ID, note.Version, domain.ClinicalNoteStatusDraft).
+		Updates(map[string]interface{}{
+			"status":      note.Status,
+			"signed_at":   note.SignedAt,
+			"cosigner_id": note.CosignerID,
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+// Cosign saves a note's co-signature. It returns false, saving nothing, when
+// the note is no longer awaiting co-signature.
+func (r *ClinicalNoteRepository) Cosign(note *domain.ClinicalNote) (bool, error) {
+	result := r.db.Model(&domain.ClinicalNote{}).
+		Where("id = ? AND status = ?", note.ID, domain.ClinicalNoteStatusPendingCosign).
+		Updates(map[string]interface{}{
+			"status":      note.Status,
+			"cosigned_at": note.CosignedAt,
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+// Delete soft deletes a note
+func (r *ClinicalNoteRepository) Delete(id uint) error {
+	return r.db.Delete(&domain.ClinicalNote{}, id).Error
+}
+
+// FindByID finds a note by ID with its author, co-signer and addenda
+func (r *ClinicalNoteRepository) FindByID(id uint) (*domain.ClinicalNote, error) {
+	var note domain.ClinicalNote
+	err := r.db.Preload("Author").
+		Preload("Cosigner").
+		Preload("Addenda", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC, id ASC")
+		}).
+		Preload("Addenda.Author").
+		First(&note, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &note, nil
+}
+
+// FindByVisit finds a visit's notes, oldest first
+func (r *ClinicalNoteRepository) FindByVisit(visitID uint) ([]*domain.ClinicalNote, error) {
+	var notes []*domain.ClinicalNote
+	err := r.db.Preload("Author").
+		Preload("Cosigner").
+		Preload("Addenda", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC, id ASC")
+		}).
+		Preload("Addenda.Author").
+		Where("visit_id = ?", visitID).
+		Order("created_at ASC, id ASC").
+		Find(&notes).Error
+	return notes, err
+}
+
+// FindVersions finds a note's versions, latest first
+func (r *ClinicalNoteRepository) FindVersions(noteID uint) ([]*domain.ClinicalNoteVersion, error) {
+	var versions []*domain.ClinicalNoteVersion
+	err := r.db.Preload("Editor").
+		Where("note_id = ?", noteID).
+		Order("version DESC").
+		Find(&versions).Error
+	return versions, err
+}
+
+// CreateAddendum appends an addendum to a note
+func (r *ClinicalNoteRepository) CreateAddendum(addendum *domain.ClinicalNoteAddendum) error {
+	return r.db.Omit("Author").Create(addendum).Error
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/minhtran/his/internal/domain"
+	"gorm.io/gorm"
+)
+
+// DeathRecordRepository handles death record data operations
+type DeathRecordRepository struct {
+	db *gorm.DB
+}
+
+// NewDeathRecordRepository creates a new death record repository
+func NewDeathRecordRepository(db *gorm.DB) *DeathRecordRepository {
+	return &DeathRecordRepository{db: db}
+}
+
+// FindByID finds a death record by ID with cause chain
+func (r *DeathRecordRepository) FindByID(id uint) (*domain.DeathRecord, error) {
+	var record domain.DeathRecord
+	err := r.preload(r.db).First(&record, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &record, nil
+}
+
+// FindByPatientID finds the death record of a patient
+func (r *DeathRecordRepository) FindByPatientID(patientID uint) (*domain.DeathRecord, error) {
+	var record domain.DeathRecord
+	err := r.preload(r.db).Where("patient_id = ?", patientID).First(&record).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &record, nil
+}
+
+// GenerateCertificateNumber generates a unique death certificate number
+func (r *DeathRecordRepository) GenerateCertificateNumber() (string, error) {
+	today := r.db.NowFunc().Format("20060102") // YYYYMMDD
+	prefix := fmt.Sprintf("DC-%s-", today)
+
+	var lastRecord domain.DeathRecord
+	err := r.db.Unscoped().Where("certificate_number LIKE ?", prefix+"%").
+		Order("certificate_number DESC").
+		First(&lastRecord).Error
+
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", err
+	}
+
+	sequence := 1
+	if lastRecord.CertificateNumber != "" {
+		var lastSeq int
+		fmt.Sscanf(lastRecord.CertificateNumber, prefix+"%d", &lastSeq)
+		sequence = lastSeq + 1
+	}
+
+	return fmt.Sprintf("%s%04d", prefix, sequence), nil
+}
+
+// preload applies the relationships needed to render a death record
+func (r *DeathRecordRepository) preload(db *gorm.DB) *gorm.DB {
+	return db.Preload("Patient").
+		Preload("Admission").
+		Preload("CertifyingDoctor").
+		Preload("UnderlyingCause").
+		Preload("Causes", func(db *gorm.DB) *gorm.DB {
+			return db.Order("FIELD(line, 'A', 'B', 'C', 'D', 'CONTRIBUTING')")
+		}).
+		Preload("Causes.ICD10Code")
+}
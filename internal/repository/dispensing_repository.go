@@ -3,7 +3,6 @@ package repository
 import (
 	"errors"
 	"fmt"
-	"time"
 
 	"github.com/minhtran/his/internal/domain"
 	"gorm.io/gorm"
@@ -48,7 +47,7 @@ func (r *DispensingRepository) FindByPatientID(patientID uint) ([]*domain.Dispen
 
 // GenerateDispensingCode generates a unique dispensing code
 func (r *DispensingRepository) GenerateDispensingCode() (string, error) {
-	today := time.Now().Format("20060102") // YYYYMMDD
+	today := r.db.NowFunc().Format("20060102") // YYYYMMDD
 	prefix := fmt.Sprintf("DIS-%s-", today)
 
 	var lastDispensing domain.Dispensing
@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/minhtran/his/internal/domain"
+	"gorm.io/gorm"
+)
+
+// DoctorScheduleRepository handles doctor schedule, override, leave and holiday data operations
+type DoctorScheduleRepository struct {
+	db *gorm.DB
+}
+
+// NewDoctorScheduleRepository creates a new doctor schedule repository
+func NewDoctorScheduleRepository(db *gorm.DB) *DoctorScheduleRepository {
+	return &DoctorScheduleRepository{db: db}
+}
+
+// CreateSchedule creates a new weekly schedule session
+func (r *DoctorScheduleRepository) CreateSchedule(schedule *domain.DoctorSchedule) error {
+	return r.db.Create(schedule).Error
+}
+
+// FindScheduleByID finds a weekly schedule session by ID
+func (r *DoctorScheduleRepository) FindScheduleByID(id uint) (*domain.DoctorSchedule, error) {
+	var schedule domain.DoctorSchedule
+	err := r.db.First(&schedule, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// FindSchedulesByDoctor finds all weekly schedule sessions of a doctor
+func (r *DoctorScheduleRepository) FindSchedulesByDoctor(doctorID uint) ([]*domain.DoctorSchedule, error) {
+	var schedules []*domain.DoctorSchedule
+	err := r.db.Where("doctor_id = ?", doctorID).
+		Order("day_of_week ASC, start_time ASC").
+		Find(&schedules).Error
+	return schedules, err
+}
+
+// FindSchedulesForDate finds the active weekly sessions of a doctor that apply on a date
+func (r *DoctorScheduleRepository) FindSchedulesForDate(doctorID uint, date time.Time) ([]*domain.DoctorSchedule, error) {
+	var schedules []*domain.DoctorSchedule
+	dateStr := date.Format("2006-01-02")
+	err := r.db.Where("doctor_id = ? AND day_of_week = ? AND is_active = ?", doctorID, int(date.Weekday()), true).
+		Where("effective_from <= ?", dateStr).
+		Where("effective_to IS NULL OR effective_to >= ?", dateStr).
+		Order("start_time ASC").
+		Find(&schedules).Error
+	return schedules, err
+}
+
+// FindSchedulesForDoctors finds the active weekly sessions of the doctors
+// that are in effect at some point between two dates (inclusive)
+func (r *DoctorScheduleRepository) FindSchedulesForDoctors(doctorIDs []uint, from, to time.Time) ([]*domain.DoctorSchedule, error) {
+	var schedules []*domain.DoctorSchedule
+	err := r.db.Where("doctor_id IN ? AND is_active = ?", doctorIDs, true).
+		Where("effective_from <= ?", to.Format("2006-01-02")).
+		Where("effective_to IS NULL OR effective_to >= ?", from.Format("2006-01-02")).
+		Order("doctor_id ASC, start_time ASC").
+		Find(&schedules).Error
+	return schedules, err
+}
+
+// UpdateSchedule updates a weekly schedule session
+func (r *DoctorScheduleRepository) UpdateSchedule(schedule *domain.DoctorSchedule) error {
+	return r.db.Save(schedule).Error
+}
+
+// DeleteSchedule soft deletes a weekly schedule session
+func (r *DoctorScheduleRepository) DeleteSchedule(id uint) error {
+	return r.db.Delete(&domain.DoctorSchedule{}, id).Error
+}
+
+// CreateOverride creates a date-specific schedule override
+func (r *DoctorScheduleRepository) CreateOverride(override *domain.DoctorScheduleOverride) error {
+	return r.db.Create(override).Error
+}
+
+// FindOverrideByID finds a schedule override by ID
+func (r *DoctorScheduleRepository) FindOverrideByID(id uint) (*domain.DoctorScheduleOverride, error) {
+	var override domain.DoctorScheduleOverride
+	err := r.db.First(&override, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &override, nil
+}
+
+// FindOverridesByDoctor finds a doctor's schedule overrides within a date range
+func (r *DoctorScheduleRepository) FindOverridesByDoctor(doctorID uint, from, to time.Time) ([]*domain.DoctorScheduleOverride, error) {
+	var overrides []*domain.DoctorScheduleOverride
+	err := r.db.Where("doctor_id = ?", doctorID).
+		Where("override_date BETWEEN ? AND ?", from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Order("override_date ASC, start_time ASC").
+		Find(&overrides).Error
+	return overrides, err
+}
+
+// FindOverridesForDate finds a doctor's schedule overrides on a date
+func (r *DoctorScheduleRepository) FindOverridesForDate(doctorID uint, date time.Time) ([]*domain.DoctorScheduleOverride, error) {
+	var overrides []*domain.DoctorScheduleOverride
+	err := r.db.Where("doctor_id = ? AND override_date = ?", doctorID, date.Format("2006-01-02")).
+		Order("start_time ASC").
+		Find(&overrides).Error
+	return overrides, err
+}
+
+// FindOverridesForDoctors finds the doctors' schedule overrides within a date range
+func (r *DoctorScheduleRepository) FindOverridesForDoctors(doctorIDs []uint, from, to time.Time) ([]*domain.DoctorScheduleOverride, error) {
+	var overrides []*domain.DoctorScheduleOverride
+	err := r.db.Where("doctor_id IN ?", doctorIDs).
+		Where("override_date BETWEEN ? AND ?", from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Order("doctor_id ASC, override_date ASC, start_time ASC").
+		Find(&overrides).Error
+	return overrides, err
+}
+
+// DeleteOverride soft deletes a schedule override
+func (r *DoctorScheduleRepository) DeleteOverride(id uint) error {
+	return r.db.Delete(&domain.DoctorScheduleOverride{}, id).Error
+}
+
+// CreateLeave creates a leave period
+func (r *DoctorScheduleRepository) CreateLeave(leave *domain.DoctorLeave) error {
+	return r.db.Create(leave).Error
+}
+
+// FindLeaveByID finds a leave period by ID
+func (r *DoctorScheduleRepository) FindLeaveByID(id uint) (*domain.DoctorLeave, error) {
+	var leave domain.DoctorLeave
+	err := r.db.First(&leave, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &leave, nil
+}
+
+// FindLeavesByDoctor finds a doctor's leave periods overlapping a date range
+func (r *DoctorScheduleRepository) FindLeavesByDoctor(doctorID uint, from, to time.Time) ([]*domain.DoctorLeave, error) {
+	var leaves []*domain.DoctorLeave
+	err := r.db.Where("doctor_id = ?", doctorID).
+		Where("start_date <= ? AND end_date >= ?", to.Format("2006-01-02"), from.Format("2006-01-02")).
+		Order("start_date ASC").
+		Find(&leaves).Error
+	return leaves, err
+}
+
+// FindLeaveOnDate finds a leave period of a doctor covering a date
+func (r *DoctorScheduleRepository) FindLeaveOnDate(doctorID uint, date time.Time) (*domain.DoctorLeave, error) {
+	var leave domain.DoctorLeave
+	dateStr := date.Format("2006-01-02")
+	err := r.db.Where("doctor_id = ? AND start_date <= ? AND end_date >= ?", doctorID, dateStr, dateStr).
+		First(&leave).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &leave, nil
+}
+
+// FindLeavesForDoctors finds the doctors' leave periods overlapping a date range
+func (r *DoctorScheduleRepository) FindLeavesForDoctors(doctorIDs []uint, from, to time.Time) ([]*domain.DoctorLeave, error) {
+	var leaves []*domain.DoctorLeave
+	err := r.db.Where("doctor_id IN ?", doctorIDs).
+		Where("start_date <= ? AND end_date >= ?", to.Format("2006-01-02"), from.Format("2006-01-02")).
+		Order("doctor_id ASC, start_date ASC").
+		Find(&leaves).Error
+	return leaves, err
+}
+
+// DeleteLeave soft deletes a leave period
+func (r *DoctorScheduleRepository) DeleteLeave(id uint) error {
+	return r.db.Delete(&domain.DoctorLeave{}, id).Error
+}
+
+// CreateHoliday creates a holiday
+func (r *DoctorScheduleRepository) CreateHoliday(holiday *domain.Holiday) error {
+	return r.db.Create(holiday).Error
+}
+
+// FindHolidayByID finds a holiday by ID
+func (r *DoctorScheduleRepository) FindHolidayByID(id uint) (*domain.Holiday, error) {
+	var holiday domain.Holiday
+	err := r.db.First(&holiday, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &holiday, nil
+}
+
+// ListHolidays lists holidays falling in a year, including recurring ones
+func (r *DoctorScheduleRepository) ListHolidays(year int) ([]*domain.Holiday, error) {
+	var holidays []*domain.Holiday
+	err := r.db.Where("YEAR(holiday_date) = ? OR is_recurring = ?", year, true).
+		Order("MONTH(holiday_date) ASC, DAY(holiday_date) ASC").
+		Find(&holidays).Error
+	return holidays, err
+}
+
+// FindHolidayOnDate finds a holiday on a date, matching recurring holidays by day and month
+func (r *DoctorScheduleRepository) FindHolidayOnDate(date time.Time) (*domain.Holiday, error) {
+	var holiday domain.Holiday
+	err := r.db.Where("holiday_date = ? OR (is_recurring = ? AND MONTH(holiday_date) = ? AND DAY(holiday_date) = ?)",
+		date.Format("2006-01-02"), true, int(date.Month()), date.Day()).
+		First(&holiday).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &holiday, nil
+}
+
+// FindHolidaysBetween finds holidays within a date range together with all
+// recurring holidays, which the caller matches by day and month
+func (r *DoctorScheduleRepository) FindHolidaysBetween(from, to time.Time) ([]*domain.Holiday, error) {
+	var holidays []*domain.Holiday
+	err := r.db.Where("holiday_date BETWEEN ? AND ? OR is_recurring = ?", from.Format("2006-01-02"), to.Format("2006-01-02"), true).
+		Order("holiday_date ASC").
+		Find(&holidays).Error
+	return holidays, err
+}
+
+// DeleteHoliday soft deletes a holiday
+func (r *DoctorScheduleRepository) DeleteHoliday(id uint) error {
+	return r.db.Delete(&domain.Holiday{}, id).Error
+}
@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/minhtran/his/internal/domain"
+	"gorm.io/gorm"
+)
+
+// FollowUpRepository handles follow-up data operations
+type FollowUpRepository struct {
+	db *gorm.DB
+}
+
+// NewFollowUpRepository creates a new follow-up repository
+func NewFollowUpRepository(db *gorm.DB) *FollowUpRepository {
+	return &FollowUpRepository{db: db}
+}
+
+// Create creates a follow-up
+func (r *FollowUpRepository) Create(followUp *domain.FollowUp) error {
+	return r.db.Omit("Visit", "Patient", "Doctor", "Appointment").Create(followUp).Error
+}
+
+// Update updates a follow-up
+func (r *FollowUpRepository) Update(followUp *domain.FollowUp) error {
+	return r.db.Omit("Visit", "Patient", "Doctor", "Appointment").Save(followUp).Error
+}
+
+// FindByID finds a follow-up by ID
+func (r *FollowUpRepository) FindByID(id uint) (*domain.FollowUp, error) {
+	var followUp domain.FollowUp
+	err := r.listQuery().First(&followUp, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &followUp, nil
+}
+
+// FindByVisit finds the follow-up ordered in a visit
+func (r *FollowUpRepository) FindByVisit(visitID uint) (*domain.FollowUp, error) {
+	var followUp domain.FollowUp
+	err := r.listQuery().Where("visit_id = ?", visitID).First(&followUp).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &followUp, nil
+}
+
+// List lists follow-ups with filters, soonest due first
+func (r *FollowUpRepository) List(filters map[string]interface{}, page, pageSize int) ([]*domain.FollowUp, int64, error) {
+	var followUps []*domain.FollowUp
+	var total int64
+
+	offset := (page - 1) * pageSize
+	query := r.listQuery()
+
+	if patientID, ok := filters["patient_id"]; ok && patientID != "" {
+		query = query.Where("patient_id = ?", patientID)
+	}
+	if doctorID, ok := filters["doctor_id"]; ok && doctorID != "" {
+		query = query.Where("doctor_id = ?", doctorID)
+	}
+	if status, ok := filters["status"]; ok && status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if dueFrom, ok := filters["due_from"]; ok && dueFrom != "" {
+		query = query.Where("due_date >= ?", dueFrom)
+	}
+	if dueTo, ok := filters["due_to"]; ok && dueTo != "" {
+		query = query.Where("due_date <= ?", dueTo)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Offset(offset).
+		Limit(pageSize).
+		Order("due_date ASC, id ASC").
+		Find(&followUps).Error
+
+	return followUps, total, err
+}
+
+// FindAwaitingReception finds the follow-ups reception has to confirm or
+// book, soonest due first
+func (r *FollowUpRepository) FindAwaitingReception() ([]*domain.FollowUp, error) {
+	var followUps []*domain.FollowUp
+	err := r.listQuery().
+		Where("status IN ?", []domain.FollowUpStatus{domain.FollowUpStatusPending, domain.FollowUpStatusUnscheduled}).
+		Order("due_date ASC, id ASC").
+		Find(&followUps).Error
+	return followUps, err
+}
+
+// FindOpenByAppointment finds the open follow-up booked as an appointment
+func (r *FollowUpRepository) FindOpenByAppointment(appointmentID uint) (*domain.FollowUp, error) {
+	var followUp domain.FollowUp
+	err := r.db.Where("appointment_id = ? AND status IN ?", appointmentID,
+		[]domain.FollowUpStatus{domain.FollowUpStatusPending, domain.FollowUpStatusConfirmed}).
+		First(&followUp).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &followUp, nil
+}
+
+// FindUnscheduled finds a patient's oldest unscheduled follow-up with a doctor
+func (r *FollowUpRepository) FindUnscheduled(patientID, doctorID uint) (*domain.FollowUp, error) {
+	var followUp domain.FollowUp
+	err := r.db.Where("patient_id = ? AND doctor_id = ? AND status = ?", patientID, doctorID, domain.FollowUpStatusUnscheduled).
+		Order("due_date ASC").
+		First(&followUp).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &followUp, nil
+}
+
+// FindSettled finds the booked follow-ups whose appointment has moved on
+// since: pending ones whose appointment is no longer scheduled and confirmed
+// ones whose appointment is no longer scheduled or confirmed. The appointment
+// is loaded.
+func (r *FollowUpRepository) FindSettled() ([]*domain.FollowUp, error) {
+	var followUps []*domain.FollowUp
+	err := r.db.Preload("Appointment").
+		Joins("JOIN appointments ON appointments.id = follow_ups.appointment_id").
+		Where("(follow_ups.status = ? AND appointments.status <> ?) OR (follow_ups.status = ? AND appointments.status NOT IN ?)",
+			domain.FollowUpStatusPending, domain.AppointmentStatusScheduled,
+			domain.FollowUpStatusConfirmed, []domain.AppointmentStatus{domain.AppointmentStatusScheduled, domain.AppointmentStatusConfirmed}).
+		Find(&followUps).Error
+	return followUps, err
+}
+
+// FindUnscheduledDueBefore finds the unscheduled follow-ups due before a date
+func (r *FollowUpRepository) FindUnscheduledDueBefore(date time.Time) ([]*domain.FollowUp, error) {
+	var followUps []*domain.FollowUp
+	err := r.db.Where("status = ? AND due_date < ?", domain.FollowUpStatusUnscheduled, date.Format("2006-01-02")).
+		Find(&followUps).Error
+	return followUps, err
+}
+
+// CountPatientOutcomes counts a patient's follow-ups due within [from, to] by status
+func (r *FollowUpRepository) CountPatientOutcomes(patientID uint, from, to time.Time) (map[domain.FollowUpStatus]int64, error) {
+	return r.countOutcomes("patient_id", patientID, from, to)
+}
+
+// CountDoctorOutcomes counts the follow-ups a doctor ordered due within [from, to] by status
+func (r *FollowUpRepository) CountDoctorOutcomes(doctorID uint, from, to time.Time) (map[domain.FollowUpStatus]int64, error) {
+	return r.countOutcomes("doctor_id", doctorID, from, to)
+}
+
+// countOutcomes counts follow-ups of a patient or doctor by status
+func (r *FollowUpRepository) countOutcomes(column string, id uint, from, to time.Time) (map[domain.FollowUpStatus]int64, error) {
+	var rows []struct {
+		Status domain.FollowUpStatus
+		Count  int64
+	}
+	err := r.db.Model(&domain.FollowUp{}).
+		Select("status, COUNT(*) AS count").
+		Where(column+" = ?", id).
+		Where("due_date BETWEEN ? AND ?", from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[domain.FollowUpStatus]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *FollowUpRepository) listQuery() *gorm.DB {
+	return r.db.Model(&domain.FollowUp{}).
+		Preload("Visit").
+		Preload("Patient").
+		Preload("Doctor").
+		Preload("Appointment")
+}
@@ -3,7 +3,6 @@ package repository
 import (
 	"errors"
 	"fmt"
-	"time"
 
 	"github.com/minhtran/his/internal/domain"
 	"gorm.io/gorm"
@@ -84,6 +83,9 @@ func (r *ImagingRequestRepository) FindByPatientID(patientID uint, filters map[s
 	if toDate, ok := filters["to_date"]; ok && toDate != "" {
 		query = query.Where("requested_date <= ?", toDate)
 	}
+	if released, ok := filters["portal_released"]; ok && released == true {
+		query = query.Where("portal_released_at IS NOT NULL")
+	}
 
 	err := query.Order("requested_date DESC").Find(&requests).Error
 	return requests, err
@@ -94,6 +96,19 @@ func (r *ImagingRequestRepository) Update(request *domain.ImagingRequest) error
 	return r.db.Save(request).Error
 }
 
+// WithResourceLock runs fn in a transaction holding row locks on the resources
+// to reserve, taken in ID order, so that checking the resources are free and
+// booking them cannot interleave with another booking. fn must run its queries
+// through the repositories it is given, which are bound to the transaction.
+func (r *ImagingRequestRepository) WithResourceLock(resourceIDs []uint, fn func(repo *ImagingRequestRepository, resources *ResourceRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockResources(tx, resourceIDs); err != nil {
+			return err
+		}
+		return fn(&ImagingRequestRepository{db: tx}, &ResourceRepository{db: tx})
+	})
+}
+
 // Delete soft deletes a request
 func (r *ImagingRequestRepository) Delete(id uint) error {
 	return r.db.Delete(&domain.ImagingRequest{}, id).Error
@@ -101,7 +116,7 @@ func (r *ImagingRequestRepository) Delete(id uint) error {
 
 // GenerateRequestCode generates a unique request code
 func (r *ImagingRequestRepository) GenerateRequestCode() (string, error) {
-	today := time.Now().Format("20060102") // YYYYMMDD
+	today := r.db.NowFunc().Format("20060102") // YYYYMMDD
 	prefix := fmt.Sprintf("IMG-%s-", today)
 
 	var lastRequest domain.ImagingRequest
@@ -3,7 +3,6 @@ package repository
 import (
 	"errors"
 	"fmt"
-	"time"
 
 	"github.com/minhtran/his/internal/domain"
 	"gorm.io/gorm"
@@ -65,7 +64,7 @@ func (r *InsuranceClaimRepository) Update(claim *domain.InsuranceClaim) error {
 
 // GenerateClaimCode generates a unique claim code
 func (r *InsuranceClaimRepository) GenerateClaimCode() (string, error) {
-	today := time.Now().Format("20060102") // YYYYMMDD
+	today := r.db.NowFunc().Format("20060102") // YYYYMMDD
 	prefix := fmt.Sprintf("CLM-%s-", today)
 
 	var lastClaim domain.InsuranceClaim
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/minhtran/his/internal/domain"
+	"gorm.io/gorm"
+)
+
+// InsurancePayerRepository handles insurance payer data operations
+type InsurancePayerRepository struct {
+	db *gorm.DB
+}
+
+// NewInsurancePayerRepository creates a new insurance payer repository
+func NewInsurancePayerRepository(db *gorm.DB) *InsurancePayerRepository {
+	return &InsurancePayerRepository{db: db}
+}
+
+// Create creates a new insurance payer
+func (r *InsurancePayerRepository) Create(payer *domain.InsurancePayer) error {
+	return r.db.Create(payer).Error
+}
+
+// FindByID finds an insurance payer by ID
+func (r *InsurancePayerRepository) FindByID(id uint) (*domain.InsurancePayer, error) {
+	var payer domain.InsurancePayer
+	err := r.db.First(&payer, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &payer, nil
+}
+
+// FindByCode finds an insurance payer by code
+func (r *InsurancePayerRepository) FindByCode(code string) (*domain.InsurancePayer, error) {
+	var payer domain.InsurancePayer
+	err := r.db.Where("code = ?", code).First(&payer).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &payer, nil
+}
+
+// Update updates an insurance payer
+func (r *InsurancePayerRepository) Update(payer *domain.InsurancePayer) error {
+	return r.db.Save(payer).Error
+}
+
+// Delete soft deletes an insurance payer
+func (r *InsurancePayerRepository) Delete(id uint) error {
+	return r.db.Delete(&domain.InsurancePayer{}, id).Error
+}
+
+// List returns a paginated list of insurance payers
+func (r *InsurancePayerRepository) List(page, pageSize int) ([]*domain.InsurancePayer, int64, error) {
+	var payers []*domain.InsurancePayer
+	var total int64
+
+	offset := (page - 1) * pageSize
+
+	if err := r.db.Model(&domain.InsurancePayer{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := r.db.Offset(offset).
+		Limit(pageSize).
+		Order("name ASC").
+		Find(&payers).Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return payers, total, nil
+}
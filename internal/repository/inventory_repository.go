@@ -42,12 +42,12 @@ func (r *InventoryRepository) FindLowStock(threshold int) ([]*domain.Inventory,
 	return inventories, err
 }
 
-// FindExpiringSoon finds items expiring soon
-func (r *InventoryRepository) FindExpiringSoon(days int) ([]*domain.Inventory, error) {
+// FindExpiringSoon finds items still usable on today that expire within the given days
+func (r *InventoryRepository) FindExpiringSoon(today time.Time, days int) ([]*domain.Inventory, error) {
 	var inventories []*domain.Inventory
-	expiryDate := time.Now().AddDate(0, 0, days)
 	err := r.db.Preload("Medication").
-		Where("expiry_date <= ? AND expiry_date > ? AND quantity > 0", expiryDate, time.Now()).
+		Where("expiry_date <= ? AND expiry_date > ? AND quantity > 0",
+			today.AddDate(0, 0, days).Format("2006-01-02"), today.Format("2006-01-02")).
 		Order("expiry_date ASC").
 		Find(&inventories).Error
 	return inventories, err
@@ -58,11 +58,11 @@ func (r *InventoryRepository) UpdateQuantity(id uint, quantity int) error {
 	return r.db.Model(&domain.Inventory{}).Where("id = ?", id).Update("quantity", quantity).Error
 }
 
-// FindAvailableStock finds available inventory for dispensing (FIFO)
-func (r *InventoryRepository) FindAvailableStock(medicationID uint, quantityNeeded int) (*domain.Inventory, error) {
+// FindAvailableStock finds inventory still usable on today for dispensing (FIFO)
+func (r *InventoryRepository) FindAvailableStock(medicationID uint, quantityNeeded int, today time.Time) (*domain.Inventory, error) {
 	var inventory domain.Inventory
 	err := r.db.Where("medication_id = ? AND quantity >= ? AND expiry_date > ?",
-		medicationID, quantityNeeded, time.Now()).
+		medicationID, quantityNeeded, today.Format("2006-01-02")).
 		Order("expiry_date ASC, received_date ASC"). // FIFO
 		First(&inventory).Error
 	if err != nil {
@@ -3,10 +3,10 @@ package repository
 import (
 	"errors"
 	"fmt"
-	"time"
 
 	"github.com/minhtran/his/internal/domain"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // InvoiceRepository handles invoice data operations
@@ -19,6 +19,18 @@ func NewInvoiceRepository(db *gorm.DB) *InvoiceRepository {
 	return &InvoiceRepository{db: db}
 }
 
+// WithVisitLock runs fn in a transaction holding a lock on the visit's row, so
+// invoices drafted for the same visit are checked and created one at a time
+func (r *InvoiceRepository) WithVisitLock(visitID uint, fn func(repo *InvoiceRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var visit domain.Visit
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&visit, visitID).Error; err != nil {
+			return err
+		}
+		return fn(&InvoiceRepository{db: tx})
+	})
+}
+
 // Create creates invoice with items
 func (r *InvoiceRepository) Create(invoice *domain.Invoice) error {
 	return r.db.Create(invoice).Error
@@ -71,6 +83,20 @@ func (r *InvoiceRepository) FindByPatientID(patientID uint) ([]*domain.Invoice,
 	return invoices, err
 }
 
+// FindActiveByVisitID finds a visit's invoice that has not been cancelled
+func (r *InvoiceRepository) FindActiveByVisitID(visitID uint) (*domain.Invoice, error) {
+	var invoice domain.Invoice
+	err := r.db.Where("visit_id = ? AND status <> ?", visitID, domain.InvoiceStatusCancelled).
+		First(&invoice).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &invoice, nil
+}
+
 // Update updates invoice
 func (r *InvoiceRepository) Update(invoice *domain.Invoice) error {
 	return r.db.Save(invoice).Error
@@ -78,7 +104,7 @@ func (r *InvoiceRepository) Update(invoice *domain.Invoice) error {
 
 // GenerateInvoiceCode generates a unique invoice code
 func (r *InvoiceRepository) GenerateInvoiceCode() (string, error) {
-	today := time.Now().Format("20060102") // YYYYMMDD
+	today := r.db.NowFunc().Format("20060102") // YYYYMMDD
 	prefix := fmt.Sprintf("INV-%s-", today)
 
 	var lastInvoice domain.Invoice
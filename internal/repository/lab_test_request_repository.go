@@ -3,7 +3,6 @@ package repository
 import (
 	"errors"
 	"fmt"
-	"time"
 
 	"github.com/minhtran/his/internal/domain"
 	"gorm.io/gorm"
@@ -86,6 +85,9 @@ func (r *LabTestRequestRepository) FindByPatientID(patientID uint, filters map[s
 	if toDate, ok := filters["to_date"]; ok && toDate != "" {
 		query = query.Where("requested_date <= ?", toDate)
 	}
+	if released, ok := filters["portal_released"]; ok && released == true {
+		query = query.Where("portal_released_at IS NOT NULL")
+	}
 
 	err := query.Order("requested_date DESC").Find(&requests).Error
 	return requests, err
@@ -103,7 +105,7 @@ func (r *LabTestRequestRepository) Delete(id uint) error {
 
 // GenerateRequestCode generates a unique request code
 func (r *LabTestRequestRepository) GenerateRequestCode() (string, error) {
-	today := time.Now().Format("20060102") // YYYYMMDD
+	today := r.db.NowFunc().Format("20060102") // YYYYMMDD
 	prefix := fmt.Sprintf("LTR-%s-", today)
 
 	var lastRequest domain.LabTestRequest
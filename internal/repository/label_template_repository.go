@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/minhtran/his/internal/domain"
+	"gorm.io/gorm"
+)
+
+// LabelTemplateRepository handles label template data operations
+type LabelTemplateRepository struct {
+	db *gorm.DB
+}
+
+// NewLabelTemplateRepository creates a new label template repository
+func NewLabelTemplateRepository(db *gorm.DB) *LabelTemplateRepository {
+	return &LabelTemplateRepository{db: db}
+}
+
+// Create creates a new label template
+func (r *LabelTemplateRepository) Create(template *domain.LabelTemplate) error {
+	return r.db.Create(template).Error
+}
+
+// FindByID finds a label template by ID
+func (r *LabelTemplateRepository) FindByID(id uint) (*domain.LabelTemplate, error) {
+	var template domain.LabelTemplate
+	err := r.db.First(&template, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &template, nil
+}
+
+// FindByCode finds a label template by code
+func (r *LabelTemplateRepository) FindByCode(code string) (*domain.LabelTemplate, error) {
+	var template domain.LabelTemplate
+	err := r.db.Where("code = ?", code).First(&template).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &template, nil
+}
+
+// FindDefault finds the active default template for a target type, falling
+// back to any active template of that type
+func (r *LabelTemplateRepository) FindDefault(targetType domain.LabelTargetType) (*domain.LabelTemplate, error) {
+	var template domain.LabelTemplate
+	err := r.db.Where("target_type = ? AND is_active = ?", targetType, true).
+		Order("is_default DESC, id ASC").
+		First(&template).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &template, nil
+}
+
+// ClearDefault unsets the default flag on all templates of a target type
+func (r *LabelTemplateRepository) ClearDefault(targetType domain.LabelTargetType) error {
+	return r.db.Model(&domain.LabelTemplate{}).
+		Where("target_type = ? AND is_default = ?", targetType, true).
+		Update("is_default", false).Error
+}
+
+// Update updates a label template
+func (r *LabelTemplateRepository) Update(template *domain.LabelTemplate) error {
+	return r.db.Save(template).Error
+}
+
+// Delete soft deletes a label template
+func (r *LabelTemplateRepository) Delete(id uint) error {
+	return r.db.Delete(&domain.LabelTemplate{}, id).Error
+}
+
+// List returns a paginated list of label templates, optionally filtered by target type
+func (r *LabelTemplateRepository) List(targetType string, page, pageSize int) ([]*domain.LabelTemplate, int64, error) {
+	var templates []*domain.LabelTemplate
+	var total int64
+
+	offset := (page - 1) * pageSize
+
+	query := r.db.Model(&domain.LabelTemplate{})
+	if targetType != "" {
+		query = query.Where("target_type = ?", targetType)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Offset(offset).
+		Limit(pageSize).
+		Order("target_type ASC, name ASC").
+		Find(&templates).Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return templates, total, nil
+}
@@ -48,6 +48,21 @@ func (r *MedicalServiceRepository) FindByCode(code string) (*domain.MedicalServi
 	return &service, nil
 }
 
+// FindConsultation finds a department's active consultation service
+func (r *MedicalServiceRepository) FindConsultation(departmentID uint) (*domain.MedicalService, error) {
+	var service domain.MedicalService
+	err := r.db.Where("department_id = ? AND service_type = ? AND is_active = ?", departmentID, domain.ServiceTypeConsultation, true).
+		Order("id ASC").
+		First(&service).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &service, nil
+}
+
 // Update updates a medical service
 func (r *MedicalServiceRepository) Update(service *domain.MedicalService) error {
 	return r.db.Save(service).Error
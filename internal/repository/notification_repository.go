@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/minhtran/his/internal/domain"
+	"gorm.io/gorm"
+)
+
+// NotificationRepository handles notification and notification template data operations
+type NotificationRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository creates a new notification repository
+func NewNotificationRepository(db *gorm.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// CreateTemplate creates a new notification template
+func (r *NotificationRepository) CreateTemplate(template *domain.NotificationTemplate) error {
+	return r.db.Create(template).Error
+}
+
+// FindTemplateByID finds a notification template by ID
+func (r *NotificationRepository) FindTemplateByID(id uint) (*domain.NotificationTemplate, error) {
+	var template domain.NotificationTemplate
+	err := r.db.First(&template, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &template, nil
+}
+
+// FindTemplate finds the template of a code for a channel and language, active or not
+func (r *NotificationRepository) FindTemplate(code string, channel domain.NotificationChannel, language string) (*domain.NotificationTemplate, error) {
+	var template domain.NotificationTemplate
+	err := r.db.Where("code = ? AND channel = ? AND language = ?", code, channel, language).First(&template).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &template, nil
+}
+
+// ListTemplates lists notification templates, optionally of one code
+func (r *NotificationRepository) ListTemplates(code string) ([]*domain.NotificationTemplate, error) {
+	var templates []*domain.NotificationTemplate
+	query := r.db.Model(&domain.NotificationTemplate{})
+	if code != "" {
+		query = query.Where("code = ?", code)
+	}
+	err := query.Order("code ASC, channel ASC, language ASC").Find(&templates).Error
+	return templates, err
+}
+
+// UpdateTemplate updates a notification template
+func (r *NotificationRepository) UpdateTemplate(template *domain.NotificationTemplate) error {
+	return r.db.Save(template).Error
+}
+
+// Create queues a notification
+func (r *NotificationRepository) Create(notification *domain.Notification) error {
+	return r.db.Create(notification).Error
+}
+
+// ExistsByDedupeKey checks whether a notification with the dedupe key was already queued
+func (r *NotificationRepository) ExistsByDedupeKey(key string) (bool, error) {
+	var count int64
+	err := r.db.Model(&domain.Notification{}).Where("dedupe_key = ?", key).Count(&count).Error
+	return count > 0, err
+}
+
+// FindByID finds a notification by ID
+func (r *NotificationRepository) FindByID(id uint) (*domain.Notification, error) {
+	var notification domain.Notification
+	err := r.db.First(&notification, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &notification, nil
+}
+
+// List lists notifications with filters, newest first
+func (r *NotificationRepository) List(filters map[string]interface{}, page, pageSize int) ([]*domain.Notification, int64, error) {
+	var notifications []*domain.Notification
+	var total int64
+
+	offset := (page - 1) * pageSize
+	query := r.db.Model(&domain.Notification{})
+
+	if status, ok := filters["status"]; ok && status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if channel, ok := filters["channel"]; ok && channel != "" {
+		query = query.Where("channel = ?", channel)
+	}
+	if templateCode, ok := filters["template_code"]; ok && templateCode != "" {
+		query = query.Where("template_code = ?", templateCode)
+	}
+	if patientID, ok := filters["patient_id"]; ok && patientID != "" {
+		query = query.Where("patient_id = ?", patientID)
+	}
+	if appointmentID, ok := filters["appointment_id"]; ok && appointmentID != "" {
+		query = query.Where("appointment_id = ?", appointmentID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Offset(offset).
+		Limit(pageSize).
+		Order("created_at DESC").
+		Find(&notifications).Error
+
+	return notifications, total, err
+}
+
+// FindDue finds pending notifications whose next attempt is due, oldest first
+func (r *NotificationRepository) FindDue(now time.Time, limit int) ([]*domain.Notification, error) {
+	var notifications []*domain.Notification
+	err := r.db.Where("status = ? AND next_attempt_at <= ?", domain.NotificationStatusPending, now).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		Find(&notifications).Error
+	return notifications, err
+}
+
+// Update updates a notification
+func (r *NotificationRepository) Update(notification *domain.Notification) error {
+	return r.db.Save(notification).Error
+}
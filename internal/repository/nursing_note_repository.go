@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"errors"
+
 	"github.com/minhtran/his/internal/domain"
 	"gorm.io/gorm"
 )
@@ -24,6 +26,7 @@ func (r *NursingNoteRepository) Create(note *domain.NursingNote) error {
 func (r *NursingNoteRepository) FindByAdmissionID(admissionID uint) ([]*domain.NursingNote, error) {
 	var notes []*domain.NursingNote
 	err := r.db.Preload("Nurse").
+		Preload("EarlyWarningScore").
 		Where("admission_id = ?", admissionID).
 		Order("note_date DESC").
 		Find(&notes).Error
@@ -34,3 +37,40 @@ func (r *NursingNoteRepository) FindByAdmissionID(admissionID uint) ([]*domain.N
 func (r *NursingNoteRepository) Update(note *domain.NursingNote) error {
 	return r.db.Save(note).Error
 }
+
+// CreateWithScore creates a nursing note and the early warning score of its
+// vital signs in one transaction
+func (r *NursingNoteRepository) CreateWithScore(note *domain.NursingNote, score *domain.EarlyWarningScore) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Admission", "Nurse", "EarlyWarningScore").Create(note).Error; err != nil {
+			return err
+		}
+		score.NursingNoteID = &note.ID
+		return tx.Omit("Admission", "Recorder").Create(score).Error
+	})
+}
+
+// FindLatestScore finds an admission's most recent early warning score
+func (r *NursingNoteRepository) FindLatestScore(admissionID uint) (*domain.EarlyWarningScore, error) {
+	var score domain.EarlyWarningScore
+	err := r.db.Where("admission_id = ?", admissionID).
+		Order("recorded_at DESC, id DESC").
+		First(&score).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &score, nil
+}
+
+// FindScores finds an admission's early warning scores, oldest first
+func (r *NursingNoteRepository) FindScores(admissionID uint) ([]*domain.EarlyWarningScore, error) {
+	var scores []*domain.EarlyWarningScore
+	err := r.db.Preload("Recorder").
+		Where("admission_id = ?", admissionID).
+		Order("recorded_at ASC, id ASC").
+		Find(&scores).Error
+	return scores, err
+}
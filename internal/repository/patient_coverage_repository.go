@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/minhtran/his/internal/domain"
+	"gorm.io/gorm"
+)
+
+// PatientCoverageRepository handles patient coverage data operations
+type PatientCoverageRepository struct {
+	db *gorm.DB
+}
+
+// NewPatientCoverageRepository creates a new patient coverage repository
+func NewPatientCoverageRepository(db *gorm.DB) *PatientCoverageRepository {
+	return &PatientCoverageRepository{db: db}
+}
+
+// Create creates a new coverage record
+func (r *PatientCoverageRepository) Create(coverage *domain.PatientCoverage) error {
+	return r.db.Create(coverage).Error
+}
+
+// FindByID finds a coverage by ID
+func (r *PatientCoverageRepository) FindByID(id uint) (*domain.PatientCoverage, error) {
+	var coverage domain.PatientCoverage
+	err := r.db.Preload("Payer").First(&coverage, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &coverage, nil
+}
+
+// FindByPatientID finds all coverages for a patient
+func (r *PatientCoverageRepository) FindByPatientID(patientID uint) ([]*domain.PatientCoverage, error) {
+	var coverages []*domain.PatientCoverage
+	err := r.db.Preload("Payer").
+		Where("patient_id = ?", patientID).
+		Order("FIELD(priority, 'PRIMARY', 'SECONDARY'), valid_from DESC").
+		Find(&coverages).Error
+	if err != nil {
+		return nil, err
+	}
+	return coverages, nil
+}
+
+// FindActiveByPatientID finds coverages valid on the given date, primary first
+func (r *PatientCoverageRepository) FindActiveByPatientID(patientID uint, date time.Time) ([]*domain.PatientCoverage, error) {
+	var coverages []*domain.PatientCoverage
+	day := date.Format("2006-01-02")
+	err := r.db.Preload("Payer").
+		Joins("JOIN insurance_payers ON insurance_payers.id = patient_coverages.payer_id AND insurance_payers.is_active = ? AND insurance_payers.deleted_at IS NULL", true).
+		Where("patient_coverages.patient_id = ? AND patient_coverages.is_active = ?", patientID, true).
+		Where("patient_coverages.valid_from <= ?", day).
+		Where("patient_coverages.valid_to IS NULL OR patient_coverages.valid_to >= ?", day).
+		Order("FIELD(patient_coverages.priority, 'PRIMARY', 'SECONDARY'), patient_coverages.valid_from DESC").
+		Find(&coverages).Error
+	if err != nil {
+		return nil, err
+	}
+	return coverages, nil
+}
+
+// HasOverlappingPriority checks whether another active coverage of the same priority overlaps the given period
+func (r *PatientCoverageRepository) HasOverlappingPriority(patientID uint, priority domain.CoveragePriority, from time.Time, to *time.Time, excludeID *uint) (bool, error) {
+	var count int64
+	query := r.db.Model(&domain.PatientCoverage{}).
+		Where("patient_id = ? AND priority = ? AND is_active = ?", patientID, priority, true).
+		Where("valid_to IS NULL OR valid_to >= ?", from.Format("2006-01-02"))
+
+	if to != nil {
+		query = query.Where("valid_from <= ?", to.Format("2006-01-02"))
+	}
+	if excludeID != nil {
+		query = query.Where("id != ?", *excludeID)
+	}
+
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+// Update updates a coverage record
+func (r *PatientCoverageRepository) Update(coverage *domain.PatientCoverage) error {
+	return r.db.Save(coverage).Error
+}
+
+// Delete soft deletes a coverage record
+func (r *PatientCoverageRepository) Delete(id uint) error {
+	return r.db.Delete(&domain.PatientCoverage{}, id).Error
+}
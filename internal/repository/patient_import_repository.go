@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/minhtran/his/internal/domain"
+	"gorm.io/gorm"
+)
+
+// PatientImportRepository handles patient import job data operations
+type PatientImportRepository struct {
+	db *gorm.DB
+}
+
+// NewPatientImportRepository creates a new patient import repository
+func NewPatientImportRepository(db *gorm.DB) *PatientImportRepository {
+	return &PatientImportRepository{db: db}
+}
+
+// Create creates an import job together with its row results
+func (r *PatientImportRepository) Create(job *domain.PatientImportJob) error {
+	return r.db.Session(&gorm.Session{CreateBatchSize: 500}).Create(job).Error
+}
+
+// SaveBatch stores the row results of an imported batch and adds its
+// outcomes to the job's counts in one transaction
+func (r *PatientImportRepository) SaveBatch(jobID uint, rows []*domain.PatientImportRow, created, skipped, failed int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if len(rows) > 0 {
+			if err := tx.Create(rows).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&domain.PatientImportJob{}).Where("id = ?", jobID).Updates(map[string]interface{}{
+			"created_count": gorm.Expr("created_count + ?", created),
+			"skipped_count": gorm.Expr("skipped_count + ?", skipped),
+			"error_count":   gorm.Expr("error_count + ?", failed),
+		}).Error
+	})
+}
+
+// Finish records the final status of an import job
+func (r *PatientImportRepository) Finish(jobID uint, status domain.ImportJobStatus, completedAt time.Time) error {
+	return r.db.Model(&domain.PatientImportJob{}).Where("id = ?", jobID).Updates(map[string]interface{}{
+		"status":       status,
+		"completed_at": completedAt,
+	}).Error
+}
+
+// FindByID finds an import job by ID without its rows
+func (r *PatientImportRepository) FindByID(id uint) (*domain.PatientImportJob, error) {
+	var job domain.PatientImportJob
+	err := r.db.First(&job, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+// FindByIDWithRows finds an import job by ID with row results in file order
+func (r *PatientImportRepository) FindByIDWithRows(id uint) (*domain.PatientImportJob, error) {
+	var job domain.PatientImportJob
+	err := r.db.Preload("Rows", func(db *gorm.DB) *gorm.DB {
+		return db.Order("line_number ASC")
+	}).First(&job, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+// List returns a paginated list of import jobs, newest first
+func (r *PatientImportRepository) List(page, pageSize int) ([]*domain.PatientImportJob, int64, error) {
+	var jobs []*domain.PatientImportJob
+	var total int64
+
+	offset := (page - 1) * pageSize
+
+	if err := r.db.Model(&domain.PatientImportJob{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := r.db.Offset(offset).
+		Limit(pageSize).
+		Order("created_at DESC").
+		Find(&jobs).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return jobs, total, nil
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/minhtran/his/internal/domain"
+	"gorm.io/gorm"
+)
+
+// PatientPortalAccountRepository handles patient portal account and OTP data operations
+type PatientPortalAccountRepository struct {
+	db *gorm.DB
+}
+
+// NewPatientPortalAccountRepository creates a new patient portal account repository
+func NewPatientPortalAccountRepository(db *gorm.DB) *PatientPortalAccountRepository {
+	return &PatientPortalAccountRepository{db: db}
+}
+
+// Create creates a new portal account
+func (r *PatientPortalAccountRepository) Create(account *domain.PatientPortalAccount) error {
+	return r.db.Create(account).Error
+}
+
+// FindByID finds a portal account by ID
+func (r *PatientPortalAccountRepository) FindByID(id uint) (*domain.PatientPortalAccount, error) {
+	var account domain.PatientPortalAccount
+	err := r.db.Preload("Patient").First(&account, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &account, nil
+}
+
+// FindByPatientID finds the portal account of a patient
+func (r *PatientPortalAccountRepository) FindByPatientID(patientID uint) (*domain.PatientPortalAccount, error) {
+	var account domain.PatientPortalAccount
+	err := r.db.Where("patient_id = ?", patientID).First(&account).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &account, nil
+}
+
+// FindByPhoneNumber finds a portal account by its login phone number
+func (r *PatientPortalAccountRepository) FindByPhoneNumber(phoneNumber string) (*domain.PatientPortalAccount, error) {
+	var account domain.PatientPortalAccount
+	err := r.db.Preload("Patient").Where("phone_number = ?", phoneNumber).First(&account).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &account, nil
+}
+
+// Update updates a portal account
+func (r *PatientPortalAccountRepository) Update(account *domain.PatientPortalAccount) error {
+	return r.db.Save(account).Error
+}
+
+// CreateOTP stores a new one-time code and invalidates earlier unused codes of the account
+func (r *PatientPortalAccountRepository) CreateOTP(otp *domain.PortalOTP) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.PortalOTP{}).
+			Where("account_id = ? AND consumed_at IS NULL", otp.AccountID).
+			Update("consumed_at", tx.NowFunc()).Error; err != nil {
+			return err
+		}
+		return tx.Create(otp).Error
+	})
+}
+
+// FindActiveOTP finds the latest unused, unexpired one-time code of an account
+func (r *PatientPortalAccountRepository) FindActiveOTP(accountID uint, now time.Time) (*domain.PortalOTP, error) {
+	var otp domain.PortalOTP
+	err := r.db.Where("account_id = ? AND consumed_at IS NULL AND expires_at > ?", accountID, now).
+		Order("created_at DESC").
+		First(&otp).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &otp, nil
+}
+
+// CountOTPsSince counts one-time codes issued to an account since a time (for rate limiting)
+func (r *PatientPortalAccountRepository) CountOTPsSince(accountID uint, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.PortalOTP{}).
+		Where("account_id = ? AND created_at >= ?", accountID, since).
+		Count(&count).Error
+	return count, err
+}
+
+// UpdateOTP updates a one-time code
+func (r *PatientPortalAccountRepository) UpdateOTP(otp *domain.PortalOTP) error {
+	return r.db.Save(otp).Error
+}
@@ -63,6 +63,21 @@ func (r *PatientRepository) FindByNationalID(nationalID string) (*domain.Patient
 	return &patient, nil
 }
 
+// FindPossibleDuplicate finds a patient with the same name, date of birth and phone number
+func (r *PatientRepository) FindPossibleDuplicate(firstName, lastName string, dob time.Time, phoneNumber string) (*domain.Patient, error) {
+	var patient domain.Patient
+	err := r.db.Where("first_name = ? AND last_name = ? AND date_of_birth = ? AND phone_number = ?",
+		firstName, lastName, dob.Format("2006-01-02"), phoneNumber).
+		First(&patient).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &patient, nil
+}
+
 // Update updates a patient
 func (r *PatientRepository) Update(patient *domain.Patient) error {
 	return r.db.Save(patient).Error
@@ -73,6 +88,12 @@ func (r *PatientRepository) Delete(id uint) error {
 	return r.db.Delete(&domain.Patient{}, id).Error
 }
 
+// DiscardPreRegistered permanently removes a patient pre-registered by an
+// online booking that was never made
+func (r *PatientRepository) DiscardPreRegistered(id uint) error {
+	return r.db.Unscoped().Where("id = ? AND pre_registered = ?", id, true).Delete(&domain.Patient{}).Error
+}
+
 // List returns a paginated list of patients with optional filters
 func (r *PatientRepository) List(page, pageSize int, filters map[string]interface{}) ([]*domain.Patient, int64, error) {
 	var patients []*domain.Patient
@@ -95,6 +116,9 @@ func (r *PatientRepository) List(page, pageSize int, filters map[string]interfac
 	if isActive, ok := filters["is_active"]; ok {
 		query = query.Where("is_active = ?", isActive)
 	}
+	if preRegistered, ok := filters["pre_registered"]; ok {
+		query = query.Where("pre_registered = ?", preRegistered)
+	}
 
 	// Count total
 	if err := query.Count(&total).Error; err != nil {
@@ -144,8 +168,9 @@ func (r *PatientRepository) Search(query string, page, pageSize int) ([]*domain.
 	return patients, total, nil
 }
 
-// GetPatientStats returns patient statistics
-func (r *PatientRepository) GetPatientStats() (map[string]interface{}, error) {
+// GetPatientStats returns patient statistics, counting new patients registered
+// since dayStart and since monthStart
+func (r *PatientRepository) GetPatientStats(dayStart, monthStart time.Time) (map[string]interface{}, error) {
 	stats := make(map[string]interface{})
 
 	// Total patients
@@ -163,17 +188,15 @@ func (r *PatientRepository) GetPatientStats() (map[string]interface{}, error) {
 	stats["active_patients"] = activePatients
 
 	// New patients today
-	today := time.Now().Truncate(24 * time.Hour)
 	var newToday int64
-	if err := r.db.Model(&domain.Patient{}).Where("DATE(created_at) = ?", today).Count(&newToday).Error; err != nil {
+	if err := r.db.Model(&domain.Patient{}).Where("created_at >= ?", dayStart).Count(&newToday).Error; err != nil {
 		return nil, err
 	}
 	stats["new_today"] = newToday
 
 	// New patients this month
-	firstDayOfMonth := time.Date(time.Now().Year(), time.Now().Month(), 1, 0, 0, 0, 0, time.Now().Location())
 	var newThisMonth int64
-	if err := r.db.Model(&domain.Patient{}).Where("created_at >= ?", firstDayOfMonth).Count(&newThisMonth).Error; err != nil {
+	if err := r.db.Model(&domain.Patient{}).Where("created_at >= ?", monthStart).Count(&newThisMonth).Error; err != nil {
 		return nil, err
 	}
 	stats["new_this_month"] = newThisMonth
@@ -196,7 +219,7 @@ func (r *PatientRepository) GetPatientStats() (map[string]interface{}, error) {
 
 // GeneratePatientCode generates a unique patient code
 func (r *PatientRepository) GeneratePatientCode() (string, error) {
-	today := time.Now().Format("20060102") // YYYYMMDD
+	today := r.db.NowFunc().Format("20060102") // YYYYMMDD
 	prefix := fmt.Sprintf("P-%s-", today)
 
 	// Find the last patient code for today
@@ -3,7 +3,6 @@ package repository
 import (
 	"errors"
 	"fmt"
-	"time"
 
 	"github.com/minhtran/his/internal/domain"
 	"gorm.io/gorm"
@@ -50,7 +49,7 @@ func (r *PaymentRepository) Update(payment *domain.Payment) error {
 
 // GeneratePaymentCode generates a unique payment code
 func (r *PaymentRepository) GeneratePaymentCode() (string, error) {
-	today := time.Now().Format("20060102") // YYYYMMDD
+	today := r.db.NowFunc().Format("20060102") // YYYYMMDD
 	prefix := fmt.Sprintf("PAY-%s-", today)
 
 	var lastPayment domain.Payment
@@ -3,7 +3,6 @@ package repository
 import (
 	"errors"
 	"fmt"
-	"time"
 
 	"github.com/minhtran/his/internal/domain"
 	"gorm.io/gorm"
@@ -107,7 +106,7 @@ func (r *PrescriptionRepository) Delete(id uint) error {
 
 // GeneratePrescriptionCode generates a unique prescription code
 func (r *PrescriptionRepository) GeneratePrescriptionCode() (string, error) {
-	today := time.Now().Format("20060102") // YYYYMMDD
+	today := r.db.NowFunc().Format("20060102") // YYYYMMDD
 	prefix := fmt.Sprintf("PRX-%s-", today)
 
 	var lastPrescription domain.Prescription
@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/minhtran/his/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// openQueueTicketStatuses are the statuses of tickets still in their queue
+var openQueueTicketStatuses = []domain.QueueTicketStatus{domain.QueueTicketStatusWaiting, domain.QueueTicketStatusCalled, domain.QueueTicketStatusSkipped}
+
+// QueueRepository handles outpatient queue and ticket data operations
+type QueueRepository struct {
+	db *gorm.DB
+}
+
+// NewQueueRepository creates a new queue repository
+func NewQueueRepository(db *gorm.DB) *QueueRepository {
+	return &QueueRepository{db: db}
+}
+
+// WithQueueLock runs fn in a transaction holding a row lock on the queue, so
+// ticket numbers are issued and tickets called one at a time per queue
+func (r *QueueRepository) WithQueueLock(queueID uint, fn func(repo *QueueRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var queue domain.ClinicQueue
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&queue, queueID).Error; err != nil {
+			return err
+		}
+		return fn(&QueueRepository{db: tx})
+	})
+}
+
+// Create creates a new queue
+func (r *QueueRepository) Create(queue *domain.ClinicQueue) error {
+	return r.db.Omit("Doctor", "Room", "Department").Create(queue).Error
+}
+
+// Update updates a queue
+func (r *QueueRepository) Update(queue *domain.ClinicQueue) error {
+	return r.db.Omit("Doctor", "Room", "Department").Save(queue).Error
+}
+
+// FindByID finds a queue by ID
+func (r *QueueRepository) FindByID(id uint) (*domain.ClinicQueue, error) {
+	var queue domain.ClinicQueue
+	err := r.db.Preload("Doctor").Preload("Room").Preload("Department").First(&queue, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &queue, nil
+}
+
+// FindByCode finds a queue by code
+func (r *QueueRepository) FindByCode(code string) (*domain.ClinicQueue, error) {
+	var queue domain.ClinicQueue
+	err := r.db.Where("code = ?", code).First(&queue).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &queue, nil
+}
+
+// FindActiveByDoctor finds the active queue serving a doctor
+func (r *QueueRepository) FindActiveByDoctor(doctorID uint) (*domain.ClinicQueue, error) {
+	var queue domain.ClinicQueue
+	err := r.db.Where("doctor_id = ? AND is_active = ?", doctorID, true).Order("id ASC").First(&queue).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &queue, nil
+}
+
+// List lists queues with filters
+func (r *QueueRepository) List(filters map[string]interface{}) ([]*domain.ClinicQueue, error) {
+	query := r.db.Model(&domain.ClinicQueue{})
+
+	if departmentID, ok := filters["department_id"]; ok {
+		query = query.Where("department_id = ?", departmentID)
+	}
+	if doctorID, ok := filters["doctor_id"]; ok {
+		query = query.Where("doctor_id = ?", doctorID)
+	}
+	if isActive, ok := filters["is_active"]; ok {
+		query = query.Where("is_active = ?", isActive)
+	}
+
+	var queues []*domain.ClinicQueue
+	err := query.Preload("Doctor").Preload("Room").Preload("Department").
+		Order("code ASC").
+		Find(&queues).Error
+	return queues, err
+}
+
+// NextTicketNumber returns the next ticket number of a queue on a date. Call
+// it under WithQueueLock.
+func (r *QueueRepository) NextTicketNumber(queueID uint, date time.Time) (int, error) {
+	var last int
+	err := r.db.Model(&domain.QueueTicket{}).
+		Select("COALESCE(MAX(ticket_number), 0)").
+		Where("queue_id = ? AND queue_date = ?", queueID, date.Format("2006-01-02")).
+		Scan(&last).Error
+	return last + 1, err
+}
+
+// CreateTicket creates a new ticket
+func (r *QueueRepository) CreateTicket(ticket *domain.QueueTicket) error {
+	return r.db.Omit("Queue", "Visit", "Patient").Create(ticket).Error
+}
+
+// UpdateTicket updates a ticket
+func (r *QueueRepository) UpdateTicket(ticket *domain.QueueTicket) error {
+	return r.db.Omit("Queue", "Visit", "Patient").Save(ticket).Error
+}
+
+// FindTicketByID finds a ticket by ID
+func (r *QueueRepository) FindTicketByID(id uint) (*domain.QueueTicket, error) {
+	var ticket domain.QueueTicket
+	err := r.db.Preload("Queue.Room").Preload("Patient").First(&ticket, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &ticket, nil
+}
+
+// FindOpenTicketByVisit finds the ticket a visit still holds in any queue
+func (r *QueueRepository) FindOpenTicketByVisit(visitID uint) (*domain.QueueTicket, error) {
+	var ticket domain.QueueTicket
+	err := r.db.Where("visit_id = ? AND status IN ?", visitID, openQueueTicketStatuses).
+		Order("id DESC").
+		First(&ticket).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &ticket, nil
+}
+
+// FindTickets finds the tickets of a queue on a date, optionally limited to statuses
+func (r *QueueRepository) FindTickets(queueID uint, date time.Time, statuses []domain.QueueTicketStatus) ([]*domain.QueueTicket, error) {
+	query := r.db.Preload("Patient").
+		Where("queue_id = ? AND queue_date = ?", queueID, date.Format("2006-01-02"))
+	if len(statuses) > 0 {
+		query = query.Where("status IN ?", statuses)
+	}
+
+	var tickets []*domain.QueueTicket
+	err := query.Order("ticket_number ASC").Find(&tickets).Error
+	return tickets, err
+}
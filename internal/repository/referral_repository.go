@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/minhtran/his/internal/domain"
+	"gorm.io/gorm"
+)
+
+// ReferralRepository handles referral data operations
+type ReferralRepository struct {
+	db *gorm.DB
+}
+
+// NewReferralRepository creates a new referral repository
+func NewReferralRepository(db *gorm.DB) *ReferralRepository {
+	return &ReferralRepository{db: db}
+}
+
+// Create numbers and creates a referral with its attachments in one transaction
+func (r *ReferralRepository) Create(referral *domain.Referral) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		number, err := generateReferralNumber(tx)
+		if err != nil {
+			return err
+		}
+		referral.ReferralNumber = number
+		return tx.Omit("Visit", "Patient", "ReferringDoctor", "TargetDepartment", "TargetDoctor", "Responder", "Appointment").
+			Create(referral).Error
+	})
+}
+
+// Update updates a referral
+func (r *ReferralRepository) Update(referral *domain.Referral) error {
+	return r.db.Omit("Visit", "Patient", "ReferringDoctor", "TargetDepartment", "TargetDoctor", "Responder", "Appointment", "Attachments").
+		Save(referral).Error
+}
+
+// FindByID finds a referral by ID with everything its letter shows
+func (r *ReferralRepository) FindByID(id uint) (*domain.Referral, error) {
+	var referral domain.Referral
+	err := r.db.Preload("Visit.Coverage").
+		Preload("Patient").
+		Preload("ReferringDoctor").
+		Preload("TargetDepartment").
+		Preload("TargetDoctor").
+		Preload("Responder").
+		Preload("Appointment").
+		Preload("Attachments.Diagnosis.ICD10Code").
+		Preload("Attachments.LabTestRequest.Template").
+		Preload("Attachments.LabTestRequest.Results").
+		Preload("Attachments.ImagingRequest.Template").
+		Preload("Attachments.ImagingRequest.Result").
+		First(&referral, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &referral, nil
+}
+
+// FindByVisit finds the referrals made in a visit
+func (r *ReferralRepository) FindByVisit(visitID uint) ([]*domain.Referral, error) {
+	var referrals []*domain.Referral
+	err := r.listQuery().
+		Where("visit_id = ?", visitID).
+		Order("sent_at ASC").
+		Find(&referrals).Error
+	return referrals, err
+}
+
+// FindInbox finds the internal referrals sent to a department or doctor,
+// most urgent first, then oldest first
+func (r *ReferralRepository) FindInbox(departmentID *uint, doctorID uint, statuses []domain.ReferralStatus) ([]*domain.Referral, error) {
+	query := r.listQuery().Where("referral_type = ?", domain.ReferralTypeInternal)
+	if departmentID != nil {
+		query = query.Where("target_department_id = ? OR target_doctor_id = ?", *departmentID, doctorID)
+	} else {
+		query = query.Where("target_doctor_id = ?", doctorID)
+	}
+	if len(statuses) > 0 {
+		query = query.Where("status IN ?", statuses)
+	}
+
+	var referrals []*domain.Referral
+	err := query.
+		Order("FIELD(urgency, 'EMERGENCY', 'URGENT', 'ROUTINE'), sent_at ASC").
+		Find(&referrals).Error
+	return referrals, err
+}
+
+// FindAcceptedByAppointment finds the accepted referral booked as an appointment
+func (r *ReferralRepository) FindAcceptedByAppointment(appointmentID uint) (*domain.Referral, error) {
+	var referral domain.Referral
+	err := r.db.Where("appointment_id = ? AND status = ?", appointmentID, domain.ReferralStatusAccepted).
+		First(&referral).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &referral, nil
+}
+
+func (r *ReferralRepository) listQuery() *gorm.DB {
+	return r.db.Model(&domain.Referral{}).
+		Preload("Patient").
+		Preload("ReferringDoctor").
+		Preload("TargetDepartment").
+		Preload("TargetDoctor")
+}
+
+// generateReferralNumber generates the next referral number using tx
+func generateReferralNumber(tx *gorm.DB) (string, error) {
+	today := tx.NowFunc().Format("20060102") // YYYYMMDD
+	prefix := fmt.Sprintf("REF-%s-", today)
+
+	var lastReferral domain.Referral
+	err := tx.Where("referral_number LIKE ?", prefix+"%").
+		Order("referral_number DESC").
+		First(&lastReferral).Error
+
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", err
+	}
+
+	sequence := 1
+	if lastReferral.ReferralNumber != "" {
+		var lastSeq int
+		fmt.Sscanf(lastReferral.ReferralNumber, prefix+"%d", &lastSeq)
+		sequence = lastSeq + 1
+	}
+
+	return fmt.Sprintf("%s%04d", prefix, sequence), nil
+}
@@ -0,0 +1,243 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/minhtran/his/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ResourceRepository handles room and equipment resource data operations
+type ResourceRepository struct {
+	db *gorm.DB
+}
+
+// NewResourceRepository creates a new resource repository
+func NewResourceRepository(db *gorm.DB) *ResourceRepository {
+	return &ResourceRepository{db: db}
+}
+
+// Create creates a new resource with its opening hours
+func (r *ResourceRepository) Create(resource *domain.Resource) error {
+	return r.db.Omit("Department").Create(resource).Error
+}
+
+// FindByID finds a resource by ID with its opening hours
+func (r *ResourceRepository) FindByID(id uint) (*domain.Resource, error) {
+	var resource domain.Resource
+	err := r.db.Preload("Department").
+		Preload("Hours", func(db *gorm.DB) *gorm.DB {
+			return db.Order("day_of_week ASC, start_time ASC")
+		}).
+		First(&resource, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// FindByIDs finds resources by ID with their opening hours
+func (r *ResourceRepository) FindByIDs(ids []uint) ([]*domain.Resource, error) {
+	var resources []*domain.Resource
+	err := r.db.Preload("Hours").
+		Where("id IN ?", ids).
+		Order("id ASC").
+		Find(&resources).Error
+	return resources, err
+}
+
+// FindByCode finds a resource by code
+func (r *ResourceRepository) FindByCode(code string) (*domain.Resource, error) {
+	var resource domain.Resource
+	err := r.db.Where("code = ?", code).First(&resource).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// List returns a paginated list of resources with filters
+func (r *ResourceRepository) List(filters map[string]interface{}, page, pageSize int) ([]*domain.Resource, int64, error) {
+	var resources []*domain.Resource
+	var total int64
+
+	query := r.db.Model(&domain.Resource{})
+
+	if resourceType, ok := filters["type"]; ok {
+		query = query.Where("type = ?", resourceType)
+	}
+	if category, ok := filters["category"]; ok {
+		query = query.Where("category = ?", category)
+	}
+	if departmentID, ok := filters["department_id"]; ok {
+		query = query.Where("department_id = ?", departmentID)
+	}
+	if isActive, ok := filters["is_active"]; ok {
+		query = query.Where("is_active = ?", isActive)
+	}
+	if search, ok := filters["search"]; ok {
+		searchPattern := "%" + search.(string) + "%"
+		query = query.Where("code LIKE ? OR name LIKE ?", searchPattern, searchPattern)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Preload("Department").
+		Preload("Hours", func(db *gorm.DB) *gorm.DB {
+			return db.Order("day_of_week ASC, start_time ASC")
+		}).
+		Offset(offset).
+		Limit(pageSize).
+		Order("type ASC, name ASC").
+		Find(&resources).Error
+
+	return resources, total, err
+}
+
+// Update updates a resource's details
+func (r *ResourceRepository) Update(resource *domain.Resource) error {
+	return r.db.Omit("Department", "Hours").Save(resource).Error
+}
+
+// ReplaceHours replaces a resource's weekly opening hours
+func (r *ResourceRepository) ReplaceHours(resourceID uint, hours []domain.ResourceHours) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("resource_id = ?", resourceID).Delete(&domain.ResourceHours{}).Error; err != nil {
+			return err
+		}
+		if len(hours) == 0 {
+			return nil
+		}
+		for i := range hours {
+			hours[i].ResourceID = resourceID
+		}
+		return tx.Create(&hours).Error
+	})
+}
+
+// CreateDowntime creates a downtime period
+func (r *ResourceRepository) CreateDowntime(downtime *domain.ResourceDowntime) error {
+	return r.db.Create(downtime).Error
+}
+
+// FindDowntimeByID finds a downtime period by ID
+func (r *ResourceRepository) FindDowntimeByID(id uint) (*domain.ResourceDowntime, error) {
+	var downtime domain.ResourceDowntime
+	err := r.db.First(&downtime, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &downtime, nil
+}
+
+// FindDowntimes finds the downtime of resources overlapping [from, to)
+func (r *ResourceRepository) FindDowntimes(resourceIDs []uint, from, to time.Time) ([]*domain.ResourceDowntime, error) {
+	var downtimes []*domain.ResourceDowntime
+	err := r.db.Where("resource_id IN ?", resourceIDs).
+		Where("start_at < ? AND end_at > ?", to, from).
+		Order("start_at ASC").
+		Find(&downtimes).Error
+	return downtimes, err
+}
+
+// DeleteDowntime soft deletes a downtime period
+func (r *ResourceRepository) DeleteDowntime(id uint) error {
+	return r.db.Delete(&domain.ResourceDowntime{}, id).Error
+}
+
+// FindActiveBookings finds the bookings of resources overlapping [from, to)
+// whose appointment or imaging request still holds them. Bookings of the
+// excluded appointment or imaging request are ignored, so a booking can be moved.
+func (r *ResourceRepository) FindActiveBookings(resourceIDs []uint, from, to time.Time, excludeAppointmentID, excludeImagingRequestID *uint) ([]*domain.ResourceBooking, error) {
+	query := r.db.Model(&domain.ResourceBooking{}).
+		Joins("LEFT JOIN appointments ON appointments.id = resource_bookings.appointment_id").
+		Joins("LEFT JOIN imaging_requests ON imaging_requests.id = resource_bookings.imaging_request_id").
+		Where("resource_bookings.resource_id IN ?", resourceIDs).
+		Where("resource_bookings.start_at < ? AND resource_bookings.end_at > ?", to, from).
+		Where("(appointments.id IS NOT NULL AND appointments.deleted_at IS NULL AND appointments.status NOT IN ?) OR (imaging_requests.id IS NOT NULL AND imaging_requests.deleted_at IS NULL AND imaging_requests.status <> ?)",
+			[]domain.AppointmentStatus{domain.AppointmentStatusCancelled, domain.AppointmentStatusNoShow},
+			domain.ImagingRequestStatusCancelled)
+
+	if excludeAppointmentID != nil {
+		query = query.Where("resource_bookings.appointment_id IS NULL OR resource_bookings.appointment_id <> ?", *excludeAppointmentID)
+	}
+	if excludeImagingRequestID != nil {
+		query = query.Where("resource_bookings.imaging_request_id IS NULL OR resource_bookings.imaging_request_id <> ?", *excludeImagingRequestID)
+	}
+
+	var bookings []*domain.ResourceBooking
+	err := query.Select("resource_bookings.*").
+		Order("resource_bookings.start_at ASC").
+		Find(&bookings).Error
+	return bookings, err
+}
+
+// FindBookingsByAppointment finds the resources booked for an appointment
+func (r *ResourceRepository) FindBookingsByAppointment(appointmentID uint) ([]*domain.ResourceBooking, error) {
+	var bookings []*domain.ResourceBooking
+	err := r.db.Preload("Resource").
+		Where("appointment_id = ?", appointmentID).
+		Order("resource_id ASC").
+		Find(&bookings).Error
+	return bookings, err
+}
+
+// FindBookingsByImagingRequest finds the resources booked for an imaging request
+func (r *ResourceRepository) FindBookingsByImagingRequest(imagingRequestID uint) ([]*domain.ResourceBooking, error) {
+	var bookings []*domain.ResourceBooking
+	err := r.db.Preload("Resource").
+		Where("imaging_request_id = ?", imagingRequestID).
+		Order("resource_id ASC").
+		Find(&bookings).Error
+	return bookings, err
+}
+
+// CreateBookings creates resource bookings
+func (r *ResourceRepository) CreateBookings(bookings []*domain.ResourceBooking) error {
+	if len(bookings) == 0 {
+		return nil
+	}
+	return r.db.Omit("Resource").Create(&bookings).Error
+}
+
+// MoveAppointmentBookings moves all resource bookings of an appointment to a new period
+func (r *ResourceRepository) MoveAppointmentBookings(appointmentID uint, startAt, endAt time.Time) error {
+	return r.db.Model(&domain.ResourceBooking{}).
+		Where("appointment_id = ?", appointmentID).
+		Updates(map[string]interface{}{"start_at": startAt, "end_at": endAt}).Error
+}
+
+// ReplaceImagingBookings replaces the resource bookings of an imaging request
+func (r *ResourceRepository) ReplaceImagingBookings(imagingRequestID uint, bookings []*domain.ResourceBooking) error {
+	if err := r.db.Where("imaging_request_id = ?", imagingRequestID).Delete(&domain.ResourceBooking{}).Error; err != nil {
+		return err
+	}
+	return r.CreateBookings(bookings)
+}
+
+// lockResources locks resource rows in ID order within a transaction
+func lockResources(tx *gorm.DB, resourceIDs []uint) error {
+	if len(resourceIDs) == 0 {
+		return nil
+	}
+	var locked []domain.Resource
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		Where("id IN ?", resourceIDs).
+		Order("id ASC").
+		Find(&locked).Error
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/minhtran/his/internal/domain"
+	"gorm.io/gorm"
+)
+
+// TriageRepository handles triage rule and assessment data operations
+type TriageRepository struct {
+	db *gorm.DB
+}
+
+// NewTriageRepository creates a new triage repository
+func NewTriageRepository(db *gorm.DB) *TriageRepository {
+	return &TriageRepository{db: db}
+}
+
+// CreateRule creates a new triage rule
+func (r *TriageRepository) CreateRule(rule *domain.TriageRule) error {
+	return r.db.Create(rule).Error
+}
+
+// UpdateRule updates a triage rule
+func (r *TriageRepository) UpdateRule(rule *domain.TriageRule) error {
+	return r.db.Save(rule).Error
+}
+
+// DeleteRule soft deletes a triage rule
+func (r *TriageRepository) DeleteRule(id uint) error {
+	return r.db.Delete(&domain.TriageRule{}, id).Error
+}
+
+// FindRuleByID finds a triage rule by ID
+func (r *TriageRepository) FindRuleByID(id uint) (*domain.TriageRule, error) {
+	var rule domain.TriageRule
+	err := r.db.First(&rule, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// ListRules lists triage rules with filters
+func (r *TriageRepository) ListRules(filters map[string]interface{}) ([]*domain.TriageRule, error) {
+	query := r.db.Model(&domain.TriageRule{})
+
+	if scale, ok := filters["scale"]; ok {
+		query = query.Where("scale = ?", scale)
+	}
+	if category, ok := filters["complaint_category"]; ok {
+		query = query.Where("complaint_category = ?", category)
+	}
+	if isActive, ok := filters["is_active"]; ok {
+		query = query.Where("is_active = ?", isActive)
+	}
+
+	var rules []*domain.TriageRule
+	err := query.Order("scale ASC, acuity_level ASC, id ASC").Find(&rules).Error
+	return rules, err
+}
+
+// FindActiveRules finds the active rules of a scale
+func (r *TriageRepository) FindActiveRules(scale domain.TriageScale) ([]*domain.TriageRule, error) {
+	var rules []*domain.TriageRule
+	err := r.db.Where("scale = ? AND is_active = ?", scale, true).
+		Order("acuity_level ASC, id ASC").
+		Find(&rules).Error
+	return rules, err
+}
+
+// CreateTriage numbers and creates a visit's assessment and makes its level
+// the visit's acuity level, in one transaction
+func (r *TriageRepository) CreateTriage(triage *domain.VisitTriage) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var last int
+		if err := tx.Model(&domain.VisitTriage{}).
+			Select("COALESCE(MAX(sequence), 0)").
+			Where("visit_id = ?", triage.VisitID).
+			Scan(&last).Error; err != nil {
+			return err
+		}
+		triage.Sequence = last + 1
+
+		if err := tx.Omit("Visit", "TriagedByUser").Create(triage).Error; err != nil {
+			return err
+		}
+		return tx.Model(&domain.Visit{}).Where("id = ?", triage.VisitID).Updates(map[string]interface{}{
+			"acuity_level": triage.AcuityLevel,
+			"triaged_at":   triage.TriagedAt,
+		}).Error
+	})
+}
+
+// FindByVisit finds a visit's assessments, latest first
+func (r *TriageRepository) FindByVisit(visitID uint) ([]*domain.VisitTriage, error) {
+	var triages []*domain.VisitTriage
+	err := r.db.Preload("TriagedByUser").
+		Where("visit_id = ?", visitID).
+		Order("sequence DESC").
+		Find(&triages).Error
+	return triages, err
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"errors"
+	"time"
 
 	"github.com/minhtran/his/internal/domain"
 	"gorm.io/gorm"
@@ -96,6 +97,32 @@ func (r *UserRepository) List(page, pageSize int) ([]*domain.User, int64, error)
 	return users, total, nil
 }
 
+// FindDoctorsWithSessions finds active users who hold clinic sessions between
+// two dates, through an active weekly schedule or an available date override,
+// optionally limited to a department or to given users
+func (r *UserRepository) FindDoctorsWithSessions(departmentID *uint, ids []uint, from, to time.Time) ([]*domain.User, error) {
+	fromStr, toStr := from.Format("2006-01-02"), to.Format("2006-01-02")
+	schedules := r.db.Model(&domain.DoctorSchedule{}).Select("doctor_id").
+		Where("is_active = ? AND effective_from <= ?", true, toStr).
+		Where("effective_to IS NULL OR effective_to >= ?", fromStr)
+	overrides := r.db.Model(&domain.DoctorScheduleOverride{}).Select("doctor_id").
+		Where("is_available = ? AND override_date BETWEEN ? AND ?", true, fromStr, toStr)
+
+	query := r.db.Preload("Department").
+		Where("is_active = ?", true).
+		Where("id IN (?) OR id IN (?)", schedules, overrides)
+	if departmentID != nil {
+		query = query.Where("department_id = ?", *departmentID)
+	}
+	if len(ids) > 0 {
+		query = query.Where("id IN ?", ids)
+	}
+
+	var users []*domain.User
+	err := query.Order("full_name ASC").Find(&users).Error
+	return users, err
+}
+
 // GetUserWithRoles finds a user by ID with roles preloaded
 func (r *UserRepository) GetUserWithRoles(id uint) (*domain.User, error) {
 	var user domain.User
@@ -94,6 +94,16 @@ func (r *VisitRepository) FindByDoctorID(doctorID uint, date time.Time) ([]*doma
 	return visits, err
 }
 
+// FindWaitingByType finds the visits of a type still waiting to be seen, oldest first
+func (r *VisitRepository) FindWaitingByType(visitType domain.VisitType) ([]*domain.Visit, error) {
+	var visits []*domain.Visit
+	err := r.db.Preload("Patient").Preload("Doctor").
+		Where("visit_type = ? AND status = ?", visitType, domain.VisitStatusWaiting).
+		Order("created_at ASC").
+		Find(&visits).Error
+	return visits, err
+}
+
 // Search searches visits with filters
 func (r *VisitRepository) Search(filters map[string]interface{}, page, pageSize int) ([]*domain.Visit, int64, error) {
 	var visits []*domain.Visit
@@ -138,6 +148,89 @@ func (r *VisitRepository) Update(visit *domain.Visit) error {
 	return r.db.Save(visit).Error
 }
 
+// UpdateStatus saves a visit's status change and records it in the status history
+func (r *VisitRepository) UpdateStatus(visit *domain.Visit, history *domain.VisitStatusHistory) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Patient", "Doctor", "Appointment", "Coverage").Save(visit).Error; err != nil {
+			return err
+		}
+		return tx.Create(history).Error
+	})
+}
+
+// FindStatusHistory finds the status changes of a visit, oldest first
+func (r *VisitRepository) FindStatusHistory(visitID uint) ([]*domain.VisitStatusHistory, error) {
+	var history []*domain.VisitStatusHistory
+	err := r.db.Preload("User").
+		Where("visit_id = ?", visitID).
+		Order("changed_at ASC, id ASC").
+		Find(&history).Error
+	return history, err
+}
+
+// VisitWaitStats is the average time visits spent in each stage, for one
+// group of visits
+type VisitWaitStats struct {
+	GroupKey               int64 // Doctor ID, department ID or hour of day; 0 for the overall row
+	GroupName              string
+	Visits                 int64 // Visits whose consultation started
+	Completed              int64 // Visits whose consultation ended
+	AvgTriageSeconds       *float64
+	AvgWaitSeconds         *float64 // Check-in to consultation start
+	AvgConsultationSeconds *float64 // Consultation start to end, less time on hold
+	AvgPausedSeconds       *float64
+	AvgCheckInToEndSeconds *float64
+}
+
+// visitWaitGroups maps each grouping to its key and name columns
+var visitWaitGroups = map[string][2]string{
+	"doctor":     {"v.doctor_id", "COALESCE(MAX(u.full_name), '')"},
+	"department": {"COALESCE(u.department_id, 0)", "COALESCE(MAX(d.name), '')"},
+	"hour":       {"HOUR(v.checked_in_at)", "''"},
+	"":           {"0", "''"},
+}
+
+// WaitTimeStats averages the stage durations of visits checked in between
+// two dates (inclusive) whose consultation started, grouped by doctor,
+// department or hour of check-in, or overall when groupBy is empty
+func (r *VisitRepository) WaitTimeStats(from, to time.Time, groupBy string, filters map[string]interface{}) ([]*VisitWaitStats, error) {
+	group, ok := visitWaitGroups[groupBy]
+	if !ok {
+		return nil, fmt.Errorf("unknown grouping %q", groupBy)
+	}
+
+	query := r.db.Table("visits v").
+		Select(group[0]+` AS group_key, `+group[1]+` AS group_name,
+			COUNT(*) AS visits,
+			COUNT(v.consultation_ended_at) AS completed,
+			AVG(TIMESTAMPDIFF(SECOND, v.checked_in_at, v.triaged_at)) AS avg_triage_seconds,
+			AVG(TIMESTAMPDIFF(SECOND, v.checked_in_at, v.consultation_started_at)) AS avg_wait_seconds,
+			AVG(TIMESTAMPDIFF(SECOND, v.consultation_started_at, v.consultation_ended_at) - v.paused_seconds) AS avg_consultation_seconds,
+			AVG(CASE WHEN v.consultation_ended_at IS NOT NULL THEN v.paused_seconds END) AS avg_paused_seconds,
+			AVG(TIMESTAMPDIFF(SECOND, v.checked_in_at, v.consultation_ended_at)) AS avg_check_in_to_end_seconds`).
+		Joins("LEFT JOIN users u ON u.id = v.doctor_id").
+		Joins("LEFT JOIN departments d ON d.id = u.department_id").
+		Where("v.deleted_at IS NULL AND v.consultation_started_at IS NOT NULL").
+		Where("v.visit_date BETWEEN ? AND ?", from.Format("2006-01-02"), to.Format("2006-01-02"))
+
+	if doctorID, ok := filters["doctor_id"]; ok {
+		query = query.Where("v.doctor_id = ?", doctorID)
+	}
+	if departmentID, ok := filters["department_id"]; ok {
+		query = query.Where("u.department_id = ?", departmentID)
+	}
+	if visitType, ok := filters["visit_type"]; ok {
+		query = query.Where("v.visit_type = ?", visitType)
+	}
+	if groupBy != "" {
+		query = query.Group("group_key").Order("group_key ASC")
+	}
+
+	var stats []*VisitWaitStats
+	err := query.Scan(&stats).Error
+	return stats, err
+}
+
 // Delete soft deletes a visit
 func (r *VisitRepository) Delete(id uint) error {
 	return r.db.Delete(&domain.Visit{}, id).Error
@@ -145,7 +238,7 @@ func (r *VisitRepository) Delete(id uint) error {
 
 // GenerateVisitCode generates a unique visit code
 func (r *VisitRepository) GenerateVisitCode() (string, error) {
-	today := time.Now().Format("20060102") // YYYYMMDD
+	today := r.db.NowFunc().Format("20060102") // YYYYMMDD
 	prefix := fmt.Sprintf("VST-%s-", today)
 
 	var lastVisit domain.Visit
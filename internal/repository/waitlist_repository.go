@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/minhtran/his/internal/domain"
+	"gorm.io/gorm"
+)
+
+// WaitlistRepository handles waitlist entry and offer data operations
+type WaitlistRepository struct {
+	db *gorm.DB
+}
+
+// NewWaitlistRepository creates a new waitlist repository
+func NewWaitlistRepository(db *gorm.DB) *WaitlistRepository {
+	return &WaitlistRepository{db: db}
+}
+
+// CreateEntry creates a new waitlist entry
+func (r *WaitlistRepository) CreateEntry(entry *domain.WaitlistEntry) error {
+	return r.db.Create(entry).Error
+}
+
+// FindEntryByID finds a waitlist entry by ID
+func (r *WaitlistRepository) FindEntryByID(id uint) (*domain.WaitlistEntry, error) {
+	var entry domain.WaitlistEntry
+	err := r.db.Preload("Patient").Preload("Doctor").Preload("Department").First(&entry, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// UpdateEntry updates a waitlist entry
+func (r *WaitlistRepository) UpdateEntry(entry *domain.WaitlistEntry) error {
+	return r.db.Omit("Patient", "Doctor", "Department", "Appointment").Save(entry).Error
+}
+
+// ListEntries lists waitlist entries with filters, most urgent first
+func (r *WaitlistRepository) ListEntries(filters map[string]interface{}, page, pageSize int) ([]*domain.WaitlistEntry, int64, error) {
+	var entries []*domain.WaitlistEntry
+	var total int64
+
+	offset := (page - 1) * pageSize
+	query := r.db.Model(&domain.WaitlistEntry{}).Preload("Patient").Preload("Doctor").Preload("Department")
+
+	if patientID, ok := filters["patient_id"]; ok && patientID != "" {
+		query = query.Where("patient_id = ?", patientID)
+	}
+	if doctorID, ok := filters["doctor_id"]; ok && doctorID != "" {
+		query = query.Where("doctor_id = ?", doctorID)
+	}
+	if departmentID, ok := filters["department_id"]; ok && departmentID != "" {
+		query = query.Where("department_id = ?", departmentID)
+	}
+	if status, ok := filters["status"]; ok && status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if priority, ok := filters["priority"]; ok && priority != "" {
+		query = query.Where("priority = ?", priority)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Offset(offset).
+		Limit(pageSize).
+		Order("FIELD(priority, 'URGENT', 'HIGH', 'NORMAL'), created_at ASC").
+		Find(&entries).Error
+
+	return entries, total, err
+}
+
+// FindMatchingEntries finds waiting entries whose preferred date range covers
+// the date, for the doctor or (when the entry names no doctor) the doctor's
+// department, most urgent and then longest waiting first
+func (r *WaitlistRepository) FindMatchingEntries(doctorID uint, departmentID *uint, date time.Time, excludeEntryIDs []uint) ([]*domain.WaitlistEntry, error) {
+	var entries []*domain.WaitlistEntry
+	day := date.Format("2006-01-02")
+
+	query := r.db.Preload("Patient").
+		Where("status = ?", domain.WaitlistStatusWaiting).
+		Where("preferred_from <= ? AND preferred_to >= ?", day, day)
+
+	if departmentID != nil {
+		query = query.Where("doctor_id = ? OR (doctor_id IS NULL AND department_id = ?)", doctorID, *departmentID)
+	} else {
+		query = query.Where("doctor_id = ?", doctorID)
+	}
+	if len(excludeEntryIDs) > 0 {
+		query = query.Where("id NOT IN ?", excludeEntryIDs)
+	}
+
+	err := query.Order("FIELD(priority, 'URGENT', 'HIGH', 'NORMAL'), created_at ASC").
+		Find(&entries).Error
+	return entries, err
+}
+
+// ExpireStaleEntries marks waiting entries whose preferred range ended before the date as expired
+func (r *WaitlistRepository) ExpireStaleEntries(date time.Time) (int64, error) {
+	result := r.db.Model(&domain.WaitlistEntry{}).
+		Where("status = ? AND preferred_to < ?", domain.WaitlistStatusWaiting, date.Format("2006-01-02")).
+		Update("status", domain.WaitlistStatusExpired)
+	return result.RowsAffected, result.Error
+}
+
+// FindOfferByID finds an offer by ID with its entry
+func (r *WaitlistRepository) FindOfferByID(id uint) (*domain.WaitlistOffer, error) {
+	var offer domain.WaitlistOffer
+	err := r.db.Preload("Entry").Preload("Entry.Patient").Preload("Doctor").First(&offer, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &offer, nil
+}
+
+// FindOffersByEntry finds the offers made for an entry, newest first
+func (r *WaitlistRepository) FindOffersByEntry(entryID uint) ([]*domain.WaitlistOffer, error) {
+	var offers []*domain.WaitlistOffer
+	err := r.db.Preload("Doctor").
+		Where("entry_id = ?", entryID).
+		Order("created_at DESC").
+		Find(&offers).Error
+	return offers, err
+}
+
+// FindPendingOfferByEntry finds the pending offer of an entry, if any
+func (r *WaitlistRepository) FindPendingOfferByEntry(entryID uint) (*domain.WaitlistOffer, error) {
+	var offer domain.WaitlistOffer
+	err := r.db.Where("entry_id = ? AND status = ?", entryID, domain.WaitlistOfferStatusPending).First(&offer).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &offer, nil
+}
+
+// FindExpiredOffers finds pending offers whose hold period has ended
+func (r *WaitlistRepository) FindExpiredOffers(now time.Time) ([]*domain.WaitlistOffer, error) {
+	var offers []*domain.WaitlistOffer
+	err := r.db.Preload("Entry").
+		Where("status = ? AND expires_at <= ?", domain.WaitlistOfferStatusPending, now).
+		Order("expires_at ASC").
+		Find(&offers).Error
+	return offers, err
+}
+
+// FindOfferedEntryIDs finds the entries a slot has already been offered to
+func (r *WaitlistRepository) FindOfferedEntryIDs(doctorID uint, date, appointmentTime time.Time) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&domain.WaitlistOffer{}).
+		Where("doctor_id = ? AND appointment_date = ? AND appointment_time = ?",
+			doctorID, date.Format("2006-01-02"), appointmentTime.Format("15:04:05")).
+		Pluck("entry_id", &ids).Error
+	return ids, err
+}
+
+// UpdateOfferWithEntry saves an offer and its entry in one transaction
+func (r *WaitlistRepository) UpdateOfferWithEntry(offer *domain.WaitlistOffer, entry *domain.WaitlistEntry) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Entry", "Doctor").Save(offer).Error; err != nil {
+			return err
+		}
+		return tx.Omit("Patient", "Doctor", "Department", "Appointment").Save(entry).Error
+	})
+}
+
+// HasOpenEntry checks whether a patient is already waiting or holding an offer
+// for the same doctor or department
+func (r *WaitlistRepository) HasOpenEntry(patientID uint, doctorID, departmentID *uint) (bool, error) {
+	query := r.db.Model(&domain.WaitlistEntry{}).
+		Where("patient_id = ?", patientID).
+		Where("status IN ?", []domain.WaitlistStatus{domain.WaitlistStatusWaiting, domain.WaitlistStatusOffered})
+
+	if doctorID != nil {
+		query = query.Where("doctor_id = ?", *doctorID)
+	} else {
+		query = query.Where("doctor_id IS NULL AND department_id = ?", *departmentID)
+	}
+
+	var count int64
+	err := query.Count(&count).Error
+	return count > 0, err
+}
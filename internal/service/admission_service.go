@@ -3,10 +3,10 @@ package service
 import (
 	"errors"
 	"fmt"
-	"time"
 
 	"github.com/minhtran/his/internal/domain"
 	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/pkg/clock"
 	"github.com/minhtran/his/internal/repository"
 )
 
@@ -16,13 +16,40 @@ var (
 	ErrBedNotAvailable   = errors.New("bed is not available")
 )
 
+// EarlyWarningPolicy configures when NEWS2 scores escalate a deteriorating
+// patient. Staff are notified each time the escalation level rises.
+type EarlyWarningPolicy struct {
+	UrgentScore    int  // Aggregate score that needs an urgent review
+	EmergencyScore int  // Aggregate score that needs an emergency assessment
+	EscalateOnRed  bool // A single parameter scoring 3 needs an urgent review
+}
+
+// escalation returns the escalation level a score calls for
+func (p EarlyWarningPolicy) escalation(score domain.NEWS2Score) domain.EscalationLevel {
+	switch {
+	case score.Total >= p.EmergencyScore:
+		return domain.EscalationEmergency
+	case score.Total >= p.UrgentScore, p.EscalateOnRed && score.RedScore:
+		return domain.EscalationUrgent
+	default:
+		return domain.EscalationNone
+	}
+}
+
+// EarlyWarningListener is called with a score whose escalation level rose,
+// loaded with its admission, patient and attending doctor
+type EarlyWarningListener func(score domain.EarlyWarningScore)
+
 // AdmissionService handles admission business logic
 type AdmissionService struct {
-	admissionRepo   *repository.AdmissionRepository
-	allocationRepo  *repository.BedAllocationRepository
-	bedRepo         *repository.BedRepository
-	visitRepo       *repository.VisitRepository
-	nursingNoteRepo *repository.NursingNoteRepository
+	admissionRepo       *repository.AdmissionRepository
+	allocationRepo      *repository.BedAllocationRepository
+	bedRepo             *repository.BedRepository
+	visitRepo           *repository.VisitRepository
+	nursingNoteRepo     *repository.NursingNoteRepository
+	earlyWarning        EarlyWarningPolicy
+	escalationListeners []EarlyWarningListener
+	clock               *clock.Clock
 }
 
 // NewAdmissionService creates a new admission service
@@ -32,6 +59,8 @@ func NewAdmissionService(
 	bedRepo *repository.BedRepository,
 	visitRepo *repository.VisitRepository,
 	nursingNoteRepo *repository.NursingNoteRepository,
+	earlyWarning EarlyWarningPolicy,
+	clk *clock.Clock,
 ) *AdmissionService {
 	return &AdmissionService{
 		admissionRepo:   admissionRepo,
@@ -39,9 +68,17 @@ func NewAdmissionService(
 		bedRepo:         bedRepo,
 		visitRepo:       visitRepo,
 		nursingNoteRepo: nursingNoteRepo,
+		earlyWarning:    earlyWarning,
+		clock:           clk,
 	}
 }
 
+// OnEscalated registers a listener called after a nursing note's early
+// warning score raises the admission's escalation level
+func (s *AdmissionService) OnEscalated(listener EarlyWarningListener) {
+	s.escalationListeners = append(s.escalationListeners, listener)
+}
+
 // CreateAdmission creates an admission with optional bed allocation
 func (s *AdmissionService) CreateAdmission(req *dto.CreateAdmissionRequest, createdBy uint) (*dto.AdmissionResponse, error) {
 	// Validate visit exists
@@ -52,6 +89,9 @@ func (s *AdmissionService) CreateAdmission(req *dto.CreateAdmissionRequest, crea
 	if visit == nil {
 		return nil, ErrVisitNotFound
 	}
+	if visit.Patient != nil && visit.Patient.IsDeceased {
+		return nil, ErrPatientDeceased
+	}
 
 	// Generate admission code
 	code, err := s.admissionRepo.GenerateAdmissionCode()
@@ -65,7 +105,7 @@ func (s *AdmissionService) CreateAdmission(req *dto.CreateAdmissionRequest, crea
 		VisitID:            req.VisitID,
 		PatientID:          visit.PatientID,
 		DoctorID:           visit.DoctorID,
-		AdmissionDate:      time.Now(),
+		AdmissionDate:      s.clock.Now(),
 		AdmissionDiagnosis: req.AdmissionDiagnosis,
 		Status:             domain.AdmissionStatusAdmitted,
 		CreatedBy:          createdBy,
@@ -108,7 +148,7 @@ func (s *AdmissionService) DischargeAdmission(id uint, req *dto.DischargeAdmissi
 	}
 
 	// Update admission
-	now := time.Now()
+	now := s.clock.Now()
 	admission.DischargeDate = &now
 	admission.DischargeDiagnosis = req.DischargeDiagnosis
 	admission.DischargeSummary = req.DischargeSummary
@@ -155,7 +195,7 @@ func (s *AdmissionService) allocateBed(admissionID, bedID uint, notes string, cr
 	allocation := &domain.BedAllocation{
 		AdmissionID:   admissionID,
 		BedID:         bedID,
-		AllocatedDate: time.Now(),
+		AllocatedDate: s.clock.Now(),
 		IsCurrent:     true,
 		Notes:         notes,
 		CreatedBy:     createdBy,
@@ -169,17 +209,114 @@ func (s *AdmissionService) allocateBed(admissionID, bedID uint, notes string, cr
 	return s.bedRepo.UpdateStatus(bedID, domain.BedStatusOccupied)
 }
 
-// CreateNursingNote creates a nursing note
-func (s *AdmissionService) CreateNursingNote(admissionID uint, req *dto.CreateNursingNoteRequest, nurseID uint) error {
+// CreateNursingNote creates a nursing note and scores its vital signs on
+// NEWS2, notifying staff when the score raises the escalation level
+func (s *AdmissionService) CreateNursingNote(admissionID uint, req *dto.CreateNursingNoteRequest, nurseID uint) (*dto.NursingNoteResponse, error) {
+	admission, err := s.admissionRepo.FindByID(admissionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find admission: %w", err)
+	}
+	if admission == nil {
+		return nil, ErrAdmissionNotFound
+	}
+
+	vitals := domain.VitalSigns{
+		Temperature:            req.VitalSigns.Temperature,
+		BloodPressureSystolic:  req.VitalSigns.BloodPressureSystolic,
+		BloodPressureDiastolic: req.VitalSigns.BloodPressureDiastolic,
+		HeartRate:              req.VitalSigns.HeartRate,
+		RespiratoryRate:        req.VitalSigns.RespiratoryRate,
+		OxygenSaturation:       req.VitalSigns.OxygenSaturation,
+		Weight:                 req.VitalSigns.Weight,
+		Height:                 req.VitalSigns.Height,
+		SupplementalOxygen:     req.VitalSigns.SupplementalOxygen,
+		Consciousness:          domain.Consciousness(req.VitalSigns.Consciousness),
+	}
+	if err := validateVitalSigns(vitals); err != nil {
+		return nil, err
+	}
+	if vitals.Weight > 0 && vitals.Height > 0 {
+		heightInMeters := vitals.Height / 100
+		vitals.BMI = vitals.Weight / (heightInMeters * heightInMeters)
+	}
+
+	now := s.clock.Now()
 	note := &domain.NursingNote{
 		AdmissionID:   admissionID,
 		NurseID:       nurseID,
-		NoteDate:      time.Now(),
-		VitalSigns:    req.VitalSigns,
+		NoteDate:      now,
+		VitalSigns:    vitals,
 		Observations:  req.Observations,
 		Interventions: req.Interventions,
 	}
-	return s.nursingNoteRepo.Create(note)
+	if !hasVitalSigns(vitals) {
+		if err := s.nursingNoteRepo.Create(note); err != nil {
+			return nil, fmt.Errorf("failed to create nursing note: %w", err)
+		}
+		return s.toNursingNoteResponse(note), nil
+	}
+
+	previous, err := s.nursingNoteRepo.FindLatestScore(admissionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find previous early warning score: %w", err)
+	}
+
+	news2 := domain.ScoreNEWS2(vitals)
+	score := &domain.EarlyWarningScore{
+		AdmissionID:     admissionID,
+		PatientID:       admission.PatientID,
+		RecordedAt:      now,
+		RecordedBy:      nurseID,
+		NEWS2Score:      news2,
+		EscalationLevel: s.earlyWarning.escalation(news2),
+	}
+	previousLevel := domain.EscalationNone
+	if previous != nil {
+		previousLevel = previous.EscalationLevel
+	}
+	score.Escalated = score.EscalationLevel.Rank() > previousLevel.Rank()
+
+	if err := s.nursingNoteRepo.CreateWithScore(note, score); err != nil {
+		return nil, fmt.Errorf("failed to create nursing note: %w", err)
+	}
+
+	if score.Escalated {
+		score.Admission = admission
+		for _, listener := range s.escalationListeners {
+			listener(*score)
+		}
+	}
+
+	note.EarlyWarningScore = score
+	return s.toNursingNoteResponse(note), nil
+}
+
+// GetEarlyWarningTrend gets an admission's NEWS2 scores over time
+func (s *AdmissionService) GetEarlyWarningTrend(admissionID uint) (*dto.EarlyWarningTrendResponse, error) {
+	admission, err := s.admissionRepo.FindByID(admissionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find admission: %w", err)
+	}
+	if admission == nil {
+		return nil, ErrAdmissionNotFound
+	}
+
+	scores, err := s.nursingNoteRepo.FindScores(admissionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get early warning scores: %w", err)
+	}
+
+	resp := &dto.EarlyWarningTrendResponse{
+		AdmissionID: admissionID,
+		Scores:      make([]*dto.EarlyWarningScoreResponse, len(scores)),
+	}
+	for i, score := range scores {
+		resp.Scores[i] = toEarlyWarningScoreResponse(score)
+	}
+	if len(scores) > 0 {
+		resp.Latest = resp.Scores[len(scores)-1]
+	}
+	return resp, nil
 }
 
 // GetAdmissionByID gets admission by ID
@@ -243,18 +380,7 @@ func (s *AdmissionService) GetAdmissionNursingNotes(admissionID uint) ([]*dto.Nu
 
 	responses := make([]*dto.NursingNoteResponse, len(notes))
 	for i, n := range notes {
-		responses[i] = &dto.NursingNoteResponse{
-			ID:            n.ID,
-			NurseID:       n.NurseID,
-			NoteDate:      n.NoteDate,
-			VitalSigns:    n.VitalSigns,
-			Observations:  n.Observations,
-			Interventions: n.Interventions,
-			CreatedAt:     n.CreatedAt,
-		}
-		if n.Nurse != nil {
-			responses[i].NurseName = n.Nurse.FullName
-		}
+		responses[i] = s.toNursingNoteResponse(n)
 	}
 	return responses, nil
 }
@@ -325,3 +451,39 @@ func (s *AdmissionService) toAdmissionListItem(a *domain.Admission) *dto.Admissi
 
 	return item
 }
+
+func (s *AdmissionService) toNursingNoteResponse(n *domain.NursingNote) *dto.NursingNoteResponse {
+	resp := &dto.NursingNoteResponse{
+		ID:            n.ID,
+		NurseID:       n.NurseID,
+		NoteDate:      n.NoteDate,
+		VitalSigns:    n.VitalSigns,
+		Observations:  n.Observations,
+		Interventions: n.Interventions,
+		CreatedAt:     n.CreatedAt,
+	}
+	if n.Nurse != nil {
+		resp.NurseName = n.Nurse.FullName
+	}
+	if n.EarlyWarningScore != nil {
+		resp.EarlyWarningScore = toEarlyWarningScoreResponse(n.EarlyWarningScore)
+	}
+	return resp
+}
+
+func toEarlyWarningScoreResponse(score *domain.EarlyWarningScore) *dto.EarlyWarningScoreResponse {
+	resp := &dto.EarlyWarningScoreResponse{
+		ID:              score.ID,
+		AdmissionID:     score.AdmissionID,
+		NursingNoteID:   score.NursingNoteID,
+		RecordedAt:      score.RecordedAt,
+		RecordedBy:      score.RecordedBy,
+		NEWS2Response:   *toNEWS2Response(score.NEWS2Score),
+		EscalationLevel: string(score.EscalationLevel),
+		Escalated:       score.Escalated,
+	}
+	if score.Recorder != nil {
+		resp.RecordedByName = score.Recorder.FullName
+	}
+	return resp
+}
@@ -0,0 +1,583 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/minhtran/his/internal/domain"
+	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/pkg/clock"
+	"github.com/minhtran/his/internal/pkg/rrule"
+	"github.com/minhtran/his/internal/repository"
+)
+
+var (
+	// ErrAppointmentSeriesNotFound is returned when the series is not found
+	ErrAppointmentSeriesNotFound = errors.New("appointment series not found")
+	// ErrInvalidRecurrenceRule is returned when the recurrence rule cannot be used
+	ErrInvalidRecurrenceRule = errors.New("invalid recurrence rule")
+	// ErrSeriesConflicts is returned when occurrences conflict and conflicts are not skipped
+	ErrSeriesConflicts = errors.New("one or more occurrences conflict with existing bookings or the doctor's schedule")
+	// ErrSeriesCancelled is returned when changing a cancelled series
+	ErrSeriesCancelled = errors.New("appointment series is cancelled")
+	// ErrSeriesAppointmentRequired is returned when the scope needs an occurrence of the series
+	ErrSeriesAppointmentRequired = errors.New("appointment_id of an occurrence in this series is required for this scope")
+	// ErrNoSeriesChange is returned when a series reschedule changes nothing
+	ErrNoSeriesChange = errors.New("provide appointment_time, shift_days or duration_minutes to reschedule the series")
+)
+
+// Series change scopes
+const (
+	SeriesScopeOne       = "ONE"
+	SeriesScopeFollowing = "FOLLOWING"
+	SeriesScopeAll       = "ALL"
+)
+
+// Occurrence conflict reasons
+const (
+	conflictPastDate           = "PAST_DATE"
+	conflictDoctorNotAvailable = "DOCTOR_NOT_AVAILABLE"
+	conflictOutsideSession     = "OUTSIDE_SESSION"
+	conflictSessionFull        = "SESSION_FULL"
+	conflictTimeSlotTaken      = "TIME_SLOT_TAKEN"
+)
+
+// AppointmentSeriesService handles recurring appointment series
+type AppointmentSeriesService struct {
+	seriesRepo         *repository.AppointmentSeriesRepository
+	appointmentRepo    *repository.AppointmentRepository
+	patientRepo        *repository.PatientRepository
+	userRepo           *repository.UserRepository
+	appointmentService *AppointmentService
+	clock              *clock.Clock
+}
+
+// NewAppointmentSeriesService creates a new appointment series service
+func NewAppointmentSeriesService(
+	seriesRepo *repository.AppointmentSeriesRepository,
+	appointmentRepo *repository.AppointmentRepository,
+	patientRepo *repository.PatientRepository,
+	userRepo *repository.UserRepository,
+	appointmentService *AppointmentService,
+	clk *clock.Clock,
+) *AppointmentSeriesService {
+	return &AppointmentSeriesService{
+		seriesRepo:         seriesRepo,
+		appointmentRepo:    appointmentRepo,
+		patientRepo:        patientRepo,
+		userRepo:           userRepo,
+		appointmentService: appointmentService,
+		clock:              clk,
+	}
+}
+
+// plannedOccurrence is an expanded occurrence with the slot it takes, which is
+// nil when the occurrence conflicts
+type plannedOccurrence struct {
+	occurrence *dto.SeriesOccurrence
+	date       time.Time
+	time       time.Time
+	slot       *occurrenceSlot
+}
+
+// occurrenceSlot is the clinic session an occurrence falls in, the session's
+// booking limit and per-slot capacity, and the occurrence's resolved duration
+type occurrenceSlot struct {
+	session  *clinicSession
+	limit    int
+	capacity int
+	duration int
+}
+
+// PreviewSeries expands the recurrence rule and checks every occurrence for conflicts
+func (s *AppointmentSeriesService) PreviewSeries(req *dto.CreateAppointmentSeriesRequest) (*dto.SeriesPreviewResponse, error) {
+	rule, planned, err := s.plan(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.SeriesPreviewResponse{
+		RecurrenceRule: rule.String(),
+		Occurrences:    make([]*dto.SeriesOccurrence, len(planned)),
+	}
+	for i, p := range planned {
+		resp.Occurrences[i] = p.occurrence
+		if !p.occurrence.Available {
+			resp.ConflictCount++
+		}
+	}
+	return resp, nil
+}
+
+// CreateSeries books every occurrence of a series. When some occurrences conflict
+// and SkipConflicts is false nothing is booked and the conflicts are returned.
+func (s *AppointmentSeriesService) CreateSeries(req *dto.CreateAppointmentSeriesRequest, createdBy uint) (*dto.AppointmentSeriesResponse, []*dto.SeriesOccurrence, error) {
+	rule, planned, err := s.plan(req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	startDate, _ := time.Parse("2006-01-02", req.StartDate)
+	series := &domain.AppointmentSeries{
+		PatientID:       req.PatientID,
+		DoctorID:        req.DoctorID,
+		RecurrenceRule:  rule.String(),
+		StartDate:       startDate,
+		AppointmentTime: req.AppointmentTime,
+		AppointmentType: domain.AppointmentType(req.AppointmentType),
+		Reason:          req.Reason,
+		Notes:           req.Notes,
+		Status:          domain.SeriesStatusActive,
+		CreatedBy:       createdBy,
+		UpdatedBy:       createdBy,
+	}
+
+	// Check every occurrence again and create the series under the doctor's
+	// booking lock, so concurrent bookings cannot take a planned slot
+	var conflicts []*dto.SeriesOccurrence
+	err = s.appointmentRepo.WithDoctorLock(req.DoctorID, nil, func(repo *repository.AppointmentRepository, _ *repository.ResourceRepository) error {
+		var appointments []*domain.Appointment
+		for _, p := range planned {
+			if p.slot != nil {
+				conflict, err := s.checkSlotFree(repo, req.DoctorID, p.date, p.time, p.slot, nil)
+				if err != nil {
+					return err
+				}
+				if conflict != "" {
+					p.occurrence.Available = false
+					p.occurrence.Conflict = conflict
+				}
+			}
+			if !p.occurrence.Available {
+				conflicts = append(conflicts, p.occurrence)
+				continue
+			}
+			appointments = append(appointments, &domain.Appointment{
+				PatientID:       req.PatientID,
+				DoctorID:        req.DoctorID,
+				AppointmentDate: p.date,
+				AppointmentTime: p.time,
+				DurationMinutes: p.slot.duration,
+				AppointmentType: domain.AppointmentType(req.AppointmentType),
+				Status:          domain.AppointmentStatusScheduled,
+				Reason:          req.Reason,
+				Notes:           req.Notes,
+				BookingSource:   domain.BookingSourceStaff,
+				SeriesIndex:     p.occurrence.Index,
+				CreatedBy:       &createdBy,
+				UpdatedBy:       createdBy,
+			})
+		}
+
+		if len(appointments) == 0 || (len(conflicts) > 0 && !req.SkipConflicts) {
+			return ErrSeriesConflicts
+		}
+
+		series.DurationMinutes = appointments[0].DurationMinutes
+		if err := repo.CreateSeries(series, appointments); err != nil {
+			return fmt.Errorf("failed to create appointment series: %w", err)
+		}
+		return nil
+	})
+	if errors.Is(err, ErrSeriesConflicts) {
+		return nil, conflicts, err
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := s.GetSeries(series.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp.Skipped = conflicts
+	return resp, nil, nil
+}
+
+// GetSeries gets series details with its occurrences
+func (s *AppointmentSeriesService) GetSeries(id uint) (*dto.AppointmentSeriesResponse, error) {
+	series, err := s.seriesRepo.FindByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find appointment series: %w", err)
+	}
+	if series == nil {
+		return nil, ErrAppointmentSeriesNotFound
+	}
+	return s.toSeriesResponse(series), nil
+}
+
+// RescheduleSeries moves one occurrence, an occurrence and the following ones,
+// or all upcoming occurrences. Bulk moves are checked first and applied
+// together; if any occurrence conflicts nothing changes and the conflicts are returned.
+func (s *AppointmentSeriesService) RescheduleSeries(id uint, req *dto.RescheduleSeriesRequest, updatedBy uint) (*dto.AppointmentSeriesResponse, []*dto.SeriesOccurrence, error) {
+	series, anchor, err := s.loadForChange(id, req.Scope, req.AppointmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if req.Scope == SeriesScopeOne {
+		_, err := s.appointmentService.RescheduleAppointment(anchor.ID, &dto.UpdateAppointmentRequest{
+			AppointmentDate: req.AppointmentDate,
+			AppointmentTime: req.AppointmentTime,
+			DurationMinutes: req.DurationMinutes,
+		}, updatedBy)
+		if err != nil {
+			return nil, nil, err
+		}
+		resp, err := s.GetSeries(id)
+		return resp, nil, err
+	}
+
+	if req.AppointmentTime == "" && req.ShiftDays == 0 && req.DurationMinutes == 0 {
+		return nil, nil, ErrNoSeriesChange
+	}
+
+	var newTime time.Time
+	if req.AppointmentTime != "" {
+		newTime, _ = time.Parse("15:04", req.AppointmentTime)
+	}
+
+	targets := s.targets(series, req.Scope, anchor)
+	excludeIDs := make([]uint, len(targets))
+	released := make([]domain.Appointment, len(targets))
+	for i, apt := range targets {
+		excludeIDs[i] = apt.ID
+		released[i] = *apt
+	}
+
+	// Check every moved occurrence before changing any
+	today := s.clock.Today()
+	moves := make([]*plannedOccurrence, len(targets))
+	var conflicts []*dto.SeriesOccurrence
+	for i, apt := range targets {
+		date := clock.Day(apt.AppointmentDate).AddDate(0, 0, req.ShiftDays)
+		at := apt.AppointmentTime
+		if req.AppointmentTime != "" {
+			at = newTime
+		}
+		duration := apt.DurationMinutes
+		if req.DurationMinutes > 0 {
+			duration = req.DurationMinutes
+		}
+
+		occurrence := &dto.SeriesOccurrence{
+			Index:           apt.SeriesIndex,
+			AppointmentID:   apt.ID,
+			AppointmentDate: date.Format("2006-01-02"),
+			AppointmentTime: at.Format("15:04"),
+		}
+		slot, conflict, err := s.checkOccurrence(series.DoctorID, date, at, duration, excludeIDs, today)
+		if err != nil {
+			return nil, nil, err
+		}
+		if conflict != "" {
+			occurrence.Conflict = conflict
+			conflicts = append(conflicts, occurrence)
+			continue
+		}
+		moves[i] = &plannedOccurrence{occurrence: occurrence, date: date, time: at, slot: slot}
+	}
+
+	if len(conflicts) > 0 {
+		return nil, conflicts, ErrSeriesConflicts
+	}
+
+	if req.AppointmentTime != "" {
+		series.AppointmentTime = req.AppointmentTime
+	}
+	if req.DurationMinutes > 0 {
+		series.DurationMinutes = req.DurationMinutes
+	}
+	series.UpdatedBy = updatedBy
+
+	// Check every move again and save them under the doctor's booking lock,
+	// so concurrent bookings cannot take a slot the occurrences move to
+	err = s.appointmentRepo.WithDoctorLock(series.DoctorID, nil, func(repo *repository.AppointmentRepository, _ *repository.ResourceRepository) error {
+		for _, move := range moves {
+			conflict, err := s.checkSlotFree(repo, series.DoctorID, move.date, move.time, move.slot, excludeIDs)
+			if err != nil {
+				return err
+			}
+			if conflict != "" {
+				move.occurrence.Conflict = conflict
+				conflicts = append(conflicts, move.occurrence)
+			}
+		}
+		if len(conflicts) > 0 {
+			return ErrSeriesConflicts
+		}
+
+		for i, apt := range targets {
+			apt.AppointmentDate = moves[i].date
+			apt.AppointmentTime = moves[i].time
+			apt.DurationMinutes = moves[i].slot.duration
+			apt.UpdatedBy = updatedBy
+		}
+		if err := repo.UpdateSeries(series, targets, nil); err != nil {
+			return fmt.Errorf("failed to reschedule appointment series: %w", err)
+		}
+		return nil
+	})
+	if errors.Is(err, ErrSeriesConflicts) {
+		return nil, conflicts, err
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, apt := range released {
+		s.appointmentService.releaseSlot(apt)
+	}
+
+	resp, err := s.GetSeries(id)
+	return resp, nil, err
+}
+
+// CancelSeries cancels one occurrence, an occurrence and the following ones, or
+// all upcoming occurrences. The series is cancelled once nothing is left booked.
+func (s *AppointmentSeriesService) CancelSeries(id uint, req *dto.CancelSeriesRequest, cancelledBy uint) (*dto.AppointmentSeriesResponse, error) {
+	series, anchor, err := s.loadForChange(id, req.Scope, req.AppointmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Scope == SeriesScopeOne {
+		if _, err := s.appointmentService.CancelAppointment(anchor.ID, req.Reason, cancelledBy); err != nil {
+			return nil, err
+		}
+		if series, err = s.seriesRepo.FindByID(id); err != nil {
+			return nil, fmt.Errorf("failed to find appointment series: %w", err)
+		}
+	} else {
+		now := s.clock.Now()
+		targets := s.targets(series, req.Scope, anchor)
+		history := make([]*domain.AppointmentStatusHistory, len(targets))
+		for i, apt := range targets {
+			history[i] = &domain.AppointmentStatusHistory{
+				AppointmentID: apt.ID,
+				FromStatus:    apt.Status,
+				ToStatus:      domain.AppointmentStatusCancelled,
+				Reason:        req.Reason,
+				ChangedAt:     now,
+				ChangedBy:     &cancelledBy,
+			}
+			apt.Status = domain.AppointmentStatusCancelled
+			apt.CancelledReason = req.Reason
+			apt.CancelledAt = &now
+			apt.CancelledBy = &cancelledBy
+			apt.UpdatedBy = cancelledBy
+		}
+		if err := s.appointmentRepo.UpdateSeries(series, targets, history); err != nil {
+			return nil, fmt.Errorf("failed to cancel appointment series: %w", err)
+		}
+		for _, apt := range targets {
+			s.appointmentService.releaseSlot(*apt)
+		}
+	}
+
+	// Close the series once no occurrence is still booked
+	if len(s.targets(series, SeriesScopeAll, nil)) == 0 {
+		series.Status = domain.SeriesStatusCancelled
+		series.UpdatedBy = cancelledBy
+		if err := s.seriesRepo.Update(series); err != nil {
+			return nil, fmt.Errorf("failed to update appointment series: %w", err)
+		}
+	}
+
+	return s.GetSeries(id)
+}
+
+// plan validates a series request and expands and checks its occurrences
+func (s *AppointmentSeriesService) plan(req *dto.CreateAppointmentSeriesRequest) (*rrule.Rule, []*plannedOccurrence, error) {
+	patient, err := s.patientRepo.FindByID(req.PatientID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find patient: %w", err)
+	}
+	if patient == nil {
+		return nil, nil, ErrPatientNotFound
+	}
+	if patient.IsDeceased {
+		return nil, nil, ErrPatientDeceased
+	}
+
+	doctor, err := s.userRepo.FindByID(req.DoctorID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find doctor: %w", err)
+	}
+	if doctor == nil {
+		return nil, nil, ErrDoctorNotFound
+	}
+
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return nil, nil, ErrInvalidDateFormat
+	}
+	at, _ := time.Parse("15:04", req.AppointmentTime)
+
+	rule, err := rrule.Parse(req.RecurrenceRule)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrInvalidRecurrenceRule, err.Error())
+	}
+	dates, err := rule.Dates(startDate)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrInvalidRecurrenceRule, err.Error())
+	}
+	if len(dates) == 0 {
+		return nil, nil, fmt.Errorf("%w: no occurrences from the start date", ErrInvalidRecurrenceRule)
+	}
+
+	today := s.clock.Today()
+	planned := make([]*plannedOccurrence, len(dates))
+	for i, date := range dates {
+		slot, conflict, err := s.checkOccurrence(req.DoctorID, date, at, req.DurationMinutes, nil, today)
+		if err != nil {
+			return nil, nil, err
+		}
+		planned[i] = &plannedOccurrence{
+			occurrence: &dto.SeriesOccurrence{
+				Index:           i + 1,
+				AppointmentDate: date.Format("2006-01-02"),
+				AppointmentTime: req.AppointmentTime,
+				Available:       conflict == "",
+				Conflict:        conflict,
+			},
+			date: date,
+			time: at,
+			slot: slot,
+		}
+	}
+
+	return rule, planned, nil
+}
+
+// checkOccurrence checks one occurrence against the doctor's clinic sessions and
+// existing bookings, returning the slot it takes or a conflict reason
+func (s *AppointmentSeriesService) checkOccurrence(doctorID uint, date, at time.Time, duration int, excludeIDs []uint, today time.Time) (*occurrenceSlot, string, error) {
+	if date.Before(today) {
+		return nil, conflictPastDate, nil
+	}
+
+	session, duration, err := s.appointmentService.findClinicSession(doctorID, date, at, duration)
+	switch {
+	case errors.Is(err, ErrDoctorNotAvailable):
+		return nil, conflictDoctorNotAvailable, nil
+	case errors.Is(err, ErrInvalidAppointmentTime):
+		return nil, conflictOutsideSession, nil
+	case err != nil:
+		return nil, "", err
+	}
+	limit, capacity, err := s.appointmentService.sessionLimit(doctorID, session)
+	if err != nil {
+		return nil, "", err
+	}
+
+	slot := &occurrenceSlot{session: session, limit: limit, capacity: capacity, duration: duration}
+	conflict, err := s.checkSlotFree(s.appointmentRepo, doctorID, date, at, slot, excludeIDs)
+	if err != nil || conflict != "" {
+		return nil, conflict, err
+	}
+	return slot, "", nil
+}
+
+// checkSlotFree checks, through repo, that an occurrence's clinic session has
+// capacity left and that its time slot is free, returning a conflict reason
+func (s *AppointmentSeriesService) checkSlotFree(repo *repository.AppointmentRepository, doctorID uint, date, at time.Time, slot *occurrenceSlot, excludeIDs []uint) (string, error) {
+	err := checkSessionCapacity(repo, slot.session, slot.limit, doctorID, date, excludeIDs)
+	if errors.Is(err, ErrClinicSessionFull) {
+		return conflictSessionFull, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	available, err := repo.CheckTimeSlotCapacity(doctorID, date, at, slot.duration, slot.capacity, excludeIDs)
+	if err != nil {
+		return "", fmt.Errorf("failed to check time slot: %w", err)
+	}
+	if !available {
+		return conflictTimeSlotTaken, nil
+	}
+	return "", nil
+}
+
+// loadForChange loads an active series and, for ONE and FOLLOWING, the occurrence the change starts at
+func (s *AppointmentSeriesService) loadForChange(id uint, scope string, appointmentID uint) (*domain.AppointmentSeries, *domain.Appointment, error) {
+	series, err := s.seriesRepo.FindByID(id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find appointment series: %w", err)
+	}
+	if series == nil {
+		return nil, nil, ErrAppointmentSeriesNotFound
+	}
+	if series.Status == domain.SeriesStatusCancelled {
+		return nil, nil, ErrSeriesCancelled
+	}
+
+	if scope == SeriesScopeAll {
+		return series, nil, nil
+	}
+	for _, apt := range series.Appointments {
+		if apt.ID == appointmentID {
+			return series, apt, nil
+		}
+	}
+	return nil, nil, ErrSeriesAppointmentRequired
+}
+
+// targets returns the still-booked occurrences a FOLLOWING or ALL change applies to;
+// ALL covers occurrences from today onwards
+func (s *AppointmentSeriesService) targets(series *domain.AppointmentSeries, scope string, anchor *domain.Appointment) []*domain.Appointment {
+	today := s.clock.Today()
+
+	var targets []*domain.Appointment
+	for _, apt := range series.Appointments {
+		if apt.Status != domain.AppointmentStatusScheduled && apt.Status != domain.AppointmentStatusConfirmed {
+			continue
+		}
+		if scope == SeriesScopeFollowing && anchor != nil {
+			if apt.SeriesIndex < anchor.SeriesIndex {
+				continue
+			}
+		} else if clock.Day(apt.AppointmentDate).Before(today) {
+			continue
+		}
+		targets = append(targets, apt)
+	}
+	return targets
+}
+
+// Helper functions
+func (s *AppointmentSeriesService) toSeriesResponse(series *domain.AppointmentSeries) *dto.AppointmentSeriesResponse {
+	resp := &dto.AppointmentSeriesResponse{
+		ID:              series.ID,
+		PatientID:       series.PatientID,
+		DoctorID:        series.DoctorID,
+		RecurrenceRule:  series.RecurrenceRule,
+		StartDate:       series.StartDate.Format("2006-01-02"),
+		AppointmentTime: series.AppointmentTime,
+		DurationMinutes: series.DurationMinutes,
+		AppointmentType: string(series.AppointmentType),
+		Status:          string(series.Status),
+		Reason:          series.Reason,
+		Notes:           series.Notes,
+		Appointments:    make([]*dto.AppointmentListItem, len(series.Appointments)),
+		CreatedAt:       series.CreatedAt,
+		UpdatedAt:       series.UpdatedAt,
+	}
+
+	if series.Patient != nil {
+		resp.PatientName = series.Patient.FullName
+	}
+	if series.Doctor != nil {
+		resp.DoctorName = series.Doctor.FullName
+	}
+
+	for i, apt := range series.Appointments {
+		item := s.appointmentService.toAppointmentListItem(apt)
+		item.PatientName = resp.PatientName
+		item.DoctorName = resp.DoctorName
+		resp.Appointments[i] = item
+	}
+
+	return resp
+}
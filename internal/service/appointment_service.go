@@ -7,22 +7,44 @@ import (
 
 	"github.com/minhtran/his/internal/domain"
 	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/pkg/clock"
 	"github.com/minhtran/his/internal/repository"
 )
 
 var (
-	ErrAppointmentNotFound     = errors.New("appointment not found")
-	ErrTimeSlotNotAvailable    = errors.New("time slot not available")
-	ErrInvalidAppointmentTime  = errors.New("appointment time must be during working hours (08:00-17:00)")
-	ErrPastAppointmentDate     = errors.New("appointment date must be today or in the future")
-	ErrInvalidStatusTransition = errors.New("invalid status transition")
+	ErrAppointmentNotFound      = errors.New("appointment not found")
+	ErrTimeSlotNotAvailable     = errors.New("time slot not available")
+	ErrInvalidAppointmentTime   = errors.New("appointment time must fall inside one of the doctor's clinic sessions and outside its break")
+	ErrDoctorNotAvailable       = errors.New("doctor is not available on this date")
+	ErrClinicSessionFull        = errors.New("clinic session is fully booked")
+	ErrPastAppointmentDate      = errors.New("appointment date must be today or in the future")
+	ErrInvalidStatusTransition  = errors.New("invalid status transition")
+	ErrAvailabilityRangeTooLong = errors.New("availability can be searched over at most 31 days")
 )
 
+// maxAvailabilityDays caps the date range of one availability search
+const maxAvailabilityDays = 31
+
+// SlotReleaseListener is called with an appointment's previous booking after
+// a cancellation or reschedule frees its slot
+type SlotReleaseListener func(released domain.Appointment)
+
+// BookingListener is called with a newly scheduled appointment, loaded with
+// its patient and doctor
+type BookingListener func(booked domain.Appointment)
+
 // AppointmentService handles appointment business logic
 type AppointmentService struct {
-	appointmentRepo *repository.AppointmentRepository
-	patientRepo     *repository.PatientRepository
-	userRepo        *repository.UserRepository
+	appointmentRepo  *repository.AppointmentRepository
+	patientRepo      *repository.PatientRepository
+	userRepo         *repository.UserRepository
+	scheduleRepo     *repository.DoctorScheduleRepository
+	resourceRepo     *repository.ResourceRepository
+	departmentRepo   *repository.DepartmentRepository
+	noShowService    *NoShowService
+	slotListeners    []SlotReleaseListener
+	bookingListeners []BookingListener
+	clock            *clock.Clock
 }
 
 // NewAppointmentService creates a new appointment service
@@ -30,16 +52,61 @@ func NewAppointmentService(
 	appointmentRepo *repository.AppointmentRepository,
 	patientRepo *repository.PatientRepository,
 	userRepo *repository.UserRepository,
+	scheduleRepo *repository.DoctorScheduleRepository,
+	resourceRepo *repository.ResourceRepository,
+	departmentRepo *repository.DepartmentRepository,
+	noShowService *NoShowService,
+	clk *clock.Clock,
 ) *AppointmentService {
 	return &AppointmentService{
 		appointmentRepo: appointmentRepo,
 		patientRepo:     patientRepo,
 		userRepo:        userRepo,
+		scheduleRepo:    scheduleRepo,
+		resourceRepo:    resourceRepo,
+		departmentRepo:  departmentRepo,
+		noShowService:   noShowService,
+		clock:           clk,
 	}
 }
 
+// OnSlotReleased registers a listener notified whenever a booked slot is freed
+func (s *AppointmentService) OnSlotReleased(listener SlotReleaseListener) {
+	s.slotListeners = append(s.slotListeners, listener)
+}
+
+// OnBooked registers a listener notified whenever an appointment is scheduled
+func (s *AppointmentService) OnBooked(listener BookingListener) {
+	s.bookingListeners = append(s.bookingListeners, listener)
+}
+
 // ScheduleAppointment schedules a new appointment
 func (s *AppointmentService) ScheduleAppointment(req *dto.CreateAppointmentRequest, createdBy uint) (*dto.AppointmentResponse, error) {
+	return s.schedule(req, domain.BookingSourceStaff, &createdBy, nil, nil, nil)
+}
+
+// SchedulePortalAppointment schedules an appointment booked by a patient through the portal
+func (s *AppointmentService) SchedulePortalAppointment(req *dto.CreateAppointmentRequest, portalAccountID uint) (*dto.AppointmentResponse, error) {
+	return s.schedule(req, domain.BookingSourcePortal, nil, &portalAccountID, nil, nil)
+}
+
+// ScheduleOnlineAppointment schedules an appointment booked through the public
+// booking site into the slot of a verified hold, confirming the hold in the
+// same transaction
+func (s *AppointmentService) ScheduleOnlineAppointment(req *dto.CreateAppointmentRequest, hold *domain.BookingHold) (*dto.AppointmentResponse, error) {
+	return s.schedule(req, domain.BookingSourceOnline, nil, nil, hold, nil)
+}
+
+// ScheduleWaitlistAppointment schedules the appointment a waitlisted patient
+// accepted an offer for into the offer's slot, accepting the offer and
+// booking the entry in the same transaction
+func (s *AppointmentService) ScheduleWaitlistAppointment(req *dto.CreateAppointmentRequest, offer *domain.WaitlistOffer, acceptedBy uint) (*dto.AppointmentResponse, error) {
+	return s.schedule(req, domain.BookingSourceStaff, &acceptedBy, nil, nil, offer)
+}
+
+// schedule validates and creates an appointment, confirming the online booking
+// hold or accepting the waitlist offer it takes the slot of, if any
+func (s *AppointmentService) schedule(req *dto.CreateAppointmentRequest, source domain.BookingSource, createdBy, portalAccountID *uint, hold *domain.BookingHold, offer *domain.WaitlistOffer) (*dto.AppointmentResponse, error) {
 	// Validate patient exists
 	patient, err := s.patientRepo.FindByID(req.PatientID)
 	if err != nil {
@@ -48,6 +115,12 @@ func (s *AppointmentService) ScheduleAppointment(req *dto.CreateAppointmentReque
 	if patient == nil {
 		return nil, ErrPatientNotFound
 	}
+	if patient.IsDeceased {
+		return nil, ErrPatientDeceased
+	}
+	if err := s.noShowService.CheckBookingAllowed(patient.ID, source); err != nil {
+		return nil, err
+	}
 
 	// Validate doctor exists
 	doctor, err := s.userRepo.FindByID(req.DoctorID)
@@ -70,41 +143,28 @@ func (s *AppointmentService) ScheduleAppointment(req *dto.CreateAppointmentReque
 	}
 
 	// Validate appointment date is not in the past
-	today := time.Now().Truncate(24 * time.Hour)
-	if appointmentDate.Before(today) {
+	if appointmentDate.Before(s.clock.Today()) {
 		return nil, ErrPastAppointmentDate
 	}
 
-	// Validate working hours (8:00 - 17:00)
-	hour := appointmentTime.Hour()
-	if hour < 8 || hour >= 17 {
-		return nil, ErrInvalidAppointmentTime
-	}
-
-	// Set default duration if not provided
-	duration := req.DurationMinutes
-	if duration == 0 {
-		duration = 30
-	}
-
-	// Check time slot availability
-	available, err := s.appointmentRepo.CheckTimeSlotAvailable(req.DoctorID, appointmentDate, appointmentTime, duration, nil)
+	// Validate against the doctor's clinic sessions; duration defaults to the session's slot length
+	session, duration, err := s.findClinicSession(req.DoctorID, appointmentDate, appointmentTime, req.DurationMinutes)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check time slot: %w", err)
+		return nil, err
 	}
-	if !available {
-		return nil, ErrTimeSlotNotAvailable
+	limit, capacity, err := s.sessionLimit(req.DoctorID, session)
+	if err != nil {
+		return nil, err
 	}
 
-	// Generate appointment code
-	code, err := s.appointmentRepo.GenerateAppointmentCode()
+	// Rooms and equipment the appointment reserves
+	resources, resourceIDs, err := loadBookableResources(s.resourceRepo, req.ResourceIDs)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate appointment code: %w", err)
+		return nil, err
 	}
+	startAt, endAt := appointmentPeriod(s.clock, appointmentDate, appointmentTime, duration)
 
-	// Create appointment
 	appointment := &domain.Appointment{
-		AppointmentCode: code,
 		PatientID:       req.PatientID,
 		DoctorID:        req.DoctorID,
 		AppointmentDate: appointmentDate,
@@ -114,16 +174,85 @@ func (s *AppointmentService) ScheduleAppointment(req *dto.CreateAppointmentReque
 		Status:          domain.AppointmentStatusScheduled,
 		Reason:          req.Reason,
 		Notes:           req.Notes,
+		BookingSource:   source,
+		PortalAccountID: portalAccountID,
 		CreatedBy:       createdBy,
 	}
 
-	if err := s.appointmentRepo.Create(appointment); err != nil {
-		return nil, fmt.Errorf("failed to create appointment: %w", err)
+	// Check the session's capacity, the slot and the resources and create the
+	// appointment under the doctor's and resources' booking locks, so concurrent
+	// bookings cannot take the same slot or resource
+	err = s.appointmentRepo.WithDoctorLock(req.DoctorID, resourceIDs, func(repo *repository.AppointmentRepository, resourceRepo *repository.ResourceRepository) error {
+		// The hold gives its slot over to the booking that confirms it
+		if hold != nil {
+			confirmed, err := repo.ConfirmBookingHold(hold, s.clock.Now())
+			if err != nil {
+				return fmt.Errorf("failed to confirm booking hold: %w", err)
+			}
+			if !confirmed {
+				return ErrBookingHoldNotFound
+			}
+		}
+		// So does the waitlist offer the patient accepts
+		if offer != nil {
+			accepted, err := repo.AcceptWaitlistOffer(offer, *createdBy, s.clock.Now())
+			if err != nil {
+				return fmt.Errorf("failed to accept waitlist offer: %w", err)
+			}
+			if !accepted {
+				return ErrWaitlistOfferNotPending
+			}
+		}
+
+		if err := checkSessionCapacity(repo, session, limit, req.DoctorID, appointmentDate, nil); err != nil {
+			return err
+		}
+
+		available, err := repo.CheckTimeSlotCapacity(req.DoctorID, appointmentDate, appointmentTime, duration, capacity, nil)
+		if err != nil {
+			return fmt.Errorf("failed to check time slot: %w", err)
+		}
+		if !available {
+			return ErrTimeSlotNotAvailable
+		}
+		if err := checkResourcesFree(resourceRepo, resources, startAt, endAt, nil, nil); err != nil {
+			return err
+		}
+
+		code, err := repo.GenerateAppointmentCode()
+		if err != nil {
+			return fmt.Errorf("failed to generate appointment code: %w", err)
+		}
+		appointment.AppointmentCode = code
+
+		if err := repo.Create(appointment); err != nil {
+			return fmt.Errorf("failed to create appointment: %w", err)
+		}
+		if err := resourceRepo.CreateBookings(newResourceBookings(resources, startAt, endAt, &appointment.ID, nil, createdBy)); err != nil {
+			return fmt.Errorf("failed to book resources: %w", err)
+		}
+		if hold != nil {
+			if err := repo.LinkBookingHold(hold.ID, appointment.ID); err != nil {
+				return fmt.Errorf("failed to update booking hold: %w", err)
+			}
+		}
+		if offer != nil {
+			if err := repo.LinkWaitlistOffer(offer, appointment.ID, *createdBy); err != nil {
+				return fmt.Errorf("failed to update waitlist entry: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	// Reload to get relationships
 	appointment, _ = s.appointmentRepo.FindByID(appointment.ID)
-	return s.toAppointmentResponse(appointment), nil
+	for _, listener := range s.bookingListeners {
+		listener(*appointment)
+	}
+	return s.toAppointmentResponseWithResources(appointment)
 }
 
 // RescheduleAppointment reschedules an appointment
@@ -136,22 +265,25 @@ func (s *AppointmentService) RescheduleAppointment(id uint, req *dto.UpdateAppoi
 		return nil, ErrAppointmentNotFound
 	}
 
-	// Can't reschedule completed or cancelled appointments
-	if appointment.Status == domain.AppointmentStatusCompleted || appointment.Status == domain.AppointmentStatusCancelled {
-		return nil, errors.New("cannot reschedule completed or cancelled appointment")
+	// Only appointments that have not started can be moved
+	if appointment.Status != domain.AppointmentStatusScheduled && appointment.Status != domain.AppointmentStatusConfirmed {
+		return nil, fmt.Errorf("%w: cannot reschedule a %s appointment", ErrInvalidStatusTransition, appointment.Status)
 	}
 
+	released := *appointment
+	slotChanged := false
+
 	// Update date if provided
 	if req.AppointmentDate != "" {
 		newDate, err := time.Parse("2006-01-02", req.AppointmentDate)
 		if err != nil {
 			return nil, ErrInvalidDateFormat
 		}
-		today := time.Now().Truncate(24 * time.Hour)
-		if newDate.Before(today) {
+		if newDate.Before(s.clock.Today()) {
 			return nil, ErrPastAppointmentDate
 		}
 		appointment.AppointmentDate = newDate
+		slotChanged = true
 	}
 
 	// Update time if provided
@@ -160,31 +292,34 @@ func (s *AppointmentService) RescheduleAppointment(id uint, req *dto.UpdateAppoi
 		if err != nil {
 			return nil, errors.New("invalid time format, use HH:MM")
 		}
-		hour := newTime.Hour()
-		if hour < 8 || hour >= 17 {
-			return nil, ErrInvalidAppointmentTime
-		}
 		appointment.AppointmentTime = newTime
+		slotChanged = true
 	}
 
 	// Update duration if provided
 	if req.DurationMinutes > 0 {
 		appointment.DurationMinutes = req.DurationMinutes
-	}
-
-	// Check new time slot availability
-	available, err := s.appointmentRepo.CheckTimeSlotAvailable(
-		appointment.DoctorID,
-		appointment.AppointmentDate,
-		appointment.AppointmentTime,
-		appointment.DurationMinutes,
-		&appointment.ID,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to check time slot: %w", err)
-	}
-	if !available {
-		return nil, ErrTimeSlotNotAvailable
+		slotChanged = true
+	}
+
+	// Validate the new slot against the doctor's clinic sessions. An unmoved
+	// appointment keeps its slot at the session's per-slot capacity, and is
+	// not checked when its session no longer exists.
+	var session *clinicSession
+	limit, capacity, checkSlot := 0, 1, true
+	cs, _, err := s.findClinicSession(appointment.DoctorID, appointment.AppointmentDate, appointment.AppointmentTime, appointment.DurationMinutes)
+	switch {
+	case err == nil:
+		if limit, capacity, err = s.sessionLimit(appointment.DoctorID, cs); err != nil {
+			return nil, err
+		}
+		if slotChanged {
+			session = cs
+		}
+	case slotChanged, !errors.Is(err, ErrDoctorNotAvailable) && !errors.Is(err, ErrInvalidAppointmentTime):
+		return nil, err
+	default:
+		checkSlot = false
 	}
 
 	// Update other fields
@@ -197,16 +332,87 @@ func (s *AppointmentService) RescheduleAppointment(id uint, req *dto.UpdateAppoi
 
 	appointment.UpdatedBy = updatedBy
 
-	if err := s.appointmentRepo.Update(appointment); err != nil {
-		return nil, fmt.Errorf("failed to update appointment: %w", err)
+	// Resources booked for the appointment move with it
+	var resources []*domain.Resource
+	var resourceIDs []uint
+	if slotChanged {
+		bookings, err := s.resourceRepo.FindBookingsByAppointment(appointment.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find resource bookings: %w", err)
+		}
+		ids := make([]uint, len(bookings))
+		for i, b := range bookings {
+			ids[i] = b.ResourceID
+		}
+		if resources, resourceIDs, err = loadBookableResources(s.resourceRepo, ids); err != nil {
+			return nil, err
+		}
+	}
+	startAt, endAt := appointmentPeriod(s.clock, appointment.AppointmentDate, appointment.AppointmentTime, appointment.DurationMinutes)
+
+	// Check the new slot and move the appointment under the doctor's and
+	// resources' booking locks
+	err = s.appointmentRepo.WithDoctorLock(appointment.DoctorID, resourceIDs, func(repo *repository.AppointmentRepository, resourceRepo *repository.ResourceRepository) error {
+		if session != nil {
+			if err := checkSessionCapacity(repo, session, limit, appointment.DoctorID, appointment.AppointmentDate, []uint{appointment.ID}); err != nil {
+				return err
+			}
+		}
+
+		if checkSlot {
+			available, err := repo.CheckTimeSlotCapacity(
+				appointment.DoctorID,
+				appointment.AppointmentDate,
+				appointment.AppointmentTime,
+				appointment.DurationMinutes,
+				capacity,
+				[]uint{appointment.ID},
+			)
+			if err != nil {
+				return fmt.Errorf("failed to check time slot: %w", err)
+			}
+			if !available {
+				return ErrTimeSlotNotAvailable
+			}
+		}
+		if err := checkResourcesFree(resourceRepo, resources, startAt, endAt, &appointment.ID, nil); err != nil {
+			return err
+		}
+
+		if err := repo.Update(appointment); err != nil {
+			return fmt.Errorf("failed to update appointment: %w", err)
+		}
+		if len(resources) > 0 {
+			if err := resourceRepo.MoveAppointmentBookings(appointment.ID, startAt, endAt); err != nil {
+				return fmt.Errorf("failed to move resource bookings: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if slotChanged {
+		s.releaseSlot(released)
 	}
 
 	appointment, _ = s.appointmentRepo.FindByID(appointment.ID)
-	return s.toAppointmentResponse(appointment), nil
+	return s.toAppointmentResponseWithResources(appointment)
 }
 
 // CancelAppointment cancels an appointment
 func (s *AppointmentService) CancelAppointment(id uint, reason string, cancelledBy uint) (*dto.AppointmentResponse, error) {
+	return s.cancel(id, reason, &cancelledBy)
+}
+
+// CancelPortalAppointment cancels an appointment on the patient's request through the portal
+func (s *AppointmentService) CancelPortalAppointment(id uint, reason string) (*dto.AppointmentResponse, error) {
+	return s.cancel(id, reason, nil)
+}
+
+// cancel cancels an appointment; cancelledBy is nil when the patient cancels
+func (s *AppointmentService) cancel(id uint, reason string, cancelledBy *uint) (*dto.AppointmentResponse, error) {
 	appointment, err := s.appointmentRepo.FindByID(id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find appointment: %w", err)
@@ -215,24 +421,17 @@ func (s *AppointmentService) CancelAppointment(id uint, reason string, cancelled
 		return nil, ErrAppointmentNotFound
 	}
 
-	if appointment.Status == domain.AppointmentStatusCompleted {
-		return nil, errors.New("cannot cancel completed appointment")
-	}
-	if appointment.Status == domain.AppointmentStatusCancelled {
-		return nil, errors.New("appointment already cancelled")
-	}
-
-	now := time.Now()
-	appointment.Status = domain.AppointmentStatusCancelled
+	now := s.clock.Now()
 	appointment.CancelledReason = reason
 	appointment.CancelledAt = &now
-	appointment.CancelledBy = &cancelledBy
-	appointment.UpdatedBy = cancelledBy
+	appointment.CancelledBy = cancelledBy
 
-	if err := s.appointmentRepo.Update(appointment); err != nil {
-		return nil, fmt.Errorf("failed to cancel appointment: %w", err)
+	if err := s.transition(appointment, domain.AppointmentStatusCancelled, reason, cancelledBy); err != nil {
+		return nil, err
 	}
 
+	s.releaseSlot(*appointment)
+
 	appointment, _ = s.appointmentRepo.FindByID(appointment.ID)
 	return s.toAppointmentResponse(appointment), nil
 }
@@ -267,13 +466,38 @@ func (s *AppointmentService) updateStatus(id uint, newStatus domain.AppointmentS
 		return ErrAppointmentNotFound
 	}
 
-	appointment.Status = newStatus
-	appointment.UpdatedBy = updatedBy
+	return s.transition(appointment, newStatus, "", &updatedBy)
+}
+
+// transition moves an appointment to a new status if the transition table
+// allows it, recording the change in the status history. changedBy is nil
+// when the patient or the system makes the change.
+func (s *AppointmentService) transition(appointment *domain.Appointment, to domain.AppointmentStatus, reason string, changedBy *uint) error {
+	from := appointment.Status
+	if !from.CanTransitionTo(to) {
+		return fmt.Errorf("%w: cannot move from %s to %s", ErrInvalidStatusTransition, from, to)
+	}
+
+	appointment.Status = to
+	if changedBy != nil {
+		appointment.UpdatedBy = *changedBy
+	}
 
-	return s.appointmentRepo.Update(appointment)
+	history := &domain.AppointmentStatusHistory{
+		AppointmentID: appointment.ID,
+		FromStatus:    from,
+		ToStatus:      to,
+		Reason:        reason,
+		ChangedAt:     s.clock.Now(),
+		ChangedBy:     changedBy,
+	}
+	if err := s.appointmentRepo.UpdateStatus(appointment, history); err != nil {
+		return fmt.Errorf("failed to update appointment status: %w", err)
+	}
+	return nil
 }
 
-// GetAppointmentByID gets appointment by ID
+// GetAppointmentByID gets appointment by ID with its status history
 func (s *AppointmentService) GetAppointmentByID(id uint) (*dto.AppointmentResponse, error) {
 	appointment, err := s.appointmentRepo.FindByID(id)
 	if err != nil {
@@ -282,7 +506,30 @@ func (s *AppointmentService) GetAppointmentByID(id uint) (*dto.AppointmentRespon
 	if appointment == nil {
 		return nil, ErrAppointmentNotFound
 	}
-	return s.toAppointmentResponse(appointment), nil
+
+	history, err := s.appointmentRepo.FindStatusHistory(appointment.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find appointment status history: %w", err)
+	}
+
+	resp, err := s.toAppointmentResponseWithResources(appointment)
+	if err != nil {
+		return nil, err
+	}
+	resp.StatusHistory = make([]*dto.AppointmentStatusChange, len(history))
+	for i, h := range history {
+		resp.StatusHistory[i] = &dto.AppointmentStatusChange{
+			FromStatus: string(h.FromStatus),
+			ToStatus:   string(h.ToStatus),
+			Reason:     h.Reason,
+			ChangedAt:  h.ChangedAt,
+			ChangedBy:  h.ChangedBy,
+		}
+		if h.User != nil {
+			resp.StatusHistory[i].ChangedByName = h.User.FullName
+		}
+	}
+	return resp, nil
 }
 
 // GetAppointmentByCode gets appointment by code
@@ -330,35 +577,195 @@ func (s *AppointmentService) GetDoctorSchedule(doctorID uint, dateStr string) ([
 	return items, nil
 }
 
-// GetAvailableTimeSlots gets available time slots for a doctor on a date
-func (s *AppointmentService) GetAvailableTimeSlots(doctorID uint, dateStr string, duration int) ([]*dto.TimeSlot, error) {
+// GetAvailableTimeSlots gets available time slots for a doctor on a date. When
+// resources are given, a slot is only available if all of them are free too.
+func (s *AppointmentService) GetAvailableTimeSlots(doctorID uint, dateStr string, duration int, resourceIDs []uint) ([]*dto.TimeSlot, error) {
 	date, err := time.Parse("2006-01-02", dateStr)
 	if err != nil {
 		return nil, ErrInvalidDateFormat
 	}
 
-	if duration == 0 {
-		duration = 30
+	days, err := s.computeAvailability([]uint{doctorID}, date, date, duration, resourceIDs)
+	if err != nil {
+		return nil, err
 	}
+	return days[doctorID][0].Slots, nil
+}
 
-	slots := []*dto.TimeSlot{}
-	startHour := 8
-	endHour := 17
+// GetAvailability gets the appointment slots of doctors over a date range
+// (inclusive, at most 31 days) for the booking screen. The doctors are the
+// active users holding clinic sessions in the range, optionally limited to a
+// department or to given doctors. A zero duration uses each session's slot length.
+func (s *AppointmentService) GetAvailability(departmentID *uint, doctorIDs []uint, fromStr, toStr string, duration int, resourceIDs []uint) (*dto.AvailabilityResponse, error) {
+	from, to, err := parseDateRange(s.clock.Today(), fromStr, toStr)
+	if err != nil {
+		return nil, err
+	}
+	if to.After(from.AddDate(0, 0, maxAvailabilityDays-1)) {
+		return nil, ErrAvailabilityRangeTooLong
+	}
 
-	for hour := startHour; hour < endHour; hour++ {
-		for minute := 0; minute < 60; minute += 30 {
-			timeStr := fmt.Sprintf("%02d:%02d", hour, minute)
-			slotTime, _ := time.Parse("15:04", timeStr)
+	if departmentID != nil {
+		department, err := s.departmentRepo.FindByID(*departmentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find department: %w", err)
+		}
+		if department == nil {
+			return nil, ErrDepartmentNotFound
+		}
+	}
 
-			available, _ := s.appointmentRepo.CheckTimeSlotAvailable(doctorID, date, slotTime, duration, nil)
-			slots = append(slots, &dto.TimeSlot{
-				Time:      timeStr,
-				Available: available,
-			})
+	doctors, err := s.userRepo.FindDoctorsWithSessions(departmentID, doctorIDs, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find doctors: %w", err)
+	}
+	ids := make([]uint, len(doctors))
+	for i, doctor := range doctors {
+		ids[i] = doctor.ID
+	}
+
+	days, err := s.computeAvailability(ids, from, to, duration, resourceIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.AvailabilityResponse{
+		From:            from.Format("2006-01-02"),
+		To:              to.Format("2006-01-02"),
+		DurationMinutes: duration,
+		Doctors:         make([]*dto.DoctorAvailability, len(doctors)),
+	}
+	for i, doctor := range doctors {
+		item := &dto.DoctorAvailability{
+			DoctorID:     doctor.ID,
+			DoctorName:   doctor.FullName,
+			DepartmentID: doctor.DepartmentID,
+			Days:         days[doctor.ID],
+		}
+		if doctor.Department != nil {
+			item.DepartmentName = doctor.Department.Name
+		}
+		for _, day := range item.Days {
+			item.AvailableSlots += day.AvailableSlots
+		}
+		resp.Doctors[i] = item
+	}
+	return resp, nil
+}
+
+// computeAvailability works out the doctors' slots on each day between two
+// dates (inclusive). Schedules, bookings and resource reservations are loaded
+// up front in a fixed number of queries and the slots computed in memory; a
+// slot is available when it is still ahead, its session has capacity, fewer
+// bookings and held waitlist offers than its slot capacity overlap it and the
+// resources are free.
+func (s *AppointmentService) computeAvailability(doctorIDs []uint, from, to time.Time, duration int, resourceIDs []uint) (map[uint][]*dto.DayAvailability, error) {
+	calendar, err := loadClinicCalendar(s.scheduleRepo, doctorIDs, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	intervals, err := s.appointmentRepo.FindBookedIntervals(doctorIDs, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load bookings: %w", err)
+	}
+	booked := make(map[uint]map[string][]*repository.BookedInterval)
+	for _, b := range intervals {
+		if booked[b.DoctorID] == nil {
+			booked[b.DoctorID] = make(map[string][]*repository.BookedInterval)
 		}
+		key := clock.Day(b.AppointmentDate).Format("2006-01-02")
+		booked[b.DoctorID][key] = append(booked[b.DoctorID][key], b)
 	}
 
-	return slots, nil
+	resources, _, err := loadBookableResources(s.resourceRepo, resourceIDs)
+	if err != nil {
+		return nil, err
+	}
+	resourceCalendar, err := loadResourceCalendar(s.resourceRepo, resources, s.clock.StartOfDay(from), s.clock.StartOfDay(to.AddDate(0, 0, 1)), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now()
+	result := make(map[uint][]*dto.DayAvailability, len(doctorIDs))
+	for _, doctorID := range doctorIDs {
+		rate, rateLoaded := 0.0, false
+
+		for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+			key := date.Format("2006-01-02")
+			day := calendar.day(doctorID, date)
+			dayBooked := booked[doctorID][key]
+
+			item := &dto.DayAvailability{Date: key, ClosedReason: day.reason, Slots: []*dto.TimeSlot{}}
+			for _, cs := range day.sessions {
+				length := duration
+				if length == 0 {
+					length = cs.slotMinutes
+				}
+
+				sessionFull, capacity := false, 1
+				if cs.maxPatients > 0 {
+					if !rateLoaded {
+						if rate, err = s.noShowService.OverbookRate(doctorID); err != nil {
+							return nil, err
+						}
+						rateLoaded = true
+					}
+					allowance := overbookAllowance(cs.maxPatients, rate)
+					sessionFull = countStarting(dayBooked, cs.start, cs.end) >= cs.maxPatients+allowance
+					capacity = s.noShowService.SlotCapacity(allowance)
+				}
+
+				for start := cs.start; start+length <= cs.end; start += cs.slotMinutes {
+					if !cs.fits(start, length) {
+						continue
+					}
+
+					slotStart, slotEnd := appointmentPeriod(s.clock, date, clockTime(start), length)
+					available := !sessionFull &&
+						slotStart.After(now) &&
+						countOverlapping(dayBooked, start, start+length) < capacity &&
+						resourceCalendar.unavailable(slotStart, slotEnd) == nil
+					if available {
+						item.AvailableSlots++
+					}
+
+					item.Slots = append(item.Slots, &dto.TimeSlot{
+						Time:      formatClock(start),
+						EndTime:   formatClock(start + length),
+						Room:      cs.room,
+						Available: available,
+					})
+				}
+			}
+			result[doctorID] = append(result[doctorID], item)
+		}
+	}
+	return result, nil
+}
+
+// countStarting counts the bookings starting within [start, end) minutes
+func countStarting(intervals []*repository.BookedInterval, start, end int) int {
+	count := 0
+	for _, b := range intervals {
+		if b.StartMinute >= start && b.StartMinute < end {
+			count++
+		}
+	}
+	return count
+}
+
+// countOverlapping counts the bookings that overlap [start, end) minutes or
+// start alongside it, as CheckTimeSlotCapacity does
+func countOverlapping(intervals []*repository.BookedInterval, start, end int) int {
+	count := 0
+	for _, b := range intervals {
+		if b.StartMinute < end && (b.StartMinute+b.DurationMinutes > start || b.StartMinute >= start) {
+			count++
+		}
+	}
+	return count
 }
 
 // SearchAppointments searches appointments
@@ -389,6 +796,82 @@ func (s *AppointmentService) GetUpcomingAppointments(limit int) ([]*dto.Appointm
 	return items, nil
 }
 
+// findClinicSession finds the doctor's clinic session an appointment falls in
+// and checks that the appointment fits it, without checking capacity. A zero
+// duration is replaced by the session's slot length; the effective duration is returned.
+func (s *AppointmentService) findClinicSession(doctorID uint, date, appointmentTime time.Time, duration int) (*clinicSession, int, error) {
+	day, err := resolveClinicDay(s.scheduleRepo, doctorID, date)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(day.sessions) == 0 {
+		return nil, 0, fmt.Errorf("%w: %s", ErrDoctorNotAvailable, day.reason)
+	}
+
+	start := appointmentTime.Hour()*60 + appointmentTime.Minute()
+	for _, cs := range day.sessions {
+		if start < cs.start || start >= cs.end {
+			continue
+		}
+		if duration == 0 {
+			duration = cs.slotMinutes
+		}
+		if !cs.fits(start, duration) {
+			return nil, 0, ErrInvalidAppointmentTime
+		}
+		return cs, duration, nil
+	}
+
+	return nil, 0, ErrInvalidAppointmentTime
+}
+
+// sessionLimit returns how many bookings a clinic session with a patient limit
+// takes, the limit plus the doctor's overbooking allowance, and how many
+// bookings one of its time slots may hold
+func (s *AppointmentService) sessionLimit(doctorID uint, cs *clinicSession) (int, int, error) {
+	allowance, err := s.noShowService.OverbookAllowance(doctorID, cs.maxPatients)
+	if err != nil {
+		return 0, 0, err
+	}
+	return cs.maxPatients + allowance, s.noShowService.SlotCapacity(allowance), nil
+}
+
+// checkSessionCapacity checks that a clinic session with a patient limit still
+// has room for limit bookings, counting bookings through repo
+func checkSessionCapacity(repo *repository.AppointmentRepository, cs *clinicSession, limit int, doctorID uint, date time.Time, excludeIDs []uint) error {
+	if cs.maxPatients <= 0 {
+		return nil
+	}
+
+	booked, err := repo.CountInTimeRange(doctorID, date, clockTime(cs.start), clockTime(cs.end), excludeIDs)
+	if err != nil {
+		return fmt.Errorf("failed to count bookings: %w", err)
+	}
+	if booked >= int64(limit) {
+		return ErrClinicSessionFull
+	}
+	return nil
+}
+
+// releaseSlot notifies the slot release listeners that an appointment's slot is free
+func (s *AppointmentService) releaseSlot(released domain.Appointment) {
+	for _, listener := range s.slotListeners {
+		listener(released)
+	}
+}
+
+// toAppointmentResponseWithResources converts an appointment with the rooms and
+// equipment booked for it
+func (s *AppointmentService) toAppointmentResponseWithResources(apt *domain.Appointment) (*dto.AppointmentResponse, error) {
+	bookings, err := s.resourceRepo.FindBookingsByAppointment(apt.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find resource bookings: %w", err)
+	}
+	resp := s.toAppointmentResponse(apt)
+	resp.Resources = toBookedResources(bookings)
+	return resp, nil
+}
+
 // Helper functions
 func (s *AppointmentService) toAppointmentResponse(apt *domain.Appointment) *dto.AppointmentResponse {
 	resp := &dto.AppointmentResponse{
@@ -405,6 +888,9 @@ func (s *AppointmentService) toAppointmentResponse(apt *domain.Appointment) *dto
 		Notes:           apt.Notes,
 		CancelledReason: apt.CancelledReason,
 		CancelledAt:     apt.CancelledAt,
+		BookingSource:   string(apt.BookingSource),
+		SeriesID:        apt.SeriesID,
+		SeriesIndex:     apt.SeriesIndex,
 		CreatedAt:       apt.CreatedAt,
 		UpdatedAt:       apt.UpdatedAt,
 	}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/minhtran/his/internal/domain"
+	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/pkg/clock"
+	"github.com/minhtran/his/internal/repository"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// testDB opens the MySQL database named by TEST_DATABASE_DSN, which must have
+// the migrations applied, and skips the test when it is not set
+func testDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	return db
+}
+
+func TestScheduleAppointmentBooksSlotOnce(t *testing.T) {
+	db := testDB(t)
+	clk := clock.New(time.Local)
+
+	suffix := time.Now().UnixNano() % 1e12
+	doctor := &domain.User{
+		Username:     fmt.Sprintf("lock-test-%d", suffix),
+		Email:        fmt.Sprintf("lock-test-%d@example.com", suffix),
+		PasswordHash: "-",
+		FullName:     "Lock Test Doctor",
+		IsActive:     true,
+	}
+	if err := db.Create(doctor).Error; err != nil {
+		t.Fatalf("failed to create doctor: %v", err)
+	}
+	patient := &domain.Patient{
+		PatientCode: fmt.Sprintf("T-%d", suffix),
+		FirstName:   "Lock",
+		LastName:    "Test",
+		FullName:    "Lock Test",
+		DateOfBirth: time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+		Gender:      domain.GenderMale,
+	}
+	if err := db.Create(patient).Error; err != nil {
+		t.Fatalf("failed to create patient: %v", err)
+	}
+
+	// A date override opens the doctor's clinic whatever the weekly templates
+	// and holidays say
+	date := clk.Today().AddDate(0, 0, 2)
+	session := &domain.DoctorScheduleOverride{
+		DoctorID:     doctor.ID,
+		OverrideDate: date,
+		IsAvailable:  true,
+		StartTime:    "08:00",
+		EndTime:      "17:00",
+		SlotMinutes:  30,
+		CreatedBy:    doctor.ID,
+		UpdatedBy:    doctor.ID,
+	}
+	if err := db.Create(session).Error; err != nil {
+		t.Fatalf("failed to create clinic session: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Unscoped().Where("doctor_id = ?", doctor.ID).Delete(&domain.Appointment{})
+		db.Unscoped().Delete(session)
+		db.Unscoped().Delete(patient)
+		db.Unscoped().Delete(doctor)
+	})
+
+	appointmentRepo := repository.NewAppointmentRepository(db)
+	patientRepo := repository.NewPatientRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	noShowService := NewNoShowService(appointmentRepo, patientRepo, userRepo, NoShowPolicy{}, clk)
+	appointmentService := NewAppointmentService(
+		appointmentRepo,
+		patientRepo,
+		userRepo,
+		repository.NewDoctorScheduleRepository(db),
+		repository.NewResourceRepository(db),
+		repository.NewDepartmentRepository(db),
+		noShowService,
+		clk,
+	)
+
+	const attempts = 10
+	results := make(chan error, attempts)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+
+			_, err := appointmentService.ScheduleAppointment(&dto.CreateAppointmentRequest{
+				PatientID:       patient.ID,
+				DoctorID:        doctor.ID,
+				AppointmentDate: date.Format("2006-01-02"),
+				AppointmentTime: "09:00",
+				DurationMinutes: 30,
+				AppointmentType: string(domain.AppointmentTypeConsultation),
+			}, doctor.ID)
+			results <- err
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(results)
+
+	booked, taken := 0, 0
+	for err := range results {
+		switch {
+		case err == nil:
+			booked++
+		case errors.Is(err, ErrTimeSlotNotAvailable):
+			taken++
+		default:
+			t.Errorf("booking failed: %v", err)
+		}
+	}
+	if booked != 1 || taken != attempts-1 {
+		t.Fatalf("slot booked %d times and refused %d times, want once and %d times", booked, taken, attempts-1)
+	}
+}
@@ -0,0 +1,302 @@
+package service
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/minhtran/his/internal/domain"
+	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/pkg/clock"
+	"github.com/minhtran/his/internal/pkg/ical"
+	"github.com/minhtran/his/internal/repository"
+)
+
+var (
+	// ErrCalendarFeedNotFound is returned when a user has no active calendar feed
+	// or a feed token is unknown or revoked
+	ErrCalendarFeedNotFound = errors.New("calendar feed not found")
+)
+
+// Patient detail levels of calendar feed events
+const (
+	CalendarPatientDetailNone     = "none"     // Appointment type only
+	CalendarPatientDetailInitials = "initials" // Type and patient initials
+	CalendarPatientDetailFull     = "full"     // Type, patient name and code, and reason
+)
+
+// calendarTokenBytes is the number of random bytes in a feed token
+const calendarTokenBytes = 32
+
+// CalendarFeedSettings configures what calendar feeds contain
+type CalendarFeedSettings struct {
+	BaseURL       string // Public URL feed links are built on
+	FacilityName  string
+	PatientDetail string
+	PastDays      int
+	FutureDays    int
+}
+
+// CalendarFeedService handles per-user iCalendar feeds of appointments
+type CalendarFeedService struct {
+	feedRepo        *repository.CalendarFeedRepository
+	appointmentRepo *repository.AppointmentRepository
+	userRepo        *repository.UserRepository
+	auditRepo       *repository.AuditLogRepository
+	settings        CalendarFeedSettings
+	clock           *clock.Clock
+}
+
+// NewCalendarFeedService creates a new calendar feed service
+func NewCalendarFeedService(
+	feedRepo *repository.CalendarFeedRepository,
+	appointmentRepo *repository.AppointmentRepository,
+	userRepo *repository.UserRepository,
+	auditRepo *repository.AuditLogRepository,
+	settings CalendarFeedSettings,
+	clk *clock.Clock,
+) *CalendarFeedService {
+	return &CalendarFeedService{
+		feedRepo:        feedRepo,
+		appointmentRepo: appointmentRepo,
+		userRepo:        userRepo,
+		auditRepo:       auditRepo,
+		settings:        settings,
+		clock:           clk,
+	}
+}
+
+// GetFeed gets a user's active calendar feed. The URL is not included since
+// only a hash of its token is kept.
+func (s *CalendarFeedService) GetFeed(userID uint) (*dto.CalendarFeedResponse, error) {
+	feed, err := s.feedRepo.FindActiveByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find calendar feed: %w", err)
+	}
+	if feed == nil {
+		return nil, ErrCalendarFeedNotFound
+	}
+	return s.toResponse(feed, ""), nil
+}
+
+// RotateFeed issues a new feed URL for a user, revoking the previous one
+func (s *CalendarFeedService) RotateFeed(userID, actorID uint) (*dto.CalendarFeedResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	token, err := generateCalendarToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate calendar feed token: %w", err)
+	}
+
+	feed := &domain.CalendarFeed{
+		UserID:    userID,
+		TokenHash: hashCalendarToken(token),
+		CreatedBy: actorID,
+	}
+	if err := s.feedRepo.Rotate(feed, actorID); err != nil {
+		return nil, fmt.Errorf("failed to create calendar feed: %w", err)
+	}
+
+	// Audit log
+	s.auditRepo.Create(&domain.AuditLog{
+		UserID:     &actorID,
+		Action:     domain.AuditActionCreate,
+		Resource:   "CalendarFeed",
+		ResourceID: fmt.Sprintf("%d", feed.ID),
+		Details: domain.AuditDetails{
+			"user_id":        userID,
+			"patient_detail": s.settings.PatientDetail,
+		},
+	})
+
+	return s.toResponse(feed, s.settings.BaseURL+"/calendar/"+token+".ics"), nil
+}
+
+// RevokeFeed revokes a user's calendar feed so its URL stops working
+func (s *CalendarFeedService) RevokeFeed(userID, actorID uint) error {
+	revoked, err := s.feedRepo.RevokeActive(userID, actorID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke calendar feed: %w", err)
+	}
+	if revoked == 0 {
+		return ErrCalendarFeedNotFound
+	}
+
+	// Audit log
+	s.auditRepo.Create(&domain.AuditLog{
+		UserID:     &actorID,
+		Action:     domain.AuditActionDelete,
+		Resource:   "CalendarFeed",
+		ResourceID: fmt.Sprintf("%d", userID),
+		Details: domain.AuditDetails{
+			"user_id": userID,
+		},
+	})
+
+	return nil
+}
+
+// RenderFeed renders the iCalendar document of the feed with the given token:
+// the owner's appointments as their doctor from PastDays ago to FutureDays ahead
+func (s *CalendarFeedService) RenderFeed(token string) ([]byte, error) {
+	if token == "" {
+		return nil, ErrCalendarFeedNotFound
+	}
+	feed, err := s.feedRepo.FindActiveByTokenHash(hashCalendarToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find calendar feed: %w", err)
+	}
+	if feed == nil || feed.User == nil || !feed.User.IsActive {
+		return nil, ErrCalendarFeedNotFound
+	}
+
+	today := s.clock.Today()
+	appointments, err := s.appointmentRepo.FindByDoctorIDBetween(feed.UserID, today.AddDate(0, 0, -s.settings.PastDays), today.AddDate(0, 0, s.settings.FutureDays))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find appointments: %w", err)
+	}
+
+	cal := &ical.Calendar{
+		ProdID:   "-//" + s.settings.FacilityName + "//HIS//EN",
+		Name:     fmt.Sprintf("%s - %s", feed.User.FullName, s.settings.FacilityName),
+		Location: s.clock.Location(),
+		Events:   make([]ical.Event, 0, len(appointments)),
+	}
+	for _, apt := range appointments {
+		cal.Events = append(cal.Events, s.toEvent(apt))
+	}
+
+	var buf bytes.Buffer
+	if err := cal.Encode(&buf); err != nil {
+		return nil, fmt.Errorf("failed to encode calendar: %w", err)
+	}
+
+	if err := s.feedRepo.TouchAccess(feed.ID, s.clock.Now()); err != nil {
+		return nil, fmt.Errorf("failed to update calendar feed: %w", err)
+	}
+	if s.settings.PatientDetail != CalendarPatientDetailNone {
+		// Audit log
+		s.auditRepo.Create(&domain.AuditLog{
+			UserID:     &feed.UserID,
+			Action:     domain.AuditActionView,
+			Resource:   "CalendarFeed",
+			ResourceID: fmt.Sprintf("%d", feed.ID),
+			Details: domain.AuditDetails{
+				"appointments":   len(appointments),
+				"patient_detail": s.settings.PatientDetail,
+			},
+		})
+	}
+
+	return buf.Bytes(), nil
+}
+
+// toEvent converts an appointment to a calendar event showing only as much of
+// the patient as the patient detail setting allows
+func (s *CalendarFeedService) toEvent(apt *domain.Appointment) ical.Event {
+	start := s.clock.At(apt.AppointmentDate, apt.AppointmentTime)
+
+	summary := appointmentTypeLabel(apt.AppointmentType)
+	description := "Appointment " + apt.AppointmentCode
+	if apt.Patient != nil {
+		switch s.settings.PatientDetail {
+		case CalendarPatientDetailInitials:
+			summary += " - " + nameInitials(apt.Patient.FullName)
+		case CalendarPatientDetailFull:
+			summary += " - " + apt.Patient.FullName
+			description += fmt.Sprintf("\nPatient: %s (%s)", apt.Patient.FullName, apt.Patient.PatientCode)
+			if apt.Reason != "" {
+				description += "\nReason: " + apt.Reason
+			}
+		}
+	}
+
+	status := ical.StatusConfirmed
+	switch apt.Status {
+	case domain.AppointmentStatusScheduled:
+		status = ical.StatusTentative
+	case domain.AppointmentStatusCancelled:
+		status = ical.StatusCancelled
+	}
+
+	// Seconds since creation of the last change grows with every update
+	return ical.Event{
+		UID:          apt.AppointmentCode + "@his",
+		Sequence:     int(apt.UpdatedAt.Unix() - apt.CreatedAt.Unix()),
+		Start:        start,
+		End:          start.Add(time.Duration(apt.DurationMinutes) * time.Minute),
+		Summary:      summary,
+		Description:  description,
+		Location:     s.settings.FacilityName,
+		Status:       status,
+		LastModified: apt.UpdatedAt,
+	}
+}
+
+func (s *CalendarFeedService) toResponse(feed *domain.CalendarFeed, url string) *dto.CalendarFeedResponse {
+	return &dto.CalendarFeedResponse{
+		ID:             feed.ID,
+		UserID:         feed.UserID,
+		URL:            url,
+		PatientDetail:  s.settings.PatientDetail,
+		CreatedAt:      feed.CreatedAt,
+		LastAccessedAt: feed.LastAccessedAt,
+	}
+}
+
+// appointmentTypeLabel returns a readable name of an appointment type
+func appointmentTypeLabel(t domain.AppointmentType) string {
+	switch t {
+	case domain.AppointmentTypeConsultation:
+		return "Consultation"
+	case domain.AppointmentTypeFollowUp:
+		return "Follow-up"
+	case domain.AppointmentTypeEmergency:
+		return "Emergency"
+	case domain.AppointmentTypeCheckup:
+		return "Check-up"
+	default:
+		return "Appointment"
+	}
+}
+
+// nameInitials returns the initials of a name, e.g. "N.V.A." for "Nguyễn Văn An"
+func nameInitials(name string) string {
+	var b strings.Builder
+	for _, word := range strings.Fields(name) {
+		for _, r := range word {
+			b.WriteRune(unicode.ToUpper(r))
+			b.WriteByte('.')
+			break
+		}
+	}
+	return b.String()
+}
+
+// generateCalendarToken returns a random URL-safe feed token
+func generateCalendarToken() (string, error) {
+	b := make([]byte, calendarTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashCalendarToken returns the hex SHA-256 of a feed token, which is what is stored
+func hashCalendarToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,588 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/minhtran/his/internal/domain"
+	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/pkg/clock"
+	"github.com/minhtran/his/internal/repository"
+)
+
+var (
+	// ErrClinicalNoteNotFound is returned when a clinical note is not found
+	ErrClinicalNoteNotFound = errors.New("clinical note not found")
+	// ErrClinicalNoteTemplateNotFound is returned when a clinical note template is not found
+	ErrClinicalNoteTemplateNotFound = errors.New("clinical note template not found")
+	// ErrClinicalNoteTemplateExists is returned when a template code is already in use
+	ErrClinicalNoteTemplateExists = errors.New("clinical note template code already exists")
+	// ErrClinicalNoteTemplateMismatch is returned when a template is inactive or for another note type
+	ErrClinicalNoteTemplateMismatch = errors.New("template is inactive or for another note type")
+	// ErrClinicalNoteLocked is returned when editing, signing or deleting a signed note
+	ErrClinicalNoteLocked = errors.New("signed notes cannot be changed, add an addendum instead")
+	// ErrClinicalNoteNotSigned is returned when adding an addendum to a draft
+	ErrClinicalNoteNotSigned = errors.New("addenda can only be added to signed notes")
+	// ErrClinicalNoteVersionConflict is returned when a draft was saved since the edit began
+	ErrClinicalNoteVersionConflict = errors.New("the note was changed by another save, reload it and edit the latest version")
+	// ErrNotNoteAuthor is returned when someone other than the author edits, signs or deletes a draft
+	ErrNotNoteAuthor = errors.New("only the author can change a draft note")
+	// ErrCosignerRequired is returned when a resident signs without naming a supervising doctor
+	ErrCosignerRequired = errors.New("notes written by residents need a co-signer")
+	// ErrInvalidCosigner is returned when the named co-signer cannot co-sign the note
+	ErrInvalidCosigner = errors.New("co-signer must be an active doctor other than the author and not a resident")
+	// ErrNotCosigner is returned when someone other than the named co-signer co-signs a note
+	ErrNotCosigner = errors.New("only the named co-signer can co-sign the note")
+	// ErrClinicalNoteNotPendingCosign is returned when co-signing a note that is not awaiting it
+	ErrClinicalNoteNotPendingCosign = errors.New("note is not awaiting co-signature")
+)
+
+// ClinicalNoteService handles structured clinical notes: SOAP notes written
+// from department templates, saved as versions while in draft, locked by
+// signing and amended only by addenda. Notes written by residents are
+// signed by a supervising doctor as well.
+type ClinicalNoteService struct {
+	noteRepo  *repository.ClinicalNoteRepository
+	visitRepo *repository.VisitRepository
+	userRepo  *repository.UserRepository
+	auditRepo *repository.AuditLogRepository
+	clock     *clock.Clock
+}
+
+// NewClinicalNoteService creates a new clinical note service
+func NewClinicalNoteService(
+	noteRepo *repository.ClinicalNoteRepository,
+	visitRepo *repository.VisitRepository,
+	userRepo *repository.UserRepository,
+	auditRepo *repository.AuditLogRepository,
+	clk *clock.Clock,
+) *ClinicalNoteService {
+	return &ClinicalNoteService{
+		noteRepo:  noteRepo,
+		visitRepo: visitRepo,
+		userRepo:  userRepo,
+		auditRepo: auditRepo,
+		clock:     clk,
+	}
+}
+
+// CreateNote starts a draft note for a visit, prefilling the sections left
+// empty from the template
+func (s *ClinicalNoteService) CreateNote(visitID uint, req *dto.CreateClinicalNoteRequest, userID uint) (*dto.ClinicalNoteResponse, error) {
+	visit, err := s.visitRepo.FindByID(visitID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find visit: %w", err)
+	}
+	if visit == nil {
+		return nil, ErrVisitNotFound
+	}
+
+	author, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find author: %w", err)
+	}
+
+	note := &domain.ClinicalNote{
+		VisitID:   visit.ID,
+		PatientID: visit.PatientID,
+		AuthorID:  userID,
+		NoteType:  domain.ClinicalNoteType(req.NoteType),
+		Status:    domain.ClinicalNoteStatusDraft,
+		Version:   1,
+		SOAPSections: domain.SOAPSections{
+			Subjective: req.Subjective,
+			Objective:  req.Objective,
+			Assessment: req.Assessment,
+			Plan:       req.Plan,
+		},
+	}
+	if author != nil {
+		note.DepartmentID = author.DepartmentID
+	}
+
+	if req.TemplateID != nil {
+		template, err := s.findTemplate(*req.TemplateID)
+		if err != nil {
+			return nil, err
+		}
+		if !template.IsActive || template.NoteType != note.NoteType {
+			return nil, ErrClinicalNoteTemplateMismatch
+		}
+		note.TemplateID = &template.ID
+		if template.DepartmentID != nil {
+			note.DepartmentID = template.DepartmentID
+		}
+		if note.Subjective == "" {
+			note.Subjective = template.Subjective
+		}
+		if note.Objective == "" {
+			note.Objective = template.Objective
+		}
+		if note.Assessment == "" {
+			note.Assessment = template.Assessment
+		}
+		if note.Plan == "" {
+			note.Plan = template.Plan
+		}
+	}
+
+	if err := s.noteRepo.Create(note); err != nil {
+		return nil, fmt.Errorf("failed to create clinical note: %w", err)
+	}
+
+	s.auditNote(note, domain.AuditActionCreate, userID, nil)
+	return s.GetNote(note.ID)
+}
+
+// GetNote gets a clinical note with its addenda
+func (s *ClinicalNoteService) GetNote(id uint) (*dto.ClinicalNoteResponse, error) {
+	note, err := s.findNote(id)
+	if err != nil {
+		return nil, err
+	}
+	return toClinicalNoteResponse(note), nil
+}
+
+// GetVisitNotes gets a visit's clinical notes, oldest first
+func (s *ClinicalNoteService) GetVisitNotes(visitID uint) ([]*dto.ClinicalNoteResponse, error) {
+	visit, err := s.visitRepo.FindByID(visitID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find visit: %w", err)
+	}
+	if visit == nil {
+		return nil, ErrVisitNotFound
+	}
+
+	notes, err := s.noteRepo.FindByVisit(visit.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clinical notes: %w", err)
+	}
+
+	items := make([]*dto.ClinicalNoteResponse, len(notes))
+	for i, n := range notes {
+		items[i] = toClinicalNoteResponse(n)
+	}
+	return items, nil
+}
+
+// UpdateNote saves the author's edit of a draft as its next version. The
+// edit must be made on the latest version, so concurrent saves cannot
+// silently overwrite each other.
+func (s *ClinicalNoteService) UpdateNote(id uint, req *dto.UpdateClinicalNoteRequest, userID uint) (*dto.ClinicalNoteResponse, error) {
+	note, err := s.findDraft(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Version != note.Version {
+		return nil, ErrClinicalNoteVersionConflict
+	}
+
+	if req.Subjective != nil {
+		note.Subjective = *req.Subjective
+	}
+	if req.Objective != nil {
+		note.Objective = *req.Objective
+	}
+	if req.Assessment != nil {
+		note.Assessment = *req.Assessment
+	}
+	if req.Plan != nil {
+		note.Plan = *req.Plan
+	}
+	note.Version++
+
+	saved, err := s.noteRepo.SaveVersion(note, req.Version, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update clinical note: %w", err)
+	}
+	if !saved {
+		return nil, ErrClinicalNoteVersionConflict
+	}
+
+	s.auditNote(note, domain.AuditActionUpdate, userID, nil)
+	return s.GetNote(note.ID)
+}
+
+// DeleteNote discards a draft. Signed notes are part of the record and are
+// never deleted.
+func (s *ClinicalNoteService) DeleteNote(id uint, userID uint) error {
+	note, err := s.findDraft(id, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.noteRepo.Delete(note.ID); err != nil {
+		return fmt.Errorf("failed to delete clinical note: %w", err)
+	}
+
+	s.auditNote(note, domain.AuditActionDelete, userID, nil)
+	return nil
+}
+
+// SignNote signs a draft, locking its sections. The signature applies to the
+// version the author reviewed, so a save made since then is never signed
+// unseen. A resident names the doctor who co-signs it; the note awaits
+// co-signature until then.
+func (s *ClinicalNoteService) SignNote(id uint, req *dto.SignClinicalNoteRequest, userID uint) (*dto.ClinicalNoteResponse, error) {
+	note, err := s.findDraft(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Version != note.Version {
+		return nil, ErrClinicalNoteVersionConflict
+	}
+
+	author, err := s.userRepo.GetUserWithRoles(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find author: %w", err)
+	}
+
+	now := s.clock.Now()
+	note.SignedAt = &now
+	note.Status = domain.ClinicalNoteStatusSigned
+	note.CosignerID = nil
+
+	if author != nil && author.HasRole(domain.RoleCodeResident) {
+		if req.CosignerID == nil {
+			return nil, ErrCosignerRequired
+		}
+		cosigner, err := s.userRepo.GetUserWithRoles(*req.CosignerID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find co-signer: %w", err)
+		}
+		if cosigner == nil || !cosigner.IsActive || cosigner.ID == userID || cosigner.HasRole(domain.RoleCodeResident) {
+			return nil, ErrInvalidCosigner
+		}
+		note.CosignerID = &cosigner.ID
+		note.Status = domain.ClinicalNoteStatusPendingCosign
+	}
+
+	signed, err := s.noteRepo.Sign(note)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign clinical note: %w", err)
+	}
+	if !signed {
+		return nil, ErrClinicalNoteVersionConflict
+	}
+
+	s.auditNote(note, domain.AuditActionUpdate, userID, domain.AuditDetails{
+		"action":      "SIGN",
+		"cosigner_id": note.CosignerID,
+	})
+	return s.GetNote(note.ID)
+}
+
+// CosignNote co-signs a resident's signed note
+func (s *ClinicalNoteService) CosignNote(id uint, userID uint) (*dto.ClinicalNoteResponse, error) {
+	note, err := s.findNote(id)
+	if err != nil {
+		return nil, err
+	}
+	if note.Status != domain.ClinicalNoteStatusPendingCosign {
+		return nil, ErrClinicalNoteNotPendingCosign
+	}
+	if note.CosignerID == nil || *note.CosignerID != userID {
+		return nil, ErrNotCosigner
+	}
+
+	now := s.clock.Now()
+	note.CosignedAt = &now
+	note.Status = domain.ClinicalNoteStatusSigned
+
+	cosigned, err := s.noteRepo.Cosign(note)
+	if err != nil {
+		return nil, fmt.Errorf("failed to co-sign clinical note: %w", err)
+	}
+	if !cosigned {
+		return nil, ErrClinicalNoteNotPendingCosign
+	}
+
+	s.auditNote(note, domain.AuditActionUpdate, userID, domain.AuditDetails{"action": "COSIGN"})
+	return s.GetNote(note.ID)
+}
+
+// AddAddendum appends an addendum to a signed note
+func (s *ClinicalNoteService) AddAddendum(id uint, req *dto.CreateClinicalNoteAddendumRequest, userID uint) (*dto.ClinicalNoteResponse, error) {
+	note, err := s.findNote(id)
+	if err != nil {
+		return nil, err
+	}
+	if !note.Status.IsLocked() {
+		return nil, ErrClinicalNoteNotSigned
+	}
+
+	addendum := &domain.ClinicalNoteAddendum{
+		NoteID:   note.ID,
+		AuthorID: userID,
+		Content:  strings.TrimSpace(req.Content),
+	}
+	if err := s.noteRepo.CreateAddendum(addendum); err != nil {
+		return nil, fmt.Errorf("failed to add addendum: %w", err)
+	}
+
+	s.auditNote(note, domain.AuditActionUpdate, userID, domain.AuditDetails{
+		"action":      "ADDENDUM",
+		"addendum_id": addendum.ID,
+	})
+	return s.GetNote(note.ID)
+}
+
+// GetNoteVersions gets the saved versions of a note, latest first
+func (s *ClinicalNoteService) GetNoteVersions(id uint) ([]*dto.ClinicalNoteVersionResponse, error) {
+	note, err := s.findNote(id)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := s.noteRepo.FindVersions(note.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get note versions: %w", err)
+	}
+
+	items := make([]*dto.ClinicalNoteVersionResponse, len(versions))
+	for i, v := range versions {
+		items[i] = &dto.ClinicalNoteVersionResponse{
+			Version:    v.Version,
+			Subjective: v.Subjective,
+			Objective:  v.Objective,
+			Assessment: v.Assessment,
+			Plan:       v.Plan,
+			EditedBy:   v.EditedBy,
+			CreatedAt:  v.CreatedAt,
+		}
+		if v.Editor != nil {
+			items[i].EditorName = v.Editor.FullName
+		}
+	}
+	return items, nil
+}
+
+// ListTemplates lists clinical note templates with filters
+func (s *ClinicalNoteService) ListTemplates(filters map[string]interface{}) ([]*dto.ClinicalNoteTemplateResponse, error) {
+	templates, err := s.noteRepo.ListTemplates(filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list clinical note templates: %w", err)
+	}
+
+	items := make([]*dto.ClinicalNoteTemplateResponse, len(templates))
+	for i, t := range templates {
+		items[i] = toClinicalNoteTemplateResponse(t)
+	}
+	return items, nil
+}
+
+// CreateTemplate creates a clinical note template
+func (s *ClinicalNoteService) CreateTemplate(req *dto.CreateClinicalNoteTemplateRequest, userID uint) (*dto.ClinicalNoteTemplateResponse, error) {
+	existing, err := s.noteRepo.FindTemplateByCode(req.Code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check template code: %w", err)
+	}
+	if existing != nil {
+		return nil, ErrClinicalNoteTemplateExists
+	}
+
+	template := &domain.ClinicalNoteTemplate{
+		Code:         req.Code,
+		Name:         req.Name,
+		DepartmentID: req.DepartmentID,
+		NoteType:     domain.ClinicalNoteType(req.NoteType),
+		SOAPSections: domain.SOAPSections{
+			Subjective: req.Subjective,
+			Objective:  req.Objective,
+			Assessment: req.Assessment,
+			Plan:       req.Plan,
+		},
+		IsActive:  true,
+		CreatedBy: userID,
+		UpdatedBy: userID,
+	}
+	if err := s.noteRepo.CreateTemplate(template); err != nil {
+		return nil, fmt.Errorf("failed to create clinical note template: %w", err)
+	}
+
+	s.auditTemplate(template, domain.AuditActionCreate, userID)
+	return toClinicalNoteTemplateResponse(template), nil
+}
+
+// UpdateTemplate updates a clinical note template. Notes already written
+// from it are unchanged.
+func (s *ClinicalNoteService) UpdateTemplate(id uint, req *dto.UpdateClinicalNoteTemplateRequest, userID uint) (*dto.ClinicalNoteTemplateResponse, error) {
+	template, err := s.findTemplate(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != "" {
+		template.Name = req.Name
+	}
+	if req.Subjective != nil {
+		template.Subjective = *req.Subjective
+	}
+	if req.Objective != nil {
+		template.Objective = *req.Objective
+	}
+	if req.Assessment != nil {
+		template.Assessment = *req.Assessment
+	}
+	if req.Plan != nil {
+		template.Plan = *req.Plan
+	}
+	if req.IsActive != nil {
+		template.IsActive = *req.IsActive
+	}
+	template.UpdatedBy = userID
+
+	if err := s.noteRepo.UpdateTemplate(template); err != nil {
+		return nil, fmt.Errorf("failed to update clinical note template: %w", err)
+	}
+
+	s.auditTemplate(template, domain.AuditActionUpdate, userID)
+	return toClinicalNoteTemplateResponse(template), nil
+}
+
+// DeleteTemplate deletes a clinical note template
+func (s *ClinicalNoteService) DeleteTemplate(id uint, userID uint) error {
+	template, err := s.findTemplate(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.noteRepo.DeleteTemplate(template.ID); err != nil {
+		return fmt.Errorf("failed to delete clinical note template: %w", err)
+	}
+
+	s.auditTemplate(template, domain.AuditActionDelete, userID)
+	return nil
+}
+
+func (s *ClinicalNoteService) findNote(id uint) (*domain.ClinicalNote, error) {
+	note, err := s.noteRepo.FindByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find clinical note: %w", err)
+	}
+	if note == nil {
+		return nil, ErrClinicalNoteNotFound
+	}
+	return note, nil
+}
+
+// findDraft finds a note its author can still change
+func (s *ClinicalNoteService) findDraft(id uint, userID uint) (*domain.ClinicalNote, error) {
+	note, err := s.findNote(id)
+	if err != nil {
+		return nil, err
+	}
+	if note.Status.IsLocked() {
+		return nil, ErrClinicalNoteLocked
+	}
+	if note.AuthorID != userID {
+		return nil, ErrNotNoteAuthor
+	}
+	return note, nil
+}
+
+func (s *ClinicalNoteService) findTemplate(id uint) (*domain.ClinicalNoteTemplate, error) {
+	template, err := s.noteRepo.FindTemplateByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find clinical note template: %w", err)
+	}
+	if template == nil {
+		return nil, ErrClinicalNoteTemplateNotFound
+	}
+	return template, nil
+}
+
+func (s *ClinicalNoteService) auditNote(note *domain.ClinicalNote, action domain.AuditAction, userID uint, details domain.AuditDetails) {
+	if details == nil {
+		details = domain.AuditDetails{}
+	}
+	details["visit_id"] = note.VisitID
+	details["status"] = note.Status
+	details["version"] = note.Version
+
+	s.auditRepo.Create(&domain.AuditLog{
+		UserID:     &userID,
+		Action:     action,
+		Resource:   "ClinicalNote",
+		ResourceID: fmt.Sprintf("%d", note.ID),
+		Details:    details,
+	})
+}
+
+func (s *ClinicalNoteService) auditTemplate(template *domain.ClinicalNoteTemplate, action domain.AuditAction, userID uint) {
+	s.auditRepo.Create(&domain.AuditLog{
+		UserID:     &userID,
+		Action:     action,
+		Resource:   "ClinicalNoteTemplate",
+		ResourceID: fmt.Sprintf("%d", template.ID),
+		Details: domain.AuditDetails{
+			"code":      template.Code,
+			"note_type": template.NoteType,
+			"is_active": template.IsActive,
+		},
+	})
+}
+
+func toClinicalNoteResponse(n *domain.ClinicalNote) *dto.ClinicalNoteResponse {
+	resp := &dto.ClinicalNoteResponse{
+		ID:           n.ID,
+		VisitID:      n.VisitID,
+		PatientID:    n.PatientID,
+		AuthorID:     n.AuthorID,
+		DepartmentID: n.DepartmentID,
+		TemplateID:   n.TemplateID,
+		NoteType:     string(n.NoteType),
+		Status:       string(n.Status),
+		Version:      n.Version,
+		Subjective:   n.Subjective,
+		Objective:    n.Objective,
+		Assessment:   n.Assessment,
+		Plan:         n.Plan,
+		SignedAt:     n.SignedAt,
+		CosignerID:   n.CosignerID,
+		CosignedAt:   n.CosignedAt,
+		CreatedAt:    n.CreatedAt,
+		UpdatedAt:    n.UpdatedAt,
+		Addenda:      make([]*dto.ClinicalNoteAddendumResponse, len(n.Addenda)),
+	}
+	if n.Author != nil {
+		resp.AuthorName = n.Author.FullName
+	}
+	if n.Cosigner != nil {
+		resp.CosignerName = n.Cosigner.FullName
+	}
+	for i, a := range n.Addenda {
+		resp.Addenda[i] = &dto.ClinicalNoteAddendumResponse{
+			ID:        a.ID,
+			AuthorID:  a.AuthorID,
+			Content:   a.Content,
+			CreatedAt: a.CreatedAt,
+		}
+		if a.Author != nil {
+			resp.Addenda[i].AuthorName = a.Author.FullName
+		}
+	}
+	return resp
+}
+
+func toClinicalNoteTemplateResponse(t *domain.ClinicalNoteTemplate) *dto.ClinicalNoteTemplateResponse {
+	resp := &dto.ClinicalNoteTemplateResponse{
+		ID:           t.ID,
+		Code:         t.Code,
+		Name:         t.Name,
+		DepartmentID: t.DepartmentID,
+		NoteType:     string(t.NoteType),
+		Subjective:   t.Subjective,
+		Objective:    t.Objective,
+		Assessment:   t.Assessment,
+		Plan:         t.Plan,
+		IsActive:     t.IsActive,
+		CreatedAt:    t.CreatedAt,
+		UpdatedAt:    t.UpdatedAt,
+	}
+	if t.Department != nil {
+		resp.DepartmentName = t.Department.Name
+	}
+	return resp
+}
@@ -0,0 +1,466 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/minhtran/his/internal/domain"
+	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/pkg/clock"
+	"github.com/minhtran/his/internal/pkg/document"
+	"github.com/minhtran/his/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrDeathRecordNotFound    = errors.New("death record not found")
+	ErrPatientAlreadyDeceased = errors.New("patient is already registered as deceased")
+	ErrInvalidDeathDateTime   = errors.New("death date/time must not be in the future or before date of birth")
+	ErrInvalidCauseChain      = errors.New("cause-of-death chain must start at line A, have no gaps and at most one code per line")
+)
+
+// deathCauseLineOrder is the order of Part I lines, immediate cause first
+var deathCauseLineOrder = []domain.DeathCauseLine{
+	domain.DeathCauseLineA,
+	domain.DeathCauseLineB,
+	domain.DeathCauseLineC,
+	domain.DeathCauseLineD,
+}
+
+// DeathRecordService handles death registration business logic
+type DeathRecordService struct {
+	deathRecordRepo    *repository.DeathRecordRepository
+	patientRepo        *repository.PatientRepository
+	userRepo           *repository.UserRepository
+	icd10Repo          *repository.ICD10CodeRepository
+	admissionRepo      *repository.AdmissionRepository
+	appointmentService *AppointmentService
+	db                 *gorm.DB
+	facilityName       string
+	clock              *clock.Clock
+}
+
+// NewDeathRecordService creates a new death record service
+func NewDeathRecordService(
+	deathRecordRepo *repository.DeathRecordRepository,
+	patientRepo *repository.PatientRepository,
+	userRepo *repository.UserRepository,
+	icd10Repo *repository.ICD10CodeRepository,
+	admissionRepo *repository.AdmissionRepository,
+	appointmentService *AppointmentService,
+	db *gorm.DB,
+	facilityName string,
+	clk *clock.Clock,
+) *DeathRecordService {
+	return &DeathRecordService{
+		deathRecordRepo:    deathRecordRepo,
+		patientRepo:        patientRepo,
+		userRepo:           userRepo,
+		icd10Repo:          icd10Repo,
+		admissionRepo:      admissionRepo,
+		appointmentService: appointmentService,
+		db:                 db,
+		facilityName:       facilityName,
+		clock:              clk,
+	}
+}
+
+// RegisterDeath records a patient's death, closes open appointments and admissions
+// and marks the patient as deceased so no new orders can be placed
+func (s *DeathRecordService) RegisterDeath(patientID uint, req *dto.RegisterDeathRequest, createdBy uint) (*dto.DeathRecordResponse, error) {
+	// Validate patient exists and is not already deceased
+	patient, err := s.patientRepo.FindByID(patientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find patient: %w", err)
+	}
+	if patient == nil {
+		return nil, ErrPatientNotFound
+	}
+	if patient.IsDeceased {
+		return nil, ErrPatientAlreadyDeceased
+	}
+
+	// Validate certifying doctor exists
+	doctor, err := s.userRepo.FindByID(req.CertifyingDoctorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find doctor: %w", err)
+	}
+	if doctor == nil {
+		return nil, errors.New("doctor not found")
+	}
+
+	// Parse date and time of death
+	deathDateTime, err := time.ParseInLocation("2006-01-02 15:04", req.DeathDate+" "+req.DeathTime, s.clock.Location())
+	if err != nil {
+		return nil, ErrInvalidDateFormat
+	}
+	if deathDateTime.After(s.clock.Now()) || deathDateTime.Before(s.clock.StartOfDay(patient.DateOfBirth)) {
+		return nil, ErrInvalidDeathDateTime
+	}
+
+	// Validate admission if provided
+	if req.AdmissionID != nil {
+		admission, err := s.admissionRepo.FindByID(*req.AdmissionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find admission: %w", err)
+		}
+		if admission == nil {
+			return nil, ErrAdmissionNotFound
+		}
+		if admission.PatientID != patientID {
+			return nil, errors.New("admission does not belong to this patient")
+		}
+	}
+
+	// Resolve cause-of-death chain
+	causes, underlyingCauseID, err := s.buildCauseChain(req.Causes)
+	if err != nil {
+		return nil, err
+	}
+
+	certificateNumber, err := s.deathRecordRepo.GenerateCertificateNumber()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate certificate number: %w", err)
+	}
+
+	mannerOfDeath := domain.MannerOfDeath(req.MannerOfDeath)
+	if mannerOfDeath == "" {
+		mannerOfDeath = domain.MannerOfDeathNatural
+	}
+
+	record := &domain.DeathRecord{
+		CertificateNumber:  certificateNumber,
+		PatientID:          patientID,
+		AdmissionID:        req.AdmissionID,
+		CertifyingDoctorID: req.CertifyingDoctorID,
+		UnderlyingCauseID:  underlyingCauseID,
+		DeathDateTime:      deathDateTime,
+		PlaceOfDeath:       domain.PlaceOfDeath(req.PlaceOfDeath),
+		PlaceDetails:       req.PlaceDetails,
+		MannerOfDeath:      mannerOfDeath,
+		Notes:              req.Notes,
+		Causes:             causes,
+		CreatedBy:          createdBy,
+	}
+
+	var cancelled []domain.Appointment
+	var closedAdmissions int
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(record).Error; err != nil {
+			return err
+		}
+
+		// Mark patient as deceased
+		if err := tx.Model(&domain.Patient{}).Where("id = ?", patientID).Updates(map[string]interface{}{
+			"is_deceased": true,
+			"deceased_at": deathDateTime,
+			"is_active":   false,
+			"updated_by":  createdBy,
+		}).Error; err != nil {
+			return err
+		}
+
+		// Cancel all open appointments, recording each status change
+		now := s.clock.Now()
+		var open []domain.Appointment
+		if err := tx.Where("patient_id = ? AND status IN ?", patientID, []domain.AppointmentStatus{
+			domain.AppointmentStatusScheduled,
+			domain.AppointmentStatusConfirmed,
+		}).Find(&open).Error; err != nil {
+			return err
+		}
+		if len(open) > 0 {
+			ids := make([]uint, len(open))
+			history := make([]*domain.AppointmentStatusHistory, len(open))
+			for i, apt := range open {
+				ids[i] = apt.ID
+				history[i] = &domain.AppointmentStatusHistory{
+					AppointmentID: apt.ID,
+					FromStatus:    apt.Status,
+					ToStatus:      domain.AppointmentStatusCancelled,
+					Reason:        "Patient deceased",
+					ChangedAt:     now,
+					ChangedBy:     &createdBy,
+				}
+			}
+			if err := tx.Model(&domain.Appointment{}).
+				Where("id IN ?", ids).
+				Updates(map[string]interface{}{
+					"status":           domain.AppointmentStatusCancelled,
+					"cancelled_reason": "Patient deceased",
+					"cancelled_at":     now,
+					"cancelled_by":     createdBy,
+					"updated_by":       createdBy,
+				}).Error; err != nil {
+				return err
+			}
+			if err := tx.Create(&history).Error; err != nil {
+				return err
+			}
+		}
+		cancelled = open
+
+		// Close active admissions and release their beds
+		var admissions []*domain.Admission
+		if err := tx.Where("patient_id = ? AND status = ?", patientID, domain.AdmissionStatusAdmitted).
+			Find(&admissions).Error; err != nil {
+			return err
+		}
+		for _, admission := range admissions {
+			if err := tx.Model(admission).Updates(map[string]interface{}{
+				"status":            domain.AdmissionStatusDeceased,
+				"discharge_date":    deathDateTime,
+				"discharge_summary": "Patient deceased - death certificate " + certificateNumber,
+				"updated_by":        createdBy,
+			}).Error; err != nil {
+				return err
+			}
+
+			var allocations []*domain.BedAllocation
+			if err := tx.Where("admission_id = ? AND is_current = ?", admission.ID, true).
+				Find(&allocations).Error; err != nil {
+				return err
+			}
+			for _, allocation := range allocations {
+				if err := tx.Model(allocation).Updates(map[string]interface{}{
+					"released_date": now,
+					"is_current":    false,
+				}).Error; err != nil {
+					return err
+				}
+				if err := tx.Model(&domain.Bed{}).Where("id = ?", allocation.BedID).
+					Update("status", domain.BedStatusAvailable).Error; err != nil {
+					return err
+				}
+			}
+		}
+		closedAdmissions = len(admissions)
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to register death: %w", err)
+	}
+
+	// The cancelled appointments' slots can be offered to waitlisted patients
+	for _, apt := range cancelled {
+		s.appointmentService.releaseSlot(apt)
+	}
+
+	record, err = s.deathRecordRepo.FindByID(record.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reload death record: %w", err)
+	}
+
+	resp := s.toDeathRecordResponse(record)
+	resp.CancelledAppointments = len(cancelled)
+	resp.ClosedAdmissions = closedAdmissions
+	return resp, nil
+}
+
+// GetDeathRecord gets the death record of a patient
+func (s *DeathRecordService) GetDeathRecord(patientID uint) (*dto.DeathRecordResponse, error) {
+	record, err := s.deathRecordRepo.FindByPatientID(patientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find death record: %w", err)
+	}
+	if record == nil {
+		return nil, ErrDeathRecordNotFound
+	}
+	return s.toDeathRecordResponse(record), nil
+}
+
+// deathCertificateCause is a cause-of-death line rendered on the certificate
+type deathCertificateCause struct {
+	Label         string
+	Code          string
+	Description   string
+	OnsetInterval string
+}
+
+// deathCertificateData is the data rendered by the death_certificate template
+type deathCertificateData struct {
+	FacilityName      string
+	CertificateNumber string
+	PatientName       string
+	PatientCode       string
+	Gender            string
+	DateOfBirth       time.Time
+	NationalID        string
+	Address           string
+	DeathDateTime     time.Time
+	PlaceOfDeath      string
+	PlaceDetails      string
+	MannerOfDeath     string
+	Causes            []deathCertificateCause
+	UnderlyingCode    string
+	UnderlyingName    string
+	CertifyingDoctor  string
+	IssuedDay         string
+	IssuedMonth       string
+	IssuedYear        string
+}
+
+// GenerateDeathCertificate renders the printable death certificate (HTML) of a patient
+func (s *DeathRecordService) GenerateDeathCertificate(patientID uint) ([]byte, error) {
+	record, err := s.deathRecordRepo.FindByPatientID(patientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find death record: %w", err)
+	}
+	if record == nil {
+		return nil, ErrDeathRecordNotFound
+	}
+
+	issued := record.CreatedAt
+	data := deathCertificateData{
+		FacilityName:      s.facilityName,
+		CertificateNumber: record.CertificateNumber,
+		DeathDateTime:     record.DeathDateTime,
+		PlaceOfDeath:      string(record.PlaceOfDeath),
+		PlaceDetails:      record.PlaceDetails,
+		MannerOfDeath:     string(record.MannerOfDeath),
+		IssuedDay:         issued.Format("02"),
+		IssuedMonth:       issued.Format("01"),
+		IssuedYear:        issued.Format("2006"),
+	}
+
+	if p := record.Patient; p != nil {
+		data.PatientName = p.FullName
+		data.PatientCode = p.PatientCode
+		data.Gender = string(p.Gender)
+		data.DateOfBirth = p.DateOfBirth
+		data.NationalID = p.NationalID
+		data.Address = p.Address
+		if p.City != "" {
+			data.Address += ", " + p.City
+		}
+	}
+	if record.CertifyingDoctor != nil {
+		data.CertifyingDoctor = record.CertifyingDoctor.FullName
+	}
+	if record.UnderlyingCause != nil {
+		data.UnderlyingCode = record.UnderlyingCause.Code
+		data.UnderlyingName = record.UnderlyingCause.Description
+	}
+
+	for _, cause := range record.Causes {
+		item := deathCertificateCause{
+			Label:         string(cause.Line),
+			Description:   cause.Description,
+			OnsetInterval: cause.OnsetInterval,
+		}
+		if cause.Line == domain.DeathCauseLineContributing {
+			item.Label = "II"
+		}
+		if cause.ICD10Code != nil {
+			item.Code = cause.ICD10Code.Code
+			if item.Description == "" {
+				item.Description = cause.ICD10Code.Description
+			}
+		}
+		data.Causes = append(data.Causes, item)
+	}
+
+	return document.Render("death_certificate", data)
+}
+
+// buildCauseChain validates the requested cause lines, resolves their ICD-10 codes
+// and returns the underlying cause (the lowest line of Part I)
+func (s *DeathRecordService) buildCauseChain(reqs []dto.DeathCauseRequest) ([]*domain.DeathCause, uint, error) {
+	byLine := make(map[domain.DeathCauseLine]bool)
+	causes := make([]*domain.DeathCause, 0, len(reqs))
+	codeIDs := make(map[domain.DeathCauseLine]uint)
+
+	for _, r := range reqs {
+		line := domain.DeathCauseLine(r.Line)
+		if line != domain.DeathCauseLineContributing {
+			if byLine[line] {
+				return nil, 0, ErrInvalidCauseChain
+			}
+			byLine[line] = true
+		}
+
+		code, err := s.icd10Repo.FindByCode(r.ICD10Code)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to find ICD-10 code: %w", err)
+		}
+		if code == nil {
+			return nil, 0, fmt.Errorf("%w: %s", ErrICD10CodeNotFound, r.ICD10Code)
+		}
+
+		if line != domain.DeathCauseLineContributing {
+			codeIDs[line] = code.ID
+		}
+		causes = append(causes, &domain.DeathCause{
+			ICD10CodeID:   code.ID,
+			Line:          line,
+			Description:   r.Description,
+			OnsetInterval: r.OnsetInterval,
+		})
+	}
+
+	// Part I must start at line A and be contiguous; the last line is the underlying cause
+	var underlyingCauseID uint
+	gap := false
+	for _, line := range deathCauseLineOrder {
+		if !byLine[line] {
+			gap = true
+			continue
+		}
+		if gap {
+			return nil, 0, ErrInvalidCauseChain
+		}
+		underlyingCauseID = codeIDs[line]
+	}
+	if underlyingCauseID == 0 {
+		return nil, 0, ErrInvalidCauseChain
+	}
+
+	return causes, underlyingCauseID, nil
+}
+
+// Helper functions
+func (s *DeathRecordService) toDeathRecordResponse(r *domain.DeathRecord) *dto.DeathRecordResponse {
+	resp := &dto.DeathRecordResponse{
+		ID:                 r.ID,
+		CertificateNumber:  r.CertificateNumber,
+		PatientID:          r.PatientID,
+		AdmissionID:        r.AdmissionID,
+		DeathDateTime:      r.DeathDateTime,
+		PlaceOfDeath:       string(r.PlaceOfDeath),
+		PlaceDetails:       r.PlaceDetails,
+		MannerOfDeath:      string(r.MannerOfDeath),
+		CertifyingDoctorID: r.CertifyingDoctorID,
+		Notes:              r.Notes,
+		CreatedAt:          r.CreatedAt,
+	}
+
+	if r.Patient != nil {
+		resp.PatientName = r.Patient.FullName
+		resp.PatientCode = r.Patient.PatientCode
+	}
+	if r.CertifyingDoctor != nil {
+		resp.CertifyingDoctorName = r.CertifyingDoctor.FullName
+	}
+	if r.UnderlyingCause != nil {
+		resp.UnderlyingCause = r.UnderlyingCause.Code
+		resp.UnderlyingCauseName = r.UnderlyingCause.Description
+	}
+
+	resp.Causes = make([]*dto.DeathCauseResponse, len(r.Causes))
+	for i, c := range r.Causes {
+		item := &dto.DeathCauseResponse{
+			Line:          string(c.Line),
+			Description:   c.Description,
+			OnsetInterval: c.OnsetInterval,
+		}
+		if c.ICD10Code != nil {
+			item.ICD10Code = c.ICD10Code.Code
+			item.ICD10Name = c.ICD10Code.Description
+		}
+		resp.Causes[i] = item
+	}
+
+	return resp
+}
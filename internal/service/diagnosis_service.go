@@ -3,10 +3,10 @@ package service
 import (
 	"errors"
 	"fmt"
-	"time"
 
 	"github.com/minhtran/his/internal/domain"
 	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/pkg/clock"
 	"github.com/minhtran/his/internal/repository"
 )
 
@@ -22,6 +22,7 @@ type DiagnosisService struct {
 	icd10Repo     *repository.ICD10CodeRepository
 	visitRepo     *repository.VisitRepository
 	patientRepo   *repository.PatientRepository
+	clock         *clock.Clock
 }
 
 // NewDiagnosisService creates a new diagnosis service
@@ -30,12 +31,14 @@ func NewDiagnosisService(
 	icd10Repo *repository.ICD10CodeRepository,
 	visitRepo *repository.VisitRepository,
 	patientRepo *repository.PatientRepository,
+	clk *clock.Clock,
 ) *DiagnosisService {
 	return &DiagnosisService{
 		diagnosisRepo: diagnosisRepo,
 		icd10Repo:     icd10Repo,
 		visitRepo:     visitRepo,
 		patientRepo:   patientRepo,
+		clock:         clk,
 	}
 }
 
@@ -75,7 +78,7 @@ func (s *DiagnosisService) AddDiagnosis(req *dto.CreateDiagnosisRequest, diagnos
 		DiagnosisType:   domain.DiagnosisType(req.DiagnosisType),
 		DiagnosisStatus: domain.DiagnosisStatus(req.DiagnosisStatus),
 		ClinicalNotes:   req.ClinicalNotes,
-		DiagnosedAt:     time.Now(),
+		DiagnosedAt:     s.clock.Now(),
 		CreatedBy:       diagnosedBy,
 	}
 
@@ -3,10 +3,10 @@ package service
 import (
 	"errors"
 	"fmt"
-	"time"
 
 	"github.com/minhtran/his/internal/domain"
 	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/pkg/clock"
 	"github.com/minhtran/his/internal/repository"
 	"gorm.io/gorm"
 )
@@ -21,6 +21,7 @@ type DispensingService struct {
 	inventoryRepo    *repository.InventoryRepository
 	prescriptionRepo *repository.PrescriptionRepository
 	db               *gorm.DB
+	clock            *clock.Clock
 }
 
 // NewDispensingService creates a new dispensing service
@@ -29,12 +30,14 @@ func NewDispensingService(
 	inventoryRepo *repository.InventoryRepository,
 	prescriptionRepo *repository.PrescriptionRepository,
 	db *gorm.DB,
+	clk *clock.Clock,
 ) *DispensingService {
 	return &DispensingService{
 		dispensingRepo:   dispensingRepo,
 		inventoryRepo:    inventoryRepo,
 		prescriptionRepo: prescriptionRepo,
 		db:               db,
+		clock:            clk,
 	}
 }
 
@@ -78,7 +81,7 @@ func (s *DispensingService) DispensePrescription(req *dto.DispensePrescriptionRe
 		}
 
 		// Check expiry
-		if inventory.ExpiryDate.Before(time.Now()) {
+		if !clock.Day(inventory.ExpiryDate).After(s.clock.Today()) {
 			tx.Rollback()
 			return nil, ErrExpiredStock
 		}
@@ -101,7 +104,7 @@ func (s *DispensingService) DispensePrescription(req *dto.DispensePrescriptionRe
 			PharmacistID:       pharmacistID,
 			QuantityDispensed:  item.Quantity,
 			BatchNumber:        inventory.BatchNumber,
-			DispensedDate:      time.Now(),
+			DispensedDate:      s.clock.Now(),
 		}
 
 		if err := tx.Create(dispensing).Error; err != nil {
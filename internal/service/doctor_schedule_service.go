@@ -0,0 +1,986 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/minhtran/his/internal/domain"
+	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/pkg/clock"
+	"github.com/minhtran/his/internal/repository"
+)
+
+var (
+	// ErrDoctorNotFound is returned when the doctor does not exist
+	ErrDoctorNotFound = errors.New("doctor not found")
+	// ErrDoctorScheduleNotFound is returned when a weekly schedule session is not found
+	ErrDoctorScheduleNotFound = errors.New("doctor schedule not found")
+	// ErrScheduleOverrideNotFound is returned when a schedule override is not found
+	ErrScheduleOverrideNotFound = errors.New("schedule override not found")
+	// ErrDoctorLeaveNotFound is returned when a leave period is not found
+	ErrDoctorLeaveNotFound = errors.New("doctor leave not found")
+	// ErrHolidayNotFound is returned when a holiday is not found
+	ErrHolidayNotFound = errors.New("holiday not found")
+	// ErrInvalidSessionTimes is returned when session or break times are inconsistent
+	ErrInvalidSessionTimes = errors.New("session must end after it starts, the break must lie inside the session and the slot must fit in the session")
+	// ErrScheduleConflict is returned when a session overlaps another session of the same doctor
+	ErrScheduleConflict = errors.New("session overlaps an existing session of this doctor")
+	// ErrInvalidDateRange is returned when an end date is before its start date
+	ErrInvalidDateRange = errors.New("end date must not be before start date")
+)
+
+// Session sources
+const (
+	sessionSourceTemplate = "TEMPLATE"
+	sessionSourceOverride = "OVERRIDE"
+)
+
+// DoctorScheduleService handles doctor work schedules, overrides, leave and holidays
+type DoctorScheduleService struct {
+	scheduleRepo    *repository.DoctorScheduleRepository
+	userRepo        *repository.UserRepository
+	appointmentRepo *repository.AppointmentRepository
+	auditRepo       *repository.AuditLogRepository
+	clock           *clock.Clock
+}
+
+// NewDoctorScheduleService creates a new doctor schedule service
+func NewDoctorScheduleService(
+	scheduleRepo *repository.DoctorScheduleRepository,
+	userRepo *repository.UserRepository,
+	appointmentRepo *repository.AppointmentRepository,
+	auditRepo *repository.AuditLogRepository,
+	clk *clock.Clock,
+) *DoctorScheduleService {
+	return &DoctorScheduleService{
+		scheduleRepo:    scheduleRepo,
+		userRepo:        userRepo,
+		appointmentRepo: appointmentRepo,
+		auditRepo:       auditRepo,
+		clock:           clk,
+	}
+}
+
+// CreateSchedule adds a weekly clinic session for a doctor
+func (s *DoctorScheduleService) CreateSchedule(doctorID uint, req *dto.CreateDoctorScheduleRequest, userID uint) (*dto.DoctorScheduleResponse, error) {
+	if err := s.ensureDoctor(doctorID); err != nil {
+		return nil, err
+	}
+
+	schedule := &domain.DoctorSchedule{
+		DoctorID:    doctorID,
+		DayOfWeek:   *req.DayOfWeek,
+		StartTime:   req.StartTime,
+		EndTime:     req.EndTime,
+		BreakStart:  req.BreakStart,
+		BreakEnd:    req.BreakEnd,
+		Room:        req.Room,
+		SlotMinutes: req.SlotMinutes,
+		MaxPatients: req.MaxPatients,
+		IsActive:    true,
+		CreatedBy:   userID,
+		UpdatedBy:   userID,
+	}
+
+	schedule.EffectiveFrom = s.clock.Today()
+	if req.EffectiveFrom != "" {
+		from, err := time.Parse("2006-01-02", req.EffectiveFrom)
+		if err != nil {
+			return nil, ErrInvalidDateFormat
+		}
+		schedule.EffectiveFrom = from
+	}
+	if req.EffectiveTo != "" {
+		to, err := time.Parse("2006-01-02", req.EffectiveTo)
+		if err != nil {
+			return nil, ErrInvalidDateFormat
+		}
+		schedule.EffectiveTo = &to
+	}
+
+	if err := s.validateSchedule(schedule); err != nil {
+		return nil, err
+	}
+
+	if err := s.scheduleRepo.CreateSchedule(schedule); err != nil {
+		return nil, fmt.Errorf("failed to create schedule: %w", err)
+	}
+
+	// Audit log
+	s.auditRepo.Create(&domain.AuditLog{
+		UserID:     &userID,
+		Action:     domain.AuditActionCreate,
+		Resource:   "DoctorSchedule",
+		ResourceID: fmt.Sprintf("%d", schedule.ID),
+		Details: domain.AuditDetails{
+			"doctor_id":   doctorID,
+			"day_of_week": schedule.DayOfWeek,
+			"start_time":  schedule.StartTime,
+			"end_time":    schedule.EndTime,
+		},
+	})
+
+	return s.toScheduleResponse(schedule), nil
+}
+
+// GetSchedules lists the weekly clinic sessions of a doctor
+func (s *DoctorScheduleService) GetSchedules(doctorID uint) ([]*dto.DoctorScheduleResponse, error) {
+	schedules, err := s.scheduleRepo.FindSchedulesByDoctor(doctorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schedules: %w", err)
+	}
+
+	responses := make([]*dto.DoctorScheduleResponse, len(schedules))
+	for i, sc := range schedules {
+		responses[i] = s.toScheduleResponse(sc)
+	}
+	return responses, nil
+}
+
+// UpdateSchedule updates a weekly clinic session
+func (s *DoctorScheduleService) UpdateSchedule(doctorID, id uint, req *dto.UpdateDoctorScheduleRequest, userID uint) (*dto.DoctorScheduleResponse, error) {
+	schedule, err := s.scheduleRepo.FindScheduleByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find schedule: %w", err)
+	}
+	if schedule == nil || schedule.DoctorID != doctorID {
+		return nil, ErrDoctorScheduleNotFound
+	}
+
+	if req.StartTime != "" {
+		schedule.StartTime = req.StartTime
+	}
+	if req.EndTime != "" {
+		schedule.EndTime = req.EndTime
+	}
+	if req.ClearBreak {
+		schedule.BreakStart = ""
+		schedule.BreakEnd = ""
+	}
+	if req.BreakStart != "" {
+		schedule.BreakStart = req.BreakStart
+	}
+	if req.BreakEnd != "" {
+		schedule.BreakEnd = req.BreakEnd
+	}
+	if req.Room != "" {
+		schedule.Room = req.Room
+	}
+	if req.SlotMinutes > 0 {
+		schedule.SlotMinutes = req.SlotMinutes
+	}
+	if req.MaxPatients != nil {
+		schedule.MaxPatients = *req.MaxPatients
+	}
+	if req.EffectiveFrom != "" {
+		from, err := time.Parse("2006-01-02", req.EffectiveFrom)
+		if err != nil {
+			return nil, ErrInvalidDateFormat
+		}
+		schedule.EffectiveFrom = from
+	}
+	if req.EffectiveTo != "" {
+		to, err := time.Parse("2006-01-02", req.EffectiveTo)
+		if err != nil {
+			return nil, ErrInvalidDateFormat
+		}
+		schedule.EffectiveTo = &to
+	}
+	if req.IsActive != nil {
+		schedule.IsActive = *req.IsActive
+	}
+	schedule.UpdatedBy = userID
+
+	if err := s.validateSchedule(schedule); err != nil {
+		return nil, err
+	}
+
+	if err := s.scheduleRepo.UpdateSchedule(schedule); err != nil {
+		return nil, fmt.Errorf("failed to update schedule: %w", err)
+	}
+
+	// Audit log
+	s.auditRepo.Create(&domain.AuditLog{
+		UserID:     &userID,
+		Action:     domain.AuditActionUpdate,
+		Resource:   "DoctorSchedule",
+		ResourceID: fmt.Sprintf("%d", schedule.ID),
+		Details:    domain.AuditDetails{"doctor_id": doctorID, "is_active": schedule.IsActive},
+	})
+
+	return s.toScheduleResponse(schedule), nil
+}
+
+// DeleteSchedule removes a weekly clinic session
+func (s *DoctorScheduleService) DeleteSchedule(doctorID, id uint, userID uint) error {
+	schedule, err := s.scheduleRepo.FindScheduleByID(id)
+	if err != nil {
+		return fmt.Errorf("failed to find schedule: %w", err)
+	}
+	if schedule == nil || schedule.DoctorID != doctorID {
+		return ErrDoctorScheduleNotFound
+	}
+
+	if err := s.scheduleRepo.DeleteSchedule(id); err != nil {
+		return fmt.Errorf("failed to delete schedule: %w", err)
+	}
+
+	// Audit log
+	s.auditRepo.Create(&domain.AuditLog{
+		UserID:     &userID,
+		Action:     domain.AuditActionDelete,
+		Resource:   "DoctorSchedule",
+		ResourceID: fmt.Sprintf("%d", id),
+		Details:    domain.AuditDetails{"doctor_id": doctorID},
+	})
+
+	return nil
+}
+
+// CreateOverride replaces a doctor's weekly sessions on one date
+func (s *DoctorScheduleService) CreateOverride(doctorID uint, req *dto.CreateScheduleOverrideRequest, userID uint) (*dto.ScheduleOverrideResponse, error) {
+	if err := s.ensureDoctor(doctorID); err != nil {
+		return nil, err
+	}
+
+	date, err := time.Parse("2006-01-02", req.OverrideDate)
+	if err != nil {
+		return nil, ErrInvalidDateFormat
+	}
+
+	override := &domain.DoctorScheduleOverride{
+		DoctorID:     doctorID,
+		OverrideDate: date,
+		IsAvailable:  *req.IsAvailable,
+		Reason:       req.Reason,
+		CreatedBy:    userID,
+		UpdatedBy:    userID,
+	}
+
+	if override.IsAvailable {
+		override.StartTime = req.StartTime
+		override.EndTime = req.EndTime
+		override.BreakStart = req.BreakStart
+		override.BreakEnd = req.BreakEnd
+		override.Room = req.Room
+		override.SlotMinutes = req.SlotMinutes
+		override.MaxPatients = req.MaxPatients
+		if override.SlotMinutes == 0 {
+			override.SlotMinutes = 30
+		}
+
+		session, err := newClinicSession(override.StartTime, override.EndTime, override.BreakStart, override.BreakEnd, override.SlotMinutes)
+		if err != nil {
+			return nil, err
+		}
+
+		// Available overrides on the same date must not overlap each other
+		// and cannot coexist with a day-off override
+		existing, err := s.scheduleRepo.FindOverridesForDate(doctorID, date)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check overrides: %w", err)
+		}
+		for _, o := range existing {
+			if !o.IsAvailable {
+				return nil, ErrScheduleConflict
+			}
+			other, err := newClinicSession(o.StartTime, o.EndTime, o.BreakStart, o.BreakEnd, o.SlotMinutes)
+			if err == nil && session.overlaps(other) {
+				return nil, ErrScheduleConflict
+			}
+		}
+	} else {
+		existing, err := s.scheduleRepo.FindOverridesForDate(doctorID, date)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check overrides: %w", err)
+		}
+		if len(existing) > 0 {
+			return nil, ErrScheduleConflict
+		}
+	}
+
+	if err := s.scheduleRepo.CreateOverride(override); err != nil {
+		return nil, fmt.Errorf("failed to create override: %w", err)
+	}
+
+	// Audit log
+	s.auditRepo.Create(&domain.AuditLog{
+		UserID:     &userID,
+		Action:     domain.AuditActionCreate,
+		Resource:   "DoctorScheduleOverride",
+		ResourceID: fmt.Sprintf("%d", override.ID),
+		Details: domain.AuditDetails{
+			"doctor_id":     doctorID,
+			"override_date": req.OverrideDate,
+			"is_available":  override.IsAvailable,
+		},
+	})
+
+	return s.toOverrideResponse(override), nil
+}
+
+// GetOverrides lists a doctor's schedule overrides within a date range
+func (s *DoctorScheduleService) GetOverrides(doctorID uint, fromStr, toStr string) ([]*dto.ScheduleOverrideResponse, error) {
+	from, to, err := parseDateRange(s.clock.Today(), fromStr, toStr)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides, err := s.scheduleRepo.FindOverridesByDoctor(doctorID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get overrides: %w", err)
+	}
+
+	responses := make([]*dto.ScheduleOverrideResponse, len(overrides))
+	for i, o := range overrides {
+		responses[i] = s.toOverrideResponse(o)
+	}
+	return responses, nil
+}
+
+// DeleteOverride removes a schedule override
+func (s *DoctorScheduleService) DeleteOverride(doctorID, id uint, userID uint) error {
+	override, err := s.scheduleRepo.FindOverrideByID(id)
+	if err != nil {
+		return fmt.Errorf("failed to find override: %w", err)
+	}
+	if override == nil || override.DoctorID != doctorID {
+		return ErrScheduleOverrideNotFound
+	}
+
+	if err := s.scheduleRepo.DeleteOverride(id); err != nil {
+		return fmt.Errorf("failed to delete override: %w", err)
+	}
+
+	// Audit log
+	s.auditRepo.Create(&domain.AuditLog{
+		UserID:     &userID,
+		Action:     domain.AuditActionDelete,
+		Resource:   "DoctorScheduleOverride",
+		ResourceID: fmt.Sprintf("%d", id),
+		Details:    domain.AuditDetails{"doctor_id": doctorID, "override_date": override.OverrideDate.Format("2006-01-02")},
+	})
+
+	return nil
+}
+
+// CreateLeave records a leave period for a doctor. Existing bookings are
+// kept and reported so staff can reschedule them.
+func (s *DoctorScheduleService) CreateLeave(doctorID uint, req *dto.CreateDoctorLeaveRequest, userID uint) (*dto.DoctorLeaveResponse, error) {
+	if err := s.ensureDoctor(doctorID); err != nil {
+		return nil, err
+	}
+
+	start, end, err := parseDateRange(s.clock.Today(), req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	leave := &domain.DoctorLeave{
+		DoctorID:  doctorID,
+		StartDate: start,
+		EndDate:   end,
+		LeaveType: domain.LeaveType(req.LeaveType),
+		Reason:    req.Reason,
+		CreatedBy: userID,
+		UpdatedBy: userID,
+	}
+
+	if err := s.scheduleRepo.CreateLeave(leave); err != nil {
+		return nil, fmt.Errorf("failed to create leave: %w", err)
+	}
+
+	// Audit log
+	s.auditRepo.Create(&domain.AuditLog{
+		UserID:     &userID,
+		Action:     domain.AuditActionCreate,
+		Resource:   "DoctorLeave",
+		ResourceID: fmt.Sprintf("%d", leave.ID),
+		Details: domain.AuditDetails{
+			"doctor_id":  doctorID,
+			"start_date": req.StartDate,
+			"end_date":   req.EndDate,
+			"leave_type": req.LeaveType,
+		},
+	})
+
+	return s.toLeaveResponse(leave)
+}
+
+// GetLeaves lists a doctor's leave periods overlapping a date range
+func (s *DoctorScheduleService) GetLeaves(doctorID uint, fromStr, toStr string) ([]*dto.DoctorLeaveResponse, error) {
+	from, to, err := parseDateRange(s.clock.Today(), fromStr, toStr)
+	if err != nil {
+		return nil, err
+	}
+
+	leaves, err := s.scheduleRepo.FindLeavesByDoctor(doctorID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get leaves: %w", err)
+	}
+
+	responses := make([]*dto.DoctorLeaveResponse, len(leaves))
+	for i, l := range leaves {
+		resp, err := s.toLeaveResponse(l)
+		if err != nil {
+			return nil, err
+		}
+		responses[i] = resp
+	}
+	return responses, nil
+}
+
+// DeleteLeave removes a leave period
+func (s *DoctorScheduleService) DeleteLeave(doctorID, id uint, userID uint) error {
+	leave, err := s.scheduleRepo.FindLeaveByID(id)
+	if err != nil {
+		return fmt.Errorf("failed to find leave: %w", err)
+	}
+	if leave == nil || leave.DoctorID != doctorID {
+		return ErrDoctorLeaveNotFound
+	}
+
+	if err := s.scheduleRepo.DeleteLeave(id); err != nil {
+		return fmt.Errorf("failed to delete leave: %w", err)
+	}
+
+	// Audit log
+	s.auditRepo.Create(&domain.AuditLog{
+		UserID:     &userID,
+		Action:     domain.AuditActionDelete,
+		Resource:   "DoctorLeave",
+		ResourceID: fmt.Sprintf("%d", id),
+		Details:    domain.AuditDetails{"doctor_id": doctorID},
+	})
+
+	return nil
+}
+
+// CreateHoliday adds a facility-wide holiday
+func (s *DoctorScheduleService) CreateHoliday(req *dto.CreateHolidayRequest, userID uint) (*dto.HolidayResponse, error) {
+	date, err := time.Parse("2006-01-02", req.HolidayDate)
+	if err != nil {
+		return nil, ErrInvalidDateFormat
+	}
+
+	holiday := &domain.Holiday{
+		HolidayDate: date,
+		Name:        req.Name,
+		IsRecurring: req.IsRecurring,
+		CreatedBy:   userID,
+		UpdatedBy:   userID,
+	}
+
+	if err := s.scheduleRepo.CreateHoliday(holiday); err != nil {
+		return nil, fmt.Errorf("failed to create holiday: %w", err)
+	}
+
+	// Audit log
+	s.auditRepo.Create(&domain.AuditLog{
+		UserID:     &userID,
+		Action:     domain.AuditActionCreate,
+		Resource:   "Holiday",
+		ResourceID: fmt.Sprintf("%d", holiday.ID),
+		Details:    domain.AuditDetails{"holiday_date": req.HolidayDate, "name": req.Name},
+	})
+
+	return s.toHolidayResponse(holiday), nil
+}
+
+// ListHolidays lists the holidays of a year; a zero year means the facility's current year
+func (s *DoctorScheduleService) ListHolidays(year int) ([]*dto.HolidayResponse, error) {
+	if year == 0 {
+		year = s.clock.Now().Year()
+	}
+	holidays, err := s.scheduleRepo.ListHolidays(year)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list holidays: %w", err)
+	}
+
+	responses := make([]*dto.HolidayResponse, len(holidays))
+	for i, h := range holidays {
+		responses[i] = s.toHolidayResponse(h)
+	}
+	return responses, nil
+}
+
+// DeleteHoliday removes a holiday
+func (s *DoctorScheduleService) DeleteHoliday(id uint, userID uint) error {
+	holiday, err := s.scheduleRepo.FindHolidayByID(id)
+	if err != nil {
+		return fmt.Errorf("failed to find holiday: %w", err)
+	}
+	if holiday == nil {
+		return ErrHolidayNotFound
+	}
+
+	if err := s.scheduleRepo.DeleteHoliday(id); err != nil {
+		return fmt.Errorf("failed to delete holiday: %w", err)
+	}
+
+	// Audit log
+	s.auditRepo.Create(&domain.AuditLog{
+		UserID:     &userID,
+		Action:     domain.AuditActionDelete,
+		Resource:   "Holiday",
+		ResourceID: fmt.Sprintf("%d", id),
+		Details:    domain.AuditDetails{"name": holiday.Name},
+	})
+
+	return nil
+}
+
+// GetDaySchedule resolves a doctor's working sessions on a date with booking counts
+func (s *DoctorScheduleService) GetDaySchedule(doctorID uint, dateStr string) (*dto.DoctorDayScheduleResponse, error) {
+	date, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		return nil, ErrInvalidDateFormat
+	}
+
+	day, err := resolveClinicDay(s.scheduleRepo, doctorID, date)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.DoctorDayScheduleResponse{
+		DoctorID:  doctorID,
+		Date:      dateStr,
+		IsWorking: len(day.sessions) > 0,
+		Reason:    day.reason,
+		Sessions:  make([]*dto.ClinicSessionResponse, len(day.sessions)),
+	}
+
+	for i, cs := range day.sessions {
+		booked, err := s.appointmentRepo.CountInTimeRange(doctorID, date, clockTime(cs.start), clockTime(cs.end), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count bookings: %w", err)
+		}
+		resp.Sessions[i] = &dto.ClinicSessionResponse{
+			StartTime:   formatClock(cs.start),
+			EndTime:     formatClock(cs.end),
+			Room:        cs.room,
+			SlotMinutes: cs.slotMinutes,
+			MaxPatients: cs.maxPatients,
+			BookedCount: booked,
+			Source:      cs.source,
+		}
+		if cs.hasBreak() {
+			resp.Sessions[i].BreakStart = formatClock(cs.breakStart)
+			resp.Sessions[i].BreakEnd = formatClock(cs.breakEnd)
+		}
+	}
+
+	return resp, nil
+}
+
+// ensureDoctor checks that the doctor exists
+func (s *DoctorScheduleService) ensureDoctor(doctorID uint) error {
+	doctor, err := s.userRepo.FindByID(doctorID)
+	if err != nil {
+		return fmt.Errorf("failed to find doctor: %w", err)
+	}
+	if doctor == nil {
+		return ErrDoctorNotFound
+	}
+	return nil
+}
+
+// validateSchedule checks session times and that the session does not overlap
+// another active session of the doctor on the same weekday and period
+func (s *DoctorScheduleService) validateSchedule(schedule *domain.DoctorSchedule) error {
+	if schedule.EffectiveTo != nil && schedule.EffectiveTo.Before(schedule.EffectiveFrom) {
+		return ErrInvalidDateRange
+	}
+
+	session, err := newClinicSession(schedule.StartTime, schedule.EndTime, schedule.BreakStart, schedule.BreakEnd, schedule.SlotMinutes)
+	if err != nil {
+		return err
+	}
+	if !schedule.IsActive {
+		return nil
+	}
+
+	existing, err := s.scheduleRepo.FindSchedulesByDoctor(schedule.DoctorID)
+	if err != nil {
+		return fmt.Errorf("failed to check schedules: %w", err)
+	}
+	for _, other := range existing {
+		if other.ID == schedule.ID || !other.IsActive || other.DayOfWeek != schedule.DayOfWeek {
+			continue
+		}
+		if !periodsOverlap(schedule.EffectiveFrom, schedule.EffectiveTo, other.EffectiveFrom, other.EffectiveTo) {
+			continue
+		}
+		otherSession, err := newClinicSession(other.StartTime, other.EndTime, other.BreakStart, other.BreakEnd, other.SlotMinutes)
+		if err == nil && session.overlaps(otherSession) {
+			return ErrScheduleConflict
+		}
+	}
+
+	return nil
+}
+
+// Helper functions
+func (s *DoctorScheduleService) toScheduleResponse(sc *domain.DoctorSchedule) *dto.DoctorScheduleResponse {
+	resp := &dto.DoctorScheduleResponse{
+		ID:            sc.ID,
+		DoctorID:      sc.DoctorID,
+		DayOfWeek:     sc.DayOfWeek,
+		StartTime:     sc.StartTime,
+		EndTime:       sc.EndTime,
+		BreakStart:    sc.BreakStart,
+		BreakEnd:      sc.BreakEnd,
+		Room:          sc.Room,
+		SlotMinutes:   sc.SlotMinutes,
+		MaxPatients:   sc.MaxPatients,
+		EffectiveFrom: sc.EffectiveFrom.Format("2006-01-02"),
+		IsActive:      sc.IsActive,
+		CreatedAt:     sc.CreatedAt,
+		UpdatedAt:     sc.UpdatedAt,
+	}
+	if sc.EffectiveTo != nil {
+		resp.EffectiveTo = sc.EffectiveTo.Format("2006-01-02")
+	}
+	return resp
+}
+
+func (s *DoctorScheduleService) toOverrideResponse(o *domain.DoctorScheduleOverride) *dto.ScheduleOverrideResponse {
+	return &dto.ScheduleOverrideResponse{
+		ID:           o.ID,
+		DoctorID:     o.DoctorID,
+		OverrideDate: o.OverrideDate.Format("2006-01-02"),
+		IsAvailable:  o.IsAvailable,
+		StartTime:    o.StartTime,
+		EndTime:      o.EndTime,
+		BreakStart:   o.BreakStart,
+		BreakEnd:     o.BreakEnd,
+		Room:         o.Room,
+		SlotMinutes:  o.SlotMinutes,
+		MaxPatients:  o.MaxPatients,
+		Reason:       o.Reason,
+		CreatedAt:    o.CreatedAt,
+	}
+}
+
+func (s *DoctorScheduleService) toLeaveResponse(l *domain.DoctorLeave) (*dto.DoctorLeaveResponse, error) {
+	affected, err := s.appointmentRepo.CountActiveInDateRange(l.DoctorID, l.StartDate, l.EndDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count affected appointments: %w", err)
+	}
+
+	return &dto.DoctorLeaveResponse{
+		ID:                   l.ID,
+		DoctorID:             l.DoctorID,
+		StartDate:            l.StartDate.Format("2006-01-02"),
+		EndDate:              l.EndDate.Format("2006-01-02"),
+		LeaveType:            string(l.LeaveType),
+		Reason:               l.Reason,
+		AffectedAppointments: affected,
+		CreatedAt:            l.CreatedAt,
+	}, nil
+}
+
+func (s *DoctorScheduleService) toHolidayResponse(h *domain.Holiday) *dto.HolidayResponse {
+	return &dto.HolidayResponse{
+		ID:          h.ID,
+		HolidayDate: h.HolidayDate.Format("2006-01-02"),
+		Name:        h.Name,
+		IsRecurring: h.IsRecurring,
+		CreatedAt:   h.CreatedAt,
+	}
+}
+
+// clinicSession is a resolved working session with times in minutes since midnight
+type clinicSession struct {
+	start, end           int
+	breakStart, breakEnd int // Both zero when the session has no break
+	room                 string
+	slotMinutes          int
+	maxPatients          int
+	source               string
+}
+
+// clinicDay is a doctor's resolved working day; reason explains an empty day
+type clinicDay struct {
+	sessions []*clinicSession
+	reason   string
+}
+
+func (cs *clinicSession) hasBreak() bool {
+	return cs.breakEnd > cs.breakStart
+}
+
+// fits reports whether an appointment lies inside the session and outside its break
+func (cs *clinicSession) fits(start, duration int) bool {
+	end := start + duration
+	if start < cs.start || end > cs.end {
+		return false
+	}
+	if cs.hasBreak() && start < cs.breakEnd && end > cs.breakStart {
+		return false
+	}
+	return true
+}
+
+// overlaps reports whether two sessions share any time
+func (cs *clinicSession) overlaps(other *clinicSession) bool {
+	return cs.start < other.end && other.start < cs.end
+}
+
+// newClinicSession parses and validates HH:MM session times
+func newClinicSession(start, end, breakStart, breakEnd string, slotMinutes int) (*clinicSession, error) {
+	cs := &clinicSession{slotMinutes: slotMinutes}
+
+	var err error
+	if cs.start, err = parseClock(start); err != nil {
+		return nil, ErrInvalidSessionTimes
+	}
+	if cs.end, err = parseClock(end); err != nil {
+		return nil, ErrInvalidSessionTimes
+	}
+	if cs.end <= cs.start || slotMinutes <= 0 || slotMinutes > cs.end-cs.start {
+		return nil, ErrInvalidSessionTimes
+	}
+
+	if breakStart != "" || breakEnd != "" {
+		if cs.breakStart, err = parseClock(breakStart); err != nil {
+			return nil, ErrInvalidSessionTimes
+		}
+		if cs.breakEnd, err = parseClock(breakEnd); err != nil {
+			return nil, ErrInvalidSessionTimes
+		}
+		if cs.breakStart <= cs.start || cs.breakEnd >= cs.end || cs.breakEnd <= cs.breakStart {
+			return nil, ErrInvalidSessionTimes
+		}
+	}
+
+	return cs, nil
+}
+
+// resolveClinicDay resolves a doctor's sessions on a date. Leave always closes
+// the day; date overrides replace the weekly sessions and may open a holiday;
+// otherwise holidays close the day and the weekly sessions apply.
+func resolveClinicDay(repo *repository.DoctorScheduleRepository, doctorID uint, date time.Time) (*clinicDay, error) {
+	leave, err := repo.FindLeaveOnDate(doctorID, date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check leave: %w", err)
+	}
+	if leave != nil {
+		return leaveDay(leave), nil
+	}
+
+	overrides, err := repo.FindOverridesForDate(doctorID, date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check overrides: %w", err)
+	}
+	if len(overrides) > 0 {
+		return overrideDay(overrides), nil
+	}
+
+	holiday, err := repo.FindHolidayOnDate(date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check holidays: %w", err)
+	}
+	if holiday != nil {
+		return holidayDay(holiday), nil
+	}
+
+	schedules, err := repo.FindSchedulesForDate(doctorID, date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schedules: %w", err)
+	}
+	return weeklyDay(schedules), nil
+}
+
+func leaveDay(leave *domain.DoctorLeave) *clinicDay {
+	return &clinicDay{reason: fmt.Sprintf("doctor on %s leave", leave.LeaveType)}
+}
+
+func holidayDay(holiday *domain.Holiday) *clinicDay {
+	return &clinicDay{reason: "holiday: " + holiday.Name}
+}
+
+// overrideDay builds a day from its date overrides; an unavailable override closes it
+func overrideDay(overrides []*domain.DoctorScheduleOverride) *clinicDay {
+	day := &clinicDay{}
+	for _, o := range overrides {
+		if !o.IsAvailable {
+			return &clinicDay{reason: "schedule override: " + o.Reason}
+		}
+		cs, err := newClinicSession(o.StartTime, o.EndTime, o.BreakStart, o.BreakEnd, o.SlotMinutes)
+		if err != nil {
+			continue
+		}
+		cs.room = o.Room
+		cs.maxPatients = o.MaxPatients
+		cs.source = sessionSourceOverride
+		day.sessions = append(day.sessions, cs)
+	}
+	return day
+}
+
+// weeklyDay builds a day from the weekly sessions that apply on it
+func weeklyDay(schedules []*domain.DoctorSchedule) *clinicDay {
+	day := &clinicDay{}
+	for _, sc := range schedules {
+		cs, err := newClinicSession(sc.StartTime, sc.EndTime, sc.BreakStart, sc.BreakEnd, sc.SlotMinutes)
+		if err != nil {
+			continue
+		}
+		cs.room = sc.Room
+		cs.maxPatients = sc.MaxPatients
+		cs.source = sessionSourceTemplate
+		day.sessions = append(day.sessions, cs)
+	}
+	if len(day.sessions) == 0 {
+		day.reason = "no clinic session scheduled"
+	}
+	return day
+}
+
+// clinicCalendar holds the weekly schedules, overrides and leave of a set of
+// doctors and the holidays over a date range, so each doctor's days resolve
+// in memory the same way resolveClinicDay resolves one
+type clinicCalendar struct {
+	schedules map[uint][]*domain.DoctorSchedule
+	overrides map[uint][]*domain.DoctorScheduleOverride
+	leaves    map[uint][]*domain.DoctorLeave
+	holidays  []*domain.Holiday
+}
+
+// loadClinicCalendar loads the doctors' schedule data between two dates (inclusive)
+func loadClinicCalendar(repo *repository.DoctorScheduleRepository, doctorIDs []uint, from, to time.Time) (*clinicCalendar, error) {
+	cc := &clinicCalendar{
+		schedules: make(map[uint][]*domain.DoctorSchedule),
+		overrides: make(map[uint][]*domain.DoctorScheduleOverride),
+		leaves:    make(map[uint][]*domain.DoctorLeave),
+	}
+	if len(doctorIDs) == 0 {
+		return cc, nil
+	}
+
+	schedules, err := repo.FindSchedulesForDoctors(doctorIDs, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schedules: %w", err)
+	}
+	for _, sc := range schedules {
+		cc.schedules[sc.DoctorID] = append(cc.schedules[sc.DoctorID], sc)
+	}
+
+	overrides, err := repo.FindOverridesForDoctors(doctorIDs, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get overrides: %w", err)
+	}
+	for _, o := range overrides {
+		cc.overrides[o.DoctorID] = append(cc.overrides[o.DoctorID], o)
+	}
+
+	leaves, err := repo.FindLeavesForDoctors(doctorIDs, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get leave: %w", err)
+	}
+	for _, l := range leaves {
+		cc.leaves[l.DoctorID] = append(cc.leaves[l.DoctorID], l)
+	}
+
+	if cc.holidays, err = repo.FindHolidaysBetween(from, to); err != nil {
+		return nil, fmt.Errorf("failed to get holidays: %w", err)
+	}
+	return cc, nil
+}
+
+// day resolves a doctor's sessions on a date
+func (cc *clinicCalendar) day(doctorID uint, date time.Time) *clinicDay {
+	for _, l := range cc.leaves[doctorID] {
+		if !date.Before(clock.Day(l.StartDate)) && !date.After(clock.Day(l.EndDate)) {
+			return leaveDay(l)
+		}
+	}
+
+	var overrides []*domain.DoctorScheduleOverride
+	for _, o := range cc.overrides[doctorID] {
+		if clock.Day(o.OverrideDate).Equal(date) {
+			overrides = append(overrides, o)
+		}
+	}
+	if len(overrides) > 0 {
+		return overrideDay(overrides)
+	}
+
+	for _, h := range cc.holidays {
+		holidayDate := clock.Day(h.HolidayDate)
+		if holidayDate.Equal(date) || (h.IsRecurring && holidayDate.Month() == date.Month() && holidayDate.Day() == date.Day()) {
+			return holidayDay(h)
+		}
+	}
+
+	var schedules []*domain.DoctorSchedule
+	for _, sc := range cc.schedules[doctorID] {
+		if sc.DayOfWeek != int(date.Weekday()) || date.Before(clock.Day(sc.EffectiveFrom)) {
+			continue
+		}
+		if sc.EffectiveTo != nil && date.After(clock.Day(*sc.EffectiveTo)) {
+			continue
+		}
+		schedules = append(schedules, sc)
+	}
+	return weeklyDay(schedules)
+}
+
+// parseClock parses HH:MM into minutes since midnight
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// formatClock formats minutes since midnight as HH:MM
+func formatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// clockTime converts minutes since midnight to a time of day as used by appointment times
+func clockTime(minutes int) time.Time {
+	return time.Date(0, 1, 1, minutes/60, minutes%60, 0, 0, time.UTC)
+}
+
+// parseDateRange parses an inclusive YYYY-MM-DD range; empty values default
+// to today and 30 days from the start
+func parseDateRange(today time.Time, fromStr, toStr string) (time.Time, time.Time, error) {
+	from := today
+	if fromStr != "" {
+		parsed, err := time.Parse("2006-01-02", fromStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidDateFormat
+		}
+		from = parsed
+	}
+
+	to := from.AddDate(0, 0, 30)
+	if toStr != "" {
+		parsed, err := time.Parse("2006-01-02", toStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidDateFormat
+		}
+		to = parsed
+	}
+
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, ErrInvalidDateRange
+	}
+	return from, to, nil
+}
+
+// periodsOverlap reports whether two effective periods share a day; nil ends are open
+func periodsOverlap(fromA time.Time, toA *time.Time, fromB time.Time, toB *time.Time) bool {
+	if toA != nil && toA.Before(fromB) {
+		return false
+	}
+	if toB != nil && toB.Before(fromA) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,614 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/minhtran/his/internal/domain"
+	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/pkg/clock"
+	"github.com/minhtran/his/internal/pkg/logger"
+	"github.com/minhtran/his/internal/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrFollowUpNotFound is returned when the follow-up is not found
+	ErrFollowUpNotFound = errors.New("follow-up not found")
+	// ErrFollowUpNotPending is returned when confirming a follow-up that is not waiting for confirmation
+	ErrFollowUpNotPending = errors.New("only pending follow-ups can be confirmed")
+	// ErrFollowUpNotUnscheduled is returned when booking a follow-up that already has an appointment
+	ErrFollowUpNotUnscheduled = errors.New("only unscheduled follow-ups can be booked")
+	// ErrFollowUpClosed is returned when cancelling a follow-up that was attended, missed or cancelled
+	ErrFollowUpClosed = errors.New("follow-up is no longer open")
+)
+
+const followUpAdherenceDefaultDays = 365
+
+// FollowUpPolicy holds how follow-ups are booked and when they count as missed
+type FollowUpPolicy struct {
+	SearchDays      int // Days from the due date searched for a free slot with the doctor
+	MissedAfterDays int // Days after its due date an unscheduled follow-up counts as missed
+}
+
+// FollowUpService turns the next visit date set when a visit is completed
+// into a follow-up appointment with the same doctor, keeps reception's list
+// of follow-ups to confirm or book, and tracks whether patients attend them
+type FollowUpService struct {
+	followUpRepo       *repository.FollowUpRepository
+	visitRepo          *repository.VisitRepository
+	patientRepo        *repository.PatientRepository
+	userRepo           *repository.UserRepository
+	auditRepo          *repository.AuditLogRepository
+	appointmentService *AppointmentService
+	policy             FollowUpPolicy
+	clock              *clock.Clock
+}
+
+// NewFollowUpService creates a new follow-up service and subscribes it to
+// visits being checked in and closed
+func NewFollowUpService(
+	followUpRepo *repository.FollowUpRepository,
+	visitRepo *repository.VisitRepository,
+	patientRepo *repository.PatientRepository,
+	userRepo *repository.UserRepository,
+	auditRepo *repository.AuditLogRepository,
+	appointmentService *AppointmentService,
+	visitService *VisitService,
+	policy FollowUpPolicy,
+	clk *clock.Clock,
+) *FollowUpService {
+	s := &FollowUpService{
+		followUpRepo:       followUpRepo,
+		visitRepo:          visitRepo,
+		patientRepo:        patientRepo,
+		userRepo:           userRepo,
+		auditRepo:          auditRepo,
+		appointmentService: appointmentService,
+		policy:             policy,
+		clock:              clk,
+	}
+	visitService.OnClosed(s.handleClosed)
+	visitService.OnCheckedIn(s.handleCheckedIn)
+	return s
+}
+
+// GetFollowUp gets a follow-up by ID
+func (s *FollowUpService) GetFollowUp(id uint) (*dto.FollowUpResponse, error) {
+	followUp, err := s.findFollowUp(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.toFollowUpResponse(followUp), nil
+}
+
+// GetVisitFollowUp gets the follow-up ordered in a visit
+func (s *FollowUpService) GetVisitFollowUp(visitID uint) (*dto.FollowUpResponse, error) {
+	followUp, err := s.followUpRepo.FindByVisit(visitID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find follow-up: %w", err)
+	}
+	if followUp == nil {
+		return nil, ErrFollowUpNotFound
+	}
+	return s.toFollowUpResponse(followUp), nil
+}
+
+// ListFollowUps lists follow-ups with filters, soonest due first
+func (s *FollowUpService) ListFollowUps(filters map[string]interface{}, page, pageSize int) ([]*dto.FollowUpResponse, int64, error) {
+	followUps, total, err := s.followUpRepo.List(filters, page, pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list follow-ups: %w", err)
+	}
+
+	items := make([]*dto.FollowUpResponse, len(followUps))
+	for i, followUp := range followUps {
+		items[i] = s.toFollowUpResponse(followUp)
+	}
+	return items, total, nil
+}
+
+// GetReceptionWorklist gets the follow-ups reception has to confirm with the
+// patient or book by hand, soonest due first
+func (s *FollowUpService) GetReceptionWorklist() ([]*dto.FollowUpResponse, error) {
+	followUps, err := s.followUpRepo.FindAwaitingReception()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find follow-ups: %w", err)
+	}
+
+	items := make([]*dto.FollowUpResponse, len(followUps))
+	for i, followUp := range followUps {
+		items[i] = s.toFollowUpResponse(followUp)
+	}
+	return items, nil
+}
+
+// ConfirmFollowUp records that reception confirmed the booked appointment
+// with the patient, confirming the appointment too
+func (s *FollowUpService) ConfirmFollowUp(id uint, userID uint) (*dto.FollowUpResponse, error) {
+	followUp, err := s.findFollowUp(id)
+	if err != nil {
+		return nil, err
+	}
+	if followUp.Status != domain.FollowUpStatusPending || followUp.AppointmentID == nil {
+		return nil, ErrFollowUpNotPending
+	}
+
+	if followUp.Appointment != nil && followUp.Appointment.Status == domain.AppointmentStatusScheduled {
+		if err := s.appointmentService.ConfirmAppointment(*followUp.AppointmentID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	s.confirm(followUp, userID)
+	if err := s.followUpRepo.Update(followUp); err != nil {
+		return nil, fmt.Errorf("failed to update follow-up: %w", err)
+	}
+
+	s.auditFollowUp(followUp, domain.AuditActionUpdate, userID)
+	return s.GetFollowUp(followUp.ID)
+}
+
+// ScheduleFollowUp books an unscheduled follow-up at a time reception agreed
+// with the patient; the booking counts as confirmed
+func (s *FollowUpService) ScheduleFollowUp(id uint, req *dto.ScheduleFollowUpRequest, userID uint) (*dto.FollowUpResponse, error) {
+	followUp, err := s.findFollowUp(id)
+	if err != nil {
+		return nil, err
+	}
+	if followUp.Status != domain.FollowUpStatusUnscheduled {
+		return nil, ErrFollowUpNotUnscheduled
+	}
+
+	doctorID := followUp.DoctorID
+	if req.DoctorID != nil {
+		doctorID = *req.DoctorID
+	}
+	doctor, err := s.userRepo.FindByID(doctorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find doctor: %w", err)
+	}
+	if doctor == nil || !doctor.IsActive {
+		return nil, ErrDoctorNotFound
+	}
+
+	appointment, err := s.appointmentService.ScheduleAppointment(&dto.CreateAppointmentRequest{
+		PatientID:       followUp.PatientID,
+		DoctorID:        doctorID,
+		AppointmentDate: req.AppointmentDate,
+		AppointmentTime: req.AppointmentTime,
+		DurationMinutes: req.DurationMinutes,
+		AppointmentType: string(domain.AppointmentTypeFollowUp),
+		Reason:          followUpReason(followUp),
+		Notes:           followUp.Instructions,
+	}, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.appointmentService.ConfirmAppointment(appointment.ID, userID); err != nil {
+		return nil, err
+	}
+
+	followUp.AppointmentID = &appointment.ID
+	s.confirm(followUp, userID)
+	if err := s.followUpRepo.Update(followUp); err != nil {
+		return nil, fmt.Errorf("failed to update follow-up: %w", err)
+	}
+
+	s.auditFollowUp(followUp, domain.AuditActionUpdate, userID)
+	return s.GetFollowUp(followUp.ID)
+}
+
+// CancelFollowUp cancels an open follow-up and its booked appointment, e.g.
+// when the patient is followed up elsewhere
+func (s *FollowUpService) CancelFollowUp(id uint, reason string, userID uint) (*dto.FollowUpResponse, error) {
+	followUp, err := s.findFollowUp(id)
+	if err != nil {
+		return nil, err
+	}
+	if !followUp.Status.IsOpen() {
+		return nil, ErrFollowUpClosed
+	}
+
+	if followUp.Appointment != nil && followUp.Appointment.Status.CanTransitionTo(domain.AppointmentStatusCancelled) {
+		if _, err := s.appointmentService.CancelAppointment(followUp.Appointment.ID, reason, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	now := s.clock.Now()
+	followUp.Status = domain.FollowUpStatusCancelled
+	followUp.CancelReason = reason
+	followUp.ResolvedAt = &now
+	followUp.UpdatedBy = userID
+	if err := s.followUpRepo.Update(followUp); err != nil {
+		return nil, fmt.Errorf("failed to cancel follow-up: %w", err)
+	}
+
+	s.auditFollowUp(followUp, domain.AuditActionUpdate, userID)
+	return s.GetFollowUp(followUp.ID)
+}
+
+// GetPatientAdherence counts the follow-ups due within a period a patient
+// attended and missed; the period defaults to the past year
+func (s *FollowUpService) GetPatientAdherence(patientID uint, fromStr, toStr string) (*dto.FollowUpAdherence, error) {
+	patient, err := s.patientRepo.FindByID(patientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find patient: %w", err)
+	}
+	if patient == nil {
+		return nil, ErrPatientNotFound
+	}
+
+	from, to, err := s.adherencePeriod(fromStr, toStr)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.followUpRepo.CountPatientOutcomes(patientID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count follow-ups: %w", err)
+	}
+
+	adherence := followUpAdherence(counts, from, to)
+	adherence.PatientID = &patient.ID
+	adherence.Name = patient.FullName
+	return adherence, nil
+}
+
+// GetDoctorAdherence counts the follow-ups a doctor ordered, due within a
+// period, that patients attended and missed; the period defaults to the past year
+func (s *FollowUpService) GetDoctorAdherence(doctorID uint, fromStr, toStr string) (*dto.FollowUpAdherence, error) {
+	doctor, err := s.userRepo.FindByID(doctorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find doctor: %w", err)
+	}
+	if doctor == nil {
+		return nil, ErrDoctorNotFound
+	}
+
+	from, to, err := s.adherencePeriod(fromStr, toStr)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.followUpRepo.CountDoctorOutcomes(doctorID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count follow-ups: %w", err)
+	}
+
+	adherence := followUpAdherence(counts, from, to)
+	adherence.DoctorID = &doctor.ID
+	adherence.Name = doctor.FullName
+	return adherence, nil
+}
+
+// ResolveOutcomes brings booked follow-ups in line with their appointments
+// (confirmed, attended, missed, or cancelled and needing a new booking) and
+// marks unscheduled follow-ups missed once they are overdue by the policy's
+// grace. It returns the number of follow-ups updated.
+func (s *FollowUpService) ResolveOutcomes() (int, error) {
+	settled, err := s.followUpRepo.FindSettled()
+	if err != nil {
+		return 0, fmt.Errorf("failed to find settled follow-ups: %w", err)
+	}
+
+	now := s.clock.Now()
+	updated := 0
+	for _, followUp := range settled {
+		apt := followUp.Appointment
+		switch apt.Status {
+		case domain.AppointmentStatusConfirmed:
+			s.confirm(followUp, apt.UpdatedBy)
+		case domain.AppointmentStatusInProgress, domain.AppointmentStatusCompleted:
+			followUp.Status = domain.FollowUpStatusAttended
+			followUp.ResolvedAt = &now
+			visit, err := s.visitRepo.FindByAppointmentID(apt.ID)
+			if err != nil {
+				return updated, fmt.Errorf("failed to find visit: %w", err)
+			}
+			if visit != nil {
+				followUp.AttendedVisitID = &visit.ID
+			}
+		case domain.AppointmentStatusNoShow:
+			followUp.Status = domain.FollowUpStatusMissed
+			followUp.ResolvedAt = &now
+		case domain.AppointmentStatusCancelled:
+			followUp.Status = domain.FollowUpStatusUnscheduled
+			followUp.AppointmentID = nil
+		default:
+			continue
+		}
+
+		followUp.Appointment = nil
+		if err := s.followUpRepo.Update(followUp); err != nil {
+			return updated, fmt.Errorf("failed to update follow-up: %w", err)
+		}
+		updated++
+	}
+
+	overdue, err := s.followUpRepo.FindUnscheduledDueBefore(s.clock.Today().AddDate(0, 0, -s.policy.MissedAfterDays))
+	if err != nil {
+		return updated, fmt.Errorf("failed to find overdue follow-ups: %w", err)
+	}
+	for _, followUp := range overdue {
+		followUp.Status = domain.FollowUpStatusMissed
+		followUp.ResolvedAt = &now
+		if err := s.followUpRepo.Update(followUp); err != nil {
+			return updated, fmt.Errorf("failed to update follow-up: %w", err)
+		}
+		updated++
+	}
+
+	return updated, nil
+}
+
+// Run resolves follow-up outcomes every interval until the context is cancelled
+func (s *FollowUpService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if n, err := s.ResolveOutcomes(); err != nil {
+				logger.Error("Failed to resolve follow-up outcomes", zap.Error(err))
+			} else if n > 0 {
+				logger.Info("Follow-up outcomes resolved", zap.Int("count", n))
+			}
+		}
+	}
+}
+
+// handleClosed orders a follow-up when a visit is completed with a next visit date
+func (s *FollowUpService) handleClosed(visit domain.Visit) {
+	if visit.Status != domain.VisitStatusCompleted || visit.NextVisitDate == nil {
+		return
+	}
+	if err := s.createFollowUp(&visit); err != nil {
+		logger.Error("Failed to order follow-up", zap.Uint("visit_id", visit.ID), zap.Error(err))
+	}
+}
+
+// handleCheckedIn records a follow-up as attended when the patient checks in
+// for its appointment, or with the same doctor while it is still unscheduled
+func (s *FollowUpService) handleCheckedIn(visit domain.Visit) {
+	var followUp *domain.FollowUp
+	var err error
+	if visit.AppointmentID != nil {
+		followUp, err = s.followUpRepo.FindOpenByAppointment(*visit.AppointmentID)
+	} else {
+		followUp, err = s.followUpRepo.FindUnscheduled(visit.PatientID, visit.DoctorID)
+	}
+	if err != nil {
+		logger.Error("Failed to find follow-up", zap.Uint("visit_id", visit.ID), zap.Error(err))
+		return
+	}
+	if followUp == nil {
+		return
+	}
+
+	now := s.clock.Now()
+	followUp.Status = domain.FollowUpStatusAttended
+	followUp.AttendedVisitID = &visit.ID
+	followUp.ResolvedAt = &now
+	if err := s.followUpRepo.Update(followUp); err != nil {
+		logger.Error("Failed to record follow-up attendance", zap.Uint("follow_up_id", followUp.ID), zap.Error(err))
+	}
+}
+
+// createFollowUp records the follow-up ordered in a visit and books the
+// first free slot with the visit's doctor from the due date on. Without a
+// free slot the follow-up is left for reception to book.
+func (s *FollowUpService) createFollowUp(visit *domain.Visit) error {
+	existing, err := s.followUpRepo.FindByVisit(visit.ID)
+	if err != nil {
+		return fmt.Errorf("failed to find follow-up: %w", err)
+	}
+	if existing != nil {
+		return nil
+	}
+
+	createdBy := visit.UpdatedBy
+	if createdBy == 0 {
+		createdBy = visit.DoctorID
+	}
+	followUp := &domain.FollowUp{
+		VisitID:      visit.ID,
+		PatientID:    visit.PatientID,
+		DoctorID:     visit.DoctorID,
+		DueDate:      clock.Day(*visit.NextVisitDate),
+		Instructions: visit.FollowUpInstructions,
+		Status:       domain.FollowUpStatusUnscheduled,
+		Visit:        visit,
+		CreatedBy:    createdBy,
+		UpdatedBy:    createdBy,
+	}
+
+	appointmentID, err := s.bookFirstSlot(followUp)
+	if err != nil {
+		return err
+	}
+	if appointmentID != nil {
+		followUp.AppointmentID = appointmentID
+		followUp.Status = domain.FollowUpStatusPending
+	}
+
+	if err := s.followUpRepo.Create(followUp); err != nil {
+		return fmt.Errorf("failed to create follow-up: %w", err)
+	}
+
+	logger.Info("Follow-up waiting for reception",
+		zap.Uint("follow_up_id", followUp.ID),
+		zap.Uint("visit_id", visit.ID),
+		zap.String("status", string(followUp.Status)),
+	)
+	s.auditFollowUp(followUp, domain.AuditActionCreate, createdBy)
+	return nil
+}
+
+// bookFirstSlot books the earliest available slot with the follow-up's doctor
+// within the policy's search window from the due date (or today, if later).
+// It returns nil when no slot can be booked for the patient.
+func (s *FollowUpService) bookFirstSlot(followUp *domain.FollowUp) (*uint, error) {
+	from := followUp.DueDate
+	if today := s.clock.Today(); from.Before(today) {
+		from = today
+	}
+	to := from.AddDate(0, 0, s.policy.SearchDays-1)
+	if to.Before(from) {
+		to = from
+	}
+
+	days, err := s.appointmentService.computeAvailability([]uint{followUp.DoctorID}, from, to, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, day := range days[followUp.DoctorID] {
+		for _, slot := range day.Slots {
+			if !slot.Available {
+				continue
+			}
+			start, _ := parseClock(slot.Time)
+			end, _ := parseClock(slot.EndTime)
+
+			appointment, err := s.appointmentService.ScheduleAppointment(&dto.CreateAppointmentRequest{
+				PatientID:       followUp.PatientID,
+				DoctorID:        followUp.DoctorID,
+				AppointmentDate: day.Date,
+				AppointmentTime: slot.Time,
+				DurationMinutes: end - start,
+				AppointmentType: string(domain.AppointmentTypeFollowUp),
+				Reason:          followUpReason(followUp),
+				Notes:           followUp.Instructions,
+			}, followUp.CreatedBy)
+			switch {
+			case err == nil:
+				return &appointment.ID, nil
+			case errors.Is(err, ErrTimeSlotNotAvailable), errors.Is(err, ErrClinicSessionFull):
+				// Taken since availability was computed
+				continue
+			case errors.Is(err, ErrPatientDeceased), errors.Is(err, ErrPatientBookingRestricted):
+				return nil, nil
+			default:
+				return nil, err
+			}
+		}
+	}
+	return nil, nil
+}
+
+// confirm marks a follow-up confirmed by a user
+func (s *FollowUpService) confirm(followUp *domain.FollowUp, userID uint) {
+	now := s.clock.Now()
+	followUp.Status = domain.FollowUpStatusConfirmed
+	followUp.ConfirmedBy = &userID
+	followUp.ConfirmedAt = &now
+	followUp.UpdatedBy = userID
+}
+
+// adherencePeriod parses an adherence period, defaulting to the past year up to today
+func (s *FollowUpService) adherencePeriod(fromStr, toStr string) (time.Time, time.Time, error) {
+	today := s.clock.Today()
+	if fromStr == "" {
+		fromStr = today.AddDate(0, 0, -followUpAdherenceDefaultDays).Format("2006-01-02")
+	}
+	if toStr == "" {
+		toStr = today.Format("2006-01-02")
+	}
+	return parseDateRange(today, fromStr, toStr)
+}
+
+func (s *FollowUpService) findFollowUp(id uint) (*domain.FollowUp, error) {
+	followUp, err := s.followUpRepo.FindByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find follow-up: %w", err)
+	}
+	if followUp == nil {
+		return nil, ErrFollowUpNotFound
+	}
+	return followUp, nil
+}
+
+// followUpReason is the reason given on a follow-up's appointment
+func followUpReason(followUp *domain.FollowUp) string {
+	if followUp.Visit != nil {
+		return "Follow-up of visit " + followUp.Visit.VisitCode
+	}
+	return fmt.Sprintf("Follow-up of visit #%d", followUp.VisitID)
+}
+
+// followUpAdherence sums follow-up counts by status into an adherence record
+func followUpAdherence(counts map[domain.FollowUpStatus]int64, from, to time.Time) *dto.FollowUpAdherence {
+	adherence := &dto.FollowUpAdherence{
+		From:      from.Format("2006-01-02"),
+		To:        to.Format("2006-01-02"),
+		Attended:  counts[domain.FollowUpStatusAttended],
+		Missed:    counts[domain.FollowUpStatusMissed],
+		Cancelled: counts[domain.FollowUpStatusCancelled],
+		Open: counts[domain.FollowUpStatusPending] +
+			counts[domain.FollowUpStatusUnscheduled] +
+			counts[domain.FollowUpStatusConfirmed],
+	}
+	adherence.Total = adherence.Attended + adherence.Missed + adherence.Cancelled + adherence.Open
+	if kept := adherence.Attended + adherence.Missed; kept > 0 {
+		adherence.AdherenceRate = float64(adherence.Attended) / float64(kept)
+	}
+	return adherence
+}
+
+func (s *FollowUpService) auditFollowUp(followUp *domain.FollowUp, action domain.AuditAction, userID uint) {
+	s.auditRepo.Create(&domain.AuditLog{
+		UserID:     &userID,
+		Action:     action,
+		Resource:   "FollowUp",
+		ResourceID: fmt.Sprintf("%d", followUp.ID),
+		Details: domain.AuditDetails{
+			"visit_id":       followUp.VisitID,
+			"patient_id":     followUp.PatientID,
+			"due_date":       followUp.DueDate.Format("2006-01-02"),
+			"status":         followUp.Status,
+			"appointment_id": followUp.AppointmentID,
+		},
+	})
+}
+
+// Helper functions
+func (s *FollowUpService) toFollowUpResponse(followUp *domain.FollowUp) *dto.FollowUpResponse {
+	resp := &dto.FollowUpResponse{
+		ID:              followUp.ID,
+		VisitID:         followUp.VisitID,
+		PatientID:       followUp.PatientID,
+		DoctorID:        followUp.DoctorID,
+		DueDate:         followUp.DueDate.Format("2006-01-02"),
+		Instructions:    followUp.Instructions,
+		Status:          string(followUp.Status),
+		AppointmentID:   followUp.AppointmentID,
+		ConfirmedBy:     followUp.ConfirmedBy,
+		ConfirmedAt:     followUp.ConfirmedAt,
+		AttendedVisitID: followUp.AttendedVisitID,
+		ResolvedAt:      followUp.ResolvedAt,
+		CancelReason:    followUp.CancelReason,
+		CreatedAt:       followUp.CreatedAt,
+	}
+	if followUp.Visit != nil {
+		resp.VisitCode = followUp.Visit.VisitCode
+	}
+	if followUp.Patient != nil {
+		resp.PatientName = followUp.Patient.FullName
+		resp.PatientPhone = followUp.Patient.PhoneNumber
+	}
+	if followUp.Doctor != nil {
+		resp.DoctorName = followUp.Doctor.FullName
+	}
+	if followUp.Appointment != nil {
+		resp.AppointmentCode = followUp.Appointment.AppointmentCode
+		resp.AppointmentDate = followUp.Appointment.AppointmentDate.Format("2006-01-02")
+		resp.AppointmentTime = followUp.Appointment.AppointmentTime.Format("15:04")
+		resp.AppointmentStatus = string(followUp.Appointment.Status)
+	}
+	return resp
+}
@@ -7,12 +7,14 @@ import (
 
 	"github.com/minhtran/his/internal/domain"
 	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/pkg/clock"
 	"github.com/minhtran/his/internal/repository"
 )
 
 var (
 	ErrImagingTemplateNotFound = errors.New("imaging template not found")
 	ErrImagingRequestNotFound  = errors.New("imaging request not found")
+	ErrImagingReportNotReady   = errors.New("imaging request must be completed with a report before release to the patient portal")
 )
 
 // ImagingRequestService handles imaging request business logic
@@ -21,6 +23,8 @@ type ImagingRequestService struct {
 	resultRepo   *repository.ImagingResultRepository
 	templateRepo *repository.ImagingTemplateRepository
 	visitRepo    *repository.VisitRepository
+	resourceRepo *repository.ResourceRepository
+	clock        *clock.Clock
 }
 
 // NewImagingRequestService creates a new imaging request service
@@ -29,12 +33,16 @@ func NewImagingRequestService(
 	resultRepo *repository.ImagingResultRepository,
 	templateRepo *repository.ImagingTemplateRepository,
 	visitRepo *repository.VisitRepository,
+	resourceRepo *repository.ResourceRepository,
+	clk *clock.Clock,
 ) *ImagingRequestService {
 	return &ImagingRequestService{
 		requestRepo:  requestRepo,
 		resultRepo:   resultRepo,
 		templateRepo: templateRepo,
 		visitRepo:    visitRepo,
+		resourceRepo: resourceRepo,
+		clock:        clk,
 	}
 }
 
@@ -48,6 +56,9 @@ func (s *ImagingRequestService) CreateImagingRequest(req *dto.CreateImagingReque
 	if visit == nil {
 		return nil, ErrVisitNotFound
 	}
+	if visit.Patient != nil && visit.Patient.IsDeceased {
+		return nil, ErrPatientDeceased
+	}
 
 	// Validate template exists
 	template, err := s.templateRepo.FindByID(req.TemplateID)
@@ -73,7 +84,7 @@ func (s *ImagingRequestService) CreateImagingRequest(req *dto.CreateImagingReque
 		TemplateID:          req.TemplateID,
 		Status:              domain.ImagingRequestStatusPending,
 		Priority:            domain.ImagingPriority(req.Priority),
-		RequestedDate:       time.Now(),
+		RequestedDate:       s.clock.Now(),
 		ClinicalIndication:  req.ClinicalIndication,
 		SpecialInstructions: req.SpecialInstructions,
 		CreatedBy:           requestedBy,
@@ -88,8 +99,10 @@ func (s *ImagingRequestService) CreateImagingRequest(req *dto.CreateImagingReque
 	return s.toImagingRequestResponse(request), nil
 }
 
-// ScheduleImaging schedules an imaging request
-func (s *ImagingRequestService) ScheduleImaging(id uint, scheduledDate time.Time, updatedBy uint) error {
+// ScheduleImaging schedules an imaging request and reserves the rooms and
+// equipment it needs. Nil resourceIDs keeps the resources already booked for
+// the request; a zero duration keeps the current slot length.
+func (s *ImagingRequestService) ScheduleImaging(id uint, scheduledDate time.Time, duration int, resourceIDs []uint, updatedBy uint) error {
 	request, err := s.requestRepo.FindByID(id)
 	if err != nil {
 		return fmt.Errorf("failed to find request: %w", err)
@@ -98,10 +111,43 @@ func (s *ImagingRequestService) ScheduleImaging(id uint, scheduledDate time.Time
 		return ErrImagingRequestNotFound
 	}
 
+	if resourceIDs == nil {
+		bookings, err := s.resourceRepo.FindBookingsByImagingRequest(id)
+		if err != nil {
+			return fmt.Errorf("failed to find resource bookings: %w", err)
+		}
+		for _, b := range bookings {
+			resourceIDs = append(resourceIDs, b.ResourceID)
+		}
+	}
+	resources, lockIDs, err := loadBookableResources(s.resourceRepo, resourceIDs)
+	if err != nil {
+		return err
+	}
+
+	if duration > 0 {
+		request.DurationMinutes = duration
+	}
+	if request.DurationMinutes <= 0 {
+		request.DurationMinutes = 30
+	}
+	startAt := scheduledDate.In(s.clock.Location())
+	endAt := startAt.Add(time.Duration(request.DurationMinutes) * time.Minute)
+
 	request.Status = domain.ImagingRequestStatusScheduled
 	request.ScheduledDate = &scheduledDate
 	request.UpdatedBy = updatedBy
-	return s.requestRepo.Update(request)
+
+	// Check the resources and book them under their booking locks
+	return s.requestRepo.WithResourceLock(lockIDs, func(repo *repository.ImagingRequestRepository, resourceRepo *repository.ResourceRepository) error {
+		if err := checkResourcesFree(resourceRepo, resources, startAt, endAt, nil, &request.ID); err != nil {
+			return err
+		}
+		if err := resourceRepo.ReplaceImagingBookings(request.ID, newResourceBookings(resources, startAt, endAt, nil, &request.ID, &updatedBy)); err != nil {
+			return fmt.Errorf("failed to book resources: %w", err)
+		}
+		return repo.Update(request)
+	})
 }
 
 // StartImaging marks imaging as in progress
@@ -129,7 +175,7 @@ func (s *ImagingRequestService) CompleteImaging(id uint, updatedBy uint) error {
 		return ErrImagingRequestNotFound
 	}
 
-	now := time.Now()
+	now := s.clock.Now()
 	request.Status = domain.ImagingRequestStatusCompleted
 	request.CompletedAt = &now
 	request.UpdatedBy = updatedBy
@@ -151,6 +197,50 @@ func (s *ImagingRequestService) CancelImaging(id uint, updatedBy uint) error {
 	return s.requestRepo.Update(request)
 }
 
+// ReleaseToPortal makes a completed imaging report visible to the patient in the portal
+func (s *ImagingRequestService) ReleaseToPortal(id uint, releasedBy uint) (*dto.ImagingRequestResponse, error) {
+	request, err := s.requestRepo.FindByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find request: %w", err)
+	}
+	if request == nil {
+		return nil, ErrImagingRequestNotFound
+	}
+	if request.Status != domain.ImagingRequestStatusCompleted || request.Result == nil {
+		return nil, ErrImagingReportNotReady
+	}
+
+	now := s.clock.Now()
+	request.PortalReleasedAt = &now
+	request.PortalReleasedBy = &releasedBy
+	request.UpdatedBy = releasedBy
+	if err := s.requestRepo.Update(request); err != nil {
+		return nil, fmt.Errorf("failed to release request: %w", err)
+	}
+
+	return s.toImagingRequestResponse(request), nil
+}
+
+// WithdrawFromPortal hides an imaging report from the patient portal again
+func (s *ImagingRequestService) WithdrawFromPortal(id uint, updatedBy uint) (*dto.ImagingRequestResponse, error) {
+	request, err := s.requestRepo.FindByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find request: %w", err)
+	}
+	if request == nil {
+		return nil, ErrImagingRequestNotFound
+	}
+
+	request.PortalReleasedAt = nil
+	request.PortalReleasedBy = nil
+	request.UpdatedBy = updatedBy
+	if err := s.requestRepo.Update(request); err != nil {
+		return nil, fmt.Errorf("failed to withdraw request: %w", err)
+	}
+
+	return s.toImagingRequestResponse(request), nil
+}
+
 // CreateOrUpdateResult creates or updates imaging result
 func (s *ImagingRequestService) CreateOrUpdateResult(requestID uint, req *dto.CreateImagingResultRequest, radiologistID uint) error {
 	// Check if result exists
@@ -165,7 +255,7 @@ func (s *ImagingRequestService) CreateOrUpdateResult(requestID uint, req *dto.Cr
 		existingResult.Impression = req.Impression
 		existingResult.DICOMFiles = req.DICOMFiles
 		existingResult.IsCritical = req.IsCritical
-		existingResult.ReportDate = time.Now()
+		existingResult.ReportDate = s.clock.Now()
 		existingResult.RadiologistID = radiologistID
 		return s.resultRepo.Update(existingResult)
 	}
@@ -177,7 +267,7 @@ func (s *ImagingRequestService) CreateOrUpdateResult(requestID uint, req *dto.Cr
 		Findings:      req.Findings,
 		Impression:    req.Impression,
 		DICOMFiles:    req.DICOMFiles,
-		ReportDate:    time.Now(),
+		ReportDate:    s.clock.Now(),
 		IsCritical:    req.IsCritical,
 	}
 
@@ -193,7 +283,14 @@ func (s *ImagingRequestService) GetImagingRequestByID(id uint) (*dto.ImagingRequ
 	if request == nil {
 		return nil, ErrImagingRequestNotFound
 	}
-	return s.toImagingRequestResponse(request), nil
+
+	bookings, err := s.resourceRepo.FindBookingsByImagingRequest(request.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find resource bookings: %w", err)
+	}
+	resp := s.toImagingRequestResponse(request)
+	resp.Resources = toBookedResources(bookings)
+	return resp, nil
 }
 
 // GetImagingRequestByCode gets request by code
@@ -249,9 +346,11 @@ func (s *ImagingRequestService) toImagingRequestResponse(r *domain.ImagingReques
 		Priority:            string(r.Priority),
 		RequestedDate:       r.RequestedDate,
 		ScheduledDate:       r.ScheduledDate,
+		DurationMinutes:     r.DurationMinutes,
 		CompletedAt:         r.CompletedAt,
 		ClinicalIndication:  r.ClinicalIndication,
 		SpecialInstructions: r.SpecialInstructions,
+		PortalReleasedAt:    r.PortalReleasedAt,
 		CreatedAt:           r.CreatedAt,
 		UpdatedAt:           r.UpdatedAt,
 	}
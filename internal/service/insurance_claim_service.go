@@ -3,31 +3,40 @@ package service
 import (
 	"errors"
 	"fmt"
-	"time"
+	"math"
 
 	"github.com/minhtran/his/internal/domain"
 	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/pkg/clock"
 	"github.com/minhtran/his/internal/repository"
 )
 
 var (
 	ErrInsuranceClaimNotFound = errors.New("insurance claim not found")
+	// ErrClaimAmountRequired is returned when a claim without a coverage has no amount to default from
+	ErrClaimAmountRequired = errors.New("claim amount is required when claiming without a recorded coverage")
 )
 
 // InsuranceClaimService handles insurance claim business logic
 type InsuranceClaimService struct {
-	claimRepo   *repository.InsuranceClaimRepository
-	invoiceRepo *repository.InvoiceRepository
+	claimRepo    *repository.InsuranceClaimRepository
+	invoiceRepo  *repository.InvoiceRepository
+	coverageRepo *repository.PatientCoverageRepository
+	clock        *clock.Clock
 }
 
 // NewInsuranceClaimService creates a new insurance claim service
 func NewInsuranceClaimService(
 	claimRepo *repository.InsuranceClaimRepository,
 	invoiceRepo *repository.InvoiceRepository,
+	coverageRepo *repository.PatientCoverageRepository,
+	clk *clock.Clock,
 ) *InsuranceClaimService {
 	return &InsuranceClaimService{
-		claimRepo:   claimRepo,
-		invoiceRepo: invoiceRepo,
+		claimRepo:    claimRepo,
+		invoiceRepo:  invoiceRepo,
+		coverageRepo: coverageRepo,
+		clock:        clk,
 	}
 }
 
@@ -42,6 +51,20 @@ func (s *InsuranceClaimService) CreateInsuranceClaim(req *dto.CreateInsuranceCla
 		return nil, ErrInvoiceNotFound
 	}
 
+	// Select coverage: explicit, then the one recorded on the visit, then the patient's active coverage
+	coverageID := req.CoverageID
+	serviceDate := invoice.InvoiceDate
+	if invoice.Visit != nil {
+		serviceDate = invoice.Visit.VisitDate
+		if coverageID == nil {
+			coverageID = invoice.Visit.CoverageID
+		}
+	}
+	coverage, err := resolveCoverage(s.coverageRepo, invoice.PatientID, serviceDate, coverageID)
+	if err != nil {
+		return nil, err
+	}
+
 	// Generate claim code
 	code, err := s.claimRepo.GenerateClaimCode()
 	if err != nil {
@@ -50,16 +73,34 @@ func (s *InsuranceClaimService) CreateInsuranceClaim(req *dto.CreateInsuranceCla
 
 	// Create claim
 	claim := &domain.InsuranceClaim{
-		ClaimCode:         code,
-		InvoiceID:         req.InvoiceID,
-		PatientID:         invoice.PatientID,
-		InsuranceProvider: req.InsuranceProvider,
-		PolicyNumber:      req.PolicyNumber,
-		ClaimAmount:       req.ClaimAmount,
-		ClaimDate:         time.Now(),
-		Status:            domain.ClaimStatusSubmitted,
-		Notes:             req.Notes,
-		CreatedBy:         createdBy,
+		ClaimCode:   code,
+		InvoiceID:   req.InvoiceID,
+		PatientID:   invoice.PatientID,
+		ClaimAmount: req.ClaimAmount,
+		ClaimDate:   s.clock.Now(),
+		Status:      domain.ClaimStatusSubmitted,
+		Notes:       req.Notes,
+		CreatedBy:   createdBy,
+	}
+
+	switch {
+	case coverage != nil:
+		claim.CoverageID = &coverage.ID
+		claim.PayerID = &coverage.PayerID
+		claim.InsuranceProvider = coverage.Payer.Name
+		claim.PolicyNumber = coverage.PolicyNumber
+		if claim.ClaimAmount == 0 {
+			claim.ClaimAmount = math.Round(invoice.TotalAmount*coverage.CoveragePercent) / 100
+		}
+	case req.InsuranceProvider != "" && req.PolicyNumber != "":
+		// Without a coverage the claim is filed against the free-text insurance given
+		if req.ClaimAmount == 0 {
+			return nil, ErrClaimAmountRequired
+		}
+		claim.InsuranceProvider = req.InsuranceProvider
+		claim.PolicyNumber = req.PolicyNumber
+	default:
+		return nil, ErrNoActiveCoverage
 	}
 
 	if err := s.claimRepo.Create(claim); err != nil {
@@ -81,7 +122,7 @@ func (s *InsuranceClaimService) ApproveClaim(id uint, req *dto.ApproveClaimReque
 		return ErrInsuranceClaimNotFound
 	}
 
-	now := time.Now()
+	now := s.clock.Now()
 	claim.ApprovedAmount = &req.ApprovedAmount
 	claim.ApprovalDate = &now
 	claim.Status = domain.ClaimStatusApproved
@@ -128,6 +169,8 @@ func (s *InsuranceClaimService) toInsuranceClaimResponse(c *domain.InsuranceClai
 		ClaimCode:         c.ClaimCode,
 		InvoiceID:         c.InvoiceID,
 		PatientID:         c.PatientID,
+		CoverageID:        c.CoverageID,
+		PayerID:           c.PayerID,
 		InsuranceProvider: c.InsuranceProvider,
 		PolicyNumber:      c.PolicyNumber,
 		ClaimAmount:       c.ClaimAmount,
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/minhtran/his/internal/domain"
+	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/repository"
+)
+
+var (
+	// ErrInsurancePayerNotFound is returned when insurance payer is not found
+	ErrInsurancePayerNotFound = errors.New("insurance payer not found")
+	// ErrInsurancePayerCodeExists is returned when insurance payer code already exists
+	ErrInsurancePayerCodeExists = errors.New("insurance payer code already exists")
+)
+
+// InsurancePayerService handles business logic for the insurance payer catalog
+type InsurancePayerService struct {
+	payerRepo *repository.InsurancePayerRepository
+	auditRepo *repository.AuditLogRepository
+}
+
+// NewInsurancePayerService creates a new insurance payer service
+func NewInsurancePayerService(payerRepo *repository.InsurancePayerRepository, auditRepo *repository.AuditLogRepository) *InsurancePayerService {
+	return &InsurancePayerService{
+		payerRepo: payerRepo,
+		auditRepo: auditRepo,
+	}
+}
+
+// CreatePayer adds a payer to the catalog
+func (s *InsurancePayerService) CreatePayer(req *dto.CreateInsurancePayerRequest, userID uint) (*dto.InsurancePayerResponse, error) {
+	existing, err := s.payerRepo.FindByCode(req.Code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check payer code: %w", err)
+	}
+	if existing != nil {
+		return nil, ErrInsurancePayerCodeExists
+	}
+
+	payer := &domain.InsurancePayer{
+		Code:        req.Code,
+		Name:        req.Name,
+		PayerType:   domain.PayerType(req.PayerType),
+		PhoneNumber: req.PhoneNumber,
+		Email:       req.Email,
+		Address:     req.Address,
+		IsActive:    true,
+		CreatedBy:   userID,
+		UpdatedBy:   userID,
+	}
+
+	if err := s.payerRepo.Create(payer); err != nil {
+		return nil, fmt.Errorf("failed to create payer: %w", err)
+	}
+
+	// Audit log
+	s.auditRepo.Create(&domain.AuditLog{
+		UserID:     &userID,
+		Action:     domain.AuditActionCreate,
+		Resource:   "InsurancePayer",
+		ResourceID: payer.Code,
+		Details:    domain.AuditDetails{"name": payer.Name, "payer_type": payer.PayerType},
+	})
+
+	return s.toPayerResponse(payer), nil
+}
+
+// GetPayer gets payer details
+func (s *InsurancePayerService) GetPayer(id uint) (*dto.InsurancePayerResponse, error) {
+	payer, err := s.payerRepo.FindByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find payer: %w", err)
+	}
+	if payer == nil {
+		return nil, ErrInsurancePayerNotFound
+	}
+
+	return s.toPayerResponse(payer), nil
+}
+
+// UpdatePayer updates a payer
+func (s *InsurancePayerService) UpdatePayer(id uint, req *dto.UpdateInsurancePayerRequest, userID uint) (*dto.InsurancePayerResponse, error) {
+	payer, err := s.payerRepo.FindByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find payer: %w", err)
+	}
+	if payer == nil {
+		return nil, ErrInsurancePayerNotFound
+	}
+
+	if req.Name != "" {
+		payer.Name = req.Name
+	}
+	if req.PayerType != "" {
+		payer.PayerType = domain.PayerType(req.PayerType)
+	}
+	if req.PhoneNumber != "" {
+		payer.PhoneNumber = req.PhoneNumber
+	}
+	if req.Email != "" {
+		payer.Email = req.Email
+	}
+	if req.Address != "" {
+		payer.Address = req.Address
+	}
+	if req.IsActive != nil {
+		payer.IsActive = *req.IsActive
+	}
+	payer.UpdatedBy = userID
+
+	if err := s.payerRepo.Update(payer); err != nil {
+		return nil, fmt.Errorf("failed to update payer: %w", err)
+	}
+
+	// Audit log
+	s.auditRepo.Create(&domain.AuditLog{
+		UserID:     &userID,
+		Action:     domain.AuditActionUpdate,
+		Resource:   "InsurancePayer",
+		ResourceID: payer.Code,
+		Details:    domain.AuditDetails{"id": id, "is_active": payer.IsActive},
+	})
+
+	return s.toPayerResponse(payer), nil
+}
+
+// DeletePayer soft deletes a payer
+func (s *InsurancePayerService) DeletePayer(id uint, userID uint) error {
+	payer, err := s.payerRepo.FindByID(id)
+	if err != nil {
+		return fmt.Errorf("failed to find payer: %w", err)
+	}
+	if payer == nil {
+		return ErrInsurancePayerNotFound
+	}
+
+	if err := s.payerRepo.Delete(id); err != nil {
+		return fmt.Errorf("failed to delete payer: %w", err)
+	}
+
+	// Audit log
+	s.auditRepo.Create(&domain.AuditLog{
+		UserID:     &userID,
+		Action:     domain.AuditActionDelete,
+		Resource:   "InsurancePayer",
+		ResourceID: payer.Code,
+		Details:    domain.AuditDetails{"id": id},
+	})
+
+	return nil
+}
+
+// ListPayers returns a paginated list of payers
+func (s *InsurancePayerService) ListPayers(page, pageSize int) ([]*dto.InsurancePayerResponse, int64, error) {
+	payers, total, err := s.payerRepo.List(page, pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list payers: %w", err)
+	}
+
+	responses := make([]*dto.InsurancePayerResponse, len(payers))
+	for i, p := range payers {
+		responses[i] = s.toPayerResponse(p)
+	}
+
+	return responses, total, nil
+}
+
+// Helper functions
+func (s *InsurancePayerService) toPayerResponse(p *domain.InsurancePayer) *dto.InsurancePayerResponse {
+	return &dto.InsurancePayerResponse{
+		ID:          p.ID,
+		Code:        p.Code,
+		Name:        p.Name,
+		PayerType:   string(p.PayerType),
+		PhoneNumber: p.PhoneNumber,
+		Email:       p.Email,
+		Address:     p.Address,
+		IsActive:    p.IsActive,
+		CreatedAt:   p.CreatedAt,
+		UpdatedAt:   p.UpdatedAt,
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/minhtran/his/internal/domain"
 	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/pkg/clock"
 	"github.com/minhtran/his/internal/repository"
 )
 
@@ -18,11 +19,12 @@ var (
 // InventoryService handles inventory business logic
 type InventoryService struct {
 	inventoryRepo *repository.InventoryRepository
+	clock         *clock.Clock
 }
 
 // NewInventoryService creates a new inventory service
-func NewInventoryService(inventoryRepo *repository.InventoryRepository) *InventoryService {
-	return &InventoryService{inventoryRepo: inventoryRepo}
+func NewInventoryService(inventoryRepo *repository.InventoryRepository, clk *clock.Clock) *InventoryService {
+	return &InventoryService{inventoryRepo: inventoryRepo, clock: clk}
 }
 
 // AddStock adds inventory
@@ -38,7 +40,7 @@ func (s *InventoryService) AddStock(req *dto.CreateInventoryRequest) (*dto.Inven
 	}
 
 	// Validate expiry date is in the future
-	if expiryDate.Before(time.Now()) {
+	if !expiryDate.After(s.clock.Today()) {
 		return nil, ErrExpiredStock
 	}
 
@@ -106,7 +108,7 @@ func (s *InventoryService) GetLowStockAlerts(threshold int) ([]*dto.InventoryLis
 
 // GetExpiringSoonAlerts gets expiring soon alerts
 func (s *InventoryService) GetExpiringSoonAlerts(days int) ([]*dto.InventoryListItem, error) {
-	inventories, err := s.inventoryRepo.FindExpiringSoon(days)
+	inventories, err := s.inventoryRepo.FindExpiringSoon(s.clock.Today(), days)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get expiring soon alerts: %w", err)
 	}
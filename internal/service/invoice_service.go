@@ -3,25 +3,33 @@ package service
 import (
 	"errors"
 	"fmt"
-	"time"
 
 	"github.com/minhtran/his/internal/domain"
 	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/pkg/clock"
 	"github.com/minhtran/his/internal/repository"
 )
 
 var (
 	ErrInvoiceNotFound = errors.New("invoice not found")
+	// ErrInvoiceNotDraft is returned when issuing an invoice that was already issued or cancelled
+	ErrInvoiceNotDraft = errors.New("only draft invoices can be issued")
+	// ErrInvoiceNotIssued is returned when paying a draft invoice
+	ErrInvoiceNotIssued = errors.New("draft invoices must be issued before they can be paid")
 )
 
+// invoicePaymentTermDays is how long after it is issued an invoice is due
+const invoicePaymentTermDays = 30
+
 // InvoiceService handles invoice business logic
 type InvoiceService struct {
 	invoiceRepo *repository.InvoiceRepository
+	clock       *clock.Clock
 }
 
 // NewInvoiceService creates a new invoice service
-func NewInvoiceService(invoiceRepo *repository.InvoiceRepository) *InvoiceService {
-	return &InvoiceService{invoiceRepo: invoiceRepo}
+func NewInvoiceService(invoiceRepo *repository.InvoiceRepository, clk *clock.Clock) *InvoiceService {
+	return &InvoiceService{invoiceRepo: invoiceRepo, clock: clk}
 }
 
 // CreateInvoice creates invoice with items
@@ -53,13 +61,13 @@ func (s *InvoiceService) CreateInvoice(req *dto.CreateInvoiceRequest, createdBy
 	totalAmount := subtotal + req.TaxAmount - req.DiscountAmount
 
 	// Create invoice
-	now := time.Now()
+	now := s.clock.Now()
 	invoice := &domain.Invoice{
 		InvoiceCode:    code,
 		VisitID:        req.VisitID,
 		PatientID:      req.PatientID,
 		InvoiceDate:    now,
-		DueDate:        now.AddDate(0, 0, 30), // 30 days from now
+		DueDate:        now.AddDate(0, 0, invoicePaymentTermDays),
 		Subtotal:       subtotal,
 		TaxAmount:      req.TaxAmount,
 		DiscountAmount: req.DiscountAmount,
@@ -79,6 +87,66 @@ func (s *InvoiceService) CreateInvoice(req *dto.CreateInvoiceRequest, createdBy
 	return s.toInvoiceResponse(invoice), nil
 }
 
+// createDraft creates, through repo, a draft invoice for a visit from priced
+// items, to be reviewed and issued by billing staff
+func (s *InvoiceService) createDraft(repo *repository.InvoiceRepository, visit *domain.Visit, items []*domain.InvoiceItem, notes string, createdBy uint) (*domain.Invoice, error) {
+	code, err := repo.GenerateInvoiceCode()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate invoice code: %w", err)
+	}
+
+	var subtotal float64
+	for _, item := range items {
+		item.Amount = float64(item.Quantity) * item.UnitPrice
+		subtotal += item.Amount
+	}
+
+	now := s.clock.Now()
+	visitID := visit.ID
+	invoice := &domain.Invoice{
+		InvoiceCode: code,
+		VisitID:     &visitID,
+		PatientID:   visit.PatientID,
+		InvoiceDate: now,
+		DueDate:     now.AddDate(0, 0, invoicePaymentTermDays),
+		Subtotal:    subtotal,
+		TotalAmount: subtotal,
+		Status:      domain.InvoiceStatusDraft,
+		Notes:       notes,
+		Items:       items,
+		CreatedBy:   createdBy,
+	}
+
+	if err := repo.Create(invoice); err != nil {
+		return nil, fmt.Errorf("failed to create invoice: %w", err)
+	}
+	return invoice, nil
+}
+
+// IssueInvoice issues a draft invoice, billing the patient from today
+func (s *InvoiceService) IssueInvoice(id uint, issuedBy uint) (*dto.InvoiceResponse, error) {
+	invoice, err := s.invoiceRepo.FindByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find invoice: %w", err)
+	}
+	if invoice == nil {
+		return nil, ErrInvoiceNotFound
+	}
+	if invoice.Status != domain.InvoiceStatusDraft {
+		return nil, ErrInvoiceNotDraft
+	}
+
+	now := s.clock.Now()
+	invoice.Status = domain.InvoiceStatusPending
+	invoice.InvoiceDate = now
+	invoice.DueDate = now.AddDate(0, 0, invoicePaymentTermDays)
+	invoice.UpdatedBy = issuedBy
+	if err := s.invoiceRepo.Update(invoice); err != nil {
+		return nil, fmt.Errorf("failed to issue invoice: %w", err)
+	}
+	return s.toInvoiceResponse(invoice), nil
+}
+
 // GetInvoiceByID gets invoice by ID
 func (s *InvoiceService) GetInvoiceByID(id uint) (*dto.InvoiceResponse, error) {
 	invoice, err := s.invoiceRepo.FindByID(id)
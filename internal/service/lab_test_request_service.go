@@ -4,16 +4,17 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
-	"time"
 
 	"github.com/minhtran/his/internal/domain"
 	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/pkg/clock"
 	"github.com/minhtran/his/internal/repository"
 )
 
 var (
 	ErrLabTestTemplateNotFound = errors.New("lab test template not found")
 	ErrLabTestRequestNotFound  = errors.New("lab test request not found")
+	ErrLabTestNotCompleted     = errors.New("lab test must be completed before release to the patient portal")
 )
 
 // LabTestRequestService handles lab test request business logic
@@ -22,6 +23,7 @@ type LabTestRequestService struct {
 	resultRepo   *repository.LabTestResultRepository
 	templateRepo *repository.LabTestTemplateRepository
 	visitRepo    *repository.VisitRepository
+	clock        *clock.Clock
 }
 
 // NewLabTestRequestService creates a new lab test request service
@@ -30,12 +32,14 @@ func NewLabTestRequestService(
 	resultRepo *repository.LabTestResultRepository,
 	templateRepo *repository.LabTestTemplateRepository,
 	visitRepo *repository.VisitRepository,
+	clk *clock.Clock,
 ) *LabTestRequestService {
 	return &LabTestRequestService{
 		requestRepo:  requestRepo,
 		resultRepo:   resultRepo,
 		templateRepo: templateRepo,
 		visitRepo:    visitRepo,
+		clock:        clk,
 	}
 }
 
@@ -49,6 +53,9 @@ func (s *LabTestRequestService) CreateLabTestRequest(req *dto.CreateLabTestReque
 	if visit == nil {
 		return nil, ErrVisitNotFound
 	}
+	if visit.Patient != nil && visit.Patient.IsDeceased {
+		return nil, ErrPatientDeceased
+	}
 
 	// Validate template exists
 	template, err := s.templateRepo.FindByID(req.TemplateID)
@@ -74,7 +81,7 @@ func (s *LabTestRequestService) CreateLabTestRequest(req *dto.CreateLabTestReque
 		TemplateID:    req.TemplateID,
 		Status:        domain.LabTestRequestStatusPending,
 		Priority:      domain.LabTestPriority(req.Priority),
-		RequestedDate: time.Now(),
+		RequestedDate: s.clock.Now(),
 		ClinicalNotes: req.ClinicalNotes,
 		CreatedBy:     requestedBy,
 	}
@@ -116,7 +123,7 @@ func (s *LabTestRequestService) CollectSample(id uint, updatedBy uint) error {
 		return ErrLabTestRequestNotFound
 	}
 
-	now := time.Now()
+	now := s.clock.Now()
 	request.Status = domain.LabTestRequestStatusSampleCollected
 	request.SampleCollectedAt = &now
 	request.UpdatedBy = updatedBy
@@ -148,7 +155,7 @@ func (s *LabTestRequestService) CompleteTest(id uint, updatedBy uint) error {
 		return ErrLabTestRequestNotFound
 	}
 
-	now := time.Now()
+	now := s.clock.Now()
 	request.Status = domain.LabTestRequestStatusCompleted
 	request.CompletedAt = &now
 	request.UpdatedBy = updatedBy
@@ -170,6 +177,50 @@ func (s *LabTestRequestService) CancelTest(id uint, updatedBy uint) error {
 	return s.requestRepo.Update(request)
 }
 
+// ReleaseToPortal makes a completed test's results visible to the patient in the portal
+func (s *LabTestRequestService) ReleaseToPortal(id uint, releasedBy uint) (*dto.LabTestRequestResponse, error) {
+	request, err := s.requestRepo.FindByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find request: %w", err)
+	}
+	if request == nil {
+		return nil, ErrLabTestRequestNotFound
+	}
+	if request.Status != domain.LabTestRequestStatusCompleted {
+		return nil, ErrLabTestNotCompleted
+	}
+
+	now := s.clock.Now()
+	request.PortalReleasedAt = &now
+	request.PortalReleasedBy = &releasedBy
+	request.UpdatedBy = releasedBy
+	if err := s.requestRepo.Update(request); err != nil {
+		return nil, fmt.Errorf("failed to release request: %w", err)
+	}
+
+	return s.toLabTestRequestResponse(request), nil
+}
+
+// WithdrawFromPortal hides a test's results from the patient portal again
+func (s *LabTestRequestService) WithdrawFromPortal(id uint, updatedBy uint) (*dto.LabTestRequestResponse, error) {
+	request, err := s.requestRepo.FindByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find request: %w", err)
+	}
+	if request == nil {
+		return nil, ErrLabTestRequestNotFound
+	}
+
+	request.PortalReleasedAt = nil
+	request.PortalReleasedBy = nil
+	request.UpdatedBy = updatedBy
+	if err := s.requestRepo.Update(request); err != nil {
+		return nil, fmt.Errorf("failed to withdraw request: %w", err)
+	}
+
+	return s.toLabTestRequestResponse(request), nil
+}
+
 // EnterResults enters test results with auto-abnormal flagging
 func (s *LabTestRequestService) EnterResults(requestID uint, req *dto.EnterLabTestResultsRequest) error {
 	// Get request with template parameters
@@ -298,6 +349,7 @@ func (s *LabTestRequestService) toLabTestRequestResponse(r *domain.LabTestReques
 		SampleCollectedAt: r.SampleCollectedAt,
 		CompletedAt:       r.CompletedAt,
 		ClinicalNotes:     r.ClinicalNotes,
+		PortalReleasedAt:  r.PortalReleasedAt,
 		CreatedAt:         r.CreatedAt,
 		UpdatedAt:         r.UpdatedAt,
 	}
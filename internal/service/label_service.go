@@ -0,0 +1,488 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/minhtran/his/internal/domain"
+	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/pkg/label"
+	"github.com/minhtran/his/internal/repository"
+)
+
+var (
+	// ErrLabelTemplateNotFound is returned when label template is not found
+	ErrLabelTemplateNotFound = errors.New("label template not found")
+	// ErrLabelTemplateCodeExists is returned when label template code already exists
+	ErrLabelTemplateCodeExists = errors.New("label template code already exists")
+	// ErrLabelTemplateTargetMismatch is returned when a template is used for a different target type
+	ErrLabelTemplateTargetMismatch = errors.New("label template is not for this record type")
+	// ErrInvalidLabelField is returned when a template contains an unknown field key
+	ErrInvalidLabelField = errors.New("invalid label field for target type")
+	// ErrLabelRenderFailed is returned when the label content cannot be laid out
+	ErrLabelRenderFailed = errors.New("failed to render label")
+)
+
+// Label field keys
+const (
+	LabelFieldPatientCode   = "patient_code"
+	LabelFieldFullName      = "full_name"
+	LabelFieldDateOfBirth   = "date_of_birth"
+	LabelFieldGenderAge     = "gender_age"
+	LabelFieldBloodType     = "blood_type"
+	LabelFieldAllergyFlag   = "allergy_flag"
+	LabelFieldFacility      = "facility"
+	LabelFieldAdmissionCode = "admission_code"
+	LabelFieldWardBed       = "ward_bed"
+	LabelFieldAdmissionDate = "admission_date"
+	LabelFieldDoctor        = "doctor"
+	LabelFieldRequestCode   = "request_code"
+	LabelFieldTestName      = "test_name"
+	LabelFieldSampleType    = "sample_type"
+	LabelFieldPriority      = "priority"
+	LabelFieldRequestedDate = "requested_date"
+)
+
+var patientLabelFields = []string{
+	LabelFieldPatientCode, LabelFieldFullName, LabelFieldDateOfBirth, LabelFieldGenderAge,
+	LabelFieldBloodType, LabelFieldAllergyFlag, LabelFieldFacility,
+}
+
+// labelFieldsByTarget lists the field keys available for each target type
+var labelFieldsByTarget = map[domain.LabelTargetType][]string{
+	domain.LabelTargetPatient: patientLabelFields,
+	domain.LabelTargetAdmission: append(append([]string{}, patientLabelFields...),
+		LabelFieldAdmissionCode, LabelFieldWardBed, LabelFieldAdmissionDate, LabelFieldDoctor),
+	domain.LabelTargetLabRequest: append(append([]string{}, patientLabelFields...),
+		LabelFieldRequestCode, LabelFieldTestName, LabelFieldSampleType, LabelFieldPriority,
+		LabelFieldRequestedDate, LabelFieldDoctor),
+}
+
+// LabelService handles label templates and rendering of wristbands and labels
+type LabelService struct {
+	templateRepo       *repository.LabelTemplateRepository
+	patientRepo        *repository.PatientRepository
+	allergyRepo        *repository.PatientAllergyRepository
+	admissionRepo      *repository.AdmissionRepository
+	labTestRequestRepo *repository.LabTestRequestRepository
+	auditRepo          *repository.AuditLogRepository
+	facilityName       string
+}
+
+// NewLabelService creates a new label service
+func NewLabelService(
+	templateRepo *repository.LabelTemplateRepository,
+	patientRepo *repository.PatientRepository,
+	allergyRepo *repository.PatientAllergyRepository,
+	admissionRepo *repository.AdmissionRepository,
+	labTestRequestRepo *repository.LabTestRequestRepository,
+	auditRepo *repository.AuditLogRepository,
+	facilityName string,
+) *LabelService {
+	return &LabelService{
+		templateRepo:       templateRepo,
+		patientRepo:        patientRepo,
+		allergyRepo:        allergyRepo,
+		admissionRepo:      admissionRepo,
+		labTestRequestRepo: labTestRequestRepo,
+		auditRepo:          auditRepo,
+		facilityName:       facilityName,
+	}
+}
+
+// CreateTemplate creates a label template
+func (s *LabelService) CreateTemplate(req *dto.CreateLabelTemplateRequest, userID uint) (*dto.LabelTemplateResponse, error) {
+	existing, err := s.templateRepo.FindByCode(req.Code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check template code: %w", err)
+	}
+	if existing != nil {
+		return nil, ErrLabelTemplateCodeExists
+	}
+
+	targetType := domain.LabelTargetType(req.TargetType)
+	if err := validateLabelFields(targetType, req.Fields); err != nil {
+		return nil, err
+	}
+
+	copies := req.Copies
+	if copies == 0 {
+		copies = 1
+	}
+
+	template := &domain.LabelTemplate{
+		Code:        req.Code,
+		Name:        req.Name,
+		TargetType:  targetType,
+		WidthMM:     req.WidthMM,
+		HeightMM:    req.HeightMM,
+		BarcodeType: domain.LabelBarcodeType(req.BarcodeType),
+		Fields:      strings.Join(req.Fields, ","),
+		FontSizePt:  req.FontSizePt,
+		Copies:      copies,
+		IsDefault:   req.IsDefault,
+		IsActive:    true,
+		CreatedBy:   userID,
+		UpdatedBy:   userID,
+	}
+
+	if template.IsDefault {
+		if err := s.templateRepo.ClearDefault(targetType); err != nil {
+			return nil, fmt.Errorf("failed to clear default template: %w", err)
+		}
+	}
+
+	if err := s.templateRepo.Create(template); err != nil {
+		return nil, fmt.Errorf("failed to create template: %w", err)
+	}
+
+	// Audit log
+	s.auditRepo.Create(&domain.AuditLog{
+		UserID:     &userID,
+		Action:     domain.AuditActionCreate,
+		Resource:   "LabelTemplate",
+		ResourceID: template.Code,
+		Details:    domain.AuditDetails{"name": template.Name, "target_type": template.TargetType},
+	})
+
+	return s.toTemplateResponse(template), nil
+}
+
+// GetTemplate gets label template details
+func (s *LabelService) GetTemplate(id uint) (*dto.LabelTemplateResponse, error) {
+	template, err := s.templateRepo.FindByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find template: %w", err)
+	}
+	if template == nil {
+		return nil, ErrLabelTemplateNotFound
+	}
+
+	return s.toTemplateResponse(template), nil
+}
+
+// UpdateTemplate updates a label template
+func (s *LabelService) UpdateTemplate(id uint, req *dto.UpdateLabelTemplateRequest, userID uint) (*dto.LabelTemplateResponse, error) {
+	template, err := s.templateRepo.FindByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find template: %w", err)
+	}
+	if template == nil {
+		return nil, ErrLabelTemplateNotFound
+	}
+
+	if req.Name != "" {
+		template.Name = req.Name
+	}
+	if req.WidthMM > 0 {
+		template.WidthMM = req.WidthMM
+	}
+	if req.HeightMM > 0 {
+		template.HeightMM = req.HeightMM
+	}
+	if req.BarcodeType != "" {
+		template.BarcodeType = domain.LabelBarcodeType(req.BarcodeType)
+	}
+	if len(req.Fields) > 0 {
+		if err := validateLabelFields(template.TargetType, req.Fields); err != nil {
+			return nil, err
+		}
+		template.Fields = strings.Join(req.Fields, ",")
+	}
+	if req.FontSizePt != nil {
+		template.FontSizePt = *req.FontSizePt
+	}
+	if req.Copies > 0 {
+		template.Copies = req.Copies
+	}
+	if req.IsActive != nil {
+		template.IsActive = *req.IsActive
+	}
+	if req.IsDefault != nil {
+		if *req.IsDefault && !template.IsDefault {
+			if err := s.templateRepo.ClearDefault(template.TargetType); err != nil {
+				return nil, fmt.Errorf("failed to clear default template: %w", err)
+			}
+		}
+		template.IsDefault = *req.IsDefault
+	}
+	template.UpdatedBy = userID
+
+	if err := s.templateRepo.Update(template); err != nil {
+		return nil, fmt.Errorf("failed to update template: %w", err)
+	}
+
+	// Audit log
+	s.auditRepo.Create(&domain.AuditLog{
+		UserID:     &userID,
+		Action:     domain.AuditActionUpdate,
+		Resource:   "LabelTemplate",
+		ResourceID: template.Code,
+		Details:    domain.AuditDetails{"id": id, "is_active": template.IsActive, "is_default": template.IsDefault},
+	})
+
+	return s.toTemplateResponse(template), nil
+}
+
+// DeleteTemplate soft deletes a label template
+func (s *LabelService) DeleteTemplate(id uint, userID uint) error {
+	template, err := s.templateRepo.FindByID(id)
+	if err != nil {
+		return fmt.Errorf("failed to find template: %w", err)
+	}
+	if template == nil {
+		return ErrLabelTemplateNotFound
+	}
+
+	if err := s.templateRepo.Delete(id); err != nil {
+		return fmt.Errorf("failed to delete template: %w", err)
+	}
+
+	// Audit log
+	s.auditRepo.Create(&domain.AuditLog{
+		UserID:     &userID,
+		Action:     domain.AuditActionDelete,
+		Resource:   "LabelTemplate",
+		ResourceID: template.Code,
+		Details:    domain.AuditDetails{"id": id},
+	})
+
+	return nil
+}
+
+// ListTemplates returns a paginated list of label templates
+func (s *LabelService) ListTemplates(targetType string, page, pageSize int) ([]*dto.LabelTemplateResponse, int64, error) {
+	templates, total, err := s.templateRepo.List(targetType, page, pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list templates: %w", err)
+	}
+
+	responses := make([]*dto.LabelTemplateResponse, len(templates))
+	for i, t := range templates {
+		responses[i] = s.toTemplateResponse(t)
+	}
+
+	return responses, total, nil
+}
+
+// PrintPatientLabels renders wristbands or chart labels for a patient
+func (s *LabelService) PrintPatientLabels(patientID uint, req *dto.PrintLabelRequest) ([]byte, string, error) {
+	patient, err := s.patientRepo.FindByID(patientID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to find patient: %w", err)
+	}
+	if patient == nil {
+		return nil, "", ErrPatientNotFound
+	}
+
+	values, err := s.patientLabelValues(patient)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return s.render(domain.LabelTargetPatient, req, values, patient.PatientCode, patient.PatientCode)
+}
+
+// PrintAdmissionLabels renders inpatient wristbands for an admission
+func (s *LabelService) PrintAdmissionLabels(admissionID uint, req *dto.PrintLabelRequest) ([]byte, string, error) {
+	admission, err := s.admissionRepo.FindByID(admissionID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to find admission: %w", err)
+	}
+	if admission == nil || admission.Patient == nil {
+		return nil, "", ErrAdmissionNotFound
+	}
+
+	values, err := s.patientLabelValues(admission.Patient)
+	if err != nil {
+		return nil, "", err
+	}
+
+	values[LabelFieldAdmissionCode] = admission.AdmissionCode
+	values[LabelFieldAdmissionDate] = "Vào viện: " + admission.AdmissionDate.Format("02/01/2006")
+	if admission.Doctor != nil {
+		values[LabelFieldDoctor] = "BS: " + admission.Doctor.FullName
+	}
+	for _, allocation := range admission.BedAllocations {
+		if allocation.IsCurrent && allocation.Bed != nil {
+			values[LabelFieldWardBed] = fmt.Sprintf("%s - Giường %s", allocation.Bed.Ward, allocation.Bed.BedNumber)
+			break
+		}
+	}
+
+	return s.render(domain.LabelTargetAdmission, req, values, admission.AdmissionCode, admission.AdmissionCode)
+}
+
+// PrintLabTestRequestLabels renders specimen labels for a lab test request
+func (s *LabelService) PrintLabTestRequestLabels(requestID uint, req *dto.PrintLabelRequest) ([]byte, string, error) {
+	request, err := s.labTestRequestRepo.FindByID(requestID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to find lab test request: %w", err)
+	}
+	if request == nil || request.Patient == nil {
+		return nil, "", ErrLabTestRequestNotFound
+	}
+
+	values, err := s.patientLabelValues(request.Patient)
+	if err != nil {
+		return nil, "", err
+	}
+
+	values[LabelFieldRequestCode] = request.RequestCode
+	values[LabelFieldPriority] = string(request.Priority)
+	values[LabelFieldRequestedDate] = request.RequestedDate.Format("02/01/2006 15:04")
+	if request.Template != nil {
+		values[LabelFieldTestName] = request.Template.Name
+		values[LabelFieldSampleType] = string(request.Template.SampleType)
+	}
+	if request.Doctor != nil {
+		values[LabelFieldDoctor] = "BS: " + request.Doctor.FullName
+	}
+
+	return s.render(domain.LabelTargetLabRequest, req, values, request.RequestCode, request.RequestCode)
+}
+
+// Helper functions
+
+// patientLabelValues builds the patient field values shared by all label targets
+func (s *LabelService) patientLabelValues(patient *domain.Patient) (map[string]string, error) {
+	allergies, err := s.allergyRepo.FindActiveByPatientID(patient.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find allergies: %w", err)
+	}
+
+	gender := "Khác"
+	switch patient.Gender {
+	case domain.GenderMale:
+		gender = "Nam"
+	case domain.GenderFemale:
+		gender = "Nữ"
+	}
+
+	values := map[string]string{
+		LabelFieldPatientCode: patient.PatientCode,
+		LabelFieldFullName:    strings.ToUpper(patient.FullName),
+		LabelFieldDateOfBirth: "NS: " + patient.DateOfBirth.Format("02/01/2006"),
+		LabelFieldGenderAge:   fmt.Sprintf("%s - %d tuổi", gender, patient.Age),
+		LabelFieldFacility:    s.facilityName,
+	}
+	if patient.BloodType != "" {
+		values[LabelFieldBloodType] = "Nhóm máu: " + string(patient.BloodType)
+	}
+	if len(allergies) > 0 {
+		allergens := make([]string, len(allergies))
+		for i, a := range allergies {
+			allergens[i] = a.Allergen
+		}
+		values[LabelFieldAllergyFlag] = "DỊ ỨNG: " + strings.ToUpper(strings.Join(allergens, ", "))
+	}
+
+	return values, nil
+}
+
+// render resolves the template and renders the label in the requested format
+func (s *LabelService) render(targetType domain.LabelTargetType, req *dto.PrintLabelRequest, values map[string]string, barcodeData, fileBase string) ([]byte, string, error) {
+	template, err := s.resolveTemplate(targetType, req.Template)
+	if err != nil {
+		return nil, "", err
+	}
+
+	content := label.Content{}
+	if template.BarcodeType != domain.LabelBarcodeNone {
+		content.BarcodeData = barcodeData
+	}
+	for _, field := range template.FieldList() {
+		if field == LabelFieldAllergyFlag {
+			content.Flag = values[field]
+			continue
+		}
+		if v := values[field]; v != "" {
+			content.Lines = append(content.Lines, v)
+		}
+	}
+
+	spec := label.Spec{
+		WidthMM:    template.WidthMM,
+		HeightMM:   template.HeightMM,
+		Barcode:    label.BarcodeType(template.BarcodeType),
+		FontSizePt: template.FontSizePt,
+	}
+	copies := template.Copies
+	if req.Copies > 0 {
+		copies = req.Copies
+	}
+
+	var data []byte
+	ext := "pdf"
+	if req.Format == "zpl" {
+		ext = "zpl"
+		data, err = label.RenderZPL(spec, []label.Content{content}, copies, req.DPI)
+	} else {
+		data, err = label.RenderPDF(spec, []label.Content{content}, copies)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrLabelRenderFailed, err)
+	}
+
+	fileName := fmt.Sprintf("%s-%s.%s", fileBase, strings.ToLower(template.Code), ext)
+	return data, fileName, nil
+}
+
+// resolveTemplate finds the template by code, or the default template for the target type
+func (s *LabelService) resolveTemplate(targetType domain.LabelTargetType, code string) (*domain.LabelTemplate, error) {
+	var template *domain.LabelTemplate
+	var err error
+	if code != "" {
+		template, err = s.templateRepo.FindByCode(code)
+	} else {
+		template, err = s.templateRepo.FindDefault(targetType)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find label template: %w", err)
+	}
+	if template == nil || !template.IsActive {
+		return nil, ErrLabelTemplateNotFound
+	}
+	if template.TargetType != targetType {
+		return nil, ErrLabelTemplateTargetMismatch
+	}
+	return template, nil
+}
+
+// validateLabelFields checks that every field key is available for the target type
+func validateLabelFields(targetType domain.LabelTargetType, fields []string) error {
+	allowed := labelFieldsByTarget[targetType]
+	for _, f := range fields {
+		found := false
+		for _, a := range allowed {
+			if f == a {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%w: %s", ErrInvalidLabelField, f)
+		}
+	}
+	return nil
+}
+
+func (s *LabelService) toTemplateResponse(t *domain.LabelTemplate) *dto.LabelTemplateResponse {
+	return &dto.LabelTemplateResponse{
+		ID:          t.ID,
+		Code:        t.Code,
+		Name:        t.Name,
+		TargetType:  string(t.TargetType),
+		WidthMM:     t.WidthMM,
+		HeightMM:    t.HeightMM,
+		BarcodeType: string(t.BarcodeType),
+		Fields:      t.FieldList(),
+		FontSizePt:  t.FontSizePt,
+		Copies:      t.Copies,
+		IsDefault:   t.IsDefault,
+		IsActive:    t.IsActive,
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   t.UpdatedAt,
+	}
+}
@@ -55,5 +55,6 @@ func (s *MedicationService) GetMedicationByID(id uint) (*dto.MedicationResponse,
 		Strength:     medication.Strength,
 		Unit:         medication.Unit,
 		Manufacturer: medication.Manufacturer,
+		UnitPrice:    medication.UnitPrice,
 	}, nil
 }
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/minhtran/his/internal/domain"
+	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/pkg/clock"
+	"github.com/minhtran/his/internal/pkg/logger"
+	"github.com/minhtran/his/internal/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrPatientBookingRestricted is returned when a patient with repeated no-shows tries to book
+	ErrPatientBookingRestricted = errors.New("booking is restricted after repeated no-shows, please contact the clinic")
+)
+
+// noShowBatchSize limits how many overdue appointments one sweep marks
+const noShowBatchSize = 500
+
+// NoShowPolicy configures automatic no-shows, booking restrictions for repeat
+// no-shows and overbooking from historical no-show rates
+type NoShowPolicy struct {
+	GracePeriod          time.Duration // How long after its start an unattended appointment becomes a no-show
+	RestrictThreshold    int           // No-shows within the window that restrict booking; 0 disables
+	RestrictWindowDays   int
+	RestrictPortalOnly   bool // Restrict only patient self-service (portal and online) bookings
+	OverbookLookbackDays int
+	OverbookMinSample    int // Past appointments a doctor needs before overbooking is allowed
+	OverbookMaxPercent   int // Cap on extra bookings as a percentage of a session's limit; 0 disables
+	OverbookMaxPerSlot   int // Bookings one time slot may hold while a session is overbooked
+}
+
+// NoShowService marks missed appointments and applies the no-show policy
+type NoShowService struct {
+	appointmentRepo *repository.AppointmentRepository
+	patientRepo     *repository.PatientRepository
+	userRepo        *repository.UserRepository
+	policy          NoShowPolicy
+	clock           *clock.Clock
+}
+
+// NewNoShowService creates a new no-show service
+func NewNoShowService(
+	appointmentRepo *repository.AppointmentRepository,
+	patientRepo *repository.PatientRepository,
+	userRepo *repository.UserRepository,
+	policy NoShowPolicy,
+	clk *clock.Clock,
+) *NoShowService {
+	return &NoShowService{
+		appointmentRepo: appointmentRepo,
+		patientRepo:     patientRepo,
+		userRepo:        userRepo,
+		policy:          policy,
+		clock:           clk,
+	}
+}
+
+// MarkOverdue marks scheduled and confirmed appointments that started more
+// than the grace period ago as no-shows and returns how many were marked
+func (s *NoShowService) MarkOverdue(now time.Time) (int, error) {
+	appointments, err := s.appointmentRepo.FindOverdue(now.Add(-s.policy.GracePeriod), noShowBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find overdue appointments: %w", err)
+	}
+
+	reason := fmt.Sprintf("Not checked in within %s of the appointment time", s.policy.GracePeriod)
+	marked := 0
+	for _, apt := range appointments {
+		from := apt.Status
+		if !from.CanTransitionTo(domain.AppointmentStatusNoShow) {
+			continue
+		}
+		history := &domain.AppointmentStatusHistory{
+			AppointmentID: apt.ID,
+			FromStatus:    from,
+			ToStatus:      domain.AppointmentStatusNoShow,
+			Reason:        reason,
+			ChangedAt:     now,
+		}
+		ok, err := s.appointmentRepo.MarkNoShow(apt, history)
+		if err != nil {
+			return marked, fmt.Errorf("failed to mark appointment %s as no-show: %w", apt.AppointmentCode, err)
+		}
+		if ok {
+			marked++
+		}
+	}
+	return marked, nil
+}
+
+// Run marks overdue appointments as no-shows every interval until ctx is cancelled
+func (s *NoShowService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if n, err := s.MarkOverdue(s.clock.Now()); err != nil {
+				logger.Error("Failed to mark overdue appointments as no-shows", zap.Error(err))
+			} else if n > 0 {
+				logger.Info("Overdue appointments marked as no-shows", zap.Int("count", n))
+			}
+		}
+	}
+}
+
+// GetPatientStats gets a patient's attendance record and whether the
+// no-show policy restricts their bookings
+func (s *NoShowService) GetPatientStats(patientID uint) (*dto.PatientNoShowStats, error) {
+	patient, err := s.patientRepo.FindByID(patientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find patient: %w", err)
+	}
+	if patient == nil {
+		return nil, ErrPatientNotFound
+	}
+
+	now := s.clock.Now()
+	recent, err := s.appointmentRepo.CountPatientOutcomes(patientID, now.AddDate(0, 0, -s.policy.RestrictWindowDays), now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count appointments: %w", err)
+	}
+	lifetime, err := s.appointmentRepo.CountPatientOutcomes(patientID, time.Time{}, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count appointments: %w", err)
+	}
+	latest, err := s.appointmentRepo.FindLatestNoShow(patientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find latest no-show: %w", err)
+	}
+
+	attended, noShows := attendance(recent)
+	stats := &dto.PatientNoShowStats{
+		PatientID:            patientID,
+		WindowDays:           s.policy.RestrictWindowDays,
+		Attended:             attended,
+		NoShows:              noShows,
+		Cancelled:            recent[domain.AppointmentStatusCancelled],
+		NoShowRate:           noShowRate(attended, noShows),
+		LifetimeNoShows:      lifetime[domain.AppointmentStatusNoShow],
+		RestrictionThreshold: s.policy.RestrictThreshold,
+		BookingRestricted:    s.policy.RestrictThreshold > 0 && noShows >= int64(s.policy.RestrictThreshold),
+		PortalOnly:           s.policy.RestrictPortalOnly,
+	}
+	if latest != nil {
+		date := latest.AppointmentDate.Format("2006-01-02")
+		stats.LastNoShowDate = &date
+	}
+	return stats, nil
+}
+
+// CheckBookingAllowed checks that the no-show policy lets a patient book
+// through the given channel
+func (s *NoShowService) CheckBookingAllowed(patientID uint, source domain.BookingSource) error {
+	if s.policy.RestrictThreshold <= 0 {
+		return nil
+	}
+	if s.policy.RestrictPortalOnly && source == domain.BookingSourceStaff {
+		return nil
+	}
+
+	now := s.clock.Now()
+	counts, err := s.appointmentRepo.CountPatientOutcomes(patientID, now.AddDate(0, 0, -s.policy.RestrictWindowDays), now)
+	if err != nil {
+		return fmt.Errorf("failed to count no-shows: %w", err)
+	}
+	if counts[domain.AppointmentStatusNoShow] >= int64(s.policy.RestrictThreshold) {
+		return ErrPatientBookingRestricted
+	}
+	return nil
+}
+
+// GetDoctorOverbooking gets a doctor's historical no-show rate and the share
+// of each clinic session's limit that may be overbooked
+func (s *NoShowService) GetDoctorOverbooking(doctorID uint) (*dto.DoctorOverbookingResponse, error) {
+	doctor, err := s.userRepo.FindByID(doctorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find doctor: %w", err)
+	}
+	if doctor == nil {
+		return nil, ErrDoctorNotFound
+	}
+
+	attended, noShows, rate, err := s.doctorNoShowRate(doctorID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.DoctorOverbookingResponse{
+		DoctorID:        doctorID,
+		DoctorName:      doctor.FullName,
+		LookbackDays:    s.policy.OverbookLookbackDays,
+		Attended:        attended,
+		NoShows:         noShows,
+		NoShowRate:      noShowRate(attended, noShows),
+		MinSample:       s.policy.OverbookMinSample,
+		MaxPercent:      s.policy.OverbookMaxPercent,
+		OverbookPercent: rate * 100,
+		MaxPerSlot:      max(s.policy.OverbookMaxPerSlot, 1),
+	}, nil
+}
+
+// OverbookAllowance returns how many bookings a doctor's clinic session may
+// take beyond its patient limit: the limit scaled by the doctor's no-show
+// rate, capped by the policy and rounded down
+func (s *NoShowService) OverbookAllowance(doctorID uint, maxPatients int) (int, error) {
+	if maxPatients <= 0 || s.policy.OverbookMaxPercent <= 0 {
+		return 0, nil
+	}
+	rate, err := s.OverbookRate(doctorID)
+	if err != nil {
+		return 0, err
+	}
+	return overbookAllowance(maxPatients, rate), nil
+}
+
+// SlotCapacity returns how many bookings one time slot of a clinic session may
+// hold given the session's overbooking allowance: one without an allowance,
+// otherwise up to the policy's per-slot cap and one more than the allowance
+func (s *NoShowService) SlotCapacity(allowance int) int {
+	if allowance <= 0 || s.policy.OverbookMaxPerSlot <= 1 {
+		return 1
+	}
+	return min(s.policy.OverbookMaxPerSlot, allowance+1)
+}
+
+// OverbookRate returns the share of a clinic session's limit a doctor's
+// sessions may be overbooked by, for callers working out many sessions
+func (s *NoShowService) OverbookRate(doctorID uint) (float64, error) {
+	if s.policy.OverbookMaxPercent <= 0 {
+		return 0, nil
+	}
+	_, _, rate, err := s.doctorNoShowRate(doctorID)
+	return rate, err
+}
+
+// doctorNoShowRate counts a doctor's past attended and missed appointments
+// over the lookback period and returns the overbooking rate they allow, which
+// is zero until the doctor has enough history
+func (s *NoShowService) doctorNoShowRate(doctorID uint) (int64, int64, float64, error) {
+	// Today's appointments are still in play
+	to := s.clock.Today().AddDate(0, 0, -1)
+	counts, err := s.appointmentRepo.CountDoctorOutcomes(doctorID, to.AddDate(0, 0, -s.policy.OverbookLookbackDays), to)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("failed to count appointments: %w", err)
+	}
+
+	attended, noShows := attendance(counts)
+	if s.policy.OverbookMaxPercent <= 0 || attended+noShows < int64(s.policy.OverbookMinSample) {
+		return attended, noShows, 0, nil
+	}
+
+	rate := noShowRate(attended, noShows)
+	if limit := float64(s.policy.OverbookMaxPercent) / 100; rate > limit {
+		rate = limit
+	}
+	return attended, noShows, rate, nil
+}
+
+// overbookAllowance scales a session's patient limit by an overbooking rate, rounding down
+func overbookAllowance(maxPatients int, rate float64) int {
+	return int(float64(maxPatients) * rate)
+}
+
+// attendance sums the appointments a patient turned up to and those they missed
+func attendance(counts map[domain.AppointmentStatus]int64) (int64, int64) {
+	attended := counts[domain.AppointmentStatusCompleted] + counts[domain.AppointmentStatusInProgress]
+	return attended, counts[domain.AppointmentStatusNoShow]
+}
+
+// noShowRate returns the share of kept-or-missed appointments that were missed
+func noShowRate(attended, noShows int64) float64 {
+	if attended+noShows == 0 {
+		return 0
+	}
+	return float64(noShows) / float64(attended+noShows)
+}
@@ -0,0 +1,585 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/minhtran/his/internal/domain"
+	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/pkg/clock"
+	"github.com/minhtran/his/internal/pkg/logger"
+	"github.com/minhtran/his/internal/pkg/notify"
+	"github.com/minhtran/his/internal/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrNotificationNotFound is returned when the notification is not found
+	ErrNotificationNotFound = errors.New("notification not found")
+	// ErrNotificationNotFailed is returned when retrying a notification that has not failed
+	ErrNotificationNotFailed = errors.New("only failed notifications can be retried")
+	// ErrNotificationTemplateNotFound is returned when the notification template is not found
+	ErrNotificationTemplateNotFound = errors.New("notification template not found")
+	// ErrNotificationTemplateExists is returned when a template already exists for the code, channel and language
+	ErrNotificationTemplateExists = errors.New("a template already exists for this code, channel and language")
+	// ErrInvalidNotificationTemplate is returned when a template subject or body does not parse
+	ErrInvalidNotificationTemplate = errors.New("invalid notification template")
+)
+
+const (
+	notificationDefaultLanguage = "vi"
+	notificationDispatchBatch   = 100
+	notificationMaxBackoff      = time.Hour
+)
+
+// NotificationService renders notification templates, queues notifications and
+// delivers them through the channel providers with retries. It sends
+// appointment confirmations on booking and reminders ahead of appointments,
+// and alerts attending doctors when an inpatient's early warning score escalates.
+type NotificationService struct {
+	notificationRepo *repository.NotificationRepository
+	appointmentRepo  *repository.AppointmentRepository
+	auditRepo        *repository.AuditLogRepository
+	dispatcher       *notify.Dispatcher
+	facilityName     string
+	reminderOffsets  []time.Duration
+	maxAttempts      int
+	clock            *clock.Clock
+}
+
+// NewNotificationService creates a new notification service and subscribes it
+// to bookings made through the appointment service and to early warning
+// escalations from the admission service
+func NewNotificationService(
+	notificationRepo *repository.NotificationRepository,
+	appointmentRepo *repository.AppointmentRepository,
+	auditRepo *repository.AuditLogRepository,
+	appointmentService *AppointmentService,
+	admissionService *AdmissionService,
+	dispatcher *notify.Dispatcher,
+	facilityName string,
+	reminderOffsets []time.Duration,
+	maxAttempts int,
+	clk *clock.Clock,
+) *NotificationService {
+	s := &NotificationService{
+		notificationRepo: notificationRepo,
+		appointmentRepo:  appointmentRepo,
+		auditRepo:        auditRepo,
+		dispatcher:       dispatcher,
+		facilityName:     facilityName,
+		reminderOffsets:  reminderOffsets,
+		maxAttempts:      maxAttempts,
+		clock:            clk,
+	}
+	appointmentService.OnBooked(s.handleBooked)
+	admissionService.OnEscalated(s.handleEscalated)
+	return s
+}
+
+// NotifyAppointment queues a notification about an appointment on every
+// channel that has a provider and a recipient address for the patient. An
+// optional key suffix distinguishes repeated notifications of one template,
+// such as reminders at different lead times; a notification already queued
+// under the same key is not queued again. It returns the number queued.
+func (s *NotificationService) NotifyAppointment(apt *domain.Appointment, templateCode, keySuffix string) (int, error) {
+	if apt.Patient == nil {
+		return 0, nil
+	}
+
+	language := apt.Patient.PreferredLanguage
+	if language == "" {
+		language = notificationDefaultLanguage
+	}
+
+	data := map[string]string{
+		"PatientName":     apt.Patient.FullName,
+		"AppointmentCode": apt.AppointmentCode,
+		"AppointmentDate": apt.AppointmentDate.Format("02/01/2006"),
+		"AppointmentTime": apt.AppointmentTime.Format("15:04"),
+		"FacilityName":    s.facilityName,
+	}
+	if apt.Doctor != nil {
+		data["DoctorName"] = apt.Doctor.FullName
+	}
+
+	queued := 0
+	for _, channel := range s.dispatcher.Channels() {
+		recipient := apt.Patient.PhoneNumber
+		if channel == notify.ChannelEmail {
+			recipient = apt.Patient.Email
+		}
+		if recipient == "" {
+			continue
+		}
+
+		key := templateCode + ":" + strconv.FormatUint(uint64(apt.ID), 10)
+		if keySuffix != "" {
+			key += ":" + keySuffix
+		}
+		key += ":" + string(channel)
+
+		exists, err := s.notificationRepo.ExistsByDedupeKey(key)
+		if err != nil {
+			return queued, fmt.Errorf("failed to check notification: %w", err)
+		}
+		if exists {
+			continue
+		}
+
+		notification, err := s.render(templateCode, domain.NotificationChannel(channel), language, recipient, data)
+		if err != nil {
+			return queued, err
+		}
+		if notification == nil {
+			continue
+		}
+		notification.PatientID = &apt.PatientID
+		notification.AppointmentID = &apt.ID
+		notification.DedupeKey = &key
+
+		if err := s.notificationRepo.Create(notification); err != nil {
+			return queued, fmt.Errorf("failed to queue notification: %w", err)
+		}
+		queued++
+	}
+
+	return queued, nil
+}
+
+// NotifyEarlyWarning queues an alert about an escalated early warning score
+// to the admission's attending doctor on every channel that has a provider
+// and a recipient address for the doctor. It returns the number queued.
+func (s *NotificationService) NotifyEarlyWarning(score *domain.EarlyWarningScore) (int, error) {
+	admission := score.Admission
+	if admission == nil || admission.Doctor == nil {
+		return 0, nil
+	}
+
+	data := map[string]string{
+		"DoctorName":      admission.Doctor.FullName,
+		"AdmissionCode":   admission.AdmissionCode,
+		"Score":           strconv.Itoa(score.Total),
+		"Risk":            string(score.Risk),
+		"EscalationLevel": string(score.EscalationLevel),
+		"RecordedAt":      score.RecordedAt.Format("15:04 02/01/2006"),
+		"FacilityName":    s.facilityName,
+	}
+	if admission.Patient != nil {
+		data["PatientName"] = admission.Patient.FullName
+	}
+	for _, alloc := range admission.BedAllocations {
+		if alloc.IsCurrent && alloc.Bed != nil {
+			data["Bed"] = alloc.Bed.Ward + " " + alloc.Bed.BedNumber
+			break
+		}
+	}
+
+	queued := 0
+	for _, channel := range s.dispatcher.Channels() {
+		recipient := admission.Doctor.PhoneNumber
+		if channel == notify.ChannelEmail {
+			recipient = admission.Doctor.Email
+		}
+		if recipient == "" {
+			continue
+		}
+
+		key := domain.NotificationTemplateEarlyWarningEscalation + ":" + strconv.FormatUint(uint64(score.ID), 10) + ":" + string(channel)
+		notification, err := s.render(domain.NotificationTemplateEarlyWarningEscalation, domain.NotificationChannel(channel), notificationDefaultLanguage, recipient, data)
+		if err != nil {
+			return queued, err
+		}
+		if notification == nil {
+			continue
+		}
+		notification.PatientID = &admission.PatientID
+		notification.DedupeKey = &key
+
+		if err := s.notificationRepo.Create(notification); err != nil {
+			return queued, fmt.Errorf("failed to queue notification: %w", err)
+		}
+		queued++
+	}
+
+	return queued, nil
+}
+
+// QueueReminders queues reminders for appointments starting within each
+// reminder offset from now. Appointments booked after their reminder time
+// are skipped for that offset. It returns the number of notifications queued.
+func (s *NotificationService) QueueReminders(now time.Time) (int, error) {
+	queued := 0
+	for _, offset := range s.reminderOffsets {
+		appointments, err := s.appointmentRepo.FindStartingBetween(now, now.Add(offset))
+		if err != nil {
+			return queued, fmt.Errorf("failed to find upcoming appointments: %w", err)
+		}
+
+		for _, apt := range appointments {
+			start := time.Date(apt.AppointmentDate.Year(), apt.AppointmentDate.Month(), apt.AppointmentDate.Day(),
+				apt.AppointmentTime.Hour(), apt.AppointmentTime.Minute(), 0, 0, now.Location())
+			if apt.CreatedAt.After(start.Add(-offset)) {
+				continue
+			}
+
+			n, err := s.NotifyAppointment(apt, domain.NotificationTemplateAppointmentReminder, offset.String())
+			if err != nil {
+				return queued, err
+			}
+			queued += n
+		}
+	}
+	return queued, nil
+}
+
+// DispatchPending delivers the notifications that are due. A failed delivery
+// is retried with exponential backoff until its attempts are used up. It
+// returns the number sent and the number that failed permanently.
+func (s *NotificationService) DispatchPending(ctx context.Context) (int, int, error) {
+	now := s.clock.Now()
+	notifications, err := s.notificationRepo.FindDue(now, notificationDispatchBatch)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to find due notifications: %w", err)
+	}
+
+	sent, failed := 0, 0
+	for _, n := range notifications {
+		msg := &notify.Message{
+			Channel:   notify.Channel(n.Channel),
+			Recipient: n.Recipient,
+			Subject:   n.Subject,
+			Body:      n.Body,
+		}
+		if n.Data != "" {
+			_ = json.Unmarshal([]byte(n.Data), &msg.Data)
+		}
+
+		ref, sendErr := s.dispatcher.Send(ctx, msg)
+		n.Attempts++
+		if sendErr == nil {
+			sentAt := s.clock.Now()
+			n.Status = domain.NotificationStatusSent
+			n.SentAt = &sentAt
+			n.ProviderRef = ref
+			n.LastError = ""
+			sent++
+		} else {
+			n.LastError = sendErr.Error()
+			if n.Attempts >= n.MaxAttempts {
+				n.Status = domain.NotificationStatusFailed
+				failed++
+			} else {
+				n.NextAttemptAt = now.Add(retryBackoff(n.Attempts))
+			}
+		}
+
+		if err := s.notificationRepo.Update(n); err != nil {
+			return sent, failed, fmt.Errorf("failed to update notification: %w", err)
+		}
+	}
+
+	return sent, failed, nil
+}
+
+// Run queues reminders and delivers due notifications every interval until
+// the context is cancelled
+func (s *NotificationService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.QueueReminders(s.clock.Now()); err != nil {
+				logger.Error("Failed to queue appointment reminders", zap.Error(err))
+			}
+			sent, failed, err := s.DispatchPending(ctx)
+			if err != nil {
+				logger.Error("Failed to dispatch notifications", zap.Error(err))
+			}
+			if sent > 0 || failed > 0 {
+				logger.Info("Notifications dispatched", zap.Int("sent", sent), zap.Int("failed", failed))
+			}
+		}
+	}
+}
+
+// ListNotifications lists notifications, newest first
+func (s *NotificationService) ListNotifications(filters map[string]interface{}, page, pageSize int) ([]*dto.NotificationResponse, int64, error) {
+	notifications, total, err := s.notificationRepo.List(filters, page, pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list notifications: %w", err)
+	}
+
+	items := make([]*dto.NotificationResponse, len(notifications))
+	for i, n := range notifications {
+		items[i] = s.toNotificationResponse(n)
+	}
+	return items, total, nil
+}
+
+// GetNotification gets a notification by ID
+func (s *NotificationService) GetNotification(id uint) (*dto.NotificationResponse, error) {
+	n, err := s.notificationRepo.FindByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find notification: %w", err)
+	}
+	if n == nil {
+		return nil, ErrNotificationNotFound
+	}
+	return s.toNotificationResponse(n), nil
+}
+
+// RetryNotification queues a failed notification for another round of attempts
+func (s *NotificationService) RetryNotification(id uint) (*dto.NotificationResponse, error) {
+	n, err := s.notificationRepo.FindByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find notification: %w", err)
+	}
+	if n == nil {
+		return nil, ErrNotificationNotFound
+	}
+	if n.Status != domain.NotificationStatusFailed {
+		return nil, ErrNotificationNotFailed
+	}
+
+	n.Status = domain.NotificationStatusPending
+	n.MaxAttempts = n.Attempts + s.maxAttempts
+	n.NextAttemptAt = s.clock.Now()
+	if err := s.notificationRepo.Update(n); err != nil {
+		return nil, fmt.Errorf("failed to update notification: %w", err)
+	}
+	return s.toNotificationResponse(n), nil
+}
+
+// ListTemplates lists notification templates, optionally of one code
+func (s *NotificationService) ListTemplates(code string) ([]*dto.NotificationTemplateResponse, error) {
+	templates, err := s.notificationRepo.ListTemplates(code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notification templates: %w", err)
+	}
+
+	items := make([]*dto.NotificationTemplateResponse, len(templates))
+	for i, t := range templates {
+		items[i] = s.toTemplateResponse(t)
+	}
+	return items, nil
+}
+
+// CreateTemplate adds a template for a code, channel and language
+func (s *NotificationService) CreateTemplate(req *dto.CreateNotificationTemplateRequest, userID uint) (*dto.NotificationTemplateResponse, error) {
+	existing, err := s.notificationRepo.FindTemplate(req.Code, domain.NotificationChannel(req.Channel), req.Language)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find notification template: %w", err)
+	}
+	if existing != nil {
+		return nil, ErrNotificationTemplateExists
+	}
+	if err := validateTemplate(req.Subject, req.Body); err != nil {
+		return nil, err
+	}
+
+	t := &domain.NotificationTemplate{
+		Code:      req.Code,
+		Channel:   domain.NotificationChannel(req.Channel),
+		Language:  req.Language,
+		Subject:   req.Subject,
+		Body:      req.Body,
+		IsActive:  true,
+		CreatedBy: userID,
+		UpdatedBy: userID,
+	}
+	if err := s.notificationRepo.CreateTemplate(t); err != nil {
+		return nil, fmt.Errorf("failed to create notification template: %w", err)
+	}
+
+	s.auditRepo.Create(&domain.AuditLog{
+		UserID:     &userID,
+		Action:     domain.AuditActionCreate,
+		Resource:   "NotificationTemplate",
+		ResourceID: strconv.FormatUint(uint64(t.ID), 10),
+		Details:    domain.AuditDetails{"code": t.Code, "channel": t.Channel, "language": t.Language},
+	})
+
+	return s.toTemplateResponse(t), nil
+}
+
+// UpdateTemplate updates a template's text or switches it on or off
+func (s *NotificationService) UpdateTemplate(id uint, req *dto.UpdateNotificationTemplateRequest, userID uint) (*dto.NotificationTemplateResponse, error) {
+	t, err := s.notificationRepo.FindTemplateByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find notification template: %w", err)
+	}
+	if t == nil {
+		return nil, ErrNotificationTemplateNotFound
+	}
+
+	if req.Subject != nil {
+		t.Subject = *req.Subject
+	}
+	if req.Body != "" {
+		t.Body = req.Body
+	}
+	if req.IsActive != nil {
+		t.IsActive = *req.IsActive
+	}
+	if err := validateTemplate(t.Subject, t.Body); err != nil {
+		return nil, err
+	}
+	t.UpdatedBy = userID
+
+	if err := s.notificationRepo.UpdateTemplate(t); err != nil {
+		return nil, fmt.Errorf("failed to update notification template: %w", err)
+	}
+
+	s.auditRepo.Create(&domain.AuditLog{
+		UserID:     &userID,
+		Action:     domain.AuditActionUpdate,
+		Resource:   "NotificationTemplate",
+		ResourceID: strconv.FormatUint(uint64(t.ID), 10),
+		Details:    domain.AuditDetails{"code": t.Code, "channel": t.Channel, "language": t.Language, "is_active": t.IsActive},
+	})
+
+	return s.toTemplateResponse(t), nil
+}
+
+// handleBooked queues a confirmation for a newly scheduled appointment
+func (s *NotificationService) handleBooked(booked domain.Appointment) {
+	if _, err := s.NotifyAppointment(&booked, domain.NotificationTemplateAppointmentConfirmation, ""); err != nil {
+		logger.Error("Failed to queue appointment confirmation", zap.Uint("appointment_id", booked.ID), zap.Error(err))
+	}
+}
+
+// handleEscalated alerts the attending doctor to a deteriorating inpatient
+func (s *NotificationService) handleEscalated(score domain.EarlyWarningScore) {
+	if _, err := s.NotifyEarlyWarning(&score); err != nil {
+		logger.Error("Failed to queue early warning alert", zap.Uint("score_id", score.ID), zap.Error(err))
+	}
+}
+
+// render builds a pending notification from the template for the channel and
+// language, falling back to the default language. It returns nil when no
+// active template exists.
+func (s *NotificationService) render(code string, channel domain.NotificationChannel, language, recipient string, data map[string]string) (*domain.Notification, error) {
+	t, err := s.notificationRepo.FindTemplate(code, channel, language)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find notification template: %w", err)
+	}
+	if t == nil && language != notificationDefaultLanguage {
+		language = notificationDefaultLanguage
+		if t, err = s.notificationRepo.FindTemplate(code, channel, language); err != nil {
+			return nil, fmt.Errorf("failed to find notification template: %w", err)
+		}
+	}
+	if t == nil || !t.IsActive {
+		return nil, nil
+	}
+
+	subject, err := executeTemplate(t.Subject, data)
+	if err != nil {
+		return nil, err
+	}
+	body, err := executeTemplate(t.Body, data)
+	if err != nil {
+		return nil, err
+	}
+	encoded, _ := json.Marshal(data)
+
+	return &domain.Notification{
+		Channel:       channel,
+		Recipient:     recipient,
+		TemplateCode:  code,
+		Language:      language,
+		Subject:       subject,
+		Body:          body,
+		Data:          string(encoded),
+		Status:        domain.NotificationStatusPending,
+		MaxAttempts:   s.maxAttempts,
+		NextAttemptAt: s.clock.Now(),
+	}, nil
+}
+
+// executeTemplate renders a text/template; unknown variables render empty
+func executeTemplate(text string, data map[string]string) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+	t, err := template.New("notification").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidNotificationTemplate, err)
+	}
+
+	var b strings.Builder
+	if err := t.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidNotificationTemplate, err)
+	}
+	return b.String(), nil
+}
+
+// validateTemplate checks that a template subject and body parse
+func validateTemplate(subject, body string) error {
+	for _, text := range []string{subject, body} {
+		if _, err := template.New("notification").Parse(text); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidNotificationTemplate, err)
+		}
+	}
+	return nil
+}
+
+// retryBackoff returns the delay before the next delivery attempt: one
+// minute, doubling with each attempt, capped at an hour
+func retryBackoff(attempts int) time.Duration {
+	backoff := time.Minute
+	for i := 1; i < attempts && backoff < notificationMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > notificationMaxBackoff {
+		backoff = notificationMaxBackoff
+	}
+	return backoff
+}
+
+// Helper functions
+func (s *NotificationService) toNotificationResponse(n *domain.Notification) *dto.NotificationResponse {
+	return &dto.NotificationResponse{
+		ID:            n.ID,
+		Channel:       string(n.Channel),
+		Recipient:     n.Recipient,
+		TemplateCode:  n.TemplateCode,
+		Language:      n.Language,
+		Subject:       n.Subject,
+		Body:          n.Body,
+		PatientID:     n.PatientID,
+		AppointmentID: n.AppointmentID,
+		Status:        string(n.Status),
+		Attempts:      n.Attempts,
+		MaxAttempts:   n.MaxAttempts,
+		NextAttemptAt: n.NextAttemptAt,
+		LastError:     n.LastError,
+		ProviderRef:   n.ProviderRef,
+		SentAt:        n.SentAt,
+		CreatedAt:     n.CreatedAt,
+	}
+}
+
+func (s *NotificationService) toTemplateResponse(t *domain.NotificationTemplate) *dto.NotificationTemplateResponse {
+	return &dto.NotificationTemplateResponse{
+		ID:        t.ID,
+		Code:      t.Code,
+		Channel:   string(t.Channel),
+		Language:  t.Language,
+		Subject:   t.Subject,
+		Body:      t.Body,
+		IsActive:  t.IsActive,
+		UpdatedAt: t.UpdatedAt,
+	}
+}
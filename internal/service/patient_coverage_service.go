@@ -0,0 +1,300 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/minhtran/his/internal/domain"
+	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/pkg/clock"
+	"github.com/minhtran/his/internal/repository"
+)
+
+var (
+	ErrCoverageNotFound       = errors.New("coverage not found")
+	ErrCoverageNotValid       = errors.New("coverage is not valid on the service date")
+	ErrCoverageOverlap        = errors.New("patient already has an active coverage with this priority in the period")
+	ErrInvalidCoveragePeriod  = errors.New("coverage valid_to must not be before valid_from")
+	ErrInsurancePayerInactive = errors.New("insurance payer is inactive")
+	ErrNoActiveCoverage       = errors.New("patient has no active insurance coverage")
+)
+
+// PatientCoverageService handles patient insurance coverage business logic
+type PatientCoverageService struct {
+	coverageRepo *repository.PatientCoverageRepository
+	patientRepo  *repository.PatientRepository
+	payerRepo    *repository.InsurancePayerRepository
+	clock        *clock.Clock
+}
+
+// NewPatientCoverageService creates a new patient coverage service
+func NewPatientCoverageService(
+	coverageRepo *repository.PatientCoverageRepository,
+	patientRepo *repository.PatientRepository,
+	payerRepo *repository.InsurancePayerRepository,
+	clk *clock.Clock,
+) *PatientCoverageService {
+	return &PatientCoverageService{
+		coverageRepo: coverageRepo,
+		patientRepo:  patientRepo,
+		payerRepo:    payerRepo,
+		clock:        clk,
+	}
+}
+
+// AddCoverage adds an insurance coverage to a patient
+func (s *PatientCoverageService) AddCoverage(patientID uint, req *dto.CreateCoverageRequest, createdBy uint) (*dto.CoverageResponse, error) {
+	patient, err := s.patientRepo.FindByID(patientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find patient: %w", err)
+	}
+	if patient == nil {
+		return nil, ErrPatientNotFound
+	}
+
+	payer, err := s.payerRepo.FindByID(req.PayerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find payer: %w", err)
+	}
+	if payer == nil {
+		return nil, ErrInsurancePayerNotFound
+	}
+	if !payer.IsActive {
+		return nil, ErrInsurancePayerInactive
+	}
+
+	validFrom, validTo, err := parseCoveragePeriod(req.ValidFrom, req.ValidTo)
+	if err != nil {
+		return nil, err
+	}
+
+	priority := domain.CoveragePriority(req.Priority)
+	overlap, err := s.coverageRepo.HasOverlappingPriority(patientID, priority, validFrom, validTo, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check coverage overlap: %w", err)
+	}
+	if overlap {
+		return nil, ErrCoverageOverlap
+	}
+
+	coverage := &domain.PatientCoverage{
+		PatientID:           patientID,
+		PayerID:             payer.ID,
+		PolicyNumber:        req.PolicyNumber,
+		ValidFrom:           validFrom,
+		ValidTo:             validTo,
+		CoveragePercent:     req.CoveragePercent,
+		PrimaryCareFacility: req.PrimaryCareFacility,
+		Priority:            priority,
+		IsActive:            true,
+		Notes:               req.Notes,
+		CreatedBy:           createdBy,
+	}
+
+	if err := s.coverageRepo.Create(coverage); err != nil {
+		return nil, fmt.Errorf("failed to create coverage: %w", err)
+	}
+
+	coverage.Payer = payer
+	return s.toCoverageResponse(coverage), nil
+}
+
+// UpdateCoverage updates a coverage record
+func (s *PatientCoverageService) UpdateCoverage(id uint, req *dto.UpdateCoverageRequest, updatedBy uint) (*dto.CoverageResponse, error) {
+	coverage, err := s.coverageRepo.FindByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find coverage: %w", err)
+	}
+	if coverage == nil {
+		return nil, ErrCoverageNotFound
+	}
+
+	if req.PolicyNumber != "" {
+		coverage.PolicyNumber = req.PolicyNumber
+	}
+	if req.ValidFrom != "" || req.ValidTo != "" {
+		from := coverage.ValidFrom.Format("2006-01-02")
+		if req.ValidFrom != "" {
+			from = req.ValidFrom
+		}
+		to := req.ValidTo
+		if to == "" && coverage.ValidTo != nil {
+			to = coverage.ValidTo.Format("2006-01-02")
+		}
+		validFrom, validTo, err := parseCoveragePeriod(from, to)
+		if err != nil {
+			return nil, err
+		}
+		coverage.ValidFrom = validFrom
+		coverage.ValidTo = validTo
+	}
+	if req.CoveragePercent != nil {
+		coverage.CoveragePercent = *req.CoveragePercent
+	}
+	if req.PrimaryCareFacility != "" {
+		coverage.PrimaryCareFacility = req.PrimaryCareFacility
+	}
+	if req.Priority != "" {
+		coverage.Priority = domain.CoveragePriority(req.Priority)
+	}
+	if req.Notes != "" {
+		coverage.Notes = req.Notes
+	}
+	if req.IsActive != nil {
+		coverage.IsActive = *req.IsActive
+	}
+
+	if coverage.IsActive {
+		overlap, err := s.coverageRepo.HasOverlappingPriority(coverage.PatientID, coverage.Priority, coverage.ValidFrom, coverage.ValidTo, &coverage.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check coverage overlap: %w", err)
+		}
+		if overlap {
+			return nil, ErrCoverageOverlap
+		}
+	}
+
+	coverage.UpdatedBy = updatedBy
+
+	if err := s.coverageRepo.Update(coverage); err != nil {
+		return nil, fmt.Errorf("failed to update coverage: %w", err)
+	}
+
+	return s.toCoverageResponse(coverage), nil
+}
+
+// GetPatientCoverages gets all coverages for a patient
+func (s *PatientCoverageService) GetPatientCoverages(patientID uint) ([]*dto.CoverageResponse, error) {
+	coverages, err := s.coverageRepo.FindByPatientID(patientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get coverages: %w", err)
+	}
+
+	items := make([]*dto.CoverageResponse, len(coverages))
+	for i, c := range coverages {
+		items[i] = s.toCoverageResponse(c)
+	}
+	return items, nil
+}
+
+// GetActiveCoverages gets coverages valid today for a patient, primary first
+func (s *PatientCoverageService) GetActiveCoverages(patientID uint) ([]*dto.CoverageResponse, error) {
+	coverages, err := s.coverageRepo.FindActiveByPatientID(patientID, s.clock.Today())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active coverages: %w", err)
+	}
+
+	items := make([]*dto.CoverageResponse, len(coverages))
+	for i, c := range coverages {
+		items[i] = s.toCoverageResponse(c)
+	}
+	return items, nil
+}
+
+// GetCoverageByID gets coverage details by ID
+func (s *PatientCoverageService) GetCoverageByID(id uint) (*dto.CoverageResponse, error) {
+	coverage, err := s.coverageRepo.FindByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find coverage: %w", err)
+	}
+	if coverage == nil {
+		return nil, ErrCoverageNotFound
+	}
+
+	return s.toCoverageResponse(coverage), nil
+}
+
+// DeleteCoverage soft deletes a coverage
+func (s *PatientCoverageService) DeleteCoverage(id uint) error {
+	coverage, err := s.coverageRepo.FindByID(id)
+	if err != nil {
+		return fmt.Errorf("failed to find coverage: %w", err)
+	}
+	if coverage == nil {
+		return ErrCoverageNotFound
+	}
+
+	if err := s.coverageRepo.Delete(id); err != nil {
+		return fmt.Errorf("failed to delete coverage: %w", err)
+	}
+
+	return nil
+}
+
+// resolveCoverage returns the coverage to bill for a patient on a date.
+// An explicit coverage must belong to the patient and be valid on that date;
+// otherwise the highest-priority valid coverage is selected (nil if none).
+func resolveCoverage(coverageRepo *repository.PatientCoverageRepository, patientID uint, date time.Time, coverageID *uint) (*domain.PatientCoverage, error) {
+	if coverageID != nil {
+		coverage, err := coverageRepo.FindByID(*coverageID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find coverage: %w", err)
+		}
+		if coverage == nil || coverage.PatientID != patientID {
+			return nil, ErrCoverageNotFound
+		}
+		if !coverage.IsValidOn(date) || coverage.Payer == nil || !coverage.Payer.IsActive {
+			return nil, ErrCoverageNotValid
+		}
+		return coverage, nil
+	}
+
+	coverages, err := coverageRepo.FindActiveByPatientID(patientID, date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find active coverages: %w", err)
+	}
+	if len(coverages) == 0 {
+		return nil, nil
+	}
+	return coverages[0], nil
+}
+
+func parseCoveragePeriod(from, to string) (time.Time, *time.Time, error) {
+	validFrom, err := time.Parse("2006-01-02", from)
+	if err != nil {
+		return time.Time{}, nil, ErrInvalidDateFormat
+	}
+
+	var validTo *time.Time
+	if to != "" {
+		parsed, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			return time.Time{}, nil, ErrInvalidDateFormat
+		}
+		if parsed.Before(validFrom) {
+			return time.Time{}, nil, ErrInvalidCoveragePeriod
+		}
+		validTo = &parsed
+	}
+
+	return validFrom, validTo, nil
+}
+
+// Helper functions
+func (s *PatientCoverageService) toCoverageResponse(c *domain.PatientCoverage) *dto.CoverageResponse {
+	resp := &dto.CoverageResponse{
+		ID:                  c.ID,
+		PatientID:           c.PatientID,
+		PayerID:             c.PayerID,
+		PolicyNumber:        c.PolicyNumber,
+		ValidFrom:           c.ValidFrom,
+		ValidTo:             c.ValidTo,
+		CoveragePercent:     c.CoveragePercent,
+		PrimaryCareFacility: c.PrimaryCareFacility,
+		Priority:            string(c.Priority),
+		IsActive:            c.IsActive,
+		IsCurrentlyValid:    c.IsValidOn(s.clock.Today()),
+		Notes:               c.Notes,
+		CreatedAt:           c.CreatedAt,
+		UpdatedAt:           c.UpdatedAt,
+	}
+
+	if c.Payer != nil {
+		resp.PayerCode = c.Payer.Code
+		resp.PayerName = c.Payer.Name
+		resp.IsCurrentlyValid = resp.IsCurrentlyValid && c.Payer.IsActive
+	}
+
+	return resp
+}
@@ -0,0 +1,488 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/minhtran/his/internal/domain"
+	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/pkg/clock"
+	"github.com/minhtran/his/internal/pkg/logger"
+	"github.com/minhtran/his/internal/pkg/spreadsheet"
+	"github.com/minhtran/his/internal/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrImportJobNotFound       = errors.New("import job not found")
+	ErrImportEmptyFile         = errors.New("import file has no data rows")
+	ErrImportTooManyRows       = errors.New("import file exceeds the maximum number of rows")
+	ErrInvalidColumnMapping    = errors.New("column mapping references an unknown patient field")
+	ErrImportColumnsMissing    = errors.New("import file is missing required columns")
+	ErrUnsupportedImportFormat = errors.New("unsupported import file format, use .csv or .xlsx")
+	ErrInvalidImportFile       = errors.New("import file could not be parsed")
+)
+
+// MaxImportRows limits the size of a single import file
+const MaxImportRows = 10000
+
+// importBatchSize is how many rows are imported before the job's report
+// and counts are saved
+const importBatchSize = 200
+
+// requiredImportFields are the patient fields every import file must provide
+var requiredImportFields = []string{"first_name", "last_name", "date_of_birth", "gender", "phone_number"}
+
+// importFields sets each importable patient field, by its JSON name in
+// CreatePatientRequest, from a cell value
+var importFields = map[string]func(req *dto.CreatePatientRequest, value string){
+	"first_name":                     func(req *dto.CreatePatientRequest, v string) { req.FirstName = v },
+	"last_name":                      func(req *dto.CreatePatientRequest, v string) { req.LastName = v },
+	"date_of_birth":                  func(req *dto.CreatePatientRequest, v string) { req.DateOfBirth = normalizeImportDate(v) },
+	"gender":                         func(req *dto.CreatePatientRequest, v string) { req.Gender = strings.ToUpper(v) },
+	"blood_type":                     func(req *dto.CreatePatientRequest, v string) { req.BloodType = strings.ToUpper(v) },
+	"phone_number":                   func(req *dto.CreatePatientRequest, v string) { req.PhoneNumber = v },
+	"email":                          func(req *dto.CreatePatientRequest, v string) { req.Email = v },
+	"address":                        func(req *dto.CreatePatientRequest, v string) { req.Address = v },
+	"city":                           func(req *dto.CreatePatientRequest, v string) { req.City = v },
+	"state":                          func(req *dto.CreatePatientRequest, v string) { req.State = v },
+	"postal_code":                    func(req *dto.CreatePatientRequest, v string) { req.PostalCode = v },
+	"country":                        func(req *dto.CreatePatientRequest, v string) { req.Country = v },
+	"preferred_language":             func(req *dto.CreatePatientRequest, v string) { req.PreferredLanguage = strings.ToLower(v) },
+	"national_id":                    func(req *dto.CreatePatientRequest, v string) { req.NationalID = v },
+	"insurance_number":               func(req *dto.CreatePatientRequest, v string) { req.InsuranceNumber = v },
+	"insurance_provider":             func(req *dto.CreatePatientRequest, v string) { req.InsuranceProvider = v },
+	"emergency_contact_name":         func(req *dto.CreatePatientRequest, v string) { req.EmergencyContactName = v },
+	"emergency_contact_phone":        func(req *dto.CreatePatientRequest, v string) { req.EmergencyContactPhone = v },
+	"emergency_contact_relationship": func(req *dto.CreatePatientRequest, v string) { req.EmergencyContactRelationship = v },
+	"allergies":                      func(req *dto.CreatePatientRequest, v string) { req.Allergies = v },
+	"chronic_conditions":             func(req *dto.CreatePatientRequest, v string) { req.ChronicConditions = v },
+	"notes":                          func(req *dto.CreatePatientRequest, v string) { req.Notes = v },
+}
+
+// PatientImportService handles bulk patient import business logic
+type PatientImportService struct {
+	importRepo     *repository.PatientImportRepository
+	patientRepo    *repository.PatientRepository
+	patientService *PatientService
+	validate       *validator.Validate
+	clock          *clock.Clock
+}
+
+// NewPatientImportService creates a new patient import service
+func NewPatientImportService(importRepo *repository.PatientImportRepository, patientRepo *repository.PatientRepository, patientService *PatientService, clk *clock.Clock) *PatientImportService {
+	// Validate rows with the same "binding" rules the JSON registration endpoint uses
+	validate := validator.New()
+	validate.SetTagName("binding")
+
+	return &PatientImportService{
+		importRepo:     importRepo,
+		patientRepo:    patientRepo,
+		patientService: patientService,
+		validate:       validate,
+		clock:          clk,
+	}
+}
+
+// ImportPatients starts importing patients from a CSV or XLSX file. The
+// mapping assigns patient fields (JSON names of CreatePatientRequest) to
+// column headers; unmapped fields default to a header with the field's own
+// name. The file and its header are checked up front and the job is saved
+// before any patient is created; rows are then imported in the background,
+// saving the per-row report and counts after every batch. In dry-run mode
+// rows are validated and checked for duplicates but no patients are created.
+func (s *PatientImportService) ImportPatients(fileName string, data []byte, mapping map[string]string, dryRun bool, createdBy uint) (*dto.PatientImportResponse, error) {
+	rows, err := spreadsheet.Read(fileName, data)
+	if err != nil {
+		if errors.Is(err, spreadsheet.ErrUnsupportedFormat) {
+			return nil, ErrUnsupportedImportFormat
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImportFile, err)
+	}
+	if len(rows) < 2 {
+		return nil, ErrImportEmptyFile
+	}
+	if len(rows)-1 > MaxImportRows {
+		return nil, fmt.Errorf("%w (%d)", ErrImportTooManyRows, MaxImportRows)
+	}
+
+	columns, resolvedMapping, err := s.resolveColumns(rows[0], mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep the spreadsheet line number of every non-blank data row
+	var records []importRecord
+	for i, record := range rows[1:] {
+		if !isBlankRecord(record) {
+			records = append(records, importRecord{lineNumber: i + 2, cells: record})
+		}
+	}
+	if len(records) == 0 {
+		return nil, ErrImportEmptyFile
+	}
+
+	job := &domain.PatientImportJob{
+		FileName:      fileName,
+		ColumnMapping: resolvedMapping,
+		DryRun:        dryRun,
+		Status:        domain.ImportJobStatusProcessing,
+		TotalRows:     len(records),
+		CreatedBy:     createdBy,
+	}
+	if err := s.importRepo.Create(job); err != nil {
+		return nil, fmt.Errorf("failed to save import job: %w", err)
+	}
+
+	go s.processJob(job, records, columns)
+
+	return s.toImportResponse(job, false), nil
+}
+
+// importRecord is a data row of an import file with its spreadsheet line number
+type importRecord struct {
+	lineNumber int
+	cells      []string
+}
+
+// processJob imports the rows of a saved job batch by batch, saving each
+// batch's row results and counts before starting the next, and marks the
+// job completed, or failed when a batch cannot be saved
+func (s *PatientImportService) processJob(job *domain.PatientImportJob, records []importRecord, columns map[string]int) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("Patient import panicked", zap.Uint("job_id", job.ID), zap.Any("panic", r))
+			s.finishJob(job, domain.ImportJobStatusFailed)
+		}
+	}()
+
+	seenNationalIDs := make(map[string]int)
+	seenIdentities := make(map[string]int)
+
+	for start := 0; start < len(records); start += importBatchSize {
+		end := start + importBatchSize
+		if end > len(records) {
+			end = len(records)
+		}
+
+		var batch []*domain.PatientImportRow
+		var created, skipped, failed int
+		for _, record := range records[start:end] {
+			row := s.importRow(record.lineNumber, record.cells, columns, seenNationalIDs, seenIdentities, job.DryRun, job.CreatedBy)
+			row.JobID = job.ID
+			batch = append(batch, row)
+
+			switch row.Status {
+			case domain.ImportRowStatusCreated, domain.ImportRowStatusValid:
+				created++
+			case domain.ImportRowStatusSkipped:
+				skipped++
+			default:
+				failed++
+			}
+		}
+
+		if err := s.importRepo.SaveBatch(job.ID, batch, created, skipped, failed); err != nil {
+			logger.Error("Failed to save patient import batch", zap.Uint("job_id", job.ID), zap.Int("first_line", records[start].lineNumber), zap.Error(err))
+			s.finishJob(job, domain.ImportJobStatusFailed)
+			return
+		}
+	}
+
+	s.finishJob(job, domain.ImportJobStatusCompleted)
+}
+
+// finishJob records how an import job ended
+func (s *PatientImportService) finishJob(job *domain.PatientImportJob, status domain.ImportJobStatus) {
+	if err := s.importRepo.Finish(job.ID, status, s.clock.Now()); err != nil {
+		logger.Error("Failed to finish patient import job", zap.Uint("job_id", job.ID), zap.Error(err))
+	}
+}
+
+// GetImportJob gets an import job with its per-row results
+func (s *PatientImportService) GetImportJob(id uint) (*dto.PatientImportResponse, error) {
+	job, err := s.importRepo.FindByIDWithRows(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find import job: %w", err)
+	}
+	if job == nil {
+		return nil, ErrImportJobNotFound
+	}
+
+	return s.toImportResponse(job, true), nil
+}
+
+// ListImportJobs lists import jobs without row details
+func (s *PatientImportService) ListImportJobs(page, pageSize int) ([]*dto.PatientImportResponse, int64, error) {
+	jobs, total, err := s.importRepo.List(page, pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list import jobs: %w", err)
+	}
+
+	items := make([]*dto.PatientImportResponse, len(jobs))
+	for i, job := range jobs {
+		items[i] = s.toImportResponse(job, false)
+	}
+	return items, total, nil
+}
+
+// GenerateImportReport renders the per-row results of an import job as CSV
+func (s *PatientImportService) GenerateImportReport(id uint) ([]byte, string, error) {
+	job, err := s.importRepo.FindByIDWithRows(id)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to find import job: %w", err)
+	}
+	if job == nil {
+		return nil, "", ErrImportJobNotFound
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("\xef\xbb\xbf") // UTF-8 BOM so spreadsheet apps detect the encoding
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"line_number", "status", "full_name", "national_id", "patient_id", "message"})
+	for _, row := range job.Rows {
+		patientID := ""
+		if row.PatientID != nil {
+			patientID = strconv.FormatUint(uint64(*row.PatientID), 10)
+		}
+		w.Write([]string{strconv.Itoa(row.LineNumber), string(row.Status), row.FullName, row.NationalID, patientID, row.Message})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, "", fmt.Errorf("failed to write import report: %w", err)
+	}
+
+	return buf.Bytes(), fmt.Sprintf("patient-import-%d-report.csv", job.ID), nil
+}
+
+// resolveColumns maps patient fields to column positions in the header row
+func (s *PatientImportService) resolveColumns(header []string, mapping map[string]string) (map[string]int, domain.ImportColumnMapping, error) {
+	headerIndex := make(map[string]int, len(header))
+	for i, h := range header {
+		key := normalizeHeader(h)
+		if _, exists := headerIndex[key]; !exists {
+			headerIndex[key] = i
+		}
+	}
+
+	for field := range mapping {
+		if _, ok := importFields[field]; !ok {
+			return nil, nil, fmt.Errorf("%w: %s", ErrInvalidColumnMapping, field)
+		}
+	}
+
+	columns := make(map[string]int)
+	resolved := domain.ImportColumnMapping{}
+	for field := range importFields {
+		source := field
+		if mapped, ok := mapping[field]; ok && strings.TrimSpace(mapped) != "" {
+			source = mapped
+		}
+		col, ok := headerIndex[normalizeHeader(source)]
+		if !ok {
+			continue
+		}
+		columns[field] = col
+		resolved[field] = header[col]
+	}
+
+	var missing []string
+	for _, field := range requiredImportFields {
+		if _, ok := resolved[field]; !ok {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, nil, fmt.Errorf("%w: %s", ErrImportColumnsMissing, strings.Join(missing, ", "))
+	}
+
+	return columns, resolved, nil
+}
+
+// importRow validates one record and, unless dry-running, creates the patient
+func (s *PatientImportService) importRow(lineNumber int, record []string, columns map[string]int, seenNationalIDs, seenIdentities map[string]int, dryRun bool, createdBy uint) *domain.PatientImportRow {
+	req := &dto.CreatePatientRequest{}
+	for field, col := range columns {
+		if col < len(record) {
+			importFields[field](req, strings.TrimSpace(record[col]))
+		}
+	}
+
+	row := &domain.PatientImportRow{
+		LineNumber: lineNumber,
+		FullName:   strings.TrimSpace(req.FirstName + " " + req.LastName),
+		NationalID: req.NationalID,
+	}
+
+	if err := s.validate.Struct(req); err != nil {
+		row.Status = domain.ImportRowStatusError
+		row.Message = s.describeValidationError(err)
+		return row
+	}
+
+	dob, err := time.Parse("2006-01-02", req.DateOfBirth)
+	if err != nil {
+		row.Status = domain.ImportRowStatusError
+		row.Message = "date_of_birth: " + ErrInvalidDateFormat.Error()
+		return row
+	}
+
+	// Duplicate detection within the file
+	if req.NationalID != "" {
+		if line, ok := seenNationalIDs[req.NationalID]; ok {
+			row.Status = domain.ImportRowStatusSkipped
+			row.Message = fmt.Sprintf("duplicate national ID of line %d", line)
+			return row
+		}
+		seenNationalIDs[req.NationalID] = lineNumber
+	}
+	identity := strings.ToLower(req.FirstName + "|" + req.LastName + "|" + req.DateOfBirth + "|" + req.PhoneNumber)
+	if line, ok := seenIdentities[identity]; ok {
+		row.Status = domain.ImportRowStatusSkipped
+		row.Message = fmt.Sprintf("same name, date of birth and phone as line %d", line)
+		return row
+	}
+	seenIdentities[identity] = lineNumber
+
+	// Duplicate detection against existing patients
+	if req.NationalID != "" {
+		existing, err := s.patientRepo.FindByNationalID(req.NationalID)
+		if err != nil {
+			row.Status = domain.ImportRowStatusError
+			row.Message = "failed to check existing patient"
+			return row
+		}
+		if existing != nil {
+			row.Status = domain.ImportRowStatusSkipped
+			row.PatientID = &existing.ID
+			row.Message = fmt.Sprintf("patient with this national ID already exists (%s)", existing.PatientCode)
+			return row
+		}
+	}
+	existing, err := s.patientRepo.FindPossibleDuplicate(req.FirstName, req.LastName, dob, req.PhoneNumber)
+	if err != nil {
+		row.Status = domain.ImportRowStatusError
+		row.Message = "failed to check existing patient"
+		return row
+	}
+	if existing != nil {
+		row.Status = domain.ImportRowStatusSkipped
+		row.PatientID = &existing.ID
+		row.Message = fmt.Sprintf("possible duplicate of %s (same name, date of birth and phone)", existing.PatientCode)
+		return row
+	}
+
+	if dryRun {
+		row.Status = domain.ImportRowStatusValid
+		row.Message = "would be created"
+		return row
+	}
+
+	patient, err := s.patientService.RegisterPatient(req, createdBy)
+	if err != nil {
+		if errors.Is(err, ErrPatientExists) {
+			row.Status = domain.ImportRowStatusSkipped
+			row.Message = "patient with this national ID already exists"
+			return row
+		}
+		row.Status = domain.ImportRowStatusError
+		row.Message = "failed to create patient"
+		return row
+	}
+
+	row.Status = domain.ImportRowStatusCreated
+	row.PatientID = &patient.ID
+	row.Message = patient.PatientCode
+	return row
+}
+
+// describeValidationError turns validator errors into "field: rule" messages
+func (s *PatientImportService) describeValidationError(err error) string {
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return err.Error()
+	}
+
+	t := reflect.TypeOf(dto.CreatePatientRequest{})
+	messages := make([]string, 0, len(verrs))
+	for _, fe := range verrs {
+		name := fe.Field()
+		if f, ok := t.FieldByName(fe.StructField()); ok {
+			name = strings.Split(f.Tag.Get("json"), ",")[0]
+		}
+		rule := fe.Tag()
+		if fe.Param() != "" {
+			rule += "=" + fe.Param()
+		}
+		messages = append(messages, fmt.Sprintf("%s: failed %s", name, rule))
+	}
+	return strings.Join(messages, "; ")
+}
+
+// normalizeImportDate accepts YYYY-MM-DD, DD/MM/YYYY and Excel serial dates
+func normalizeImportDate(value string) string {
+	if value == "" {
+		return value
+	}
+	if t, err := time.Parse("2/1/2006", value); err == nil {
+		return t.Format("2006-01-02")
+	}
+	if serial, err := strconv.ParseFloat(value, 64); err == nil && serial > 0 && serial < 100000 {
+		return spreadsheet.ExcelSerialToDate(serial).Format("2006-01-02")
+	}
+	return value
+}
+
+func normalizeHeader(h string) string {
+	h = strings.ToLower(strings.TrimSpace(h))
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(h)
+}
+
+func isBlankRecord(record []string) bool {
+	for _, cell := range record {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// Helper functions
+func (s *PatientImportService) toImportResponse(job *domain.PatientImportJob, withRows bool) *dto.PatientImportResponse {
+	resp := &dto.PatientImportResponse{
+		ID:            job.ID,
+		FileName:      job.FileName,
+		DryRun:        job.DryRun,
+		Status:        string(job.Status),
+		ColumnMapping: job.ColumnMapping,
+		TotalRows:     job.TotalRows,
+		CreatedCount:  job.CreatedCount,
+		SkippedCount:  job.SkippedCount,
+		ErrorCount:    job.ErrorCount,
+		CreatedAt:     job.CreatedAt,
+		CompletedAt:   job.CompletedAt,
+	}
+
+	if withRows {
+		resp.Rows = make([]*dto.PatientImportRowResponse, len(job.Rows))
+		for i, row := range job.Rows {
+			resp.Rows[i] = &dto.PatientImportRowResponse{
+				LineNumber: row.LineNumber,
+				Status:     string(row.Status),
+				FullName:   row.FullName,
+				NationalID: row.NationalID,
+				PatientID:  row.PatientID,
+				Message:    row.Message,
+			}
+		}
+	}
+
+	return resp
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/minhtran/his/internal/domain"
 	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/pkg/clock"
 	"github.com/minhtran/his/internal/repository"
 )
 
@@ -14,17 +15,20 @@ var (
 	ErrPatientExists     = errors.New("patient already exists")
 	ErrPatientNotFound   = errors.New("patient not found")
 	ErrInvalidDateFormat = errors.New("invalid date format, use YYYY-MM-DD")
+	ErrPatientDeceased   = errors.New("patient is deceased")
 )
 
 // PatientService handles patient business logic
 type PatientService struct {
 	patientRepo *repository.PatientRepository
+	clock       *clock.Clock
 }
 
 // NewPatientService creates a new patient service
-func NewPatientService(patientRepo *repository.PatientRepository) *PatientService {
+func NewPatientService(patientRepo *repository.PatientRepository, clk *clock.Clock) *PatientService {
 	return &PatientService{
 		patientRepo: patientRepo,
+		clock:       clk,
 	}
 }
 
@@ -54,6 +58,17 @@ func (s *PatientService) RegisterPatient(req *dto.CreatePatientRequest, createdB
 	}
 
 	// Create patient
+	patient := newPatientFromRequest(req, dob, patientCode, createdBy)
+
+	if err := s.patientRepo.Create(patient); err != nil {
+		return nil, fmt.Errorf("failed to create patient: %w", err)
+	}
+
+	return s.toPatientResponse(patient), nil
+}
+
+// newPatientFromRequest builds a patient entity from a registration request
+func newPatientFromRequest(req *dto.CreatePatientRequest, dob time.Time, patientCode string, createdBy uint) *domain.Patient {
 	patient := &domain.Patient{
 		PatientCode:                  patientCode,
 		FirstName:                    req.FirstName,
@@ -68,6 +83,7 @@ func (s *PatientService) RegisterPatient(req *dto.CreatePatientRequest, createdB
 		State:                        req.State,
 		PostalCode:                   req.PostalCode,
 		Country:                      req.Country,
+		PreferredLanguage:            req.PreferredLanguage,
 		NationalID:                   req.NationalID,
 		InsuranceNumber:              req.InsuranceNumber,
 		InsuranceProvider:            req.InsuranceProvider,
@@ -78,18 +94,17 @@ func (s *PatientService) RegisterPatient(req *dto.CreatePatientRequest, createdB
 		ChronicConditions:            req.ChronicConditions,
 		Notes:                        req.Notes,
 		IsActive:                     true,
-		CreatedBy:                    createdBy,
+		CreatedBy:                    &createdBy,
 	}
 
 	if patient.Country == "" {
 		patient.Country = "Vietnam"
 	}
-
-	if err := s.patientRepo.Create(patient); err != nil {
-		return nil, fmt.Errorf("failed to create patient: %w", err)
+	if patient.PreferredLanguage == "" {
+		patient.PreferredLanguage = "vi"
 	}
 
-	return s.toPatientResponse(patient), nil
+	return patient
 }
 
 // UpdatePatient updates patient information
@@ -143,6 +158,9 @@ func (s *PatientService) UpdatePatient(id uint, req *dto.UpdatePatientRequest, u
 	if req.Country != "" {
 		patient.Country = req.Country
 	}
+	if req.PreferredLanguage != "" {
+		patient.PreferredLanguage = req.PreferredLanguage
+	}
 	if req.NationalID != "" {
 		patient.NationalID = req.NationalID
 	}
@@ -171,9 +189,15 @@ func (s *PatientService) UpdatePatient(id uint, req *dto.UpdatePatientRequest, u
 		patient.Notes = req.Notes
 	}
 	if req.IsActive != nil {
+		// A deceased patient cannot be reactivated
+		if *req.IsActive && patient.IsDeceased {
+			return nil, ErrPatientDeceased
+		}
 		patient.IsActive = *req.IsActive
 	}
 
+	// Staff editing the record completes an online pre-registration
+	patient.PreRegistered = false
 	patient.UpdatedBy = updatedBy
 
 	if err := s.patientRepo.Update(patient); err != nil {
@@ -256,13 +280,19 @@ func (s *PatientService) DeletePatient(id uint) error {
 
 // GetPatientStats gets patient statistics
 func (s *PatientService) GetPatientStats() (map[string]interface{}, error) {
-	stats, err := s.patientRepo.GetPatientStats()
+	dayStart, monthStart := statsPeriodStarts(s.clock)
+	stats, err := s.patientRepo.GetPatientStats(dayStart, monthStart)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get patient stats: %w", err)
 	}
 	return stats, nil
 }
 
+// statsPeriodStarts returns the instants the facility's current day and month began
+func statsPeriodStarts(clk *clock.Clock) (time.Time, time.Time) {
+	return clk.StartOfDay(clk.Today()), clk.StartOfMonth()
+}
+
 // Helper to convert domain patient to DTO
 func (s *PatientService) toPatientResponse(patient *domain.Patient) *dto.PatientResponse {
 	return &dto.PatientResponse{
@@ -282,6 +312,7 @@ func (s *PatientService) toPatientResponse(patient *domain.Patient) *dto.Patient
 		State:                        patient.State,
 		PostalCode:                   patient.PostalCode,
 		Country:                      patient.Country,
+		PreferredLanguage:            patient.PreferredLanguage,
 		NationalID:                   patient.NationalID,
 		InsuranceNumber:              patient.InsuranceNumber,
 		InsuranceProvider:            patient.InsuranceProvider,
@@ -292,6 +323,9 @@ func (s *PatientService) toPatientResponse(patient *domain.Patient) *dto.Patient
 		ChronicConditions:            patient.ChronicConditions,
 		Notes:                        patient.Notes,
 		IsActive:                     patient.IsActive,
+		IsDeceased:                   patient.IsDeceased,
+		DeceasedAt:                   patient.DeceasedAt,
+		PreRegistered:                patient.PreRegistered,
 		CreatedAt:                    patient.CreatedAt,
 		UpdatedAt:                    patient.UpdatedAt,
 	}
@@ -300,14 +334,16 @@ func (s *PatientService) toPatientResponse(patient *domain.Patient) *dto.Patient
 // Helper to convert domain patient to list item
 func (s *PatientService) toPatientListItem(patient *domain.Patient) *dto.PatientListItem {
 	return &dto.PatientListItem{
-		ID:          patient.ID,
-		PatientCode: patient.PatientCode,
-		FullName:    patient.FullName,
-		Age:         patient.Age,
-		Gender:      string(patient.Gender),
-		PhoneNumber: patient.PhoneNumber,
-		City:        patient.City,
-		IsActive:    patient.IsActive,
-		CreatedAt:   patient.CreatedAt.Format(time.RFC3339),
+		ID:            patient.ID,
+		PatientCode:   patient.PatientCode,
+		FullName:      patient.FullName,
+		Age:           patient.Age,
+		Gender:        string(patient.Gender),
+		PhoneNumber:   patient.PhoneNumber,
+		City:          patient.City,
+		IsActive:      patient.IsActive,
+		IsDeceased:    patient.IsDeceased,
+		PreRegistered: patient.PreRegistered,
+		CreatedAt:     patient.CreatedAt.Format(time.RFC3339),
 	}
 }
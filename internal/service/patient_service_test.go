@@ -0,0 +1,75 @@
+package service
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/minhtran/his/internal/pkg/clock"
+)
+
+func TestStatsPeriodStarts(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("failed to load time zone: %v", err)
+	}
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		t.Fatalf("failed to load time zone: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		loc       *time.Location
+		now       time.Time
+		wantDay   time.Time
+		wantMonth time.Time
+	}{
+		{
+			name:      "late evening before utc midnight",
+			loc:       newYork,
+			now:       time.Date(2026, 3, 8, 3, 30, 0, 0, time.UTC), // 22:30 EST on 7 March
+			wantDay:   time.Date(2026, 3, 7, 5, 0, 0, 0, time.UTC),
+			wantMonth: time.Date(2026, 3, 1, 5, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "day after spring forward",
+			loc:       newYork,
+			now:       time.Date(2026, 3, 9, 12, 0, 0, 0, time.UTC),
+			wantDay:   time.Date(2026, 3, 9, 4, 0, 0, 0, time.UTC), // EDT
+			wantMonth: time.Date(2026, 3, 1, 5, 0, 0, 0, time.UTC), // EST
+		},
+		{
+			name:      "first of month before utc midnight",
+			loc:       newYork,
+			now:       time.Date(2026, 11, 1, 2, 0, 0, 0, time.UTC), // 22:00 EDT on 31 October
+			wantDay:   time.Date(2026, 10, 31, 4, 0, 0, 0, time.UTC),
+			wantMonth: time.Date(2026, 10, 1, 4, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "day after fall back",
+			loc:       newYork,
+			now:       time.Date(2026, 11, 2, 12, 0, 0, 0, time.UTC),
+			wantDay:   time.Date(2026, 11, 2, 5, 0, 0, 0, time.UTC), // EST
+			wantMonth: time.Date(2026, 11, 1, 4, 0, 0, 0, time.UTC), // EDT
+		},
+		{
+			name:      "midnight skipped by spring forward",
+			loc:       saoPaulo,
+			now:       time.Date(2018, 11, 4, 12, 0, 0, 0, time.UTC),
+			wantDay:   time.Date(2018, 11, 4, 3, 0, 0, 0, time.UTC), // 01:00 -02
+			wantMonth: time.Date(2018, 11, 1, 3, 0, 0, 0, time.UTC), // 00:00 -03
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			day, month := statsPeriodStarts(clock.Fixed(tt.loc, tt.now))
+			if !day.Equal(tt.wantDay) {
+				t.Errorf("day start = %v, want %v", day.UTC(), tt.wantDay)
+			}
+			if !month.Equal(tt.wantMonth) {
+				t.Errorf("month start = %v, want %v", month.UTC(), tt.wantMonth)
+			}
+		})
+	}
+}
@@ -3,10 +3,10 @@ package service
 import (
 	"errors"
 	"fmt"
-	"time"
 
 	"github.com/minhtran/his/internal/domain"
 	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/pkg/clock"
 	"github.com/minhtran/his/internal/repository"
 )
 
@@ -18,16 +18,19 @@ var (
 type PaymentService struct {
 	paymentRepo *repository.PaymentRepository
 	invoiceRepo *repository.InvoiceRepository
+	clock       *clock.Clock
 }
 
 // NewPaymentService creates a new payment service
 func NewPaymentService(
 	paymentRepo *repository.PaymentRepository,
 	invoiceRepo *repository.InvoiceRepository,
+	clk *clock.Clock,
 ) *PaymentService {
 	return &PaymentService{
 		paymentRepo: paymentRepo,
 		invoiceRepo: invoiceRepo,
+		clock:       clk,
 	}
 }
 
@@ -41,6 +44,9 @@ func (s *PaymentService) CreatePayment(req *dto.CreatePaymentRequest, createdBy
 	if invoice == nil {
 		return nil, ErrInvoiceNotFound
 	}
+	if invoice.Status == domain.InvoiceStatusDraft {
+		return nil, ErrInvoiceNotIssued
+	}
 
 	// Calculate paid amount
 	var paidAmount float64
@@ -69,7 +75,7 @@ func (s *PaymentService) CreatePayment(req *dto.CreatePaymentRequest, createdBy
 		PatientID:     invoice.PatientID,
 		PaymentMethod: domain.PaymentMethod(req.PaymentMethod),
 		Amount:        req.Amount,
-		PaymentDate:   time.Now(),
+		PaymentDate:   s.clock.Now(),
 		Notes:         req.Notes,
 		CreatedBy:     createdBy,
 	}
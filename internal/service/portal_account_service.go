@@ -0,0 +1,494 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/minhtran/his/internal/domain"
+	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/pkg/clock"
+	"github.com/minhtran/his/internal/pkg/jwt"
+	"github.com/minhtran/his/internal/pkg/logger"
+	"github.com/minhtran/his/internal/pkg/notify"
+	"github.com/minhtran/his/internal/repository"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	// ErrPortalAccountNotFound is returned when portal account is not found
+	ErrPortalAccountNotFound = errors.New("portal account not found")
+	// ErrPortalAccountExists is returned when the patient already has an active portal account
+	ErrPortalAccountExists = errors.New("patient already has a portal account")
+	// ErrPortalPhoneInUse is returned when the phone number is used by another portal account
+	ErrPortalPhoneInUse = errors.New("phone number is used by another portal account")
+	// ErrPortalPhoneRequired is returned when no phone number is available for the account
+	ErrPortalPhoneRequired = errors.New("phone number is required for a portal account")
+	// ErrPortalAccountInactive is returned when the portal account is deactivated
+	ErrPortalAccountInactive = errors.New("portal account is inactive")
+	// ErrPortalAccountLocked is returned after too many failed logins
+	ErrPortalAccountLocked = errors.New("portal account is temporarily locked")
+	// ErrInvalidOTP is returned when the one-time code is wrong, expired or used
+	ErrInvalidOTP = errors.New("invalid or expired one-time code")
+	// ErrOTPRateLimited is returned when one-time codes are requested too often
+	ErrOTPRateLimited = errors.New("too many one-time code requests, try again later")
+)
+
+const (
+	portalOTPLength         = 6
+	portalOTPTTL            = 5 * time.Minute
+	portalOTPMaxAttempts    = 5
+	portalOTPResendInterval = time.Minute
+	portalOTPHourlyLimit    = 5
+	portalMaxFailedLogins   = 5
+	portalLockDuration      = 15 * time.Minute
+)
+
+// OTPSender delivers one-time login codes to patients
+type OTPSender interface {
+	SendOTP(phoneNumber, code string) error
+}
+
+// LogOTPSender writes one-time codes to the application log. It is meant for
+// development and testing until an SMS gateway is configured.
+type LogOTPSender struct{}
+
+// SendOTP logs the one-time code
+func (LogOTPSender) SendOTP(phoneNumber, code string) error {
+	logger.Info("Portal one-time code issued", zap.String("phone_number", phoneNumber), zap.String("code", code))
+	return nil
+}
+
+// SMSOTPSender sends one-time codes by SMS through the notification
+// dispatcher, which falls back to the outbox or log when no SMS gateway is
+// configured
+type SMSOTPSender struct {
+	dispatcher   *notify.Dispatcher
+	facilityName string
+}
+
+// NewSMSOTPSender creates a new SMS one-time code sender
+func NewSMSOTPSender(dispatcher *notify.Dispatcher, facilityName string) *SMSOTPSender {
+	return &SMSOTPSender{dispatcher: dispatcher, facilityName: facilityName}
+}
+
+// SendOTP sends the one-time code by SMS
+func (s *SMSOTPSender) SendOTP(phoneNumber, code string) error {
+	_, err := s.dispatcher.Send(context.Background(), &notify.Message{
+		Channel:   notify.ChannelSMS,
+		Recipient: phoneNumber,
+		Body:      fmt.Sprintf("%s: ma xac thuc cua ban la %s. Khong chia se ma nay voi bat ky ai.", s.facilityName, code),
+		Data:      map[string]string{"facility_name": s.facilityName, "code": code},
+	})
+	return err
+}
+
+// PortalAccountService handles patient portal accounts and portal authentication
+type PortalAccountService struct {
+	accountRepo *repository.PatientPortalAccountRepository
+	patientRepo *repository.PatientRepository
+	auditRepo   *repository.AuditLogRepository
+	jwtManager  *jwt.Manager
+	otpSender   OTPSender
+	clock       *clock.Clock
+}
+
+// NewPortalAccountService creates a new portal account service
+func NewPortalAccountService(
+	accountRepo *repository.PatientPortalAccountRepository,
+	patientRepo *repository.PatientRepository,
+	auditRepo *repository.AuditLogRepository,
+	jwtManager *jwt.Manager,
+	otpSender OTPSender,
+	clk *clock.Clock,
+) *PortalAccountService {
+	return &PortalAccountService{
+		accountRepo: accountRepo,
+		patientRepo: patientRepo,
+		auditRepo:   auditRepo,
+		jwtManager:  jwtManager,
+		otpSender:   otpSender,
+		clock:       clk,
+	}
+}
+
+// CreateAccount opens (or reactivates) the portal account of a patient
+func (s *PortalAccountService) CreateAccount(patientID uint, req *dto.CreatePortalAccountRequest, userID uint) (*dto.PortalAccountResponse, error) {
+	patient, err := s.patientRepo.FindByID(patientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find patient: %w", err)
+	}
+	if patient == nil {
+		return nil, ErrPatientNotFound
+	}
+	if patient.IsDeceased {
+		return nil, ErrPatientDeceased
+	}
+
+	phoneNumber := normalizePhoneNumber(req.PhoneNumber)
+	if phoneNumber == "" {
+		phoneNumber = normalizePhoneNumber(patient.PhoneNumber)
+	}
+	if phoneNumber == "" {
+		return nil, ErrPortalPhoneRequired
+	}
+
+	account, err := s.accountRepo.FindByPatientID(patientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find portal account: %w", err)
+	}
+	if account != nil && account.IsActive {
+		return nil, ErrPortalAccountExists
+	}
+
+	other, err := s.accountRepo.FindByPhoneNumber(phoneNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check phone number: %w", err)
+	}
+	if other != nil && other.PatientID != patientID {
+		return nil, ErrPortalPhoneInUse
+	}
+
+	if account == nil {
+		account = &domain.PatientPortalAccount{
+			PatientID:   patientID,
+			PhoneNumber: phoneNumber,
+			IsActive:    true,
+			CreatedBy:   userID,
+			UpdatedBy:   userID,
+		}
+		if err := s.accountRepo.Create(account); err != nil {
+			return nil, fmt.Errorf("failed to create portal account: %w", err)
+		}
+	} else {
+		account.PhoneNumber = phoneNumber
+		account.IsActive = true
+		account.FailedLoginAttempts = 0
+		account.LockedUntil = nil
+		account.UpdatedBy = userID
+		if err := s.accountRepo.Update(account); err != nil {
+			return nil, fmt.Errorf("failed to reactivate portal account: %w", err)
+		}
+	}
+
+	// Audit log
+	s.auditRepo.Create(&domain.AuditLog{
+		UserID:     &userID,
+		Action:     domain.AuditActionCreate,
+		Resource:   "PatientPortalAccount",
+		ResourceID: patient.PatientCode,
+		Details:    domain.AuditDetails{"account_id": account.ID},
+	})
+
+	return s.toAccountResponse(account), nil
+}
+
+// GetAccount gets the portal account of a patient
+func (s *PortalAccountService) GetAccount(patientID uint) (*dto.PortalAccountResponse, error) {
+	account, err := s.accountRepo.FindByPatientID(patientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find portal account: %w", err)
+	}
+	if account == nil {
+		return nil, ErrPortalAccountNotFound
+	}
+
+	return s.toAccountResponse(account), nil
+}
+
+// DeactivateAccount disables portal access for a patient
+func (s *PortalAccountService) DeactivateAccount(patientID uint, userID uint) error {
+	account, err := s.accountRepo.FindByPatientID(patientID)
+	if err != nil {
+		return fmt.Errorf("failed to find portal account: %w", err)
+	}
+	if account == nil {
+		return ErrPortalAccountNotFound
+	}
+
+	account.IsActive = false
+	account.UpdatedBy = userID
+	if err := s.accountRepo.Update(account); err != nil {
+		return fmt.Errorf("failed to deactivate portal account: %w", err)
+	}
+
+	// Audit log
+	s.auditRepo.Create(&domain.AuditLog{
+		UserID:     &userID,
+		Action:     domain.AuditActionUpdate,
+		Resource:   "PatientPortalAccount",
+		ResourceID: fmt.Sprintf("%d", account.ID),
+		Details:    domain.AuditDetails{"patient_id": patientID, "is_active": false},
+	})
+
+	return nil
+}
+
+// RequestOTP sends a one-time login code to the phone of an active portal account.
+// Unknown numbers are silently ignored so the endpoint cannot be used to discover patients.
+func (s *PortalAccountService) RequestOTP(req *dto.PortalOTPRequest) error {
+	account, err := s.accountRepo.FindByPhoneNumber(normalizePhoneNumber(req.PhoneNumber))
+	if err != nil {
+		return fmt.Errorf("failed to find portal account: %w", err)
+	}
+	if account == nil || !account.IsActive || (account.Patient != nil && account.Patient.IsDeceased) {
+		return nil
+	}
+
+	now := s.clock.Now()
+	recent, err := s.accountRepo.CountOTPsSince(account.ID, now.Add(-portalOTPResendInterval))
+	if err != nil {
+		return fmt.Errorf("failed to check one-time codes: %w", err)
+	}
+	hourly, err := s.accountRepo.CountOTPsSince(account.ID, now.Add(-time.Hour))
+	if err != nil {
+		return fmt.Errorf("failed to check one-time codes: %w", err)
+	}
+	if recent > 0 || hourly >= portalOTPHourlyLimit {
+		return ErrOTPRateLimited
+	}
+
+	code, err := generateOTPCode(portalOTPLength)
+	if err != nil {
+		return fmt.Errorf("failed to generate one-time code: %w", err)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(code), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash one-time code: %w", err)
+	}
+
+	otp := &domain.PortalOTP{
+		AccountID: account.ID,
+		CodeHash:  string(hash),
+		ExpiresAt: now.Add(portalOTPTTL),
+	}
+	if err := s.accountRepo.CreateOTP(otp); err != nil {
+		return fmt.Errorf("failed to store one-time code: %w", err)
+	}
+
+	if err := s.otpSender.SendOTP(account.PhoneNumber, code); err != nil {
+		return fmt.Errorf("failed to send one-time code: %w", err)
+	}
+
+	return nil
+}
+
+// VerifyOTP logs a patient in with a one-time code
+func (s *PortalAccountService) VerifyOTP(req *dto.PortalOTPVerifyRequest) (*dto.PortalAuthResponse, error) {
+	account, err := s.accountRepo.FindByPhoneNumber(normalizePhoneNumber(req.PhoneNumber))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find portal account: %w", err)
+	}
+	if account == nil || !account.IsActive {
+		return nil, ErrInvalidOTP
+	}
+
+	now := s.clock.Now()
+	if account.IsLocked(now) {
+		return nil, ErrPortalAccountLocked
+	}
+
+	otp, err := s.accountRepo.FindActiveOTP(account.ID, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find one-time code: %w", err)
+	}
+	if otp == nil {
+		return nil, ErrInvalidOTP
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(otp.CodeHash), []byte(req.Code)) != nil {
+		otp.Attempts++
+		if otp.Attempts >= portalOTPMaxAttempts {
+			otp.ConsumedAt = &now
+		}
+		if err := s.accountRepo.UpdateOTP(otp); err != nil {
+			return nil, fmt.Errorf("failed to update one-time code: %w", err)
+		}
+		return nil, ErrInvalidOTP
+	}
+
+	otp.ConsumedAt = &now
+	if err := s.accountRepo.UpdateOTP(otp); err != nil {
+		return nil, fmt.Errorf("failed to update one-time code: %w", err)
+	}
+
+	return s.completeLogin(account, now)
+}
+
+// Login logs a patient in with phone number and password
+func (s *PortalAccountService) Login(req *dto.PortalLoginRequest) (*dto.PortalAuthResponse, error) {
+	account, err := s.accountRepo.FindByPhoneNumber(normalizePhoneNumber(req.PhoneNumber))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find portal account: %w", err)
+	}
+	// Deactivated accounts fail like unknown ones so logins cannot reveal them
+	if account == nil || account.PasswordHash == "" || !account.IsActive {
+		return nil, ErrInvalidCredentials
+	}
+
+	now := s.clock.Now()
+	if account.IsLocked(now) {
+		return nil, ErrPortalAccountLocked
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(account.PasswordHash), []byte(req.Password)) != nil {
+		account.FailedLoginAttempts++
+		if account.FailedLoginAttempts >= portalMaxFailedLogins {
+			lockedUntil := now.Add(portalLockDuration)
+			account.LockedUntil = &lockedUntil
+			account.FailedLoginAttempts = 0
+		}
+		if err := s.accountRepo.Update(account); err != nil {
+			return nil, fmt.Errorf("failed to update portal account: %w", err)
+		}
+		return nil, ErrInvalidCredentials
+	}
+
+	return s.completeLogin(account, now)
+}
+
+// RefreshToken issues a new portal access token for an active account
+func (s *PortalAccountService) RefreshToken(refreshToken string) (string, error) {
+	claims, err := s.jwtManager.ValidatePortalToken(refreshToken)
+	if err != nil {
+		return "", fmt.Errorf("failed to refresh token: %w", err)
+	}
+
+	account, err := s.accountRepo.FindByID(claims.AccountID)
+	if err != nil {
+		return "", fmt.Errorf("failed to find portal account: %w", err)
+	}
+	if account == nil || !account.IsActive {
+		return "", ErrPortalAccountInactive
+	}
+
+	accessToken, err := s.jwtManager.RefreshPortalAccessToken(refreshToken)
+	if err != nil {
+		return "", fmt.Errorf("failed to refresh token: %w", err)
+	}
+
+	return accessToken, nil
+}
+
+// GetProfile gets the signed-in patient's profile
+func (s *PortalAccountService) GetProfile(accountID uint) (*dto.PortalProfileResponse, error) {
+	account, err := s.accountRepo.FindByID(accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find portal account: %w", err)
+	}
+	if account == nil || !account.IsActive || account.Patient == nil {
+		return nil, ErrPortalAccountInactive
+	}
+
+	return s.toProfileResponse(account), nil
+}
+
+// SetPassword sets or changes the portal password of the signed-in patient
+func (s *PortalAccountService) SetPassword(accountID uint, req *dto.PortalSetPasswordRequest) error {
+	account, err := s.accountRepo.FindByID(accountID)
+	if err != nil {
+		return fmt.Errorf("failed to find portal account: %w", err)
+	}
+	if account == nil || !account.IsActive {
+		return ErrPortalAccountInactive
+	}
+
+	if account.PasswordHash != "" {
+		if bcrypt.CompareHashAndPassword([]byte(account.PasswordHash), []byte(req.CurrentPassword)) != nil {
+			return ErrInvalidCredentials
+		}
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	account.PasswordHash = string(hash)
+	if err := s.accountRepo.Update(account); err != nil {
+		return fmt.Errorf("failed to update portal account: %w", err)
+	}
+
+	return nil
+}
+
+// Helper functions
+
+// completeLogin resets the failed login counter and issues portal tokens
+func (s *PortalAccountService) completeLogin(account *domain.PatientPortalAccount, now time.Time) (*dto.PortalAuthResponse, error) {
+	if account.Patient == nil || account.Patient.IsDeceased {
+		return nil, ErrPortalAccountInactive
+	}
+
+	account.FailedLoginAttempts = 0
+	account.LockedUntil = nil
+	account.LastLoginAt = &now
+	if err := s.accountRepo.Update(account); err != nil {
+		return nil, fmt.Errorf("failed to update portal account: %w", err)
+	}
+
+	tokenPair, err := s.jwtManager.GeneratePortalTokenPair(account.ID, account.PatientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate tokens: %w", err)
+	}
+
+	return &dto.PortalAuthResponse{
+		Patient:      s.toProfileResponse(account),
+		AccessToken:  tokenPair.AccessToken,
+		RefreshToken: tokenPair.RefreshToken,
+		ExpiresIn:    tokenPair.ExpiresIn,
+	}, nil
+}
+
+// generateOTPCode returns a random numeric code of the given length
+func generateOTPCode(length int) (string, error) {
+	var sb strings.Builder
+	for i := 0; i < length; i++ {
+		n, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(byte('0' + n.Int64()))
+	}
+	return sb.String(), nil
+}
+
+// normalizePhoneNumber strips spaces, dots and dashes from a phone number
+func normalizePhoneNumber(phone string) string {
+	return strings.NewReplacer(" ", "", ".", "", "-", "").Replace(strings.TrimSpace(phone))
+}
+
+func (s *PortalAccountService) toAccountResponse(a *domain.PatientPortalAccount) *dto.PortalAccountResponse {
+	return &dto.PortalAccountResponse{
+		ID:          a.ID,
+		PatientID:   a.PatientID,
+		PhoneNumber: a.PhoneNumber,
+		HasPassword: a.PasswordHash != "",
+		IsActive:    a.IsActive,
+		LockedUntil: a.LockedUntil,
+		LastLoginAt: a.LastLoginAt,
+		CreatedAt:   a.CreatedAt,
+		UpdatedAt:   a.UpdatedAt,
+	}
+}
+
+func (s *PortalAccountService) toProfileResponse(a *domain.PatientPortalAccount) *dto.PortalProfileResponse {
+	resp := &dto.PortalProfileResponse{
+		PatientID:   a.PatientID,
+		PhoneNumber: a.PhoneNumber,
+		HasPassword: a.PasswordHash != "",
+	}
+	if p := a.Patient; p != nil {
+		resp.PatientCode = p.PatientCode
+		resp.FullName = p.FullName
+		resp.DateOfBirth = p.DateOfBirth.Format("2006-01-02")
+		resp.Gender = string(p.Gender)
+		resp.Email = p.Email
+	}
+	return resp
+}
@@ -0,0 +1,297 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/minhtran/his/internal/domain"
+	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/pkg/clock"
+	"github.com/minhtran/his/internal/repository"
+)
+
+var (
+	// ErrPortalBookingTooSoon is returned when a portal booking starts within the minimum lead time
+	ErrPortalBookingTooSoon = errors.New("appointments must be booked at least 2 hours in advance")
+	// ErrPortalBookingTooFar is returned when a portal booking is beyond the booking horizon
+	ErrPortalBookingTooFar = errors.New("appointments can be booked at most 60 days in advance")
+	// ErrPortalBookingLimit is returned when the patient already has the maximum number of upcoming portal bookings
+	ErrPortalBookingLimit = errors.New("maximum number of upcoming online bookings reached")
+	// ErrPortalCancelTooLate is returned when a portal cancellation is within the cancellation cutoff
+	ErrPortalCancelTooLate = errors.New("appointments can only be cancelled online at least 24 hours in advance")
+	// ErrPortalAppointmentNotCancellable is returned when the appointment is not scheduled or confirmed
+	ErrPortalAppointmentNotCancellable = errors.New("appointment cannot be cancelled")
+)
+
+// Portal booking rules
+const (
+	portalMinBookingLead    = 2 * time.Hour
+	portalMaxBookingHorizon = 60 * 24 * time.Hour
+	portalMaxActiveBookings = 3
+	portalCancelCutoff      = 24 * time.Hour
+)
+
+// PortalService serves the patient-facing portal. Every method is scoped to the
+// signed-in account's patient; records of other patients and unreleased results
+// are reported as not found.
+type PortalService struct {
+	accountRepo           *repository.PatientPortalAccountRepository
+	appointmentRepo       *repository.AppointmentRepository
+	appointmentService    *AppointmentService
+	labTestRequestService *LabTestRequestService
+	imagingRequestService *ImagingRequestService
+	prescriptionService   *PrescriptionService
+	invoiceService        *InvoiceService
+	clock                 *clock.Clock
+}
+
+// NewPortalService creates a new portal service
+func NewPortalService(
+	accountRepo *repository.PatientPortalAccountRepository,
+	appointmentRepo *repository.AppointmentRepository,
+	appointmentService *AppointmentService,
+	labTestRequestService *LabTestRequestService,
+	imagingRequestService *ImagingRequestService,
+	prescriptionService *PrescriptionService,
+	invoiceService *InvoiceService,
+	clk *clock.Clock,
+) *PortalService {
+	return &PortalService{
+		accountRepo:           accountRepo,
+		appointmentRepo:       appointmentRepo,
+		appointmentService:    appointmentService,
+		labTestRequestService: labTestRequestService,
+		imagingRequestService: imagingRequestService,
+		prescriptionService:   prescriptionService,
+		invoiceService:        invoiceService,
+		clock:                 clk,
+	}
+}
+
+// GetAppointments lists the patient's appointments
+func (s *PortalService) GetAppointments(accountID uint, filters map[string]interface{}) ([]*dto.AppointmentListItem, error) {
+	patientID, err := s.patientIDFor(accountID)
+	if err != nil {
+		return nil, err
+	}
+	return s.appointmentService.GetPatientAppointments(patientID, filters)
+}
+
+// GetAppointment gets one of the patient's appointments
+func (s *PortalService) GetAppointment(accountID, appointmentID uint) (*dto.AppointmentResponse, error) {
+	patientID, err := s.patientIDFor(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	appointment, err := s.appointmentService.GetAppointmentByID(appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	if appointment.PatientID != patientID {
+		return nil, ErrAppointmentNotFound
+	}
+	return appointment, nil
+}
+
+// BookAppointment books an appointment for the patient within the portal booking rules
+func (s *PortalService) BookAppointment(accountID uint, req *dto.PortalBookAppointmentRequest) (*dto.AppointmentResponse, error) {
+	patientID, err := s.patientIDFor(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	date, err := time.Parse("2006-01-02", req.AppointmentDate)
+	if err != nil {
+		return nil, ErrInvalidDateFormat
+	}
+	at, err := time.Parse("15:04", req.AppointmentTime)
+	if err != nil {
+		return nil, errors.New("invalid time format, use HH:MM")
+	}
+
+	now := s.clock.Now()
+	start := s.clock.At(date, at)
+	if start.Before(now.Add(portalMinBookingLead)) {
+		return nil, ErrPortalBookingTooSoon
+	}
+	if start.After(now.Add(portalMaxBookingHorizon)) {
+		return nil, ErrPortalBookingTooFar
+	}
+
+	active, err := s.appointmentRepo.CountActivePortalBookings(patientID, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count portal bookings: %w", err)
+	}
+	if active >= portalMaxActiveBookings {
+		return nil, ErrPortalBookingLimit
+	}
+
+	return s.appointmentService.SchedulePortalAppointment(&dto.CreateAppointmentRequest{
+		PatientID:       patientID,
+		DoctorID:        req.DoctorID,
+		AppointmentDate: req.AppointmentDate,
+		AppointmentTime: req.AppointmentTime,
+		AppointmentType: req.AppointmentType,
+		Reason:          req.Reason,
+	}, accountID)
+}
+
+// CancelAppointment cancels one of the patient's upcoming appointments
+func (s *PortalService) CancelAppointment(accountID, appointmentID uint, reason string) (*dto.AppointmentResponse, error) {
+	patientID, err := s.patientIDFor(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	appointment, err := s.appointmentRepo.FindByID(appointmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find appointment: %w", err)
+	}
+	if appointment == nil || appointment.PatientID != patientID {
+		return nil, ErrAppointmentNotFound
+	}
+
+	if appointment.Status != domain.AppointmentStatusScheduled && appointment.Status != domain.AppointmentStatusConfirmed {
+		return nil, ErrPortalAppointmentNotCancellable
+	}
+
+	start := s.clock.At(appointment.AppointmentDate, appointment.AppointmentTime)
+	if start.Sub(s.clock.Now()) < portalCancelCutoff {
+		return nil, ErrPortalCancelTooLate
+	}
+
+	return s.appointmentService.CancelPortalAppointment(appointmentID, reason)
+}
+
+// GetLabResults lists the patient's lab tests released to the portal
+func (s *PortalService) GetLabResults(accountID uint) ([]*dto.LabTestRequestListItem, error) {
+	patientID, err := s.patientIDFor(accountID)
+	if err != nil {
+		return nil, err
+	}
+	return s.labTestRequestService.GetPatientLabTests(patientID, map[string]interface{}{"portal_released": true})
+}
+
+// GetLabResult gets a released lab test with its results
+func (s *PortalService) GetLabResult(accountID, requestID uint) (*dto.LabTestRequestResponse, error) {
+	patientID, err := s.patientIDFor(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := s.labTestRequestService.GetLabTestRequestByID(requestID)
+	if err != nil {
+		return nil, err
+	}
+	if request.PatientID != patientID || request.PortalReleasedAt == nil {
+		return nil, ErrLabTestRequestNotFound
+	}
+	return request, nil
+}
+
+// GetImagingReports lists the patient's imaging reports released to the portal
+func (s *PortalService) GetImagingReports(accountID uint) ([]*dto.ImagingRequestListItem, error) {
+	patientID, err := s.patientIDFor(accountID)
+	if err != nil {
+		return nil, err
+	}
+	return s.imagingRequestService.GetPatientImagingRequests(patientID, map[string]interface{}{"portal_released": true})
+}
+
+// GetImagingReport gets a released imaging report
+func (s *PortalService) GetImagingReport(accountID, requestID uint) (*dto.ImagingRequestResponse, error) {
+	patientID, err := s.patientIDFor(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := s.imagingRequestService.GetImagingRequestByID(requestID)
+	if err != nil {
+		return nil, err
+	}
+	if request.PatientID != patientID || request.PortalReleasedAt == nil {
+		return nil, ErrImagingRequestNotFound
+	}
+	return request, nil
+}
+
+// GetPrescriptions lists the patient's prescriptions
+func (s *PortalService) GetPrescriptions(accountID uint) ([]*dto.PrescriptionListItem, error) {
+	patientID, err := s.patientIDFor(accountID)
+	if err != nil {
+		return nil, err
+	}
+	return s.prescriptionService.GetPatientPrescriptions(patientID, map[string]interface{}{})
+}
+
+// GetPrescription gets one of the patient's prescriptions
+func (s *PortalService) GetPrescription(accountID, prescriptionID uint) (*dto.PrescriptionResponse, error) {
+	patientID, err := s.patientIDFor(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	prescription, err := s.prescriptionService.GetPrescriptionByID(prescriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if prescription.PatientID != patientID {
+		return nil, ErrPrescriptionNotFound
+	}
+	return prescription, nil
+}
+
+// GetInvoices lists the patient's issued invoices
+func (s *PortalService) GetInvoices(accountID uint) ([]*dto.InvoiceListItem, error) {
+	patientID, err := s.patientIDFor(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	invoices, err := s.invoiceService.GetPatientInvoices(patientID)
+	if err != nil {
+		return nil, err
+	}
+
+	issued := make([]*dto.InvoiceListItem, 0, len(invoices))
+	for _, invoice := range invoices {
+		if invoice.Status != string(domain.InvoiceStatusDraft) {
+			issued = append(issued, invoice)
+		}
+	}
+	return issued, nil
+}
+
+// GetInvoice gets one of the patient's invoices
+func (s *PortalService) GetInvoice(accountID, invoiceID uint) (*dto.InvoiceResponse, error) {
+	patientID, err := s.patientIDFor(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice, err := s.invoiceService.GetInvoiceByID(invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if invoice.PatientID != patientID || invoice.Status == string(domain.InvoiceStatusDraft) {
+		return nil, ErrInvoiceNotFound
+	}
+	return invoice, nil
+}
+
+// Helper functions
+
+// patientIDFor resolves the patient of an active portal account, so deactivated
+// accounts lose access even while their tokens are still valid
+func (s *PortalService) patientIDFor(accountID uint) (uint, error) {
+	account, err := s.accountRepo.FindByID(accountID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find portal account: %w", err)
+	}
+	if account == nil || !account.IsActive || account.Patient == nil || account.Patient.IsDeceased {
+		return 0, ErrPortalAccountInactive
+	}
+	return account.PatientID, nil
+}
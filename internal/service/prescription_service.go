@@ -3,10 +3,10 @@ package service
 import (
 	"errors"
 	"fmt"
-	"time"
 
 	"github.com/minhtran/his/internal/domain"
 	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/pkg/clock"
 	"github.com/minhtran/his/internal/repository"
 )
 
@@ -20,6 +20,7 @@ type PrescriptionService struct {
 	prescriptionItemRepo *repository.PrescriptionItemRepository
 	medicationRepo       *repository.MedicationRepository
 	visitRepo            *repository.VisitRepository
+	clock                *clock.Clock
 }
 
 // NewPrescriptionService creates a new prescription service
@@ -28,12 +29,14 @@ func NewPrescriptionService(
 	prescriptionItemRepo *repository.PrescriptionItemRepository,
 	medicationRepo *repository.MedicationRepository,
 	visitRepo *repository.VisitRepository,
+	clk *clock.Clock,
 ) *PrescriptionService {
 	return &PrescriptionService{
 		prescriptionRepo:     prescriptionRepo,
 		prescriptionItemRepo: prescriptionItemRepo,
 		medicationRepo:       medicationRepo,
 		visitRepo:            visitRepo,
+		clock:                clk,
 	}
 }
 
@@ -47,6 +50,9 @@ func (s *PrescriptionService) CreatePrescription(req *dto.CreatePrescriptionRequ
 	if visit == nil {
 		return nil, ErrVisitNotFound
 	}
+	if visit.Patient != nil && visit.Patient.IsDeceased {
+		return nil, ErrPatientDeceased
+	}
 
 	// Validate medications exist
 	for _, item := range req.Items {
@@ -73,7 +79,7 @@ func (s *PrescriptionService) CreatePrescription(req *dto.CreatePrescriptionRequ
 		DoctorID:         visit.DoctorID,
 		DiagnosisID:      req.DiagnosisID,
 		Status:           domain.PrescriptionStatusPending,
-		PrescribedDate:   time.Now(),
+		PrescribedDate:   s.clock.Now(),
 		Notes:            req.Notes,
 		CreatedBy:        prescribedBy,
 	}
@@ -0,0 +1,592 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/minhtran/his/internal/domain"
+	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/pkg/clock"
+	"github.com/minhtran/his/internal/pkg/logger"
+	"github.com/minhtran/his/internal/repository"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	// ErrBookingHoldNotFound is returned when a hold token is unknown, expired or already used
+	ErrBookingHoldNotFound = errors.New("booking hold not found or expired")
+	// ErrBookingHoldNotVerified is returned when a hold is confirmed before its phone number is verified
+	ErrBookingHoldNotVerified = errors.New("phone number has not been verified")
+	// ErrPublicBookingOutOfRange is returned for dates outside the public booking window
+	ErrPublicBookingOutOfRange = errors.New("online booking is open from today for the next 30 days")
+	// ErrPublicBookingTooSoon is returned when a slot starts within the minimum lead time
+	ErrPublicBookingTooSoon = errors.New("appointments must be booked online at least 1 hour in advance")
+	// ErrPublicBookingLimit is returned when a phone number already has the maximum number of upcoming online bookings
+	ErrPublicBookingLimit = errors.New("maximum number of upcoming online bookings reached for this phone number")
+	// ErrPublicBookingRateLimited is returned when too many holds are placed from one phone number or address
+	ErrPublicBookingRateLimited = errors.New("too many booking attempts, try again later")
+)
+
+// Public booking rules
+const (
+	publicBookingDays        = 30 // Days ahead, including today, that can be booked
+	publicMinBookingLead     = time.Hour
+	publicMaxUpcomingByPhone = 2
+	publicHoldTTL            = 10 * time.Minute
+	publicHoldsPerPhoneDay   = 5
+	publicHoldsPerIPHour     = 20
+	publicCodeMaxSends       = 3
+	publicCodeResendInterval = time.Minute
+	publicCodeMaxAttempts    = 5
+	publicHoldTokenBytes     = 24
+)
+
+// PublicBookingService serves the public booking site. Visitors pick a free
+// slot, which is held for a few minutes while they verify their phone number
+// with a one-time code, then confirm with their details: an existing patient
+// with the same name, date of birth and phone number is booked, otherwise the
+// patient is pre-registered for staff to check at arrival.
+type PublicBookingService struct {
+	holdRepo           *repository.BookingHoldRepository
+	appointmentRepo    *repository.AppointmentRepository
+	patientRepo        *repository.PatientRepository
+	userRepo           *repository.UserRepository
+	departmentRepo     *repository.DepartmentRepository
+	auditRepo          *repository.AuditLogRepository
+	appointmentService *AppointmentService
+	otpSender          OTPSender
+	clock              *clock.Clock
+}
+
+// NewPublicBookingService creates a new public booking service
+func NewPublicBookingService(
+	holdRepo *repository.BookingHoldRepository,
+	appointmentRepo *repository.AppointmentRepository,
+	patientRepo *repository.PatientRepository,
+	userRepo *repository.UserRepository,
+	departmentRepo *repository.DepartmentRepository,
+	auditRepo *repository.AuditLogRepository,
+	appointmentService *AppointmentService,
+	otpSender OTPSender,
+	clk *clock.Clock,
+) *PublicBookingService {
+	return &PublicBookingService{
+		holdRepo:           holdRepo,
+		appointmentRepo:    appointmentRepo,
+		patientRepo:        patientRepo,
+		userRepo:           userRepo,
+		departmentRepo:     departmentRepo,
+		auditRepo:          auditRepo,
+		appointmentService: appointmentService,
+		otpSender:          otpSender,
+		clock:              clk,
+	}
+}
+
+// ListDepartments lists the active departments
+func (s *PublicBookingService) ListDepartments() ([]*dto.PublicDepartmentResponse, error) {
+	departments, err := s.departmentRepo.ListActive()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list departments: %w", err)
+	}
+
+	items := make([]*dto.PublicDepartmentResponse, len(departments))
+	for i, d := range departments {
+		items[i] = &dto.PublicDepartmentResponse{ID: d.ID, Code: d.Code, Name: d.Name}
+	}
+	return items, nil
+}
+
+// ListDoctors lists the doctors holding clinic sessions within the booking
+// window, optionally limited to a department
+func (s *PublicBookingService) ListDoctors(departmentID *uint) ([]*dto.PublicDoctorResponse, error) {
+	from := s.clock.Today()
+	doctors, err := s.userRepo.FindDoctorsWithSessions(departmentID, nil, from, from.AddDate(0, 0, publicBookingDays-1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find doctors: %w", err)
+	}
+
+	items := make([]*dto.PublicDoctorResponse, len(doctors))
+	for i, d := range doctors {
+		items[i] = &dto.PublicDoctorResponse{ID: d.ID, FullName: d.FullName, DepartmentID: d.DepartmentID}
+		if d.Department != nil {
+			items[i].DepartmentName = d.Department.Name
+		}
+	}
+	return items, nil
+}
+
+// GetAvailability gets the free slots of doctors over a date range within the
+// booking window; taken slots are left out. The range defaults to a week.
+func (s *PublicBookingService) GetAvailability(departmentID *uint, doctorIDs []uint, fromStr, toStr string) (*dto.AvailabilityResponse, error) {
+	today := s.clock.Today()
+	last := today.AddDate(0, 0, publicBookingDays-1)
+
+	from := today
+	if fromStr != "" {
+		parsed, err := time.Parse("2006-01-02", fromStr)
+		if err != nil {
+			return nil, ErrInvalidDateFormat
+		}
+		from = parsed
+	}
+	to := from.AddDate(0, 0, 6)
+	if toStr != "" {
+		parsed, err := time.Parse("2006-01-02", toStr)
+		if err != nil {
+			return nil, ErrInvalidDateFormat
+		}
+		to = parsed
+	} else if to.After(last) {
+		to = last
+	}
+	if from.Before(today) || to.After(last) {
+		return nil, ErrPublicBookingOutOfRange
+	}
+
+	availability, err := s.appointmentService.GetAvailability(departmentID, doctorIDs, from.Format("2006-01-02"), to.Format("2006-01-02"), 0, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, doctor := range availability.Doctors {
+		for _, day := range doctor.Days {
+			free := make([]*dto.TimeSlot, 0, day.AvailableSlots)
+			for _, slot := range day.Slots {
+				if slot.Available {
+					free = append(free, slot)
+				}
+			}
+			day.Slots = free
+		}
+	}
+	return availability, nil
+}
+
+// CreateHold holds a free slot for a phone number and sends the number a
+// one-time code. Holds are limited per phone number and client address, and
+// a new hold releases the phone number's previous one.
+func (s *PublicBookingService) CreateHold(req *dto.CreateBookingHoldRequest, clientIP string) (*dto.BookingHoldResponse, error) {
+	phoneNumber := normalizePhoneNumber(req.PhoneNumber)
+
+	date, err := time.Parse("2006-01-02", req.AppointmentDate)
+	if err != nil {
+		return nil, ErrInvalidDateFormat
+	}
+	at, err := time.Parse("15:04", req.AppointmentTime)
+	if err != nil {
+		return nil, ErrInvalidAppointmentTime
+	}
+
+	today := s.clock.Today()
+	if date.Before(today) || date.After(today.AddDate(0, 0, publicBookingDays-1)) {
+		return nil, ErrPublicBookingOutOfRange
+	}
+	now := s.clock.Now()
+	start := s.clock.At(date, at)
+	if start.Before(now.Add(publicMinBookingLead)) {
+		return nil, ErrPublicBookingTooSoon
+	}
+
+	if err := s.checkHoldLimits(phoneNumber, clientIP, now); err != nil {
+		return nil, err
+	}
+
+	doctors, err := s.userRepo.FindDoctorsWithSessions(nil, []uint{req.DoctorID}, date, date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find doctor: %w", err)
+	}
+	if len(doctors) == 0 {
+		return nil, ErrDoctorNotFound
+	}
+	doctor := doctors[0]
+
+	session, duration, err := s.appointmentService.findClinicSession(req.DoctorID, date, at, 0)
+	if err != nil {
+		return nil, err
+	}
+	limit, capacity, err := s.appointmentService.sessionLimit(req.DoctorID, session)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.holdRepo.ReleaseActiveByPhone(phoneNumber, now); err != nil {
+		return nil, fmt.Errorf("failed to release previous holds: %w", err)
+	}
+
+	token, err := generateHoldToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate hold token: %w", err)
+	}
+
+	// The hold never outlasts the slot itself
+	expiresAt := now.Add(publicHoldTTL)
+	if start.Before(expiresAt) {
+		expiresAt = start
+	}
+	hold := &domain.BookingHold{
+		TokenHash:       hashHoldToken(token),
+		DoctorID:        req.DoctorID,
+		Doctor:          doctor,
+		AppointmentDate: date,
+		AppointmentTime: at,
+		DurationMinutes: duration,
+		PhoneNumber:     phoneNumber,
+		ClientIP:        clientIP,
+		Status:          domain.BookingHoldStatusPending,
+		ExpiresAt:       expiresAt,
+	}
+
+	// Check and hold the slot under the doctor's booking lock, as bookings do
+	err = s.appointmentRepo.WithDoctorLock(req.DoctorID, nil, func(repo *repository.AppointmentRepository, _ *repository.ResourceRepository) error {
+		if err := checkSessionCapacity(repo, session, limit, req.DoctorID, date, nil); err != nil {
+			return err
+		}
+		available, err := repo.CheckTimeSlotCapacity(req.DoctorID, date, at, duration, capacity, nil)
+		if err != nil {
+			return fmt.Errorf("failed to check time slot: %w", err)
+		}
+		if !available {
+			return ErrTimeSlotNotAvailable
+		}
+		if err := repo.CreateBookingHold(hold); err != nil {
+			return fmt.Errorf("failed to hold time slot: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.sendCode(hold, now); err != nil {
+		s.release(hold)
+		return nil, err
+	}
+
+	resp := s.toHoldResponse(hold)
+	resp.HoldToken = token
+	return resp, nil
+}
+
+// GetHold gets a hold that still blocks its slot
+func (s *PublicBookingService) GetHold(token string) (*dto.BookingHoldResponse, error) {
+	hold, err := s.findActiveHold(token)
+	if err != nil {
+		return nil, err
+	}
+	return s.toHoldResponse(hold), nil
+}
+
+// ResendCode sends a new one-time code for a hold awaiting verification
+func (s *PublicBookingService) ResendCode(token string) error {
+	hold, err := s.findActiveHold(token)
+	if err != nil {
+		return err
+	}
+	if hold.Status != domain.BookingHoldStatusPending {
+		return nil
+	}
+
+	now := s.clock.Now()
+	if hold.CodesSent >= publicCodeMaxSends || (hold.CodeSentAt != nil && now.Sub(*hold.CodeSentAt) < publicCodeResendInterval) {
+		return ErrOTPRateLimited
+	}
+	return s.sendCode(hold, now)
+}
+
+// VerifyPhone verifies a hold's phone number with its one-time code. Too many
+// wrong codes release the hold. A verified hold is kept for another hold
+// period so the visitor can enter their details.
+func (s *PublicBookingService) VerifyPhone(token string, req *dto.VerifyBookingHoldRequest) (*dto.BookingHoldResponse, error) {
+	hold, err := s.findActiveHold(token)
+	if err != nil {
+		return nil, err
+	}
+	if hold.Status == domain.BookingHoldStatusVerified {
+		return s.toHoldResponse(hold), nil
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(hold.CodeHash), []byte(req.Code)) != nil {
+		hold.Attempts++
+		if hold.Attempts >= publicCodeMaxAttempts {
+			hold.Status = domain.BookingHoldStatusReleased
+		}
+		if err := s.holdRepo.Update(hold); err != nil {
+			return nil, fmt.Errorf("failed to update booking hold: %w", err)
+		}
+		return nil, ErrInvalidOTP
+	}
+
+	now := s.clock.Now()
+	hold.Status = domain.BookingHoldStatusVerified
+	hold.VerifiedAt = &now
+	if extended := now.Add(publicHoldTTL); extended.Before(s.clock.At(clock.Day(hold.AppointmentDate), hold.AppointmentTime)) {
+		hold.ExpiresAt = extended
+	}
+	if err := s.holdRepo.Update(hold); err != nil {
+		return nil, fmt.Errorf("failed to update booking hold: %w", err)
+	}
+	return s.toHoldResponse(hold), nil
+}
+
+// ReleaseHold gives up a hold so the slot is free again
+func (s *PublicBookingService) ReleaseHold(token string) error {
+	hold, err := s.findActiveHold(token)
+	if err != nil {
+		return err
+	}
+	hold.Status = domain.BookingHoldStatusReleased
+	if err := s.holdRepo.Update(hold); err != nil {
+		return fmt.Errorf("failed to release booking hold: %w", err)
+	}
+	return nil
+}
+
+// ConfirmBooking books the slot of a verified hold for the patient with the
+// given details and the hold's phone number, pre-registering the patient
+// when no record matches
+func (s *PublicBookingService) ConfirmBooking(token string, req *dto.ConfirmBookingRequest, clientIP string) (*dto.PublicBookingResponse, error) {
+	hold, err := s.findActiveHold(token)
+	if err != nil {
+		return nil, err
+	}
+	if hold.Status != domain.BookingHoldStatusVerified {
+		return nil, ErrBookingHoldNotVerified
+	}
+
+	dob, err := time.Parse("2006-01-02", req.DateOfBirth)
+	if err != nil {
+		return nil, ErrInvalidDateFormat
+	}
+	if dob.After(s.clock.Today()) {
+		return nil, fmt.Errorf("%w: date of birth is in the future", ErrInvalidDateFormat)
+	}
+
+	upcoming, err := s.holdRepo.CountUpcomingByPhone(hold.PhoneNumber, s.clock.Today())
+	if err != nil {
+		return nil, fmt.Errorf("failed to count online bookings: %w", err)
+	}
+	if upcoming >= publicMaxUpcomingByPhone {
+		return nil, ErrPublicBookingLimit
+	}
+
+	patient, preRegistered, err := s.matchPatient(req, dob, hold.PhoneNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	appointmentType := req.AppointmentType
+	if appointmentType == "" {
+		appointmentType = string(domain.AppointmentTypeConsultation)
+	}
+
+	// Confirm the hold and book its slot in one transaction under the doctor's booking lock
+	hold.PatientID = &patient.ID
+	appointment, err := s.appointmentService.ScheduleOnlineAppointment(&dto.CreateAppointmentRequest{
+		PatientID:       patient.ID,
+		DoctorID:        hold.DoctorID,
+		AppointmentDate: hold.AppointmentDate.Format("2006-01-02"),
+		AppointmentTime: hold.AppointmentTime.Format("15:04"),
+		DurationMinutes: hold.DurationMinutes,
+		AppointmentType: appointmentType,
+		Reason:          req.Reason,
+	}, hold)
+	if err != nil {
+		// A patient pre-registered for this booking is left without one
+		if preRegistered {
+			if discardErr := s.patientRepo.DiscardPreRegistered(patient.ID); discardErr != nil {
+				logger.Error("Failed to discard pre-registered patient", zap.Uint("patient_id", patient.ID), zap.Error(discardErr))
+			}
+		}
+		return nil, err
+	}
+
+	// Audit log
+	s.auditRepo.Create(&domain.AuditLog{
+		Action:     domain.AuditActionCreate,
+		Resource:   "Appointment",
+		ResourceID: appointment.AppointmentCode,
+		Details: domain.AuditDetails{
+			"booking_source": string(domain.BookingSourceOnline),
+			"hold_id":        hold.ID,
+			"patient_code":   patient.PatientCode,
+			"pre_registered": preRegistered,
+		},
+		IPAddress: clientIP,
+	})
+
+	resp := &dto.PublicBookingResponse{
+		AppointmentCode: appointment.AppointmentCode,
+		DoctorName:      appointment.DoctorName,
+		AppointmentDate: appointment.AppointmentDate,
+		AppointmentTime: appointment.AppointmentTime,
+		DurationMinutes: appointment.DurationMinutes,
+		Status:          appointment.Status,
+	}
+	if hold.Doctor != nil && hold.Doctor.Department != nil {
+		resp.DepartmentName = hold.Doctor.Department.Name
+	}
+	return resp, nil
+}
+
+// checkHoldLimits checks the phone number's and client address's hold rates
+// and the phone number's upcoming online bookings
+func (s *PublicBookingService) checkHoldLimits(phoneNumber, clientIP string, now time.Time) error {
+	byIP, err := s.holdRepo.CountByIPSince(clientIP, now.Add(-time.Hour))
+	if err != nil {
+		return fmt.Errorf("failed to count booking holds: %w", err)
+	}
+	byPhone, err := s.holdRepo.CountByPhoneSince(phoneNumber, now.Add(-24*time.Hour))
+	if err != nil {
+		return fmt.Errorf("failed to count booking holds: %w", err)
+	}
+	if byIP >= publicHoldsPerIPHour || byPhone >= publicHoldsPerPhoneDay {
+		return ErrPublicBookingRateLimited
+	}
+
+	upcoming, err := s.holdRepo.CountUpcomingByPhone(phoneNumber, s.clock.Today())
+	if err != nil {
+		return fmt.Errorf("failed to count online bookings: %w", err)
+	}
+	if upcoming >= publicMaxUpcomingByPhone {
+		return ErrPublicBookingLimit
+	}
+	return nil
+}
+
+// matchPatient finds the patient with the visitor's name, date of birth and
+// phone number, or pre-registers one; it reports whether the patient is new
+func (s *PublicBookingService) matchPatient(req *dto.ConfirmBookingRequest, dob time.Time, phoneNumber string) (*domain.Patient, bool, error) {
+	patient, err := s.patientRepo.FindPossibleDuplicate(req.FirstName, req.LastName, dob, phoneNumber)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to find patient: %w", err)
+	}
+	if patient != nil {
+		if patient.IsDeceased {
+			return nil, false, ErrPatientDeceased
+		}
+		return patient, false, nil
+	}
+
+	patientCode, err := s.patientRepo.GeneratePatientCode()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to generate patient code: %w", err)
+	}
+	patient = &domain.Patient{
+		PatientCode:       patientCode,
+		FirstName:         req.FirstName,
+		LastName:          req.LastName,
+		DateOfBirth:       dob,
+		Gender:            domain.Gender(req.Gender),
+		PhoneNumber:       phoneNumber,
+		Email:             req.Email,
+		Country:           "Vietnam",
+		PreferredLanguage: "vi",
+		IsActive:          true,
+		PreRegistered:     true,
+	}
+	if err := s.patientRepo.Create(patient); err != nil {
+		return nil, false, fmt.Errorf("failed to pre-register patient: %w", err)
+	}
+	return patient, true, nil
+}
+
+// sendCode issues a new one-time code for a hold and sends it to its phone number
+func (s *PublicBookingService) sendCode(hold *domain.BookingHold, now time.Time) error {
+	code, err := generateOTPCode(portalOTPLength)
+	if err != nil {
+		return fmt.Errorf("failed to generate one-time code: %w", err)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(code), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash one-time code: %w", err)
+	}
+
+	hold.CodeHash = string(hash)
+	hold.CodeSentAt = &now
+	hold.CodesSent++
+	hold.Attempts = 0
+	if err := s.holdRepo.Update(hold); err != nil {
+		return fmt.Errorf("failed to store one-time code: %w", err)
+	}
+
+	if err := s.otpSender.SendOTP(hold.PhoneNumber, code); err != nil {
+		return fmt.Errorf("failed to send one-time code: %w", err)
+	}
+	return nil
+}
+
+// release frees a hold's slot after a failure, logging rather than returning errors
+func (s *PublicBookingService) release(hold *domain.BookingHold) {
+	hold.Status = domain.BookingHoldStatusReleased
+	if err := s.holdRepo.Update(hold); err != nil {
+		logger.Error("Failed to release booking hold", zap.Uint("hold_id", hold.ID), zap.Error(err))
+	}
+}
+
+// findActiveHold finds a hold by its token and checks that it still blocks its slot
+func (s *PublicBookingService) findActiveHold(token string) (*domain.BookingHold, error) {
+	if token == "" {
+		return nil, ErrBookingHoldNotFound
+	}
+	hold, err := s.holdRepo.FindByTokenHash(hashHoldToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find booking hold: %w", err)
+	}
+	if hold == nil || !hold.IsActive(s.clock.Now()) {
+		return nil, ErrBookingHoldNotFound
+	}
+	return hold, nil
+}
+
+func (s *PublicBookingService) toHoldResponse(hold *domain.BookingHold) *dto.BookingHoldResponse {
+	resp := &dto.BookingHoldResponse{
+		DoctorID:        hold.DoctorID,
+		AppointmentDate: hold.AppointmentDate.Format("2006-01-02"),
+		AppointmentTime: hold.AppointmentTime.Format("15:04"),
+		DurationMinutes: hold.DurationMinutes,
+		PhoneNumber:     maskPhoneNumber(hold.PhoneNumber),
+		Status:          string(hold.Status),
+		ExpiresAt:       hold.ExpiresAt,
+	}
+	if hold.Doctor != nil {
+		resp.DoctorName = hold.Doctor.FullName
+		if hold.Doctor.Department != nil {
+			resp.DepartmentName = hold.Doctor.Department.Name
+		}
+	}
+	return resp
+}
+
+// maskPhoneNumber hides all but the first three and last three digits
+func maskPhoneNumber(phone string) string {
+	if len(phone) <= 6 {
+		return phone
+	}
+	masked := []byte(phone)
+	for i := 3; i < len(masked)-3; i++ {
+		masked[i] = '*'
+	}
+	return string(masked)
+}
+
+// generateHoldToken returns a random URL-safe hold token
+func generateHoldToken() (string, error) {
+	b := make([]byte, publicHoldTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashHoldToken returns the hex SHA-256 of a hold token, which is what is stored
+func hashHoldToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,710 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/minhtran/his/internal/domain"
+	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/pkg/broadcast"
+	"github.com/minhtran/his/internal/pkg/clock"
+	"github.com/minhtran/his/internal/pkg/logger"
+	"github.com/minhtran/his/internal/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrQueueNotFound is returned when a queue is not found
+	ErrQueueNotFound = errors.New("queue not found")
+	// ErrQueueCodeExists is returned when another queue already uses the code
+	ErrQueueCodeExists = errors.New("queue code already exists")
+	// ErrQueueInactive is returned when issuing tickets in or calling from a queue that is out of use
+	ErrQueueInactive = errors.New("queue is not active")
+	// ErrQueueTicketNotFound is returned when a queue ticket is not found
+	ErrQueueTicketNotFound = errors.New("queue ticket not found")
+	// ErrQueueEmpty is returned when calling the next patient of a queue nobody waits in
+	ErrQueueEmpty = errors.New("no patients waiting in the queue")
+	// ErrVisitAlreadyQueued is returned when a visit already holds a ticket in a queue
+	ErrVisitAlreadyQueued = errors.New("visit already has a ticket in a queue")
+	// ErrVisitNotWaiting is returned when queueing a visit that is no longer waiting
+	ErrVisitNotWaiting = errors.New("only waiting visits can join a queue")
+	// ErrInvalidTicketTransition is returned when a ticket action does not apply to its status
+	ErrInvalidTicketTransition = errors.New("invalid queue ticket status transition")
+)
+
+// Priority lanes follow the Law on Medical Examination and Treatment, which
+// puts the elderly and young children ahead of other outpatients
+const (
+	queueElderlyAge  = 75 // Patients this old or older join the elderly lane
+	queueChildAge    = 6  // Patients younger than this join the child lane
+	queueDisplayNext = 5  // Waiting tickets shown on displays
+
+	queueEmergencyAcuity = 2 // Visits triaged this urgent or more join the emergency lane
+)
+
+// QueueService manages outpatient queues: daily numbered tickets issued at
+// check-in, priority lanes, calling patients to the room and the live state
+// shown on waiting-room displays
+type QueueService struct {
+	queueRepo      *repository.QueueRepository
+	visitRepo      *repository.VisitRepository
+	userRepo       *repository.UserRepository
+	resourceRepo   *repository.ResourceRepository
+	departmentRepo *repository.DepartmentRepository
+	auditRepo      *repository.AuditLogRepository
+	hub            *broadcast.Hub
+	clock          *clock.Clock
+}
+
+// NewQueueService creates a new queue service. Visits checked in with a
+// doctor who has a queue join it, move up it when triaged, and leave it when
+// they are closed.
+func NewQueueService(
+	queueRepo *repository.QueueRepository,
+	visitRepo *repository.VisitRepository,
+	userRepo *repository.UserRepository,
+	resourceRepo *repository.ResourceRepository,
+	departmentRepo *repository.DepartmentRepository,
+	auditRepo *repository.AuditLogRepository,
+	visitService *VisitService,
+	triageService *TriageService,
+	clk *clock.Clock,
+) *QueueService {
+	s := &QueueService{
+		queueRepo:      queueRepo,
+		visitRepo:      visitRepo,
+		userRepo:       userRepo,
+		resourceRepo:   resourceRepo,
+		departmentRepo: departmentRepo,
+		auditRepo:      auditRepo,
+		hub:            broadcast.NewHub(),
+		clock:          clk,
+	}
+	visitService.OnCheckedIn(s.handleCheckedIn)
+	visitService.OnStarted(s.handleStarted)
+	visitService.OnClosed(s.handleClosed)
+	triageService.OnTriaged(s.handleTriaged)
+	return s
+}
+
+// CreateQueue opens a queue for a room or doctor
+func (s *QueueService) CreateQueue(req *dto.CreateQueueRequest, userID uint) (*dto.QueueResponse, error) {
+	existing, err := s.queueRepo.FindByCode(req.Code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check queue code: %w", err)
+	}
+	if existing != nil {
+		return nil, ErrQueueCodeExists
+	}
+	if err := s.ensureReferences(req.DoctorID, req.RoomID, req.DepartmentID); err != nil {
+		return nil, err
+	}
+
+	queue := &domain.ClinicQueue{
+		Code:         req.Code,
+		Name:         req.Name,
+		TicketPrefix: req.TicketPrefix,
+		DoctorID:     req.DoctorID,
+		RoomID:       req.RoomID,
+		DepartmentID: req.DepartmentID,
+		IsActive:     true,
+		CreatedBy:    userID,
+		UpdatedBy:    userID,
+	}
+	if err := s.queueRepo.Create(queue); err != nil {
+		return nil, fmt.Errorf("failed to create queue: %w", err)
+	}
+
+	// Audit log
+	s.auditRepo.Create(&domain.AuditLog{
+		UserID:     &userID,
+		Action:     domain.AuditActionCreate,
+		Resource:   "ClinicQueue",
+		ResourceID: fmt.Sprintf("%d", queue.ID),
+		Details: domain.AuditDetails{
+			"code": queue.Code,
+			"name": queue.Name,
+		},
+	})
+
+	return s.GetQueue(queue.ID)
+}
+
+// GetQueue gets a queue
+func (s *QueueService) GetQueue(id uint) (*dto.QueueResponse, error) {
+	queue, err := s.findQueue(id)
+	if err != nil {
+		return nil, err
+	}
+	return toQueueResponse(queue), nil
+}
+
+// ListQueues lists queues with filters
+func (s *QueueService) ListQueues(filters map[string]interface{}) ([]*dto.QueueResponse, error) {
+	queues, err := s.queueRepo.List(filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list queues: %w", err)
+	}
+
+	items := make([]*dto.QueueResponse, len(queues))
+	for i, q := range queues {
+		items[i] = toQueueResponse(q)
+	}
+	return items, nil
+}
+
+// UpdateQueue updates a queue or takes it out of use
+func (s *QueueService) UpdateQueue(id uint, req *dto.UpdateQueueRequest, userID uint) (*dto.QueueResponse, error) {
+	queue, err := s.findQueue(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureReferences(req.DoctorID, req.RoomID, req.DepartmentID); err != nil {
+		return nil, err
+	}
+
+	if req.Name != "" {
+		queue.Name = req.Name
+	}
+	if req.TicketPrefix != "" {
+		queue.TicketPrefix = req.TicketPrefix
+	}
+	if req.DoctorID != nil {
+		queue.DoctorID = req.DoctorID
+	}
+	if req.RoomID != nil {
+		queue.RoomID = req.RoomID
+	}
+	if req.DepartmentID != nil {
+		queue.DepartmentID = req.DepartmentID
+	}
+	if req.IsActive != nil {
+		queue.IsActive = *req.IsActive
+	}
+	queue.UpdatedBy = userID
+
+	if err := s.queueRepo.Update(queue); err != nil {
+		return nil, fmt.Errorf("failed to update queue: %w", err)
+	}
+
+	// Audit log
+	s.auditRepo.Create(&domain.AuditLog{
+		UserID:     &userID,
+		Action:     domain.AuditActionUpdate,
+		Resource:   "ClinicQueue",
+		ResourceID: fmt.Sprintf("%d", queue.ID),
+		Details: domain.AuditDetails{
+			"code":      queue.Code,
+			"is_active": queue.IsActive,
+		},
+	})
+
+	s.publish(queue.ID)
+	return s.GetQueue(queue.ID)
+}
+
+// IssueTicket puts a waiting visit in a queue with today's next ticket
+// number. The lane defaults from the visit type and the patient's age.
+func (s *QueueService) IssueTicket(queueID uint, req *dto.IssueQueueTicketRequest, userID uint) (*dto.QueueTicketResponse, error) {
+	queue, err := s.findQueue(queueID)
+	if err != nil {
+		return nil, err
+	}
+
+	visit, err := s.visitRepo.FindByID(req.VisitID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find visit: %w", err)
+	}
+	if visit == nil {
+		return nil, ErrVisitNotFound
+	}
+
+	ticket, err := s.issue(queue, visit, domain.QueueLane(req.Lane), userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.toTicketResponse(ticket), nil
+}
+
+// CallNext calls the next waiting patient of a queue to the room: emergency
+// tickets first, then the elderly and child lane, then the rest, each by
+// acuity and waiting time. Patients still called from before are taken as served.
+func (s *QueueService) CallNext(queueID uint, userID uint) (*dto.QueueTicketResponse, error) {
+	queue, err := s.findQueue(queueID)
+	if err != nil {
+		return nil, err
+	}
+	if !queue.IsActive {
+		return nil, ErrQueueInactive
+	}
+
+	var next *domain.QueueTicket
+	now := s.clock.Now()
+	today := s.clock.Today()
+	err = s.queueRepo.WithQueueLock(queue.ID, func(repo *repository.QueueRepository) error {
+		tickets, err := repo.FindTickets(queue.ID, today, []domain.QueueTicketStatus{domain.QueueTicketStatusWaiting, domain.QueueTicketStatusCalled})
+		if err != nil {
+			return fmt.Errorf("failed to find tickets: %w", err)
+		}
+
+		var waiting []*domain.QueueTicket
+		for _, t := range tickets {
+			if t.Status == domain.QueueTicketStatusCalled {
+				t.Status = domain.QueueTicketStatusServed
+				t.ServedAt = &now
+				if err := repo.UpdateTicket(t); err != nil {
+					return fmt.Errorf("failed to update ticket: %w", err)
+				}
+				continue
+			}
+			waiting = append(waiting, t)
+		}
+		if len(waiting) == 0 {
+			return ErrQueueEmpty
+		}
+
+		sortQueueTickets(waiting, now)
+		next = waiting[0]
+		next.Status = domain.QueueTicketStatusCalled
+		next.CalledAt = &now
+		next.CalledBy = &userID
+		next.CallCount = 1
+		if err := repo.UpdateTicket(next); err != nil {
+			return fmt.Errorf("failed to update ticket: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.publish(queue.ID)
+	return s.toTicketResponse(next), nil
+}
+
+// RecallTicket calls a called patient again, e.g. when they did not hear the first call
+func (s *QueueService) RecallTicket(ticketID uint, userID uint) (*dto.QueueTicketResponse, error) {
+	return s.moveTicket(ticketID, domain.QueueTicketStatusCalled, func(t *domain.QueueTicket) {
+		now := s.clock.Now()
+		t.CalledAt = &now
+		t.CalledBy = &userID
+		t.CallCount++
+	})
+}
+
+// SkipTicket sets aside a called patient who did not come to the room
+func (s *QueueService) SkipTicket(ticketID uint, userID uint) (*dto.QueueTicketResponse, error) {
+	return s.moveTicket(ticketID, domain.QueueTicketStatusCalled, func(t *domain.QueueTicket) {
+		now := s.clock.Now()
+		t.Status = domain.QueueTicketStatusSkipped
+		t.SkippedAt = &now
+	})
+}
+
+// RequeueTicket puts a skipped patient who turned up back in line; the
+// ticket keeps its number and so its place
+func (s *QueueService) RequeueTicket(ticketID uint, userID uint) (*dto.QueueTicketResponse, error) {
+	return s.moveTicket(ticketID, domain.QueueTicketStatusSkipped, func(t *domain.QueueTicket) {
+		t.Status = domain.QueueTicketStatusWaiting
+	})
+}
+
+// GetBoard gets the state of a queue on a date, today by default
+func (s *QueueService) GetBoard(queueID uint, dateStr string) (*dto.QueueBoardResponse, error) {
+	queue, err := s.findQueue(queueID)
+	if err != nil {
+		return nil, err
+	}
+
+	date := s.clock.Today()
+	if dateStr != "" {
+		date, err = time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			return nil, ErrInvalidDateFormat
+		}
+	}
+
+	tickets, err := s.queueRepo.FindTickets(queue.ID, date, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tickets: %w", err)
+	}
+	sortQueueTickets(tickets, s.clock.Now())
+
+	board := &dto.QueueBoardResponse{
+		Queue:   toQueueResponse(queue),
+		Date:    date.Format("2006-01-02"),
+		Called:  []*dto.QueueTicketResponse{},
+		Waiting: []*dto.QueueTicketResponse{},
+		Skipped: []*dto.QueueTicketResponse{},
+	}
+	for _, t := range tickets {
+		switch t.Status {
+		case domain.QueueTicketStatusCalled:
+			board.Called = append(board.Called, s.toTicketResponse(t))
+		case domain.QueueTicketStatusWaiting:
+			board.Waiting = append(board.Waiting, s.toTicketResponse(t))
+		case domain.QueueTicketStatusSkipped:
+			board.Skipped = append(board.Skipped, s.toTicketResponse(t))
+		case domain.QueueTicketStatusServed:
+			board.Served++
+		}
+	}
+	return board, nil
+}
+
+// GetDisplay gets what waiting-room displays show for a queue today
+func (s *QueueService) GetDisplay(queueID uint) (*dto.QueueDisplayResponse, error) {
+	queue, err := s.findQueue(queueID)
+	if err != nil {
+		return nil, err
+	}
+	return s.display(queue)
+}
+
+// Subscribe subscribes to a queue's display updates, each a
+// *dto.QueueDisplayResponse. The returned function unsubscribes.
+func (s *QueueService) Subscribe(queueID uint) (<-chan interface{}, func()) {
+	return s.hub.Subscribe(queueTopic(queueID))
+}
+
+// handleCheckedIn issues a visit a ticket in its doctor's queue, if the doctor has one
+func (s *QueueService) handleCheckedIn(visit domain.Visit) {
+	queue, err := s.queueRepo.FindActiveByDoctor(visit.DoctorID)
+	if err != nil {
+		logger.Error("Failed to find doctor's queue", zap.Uint("visit_id", visit.ID), zap.Error(err))
+		return
+	}
+	if queue == nil {
+		return
+	}
+	if _, err := s.issue(queue, &visit, "", visit.CreatedBy); err != nil {
+		logger.Error("Failed to issue queue ticket", zap.Uint("visit_id", visit.ID), zap.Error(err))
+	}
+}
+
+// handleTriaged reorders a triaged visit's ticket by its new acuity level.
+// Emergent patients move to the emergency lane.
+func (s *QueueService) handleTriaged(triage domain.VisitTriage) {
+	ticket, err := s.queueRepo.FindOpenTicketByVisit(triage.VisitID)
+	if err != nil {
+		logger.Error("Failed to find visit's queue ticket", zap.Uint("visit_id", triage.VisitID), zap.Error(err))
+		return
+	}
+	if ticket == nil {
+		return
+	}
+
+	level := triage.AcuityLevel
+	ticket.AcuityLevel = &level
+	if level <= queueEmergencyAcuity {
+		ticket.Lane = domain.QueueLaneEmergency
+	}
+	if err := s.queueRepo.UpdateTicket(ticket); err != nil {
+		logger.Error("Failed to update queue ticket", zap.Uint("ticket_id", ticket.ID), zap.Error(err))
+		return
+	}
+	s.publish(ticket.QueueID)
+}
+
+// handleStarted marks a visit's ticket served once its consultation starts
+func (s *QueueService) handleStarted(visit domain.Visit) {
+	ticket, err := s.queueRepo.FindOpenTicketByVisit(visit.ID)
+	if err != nil {
+		logger.Error("Failed to find visit's queue ticket", zap.Uint("visit_id", visit.ID), zap.Error(err))
+		return
+	}
+	if ticket == nil {
+		return
+	}
+
+	now := s.clock.Now()
+	ticket.Status = domain.QueueTicketStatusServed
+	ticket.ServedAt = &now
+	if err := s.queueRepo.UpdateTicket(ticket); err != nil {
+		logger.Error("Failed to serve queue ticket", zap.Uint("ticket_id", ticket.ID), zap.Error(err))
+		return
+	}
+	s.publish(ticket.QueueID)
+}
+
+// handleClosed takes a completed or cancelled visit out of its queue
+func (s *QueueService) handleClosed(visit domain.Visit) {
+	ticket, err := s.queueRepo.FindOpenTicketByVisit(visit.ID)
+	if err != nil {
+		logger.Error("Failed to find visit's queue ticket", zap.Uint("visit_id", visit.ID), zap.Error(err))
+		return
+	}
+	if ticket == nil {
+		return
+	}
+
+	now := s.clock.Now()
+	if visit.Status == domain.VisitStatusCancelled {
+		ticket.Status = domain.QueueTicketStatusCancelled
+	} else {
+		ticket.Status = domain.QueueTicketStatusServed
+		ticket.ServedAt = &now
+	}
+	if err := s.queueRepo.UpdateTicket(ticket); err != nil {
+		logger.Error("Failed to close queue ticket", zap.Uint("ticket_id", ticket.ID), zap.Error(err))
+		return
+	}
+	s.publish(ticket.QueueID)
+}
+
+// issue numbers a ticket for a visit in a queue under the queue's lock
+func (s *QueueService) issue(queue *domain.ClinicQueue, visit *domain.Visit, lane domain.QueueLane, userID uint) (*domain.QueueTicket, error) {
+	if !queue.IsActive {
+		return nil, ErrQueueInactive
+	}
+	if visit.Status != domain.VisitStatusWaiting {
+		return nil, ErrVisitNotWaiting
+	}
+	if lane == "" {
+		lane = s.defaultLane(visit)
+	}
+
+	today := s.clock.Today()
+	ticket := &domain.QueueTicket{
+		QueueID:     queue.ID,
+		QueueDate:   today,
+		VisitID:     &visit.ID,
+		PatientID:   visit.PatientID,
+		Patient:     visit.Patient,
+		Lane:        lane,
+		AcuityLevel: visit.AcuityLevel,
+		Status:      domain.QueueTicketStatusWaiting,
+		IssuedBy:    userID,
+	}
+
+	err := s.queueRepo.WithQueueLock(queue.ID, func(repo *repository.QueueRepository) error {
+		existing, err := repo.FindOpenTicketByVisit(visit.ID)
+		if err != nil {
+			return fmt.Errorf("failed to check visit's ticket: %w", err)
+		}
+		if existing != nil {
+			return ErrVisitAlreadyQueued
+		}
+
+		number, err := repo.NextTicketNumber(queue.ID, today)
+		if err != nil {
+			return fmt.Errorf("failed to number ticket: %w", err)
+		}
+		ticket.TicketNumber = number
+		ticket.TicketCode = fmt.Sprintf("%s%03d", queue.TicketPrefix, number)
+		if err := repo.CreateTicket(ticket); err != nil {
+			return fmt.Errorf("failed to create ticket: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.publish(queue.ID)
+	return ticket, nil
+}
+
+// moveTicket applies a call action to a ticket of today that is in the given status
+func (s *QueueService) moveTicket(ticketID uint, from domain.QueueTicketStatus, apply func(t *domain.QueueTicket)) (*dto.QueueTicketResponse, error) {
+	ticket, err := s.queueRepo.FindTicketByID(ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find ticket: %w", err)
+	}
+	if ticket == nil {
+		return nil, ErrQueueTicketNotFound
+	}
+	if ticket.Status != from || !clock.Day(ticket.QueueDate).Equal(s.clock.Today()) {
+		return nil, fmt.Errorf("%w: ticket is %s", ErrInvalidTicketTransition, ticket.Status)
+	}
+
+	apply(ticket)
+	if err := s.queueRepo.UpdateTicket(ticket); err != nil {
+		return nil, fmt.Errorf("failed to update ticket: %w", err)
+	}
+
+	s.publish(ticket.QueueID)
+	return s.toTicketResponse(ticket), nil
+}
+
+// defaultLane puts emergency and emergent visits in the emergency lane and
+// elderly patients and young children in the priority lane
+func (s *QueueService) defaultLane(visit *domain.Visit) domain.QueueLane {
+	if visit.VisitType == domain.VisitTypeEmergency || (visit.AcuityLevel != nil && *visit.AcuityLevel <= queueEmergencyAcuity) {
+		return domain.QueueLaneEmergency
+	}
+	if visit.Patient != nil {
+		age := clock.Age(clock.Day(visit.Patient.DateOfBirth), s.clock.Today())
+		switch {
+		case age >= queueElderlyAge:
+			return domain.QueueLaneElderly
+		case age < queueChildAge:
+			return domain.QueueLaneChild
+		}
+	}
+	return domain.QueueLaneNormal
+}
+
+// publish sends a queue's display state to its subscribers
+func (s *QueueService) publish(queueID uint) {
+	topic := queueTopic(queueID)
+	queue, err := s.queueRepo.FindByID(queueID)
+	if err != nil || queue == nil {
+		logger.Error("Failed to load queue for display", zap.Uint("queue_id", queueID), zap.Error(err))
+		return
+	}
+	display, err := s.display(queue)
+	if err != nil {
+		logger.Error("Failed to build queue display", zap.Uint("queue_id", queueID), zap.Error(err))
+		return
+	}
+	s.hub.Publish(topic, display)
+}
+
+// display builds a queue's display state for today
+func (s *QueueService) display(queue *domain.ClinicQueue) (*dto.QueueDisplayResponse, error) {
+	tickets, err := s.queueRepo.FindTickets(queue.ID, s.clock.Today(), []domain.QueueTicketStatus{domain.QueueTicketStatusWaiting, domain.QueueTicketStatusCalled})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tickets: %w", err)
+	}
+	sortQueueTickets(tickets, s.clock.Now())
+
+	display := &dto.QueueDisplayResponse{
+		QueueID:   queue.ID,
+		QueueName: queue.Name,
+		Called:    []*dto.QueueDisplayTicket{},
+		Next:      []*dto.QueueDisplayTicket{},
+		UpdatedAt: s.clock.Now(),
+	}
+	if queue.Room != nil {
+		display.RoomName = queue.Room.Name
+	}
+	for _, t := range tickets {
+		item := &dto.QueueDisplayTicket{TicketCode: t.TicketCode, Lane: string(t.Lane)}
+		if t.Status == domain.QueueTicketStatusCalled {
+			item.CalledAt = t.CalledAt
+			item.CallCount = t.CallCount
+			display.Called = append(display.Called, item)
+			continue
+		}
+		display.WaitingCount++
+		if len(display.Next) < queueDisplayNext {
+			display.Next = append(display.Next, item)
+		}
+	}
+	return display, nil
+}
+
+// ensureReferences checks that the queue's doctor, room and department exist
+func (s *QueueService) ensureReferences(doctorID, roomID, departmentID *uint) error {
+	if doctorID != nil {
+		doctor, err := s.userRepo.FindByID(*doctorID)
+		if err != nil {
+			return fmt.Errorf("failed to find doctor: %w", err)
+		}
+		if doctor == nil {
+			return ErrDoctorNotFound
+		}
+	}
+	if roomID != nil {
+		room, err := s.resourceRepo.FindByID(*roomID)
+		if err != nil {
+			return fmt.Errorf("failed to find room: %w", err)
+		}
+		if room == nil || room.Type != domain.ResourceTypeRoom {
+			return ErrResourceNotFound
+		}
+	}
+	if departmentID != nil {
+		department, err := s.departmentRepo.FindByID(*departmentID)
+		if err != nil {
+			return fmt.Errorf("failed to find department: %w", err)
+		}
+		if department == nil {
+			return ErrDepartmentNotFound
+		}
+	}
+	return nil
+}
+
+func (s *QueueService) findQueue(id uint) (*domain.ClinicQueue, error) {
+	queue, err := s.queueRepo.FindByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find queue: %w", err)
+	}
+	if queue == nil {
+		return nil, ErrQueueNotFound
+	}
+	return queue, nil
+}
+
+func (s *QueueService) toTicketResponse(t *domain.QueueTicket) *dto.QueueTicketResponse {
+	resp := &dto.QueueTicketResponse{
+		ID:           t.ID,
+		QueueID:      t.QueueID,
+		QueueDate:    t.QueueDate.Format("2006-01-02"),
+		TicketNumber: t.TicketNumber,
+		TicketCode:   t.TicketCode,
+		VisitID:      t.VisitID,
+		PatientID:    t.PatientID,
+		Lane:         string(t.Lane),
+		Status:       string(t.Status),
+		CalledAt:     t.CalledAt,
+		CallCount:    t.CallCount,
+		SkippedAt:    t.SkippedAt,
+		ServedAt:     t.ServedAt,
+		IssuedAt:     t.CreatedAt,
+		AcuityLevel:  t.AcuityLevel,
+	}
+	if t.Patient != nil {
+		resp.PatientName = t.Patient.FullName
+	}
+	return resp
+}
+
+func toQueueResponse(q *domain.ClinicQueue) *dto.QueueResponse {
+	resp := &dto.QueueResponse{
+		ID:           q.ID,
+		Code:         q.Code,
+		Name:         q.Name,
+		TicketPrefix: q.TicketPrefix,
+		DoctorID:     q.DoctorID,
+		RoomID:       q.RoomID,
+		DepartmentID: q.DepartmentID,
+		IsActive:     q.IsActive,
+		CreatedAt:    q.CreatedAt,
+		UpdatedAt:    q.UpdatedAt,
+	}
+	if q.Doctor != nil {
+		resp.DoctorName = q.Doctor.FullName
+	}
+	if q.Room != nil {
+		resp.RoomName = q.Room.Name
+	}
+	if q.Department != nil {
+		resp.DepartmentName = q.Department.Name
+	}
+	return resp
+}
+
+// sortQueueTickets orders tickets by lane, then by acuity after waiting
+// until now, then by ticket number
+func sortQueueTickets(tickets []*domain.QueueTicket, now time.Time) {
+	sort.SliceStable(tickets, func(i, j int) bool {
+		ti, tj := tickets[i], tickets[j]
+		if ri, rj := ti.Lane.Rank(), tj.Lane.Rank(); ri != rj {
+			return ri < rj
+		}
+		if ai, aj := effectiveAcuity(ti.AcuityLevel, now.Sub(ti.CreatedAt)), effectiveAcuity(tj.AcuityLevel, now.Sub(tj.CreatedAt)); ai != aj {
+			return ai < aj
+		}
+		return ti.TicketNumber < tj.TicketNumber
+	})
+}
+
+func queueTopic(queueID uint) string {
+	return strconv.FormatUint(uint64(queueID), 10)
+}
@@ -0,0 +1,671 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/minhtran/his/internal/domain"
+	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/pkg/clock"
+	"github.com/minhtran/his/internal/pkg/document"
+	"github.com/minhtran/his/internal/pkg/logger"
+	"github.com/minhtran/his/internal/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrReferralNotFound is returned when a referral is not found
+	ErrReferralNotFound = errors.New("referral not found")
+	// ErrReferralTargetRequired is returned when a referral's target does not match its type
+	ErrReferralTargetRequired = errors.New("internal referrals need a target department or doctor, external referrals a facility")
+	// ErrReferralAttachmentInvalid is returned when an attached diagnosis or test is not the patient's
+	ErrReferralAttachmentInvalid = errors.New("attached diagnoses and tests must belong to the referred patient")
+	// ErrInvalidReferralTransition is returned when a referral cannot move to the requested status
+	ErrInvalidReferralTransition = errors.New("invalid referral status transition")
+	// ErrReferralDeclineReason is returned when declining a referral without a reason
+	ErrReferralDeclineReason = errors.New("a reason is required to decline a referral")
+	// ErrReferralNotInternal is returned when booking an external referral as an appointment
+	ErrReferralNotInternal = errors.New("only internal referrals can be booked as appointments")
+	// ErrReferralAlreadyScheduled is returned when booking a referral that already has an appointment
+	ErrReferralAlreadyScheduled = errors.New("referral already has an appointment")
+	// ErrReferralDoctorRequired is returned when booking a referral without a doctor to see the patient
+	ErrReferralDoctorRequired = errors.New("a doctor is required to book the referral")
+)
+
+// ReferralService handles referrals to other departments, doctors and
+// facilities: the receiving department's inbox, booking accepted referrals
+// as appointments and the printable referral letter
+type ReferralService struct {
+	referralRepo       *repository.ReferralRepository
+	visitRepo          *repository.VisitRepository
+	userRepo           *repository.UserRepository
+	departmentRepo     *repository.DepartmentRepository
+	diagnosisRepo      *repository.DiagnosisRepository
+	labTestRequestRepo *repository.LabTestRequestRepository
+	imagingRequestRepo *repository.ImagingRequestRepository
+	auditRepo          *repository.AuditLogRepository
+	appointmentService *AppointmentService
+	facilityName       string
+	clock              *clock.Clock
+}
+
+// NewReferralService creates a new referral service. A referral booked as an
+// appointment is completed when the visit for the appointment is.
+func NewReferralService(
+	referralRepo *repository.ReferralRepository,
+	visitRepo *repository.VisitRepository,
+	userRepo *repository.UserRepository,
+	departmentRepo *repository.DepartmentRepository,
+	diagnosisRepo *repository.DiagnosisRepository,
+	labTestRequestRepo *repository.LabTestRequestRepository,
+	imagingRequestRepo *repository.ImagingRequestRepository,
+	auditRepo *repository.AuditLogRepository,
+	appointmentService *AppointmentService,
+	visitService *VisitService,
+	facilityName string,
+	clk *clock.Clock,
+) *ReferralService {
+	s := &ReferralService{
+		referralRepo:       referralRepo,
+		visitRepo:          visitRepo,
+		userRepo:           userRepo,
+		departmentRepo:     departmentRepo,
+		diagnosisRepo:      diagnosisRepo,
+		labTestRequestRepo: labTestRequestRepo,
+		imagingRequestRepo: imagingRequestRepo,
+		auditRepo:          auditRepo,
+		appointmentService: appointmentService,
+		facilityName:       facilityName,
+		clock:              clk,
+	}
+	visitService.OnClosed(s.handleClosed)
+	return s
+}
+
+// CreateReferral refers the patient of a visit, attaching the diagnoses and
+// tests the receiving side needs
+func (s *ReferralService) CreateReferral(visitID uint, req *dto.CreateReferralRequest, userID uint) (*dto.ReferralResponse, error) {
+	visit, err := s.visitRepo.FindByID(visitID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find visit: %w", err)
+	}
+	if visit == nil {
+		return nil, ErrVisitNotFound
+	}
+
+	referral := &domain.Referral{
+		VisitID:              visit.ID,
+		PatientID:            visit.PatientID,
+		ReferringDoctorID:    userID,
+		ReferralType:         domain.ReferralType(req.ReferralType),
+		ExternalFacility:     strings.TrimSpace(req.ExternalFacility),
+		ExternalFacilityCode: req.ExternalFacilityCode,
+		Urgency:              domain.ReferralUrgencyRoutine,
+		Reason:               req.Reason,
+		ClinicalSummary:      req.ClinicalSummary,
+		TreatmentGiven:       req.TreatmentGiven,
+		PatientCondition:     req.PatientCondition,
+		TreatmentDirection:   req.TreatmentDirection,
+		Transport:            req.Transport,
+		Escort:               req.Escort,
+		Status:               domain.ReferralStatusSent,
+		SentAt:               s.clock.Now(),
+		CreatedBy:            userID,
+		UpdatedBy:            userID,
+	}
+	if req.Urgency != "" {
+		referral.Urgency = domain.ReferralUrgency(req.Urgency)
+	}
+	if err := s.resolveTarget(referral, req); err != nil {
+		return nil, err
+	}
+
+	attachments, err := s.buildAttachments(visit.PatientID, req)
+	if err != nil {
+		return nil, err
+	}
+	referral.Attachments = attachments
+
+	if err := s.referralRepo.Create(referral); err != nil {
+		return nil, fmt.Errorf("failed to create referral: %w", err)
+	}
+
+	s.auditReferral(referral, domain.AuditActionCreate, userID)
+	return s.GetReferral(referral.ID)
+}
+
+// GetReferral gets a referral with its attachments
+func (s *ReferralService) GetReferral(id uint) (*dto.ReferralResponse, error) {
+	referral, err := s.findReferral(id)
+	if err != nil {
+		return nil, err
+	}
+	return toReferralResponse(referral), nil
+}
+
+// GetVisitReferrals gets the referrals made in a visit
+func (s *ReferralService) GetVisitReferrals(visitID uint) ([]*dto.ReferralResponse, error) {
+	visit, err := s.visitRepo.FindByID(visitID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find visit: %w", err)
+	}
+	if visit == nil {
+		return nil, ErrVisitNotFound
+	}
+
+	referrals, err := s.referralRepo.FindByVisit(visit.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get referrals: %w", err)
+	}
+	return toReferralResponses(referrals), nil
+}
+
+// GetInbox gets the internal referrals sent to a department or to the user,
+// most urgent first. The department defaults to the user's; without a status
+// the referrals still to be answered or booked are returned.
+func (s *ReferralService) GetInbox(userID uint, departmentID *uint, status string) ([]*dto.ReferralResponse, error) {
+	if departmentID == nil {
+		user, err := s.userRepo.FindByID(userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find user: %w", err)
+		}
+		if user != nil {
+			departmentID = user.DepartmentID
+		}
+	}
+
+	statuses := []domain.ReferralStatus{domain.ReferralStatusSent, domain.ReferralStatusAccepted}
+	if status != "" {
+		statuses = []domain.ReferralStatus{domain.ReferralStatus(status)}
+	}
+
+	referrals, err := s.referralRepo.FindInbox(departmentID, userID, statuses)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get referral inbox: %w", err)
+	}
+	return toReferralResponses(referrals), nil
+}
+
+// AcceptReferral records that the receiving side will see the patient
+func (s *ReferralService) AcceptReferral(id uint, req *dto.RespondReferralRequest, userID uint) (*dto.ReferralResponse, error) {
+	referral, err := s.findReferral(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.respond(referral, domain.ReferralStatusAccepted, req.Note, userID); err != nil {
+		return nil, err
+	}
+	return s.GetReferral(referral.ID)
+}
+
+// DeclineReferral records that the receiving side will not see the patient, and why
+func (s *ReferralService) DeclineReferral(id uint, req *dto.RespondReferralRequest, userID uint) (*dto.ReferralResponse, error) {
+	if strings.TrimSpace(req.Note) == "" {
+		return nil, ErrReferralDeclineReason
+	}
+
+	referral, err := s.findReferral(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.respond(referral, domain.ReferralStatusDeclined, req.Note, userID); err != nil {
+		return nil, err
+	}
+	return s.GetReferral(referral.ID)
+}
+
+// ScheduleReferral books an internal referral as an appointment, accepting
+// it if it was not answered yet
+func (s *ReferralService) ScheduleReferral(id uint, req *dto.ScheduleReferralRequest, userID uint) (*dto.ReferralResponse, error) {
+	referral, err := s.findReferral(id)
+	if err != nil {
+		return nil, err
+	}
+	if referral.ReferralType != domain.ReferralTypeInternal {
+		return nil, ErrReferralNotInternal
+	}
+	if referral.AppointmentID != nil {
+		return nil, ErrReferralAlreadyScheduled
+	}
+	if referral.Status != domain.ReferralStatusAccepted && !referral.Status.CanTransitionTo(domain.ReferralStatusAccepted) {
+		return nil, fmt.Errorf("%w: cannot book a %s referral", ErrInvalidReferralTransition, referral.Status)
+	}
+
+	doctorID := referral.TargetDoctorID
+	if req.DoctorID != nil {
+		doctorID = req.DoctorID
+	}
+	if doctorID == nil {
+		return nil, ErrReferralDoctorRequired
+	}
+
+	appointment, err := s.appointmentService.ScheduleAppointment(&dto.CreateAppointmentRequest{
+		PatientID:       referral.PatientID,
+		DoctorID:        *doctorID,
+		AppointmentDate: req.AppointmentDate,
+		AppointmentTime: req.AppointmentTime,
+		DurationMinutes: req.DurationMinutes,
+		AppointmentType: string(domain.AppointmentTypeConsultation),
+		Reason:          fmt.Sprintf("Referral %s: %s", referral.ReferralNumber, referral.Reason),
+		Notes:           referral.ClinicalSummary,
+	}, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	referral.AppointmentID = &appointment.ID
+	if referral.Status == domain.ReferralStatusSent {
+		now := s.clock.Now()
+		referral.Status = domain.ReferralStatusAccepted
+		referral.RespondedBy = &userID
+		referral.RespondedAt = &now
+	}
+	referral.UpdatedBy = userID
+	if err := s.referralRepo.Update(referral); err != nil {
+		return nil, fmt.Errorf("failed to update referral: %w", err)
+	}
+
+	s.auditReferral(referral, domain.AuditActionUpdate, userID)
+	return s.GetReferral(referral.ID)
+}
+
+// CompleteReferral records that the receiving side has seen the patient.
+// Referrals booked as appointments are completed with the appointment's visit.
+func (s *ReferralService) CompleteReferral(id uint, userID uint) (*dto.ReferralResponse, error) {
+	referral, err := s.findReferral(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.complete(referral, userID); err != nil {
+		return nil, err
+	}
+	return s.GetReferral(referral.ID)
+}
+
+func (s *ReferralService) respond(referral *domain.Referral, status domain.ReferralStatus, note string, userID uint) error {
+	if !referral.Status.CanTransitionTo(status) {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidReferralTransition, referral.Status, status)
+	}
+
+	now := s.clock.Now()
+	referral.Status = status
+	referral.RespondedBy = &userID
+	referral.RespondedAt = &now
+	referral.ResponseNote = note
+	referral.UpdatedBy = userID
+	if err := s.referralRepo.Update(referral); err != nil {
+		return fmt.Errorf("failed to update referral: %w", err)
+	}
+
+	s.auditReferral(referral, domain.AuditActionUpdate, userID)
+	return nil
+}
+
+func (s *ReferralService) complete(referral *domain.Referral, userID uint) error {
+	if !referral.Status.CanTransitionTo(domain.ReferralStatusCompleted) {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidReferralTransition, referral.Status, domain.ReferralStatusCompleted)
+	}
+
+	now := s.clock.Now()
+	referral.Status = domain.ReferralStatusCompleted
+	referral.CompletedAt = &now
+	referral.UpdatedBy = userID
+	if err := s.referralRepo.Update(referral); err != nil {
+		return fmt.Errorf("failed to update referral: %w", err)
+	}
+
+	s.auditReferral(referral, domain.AuditActionUpdate, userID)
+	return nil
+}
+
+// handleClosed completes the referral booked as the appointment of a completed visit
+func (s *ReferralService) handleClosed(visit domain.Visit) {
+	if visit.Status != domain.VisitStatusCompleted || visit.AppointmentID == nil {
+		return
+	}
+
+	referral, err := s.referralRepo.FindAcceptedByAppointment(*visit.AppointmentID)
+	if err != nil {
+		logger.Error("Failed to find appointment's referral", zap.Uint("appointment_id", *visit.AppointmentID), zap.Error(err))
+		return
+	}
+	if referral == nil {
+		return
+	}
+
+	if err := s.complete(referral, visit.DoctorID); err != nil {
+		logger.Error("Failed to complete referral", zap.Uint("referral_id", referral.ID), zap.Error(err))
+	}
+}
+
+// resolveTarget checks the referral's target against its type. An internal
+// referral to a doctor goes to the doctor's department unless one is named.
+func (s *ReferralService) resolveTarget(referral *domain.Referral, req *dto.CreateReferralRequest) error {
+	if referral.ReferralType == domain.ReferralTypeExternal {
+		if referral.ExternalFacility == "" || req.TargetDepartmentID != nil || req.TargetDoctorID != nil {
+			return ErrReferralTargetRequired
+		}
+		return nil
+	}
+
+	if (req.TargetDepartmentID == nil && req.TargetDoctorID == nil) || referral.ExternalFacility != "" || referral.ExternalFacilityCode != "" {
+		return ErrReferralTargetRequired
+	}
+	if req.TargetDoctorID != nil {
+		doctor, err := s.userRepo.FindByID(*req.TargetDoctorID)
+		if err != nil {
+			return fmt.Errorf("failed to find doctor: %w", err)
+		}
+		if doctor == nil || !doctor.IsActive {
+			return ErrDoctorNotFound
+		}
+		referral.TargetDoctorID = &doctor.ID
+		referral.TargetDepartmentID = doctor.DepartmentID
+	}
+	if req.TargetDepartmentID != nil {
+		department, err := s.departmentRepo.FindByID(*req.TargetDepartmentID)
+		if err != nil {
+			return fmt.Errorf("failed to find department: %w", err)
+		}
+		if department == nil || !department.IsActive {
+			return ErrDepartmentNotFound
+		}
+		referral.TargetDepartmentID = &department.ID
+	}
+	return nil
+}
+
+// buildAttachments checks that the attached diagnoses and tests are the patient's
+func (s *ReferralService) buildAttachments(patientID uint, req *dto.CreateReferralRequest) ([]*domain.ReferralAttachment, error) {
+	var attachments []*domain.ReferralAttachment
+
+	for _, id := range req.DiagnosisIDs {
+		diagnosis, err := s.diagnosisRepo.FindByID(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find diagnosis: %w", err)
+		}
+		if diagnosis == nil || diagnosis.PatientID != patientID {
+			return nil, fmt.Errorf("%w: diagnosis %d", ErrReferralAttachmentInvalid, id)
+		}
+		attachments = append(attachments, &domain.ReferralAttachment{DiagnosisID: &diagnosis.ID})
+	}
+	for _, id := range req.LabTestRequestIDs {
+		request, err := s.labTestRequestRepo.FindByID(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find lab test request: %w", err)
+		}
+		if request == nil || request.PatientID != patientID {
+			return nil, fmt.Errorf("%w: lab test request %d", ErrReferralAttachmentInvalid, id)
+		}
+		attachments = append(attachments, &domain.ReferralAttachment{LabTestRequestID: &request.ID})
+	}
+	for _, id := range req.ImagingRequestIDs {
+		request, err := s.imagingRequestRepo.FindByID(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find imaging request: %w", err)
+		}
+		if request == nil || request.PatientID != patientID {
+			return nil, fmt.Errorf("%w: imaging request %d", ErrReferralAttachmentInvalid, id)
+		}
+		attachments = append(attachments, &domain.ReferralAttachment{ImagingRequestID: &request.ID})
+	}
+
+	return attachments, nil
+}
+
+func (s *ReferralService) findReferral(id uint) (*domain.Referral, error) {
+	referral, err := s.referralRepo.FindByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find referral: %w", err)
+	}
+	if referral == nil {
+		return nil, ErrReferralNotFound
+	}
+	return referral, nil
+}
+
+func (s *ReferralService) auditReferral(referral *domain.Referral, action domain.AuditAction, userID uint) {
+	s.auditRepo.Create(&domain.AuditLog{
+		UserID:     &userID,
+		Action:     action,
+		Resource:   "Referral",
+		ResourceID: fmt.Sprintf("%d", referral.ID),
+		Details: domain.AuditDetails{
+			"referral_number": referral.ReferralNumber,
+			"visit_id":        referral.VisitID,
+			"referral_type":   referral.ReferralType,
+			"status":          referral.Status,
+			"appointment_id":  referral.AppointmentID,
+		},
+	})
+}
+
+// referralLetterData is the data rendered by the referral_letter template
+type referralLetterData struct {
+	FacilityName       string
+	ReferralNumber     string
+	Recipient          string
+	RecipientCode      string
+	PatientName        string
+	PatientCode        string
+	Gender             string
+	Age                int
+	Address            string
+	InsuranceNumber    string
+	InsuranceValidTo   time.Time
+	TreatedFrom        time.Time
+	TreatedTo          time.Time
+	ClinicalSummary    string
+	Results            []dto.ReferralAttachmentResponse
+	Diagnoses          []dto.ReferralAttachmentResponse
+	TreatmentGiven     string
+	PatientCondition   string
+	Reason             string
+	TreatmentDirection string
+	SentAt             time.Time
+	Transport          string
+	Escort             string
+	ReferringDoctor    string
+	IssuedDay          string
+	IssuedMonth        string
+	IssuedYear         string
+}
+
+// GenerateReferralLetter renders the printable referral letter (HTML, giấy
+// chuyển tuyến layout) of a referral
+func (s *ReferralService) GenerateReferralLetter(id uint) ([]byte, error) {
+	referral, err := s.findReferral(id)
+	if err != nil {
+		return nil, err
+	}
+
+	sent := referral.SentAt.In(s.clock.Location())
+	data := referralLetterData{
+		FacilityName:       s.facilityName,
+		ReferralNumber:     referral.ReferralNumber,
+		Recipient:          referral.ExternalFacility,
+		RecipientCode:      referral.ExternalFacilityCode,
+		TreatedTo:          sent,
+		ClinicalSummary:    referral.ClinicalSummary,
+		TreatmentGiven:     referral.TreatmentGiven,
+		PatientCondition:   referral.PatientCondition,
+		Reason:             referral.Reason,
+		TreatmentDirection: referral.TreatmentDirection,
+		SentAt:             sent,
+		Transport:          referral.Transport,
+		Escort:             referral.Escort,
+		IssuedDay:          sent.Format("02"),
+		IssuedMonth:        sent.Format("01"),
+		IssuedYear:         sent.Format("2006"),
+	}
+	if referral.ReferralType == domain.ReferralTypeInternal {
+		var names []string
+		if referral.TargetDepartment != nil {
+			names = append(names, referral.TargetDepartment.Name)
+		}
+		if referral.TargetDoctor != nil {
+			names = append(names, referral.TargetDoctor.FullName)
+		}
+		data.Recipient = strings.Join(names, " - ")
+	}
+
+	if p := referral.Patient; p != nil {
+		data.PatientName = p.FullName
+		data.PatientCode = p.PatientCode
+		data.Age = clock.Age(clock.Day(p.DateOfBirth), s.clock.DayOf(sent))
+		data.Address = p.Address
+		if p.City != "" {
+			data.Address += ", " + p.City
+		}
+		data.Gender = "Khác"
+		switch p.Gender {
+		case domain.GenderMale:
+			data.Gender = "Nam"
+		case domain.GenderFemale:
+			data.Gender = "Nữ"
+		}
+	}
+	if v := referral.Visit; v != nil {
+		data.TreatedFrom = v.VisitDate
+		if v.Coverage != nil {
+			data.InsuranceNumber = v.Coverage.PolicyNumber
+			if v.Coverage.ValidTo != nil {
+				data.InsuranceValidTo = *v.Coverage.ValidTo
+			}
+		}
+	}
+	if referral.ReferringDoctor != nil {
+		data.ReferringDoctor = referral.ReferringDoctor.FullName
+	}
+
+	for _, a := range toReferralAttachmentResponses(referral.Attachments) {
+		if a.Type == referralAttachmentDiagnosis {
+			data.Diagnoses = append(data.Diagnoses, *a)
+		} else {
+			data.Results = append(data.Results, *a)
+		}
+	}
+
+	return document.Render("referral_letter", data)
+}
+
+// Attachment types of referral responses
+const (
+	referralAttachmentDiagnosis = "DIAGNOSIS"
+	referralAttachmentLabTest   = "LAB_TEST"
+	referralAttachmentImaging   = "IMAGING"
+)
+
+func toReferralResponses(referrals []*domain.Referral) []*dto.ReferralResponse {
+	items := make([]*dto.ReferralResponse, len(referrals))
+	for i, r := range referrals {
+		items[i] = toReferralResponse(r)
+	}
+	return items
+}
+
+func toReferralResponse(r *domain.Referral) *dto.ReferralResponse {
+	resp := &dto.ReferralResponse{
+		ID:                   r.ID,
+		ReferralNumber:       r.ReferralNumber,
+		VisitID:              r.VisitID,
+		PatientID:            r.PatientID,
+		ReferringDoctorID:    r.ReferringDoctorID,
+		ReferralType:         string(r.ReferralType),
+		TargetDepartmentID:   r.TargetDepartmentID,
+		TargetDoctorID:       r.TargetDoctorID,
+		ExternalFacility:     r.ExternalFacility,
+		ExternalFacilityCode: r.ExternalFacilityCode,
+		Urgency:              string(r.Urgency),
+		Reason:               r.Reason,
+		ClinicalSummary:      r.ClinicalSummary,
+		TreatmentGiven:       r.TreatmentGiven,
+		PatientCondition:     r.PatientCondition,
+		TreatmentDirection:   r.TreatmentDirection,
+		Transport:            r.Transport,
+		Escort:               r.Escort,
+		Status:               string(r.Status),
+		SentAt:               r.SentAt,
+		RespondedBy:          r.RespondedBy,
+		RespondedAt:          r.RespondedAt,
+		ResponseNote:         r.ResponseNote,
+		AppointmentID:        r.AppointmentID,
+		CompletedAt:          r.CompletedAt,
+		Attachments:          toReferralAttachmentResponses(r.Attachments),
+	}
+	if r.Patient != nil {
+		resp.PatientName = r.Patient.FullName
+	}
+	if r.ReferringDoctor != nil {
+		resp.ReferringDoctorName = r.ReferringDoctor.FullName
+	}
+	if r.TargetDepartment != nil {
+		resp.TargetDepartmentName = r.TargetDepartment.Name
+	}
+	if r.TargetDoctor != nil {
+		resp.TargetDoctorName = r.TargetDoctor.FullName
+	}
+	if r.Responder != nil {
+		resp.RespondedByName = r.Responder.FullName
+	}
+	if r.Appointment != nil {
+		resp.AppointmentCode = r.Appointment.AppointmentCode
+	}
+	return resp
+}
+
+func toReferralAttachmentResponses(attachments []*domain.ReferralAttachment) []*dto.ReferralAttachmentResponse {
+	var items []*dto.ReferralAttachmentResponse
+	for _, a := range attachments {
+		switch {
+		case a.Diagnosis != nil:
+			item := &dto.ReferralAttachmentResponse{
+				Type:   referralAttachmentDiagnosis,
+				ID:     a.Diagnosis.ID,
+				Status: string(a.Diagnosis.DiagnosisStatus),
+			}
+			if a.Diagnosis.ICD10Code != nil {
+				item.Code = a.Diagnosis.ICD10Code.Code
+				item.Name = a.Diagnosis.ICD10Code.Description
+			}
+			items = append(items, item)
+		case a.LabTestRequest != nil:
+			item := &dto.ReferralAttachmentResponse{
+				Type:   referralAttachmentLabTest,
+				ID:     a.LabTestRequest.ID,
+				Code:   a.LabTestRequest.RequestCode,
+				Status: string(a.LabTestRequest.Status),
+			}
+			if a.LabTestRequest.Template != nil {
+				item.Name = a.LabTestRequest.Template.Name
+			}
+			var results []string
+			for _, r := range a.LabTestRequest.Results {
+				result := strings.TrimSpace(fmt.Sprintf("%s: %s %s", r.ParameterName, r.Value, r.Unit))
+				if r.IsAbnormal {
+					result += " (*)"
+				}
+				results = append(results, result)
+			}
+			item.Summary = strings.Join(results, "; ")
+			items = append(items, item)
+		case a.ImagingRequest != nil:
+			item := &dto.ReferralAttachmentResponse{
+				Type:   referralAttachmentImaging,
+				ID:     a.ImagingRequest.ID,
+				Code:   a.ImagingRequest.RequestCode,
+				Status: string(a.ImagingRequest.Status),
+			}
+			if a.ImagingRequest.Template != nil {
+				item.Name = a.ImagingRequest.Template.Name
+			}
+			if a.ImagingRequest.Result != nil {
+				item.Summary = a.ImagingRequest.Result.Impression
+			}
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,586 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/minhtran/his/internal/domain"
+	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/pkg/clock"
+	"github.com/minhtran/his/internal/repository"
+)
+
+var (
+	// ErrResourceNotFound is returned when a room or equipment resource is not found
+	ErrResourceNotFound = errors.New("resource not found")
+	// ErrResourceCodeExists is returned when another resource already uses the code
+	ErrResourceCodeExists = errors.New("resource code already exists")
+	// ErrResourceInactive is returned when booking a resource that is out of use
+	ErrResourceInactive = errors.New("resource is not active")
+	// ErrResourceUnavailable is returned when a resource is closed, down or already booked for the period
+	ErrResourceUnavailable = errors.New("resource is not available at this time")
+	// ErrInvalidResourceHours is returned when an opening period does not end after it starts
+	ErrInvalidResourceHours = errors.New("opening hours must end after they start")
+	// ErrResourceDowntimeNotFound is returned when a downtime period is not found
+	ErrResourceDowntimeNotFound = errors.New("resource downtime not found")
+	// ErrInvalidDowntimePeriod is returned when a downtime period does not end after it starts
+	ErrInvalidDowntimePeriod = errors.New("downtime must end after it starts")
+)
+
+// ResourceService handles rooms and equipment, their opening hours and downtime
+type ResourceService struct {
+	resourceRepo   *repository.ResourceRepository
+	departmentRepo *repository.DepartmentRepository
+	auditRepo      *repository.AuditLogRepository
+	clock          *clock.Clock
+}
+
+// NewResourceService creates a new resource service
+func NewResourceService(
+	resourceRepo *repository.ResourceRepository,
+	departmentRepo *repository.DepartmentRepository,
+	auditRepo *repository.AuditLogRepository,
+	clk *clock.Clock,
+) *ResourceService {
+	return &ResourceService{
+		resourceRepo:   resourceRepo,
+		departmentRepo: departmentRepo,
+		auditRepo:      auditRepo,
+		clock:          clk,
+	}
+}
+
+// CreateResource adds a room or piece of equipment
+func (s *ResourceService) CreateResource(req *dto.CreateResourceRequest, userID uint) (*dto.ResourceResponse, error) {
+	existing, err := s.resourceRepo.FindByCode(req.Code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check resource code: %w", err)
+	}
+	if existing != nil {
+		return nil, ErrResourceCodeExists
+	}
+	if err := s.ensureDepartment(req.DepartmentID); err != nil {
+		return nil, err
+	}
+
+	hours, err := toResourceHours(req.Hours)
+	if err != nil {
+		return nil, err
+	}
+
+	resource := &domain.Resource{
+		Code:         req.Code,
+		Name:         req.Name,
+		Type:         domain.ResourceType(req.Type),
+		Category:     req.Category,
+		Location:     req.Location,
+		DepartmentID: req.DepartmentID,
+		Notes:        req.Notes,
+		IsActive:     true,
+		Hours:        hours,
+		CreatedBy:    userID,
+		UpdatedBy:    userID,
+	}
+
+	if err := s.resourceRepo.Create(resource); err != nil {
+		return nil, fmt.Errorf("failed to create resource: %w", err)
+	}
+
+	// Audit log
+	s.auditRepo.Create(&domain.AuditLog{
+		UserID:     &userID,
+		Action:     domain.AuditActionCreate,
+		Resource:   "Resource",
+		ResourceID: fmt.Sprintf("%d", resource.ID),
+		Details: domain.AuditDetails{
+			"code": resource.Code,
+			"name": resource.Name,
+			"type": resource.Type,
+		},
+	})
+
+	return s.GetResource(resource.ID)
+}
+
+// GetResource gets a resource with its opening hours
+func (s *ResourceService) GetResource(id uint) (*dto.ResourceResponse, error) {
+	resource, err := s.resourceRepo.FindByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find resource: %w", err)
+	}
+	if resource == nil {
+		return nil, ErrResourceNotFound
+	}
+	return s.toResourceResponse(resource), nil
+}
+
+// ListResources lists resources with filters
+func (s *ResourceService) ListResources(filters map[string]interface{}, page, pageSize int) ([]*dto.ResourceResponse, int64, error) {
+	resources, total, err := s.resourceRepo.List(filters, page, pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list resources: %w", err)
+	}
+
+	items := make([]*dto.ResourceResponse, len(resources))
+	for i, r := range resources {
+		items[i] = s.toResourceResponse(r)
+	}
+	return items, total, nil
+}
+
+// UpdateResource updates a resource's details or takes it out of use
+func (s *ResourceService) UpdateResource(id uint, req *dto.UpdateResourceRequest, userID uint) (*dto.ResourceResponse, error) {
+	resource, err := s.resourceRepo.FindByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find resource: %w", err)
+	}
+	if resource == nil {
+		return nil, ErrResourceNotFound
+	}
+
+	if req.Name != "" {
+		resource.Name = req.Name
+	}
+	if req.Category != "" {
+		resource.Category = req.Category
+	}
+	if req.Location != "" {
+		resource.Location = req.Location
+	}
+	if req.DepartmentID != nil {
+		if err := s.ensureDepartment(req.DepartmentID); err != nil {
+			return nil, err
+		}
+		resource.DepartmentID = req.DepartmentID
+	}
+	if req.Notes != "" {
+		resource.Notes = req.Notes
+	}
+	if req.IsActive != nil {
+		resource.IsActive = *req.IsActive
+	}
+	resource.UpdatedBy = userID
+
+	if err := s.resourceRepo.Update(resource); err != nil {
+		return nil, fmt.Errorf("failed to update resource: %w", err)
+	}
+
+	// Audit log
+	s.auditRepo.Create(&domain.AuditLog{
+		UserID:     &userID,
+		Action:     domain.AuditActionUpdate,
+		Resource:   "Resource",
+		ResourceID: fmt.Sprintf("%d", resource.ID),
+		Details: domain.AuditDetails{
+			"code":      resource.Code,
+			"is_active": resource.IsActive,
+		},
+	})
+
+	return s.GetResource(resource.ID)
+}
+
+// SetHours replaces a resource's weekly opening hours; no hours means the
+// resource can be booked at any time
+func (s *ResourceService) SetHours(id uint, req *dto.SetResourceHoursRequest, userID uint) (*dto.ResourceResponse, error) {
+	resource, err := s.resourceRepo.FindByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find resource: %w", err)
+	}
+	if resource == nil {
+		return nil, ErrResourceNotFound
+	}
+
+	hours, err := toResourceHours(req.Hours)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.resourceRepo.ReplaceHours(id, hours); err != nil {
+		return nil, fmt.Errorf("failed to update opening hours: %w", err)
+	}
+
+	// Audit log
+	s.auditRepo.Create(&domain.AuditLog{
+		UserID:     &userID,
+		Action:     domain.AuditActionUpdate,
+		Resource:   "Resource",
+		ResourceID: fmt.Sprintf("%d", id),
+		Details: domain.AuditDetails{
+			"code":  resource.Code,
+			"hours": len(hours),
+		},
+	})
+
+	return s.GetResource(id)
+}
+
+// CreateDowntime takes a resource out of service for a period
+func (s *ResourceService) CreateDowntime(id uint, req *dto.CreateResourceDowntimeRequest, userID uint) (*dto.ResourceDowntimeResponse, error) {
+	resource, err := s.resourceRepo.FindByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find resource: %w", err)
+	}
+	if resource == nil {
+		return nil, ErrResourceNotFound
+	}
+
+	startAt, err := time.Parse(time.RFC3339, req.StartAt)
+	if err != nil {
+		return nil, ErrInvalidDateFormat
+	}
+	endAt, err := time.Parse(time.RFC3339, req.EndAt)
+	if err != nil {
+		return nil, ErrInvalidDateFormat
+	}
+	if !endAt.After(startAt) {
+		return nil, ErrInvalidDowntimePeriod
+	}
+
+	downtime := &domain.ResourceDowntime{
+		ResourceID: id,
+		StartAt:    startAt,
+		EndAt:      endAt,
+		Reason:     req.Reason,
+		CreatedBy:  userID,
+		UpdatedBy:  userID,
+	}
+	if err := s.resourceRepo.CreateDowntime(downtime); err != nil {
+		return nil, fmt.Errorf("failed to create downtime: %w", err)
+	}
+
+	// Audit log
+	s.auditRepo.Create(&domain.AuditLog{
+		UserID:     &userID,
+		Action:     domain.AuditActionCreate,
+		Resource:   "ResourceDowntime",
+		ResourceID: fmt.Sprintf("%d", downtime.ID),
+		Details: domain.AuditDetails{
+			"resource_id": id,
+			"start_at":    req.StartAt,
+			"end_at":      req.EndAt,
+		},
+	})
+
+	return toDowntimeResponse(downtime), nil
+}
+
+// DeleteDowntime puts a resource back in service by removing a downtime period
+func (s *ResourceService) DeleteDowntime(id, downtimeID uint, userID uint) error {
+	downtime, err := s.resourceRepo.FindDowntimeByID(downtimeID)
+	if err != nil {
+		return fmt.Errorf("failed to find downtime: %w", err)
+	}
+	if downtime == nil || downtime.ResourceID != id {
+		return ErrResourceDowntimeNotFound
+	}
+
+	if err := s.resourceRepo.DeleteDowntime(downtimeID); err != nil {
+		return fmt.Errorf("failed to delete downtime: %w", err)
+	}
+
+	// Audit log
+	s.auditRepo.Create(&domain.AuditLog{
+		UserID:     &userID,
+		Action:     domain.AuditActionDelete,
+		Resource:   "ResourceDowntime",
+		ResourceID: fmt.Sprintf("%d", downtimeID),
+		Details:    domain.AuditDetails{"resource_id": id},
+	})
+
+	return nil
+}
+
+// GetSchedule lists a resource's downtime and active bookings over an
+// inclusive date range
+func (s *ResourceService) GetSchedule(id uint, fromStr, toStr string) (*dto.ResourceScheduleResponse, error) {
+	resource, err := s.resourceRepo.FindByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find resource: %w", err)
+	}
+	if resource == nil {
+		return nil, ErrResourceNotFound
+	}
+
+	from, to, err := parseDateRange(s.clock.Today(), fromStr, toStr)
+	if err != nil {
+		return nil, err
+	}
+	rangeStart := s.clock.StartOfDay(from)
+	rangeEnd := s.clock.StartOfDay(to.AddDate(0, 0, 1))
+
+	downtimes, err := s.resourceRepo.FindDowntimes([]uint{id}, rangeStart, rangeEnd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get downtime: %w", err)
+	}
+	bookings, err := s.resourceRepo.FindActiveBookings([]uint{id}, rangeStart, rangeEnd, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bookings: %w", err)
+	}
+
+	resp := &dto.ResourceScheduleResponse{
+		Resource:  s.toResourceResponse(resource),
+		From:      from.Format("2006-01-02"),
+		To:        to.Format("2006-01-02"),
+		Downtimes: make([]*dto.ResourceDowntimeResponse, len(downtimes)),
+		Bookings:  make([]*dto.ResourceBookingResponse, len(bookings)),
+	}
+	for i, d := range downtimes {
+		resp.Downtimes[i] = toDowntimeResponse(d)
+	}
+	for i, b := range bookings {
+		resp.Bookings[i] = &dto.ResourceBookingResponse{
+			ID:               b.ID,
+			ResourceID:       b.ResourceID,
+			AppointmentID:    b.AppointmentID,
+			ImagingRequestID: b.ImagingRequestID,
+			StartAt:          b.StartAt,
+			EndAt:            b.EndAt,
+		}
+	}
+	return resp, nil
+}
+
+// ensureDepartment checks that an optional department exists
+func (s *ResourceService) ensureDepartment(departmentID *uint) error {
+	if departmentID == nil {
+		return nil
+	}
+	department, err := s.departmentRepo.FindByID(*departmentID)
+	if err != nil {
+		return fmt.Errorf("failed to find department: %w", err)
+	}
+	if department == nil {
+		return ErrDepartmentNotFound
+	}
+	return nil
+}
+
+// resourceCalendar holds what decides whether resources are free over a
+// period: their weekly hours, their downtime and the bookings still holding them
+type resourceCalendar struct {
+	resources []*domain.Resource
+	downtimes []*domain.ResourceDowntime
+	bookings  []*domain.ResourceBooking
+}
+
+// loadBookableResources loads the resources to reserve, checking that each
+// exists and is active. IDs are deduplicated and returned in ID order, the
+// order in which their rows are locked.
+func loadBookableResources(repo *repository.ResourceRepository, ids []uint) ([]*domain.Resource, []uint, error) {
+	if len(ids) == 0 {
+		return nil, nil, nil
+	}
+
+	seen := make(map[uint]bool, len(ids))
+	unique := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	sort.Slice(unique, func(i, j int) bool { return unique[i] < unique[j] })
+
+	resources, err := repo.FindByIDs(unique)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find resources: %w", err)
+	}
+	if len(resources) != len(unique) {
+		return nil, nil, ErrResourceNotFound
+	}
+	for _, r := range resources {
+		if !r.IsActive {
+			return nil, nil, fmt.Errorf("%w: %s", ErrResourceInactive, r.Name)
+		}
+	}
+	return resources, unique, nil
+}
+
+// loadResourceCalendar loads the downtime and active bookings of resources
+// over [from, to), ignoring the bookings of the excluded appointment or
+// imaging request
+func loadResourceCalendar(repo *repository.ResourceRepository, resources []*domain.Resource, from, to time.Time, excludeAppointmentID, excludeImagingRequestID *uint) (*resourceCalendar, error) {
+	calendar := &resourceCalendar{resources: resources}
+	if len(resources) == 0 {
+		return calendar, nil
+	}
+
+	ids := make([]uint, len(resources))
+	for i, r := range resources {
+		ids[i] = r.ID
+	}
+
+	var err error
+	if calendar.downtimes, err = repo.FindDowntimes(ids, from, to); err != nil {
+		return nil, fmt.Errorf("failed to get resource downtime: %w", err)
+	}
+	if calendar.bookings, err = repo.FindActiveBookings(ids, from, to, excludeAppointmentID, excludeImagingRequestID); err != nil {
+		return nil, fmt.Errorf("failed to get resource bookings: %w", err)
+	}
+	return calendar, nil
+}
+
+// unavailable returns the first resource that is closed, down or booked at
+// some point in [start, end), or nil when all resources are free
+func (c *resourceCalendar) unavailable(start, end time.Time) *domain.Resource {
+	for _, r := range c.resources {
+		if !resourceOpen(r, start, end) {
+			return r
+		}
+		for _, d := range c.downtimes {
+			if d.ResourceID == r.ID && d.StartAt.Before(end) && d.EndAt.After(start) {
+				return r
+			}
+		}
+		for _, b := range c.bookings {
+			if b.ResourceID == r.ID && b.StartAt.Before(end) && b.EndAt.After(start) {
+				return r
+			}
+		}
+	}
+	return nil
+}
+
+// checkResourcesFree checks that resources can be reserved over [start, end)
+func checkResourcesFree(repo *repository.ResourceRepository, resources []*domain.Resource, start, end time.Time, excludeAppointmentID, excludeImagingRequestID *uint) error {
+	calendar, err := loadResourceCalendar(repo, resources, start, end, excludeAppointmentID, excludeImagingRequestID)
+	if err != nil {
+		return err
+	}
+	if r := calendar.unavailable(start, end); r != nil {
+		return fmt.Errorf("%w: %s", ErrResourceUnavailable, r.Name)
+	}
+	return nil
+}
+
+// resourceOpen reports whether [start, end) lies within one of a resource's
+// weekly opening periods; a resource without hours is always open
+func resourceOpen(r *domain.Resource, start, end time.Time) bool {
+	if len(r.Hours) == 0 {
+		return true
+	}
+	from := start.Hour()*60 + start.Minute()
+	to := from + int(end.Sub(start)/time.Minute)
+	if to > 24*60 {
+		return false
+	}
+	for _, h := range r.Hours {
+		if h.DayOfWeek != int(start.Weekday()) {
+			continue
+		}
+		open, err1 := parseClock(h.StartTime)
+		close, err2 := parseClock(h.EndTime)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		if from >= open && to <= close {
+			return true
+		}
+	}
+	return false
+}
+
+// newResourceBookings builds the bookings reserving resources over [start, end)
+func newResourceBookings(resources []*domain.Resource, start, end time.Time, appointmentID, imagingRequestID, createdBy *uint) []*domain.ResourceBooking {
+	bookings := make([]*domain.ResourceBooking, len(resources))
+	for i, r := range resources {
+		bookings[i] = &domain.ResourceBooking{
+			ResourceID:       r.ID,
+			AppointmentID:    appointmentID,
+			ImagingRequestID: imagingRequestID,
+			StartAt:          start,
+			EndAt:            end,
+			CreatedBy:        createdBy,
+		}
+	}
+	return bookings
+}
+
+// toBookedResources lists the resources held by bookings
+func toBookedResources(bookings []*domain.ResourceBooking) []*dto.BookedResource {
+	if len(bookings) == 0 {
+		return nil
+	}
+	items := make([]*dto.BookedResource, 0, len(bookings))
+	for _, b := range bookings {
+		if b.Resource == nil {
+			continue
+		}
+		items = append(items, &dto.BookedResource{
+			ID:   b.Resource.ID,
+			Code: b.Resource.Code,
+			Name: b.Resource.Name,
+			Type: string(b.Resource.Type),
+		})
+	}
+	return items
+}
+
+// appointmentPeriod returns the start and end of an appointment in the facility's time zone
+func appointmentPeriod(clk *clock.Clock, date, at time.Time, duration int) (time.Time, time.Time) {
+	start := clk.At(date, at)
+	return start, start.Add(time.Duration(duration) * time.Minute)
+}
+
+// toResourceHours validates and converts weekly opening hours
+func toResourceHours(req []dto.ResourceHoursRequest) ([]domain.ResourceHours, error) {
+	hours := make([]domain.ResourceHours, len(req))
+	for i, h := range req {
+		open, err := parseClock(h.StartTime)
+		if err != nil {
+			return nil, ErrInvalidResourceHours
+		}
+		close, err := parseClock(h.EndTime)
+		if err != nil || close <= open {
+			return nil, ErrInvalidResourceHours
+		}
+		hours[i] = domain.ResourceHours{
+			DayOfWeek: *h.DayOfWeek,
+			StartTime: h.StartTime,
+			EndTime:   h.EndTime,
+		}
+	}
+	return hours, nil
+}
+
+func toDowntimeResponse(d *domain.ResourceDowntime) *dto.ResourceDowntimeResponse {
+	return &dto.ResourceDowntimeResponse{
+		ID:         d.ID,
+		ResourceID: d.ResourceID,
+		StartAt:    d.StartAt,
+		EndAt:      d.EndAt,
+		Reason:     d.Reason,
+	}
+}
+
+// Helper functions
+func (s *ResourceService) toResourceResponse(r *domain.Resource) *dto.ResourceResponse {
+	resp := &dto.ResourceResponse{
+		ID:           r.ID,
+		Code:         r.Code,
+		Name:         r.Name,
+		Type:         string(r.Type),
+		Category:     r.Category,
+		Location:     r.Location,
+		DepartmentID: r.DepartmentID,
+		Notes:        r.Notes,
+		IsActive:     r.IsActive,
+		Hours:        make([]*dto.ResourceHoursResponse, len(r.Hours)),
+		CreatedAt:    r.CreatedAt,
+		UpdatedAt:    r.UpdatedAt,
+	}
+	if r.Department != nil {
+		resp.DepartmentName = r.Department.Name
+	}
+	for i, h := range r.Hours {
+		resp.Hours[i] = &dto.ResourceHoursResponse{
+			DayOfWeek: h.DayOfWeek,
+			StartTime: h.StartTime,
+			EndTime:   h.EndTime,
+		}
+	}
+	return resp
+}
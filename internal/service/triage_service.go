@@ -0,0 +1,484 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/minhtran/his/internal/domain"
+	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/pkg/clock"
+	"github.com/minhtran/his/internal/repository"
+)
+
+var (
+	// ErrTriageRuleNotFound is returned when a triage rule is not found
+	ErrTriageRuleNotFound = errors.New("triage rule not found")
+	// ErrInvalidTriageRule is returned when a triage rule cannot be evaluated
+	ErrInvalidTriageRule = errors.New("invalid triage rule")
+	// ErrVisitNotOpen is returned when triaging a visit that is completed or cancelled
+	ErrVisitNotOpen = errors.New("completed or cancelled visits cannot be triaged")
+	// ErrTriageOverrideReason is returned when overriding the computed acuity level without a reason
+	ErrTriageOverrideReason = errors.New("a reason is required to override the computed acuity level")
+)
+
+// triageUntriagedLevel is the acuity level patients wait at until they are
+// triaged, the middle of the scale
+const triageUntriagedLevel = 3
+
+// TriageListener is called after a visit is triaged or re-triaged
+type TriageListener func(triage domain.VisitTriage)
+
+// TriageService handles triage: acuity levels computed from the configured
+// ruleset, re-triage history and the emergency worklist
+type TriageService struct {
+	triageRepo *repository.TriageRepository
+	visitRepo  *repository.VisitRepository
+	auditRepo  *repository.AuditLogRepository
+	scale      domain.TriageScale
+	clock      *clock.Clock
+	listeners  []TriageListener
+}
+
+// NewTriageService creates a new triage service computing levels with the rules of scale
+func NewTriageService(
+	triageRepo *repository.TriageRepository,
+	visitRepo *repository.VisitRepository,
+	auditRepo *repository.AuditLogRepository,
+	scale string,
+	clk *clock.Clock,
+) *TriageService {
+	return &TriageService{
+		triageRepo: triageRepo,
+		visitRepo:  visitRepo,
+		auditRepo:  auditRepo,
+		scale:      domain.TriageScale(scale),
+		clock:      clk,
+	}
+}
+
+// OnTriaged registers a listener called after a visit is triaged
+func (s *TriageService) OnTriaged(listener TriageListener) {
+	s.listeners = append(s.listeners, listener)
+}
+
+// TriageVisit records a triage assessment of a visit. The acuity level is
+// computed from the ruleset; a nurse may override it, giving a reason. Each
+// re-triage adds an assessment and replaces the visit's level.
+func (s *TriageService) TriageVisit(visitID uint, req *dto.TriageVisitRequest, userID uint) (*dto.VisitTriageResponse, error) {
+	visit, err := s.visitRepo.FindByID(visitID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find visit: %w", err)
+	}
+	if visit == nil {
+		return nil, ErrVisitNotFound
+	}
+	if visit.Status.IsClosed() {
+		return nil, ErrVisitNotOpen
+	}
+
+	triage := &domain.VisitTriage{
+		VisitID:                visit.ID,
+		PatientID:              visit.PatientID,
+		TriagedBy:              userID,
+		TriagedAt:              s.clock.Now(),
+		ComplaintCategory:      domain.ComplaintCategory(req.ComplaintCategory),
+		ComplaintNotes:         req.ComplaintNotes,
+		PainScore:              req.PainScore,
+		Consciousness:          domain.Consciousness(req.Consciousness),
+		Temperature:            req.Temperature,
+		BloodPressureSystolic:  req.BloodPressureSystolic,
+		BloodPressureDiastolic: req.BloodPressureDiastolic,
+		HeartRate:              req.HeartRate,
+		RespiratoryRate:        req.RespiratoryRate,
+		OxygenSaturation:       req.OxygenSaturation,
+		Scale:                  s.scale,
+	}
+
+	age := -1
+	if visit.Patient != nil {
+		age = clock.Age(clock.Day(visit.Patient.DateOfBirth), s.clock.Today())
+	}
+
+	rules, err := s.triageRepo.FindActiveRules(s.scale)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load triage rules: %w", err)
+	}
+	computed, matched := assessAcuity(rules, triage, age)
+	triage.ComputedLevel = computed
+	triage.AcuityLevel = computed
+	triage.MatchedRules = strings.Join(matched, "\n")
+
+	if req.AcuityLevel != nil && *req.AcuityLevel != computed {
+		if strings.TrimSpace(req.OverrideReason) == "" {
+			return nil, ErrTriageOverrideReason
+		}
+		triage.AcuityLevel = *req.AcuityLevel
+		triage.OverrideReason = req.OverrideReason
+	}
+
+	if err := s.triageRepo.CreateTriage(triage); err != nil {
+		return nil, fmt.Errorf("failed to save triage: %w", err)
+	}
+
+	// Audit log
+	s.auditRepo.Create(&domain.AuditLog{
+		UserID:     &userID,
+		Action:     domain.AuditActionCreate,
+		Resource:   "VisitTriage",
+		ResourceID: fmt.Sprintf("%d", triage.ID),
+		Details: domain.AuditDetails{
+			"visit_id":       visit.ID,
+			"sequence":       triage.Sequence,
+			"computed_level": triage.ComputedLevel,
+			"acuity_level":   triage.AcuityLevel,
+		},
+	})
+
+	for _, listener := range s.listeners {
+		listener(*triage)
+	}
+	return s.toTriageResponse(triage), nil
+}
+
+// GetVisitTriages gets a visit's triage assessments, latest first
+func (s *TriageService) GetVisitTriages(visitID uint) ([]*dto.VisitTriageResponse, error) {
+	visit, err := s.visitRepo.FindByID(visitID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find visit: %w", err)
+	}
+	if visit == nil {
+		return nil, ErrVisitNotFound
+	}
+
+	triages, err := s.triageRepo.FindByVisit(visit.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get triages: %w", err)
+	}
+
+	items := make([]*dto.VisitTriageResponse, len(triages))
+	for i, t := range triages {
+		items[i] = s.toTriageResponse(t)
+	}
+	return items, nil
+}
+
+// GetWorklist gets the emergency visits waiting to be seen: patients still
+// to be triaged first, then by acuity and waiting time
+func (s *TriageService) GetWorklist() ([]*dto.TriageWorklistItem, error) {
+	visits, err := s.visitRepo.FindWaitingByType(domain.VisitTypeEmergency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get visits: %w", err)
+	}
+
+	now := s.clock.Now()
+	sort.SliceStable(visits, func(i, j int) bool {
+		vi, vj := visits[i], visits[j]
+		if (vi.AcuityLevel == nil) != (vj.AcuityLevel == nil) {
+			return vi.AcuityLevel == nil
+		}
+		li, lj := effectiveAcuity(vi.AcuityLevel, now.Sub(vi.CreatedAt)), effectiveAcuity(vj.AcuityLevel, now.Sub(vj.CreatedAt))
+		if li != lj {
+			return li < lj
+		}
+		return vi.CreatedAt.Before(vj.CreatedAt)
+	})
+
+	items := make([]*dto.TriageWorklistItem, len(visits))
+	for i, v := range visits {
+		waited := now.Sub(v.CreatedAt)
+		item := &dto.TriageWorklistItem{
+			VisitID:        v.ID,
+			VisitCode:      v.VisitCode,
+			PatientID:      v.PatientID,
+			ChiefComplaint: v.ChiefComplaint,
+			ArrivedAt:      v.CreatedAt,
+			WaitingMinutes: int(waited.Minutes()),
+			AcuityLevel:    v.AcuityLevel,
+			TriagedAt:      v.TriagedAt,
+		}
+		if v.AcuityLevel != nil {
+			item.AcuityName = s.scale.LevelName(*v.AcuityLevel)
+			item.Overdue = waited > domain.TriageTargetWait(*v.AcuityLevel)
+		}
+		if v.Patient != nil {
+			item.PatientName = v.Patient.FullName
+		}
+		if v.Doctor != nil {
+			item.DoctorName = v.Doctor.FullName
+		}
+		items[i] = item
+	}
+	return items, nil
+}
+
+// ListRules lists triage rules with filters
+func (s *TriageService) ListRules(filters map[string]interface{}) ([]*dto.TriageRuleResponse, error) {
+	rules, err := s.triageRepo.ListRules(filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list triage rules: %w", err)
+	}
+
+	items := make([]*dto.TriageRuleResponse, len(rules))
+	for i, r := range rules {
+		items[i] = toTriageRuleResponse(r)
+	}
+	return items, nil
+}
+
+// CreateRule adds a rule to a triage ruleset
+func (s *TriageService) CreateRule(req *dto.CreateTriageRuleRequest, userID uint) (*dto.TriageRuleResponse, error) {
+	rule := &domain.TriageRule{
+		Scale:             domain.TriageScale(req.Scale),
+		Name:              req.Name,
+		ComplaintCategory: domain.ComplaintCategory(req.ComplaintCategory),
+		Parameter:         domain.TriageParameter(req.Parameter),
+		Operator:          domain.TriageOperator(req.Operator),
+		Threshold:         req.Threshold,
+		AcuityLevel:       req.AcuityLevel,
+		IsActive:          true,
+		CreatedBy:         &userID,
+		UpdatedBy:         &userID,
+	}
+	if err := validateTriageRule(rule); err != nil {
+		return nil, err
+	}
+
+	if err := s.triageRepo.CreateRule(rule); err != nil {
+		return nil, fmt.Errorf("failed to create triage rule: %w", err)
+	}
+
+	s.auditRule(rule, domain.AuditActionCreate, userID)
+	return toTriageRuleResponse(rule), nil
+}
+
+// UpdateRule updates a triage rule or takes it out of use
+func (s *TriageService) UpdateRule(id uint, req *dto.UpdateTriageRuleRequest, userID uint) (*dto.TriageRuleResponse, error) {
+	rule, err := s.findRule(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != "" {
+		rule.Name = req.Name
+	}
+	if req.ComplaintCategory != nil {
+		rule.ComplaintCategory = domain.ComplaintCategory(*req.ComplaintCategory)
+	}
+	if req.Operator != "" {
+		rule.Operator = domain.TriageOperator(req.Operator)
+	}
+	if req.Threshold != nil {
+		rule.Threshold = *req.Threshold
+	}
+	if req.AcuityLevel != 0 {
+		rule.AcuityLevel = req.AcuityLevel
+	}
+	if req.IsActive != nil {
+		rule.IsActive = *req.IsActive
+	}
+	rule.UpdatedBy = &userID
+	if err := validateTriageRule(rule); err != nil {
+		return nil, err
+	}
+
+	if err := s.triageRepo.UpdateRule(rule); err != nil {
+		return nil, fmt.Errorf("failed to update triage rule: %w", err)
+	}
+
+	s.auditRule(rule, domain.AuditActionUpdate, userID)
+	return toTriageRuleResponse(rule), nil
+}
+
+// DeleteRule removes a rule from its ruleset
+func (s *TriageService) DeleteRule(id uint, userID uint) error {
+	rule, err := s.findRule(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.triageRepo.DeleteRule(rule.ID); err != nil {
+		return fmt.Errorf("failed to delete triage rule: %w", err)
+	}
+
+	s.auditRule(rule, domain.AuditActionDelete, userID)
+	return nil
+}
+
+func (s *TriageService) findRule(id uint) (*domain.TriageRule, error) {
+	rule, err := s.triageRepo.FindRuleByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find triage rule: %w", err)
+	}
+	if rule == nil {
+		return nil, ErrTriageRuleNotFound
+	}
+	return rule, nil
+}
+
+func (s *TriageService) auditRule(rule *domain.TriageRule, action domain.AuditAction, userID uint) {
+	s.auditRepo.Create(&domain.AuditLog{
+		UserID:     &userID,
+		Action:     action,
+		Resource:   "TriageRule",
+		ResourceID: fmt.Sprintf("%d", rule.ID),
+		Details: domain.AuditDetails{
+			"scale":        rule.Scale,
+			"name":         rule.Name,
+			"acuity_level": rule.AcuityLevel,
+			"is_active":    rule.IsActive,
+		},
+	})
+}
+
+func (s *TriageService) toTriageResponse(t *domain.VisitTriage) *dto.VisitTriageResponse {
+	resp := &dto.VisitTriageResponse{
+		ID:                     t.ID,
+		VisitID:                t.VisitID,
+		PatientID:              t.PatientID,
+		Sequence:               t.Sequence,
+		TriagedBy:              t.TriagedBy,
+		TriagedAt:              t.TriagedAt,
+		ComplaintCategory:      string(t.ComplaintCategory),
+		ComplaintNotes:         t.ComplaintNotes,
+		PainScore:              t.PainScore,
+		Consciousness:          string(t.Consciousness),
+		Temperature:            t.Temperature,
+		BloodPressureSystolic:  t.BloodPressureSystolic,
+		BloodPressureDiastolic: t.BloodPressureDiastolic,
+		HeartRate:              t.HeartRate,
+		RespiratoryRate:        t.RespiratoryRate,
+		OxygenSaturation:       t.OxygenSaturation,
+		Scale:                  string(t.Scale),
+		ComputedLevel:          t.ComputedLevel,
+		AcuityLevel:            t.AcuityLevel,
+		AcuityName:             t.Scale.LevelName(t.AcuityLevel),
+		Overridden:             t.AcuityLevel != t.ComputedLevel,
+		OverrideReason:         t.OverrideReason,
+		MatchedRules:           []string{},
+		TargetWaitMinutes:      int(domain.TriageTargetWait(t.AcuityLevel).Minutes()),
+	}
+	if t.MatchedRules != "" {
+		resp.MatchedRules = strings.Split(t.MatchedRules, "\n")
+	}
+	if t.TriagedByUser != nil {
+		resp.TriagedByName = t.TriagedByUser.FullName
+	}
+	return resp
+}
+
+func toTriageRuleResponse(r *domain.TriageRule) *dto.TriageRuleResponse {
+	return &dto.TriageRuleResponse{
+		ID:                r.ID,
+		Scale:             string(r.Scale),
+		Name:              r.Name,
+		ComplaintCategory: string(r.ComplaintCategory),
+		Parameter:         string(r.Parameter),
+		Operator:          string(r.Operator),
+		Threshold:         r.Threshold,
+		AcuityLevel:       r.AcuityLevel,
+		IsActive:          r.IsActive,
+		CreatedAt:         r.CreatedAt,
+		UpdatedAt:         r.UpdatedAt,
+	}
+}
+
+// validateTriageRule checks that a rule can be evaluated: complaint rules
+// name a complaint, the others compare with an operator
+func validateTriageRule(rule *domain.TriageRule) error {
+	if rule.ComplaintCategory != "" && !rule.ComplaintCategory.IsValid() {
+		return fmt.Errorf("%w: unknown complaint category %s", ErrInvalidTriageRule, rule.ComplaintCategory)
+	}
+	if rule.Parameter == domain.TriageParamComplaint {
+		if rule.ComplaintCategory == "" {
+			return fmt.Errorf("%w: complaint rules need a complaint category", ErrInvalidTriageRule)
+		}
+		return nil
+	}
+	if rule.Operator == "" {
+		return fmt.Errorf("%w: %s rules need an operator", ErrInvalidTriageRule, rule.Parameter)
+	}
+	return nil
+}
+
+// assessAcuity computes the acuity level of an assessment: the most urgent
+// level of the rules it matches, or the lowest level when none match. It
+// returns the names of the rules that set the level. age is -1 when unknown.
+func assessAcuity(rules []*domain.TriageRule, t *domain.VisitTriage, age int) (int, []string) {
+	level := domain.AcuityLevelLowest
+	var matched []string
+	for _, rule := range rules {
+		if rule.ComplaintCategory != "" && rule.ComplaintCategory != t.ComplaintCategory {
+			continue
+		}
+		if rule.Parameter != domain.TriageParamComplaint {
+			value, ok := triageFinding(rule.Parameter, t, age)
+			if !ok || !rule.Operator.Compare(value, rule.Threshold) {
+				continue
+			}
+		}
+
+		switch {
+		case rule.AcuityLevel < level:
+			level = rule.AcuityLevel
+			matched = []string{rule.Name}
+		case rule.AcuityLevel == level:
+			matched = append(matched, rule.Name)
+		}
+	}
+	return level, matched
+}
+
+// triageFinding returns the assessment's value of a rule parameter, or false
+// when it was not recorded
+func triageFinding(param domain.TriageParameter, t *domain.VisitTriage, age int) (float64, bool) {
+	switch param {
+	case domain.TriageParamTemperature:
+		if t.Temperature != nil {
+			return *t.Temperature, true
+		}
+	case domain.TriageParamHeartRate:
+		return intFinding(t.HeartRate)
+	case domain.TriageParamRespiratoryRate:
+		return intFinding(t.RespiratoryRate)
+	case domain.TriageParamOxygenSaturation:
+		return intFinding(t.OxygenSaturation)
+	case domain.TriageParamSystolicBP:
+		return intFinding(t.BloodPressureSystolic)
+	case domain.TriageParamPainScore:
+		return intFinding(t.PainScore)
+	case domain.TriageParamConsciousness:
+		if rank := t.Consciousness.Rank(); rank >= 0 {
+			return float64(rank), true
+		}
+	case domain.TriageParamAge:
+		if age >= 0 {
+			return float64(age), true
+		}
+	}
+	return 0, false
+}
+
+func intFinding(v *int) (float64, bool) {
+	if v == nil {
+		return 0, false
+	}
+	return float64(*v), true
+}
+
+// effectiveAcuity returns the level a patient is ordered at after waiting:
+// untriaged patients wait at the middle level, and patients who waited past
+// their level's target move up one level, though never to level 1, which is
+// reserved for immediate resuscitation
+func effectiveAcuity(level *int, waited time.Duration) int {
+	if level == nil {
+		return triageUntriagedLevel
+	}
+	l := *level
+	if l > 2 && waited > domain.TriageTargetWait(l) {
+		l--
+	}
+	return l
+}
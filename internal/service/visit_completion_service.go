@@ -0,0 +1,462 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/minhtran/his/internal/domain"
+	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/pkg/logger"
+	"github.com/minhtran/his/internal/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrVisitCompletionBlocked is returned when a required completion rule fails
+	ErrVisitCompletionBlocked = errors.New("visit cannot be completed")
+	// ErrVisitNotCompleted is returned when drafting the invoice of a visit that was not completed
+	ErrVisitNotCompleted = errors.New("only completed visits can be invoiced")
+	// ErrVisitAlreadyInvoiced is returned when drafting the invoice of a visit that already has one
+	ErrVisitAlreadyInvoiced = errors.New("visit already has an active invoice")
+	// ErrVisitNothingToInvoice is returned when a visit has no catalog charges to draft
+	ErrVisitNothingToInvoice = errors.New("visit has no charges to invoice")
+)
+
+// Completion rules reported in a visit's checklist
+const (
+	completionRulePrimaryDiagnosis = "PRIMARY_DIAGNOSIS"
+	completionRuleVitalSigns       = "VITAL_SIGNS"
+	completionRulePendingOrders    = "PENDING_ORDERS"
+	completionRuleNotesSigned      = "NOTES_SIGNED"
+)
+
+// VisitCompletionPolicy holds which completion rules block completing a
+// visit and how its consultation is charged
+type VisitCompletionPolicy struct {
+	RequirePrimaryDiagnosis   bool
+	RequireVitals             bool
+	RequireOrdersAcknowledged bool
+	RequireNotesSigned        bool
+	ConsultationServiceCode   string // Charged when the doctor's department has no consultation service
+}
+
+// VisitCompletionService checks the rules a visit must meet before it is
+// completed and drafts the invoice of a completed visit from catalog prices
+type VisitCompletionService struct {
+	visitRepo          *repository.VisitRepository
+	diagnosisRepo      *repository.DiagnosisRepository
+	labTestRequestRepo *repository.LabTestRequestRepository
+	imagingRequestRepo *repository.ImagingRequestRepository
+	prescriptionRepo   *repository.PrescriptionRepository
+	clinicalNoteRepo   *repository.ClinicalNoteRepository
+	medicalServiceRepo *repository.MedicalServiceRepository
+	invoiceRepo        *repository.InvoiceRepository
+	auditRepo          *repository.AuditLogRepository
+	invoiceService     *InvoiceService
+	policy             VisitCompletionPolicy
+}
+
+// NewVisitCompletionService creates a new visit completion service and
+// subscribes it to visits being completed
+func NewVisitCompletionService(
+	visitRepo *repository.VisitRepository,
+	diagnosisRepo *repository.DiagnosisRepository,
+	labTestRequestRepo *repository.LabTestRequestRepository,
+	imagingRequestRepo *repository.ImagingRequestRepository,
+	prescriptionRepo *repository.PrescriptionRepository,
+	clinicalNoteRepo *repository.ClinicalNoteRepository,
+	medicalServiceRepo *repository.MedicalServiceRepository,
+	invoiceRepo *repository.InvoiceRepository,
+	auditRepo *repository.AuditLogRepository,
+	invoiceService *InvoiceService,
+	visitService *VisitService,
+	policy VisitCompletionPolicy,
+) *VisitCompletionService {
+	s := &VisitCompletionService{
+		visitRepo:          visitRepo,
+		diagnosisRepo:      diagnosisRepo,
+		labTestRequestRepo: labTestRequestRepo,
+		imagingRequestRepo: imagingRequestRepo,
+		prescriptionRepo:   prescriptionRepo,
+		clinicalNoteRepo:   clinicalNoteRepo,
+		medicalServiceRepo: medicalServiceRepo,
+		invoiceRepo:        invoiceRepo,
+		auditRepo:          auditRepo,
+		invoiceService:     invoiceService,
+		policy:             policy,
+	}
+	visitService.OnCompleting(s.checkCompletion)
+	visitService.OnClosed(s.handleClosed)
+	return s
+}
+
+// GetChecklist reports a visit's completion rules, treating pending orders
+// as acknowledged when asked to
+func (s *VisitCompletionService) GetChecklist(visitID uint, acknowledgePendingOrders bool) (*dto.VisitCompletionChecklist, error) {
+	visit, err := s.visitRepo.FindByID(visitID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find visit: %w", err)
+	}
+	if visit == nil {
+		return nil, ErrVisitNotFound
+	}
+	return s.buildChecklist(visit, acknowledgePendingOrders)
+}
+
+// checkCompletion refuses to complete a visit while a required rule fails
+func (s *VisitCompletionService) checkCompletion(visit domain.Visit, req dto.CompleteVisitRequest) error {
+	checklist, err := s.buildChecklist(&visit, req.AcknowledgePendingOrders)
+	if err != nil {
+		return err
+	}
+	if checklist.CanComplete {
+		return nil
+	}
+
+	var reasons []string
+	for _, item := range checklist.Items {
+		if item.Blocking {
+			reasons = append(reasons, item.Message)
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrVisitCompletionBlocked, strings.Join(reasons, "; "))
+}
+
+// buildChecklist evaluates every completion rule, marking the failed rules
+// the policy requires as blocking
+func (s *VisitCompletionService) buildChecklist(visit *domain.Visit, acknowledgePendingOrders bool) (*dto.VisitCompletionChecklist, error) {
+	diagnoses, err := s.diagnosisRepo.FindByVisitID(visit.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find diagnoses: %w", err)
+	}
+	notes, err := s.clinicalNoteRepo.FindByVisit(visit.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find clinical notes: %w", err)
+	}
+	pendingOrders, err := s.findPendingOrders(visit.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	checklist := &dto.VisitCompletionChecklist{
+		VisitID:       visit.ID,
+		VisitCode:     visit.VisitCode,
+		Status:        string(visit.Status),
+		CanComplete:   true,
+		PendingOrders: pendingOrders,
+	}
+	add := func(rule string, passed, required bool, message string) {
+		item := &dto.VisitCompletionItem{
+			Rule:     rule,
+			Passed:   passed,
+			Required: required,
+			Blocking: required && !passed,
+			Message:  message,
+		}
+		if item.Blocking {
+			checklist.CanComplete = false
+		}
+		checklist.Items = append(checklist.Items, item)
+	}
+
+	hasPrimary := false
+	for _, diagnosis := range diagnoses {
+		if diagnosis.DiagnosisType == domain.DiagnosisTypePrimary && diagnosis.DiagnosisStatus != domain.DiagnosisStatusRuledOut {
+			hasPrimary = true
+			break
+		}
+	}
+	if hasPrimary {
+		add(completionRulePrimaryDiagnosis, true, s.policy.RequirePrimaryDiagnosis, "Primary diagnosis recorded")
+	} else {
+		add(completionRulePrimaryDiagnosis, false, s.policy.RequirePrimaryDiagnosis, "No primary diagnosis recorded")
+	}
+
+	if hasVitalSigns(visit.VitalSigns()) {
+		add(completionRuleVitalSigns, true, s.policy.RequireVitals, "Vital signs recorded")
+	} else {
+		add(completionRuleVitalSigns, false, s.policy.RequireVitals, "No vital signs recorded")
+	}
+
+	switch {
+	case len(pendingOrders) == 0:
+		add(completionRulePendingOrders, true, s.policy.RequireOrdersAcknowledged, "No pending lab or imaging orders")
+	case acknowledgePendingOrders:
+		add(completionRulePendingOrders, true, s.policy.RequireOrdersAcknowledged,
+			fmt.Sprintf("%d pending lab or imaging orders acknowledged", len(pendingOrders)))
+	default:
+		add(completionRulePendingOrders, false, s.policy.RequireOrdersAcknowledged,
+			fmt.Sprintf("%d lab or imaging orders are still pending and must be acknowledged", len(pendingOrders)))
+	}
+
+	unsigned := 0
+	for _, note := range notes {
+		if note.Status != domain.ClinicalNoteStatusSigned {
+			unsigned++
+		}
+	}
+	if unsigned == 0 {
+		add(completionRuleNotesSigned, true, s.policy.RequireNotesSigned, "All clinical notes signed")
+	} else {
+		add(completionRuleNotesSigned, false, s.policy.RequireNotesSigned,
+			fmt.Sprintf("%d clinical notes are unsigned or awaiting co-signature", unsigned))
+	}
+
+	return checklist, nil
+}
+
+// findPendingOrders finds a visit's lab and imaging orders that were
+// neither completed nor cancelled
+func (s *VisitCompletionService) findPendingOrders(visitID uint) ([]*dto.VisitPendingOrder, error) {
+	labRequests, err := s.labTestRequestRepo.FindByVisitID(visitID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find lab test requests: %w", err)
+	}
+	imagingRequests, err := s.imagingRequestRepo.FindByVisitID(visitID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find imaging requests: %w", err)
+	}
+
+	orders := []*dto.VisitPendingOrder{}
+	for _, request := range labRequests {
+		if request.Status == domain.LabTestRequestStatusCompleted || request.Status == domain.LabTestRequestStatusCancelled {
+			continue
+		}
+		order := &dto.VisitPendingOrder{
+			OrderType:   string(domain.ItemTypeLabTest),
+			ID:          request.ID,
+			RequestCode: request.RequestCode,
+			Status:      string(request.Status),
+		}
+		if request.Template != nil {
+			order.Name = request.Template.Name
+		}
+		orders = append(orders, order)
+	}
+	for _, request := range imagingRequests {
+		if request.Status == domain.ImagingRequestStatusCompleted || request.Status == domain.ImagingRequestStatusCancelled {
+			continue
+		}
+		order := &dto.VisitPendingOrder{
+			OrderType:   string(domain.ItemTypeImaging),
+			ID:          request.ID,
+			RequestCode: request.RequestCode,
+			Status:      string(request.Status),
+		}
+		if request.Template != nil {
+			order.Name = request.Template.Name
+		}
+		orders = append(orders, order)
+	}
+	return orders, nil
+}
+
+// DraftInvoice drafts the invoice of a completed visit that has none, for
+// when drafting it on completion failed or the invoice was cancelled
+func (s *VisitCompletionService) DraftInvoice(visitID uint, createdBy uint) (*dto.InvoiceResponse, error) {
+	visit, err := s.visitRepo.FindByID(visitID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find visit: %w", err)
+	}
+	if visit == nil {
+		return nil, ErrVisitNotFound
+	}
+	if visit.Status != domain.VisitStatusCompleted {
+		return nil, ErrVisitNotCompleted
+	}
+
+	invoice, err := s.draftInvoice(visit, createdBy)
+	if err != nil {
+		return nil, err
+	}
+	return s.invoiceService.toInvoiceResponse(invoice), nil
+}
+
+// handleClosed drafts the invoice of a completed visit; a draft that fails
+// is logged and can be retried through DraftInvoice
+func (s *VisitCompletionService) handleClosed(visit domain.Visit) {
+	if visit.Status != domain.VisitStatusCompleted {
+		return
+	}
+	createdBy := visit.UpdatedBy
+	if createdBy == 0 {
+		createdBy = visit.DoctorID
+	}
+	_, err := s.draftInvoice(&visit, createdBy)
+	if err != nil && !errors.Is(err, ErrVisitAlreadyInvoiced) && !errors.Is(err, ErrVisitNothingToInvoice) {
+		logger.Error("Failed to draft visit invoice", zap.Uint("visit_id", visit.ID), zap.Error(err))
+	}
+}
+
+// draftInvoice charges a visit's consultation, its lab and imaging orders
+// that were not cancelled and its prescribed medications at catalog prices
+// on a draft invoice, unless the visit was already invoiced. Items without a
+// catalog price are left off and listed in the invoice notes
+func (s *VisitCompletionService) draftInvoice(visit *domain.Visit, createdBy uint) (*domain.Invoice, error) {
+	existing, err := s.invoiceRepo.FindActiveByVisitID(visit.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find visit invoice: %w", err)
+	}
+	if existing != nil {
+		return nil, ErrVisitAlreadyInvoiced
+	}
+
+	var items []*domain.InvoiceItem
+	var notes, unpriced []string
+	add := func(item *domain.InvoiceItem) {
+		if item.UnitPrice <= 0 {
+			unpriced = append(unpriced, item.Description)
+			return
+		}
+		items = append(items, item)
+	}
+
+	consultation, err := s.findConsultationService(visit)
+	if err != nil {
+		return nil, err
+	}
+	if consultation != nil {
+		add(&domain.InvoiceItem{
+			ItemType:    domain.ItemTypeConsultation,
+			ItemID:      &consultation.ID,
+			Description: consultation.Name,
+			Quantity:    1,
+			UnitPrice:   consultation.BasePrice,
+		})
+	} else {
+		notes = append(notes, "No consultation service found for the doctor's department; add the consultation charge before issuing.")
+	}
+
+	labRequests, err := s.labTestRequestRepo.FindByVisitID(visit.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find lab test requests: %w", err)
+	}
+	for _, request := range labRequests {
+		if request.Status == domain.LabTestRequestStatusCancelled || request.Template == nil {
+			continue
+		}
+		requestID := request.ID
+		add(&domain.InvoiceItem{
+			ItemType:    domain.ItemTypeLabTest,
+			ItemID:      &requestID,
+			Description: fmt.Sprintf("%s (%s)", request.Template.Name, request.RequestCode),
+			Quantity:    1,
+			UnitPrice:   request.Template.Price,
+		})
+	}
+
+	imagingRequests, err := s.imagingRequestRepo.FindByVisitID(visit.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find imaging requests: %w", err)
+	}
+	for _, request := range imagingRequests {
+		if request.Status == domain.ImagingRequestStatusCancelled || request.Template == nil {
+			continue
+		}
+		requestID := request.ID
+		add(&domain.InvoiceItem{
+			ItemType:    domain.ItemTypeImaging,
+			ItemID:      &requestID,
+			Description: fmt.Sprintf("%s (%s)", request.Template.Name, request.RequestCode),
+			Quantity:    1,
+			UnitPrice:   request.Template.Price,
+		})
+	}
+
+	prescriptions, err := s.prescriptionRepo.FindByVisitID(visit.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find prescriptions: %w", err)
+	}
+	for _, prescription := range prescriptions {
+		if prescription.Status == domain.PrescriptionStatusCancelled {
+			continue
+		}
+		for _, item := range prescription.Items {
+			if item.Medication == nil || item.Quantity <= 0 {
+				continue
+			}
+			medicationID := item.MedicationID
+			description := item.Medication.Name
+			if item.Medication.Strength != "" {
+				description += " " + item.Medication.Strength
+			}
+			add(&domain.InvoiceItem{
+				ItemType:    domain.ItemTypeMedication,
+				ItemID:      &medicationID,
+				Description: fmt.Sprintf("%s (%s)", description, prescription.PrescriptionCode),
+				Quantity:    item.Quantity,
+				UnitPrice:   item.Medication.UnitPrice,
+			})
+		}
+	}
+
+	if len(unpriced) > 0 {
+		notes = append(notes, fmt.Sprintf("No catalog price for %s; add these charges before issuing.", strings.Join(unpriced, ", ")))
+	}
+	if len(items) == 0 && len(unpriced) == 0 {
+		return nil, ErrVisitNothingToInvoice
+	}
+
+	notes = append([]string{fmt.Sprintf("Drafted from completed visit %s.", visit.VisitCode)}, notes...)
+
+	// Check again and create the draft under the visit's lock, so concurrent
+	// drafts cannot both invoice the visit
+	var invoice *domain.Invoice
+	err = s.invoiceRepo.WithVisitLock(visit.ID, func(repo *repository.InvoiceRepository) error {
+		existing, err := repo.FindActiveByVisitID(visit.ID)
+		if err != nil {
+			return fmt.Errorf("failed to find visit invoice: %w", err)
+		}
+		if existing != nil {
+			return ErrVisitAlreadyInvoiced
+		}
+		invoice, err = s.invoiceService.createDraft(repo, visit, items, strings.Join(notes, " "), createdBy)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditRepo.Create(&domain.AuditLog{
+		UserID:     &createdBy,
+		Action:     domain.AuditActionCreate,
+		Resource:   "Invoice",
+		ResourceID: fmt.Sprintf("%d", invoice.ID),
+		Details: domain.AuditDetails{
+			"invoice_code": invoice.InvoiceCode,
+			"visit_id":     visit.ID,
+			"patient_id":   visit.PatientID,
+			"status":       invoice.Status,
+			"items":        len(items),
+			"total_amount": invoice.TotalAmount,
+		},
+	})
+	return invoice, nil
+}
+
+// findConsultationService finds the consultation service of the visit
+// doctor's department, falling back to the configured service
+func (s *VisitCompletionService) findConsultationService(visit *domain.Visit) (*domain.MedicalService, error) {
+	if visit.Doctor != nil && visit.Doctor.DepartmentID != nil {
+		service, err := s.medicalServiceRepo.FindConsultation(*visit.Doctor.DepartmentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find consultation service: %w", err)
+		}
+		if service != nil {
+			return service, nil
+		}
+	}
+
+	if s.policy.ConsultationServiceCode == "" {
+		return nil, nil
+	}
+	service, err := s.medicalServiceRepo.FindByCode(s.policy.ConsultationServiceCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find consultation service: %w", err)
+	}
+	if service == nil || !service.IsActive {
+		return nil, nil
+	}
+	return service, nil
+}
@@ -3,23 +3,53 @@ package service
 import (
 	"errors"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/minhtran/his/internal/domain"
 	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/pkg/clock"
+	"github.com/minhtran/his/internal/pkg/logger"
 	"github.com/minhtran/his/internal/repository"
+	"go.uber.org/zap"
 )
 
 var (
 	ErrVisitNotFound = errors.New("visit not found")
+	// ErrInvalidGrouping is returned when analytics are grouped by an unknown field
+	ErrInvalidGrouping = errors.New("invalid grouping, use doctor, department or hour")
+	// ErrImplausibleVitalSigns is returned when vital signs contradict each other
+	ErrImplausibleVitalSigns = errors.New("implausible vital signs")
+	// ErrVisitDocumentationLocked is returned when editing the documentation of a closed visit
+	ErrVisitDocumentationLocked = errors.New("documentation of completed or cancelled visits cannot be edited, add a clinical note addendum instead")
 )
 
+// visitAnalyticsDays is the default and longest period wait-time analytics cover
+const (
+	visitAnalyticsDefaultDays = 30
+	visitAnalyticsMaxDays     = 366
+)
+
+// VisitListener is called with a visit, loaded with its patient and doctor
+type VisitListener func(visit domain.Visit)
+
+// VisitCompletionGuard is called before a visit is completed, with the visit
+// loaded with its patient and doctor, and blocks completion by returning an error
+type VisitCompletionGuard func(visit domain.Visit, req dto.CompleteVisitRequest) error
+
 // VisitService handles visit business logic
 type VisitService struct {
-	visitRepo       *repository.VisitRepository
-	patientRepo     *repository.PatientRepository
-	userRepo        *repository.UserRepository
-	appointmentRepo *repository.AppointmentRepository
+	visitRepo          *repository.VisitRepository
+	patientRepo        *repository.PatientRepository
+	userRepo           *repository.UserRepository
+	appointmentRepo    *repository.AppointmentRepository
+	coverageRepo       *repository.PatientCoverageRepository
+	appointmentService *AppointmentService
+	checkInListeners   []VisitListener
+	startListeners     []VisitListener
+	closeListeners     []VisitListener
+	completionGuards   []VisitCompletionGuard
+	clock              *clock.Clock
 }
 
 // NewVisitService creates a new visit service
@@ -28,15 +58,41 @@ func NewVisitService(
 	patientRepo *repository.PatientRepository,
 	userRepo *repository.UserRepository,
 	appointmentRepo *repository.AppointmentRepository,
+	coverageRepo *repository.PatientCoverageRepository,
+	appointmentService *AppointmentService,
+	clk *clock.Clock,
 ) *VisitService {
 	return &VisitService{
-		visitRepo:       visitRepo,
-		patientRepo:     patientRepo,
-		userRepo:        userRepo,
-		appointmentRepo: appointmentRepo,
+		visitRepo:          visitRepo,
+		patientRepo:        patientRepo,
+		userRepo:           userRepo,
+		appointmentRepo:    appointmentRepo,
+		coverageRepo:       coverageRepo,
+		appointmentService: appointmentService,
+		clock:              clk,
 	}
 }
 
+// OnCheckedIn registers a listener called after a visit is created
+func (s *VisitService) OnCheckedIn(listener VisitListener) {
+	s.checkInListeners = append(s.checkInListeners, listener)
+}
+
+// OnStarted registers a listener called after a visit's consultation starts
+func (s *VisitService) OnStarted(listener VisitListener) {
+	s.startListeners = append(s.startListeners, listener)
+}
+
+// OnClosed registers a listener called after a visit is completed or cancelled
+func (s *VisitService) OnClosed(listener VisitListener) {
+	s.closeListeners = append(s.closeListeners, listener)
+}
+
+// OnCompleting registers a guard that can refuse to complete a visit
+func (s *VisitService) OnCompleting(guard VisitCompletionGuard) {
+	s.completionGuards = append(s.completionGuards, guard)
+}
+
 // CreateVisit creates a new visit
 func (s *VisitService) CreateVisit(req *dto.CreateVisitRequest, createdBy uint) (*dto.VisitResponse, error) {
 	// Validate patient exists
@@ -47,6 +103,9 @@ func (s *VisitService) CreateVisit(req *dto.CreateVisitRequest, createdBy uint)
 	if patient == nil {
 		return nil, ErrPatientNotFound
 	}
+	if patient.IsDeceased {
+		return nil, ErrPatientDeceased
+	}
 
 	// Validate doctor exists
 	doctor, err := s.userRepo.FindByID(req.DoctorID)
@@ -69,6 +128,23 @@ func (s *VisitService) CreateVisit(req *dto.CreateVisitRequest, createdBy uint)
 		if appointment.PatientID != req.PatientID {
 			return nil, errors.New("appointment does not belong to this patient")
 		}
+		if !appointment.Status.CanTransitionTo(domain.AppointmentStatusInProgress) {
+			return nil, fmt.Errorf("%w: cannot start a visit for a %s appointment", ErrInvalidStatusTransition, appointment.Status)
+		}
+	}
+
+	now := s.clock.Now()
+
+	// Validate insurance coverage, defaulting to the patient's active primary coverage
+	var coverageID *uint
+	if !req.SelfPay {
+		coverage, err := resolveCoverage(s.coverageRepo, req.PatientID, now, req.CoverageID)
+		if err != nil {
+			return nil, err
+		}
+		if coverage != nil {
+			coverageID = &coverage.ID
+		}
 	}
 
 	// Generate visit code
@@ -77,16 +153,17 @@ func (s *VisitService) CreateVisit(req *dto.CreateVisitRequest, createdBy uint)
 		return nil, fmt.Errorf("failed to generate visit code: %w", err)
 	}
 
-	now := time.Now()
 	visit := &domain.Visit{
 		VisitCode:      code,
 		AppointmentID:  req.AppointmentID,
 		PatientID:      req.PatientID,
 		DoctorID:       req.DoctorID,
+		CoverageID:     coverageID,
 		VisitDate:      now,
 		VisitTime:      now,
 		VisitType:      domain.VisitType(req.VisitType),
 		Status:         domain.VisitStatusWaiting,
+		CheckedInAt:    now,
 		ChiefComplaint: req.ChiefComplaint,
 		CreatedBy:      createdBy,
 	}
@@ -101,6 +178,11 @@ func (s *VisitService) CreateVisit(req *dto.CreateVisitRequest, createdBy uint)
 		visit.OxygenSaturation = req.VitalSigns.OxygenSaturation
 		visit.Weight = req.VitalSigns.Weight
 		visit.Height = req.VitalSigns.Height
+		visit.SupplementalOxygen = req.VitalSigns.SupplementalOxygen
+		visit.Consciousness = domain.Consciousness(req.VitalSigns.Consciousness)
+		if err := validateVitalSigns(visit.VitalSigns()); err != nil {
+			return nil, err
+		}
 	}
 
 	if err := s.visitRepo.Create(visit); err != nil {
@@ -109,15 +191,14 @@ func (s *VisitService) CreateVisit(req *dto.CreateVisitRequest, createdBy uint)
 
 	// Update appointment status to IN_PROGRESS if linked
 	if req.AppointmentID != nil {
-		appointment, _ := s.appointmentRepo.FindByID(*req.AppointmentID)
-		if appointment != nil {
-			appointment.Status = domain.AppointmentStatusInProgress
-			s.appointmentRepo.Update(appointment)
-		}
+		s.moveAppointment(*req.AppointmentID, domain.AppointmentStatusInProgress, createdBy)
 	}
 
 	// Reload to get relationships
 	visit, _ = s.visitRepo.FindByID(visit.ID)
+	for _, listener := range s.checkInListeners {
+		listener(*visit)
+	}
 	return s.toVisitResponse(visit), nil
 }
 
@@ -130,6 +211,9 @@ func (s *VisitService) UpdateVisit(id uint, req *dto.UpdateVisitRequest, updated
 	if visit == nil {
 		return nil, ErrVisitNotFound
 	}
+	if visit.Status.IsClosed() && (req.Symptoms != "" || req.PhysicalExamination != "" || req.ClinicalNotes != "" || req.TreatmentPlan != "") {
+		return nil, ErrVisitDocumentationLocked
+	}
 
 	// Update fields
 	if req.Symptoms != "" {
@@ -181,6 +265,15 @@ func (s *VisitService) UpdateVisit(id uint, req *dto.UpdateVisitRequest, updated
 		if req.VitalSigns.Height > 0 {
 			visit.Height = req.VitalSigns.Height
 		}
+		if req.VitalSigns.SupplementalOxygen != nil {
+			visit.SupplementalOxygen = req.VitalSigns.SupplementalOxygen
+		}
+		if req.VitalSigns.Consciousness != "" {
+			visit.Consciousness = domain.Consciousness(req.VitalSigns.Consciousness)
+		}
+		if err := validateVitalSigns(visit.VitalSigns()); err != nil {
+			return nil, err
+		}
 	}
 
 	visit.UpdatedBy = updatedBy
@@ -193,61 +286,229 @@ func (s *VisitService) UpdateVisit(id uint, req *dto.UpdateVisitRequest, updated
 	return s.toVisitResponse(visit), nil
 }
 
-// CompleteVisit marks visit as completed
-func (s *VisitService) CompleteVisit(id uint, updatedBy uint) error {
-	visit, err := s.visitRepo.FindByID(id)
+// StartVisit starts a waiting visit's consultation, or resumes one on hold
+func (s *VisitService) StartVisit(id uint, updatedBy uint) (*dto.VisitResponse, error) {
+	visit, err := s.findVisit(id)
 	if err != nil {
-		return fmt.Errorf("failed to find visit: %w", err)
+		return nil, err
 	}
-	if visit == nil {
-		return ErrVisitNotFound
+
+	now := s.clock.Now()
+	if visit.ConsultationStartedAt == nil {
+		visit.ConsultationStartedAt = &now
+	}
+	if err := s.transition(visit, domain.VisitStatusInProgress, "", updatedBy, now); err != nil {
+		return nil, err
 	}
 
-	visit.Status = domain.VisitStatusCompleted
-	visit.UpdatedBy = updatedBy
+	for _, listener := range s.startListeners {
+		listener(*visit)
+	}
+	return s.toVisitResponse(visit), nil
+}
 
-	if err := s.visitRepo.Update(visit); err != nil {
-		return fmt.Errorf("failed to complete visit: %w", err)
+// PauseVisit puts a visit's consultation on hold, by default while waiting for results
+func (s *VisitService) PauseVisit(id uint, req *dto.PauseVisitRequest, updatedBy uint) (*dto.VisitResponse, error) {
+	visit, err := s.findVisit(id)
+	if err != nil {
+		return nil, err
+	}
+
+	reason := domain.VisitPauseReason(req.Reason)
+	if reason == "" {
+		reason = domain.VisitPauseAwaitingResults
+	}
+
+	now := s.clock.Now()
+	visit.PausedAt = &now
+	visit.PauseReason = reason
+
+	note := string(reason)
+	if req.Note != "" {
+		note += ": " + req.Note
+	}
+	if err := s.transition(visit, domain.VisitStatusPaused, note, updatedBy, now); err != nil {
+		return nil, err
+	}
+	return s.toVisitResponse(visit), nil
+}
+
+// CompleteVisit ends a visit's consultation and discharges the patient
+func (s *VisitService) CompleteVisit(id uint, req *dto.CompleteVisitRequest, updatedBy uint) error {
+	visit, err := s.findVisit(id)
+	if err != nil {
+		return err
+	}
+	if !visit.Status.CanTransitionTo(domain.VisitStatusCompleted) {
+		return fmt.Errorf("%w: cannot move from %s to %s", ErrInvalidStatusTransition, visit.Status, domain.VisitStatusCompleted)
+	}
+	for _, guard := range s.completionGuards {
+		if err := guard(*visit, *req); err != nil {
+			return err
+		}
+	}
+
+	now := s.clock.Now()
+	visit.ConsultationEndedAt = &now
+	visit.DischargedAt = &now
+	if err := s.transition(visit, domain.VisitStatusCompleted, "", updatedBy, now); err != nil {
+		return err
 	}
 
 	// Update appointment status to COMPLETED if linked
 	if visit.AppointmentID != nil {
-		appointment, _ := s.appointmentRepo.FindByID(*visit.AppointmentID)
-		if appointment != nil {
-			appointment.Status = domain.AppointmentStatusCompleted
-			s.appointmentRepo.Update(appointment)
-		}
+		s.moveAppointment(*visit.AppointmentID, domain.AppointmentStatusCompleted, updatedBy)
 	}
 
+	for _, listener := range s.closeListeners {
+		listener(*visit)
+	}
 	return nil
 }
 
+// moveAppointment moves a linked appointment along with its visit through
+// the appointment transition table. The visit change is already saved, so a
+// failure is logged rather than returned.
+func (s *VisitService) moveAppointment(appointmentID uint, to domain.AppointmentStatus, changedBy uint) {
+	if err := s.appointmentService.updateStatus(appointmentID, to, changedBy); err != nil {
+		logger.Error("Failed to move appointment with its visit",
+			zap.Uint("appointment_id", appointmentID), zap.String("to", string(to)), zap.Error(err))
+	}
+}
+
 // CancelVisit cancels a visit
 func (s *VisitService) CancelVisit(id uint, updatedBy uint) error {
-	visit, err := s.visitRepo.FindByID(id)
+	visit, err := s.findVisit(id)
 	if err != nil {
-		return fmt.Errorf("failed to find visit: %w", err)
+		return err
 	}
-	if visit == nil {
-		return ErrVisitNotFound
+
+	now := s.clock.Now()
+	visit.DischargedAt = &now
+	if err := s.transition(visit, domain.VisitStatusCancelled, "", updatedBy, now); err != nil {
+		return err
 	}
 
-	visit.Status = domain.VisitStatusCancelled
-	visit.UpdatedBy = updatedBy
+	for _, listener := range s.closeListeners {
+		listener(*visit)
+	}
+	return nil
+}
+
+// transition moves a visit to a new status if the transition table allows
+// it, adding any time spent on hold and recording the change in the status
+// history
+func (s *VisitService) transition(visit *domain.Visit, to domain.VisitStatus, reason string, changedBy uint, now time.Time) error {
+	from := visit.Status
+	if !from.CanTransitionTo(to) {
+		return fmt.Errorf("%w: cannot move from %s to %s", ErrInvalidStatusTransition, from, to)
+	}
+
+	if from == domain.VisitStatusPaused && visit.PausedAt != nil {
+		visit.PausedSeconds += int(now.Sub(*visit.PausedAt).Seconds())
+	}
+	visit.Status = to
+	visit.UpdatedBy = changedBy
 
-	return s.visitRepo.Update(visit)
+	history := &domain.VisitStatusHistory{
+		VisitID:    visit.ID,
+		FromStatus: from,
+		ToStatus:   to,
+		Reason:     reason,
+		ChangedAt:  now,
+		ChangedBy:  changedBy,
+	}
+	if err := s.visitRepo.UpdateStatus(visit, history); err != nil {
+		return fmt.Errorf("failed to update visit status: %w", err)
+	}
+	return nil
 }
 
-// GetVisitByID gets visit by ID
+// GetVisitByID gets visit by ID with its status history
 func (s *VisitService) GetVisitByID(id uint) (*dto.VisitResponse, error) {
-	visit, err := s.visitRepo.FindByID(id)
+	visit, err := s.findVisit(id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find visit: %w", err)
+		return nil, err
 	}
-	if visit == nil {
-		return nil, ErrVisitNotFound
+
+	history, err := s.visitRepo.FindStatusHistory(visit.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find visit status history: %w", err)
+	}
+
+	resp := s.toVisitResponse(visit)
+	resp.StatusHistory = make([]*dto.VisitStatusChange, len(history))
+	for i, h := range history {
+		resp.StatusHistory[i] = &dto.VisitStatusChange{
+			FromStatus: string(h.FromStatus),
+			ToStatus:   string(h.ToStatus),
+			Reason:     h.Reason,
+			ChangedAt:  h.ChangedAt,
+			ChangedBy:  h.ChangedBy,
+		}
+		if h.User != nil {
+			resp.StatusHistory[i].ChangedByName = h.User.FullName
+		}
 	}
-	return s.toVisitResponse(visit), nil
+	return resp, nil
+}
+
+// GetWaitTimeAnalytics averages how long visits waited for and spent in
+// consultation between two dates, the last 30 days by default, overall and
+// grouped by doctor, department or hour of check-in
+func (s *VisitService) GetWaitTimeAnalytics(fromStr, toStr, groupBy string, filters map[string]interface{}) (*dto.VisitWaitAnalyticsResponse, error) {
+	switch groupBy {
+	case "doctor", "department", "hour":
+	default:
+		return nil, ErrInvalidGrouping
+	}
+
+	to := s.clock.Today()
+	if toStr != "" {
+		parsed, err := time.Parse("2006-01-02", toStr)
+		if err != nil {
+			return nil, ErrInvalidDateFormat
+		}
+		to = parsed
+	}
+	from := to.AddDate(0, 0, -(visitAnalyticsDefaultDays - 1))
+	if fromStr != "" {
+		parsed, err := time.Parse("2006-01-02", fromStr)
+		if err != nil {
+			return nil, ErrInvalidDateFormat
+		}
+		from = parsed
+	}
+	if to.Before(from) {
+		return nil, ErrInvalidDateRange
+	}
+	if to.Sub(from) >= visitAnalyticsMaxDays*24*time.Hour {
+		return nil, fmt.Errorf("%w: analytics cover at most %d days", ErrInvalidDateRange, visitAnalyticsMaxDays)
+	}
+
+	overall, err := s.visitRepo.WaitTimeStats(from, to, "", filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute wait times: %w", err)
+	}
+	groups, err := s.visitRepo.WaitTimeStats(from, to, groupBy, filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute wait times: %w", err)
+	}
+
+	resp := &dto.VisitWaitAnalyticsResponse{
+		FromDate: from.Format("2006-01-02"),
+		ToDate:   to.Format("2006-01-02"),
+		GroupBy:  groupBy,
+		Overall:  &dto.VisitWaitStats{},
+		Groups:   make([]*dto.VisitWaitStats, len(groups)),
+	}
+	if len(overall) > 0 {
+		resp.Overall = toVisitWaitStats(overall[0], "")
+	}
+	for i, g := range groups {
+		resp.Groups[i] = toVisitWaitStats(g, groupBy)
+	}
+	return resp, nil
 }
 
 // GetVisitByCode gets visit by code
@@ -310,6 +571,17 @@ func (s *VisitService) SearchVisits(filters map[string]interface{}, page, pageSi
 }
 
 // Helper functions
+func (s *VisitService) findVisit(id uint) (*domain.Visit, error) {
+	visit, err := s.visitRepo.FindByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find visit: %w", err)
+	}
+	if visit == nil {
+		return nil, ErrVisitNotFound
+	}
+	return visit, nil
+}
+
 func (s *VisitService) toVisitResponse(v *domain.Visit) *dto.VisitResponse {
 	resp := &dto.VisitResponse{
 		ID:                     v.ID,
@@ -317,12 +589,22 @@ func (s *VisitService) toVisitResponse(v *domain.Visit) *dto.VisitResponse {
 		AppointmentID:          v.AppointmentID,
 		PatientID:              v.PatientID,
 		DoctorID:               v.DoctorID,
+		CoverageID:             v.CoverageID,
 		VisitDate:              v.VisitDate.Format("2006-01-02"),
 		VisitTime:              v.VisitTime.Format("15:04"),
 		VisitType:              string(v.VisitType),
 		Status:                 string(v.Status),
 		ChiefComplaint:         v.ChiefComplaint,
 		Symptoms:               v.Symptoms,
+		AcuityLevel:            v.AcuityLevel,
+		TriagedAt:              v.TriagedAt,
+		CheckedInAt:            v.CheckedInAt,
+		ConsultationStartedAt:  v.ConsultationStartedAt,
+		PausedAt:               v.PausedAt,
+		PauseReason:            string(v.PauseReason),
+		PausedSeconds:          v.PausedSeconds,
+		ConsultationEndedAt:    v.ConsultationEndedAt,
+		DischargedAt:           v.DischargedAt,
 		Temperature:            v.Temperature,
 		BloodPressureSystolic:  v.BloodPressureSystolic,
 		BloodPressureDiastolic: v.BloodPressureDiastolic,
@@ -332,6 +614,8 @@ func (s *VisitService) toVisitResponse(v *domain.Visit) *dto.VisitResponse {
 		Weight:                 v.Weight,
 		Height:                 v.Height,
 		BMI:                    v.BMI,
+		SupplementalOxygen:     v.SupplementalOxygen,
+		Consciousness:          string(v.Consciousness),
 		PhysicalExamination:    v.PhysicalExamination,
 		ClinicalNotes:          v.ClinicalNotes,
 		TreatmentPlan:          v.TreatmentPlan,
@@ -347,6 +631,9 @@ func (s *VisitService) toVisitResponse(v *domain.Visit) *dto.VisitResponse {
 	if v.Doctor != nil {
 		resp.DoctorName = v.Doctor.FullName
 	}
+	if vitals := v.VitalSigns(); hasVitalSigns(vitals) {
+		resp.NEWS2 = toNEWS2Response(domain.ScoreNEWS2(vitals))
+	}
 
 	return resp
 }
@@ -360,6 +647,7 @@ func (s *VisitService) toVisitListItem(v *domain.Visit) *dto.VisitListItem {
 		VisitType:      string(v.VisitType),
 		Status:         string(v.Status),
 		ChiefComplaint: v.ChiefComplaint,
+		AcuityLevel:    v.AcuityLevel,
 	}
 
 	if v.Patient != nil {
@@ -371,3 +659,65 @@ func (s *VisitService) toVisitListItem(v *domain.Visit) *dto.VisitListItem {
 
 	return item
 }
+
+// toVisitWaitStats converts a stats row to minutes, labelling hour groups
+func toVisitWaitStats(row *repository.VisitWaitStats, groupBy string) *dto.VisitWaitStats {
+	stats := &dto.VisitWaitStats{
+		Name:                   row.GroupName,
+		Visits:                 row.Visits,
+		Completed:              row.Completed,
+		AvgTriageMinutes:       secondsToMinutes(row.AvgTriageSeconds),
+		AvgWaitMinutes:         secondsToMinutes(row.AvgWaitSeconds),
+		AvgConsultationMinutes: secondsToMinutes(row.AvgConsultationSeconds),
+		AvgPausedMinutes:       secondsToMinutes(row.AvgPausedSeconds),
+		AvgTotalMinutes:        secondsToMinutes(row.AvgCheckInToEndSeconds),
+	}
+	if groupBy != "" {
+		key := row.GroupKey
+		stats.Key = &key
+	}
+	if groupBy == "hour" {
+		stats.Name = fmt.Sprintf("%02d:00", row.GroupKey)
+	}
+	return stats
+}
+
+// secondsToMinutes converts an average in seconds to minutes with one decimal
+func secondsToMinutes(seconds *float64) *float64 {
+	if seconds == nil {
+		return nil
+	}
+	minutes := math.Round(*seconds/6) / 10
+	return &minutes
+}
+
+// validateVitalSigns checks that vital signs, each within plausible ranges
+// from request binding, are plausible together
+func validateVitalSigns(v domain.VitalSigns) error {
+	if v.BloodPressureSystolic > 0 && v.BloodPressureDiastolic > 0 && v.BloodPressureDiastolic >= v.BloodPressureSystolic {
+		return fmt.Errorf("%w: diastolic blood pressure must be below systolic", ErrImplausibleVitalSigns)
+	}
+	return nil
+}
+
+// hasVitalSigns reports whether any parameter NEWS2 scores was measured
+func hasVitalSigns(v domain.VitalSigns) bool {
+	return v.Temperature > 0 || v.BloodPressureSystolic > 0 || v.HeartRate > 0 ||
+		v.RespiratoryRate > 0 || v.OxygenSaturation > 0 || v.SupplementalOxygen != nil || v.Consciousness != ""
+}
+
+func toNEWS2Response(score domain.NEWS2Score) *dto.NEWS2Response {
+	return &dto.NEWS2Response{
+		Total:                    score.Total,
+		Risk:                     string(score.Risk),
+		Complete:                 score.Complete,
+		RedScore:                 score.RedScore,
+		RespiratoryRatePoints:    score.RespiratoryRatePoints,
+		OxygenSaturationPoints:   score.OxygenSaturationPoints,
+		SupplementalOxygenPoints: score.SupplementalOxygenPoints,
+		SystolicBPPoints:         score.SystolicBPPoints,
+		HeartRatePoints:          score.HeartRatePoints,
+		ConsciousnessPoints:      score.ConsciousnessPoints,
+		TemperaturePoints:        score.TemperaturePoints,
+	}
+}
@@ -0,0 +1,600 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/minhtran/his/internal/domain"
+	"github.com/minhtran/his/internal/dto"
+	"github.com/minhtran/his/internal/pkg/clock"
+	"github.com/minhtran/his/internal/pkg/logger"
+	"github.com/minhtran/his/internal/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrWaitlistEntryNotFound is returned when the waitlist entry is not found
+	ErrWaitlistEntryNotFound = errors.New("waitlist entry not found")
+	// ErrWaitlistOfferNotFound is returned when the waitlist offer is not found
+	ErrWaitlistOfferNotFound = errors.New("waitlist offer not found")
+	// ErrWaitlistTargetRequired is returned when an entry names neither a doctor nor a department
+	ErrWaitlistTargetRequired = errors.New("doctor_id or department_id is required")
+	// ErrDuplicateWaitlistEntry is returned when the patient is already waiting for the same doctor or department
+	ErrDuplicateWaitlistEntry = errors.New("patient is already on this waitlist")
+	// ErrInvalidPreferredTime is returned when the preferred time window is incomplete or empty
+	ErrInvalidPreferredTime = errors.New("preferred_time_from and preferred_time_to must be given together and the window must end after it starts")
+	// ErrWaitlistEntryClosed is returned when changing an entry that is booked, cancelled or expired
+	ErrWaitlistEntryClosed = errors.New("waitlist entry is no longer open")
+	// ErrWaitlistOfferNotPending is returned when answering an offer that was already answered or expired
+	ErrWaitlistOfferNotPending = errors.New("waitlist offer is no longer pending")
+	// ErrWaitlistOfferExpired is returned when accepting an offer after its hold period
+	ErrWaitlistOfferExpired = errors.New("waitlist offer has expired")
+)
+
+// WaitlistService keeps patients waiting for a doctor or department and offers
+// them slots freed by cancellations and reschedules
+type WaitlistService struct {
+	waitlistRepo       *repository.WaitlistRepository
+	appointmentRepo    *repository.AppointmentRepository
+	patientRepo        *repository.PatientRepository
+	userRepo           *repository.UserRepository
+	departmentRepo     *repository.DepartmentRepository
+	appointmentService *AppointmentService
+	holdPeriod         time.Duration
+	clock              *clock.Clock
+}
+
+// NewWaitlistService creates a new waitlist service and subscribes it to
+// slots released by the appointment service
+func NewWaitlistService(
+	waitlistRepo *repository.WaitlistRepository,
+	appointmentRepo *repository.AppointmentRepository,
+	patientRepo *repository.PatientRepository,
+	userRepo *repository.UserRepository,
+	departmentRepo *repository.DepartmentRepository,
+	appointmentService *AppointmentService,
+	holdPeriod time.Duration,
+	clk *clock.Clock,
+) *WaitlistService {
+	s := &WaitlistService{
+		waitlistRepo:       waitlistRepo,
+		appointmentRepo:    appointmentRepo,
+		patientRepo:        patientRepo,
+		userRepo:           userRepo,
+		departmentRepo:     departmentRepo,
+		appointmentService: appointmentService,
+		holdPeriod:         holdPeriod,
+		clock:              clk,
+	}
+	appointmentService.OnSlotReleased(s.handleSlotReleased)
+	return s
+}
+
+// waitlistSlot is a free slot that can be offered to the waitlist
+type waitlistSlot struct {
+	doctorID            uint
+	date                time.Time
+	time                time.Time
+	duration            int
+	sourceAppointmentID *uint
+	skipPatientID       uint // The patient who gave the slot up
+}
+
+// CreateEntry puts a patient on the waitlist
+func (s *WaitlistService) CreateEntry(req *dto.CreateWaitlistEntryRequest, createdBy uint) (*dto.WaitlistEntryResponse, error) {
+	if req.DoctorID == nil && req.DepartmentID == nil {
+		return nil, ErrWaitlistTargetRequired
+	}
+
+	patient, err := s.patientRepo.FindByID(req.PatientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find patient: %w", err)
+	}
+	if patient == nil {
+		return nil, ErrPatientNotFound
+	}
+	if patient.IsDeceased {
+		return nil, ErrPatientDeceased
+	}
+
+	if req.DoctorID != nil {
+		doctor, err := s.userRepo.FindByID(*req.DoctorID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find doctor: %w", err)
+		}
+		if doctor == nil {
+			return nil, ErrDoctorNotFound
+		}
+	}
+	if req.DepartmentID != nil {
+		dept, err := s.departmentRepo.FindByID(*req.DepartmentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find department: %w", err)
+		}
+		if dept == nil {
+			return nil, ErrDepartmentNotFound
+		}
+	}
+
+	from, err := time.Parse("2006-01-02", req.PreferredFrom)
+	if err != nil {
+		return nil, ErrInvalidDateFormat
+	}
+	to, err := time.Parse("2006-01-02", req.PreferredTo)
+	if err != nil {
+		return nil, ErrInvalidDateFormat
+	}
+	if to.Before(from) {
+		return nil, ErrInvalidDateRange
+	}
+	if to.Before(s.clock.Today()) {
+		return nil, ErrPastAppointmentDate
+	}
+
+	if (req.PreferredTimeFrom == "") != (req.PreferredTimeTo == "") {
+		return nil, ErrInvalidPreferredTime
+	}
+	if req.PreferredTimeFrom != "" {
+		start, _ := parseClock(req.PreferredTimeFrom)
+		end, _ := parseClock(req.PreferredTimeTo)
+		if end <= start {
+			return nil, ErrInvalidPreferredTime
+		}
+	}
+
+	exists, err := s.waitlistRepo.HasOpenEntry(req.PatientID, req.DoctorID, req.DepartmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check waitlist: %w", err)
+	}
+	if exists {
+		return nil, ErrDuplicateWaitlistEntry
+	}
+
+	priority := domain.WaitlistPriorityNormal
+	if req.Priority != "" {
+		priority = domain.WaitlistPriority(req.Priority)
+	}
+
+	entry := &domain.WaitlistEntry{
+		PatientID:         req.PatientID,
+		DoctorID:          req.DoctorID,
+		DepartmentID:      req.DepartmentID,
+		PreferredFrom:     from,
+		PreferredTo:       to,
+		PreferredTimeFrom: req.PreferredTimeFrom,
+		PreferredTimeTo:   req.PreferredTimeTo,
+		DurationMinutes:   req.DurationMinutes,
+		Priority:          priority,
+		AppointmentType:   domain.AppointmentType(req.AppointmentType),
+		Reason:            req.Reason,
+		Notes:             req.Notes,
+		Status:            domain.WaitlistStatusWaiting,
+		CreatedBy:         createdBy,
+		UpdatedBy:         createdBy,
+	}
+
+	if err := s.waitlistRepo.CreateEntry(entry); err != nil {
+		return nil, fmt.Errorf("failed to create waitlist entry: %w", err)
+	}
+
+	return s.GetEntry(entry.ID)
+}
+
+// GetEntry gets a waitlist entry with its offers
+func (s *WaitlistService) GetEntry(id uint) (*dto.WaitlistEntryResponse, error) {
+	entry, err := s.waitlistRepo.FindEntryByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find waitlist entry: %w", err)
+	}
+	if entry == nil {
+		return nil, ErrWaitlistEntryNotFound
+	}
+
+	offers, err := s.waitlistRepo.FindOffersByEntry(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find waitlist offers: %w", err)
+	}
+
+	resp := s.toEntryResponse(entry)
+	resp.Offers = make([]*dto.WaitlistOfferResponse, len(offers))
+	for i, offer := range offers {
+		resp.Offers[i] = s.toOfferResponse(offer)
+	}
+	return resp, nil
+}
+
+// ListEntries lists waitlist entries, most urgent and longest waiting first
+func (s *WaitlistService) ListEntries(filters map[string]interface{}, page, pageSize int) ([]*dto.WaitlistEntryResponse, int64, error) {
+	entries, total, err := s.waitlistRepo.ListEntries(filters, page, pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list waitlist entries: %w", err)
+	}
+
+	items := make([]*dto.WaitlistEntryResponse, len(entries))
+	for i, entry := range entries {
+		items[i] = s.toEntryResponse(entry)
+	}
+	return items, total, nil
+}
+
+// CancelEntry takes a patient off the waitlist; a slot held for the entry is
+// offered to the next patient
+func (s *WaitlistService) CancelEntry(id uint, cancelledBy uint) (*dto.WaitlistEntryResponse, error) {
+	entry, err := s.waitlistRepo.FindEntryByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find waitlist entry: %w", err)
+	}
+	if entry == nil {
+		return nil, ErrWaitlistEntryNotFound
+	}
+	if entry.Status != domain.WaitlistStatusWaiting && entry.Status != domain.WaitlistStatusOffered {
+		return nil, ErrWaitlistEntryClosed
+	}
+
+	entry.Status = domain.WaitlistStatusCancelled
+	entry.UpdatedBy = cancelledBy
+
+	offer, err := s.waitlistRepo.FindPendingOfferByEntry(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find waitlist offer: %w", err)
+	}
+	if offer == nil {
+		if err := s.waitlistRepo.UpdateEntry(entry); err != nil {
+			return nil, fmt.Errorf("failed to cancel waitlist entry: %w", err)
+		}
+		return s.GetEntry(id)
+	}
+
+	now := s.clock.Now()
+	offer.Status = domain.WaitlistOfferStatusDeclined
+	offer.RespondedAt = &now
+	offer.RespondedBy = &cancelledBy
+	if err := s.waitlistRepo.UpdateOfferWithEntry(offer, entry); err != nil {
+		return nil, fmt.Errorf("failed to cancel waitlist entry: %w", err)
+	}
+
+	if err := s.offerSlot(s.offeredSlot(offer, entry.PatientID)); err != nil {
+		return nil, err
+	}
+	return s.GetEntry(id)
+}
+
+// AcceptOffer books the held slot for the waitlisted patient
+func (s *WaitlistService) AcceptOffer(offerID uint, acceptedBy uint) (*dto.AppointmentResponse, error) {
+	offer, err := s.findPendingOffer(offerID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.clock.Now().Before(offer.ExpiresAt) {
+		if err := s.expireOffer(offer); err != nil {
+			return nil, err
+		}
+		return nil, ErrWaitlistOfferExpired
+	}
+
+	// The booking accepts the offer, releasing its hold, in the transaction
+	// that takes the slot
+	entry := offer.Entry
+	return s.appointmentService.ScheduleWaitlistAppointment(&dto.CreateAppointmentRequest{
+		PatientID:       entry.PatientID,
+		DoctorID:        offer.DoctorID,
+		AppointmentDate: offer.AppointmentDate.Format("2006-01-02"),
+		AppointmentTime: offer.AppointmentTime.Format("15:04"),
+		DurationMinutes: offer.DurationMinutes,
+		AppointmentType: string(entry.AppointmentType),
+		Reason:          entry.Reason,
+		Notes:           entry.Notes,
+	}, offer, acceptedBy)
+}
+
+// DeclineOffer records that the patient turned the slot down; the entry goes
+// back to waiting and the slot is offered to the next patient
+func (s *WaitlistService) DeclineOffer(offerID uint, declinedBy uint) (*dto.WaitlistEntryResponse, error) {
+	offer, err := s.findPendingOffer(offerID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now()
+	offer.Status = domain.WaitlistOfferStatusDeclined
+	offer.RespondedAt = &now
+	offer.RespondedBy = &declinedBy
+
+	entry := offer.Entry
+	entry.Status = domain.WaitlistStatusWaiting
+	entry.UpdatedBy = declinedBy
+	if err := s.waitlistRepo.UpdateOfferWithEntry(offer, entry); err != nil {
+		return nil, fmt.Errorf("failed to decline waitlist offer: %w", err)
+	}
+
+	if err := s.offerSlot(s.offeredSlot(offer, entry.PatientID)); err != nil {
+		return nil, err
+	}
+	return s.GetEntry(entry.ID)
+}
+
+// ProcessExpiredOffers expires entries whose preferred dates have passed and
+// moves offers whose hold period ended on to the next patient. It returns the
+// number of offers expired.
+func (s *WaitlistService) ProcessExpiredOffers() (int, error) {
+	now := s.clock.Now()
+	if _, err := s.waitlistRepo.ExpireStaleEntries(s.clock.DayOf(now)); err != nil {
+		return 0, fmt.Errorf("failed to expire waitlist entries: %w", err)
+	}
+
+	offers, err := s.waitlistRepo.FindExpiredOffers(now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find expired waitlist offers: %w", err)
+	}
+
+	for i, offer := range offers {
+		if err := s.expireOffer(offer); err != nil {
+			return i, err
+		}
+	}
+	return len(offers), nil
+}
+
+// Run processes expired offers every interval until the context is cancelled
+func (s *WaitlistService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if n, err := s.ProcessExpiredOffers(); err != nil {
+				logger.Error("Failed to process expired waitlist offers", zap.Error(err))
+			} else if n > 0 {
+				logger.Info("Expired waitlist offers moved on", zap.Int("count", n))
+			}
+		}
+	}
+}
+
+// handleSlotReleased offers a slot freed by a cancellation or reschedule to the waitlist
+func (s *WaitlistService) handleSlotReleased(released domain.Appointment) {
+	err := s.offerSlot(&waitlistSlot{
+		doctorID:            released.DoctorID,
+		date:                released.AppointmentDate,
+		time:                released.AppointmentTime,
+		duration:            released.DurationMinutes,
+		sourceAppointmentID: &released.ID,
+		skipPatientID:       released.PatientID,
+	})
+	if err != nil {
+		logger.Error("Failed to offer released slot to waitlist", zap.Uint("appointment_id", released.ID), zap.Error(err))
+	}
+}
+
+// offerSlot holds a free slot for the first matching waiting entry that has
+// not been offered this slot before. Slots that are in the past, already
+// taken or no longer inside a clinic session are not offered.
+func (s *WaitlistService) offerSlot(slot *waitlistSlot) error {
+	now := s.clock.Now()
+	start := s.clock.At(slot.date, slot.time)
+	if !start.After(now) {
+		return nil
+	}
+
+	doctor, err := s.userRepo.FindByID(slot.doctorID)
+	if err != nil {
+		return fmt.Errorf("failed to find doctor: %w", err)
+	}
+	if doctor == nil {
+		return nil
+	}
+
+	offeredIDs, err := s.waitlistRepo.FindOfferedEntryIDs(slot.doctorID, slot.date, slot.time)
+	if err != nil {
+		return fmt.Errorf("failed to find previous offers: %w", err)
+	}
+
+	entries, err := s.waitlistRepo.FindMatchingEntries(slot.doctorID, doctor.DepartmentID, slot.date, offeredIDs)
+	if err != nil {
+		return fmt.Errorf("failed to find waitlist entries: %w", err)
+	}
+
+	slotStart := slot.time.Hour()*60 + slot.time.Minute()
+	for _, entry := range entries {
+		if entry.PatientID == slot.skipPatientID {
+			continue
+		}
+		if entry.Patient != nil && entry.Patient.IsDeceased {
+			continue
+		}
+
+		duration := slot.duration
+		if entry.DurationMinutes > 0 {
+			if entry.DurationMinutes > slot.duration {
+				continue
+			}
+			duration = entry.DurationMinutes
+		}
+
+		if entry.PreferredTimeFrom != "" {
+			from, _ := parseClock(entry.PreferredTimeFrom)
+			to, _ := parseClock(entry.PreferredTimeTo)
+			if slotStart < from || slotStart+duration > to {
+				continue
+			}
+		}
+
+		// The slot must still lie inside a clinic session
+		session, _, err := s.appointmentService.findClinicSession(slot.doctorID, slot.date, slot.time, duration)
+		if errors.Is(err, ErrDoctorNotAvailable) || errors.Is(err, ErrInvalidAppointmentTime) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		limit, capacity, err := s.appointmentService.sessionLimit(slot.doctorID, session)
+		if err != nil {
+			return err
+		}
+
+		// The hold never outlasts the slot itself
+		expiresAt := now.Add(s.holdPeriod)
+		if start.Before(expiresAt) {
+			expiresAt = start
+		}
+
+		offer := &domain.WaitlistOffer{
+			EntryID:             entry.ID,
+			DoctorID:            slot.doctorID,
+			AppointmentDate:     slot.date,
+			AppointmentTime:     slot.time,
+			DurationMinutes:     duration,
+			SourceAppointmentID: slot.sourceAppointmentID,
+			Status:              domain.WaitlistOfferStatusPending,
+			ExpiresAt:           expiresAt,
+		}
+
+		// Check the session's capacity and the slot and hold it under the
+		// doctor's booking lock, as bookings do
+		err = s.appointmentRepo.WithDoctorLock(slot.doctorID, nil, func(repo *repository.AppointmentRepository, _ *repository.ResourceRepository) error {
+			if err := checkSessionCapacity(repo, session, limit, slot.doctorID, slot.date, nil); err != nil {
+				return err
+			}
+			available, err := repo.CheckTimeSlotCapacity(slot.doctorID, slot.date, slot.time, duration, capacity, nil)
+			if err != nil {
+				return fmt.Errorf("failed to check time slot: %w", err)
+			}
+			if !available {
+				return ErrTimeSlotNotAvailable
+			}
+			if err := repo.CreateWaitlistOffer(offer, entry); err != nil {
+				return fmt.Errorf("failed to create waitlist offer: %w", err)
+			}
+			return nil
+		})
+		if errors.Is(err, ErrClinicSessionFull) || errors.Is(err, ErrTimeSlotNotAvailable) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		logger.Info("Released slot offered to waitlist",
+			zap.Uint("offer_id", offer.ID),
+			zap.Uint("entry_id", entry.ID),
+			zap.Uint("doctor_id", slot.doctorID),
+			zap.Time("expires_at", expiresAt),
+		)
+		return nil
+	}
+
+	return nil
+}
+
+// expireOffer ends an offer's hold, puts its entry back to waiting and offers
+// the slot to the next patient
+func (s *WaitlistService) expireOffer(offer *domain.WaitlistOffer) error {
+	offer.Status = domain.WaitlistOfferStatusExpired
+
+	entry := offer.Entry
+	if entry.Status == domain.WaitlistStatusOffered {
+		entry.Status = domain.WaitlistStatusWaiting
+	}
+	if err := s.waitlistRepo.UpdateOfferWithEntry(offer, entry); err != nil {
+		return fmt.Errorf("failed to expire waitlist offer: %w", err)
+	}
+
+	return s.offerSlot(s.offeredSlot(offer, entry.PatientID))
+}
+
+// findPendingOffer loads an offer with its entry and checks it still awaits an answer
+func (s *WaitlistService) findPendingOffer(id uint) (*domain.WaitlistOffer, error) {
+	offer, err := s.waitlistRepo.FindOfferByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find waitlist offer: %w", err)
+	}
+	if offer == nil {
+		return nil, ErrWaitlistOfferNotFound
+	}
+	if offer.Status != domain.WaitlistOfferStatusPending {
+		return nil, ErrWaitlistOfferNotPending
+	}
+	return offer, nil
+}
+
+// offeredSlot returns the slot held by an offer so it can be offered again
+func (s *WaitlistService) offeredSlot(offer *domain.WaitlistOffer, patientID uint) *waitlistSlot {
+	return &waitlistSlot{
+		doctorID:            offer.DoctorID,
+		date:                offer.AppointmentDate,
+		time:                offer.AppointmentTime,
+		duration:            offer.DurationMinutes,
+		sourceAppointmentID: offer.SourceAppointmentID,
+		skipPatientID:       patientID,
+	}
+}
+
+// Helper functions
+func (s *WaitlistService) toEntryResponse(entry *domain.WaitlistEntry) *dto.WaitlistEntryResponse {
+	resp := &dto.WaitlistEntryResponse{
+		ID:                entry.ID,
+		PatientID:         entry.PatientID,
+		DoctorID:          entry.DoctorID,
+		DepartmentID:      entry.DepartmentID,
+		PreferredFrom:     entry.PreferredFrom.Format("2006-01-02"),
+		PreferredTo:       entry.PreferredTo.Format("2006-01-02"),
+		PreferredTimeFrom: entry.PreferredTimeFrom,
+		PreferredTimeTo:   entry.PreferredTimeTo,
+		DurationMinutes:   entry.DurationMinutes,
+		Priority:          string(entry.Priority),
+		AppointmentType:   string(entry.AppointmentType),
+		Reason:            entry.Reason,
+		Notes:             entry.Notes,
+		Status:            string(entry.Status),
+		OfferCount:        entry.OfferCount,
+		AppointmentID:     entry.AppointmentID,
+		CreatedAt:         entry.CreatedAt,
+		UpdatedAt:         entry.UpdatedAt,
+	}
+
+	if entry.Patient != nil {
+		resp.PatientName = entry.Patient.FullName
+	}
+	if entry.Doctor != nil {
+		resp.DoctorName = entry.Doctor.FullName
+	}
+	if entry.Department != nil {
+		resp.DepartmentName = entry.Department.Name
+	}
+
+	return resp
+}
+
+func (s *WaitlistService) toOfferResponse(offer *domain.WaitlistOffer) *dto.WaitlistOfferResponse {
+	resp := &dto.WaitlistOfferResponse{
+		ID:                  offer.ID,
+		EntryID:             offer.EntryID,
+		DoctorID:            offer.DoctorID,
+		AppointmentDate:     offer.AppointmentDate.Format("2006-01-02"),
+		AppointmentTime:     offer.AppointmentTime.Format("15:04"),
+		DurationMinutes:     offer.DurationMinutes,
+		SourceAppointmentID: offer.SourceAppointmentID,
+		Status:              string(offer.Status),
+		ExpiresAt:           offer.ExpiresAt,
+		RespondedAt:         offer.RespondedAt,
+		AppointmentID:       offer.AppointmentID,
+		CreatedAt:           offer.CreatedAt,
+	}
+
+	if offer.Entry != nil {
+		resp.PatientID = offer.Entry.PatientID
+		if offer.Entry.Patient != nil {
+			resp.PatientName = offer.Entry.Patient.FullName
+		}
+	}
+	if offer.Doctor != nil {
+		resp.DoctorName = offer.Doctor.FullName
+	}
+
+	return resp
+}
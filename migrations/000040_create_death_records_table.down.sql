@@ -0,0 +1,8 @@
+-- Drop death registration tables
+DROP TABLE IF EXISTS death_causes;
+DROP TABLE IF EXISTS death_records;
+
+-- Remove deceased status from patients table
+DROP INDEX idx_patients_is_deceased ON patients;
+ALTER TABLE patients DROP COLUMN deceased_at;
+ALTER TABLE patients DROP COLUMN is_deceased;
@@ -0,0 +1,73 @@
+-- Add deceased status to patients table
+ALTER TABLE patients ADD COLUMN is_deceased BOOLEAN NOT NULL DEFAULT FALSE AFTER is_active;
+ALTER TABLE patients ADD COLUMN deceased_at TIMESTAMP NULL AFTER is_deceased;
+CREATE INDEX idx_patients_is_deceased ON patients (is_deceased);
+
+-- Create death_records table
+CREATE TABLE IF NOT EXISTS death_records (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    certificate_number VARCHAR(20) NOT NULL UNIQUE,
+    
+    -- Foreign Keys
+    patient_id BIGINT UNSIGNED NOT NULL UNIQUE,
+    admission_id BIGINT UNSIGNED,
+    certifying_doctor_id BIGINT UNSIGNED NOT NULL,
+    underlying_cause_id BIGINT UNSIGNED NOT NULL,
+    
+    -- Death Details
+    death_date_time TIMESTAMP NOT NULL,
+    place_of_death VARCHAR(20) NOT NULL,
+    place_details VARCHAR(255),
+    manner_of_death VARCHAR(20) NOT NULL DEFAULT 'NATURAL',
+    notes TEXT,
+    
+    -- Audit fields
+    created_by BIGINT UNSIGNED NOT NULL,
+    updated_by BIGINT UNSIGNED,
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    
+    -- Indexes
+    INDEX idx_death_records_certificate_number (certificate_number),
+    INDEX idx_death_records_death_date_time (death_date_time),
+    INDEX idx_death_records_certifying_doctor_id (certifying_doctor_id),
+    INDEX idx_death_records_deleted_at (deleted_at),
+    
+    -- Foreign Keys
+    FOREIGN KEY (patient_id) REFERENCES patients(id),
+    FOREIGN KEY (admission_id) REFERENCES admissions(id),
+    FOREIGN KEY (certifying_doctor_id) REFERENCES users(id),
+    FOREIGN KEY (underlying_cause_id) REFERENCES icd10_codes(id),
+    FOREIGN KEY (created_by) REFERENCES users(id),
+    FOREIGN KEY (updated_by) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create death_causes table (cause-of-death chain, WHO certificate layout)
+CREATE TABLE IF NOT EXISTS death_causes (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    
+    -- Foreign Keys
+    death_record_id BIGINT UNSIGNED NOT NULL,
+    icd10_code_id BIGINT UNSIGNED NOT NULL,
+    
+    -- Cause Details
+    line VARCHAR(20) NOT NULL, -- A (immediate), B, C, D (underlying), CONTRIBUTING
+    description VARCHAR(255),
+    onset_interval VARCHAR(50),
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    
+    -- Indexes
+    INDEX idx_death_causes_death_record_id (death_record_id),
+    INDEX idx_death_causes_deleted_at (deleted_at),
+    
+    -- Foreign Keys
+    FOREIGN KEY (death_record_id) REFERENCES death_records(id) ON DELETE CASCADE,
+    FOREIGN KEY (icd10_code_id) REFERENCES icd10_codes(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,13 @@
+ALTER TABLE insurance_claims DROP FOREIGN KEY fk_insurance_claims_payer;
+ALTER TABLE insurance_claims DROP FOREIGN KEY fk_insurance_claims_coverage;
+ALTER TABLE insurance_claims DROP INDEX idx_insurance_claims_payer_id;
+ALTER TABLE insurance_claims DROP INDEX idx_insurance_claims_coverage_id;
+ALTER TABLE insurance_claims DROP COLUMN payer_id;
+ALTER TABLE insurance_claims DROP COLUMN coverage_id;
+
+ALTER TABLE visits DROP FOREIGN KEY fk_visits_coverage;
+ALTER TABLE visits DROP INDEX idx_visits_coverage_id;
+ALTER TABLE visits DROP COLUMN coverage_id;
+
+DROP TABLE IF EXISTS patient_coverages;
+DROP TABLE IF EXISTS insurance_payers;
@@ -0,0 +1,101 @@
+-- Create insurance_payers table
+CREATE TABLE IF NOT EXISTS insurance_payers (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    code VARCHAR(50) NOT NULL UNIQUE,
+    name VARCHAR(200) NOT NULL,
+    payer_type VARCHAR(20) NOT NULL DEFAULT 'PRIVATE',
+    phone_number VARCHAR(20),
+    email VARCHAR(100),
+    address VARCHAR(255),
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    
+    -- Audit fields
+    created_by BIGINT UNSIGNED,
+    updated_by BIGINT UNSIGNED,
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    
+    -- Indexes
+    INDEX idx_insurance_payers_payer_type (payer_type),
+    INDEX idx_insurance_payers_is_active (is_active),
+    INDEX idx_insurance_payers_deleted_at (deleted_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create patient_coverages table
+CREATE TABLE IF NOT EXISTS patient_coverages (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    
+    -- Foreign Keys
+    patient_id BIGINT UNSIGNED NOT NULL,
+    payer_id BIGINT UNSIGNED NOT NULL,
+    
+    -- Coverage Details
+    policy_number VARCHAR(100) NOT NULL,
+    valid_from DATE NOT NULL,
+    valid_to DATE NULL,
+    coverage_percent DECIMAL(5,2) NOT NULL DEFAULT 0,
+    primary_care_facility VARCHAR(200),
+    priority VARCHAR(20) NOT NULL DEFAULT 'PRIMARY',
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    notes TEXT,
+    
+    -- Audit fields
+    created_by BIGINT UNSIGNED NOT NULL,
+    updated_by BIGINT UNSIGNED,
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    
+    -- Indexes
+    INDEX idx_patient_coverages_patient_id (patient_id),
+    INDEX idx_patient_coverages_payer_id (payer_id),
+    INDEX idx_patient_coverages_policy_number (policy_number),
+    INDEX idx_patient_coverages_validity (valid_from, valid_to),
+    INDEX idx_patient_coverages_deleted_at (deleted_at),
+    
+    -- Foreign Keys
+    FOREIGN KEY (patient_id) REFERENCES patients(id),
+    FOREIGN KEY (payer_id) REFERENCES insurance_payers(id),
+    FOREIGN KEY (created_by) REFERENCES users(id),
+    FOREIGN KEY (updated_by) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Link visits and claims to the coverage they were billed against
+ALTER TABLE visits ADD COLUMN coverage_id BIGINT UNSIGNED NULL AFTER doctor_id;
+ALTER TABLE visits ADD INDEX idx_visits_coverage_id (coverage_id);
+ALTER TABLE visits ADD CONSTRAINT fk_visits_coverage FOREIGN KEY (coverage_id) REFERENCES patient_coverages(id);
+
+ALTER TABLE insurance_claims ADD COLUMN coverage_id BIGINT UNSIGNED NULL AFTER patient_id;
+ALTER TABLE insurance_claims ADD COLUMN payer_id BIGINT UNSIGNED NULL AFTER coverage_id;
+ALTER TABLE insurance_claims ADD INDEX idx_insurance_claims_coverage_id (coverage_id);
+ALTER TABLE insurance_claims ADD INDEX idx_insurance_claims_payer_id (payer_id);
+ALTER TABLE insurance_claims ADD CONSTRAINT fk_insurance_claims_coverage FOREIGN KEY (coverage_id) REFERENCES patient_coverages(id);
+ALTER TABLE insurance_claims ADD CONSTRAINT fk_insurance_claims_payer FOREIGN KEY (payer_id) REFERENCES insurance_payers(id);
+
+-- Seed the national social health insurance payer
+INSERT INTO insurance_payers (code, name, payer_type, is_active) VALUES
+('BHYT', 'Bảo hiểm Xã hội Việt Nam', 'SOCIAL', TRUE);
+
+-- Migrate legacy free-text insurance data into the catalog. The legacy fields carry no
+-- coverage percentage, so migrated coverages stay inactive until staff verify them and
+-- set it; until then claims fall back to the patient's free-text insurance
+INSERT INTO insurance_payers (code, name, payer_type, is_active)
+SELECT CONCAT('LEGACY-', LPAD(ROW_NUMBER() OVER (ORDER BY p.provider), 4, '0')), p.provider, 'PRIVATE', TRUE
+FROM (
+    SELECT DISTINCT TRIM(insurance_provider) AS provider
+    FROM patients
+    WHERE insurance_provider IS NOT NULL AND TRIM(insurance_provider) <> ''
+) p;
+
+INSERT INTO patient_coverages (patient_id, payer_id, policy_number, valid_from, coverage_percent, priority, is_active, notes, created_by)
+SELECT pt.id, ip.id, pt.insurance_number, DATE(pt.created_at), 0, 'PRIMARY', FALSE,
+       'Migrated from legacy patient insurance fields; set the coverage percentage and activate once verified', pt.created_by
+FROM patients pt
+JOIN insurance_payers ip ON ip.name = TRIM(pt.insurance_provider) AND ip.code LIKE 'LEGACY-%'
+WHERE pt.insurance_number IS NOT NULL AND TRIM(pt.insurance_number) <> ''
+  AND pt.deleted_at IS NULL;
@@ -0,0 +1,3 @@
+-- Drop patient import tables
+DROP TABLE IF EXISTS patient_import_rows;
+DROP TABLE IF EXISTS patient_import_jobs;
@@ -0,0 +1,59 @@
+-- Create patient_import_jobs table
+CREATE TABLE IF NOT EXISTS patient_import_jobs (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    
+    -- Source File
+    file_name VARCHAR(255) NOT NULL,
+    column_mapping JSON,
+    dry_run BOOLEAN NOT NULL DEFAULT FALSE,
+    
+    -- Result Summary
+    status VARCHAR(20) NOT NULL,
+    total_rows INT NOT NULL DEFAULT 0,
+    created_count INT NOT NULL DEFAULT 0,
+    skipped_count INT NOT NULL DEFAULT 0,
+    error_count INT NOT NULL DEFAULT 0,
+    completed_at TIMESTAMP NULL,
+    
+    -- Audit fields
+    created_by BIGINT UNSIGNED NOT NULL,
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    
+    -- Indexes
+    INDEX idx_patient_import_jobs_status (status),
+    INDEX idx_patient_import_jobs_created_by (created_by),
+    
+    -- Foreign Keys
+    FOREIGN KEY (created_by) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create patient_import_rows table
+CREATE TABLE IF NOT EXISTS patient_import_rows (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    
+    -- Foreign Keys
+    job_id BIGINT UNSIGNED NOT NULL,
+    patient_id BIGINT UNSIGNED,
+    
+    -- Row Result
+    line_number INT NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    full_name VARCHAR(100),
+    national_id VARCHAR(20),
+    message TEXT,
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    
+    -- Indexes
+    INDEX idx_patient_import_rows_job_id (job_id),
+    INDEX idx_patient_import_rows_patient_id (patient_id),
+    INDEX idx_patient_import_rows_status (status),
+    
+    -- Foreign Keys
+    FOREIGN KEY (job_id) REFERENCES patient_import_jobs(id) ON DELETE CASCADE,
+    FOREIGN KEY (patient_id) REFERENCES patients(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
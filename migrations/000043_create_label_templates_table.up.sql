@@ -0,0 +1,36 @@
+-- Create label_templates table
+CREATE TABLE IF NOT EXISTS label_templates (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    code VARCHAR(50) NOT NULL UNIQUE,
+    name VARCHAR(200) NOT NULL,
+    target_type VARCHAR(20) NOT NULL,
+    width_mm DECIMAL(6,2) NOT NULL,
+    height_mm DECIMAL(6,2) NOT NULL,
+    barcode_type VARCHAR(20) NOT NULL DEFAULT 'CODE128',
+    fields VARCHAR(500) NOT NULL,
+    font_size_pt DECIMAL(4,1) DEFAULT 0,
+    copies INT DEFAULT 1,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    
+    -- Audit fields
+    created_by BIGINT UNSIGNED,
+    updated_by BIGINT UNSIGNED,
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    
+    -- Indexes
+    INDEX idx_label_templates_target_type (target_type),
+    INDEX idx_label_templates_is_active (is_active),
+    INDEX idx_label_templates_deleted_at (deleted_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Seed default label templates
+INSERT INTO label_templates (code, name, target_type, width_mm, height_mm, barcode_type, fields, font_size_pt, copies, is_default) VALUES
+('WRISTBAND_ADULT', 'Vòng đeo tay người lớn', 'PATIENT', 279, 25, 'CODE128', 'full_name,patient_code,date_of_birth,gender_age,allergy_flag', 0, 1, TRUE),
+('WRISTBAND_INPATIENT', 'Vòng đeo tay nội trú', 'ADMISSION', 279, 25, 'QR', 'full_name,patient_code,date_of_birth,ward_bed,allergy_flag', 0, 1, TRUE),
+('CHART_LABEL', 'Nhãn hồ sơ bệnh án', 'PATIENT', 70, 35, 'CODE128', 'full_name,patient_code,date_of_birth,gender_age,blood_type,allergy_flag', 0, 6, FALSE),
+('SPECIMEN', 'Nhãn ống mẫu xét nghiệm', 'LAB_REQUEST', 50, 25, 'CODE128', 'full_name,request_code,test_name,sample_type', 0, 1, TRUE);
@@ -0,0 +1,19 @@
+ALTER TABLE appointments DROP FOREIGN KEY fk_appointments_portal_account;
+ALTER TABLE appointments DROP INDEX idx_appointments_portal_account_id;
+ALTER TABLE appointments DROP COLUMN portal_account_id;
+ALTER TABLE appointments DROP COLUMN booking_source;
+DELETE FROM appointments WHERE created_by IS NULL;
+ALTER TABLE appointments MODIFY COLUMN created_by BIGINT UNSIGNED NOT NULL;
+
+ALTER TABLE imaging_requests DROP FOREIGN KEY fk_imaging_requests_portal_released_by;
+ALTER TABLE imaging_requests DROP INDEX idx_imaging_requests_portal_released_at;
+ALTER TABLE imaging_requests DROP COLUMN portal_released_by;
+ALTER TABLE imaging_requests DROP COLUMN portal_released_at;
+
+ALTER TABLE lab_test_requests DROP FOREIGN KEY fk_lab_test_requests_portal_released_by;
+ALTER TABLE lab_test_requests DROP INDEX idx_lab_test_requests_portal_released_at;
+ALTER TABLE lab_test_requests DROP COLUMN portal_released_by;
+ALTER TABLE lab_test_requests DROP COLUMN portal_released_at;
+
+DROP TABLE IF EXISTS portal_otps;
+DROP TABLE IF EXISTS patient_portal_accounts;
@@ -0,0 +1,65 @@
+-- Create patient_portal_accounts table
+CREATE TABLE IF NOT EXISTS patient_portal_accounts (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    patient_id BIGINT UNSIGNED NOT NULL UNIQUE,
+    phone_number VARCHAR(20) NOT NULL UNIQUE,
+    password_hash VARCHAR(255),
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    failed_login_attempts INT NOT NULL DEFAULT 0,
+    locked_until TIMESTAMP NULL,
+    last_login_at TIMESTAMP NULL,
+    
+    -- Audit fields
+    created_by BIGINT UNSIGNED,
+    updated_by BIGINT UNSIGNED,
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    
+    -- Indexes
+    INDEX idx_patient_portal_accounts_is_active (is_active),
+    INDEX idx_patient_portal_accounts_deleted_at (deleted_at),
+    
+    -- Foreign Keys
+    FOREIGN KEY (patient_id) REFERENCES patients(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create portal_otps table
+CREATE TABLE IF NOT EXISTS portal_otps (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    account_id BIGINT UNSIGNED NOT NULL,
+    code_hash VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    consumed_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    
+    -- Indexes
+    INDEX idx_portal_otps_account_id (account_id),
+    
+    -- Foreign Keys
+    FOREIGN KEY (account_id) REFERENCES patient_portal_accounts(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Portal release of lab and imaging results
+ALTER TABLE lab_test_requests
+    ADD COLUMN portal_released_at TIMESTAMP NULL AFTER clinical_notes,
+    ADD COLUMN portal_released_by BIGINT UNSIGNED NULL AFTER portal_released_at,
+    ADD INDEX idx_lab_test_requests_portal_released_at (portal_released_at),
+    ADD CONSTRAINT fk_lab_test_requests_portal_released_by FOREIGN KEY (portal_released_by) REFERENCES users(id);
+
+ALTER TABLE imaging_requests
+    ADD COLUMN portal_released_at TIMESTAMP NULL AFTER special_instructions,
+    ADD COLUMN portal_released_by BIGINT UNSIGNED NULL AFTER portal_released_at,
+    ADD INDEX idx_imaging_requests_portal_released_at (portal_released_at),
+    ADD CONSTRAINT fk_imaging_requests_portal_released_by FOREIGN KEY (portal_released_by) REFERENCES users(id);
+
+-- Appointments booked through the portal have no staff creator
+ALTER TABLE appointments
+    MODIFY COLUMN created_by BIGINT UNSIGNED NULL,
+    ADD COLUMN booking_source VARCHAR(20) NOT NULL DEFAULT 'STAFF' AFTER cancelled_by,
+    ADD COLUMN portal_account_id BIGINT UNSIGNED NULL AFTER booking_source,
+    ADD INDEX idx_appointments_portal_account_id (portal_account_id),
+    ADD CONSTRAINT fk_appointments_portal_account FOREIGN KEY (portal_account_id) REFERENCES patient_portal_accounts(id);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS holidays;
+DROP TABLE IF EXISTS doctor_leaves;
+DROP TABLE IF EXISTS doctor_schedule_overrides;
+DROP TABLE IF EXISTS doctor_schedules;
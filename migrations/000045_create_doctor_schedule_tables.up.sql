@@ -0,0 +1,145 @@
+-- Create doctor_schedules table (weekly session templates)
+CREATE TABLE IF NOT EXISTS doctor_schedules (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    doctor_id BIGINT UNSIGNED NOT NULL,
+    day_of_week TINYINT NOT NULL,
+    start_time CHAR(5) NOT NULL,
+    end_time CHAR(5) NOT NULL,
+    break_start CHAR(5),
+    break_end CHAR(5),
+    room VARCHAR(50),
+    slot_minutes INT NOT NULL DEFAULT 30,
+    max_patients INT NOT NULL DEFAULT 0,
+    effective_from DATE NOT NULL,
+    effective_to DATE NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    
+    -- Audit fields
+    created_by BIGINT UNSIGNED,
+    updated_by BIGINT UNSIGNED,
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    
+    -- Indexes
+    INDEX idx_doctor_schedules_doctor_day (doctor_id, day_of_week),
+    INDEX idx_doctor_schedules_is_active (is_active),
+    INDEX idx_doctor_schedules_deleted_at (deleted_at),
+    
+    -- Foreign Keys
+    FOREIGN KEY (doctor_id) REFERENCES users(id),
+    FOREIGN KEY (created_by) REFERENCES users(id),
+    FOREIGN KEY (updated_by) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create doctor_schedule_overrides table (date-specific sessions)
+CREATE TABLE IF NOT EXISTS doctor_schedule_overrides (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    doctor_id BIGINT UNSIGNED NOT NULL,
+    override_date DATE NOT NULL,
+    is_available BOOLEAN NOT NULL,
+    start_time CHAR(5),
+    end_time CHAR(5),
+    break_start CHAR(5),
+    break_end CHAR(5),
+    room VARCHAR(50),
+    slot_minutes INT NOT NULL DEFAULT 30,
+    max_patients INT NOT NULL DEFAULT 0,
+    reason VARCHAR(255),
+    
+    -- Audit fields
+    created_by BIGINT UNSIGNED,
+    updated_by BIGINT UNSIGNED,
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    
+    -- Indexes
+    INDEX idx_doctor_schedule_overrides_doctor_date (doctor_id, override_date),
+    INDEX idx_doctor_schedule_overrides_deleted_at (deleted_at),
+    
+    -- Foreign Keys
+    FOREIGN KEY (doctor_id) REFERENCES users(id),
+    FOREIGN KEY (created_by) REFERENCES users(id),
+    FOREIGN KEY (updated_by) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create doctor_leaves table
+CREATE TABLE IF NOT EXISTS doctor_leaves (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    doctor_id BIGINT UNSIGNED NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    leave_type VARCHAR(20) NOT NULL,
+    reason VARCHAR(255),
+    
+    -- Audit fields
+    created_by BIGINT UNSIGNED,
+    updated_by BIGINT UNSIGNED,
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    
+    -- Indexes
+    INDEX idx_doctor_leaves_doctor_dates (doctor_id, start_date, end_date),
+    INDEX idx_doctor_leaves_deleted_at (deleted_at),
+    
+    -- Foreign Keys
+    FOREIGN KEY (doctor_id) REFERENCES users(id),
+    FOREIGN KEY (created_by) REFERENCES users(id),
+    FOREIGN KEY (updated_by) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create holidays table
+CREATE TABLE IF NOT EXISTS holidays (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    holiday_date DATE NOT NULL,
+    name VARCHAR(200) NOT NULL,
+    is_recurring BOOLEAN NOT NULL DEFAULT FALSE,
+    
+    -- Audit fields
+    created_by BIGINT UNSIGNED,
+    updated_by BIGINT UNSIGNED,
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    
+    -- Indexes
+    INDEX idx_holidays_holiday_date (holiday_date),
+    INDEX idx_holidays_deleted_at (deleted_at),
+    
+    -- Foreign Keys
+    FOREIGN KEY (created_by) REFERENCES users(id),
+    FOREIGN KEY (updated_by) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Seed fixed-date public holidays (lunar holidays are added per year)
+INSERT INTO holidays (holiday_date, name, is_recurring) VALUES
+('2025-01-01', 'Tết Dương lịch', TRUE),
+('2025-04-30', 'Ngày Giải phóng miền Nam', TRUE),
+('2025-05-01', 'Ngày Quốc tế Lao động', TRUE),
+('2025-09-02', 'Quốc khánh', TRUE);
+
+-- Seed the hours every doctor was bookable before schedules existed
+-- (08:00-17:00 every day, 30-minute slots) for existing active doctors, so
+-- they stay bookable until their own templates are set up
+INSERT INTO doctor_schedules (doctor_id, day_of_week, start_time, end_time, slot_minutes, max_patients, effective_from, is_active)
+SELECT DISTINCT u.id, d.day_of_week, '08:00', '17:00', 30, 0, CURRENT_DATE, TRUE
+FROM users u
+JOIN user_roles ur ON ur.user_id = u.id
+JOIN roles r ON r.id = ur.role_id
+CROSS JOIN (
+    SELECT 0 AS day_of_week UNION ALL SELECT 1 UNION ALL SELECT 2 UNION ALL SELECT 3
+    UNION ALL SELECT 4 UNION ALL SELECT 5 UNION ALL SELECT 6
+) d
+WHERE r.code = 'DOCTOR'
+AND u.is_active = TRUE
+AND u.deleted_at IS NULL;
@@ -0,0 +1,7 @@
+ALTER TABLE appointments
+    DROP FOREIGN KEY fk_appointments_series,
+    DROP INDEX idx_appointments_series_id,
+    DROP COLUMN series_index,
+    DROP COLUMN series_id;
+
+DROP TABLE IF EXISTS appointment_series;
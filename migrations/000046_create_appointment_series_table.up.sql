@@ -0,0 +1,42 @@
+-- Create appointment_series table
+CREATE TABLE IF NOT EXISTS appointment_series (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    patient_id BIGINT UNSIGNED NOT NULL,
+    doctor_id BIGINT UNSIGNED NOT NULL,
+    recurrence_rule VARCHAR(255) NOT NULL,
+    start_date DATE NOT NULL,
+    appointment_time CHAR(5) NOT NULL,
+    duration_minutes INT NOT NULL DEFAULT 30,
+    appointment_type VARCHAR(20) NOT NULL,
+    reason TEXT,
+    notes TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE',
+    
+    -- Audit fields
+    created_by BIGINT UNSIGNED,
+    updated_by BIGINT UNSIGNED,
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    
+    -- Indexes
+    INDEX idx_appointment_series_patient_id (patient_id),
+    INDEX idx_appointment_series_doctor_id (doctor_id),
+    INDEX idx_appointment_series_status (status),
+    INDEX idx_appointment_series_deleted_at (deleted_at),
+    
+    -- Foreign Keys
+    FOREIGN KEY (patient_id) REFERENCES patients(id),
+    FOREIGN KEY (doctor_id) REFERENCES users(id),
+    FOREIGN KEY (created_by) REFERENCES users(id),
+    FOREIGN KEY (updated_by) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Link appointments to their series
+ALTER TABLE appointments
+    ADD COLUMN series_id BIGINT UNSIGNED NULL AFTER portal_account_id,
+    ADD COLUMN series_index INT NOT NULL DEFAULT 0 AFTER series_id,
+    ADD INDEX idx_appointments_series_id (series_id),
+    ADD CONSTRAINT fk_appointments_series FOREIGN KEY (series_id) REFERENCES appointment_series(id);
@@ -0,0 +1,84 @@
+-- Create waitlist_entries table
+CREATE TABLE IF NOT EXISTS waitlist_entries (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    patient_id BIGINT UNSIGNED NOT NULL,
+    doctor_id BIGINT UNSIGNED,
+    department_id BIGINT UNSIGNED,
+    
+    -- Preferences
+    preferred_from DATE NOT NULL,
+    preferred_to DATE NOT NULL,
+    preferred_time_from CHAR(5),
+    preferred_time_to CHAR(5),
+    duration_minutes INT NOT NULL DEFAULT 0,
+    
+    priority VARCHAR(10) NOT NULL DEFAULT 'NORMAL',
+    appointment_type VARCHAR(20) NOT NULL,
+    reason TEXT,
+    notes TEXT,
+    
+    status VARCHAR(20) NOT NULL DEFAULT 'WAITING',
+    offer_count INT NOT NULL DEFAULT 0,
+    appointment_id BIGINT UNSIGNED,
+    
+    -- Audit fields
+    created_by BIGINT UNSIGNED,
+    updated_by BIGINT UNSIGNED,
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    
+    -- Indexes
+    INDEX idx_waitlist_entries_patient_id (patient_id),
+    INDEX idx_waitlist_entries_doctor_id (doctor_id),
+    INDEX idx_waitlist_entries_department_id (department_id),
+    INDEX idx_waitlist_entries_status (status),
+    INDEX idx_waitlist_entries_deleted_at (deleted_at),
+    INDEX idx_waitlist_entries_matching (status, preferred_from, preferred_to),
+    
+    -- Foreign Keys
+    FOREIGN KEY (patient_id) REFERENCES patients(id),
+    FOREIGN KEY (doctor_id) REFERENCES users(id),
+    FOREIGN KEY (department_id) REFERENCES departments(id),
+    FOREIGN KEY (appointment_id) REFERENCES appointments(id),
+    FOREIGN KEY (created_by) REFERENCES users(id),
+    FOREIGN KEY (updated_by) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create waitlist_offers table
+CREATE TABLE IF NOT EXISTS waitlist_offers (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    entry_id BIGINT UNSIGNED NOT NULL,
+    
+    -- Held slot
+    doctor_id BIGINT UNSIGNED NOT NULL,
+    appointment_date DATE NOT NULL,
+    appointment_time TIME NOT NULL,
+    duration_minutes INT NOT NULL,
+    source_appointment_id BIGINT UNSIGNED,
+    
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    expires_at TIMESTAMP NOT NULL,
+    responded_at TIMESTAMP NULL,
+    responded_by BIGINT UNSIGNED,
+    appointment_id BIGINT UNSIGNED,
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    
+    -- Indexes
+    INDEX idx_waitlist_offers_entry_id (entry_id),
+    INDEX idx_waitlist_offers_status (status),
+    INDEX idx_waitlist_offers_expires_at (expires_at),
+    INDEX idx_waitlist_offers_doctor_datetime (doctor_id, appointment_date, appointment_time),
+    
+    -- Foreign Keys
+    FOREIGN KEY (entry_id) REFERENCES waitlist_entries(id),
+    FOREIGN KEY (doctor_id) REFERENCES users(id),
+    FOREIGN KEY (source_appointment_id) REFERENCES appointments(id),
+    FOREIGN KEY (responded_by) REFERENCES users(id),
+    FOREIGN KEY (appointment_id) REFERENCES appointments(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,5 @@
+ALTER TABLE patients
+    DROP COLUMN preferred_language;
+
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS notification_templates;
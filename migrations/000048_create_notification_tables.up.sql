@@ -0,0 +1,100 @@
+-- Create notification_templates table
+CREATE TABLE IF NOT EXISTS notification_templates (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    code VARCHAR(50) NOT NULL,
+    channel VARCHAR(10) NOT NULL,
+    language VARCHAR(5) NOT NULL,
+    subject VARCHAR(255),
+    body TEXT NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    
+    -- Audit fields
+    created_by BIGINT UNSIGNED,
+    updated_by BIGINT UNSIGNED,
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    
+    -- Indexes
+    UNIQUE INDEX idx_notification_templates_code_channel_language (code, channel, language),
+    INDEX idx_notification_templates_deleted_at (deleted_at),
+    
+    -- Foreign Keys
+    FOREIGN KEY (created_by) REFERENCES users(id),
+    FOREIGN KEY (updated_by) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create notifications table
+CREATE TABLE IF NOT EXISTS notifications (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    channel VARCHAR(10) NOT NULL,
+    recipient VARCHAR(100) NOT NULL,
+    template_code VARCHAR(50) NOT NULL,
+    language VARCHAR(5) NOT NULL,
+    subject VARCHAR(255),
+    body TEXT NOT NULL,
+    data TEXT,
+    
+    -- Related records
+    patient_id BIGINT UNSIGNED,
+    appointment_id BIGINT UNSIGNED,
+    dedupe_key VARCHAR(100) UNIQUE,
+    
+    -- Delivery
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    attempts INT NOT NULL DEFAULT 0,
+    max_attempts INT NOT NULL DEFAULT 5,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT,
+    provider_ref VARCHAR(100),
+    sent_at TIMESTAMP NULL,
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    
+    -- Indexes
+    INDEX idx_notifications_channel (channel),
+    INDEX idx_notifications_template_code (template_code),
+    INDEX idx_notifications_patient_id (patient_id),
+    INDEX idx_notifications_appointment_id (appointment_id),
+    INDEX idx_notifications_status (status),
+    INDEX idx_notifications_due (status, next_attempt_at),
+    
+    -- Foreign Keys
+    FOREIGN KEY (patient_id) REFERENCES patients(id),
+    FOREIGN KEY (appointment_id) REFERENCES appointments(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Notification language preference
+ALTER TABLE patients
+    ADD COLUMN preferred_language VARCHAR(5) NOT NULL DEFAULT 'vi' AFTER country;
+
+-- Seed default appointment templates (SMS in Vietnamese is unaccented to fit one message)
+INSERT INTO notification_templates (code, channel, language, subject, body) VALUES
+('APPOINTMENT_CONFIRMATION', 'SMS', 'vi', NULL,
+ '{{.FacilityName}}: Lich hen {{.AppointmentCode}} cua {{.PatientName}} voi BS {{.DoctorName}} luc {{.AppointmentTime}} ngay {{.AppointmentDate}} da duoc xac nhan.'),
+('APPOINTMENT_CONFIRMATION', 'SMS', 'en', NULL,
+ '{{.FacilityName}}: Appointment {{.AppointmentCode}} for {{.PatientName}} with Dr. {{.DoctorName}} at {{.AppointmentTime}} on {{.AppointmentDate}} is confirmed.'),
+('APPOINTMENT_CONFIRMATION', 'EMAIL', 'vi', 'Xác nhận lịch hẹn {{.AppointmentCode}}',
+ 'Kính gửi {{.PatientName}},\n\nLịch hẹn của Quý khách với BS {{.DoctorName}} lúc {{.AppointmentTime}} ngày {{.AppointmentDate}} đã được xác nhận.\nMã lịch hẹn: {{.AppointmentCode}}\n\nVui lòng đến trước 15 phút để làm thủ tục.\n\n{{.FacilityName}}'),
+('APPOINTMENT_CONFIRMATION', 'EMAIL', 'en', 'Appointment {{.AppointmentCode}} confirmed',
+ 'Dear {{.PatientName}},\n\nYour appointment with Dr. {{.DoctorName}} at {{.AppointmentTime}} on {{.AppointmentDate}} is confirmed.\nAppointment code: {{.AppointmentCode}}\n\nPlease arrive 15 minutes early to check in.\n\n{{.FacilityName}}'),
+('APPOINTMENT_CONFIRMATION', 'ZALO', 'vi', NULL,
+ 'Lịch hẹn {{.AppointmentCode}} của {{.PatientName}} với BS {{.DoctorName}} lúc {{.AppointmentTime}} ngày {{.AppointmentDate}} đã được xác nhận.'),
+('APPOINTMENT_CONFIRMATION', 'ZALO', 'en', NULL,
+ 'Appointment {{.AppointmentCode}} for {{.PatientName}} with Dr. {{.DoctorName}} at {{.AppointmentTime}} on {{.AppointmentDate}} is confirmed.'),
+('APPOINTMENT_REMINDER', 'SMS', 'vi', NULL,
+ '{{.FacilityName}}: Nhac lich hen {{.AppointmentCode}} cua {{.PatientName}} voi BS {{.DoctorName}} luc {{.AppointmentTime}} ngay {{.AppointmentDate}}. Vui long den truoc 15 phut.'),
+('APPOINTMENT_REMINDER', 'SMS', 'en', NULL,
+ '{{.FacilityName}}: Reminder of appointment {{.AppointmentCode}} for {{.PatientName}} with Dr. {{.DoctorName}} at {{.AppointmentTime}} on {{.AppointmentDate}}. Please arrive 15 minutes early.'),
+('APPOINTMENT_REMINDER', 'EMAIL', 'vi', 'Nhắc lịch hẹn {{.AppointmentCode}}',
+ 'Kính gửi {{.PatientName}},\n\nXin nhắc Quý khách lịch hẹn với BS {{.DoctorName}} lúc {{.AppointmentTime}} ngày {{.AppointmentDate}}.\nMã lịch hẹn: {{.AppointmentCode}}\n\nVui lòng đến trước 15 phút để làm thủ tục.\n\n{{.FacilityName}}'),
+('APPOINTMENT_REMINDER', 'EMAIL', 'en', 'Reminder: appointment {{.AppointmentCode}}',
+ 'Dear {{.PatientName}},\n\nThis is a reminder of your appointment with Dr. {{.DoctorName}} at {{.AppointmentTime}} on {{.AppointmentDate}}.\nAppointment code: {{.AppointmentCode}}\n\nPlease arrive 15 minutes early to check in.\n\n{{.FacilityName}}'),
+('APPOINTMENT_REMINDER', 'ZALO', 'vi', NULL,
+ 'Nhắc lịch hẹn {{.AppointmentCode}} của {{.PatientName}} với BS {{.DoctorName}} lúc {{.AppointmentTime}} ngày {{.AppointmentDate}}.'),
+('APPOINTMENT_REMINDER', 'ZALO', 'en', NULL,
+ 'Reminder of appointment {{.AppointmentCode}} for {{.PatientName}} with Dr. {{.DoctorName}} at {{.AppointmentTime}} on {{.AppointmentDate}}.');
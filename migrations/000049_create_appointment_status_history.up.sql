@@ -0,0 +1,24 @@
+-- Create appointment_status_history table
+CREATE TABLE IF NOT EXISTS appointment_status_history (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    appointment_id BIGINT UNSIGNED NOT NULL,
+    
+    -- Transition
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    reason TEXT,
+    
+    -- Who and when (changed_by is NULL for patient and system changes)
+    changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    changed_by BIGINT UNSIGNED,
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    
+    -- Indexes
+    INDEX idx_appointment_status_history_appointment_id (appointment_id),
+    
+    -- Foreign Keys
+    FOREIGN KEY (appointment_id) REFERENCES appointments(id),
+    FOREIGN KEY (changed_by) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,7 @@
+ALTER TABLE imaging_requests
+    DROP COLUMN duration_minutes;
+
+DROP TABLE IF EXISTS resource_bookings;
+DROP TABLE IF EXISTS resource_downtimes;
+DROP TABLE IF EXISTS resource_hours;
+DROP TABLE IF EXISTS resources;
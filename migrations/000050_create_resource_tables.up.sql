@@ -0,0 +1,107 @@
+-- Create resources table (rooms and equipment reserved by appointments and imaging)
+CREATE TABLE IF NOT EXISTS resources (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    code VARCHAR(20) NOT NULL UNIQUE,
+    name VARCHAR(200) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    category VARCHAR(50),
+    location VARCHAR(100),
+    department_id BIGINT UNSIGNED,
+    notes TEXT,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    
+    -- Audit fields
+    created_by BIGINT UNSIGNED,
+    updated_by BIGINT UNSIGNED,
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    
+    -- Indexes
+    INDEX idx_resources_type (type),
+    INDEX idx_resources_category (category),
+    INDEX idx_resources_department_id (department_id),
+    INDEX idx_resources_is_active (is_active),
+    INDEX idx_resources_deleted_at (deleted_at),
+    
+    -- Foreign Keys
+    FOREIGN KEY (department_id) REFERENCES departments(id),
+    FOREIGN KEY (created_by) REFERENCES users(id),
+    FOREIGN KEY (updated_by) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create resource_hours table (weekly opening hours; none means always open)
+CREATE TABLE IF NOT EXISTS resource_hours (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    resource_id BIGINT UNSIGNED NOT NULL,
+    day_of_week TINYINT NOT NULL,
+    start_time VARCHAR(5) NOT NULL,
+    end_time VARCHAR(5) NOT NULL,
+    
+    -- Indexes
+    INDEX idx_resource_hours_resource_id (resource_id),
+    
+    -- Foreign Keys
+    FOREIGN KEY (resource_id) REFERENCES resources(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create resource_downtimes table
+CREATE TABLE IF NOT EXISTS resource_downtimes (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    resource_id BIGINT UNSIGNED NOT NULL,
+    start_at TIMESTAMP NOT NULL,
+    end_at TIMESTAMP NOT NULL,
+    reason VARCHAR(255),
+    
+    -- Audit fields
+    created_by BIGINT UNSIGNED,
+    updated_by BIGINT UNSIGNED,
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    
+    -- Indexes
+    INDEX idx_resource_downtimes_resource_period (resource_id, start_at, end_at),
+    INDEX idx_resource_downtimes_deleted_at (deleted_at),
+    
+    -- Foreign Keys
+    FOREIGN KEY (resource_id) REFERENCES resources(id),
+    FOREIGN KEY (created_by) REFERENCES users(id),
+    FOREIGN KEY (updated_by) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create resource_bookings table
+CREATE TABLE IF NOT EXISTS resource_bookings (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    resource_id BIGINT UNSIGNED NOT NULL,
+    appointment_id BIGINT UNSIGNED,
+    imaging_request_id BIGINT UNSIGNED,
+    start_at TIMESTAMP NOT NULL,
+    end_at TIMESTAMP NOT NULL,
+    
+    -- Audit fields
+    created_by BIGINT UNSIGNED,
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    
+    -- Indexes
+    INDEX idx_resource_bookings_resource_period (resource_id, start_at, end_at),
+    INDEX idx_resource_bookings_appointment_id (appointment_id),
+    INDEX idx_resource_bookings_imaging_request_id (imaging_request_id),
+    
+    -- Foreign Keys
+    FOREIGN KEY (resource_id) REFERENCES resources(id),
+    FOREIGN KEY (appointment_id) REFERENCES appointments(id) ON DELETE CASCADE,
+    FOREIGN KEY (imaging_request_id) REFERENCES imaging_requests(id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Length of a scheduled imaging slot, used to reserve its resources
+ALTER TABLE imaging_requests
+    ADD COLUMN duration_minutes INT NOT NULL DEFAULT 30 AFTER scheduled_date;
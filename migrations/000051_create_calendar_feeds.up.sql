@@ -0,0 +1,31 @@
+-- Create calendar_feeds table (secret iCalendar feed URLs of users)
+CREATE TABLE IF NOT EXISTS calendar_feeds (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT UNSIGNED NOT NULL,
+    
+    -- Token (only its SHA-256 is stored)
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    
+    -- Usage
+    last_accessed_at TIMESTAMP NULL,
+    
+    -- Revocation
+    revoked_at TIMESTAMP NULL,
+    revoked_by BIGINT UNSIGNED,
+    
+    -- Audit fields
+    created_by BIGINT UNSIGNED,
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    
+    -- Indexes
+    INDEX idx_calendar_feeds_user_id (user_id),
+    INDEX idx_calendar_feeds_revoked_at (revoked_at),
+    
+    -- Foreign Keys
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (revoked_by) REFERENCES users(id),
+    FOREIGN KEY (created_by) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
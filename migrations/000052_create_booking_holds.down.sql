@@ -0,0 +1,6 @@
+ALTER TABLE patients
+    DROP INDEX idx_patients_pre_registered,
+    DROP COLUMN pre_registered,
+    MODIFY created_by BIGINT UNSIGNED NOT NULL;
+
+DROP TABLE IF EXISTS booking_holds;
@@ -0,0 +1,54 @@
+-- Create booking_holds table (slots held during public online booking)
+CREATE TABLE IF NOT EXISTS booking_holds (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    
+    -- Token (only its SHA-256 is stored)
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    
+    -- Held slot
+    doctor_id BIGINT UNSIGNED NOT NULL,
+    appointment_date DATE NOT NULL,
+    appointment_time TIME NOT NULL,
+    duration_minutes INT NOT NULL,
+    
+    -- Requester
+    phone_number VARCHAR(20) NOT NULL,
+    client_ip VARCHAR(45) NOT NULL,
+    
+    -- Phone verification
+    code_hash VARCHAR(255),
+    code_sent_at TIMESTAMP NULL,
+    codes_sent INT NOT NULL DEFAULT 0,
+    attempts INT NOT NULL DEFAULT 0,
+    verified_at TIMESTAMP NULL,
+    
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    expires_at TIMESTAMP NOT NULL,
+    
+    -- Result
+    patient_id BIGINT UNSIGNED NULL,
+    appointment_id BIGINT UNSIGNED NULL,
+    confirmed_at TIMESTAMP NULL,
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    
+    -- Indexes
+    INDEX idx_booking_holds_doctor_date (doctor_id, appointment_date, status),
+    INDEX idx_booking_holds_phone_number (phone_number, created_at),
+    INDEX idx_booking_holds_client_ip (client_ip, created_at),
+    INDEX idx_booking_holds_expires_at (expires_at),
+    INDEX idx_booking_holds_appointment_id (appointment_id),
+    
+    -- Foreign Keys
+    FOREIGN KEY (doctor_id) REFERENCES users(id),
+    FOREIGN KEY (patient_id) REFERENCES patients(id),
+    FOREIGN KEY (appointment_id) REFERENCES appointments(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Patients pre-registered through online booking have no creating user
+ALTER TABLE patients
+    MODIFY created_by BIGINT UNSIGNED NULL,
+    ADD COLUMN pre_registered BOOLEAN NOT NULL DEFAULT FALSE AFTER is_active,
+    ADD INDEX idx_patients_pre_registered (pre_registered);
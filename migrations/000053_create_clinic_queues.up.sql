@@ -0,0 +1,79 @@
+-- Create clinic_queues table (waiting lines of examination rooms and doctors)
+CREATE TABLE IF NOT EXISTS clinic_queues (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    code VARCHAR(20) NOT NULL UNIQUE,
+    name VARCHAR(200) NOT NULL,
+    ticket_prefix VARCHAR(5),
+    
+    -- Served doctor, room called to and department
+    doctor_id BIGINT UNSIGNED NULL,
+    room_id BIGINT UNSIGNED NULL,
+    department_id BIGINT UNSIGNED NULL,
+    
+    is_active BOOLEAN DEFAULT TRUE,
+    
+    -- Audit fields
+    created_by BIGINT UNSIGNED,
+    updated_by BIGINT UNSIGNED,
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    
+    -- Indexes
+    INDEX idx_clinic_queues_doctor_id (doctor_id),
+    INDEX idx_clinic_queues_room_id (room_id),
+    INDEX idx_clinic_queues_department_id (department_id),
+    INDEX idx_clinic_queues_is_active (is_active),
+    INDEX idx_clinic_queues_deleted_at (deleted_at),
+    
+    -- Foreign Keys
+    FOREIGN KEY (doctor_id) REFERENCES users(id),
+    FOREIGN KEY (room_id) REFERENCES resources(id),
+    FOREIGN KEY (department_id) REFERENCES departments(id),
+    FOREIGN KEY (created_by) REFERENCES users(id),
+    FOREIGN KEY (updated_by) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create queue_tickets table (daily numbered places in a queue)
+CREATE TABLE IF NOT EXISTS queue_tickets (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    queue_id BIGINT UNSIGNED NOT NULL,
+    queue_date DATE NOT NULL,
+    ticket_number INT NOT NULL,
+    ticket_code VARCHAR(10) NOT NULL,
+    
+    visit_id BIGINT UNSIGNED NULL,
+    patient_id BIGINT UNSIGNED NOT NULL,
+    
+    lane VARCHAR(20) NOT NULL DEFAULT 'NORMAL',
+    status VARCHAR(20) NOT NULL DEFAULT 'WAITING',
+    
+    -- Calls
+    called_at TIMESTAMP NULL,
+    called_by BIGINT UNSIGNED NULL,
+    call_count INT NOT NULL DEFAULT 0,
+    skipped_at TIMESTAMP NULL,
+    served_at TIMESTAMP NULL,
+    
+    -- Audit fields
+    issued_by BIGINT UNSIGNED,
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    
+    -- Indexes
+    UNIQUE INDEX idx_queue_tickets_number (queue_id, queue_date, ticket_number),
+    INDEX idx_queue_tickets_visit_id (visit_id),
+    INDEX idx_queue_tickets_patient_id (patient_id),
+    INDEX idx_queue_tickets_status (status),
+    
+    -- Foreign Keys
+    FOREIGN KEY (queue_id) REFERENCES clinic_queues(id),
+    FOREIGN KEY (visit_id) REFERENCES visits(id),
+    FOREIGN KEY (patient_id) REFERENCES patients(id),
+    FOREIGN KEY (called_by) REFERENCES users(id),
+    FOREIGN KEY (issued_by) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
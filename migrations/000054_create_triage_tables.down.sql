@@ -0,0 +1,10 @@
+ALTER TABLE queue_tickets
+    DROP COLUMN acuity_level;
+
+ALTER TABLE visits
+    DROP INDEX idx_visits_acuity_level,
+    DROP COLUMN triaged_at,
+    DROP COLUMN acuity_level;
+
+DROP TABLE IF EXISTS visit_triages;
+DROP TABLE IF EXISTS triage_rules;
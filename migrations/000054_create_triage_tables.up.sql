@@ -0,0 +1,155 @@
+-- Create triage_rules table (configurable ESI and Manchester rulesets)
+CREATE TABLE IF NOT EXISTS triage_rules (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    scale VARCHAR(10) NOT NULL,
+    name VARCHAR(200) NOT NULL,
+    
+    -- NULL matches any complaint
+    complaint_category VARCHAR(30) NULL,
+    
+    -- Finding compared to the threshold; COMPLAINT rules match on the category alone
+    parameter VARCHAR(30) NOT NULL,
+    operator VARCHAR(5) NULL,
+    threshold DECIMAL(10,2) NOT NULL DEFAULT 0,
+    acuity_level TINYINT NOT NULL,
+    
+    is_active BOOLEAN DEFAULT TRUE,
+    
+    -- Audit fields
+    created_by BIGINT UNSIGNED NULL,
+    updated_by BIGINT UNSIGNED NULL,
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    
+    -- Indexes
+    INDEX idx_triage_rules_scale (scale, is_active),
+    INDEX idx_triage_rules_deleted_at (deleted_at),
+    
+    -- Foreign Keys
+    FOREIGN KEY (created_by) REFERENCES users(id),
+    FOREIGN KEY (updated_by) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create visit_triages table (triage assessments, one per triage or re-triage)
+CREATE TABLE IF NOT EXISTS visit_triages (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    visit_id BIGINT UNSIGNED NOT NULL,
+    patient_id BIGINT UNSIGNED NOT NULL,
+    sequence INT NOT NULL,
+    
+    triaged_by BIGINT UNSIGNED NOT NULL,
+    triaged_at TIMESTAMP NOT NULL,
+    
+    -- Presentation
+    complaint_category VARCHAR(30) NOT NULL,
+    complaint_notes TEXT,
+    pain_score TINYINT NULL,
+    consciousness VARCHAR(20) NULL,
+    
+    -- Vital signs, NULL when not measured
+    temperature DECIMAL(4,1) NULL,
+    blood_pressure_systolic INT NULL,
+    blood_pressure_diastolic INT NULL,
+    heart_rate INT NULL,
+    respiratory_rate INT NULL,
+    oxygen_saturation INT NULL,
+    
+    -- Acuity
+    scale VARCHAR(10) NOT NULL,
+    computed_level TINYINT NOT NULL,
+    acuity_level TINYINT NOT NULL,
+    override_reason TEXT,
+    matched_rules TEXT,
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    
+    -- Indexes
+    UNIQUE INDEX idx_visit_triages_sequence (visit_id, sequence),
+    INDEX idx_visit_triages_patient_id (patient_id),
+    
+    -- Foreign Keys
+    FOREIGN KEY (visit_id) REFERENCES visits(id),
+    FOREIGN KEY (patient_id) REFERENCES patients(id),
+    FOREIGN KEY (triaged_by) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Latest acuity level on visits and their queue tickets
+ALTER TABLE visits
+    ADD COLUMN acuity_level TINYINT NULL AFTER symptoms,
+    ADD COLUMN triaged_at TIMESTAMP NULL AFTER acuity_level,
+    ADD INDEX idx_visits_acuity_level (acuity_level);
+
+ALTER TABLE queue_tickets
+    ADD COLUMN acuity_level TINYINT NULL AFTER lane;
+
+-- Emergency Severity Index: level 1 needs immediate life-saving care, level 2
+-- covers high-risk presentations, confusion, severe pain and danger-zone vital signs
+INSERT INTO triage_rules (scale, name, complaint_category, parameter, operator, threshold, acuity_level, is_active, created_at, updated_at) VALUES
+('ESI', 'Cardiac arrest', 'CARDIAC_ARREST', 'COMPLAINT', NULL, 0, 1, TRUE, NOW(), NOW()),
+('ESI', 'Unresponsive or responds to pain only', NULL, 'CONSCIOUSNESS', 'GTE', 3, 1, TRUE, NOW(), NOW()),
+('ESI', 'SpO2 below 90%', NULL, 'OXYGEN_SATURATION', 'LT', 90, 1, TRUE, NOW(), NOW()),
+('ESI', 'Respiratory rate below 10', NULL, 'RESPIRATORY_RATE', 'LT', 10, 1, TRUE, NOW(), NOW()),
+('ESI', 'Systolic blood pressure below 80 mmHg', NULL, 'SYSTOLIC_BP', 'LT', 80, 1, TRUE, NOW(), NOW()),
+('ESI', 'Heart rate below 40', NULL, 'HEART_RATE', 'LT', 40, 1, TRUE, NOW(), NOW()),
+('ESI', 'Chest pain', 'CHEST_PAIN', 'COMPLAINT', NULL, 0, 2, TRUE, NOW(), NOW()),
+('ESI', 'Stroke symptoms', 'STROKE_SYMPTOMS', 'COMPLAINT', NULL, 0, 2, TRUE, NOW(), NOW()),
+('ESI', 'Altered consciousness', 'ALTERED_CONSCIOUSNESS', 'COMPLAINT', NULL, 0, 2, TRUE, NOW(), NOW()),
+('ESI', 'Seizure', 'SEIZURE', 'COMPLAINT', NULL, 0, 2, TRUE, NOW(), NOW()),
+('ESI', 'Major trauma', 'MAJOR_TRAUMA', 'COMPLAINT', NULL, 0, 2, TRUE, NOW(), NOW()),
+('ESI', 'Poisoning or overdose', 'POISONING', 'COMPLAINT', NULL, 0, 2, TRUE, NOW(), NOW()),
+('ESI', 'New confusion or responds to voice only', NULL, 'CONSCIOUSNESS', 'GTE', 1, 2, TRUE, NOW(), NOW()),
+('ESI', 'Severe pain (7/10 or more)', NULL, 'PAIN_SCORE', 'GTE', 7, 2, TRUE, NOW(), NOW()),
+('ESI', 'Heart rate above 100', NULL, 'HEART_RATE', 'GT', 100, 2, TRUE, NOW(), NOW()),
+('ESI', 'Respiratory rate above 20', NULL, 'RESPIRATORY_RATE', 'GT', 20, 2, TRUE, NOW(), NOW()),
+('ESI', 'SpO2 below 92%', NULL, 'OXYGEN_SATURATION', 'LT', 92, 2, TRUE, NOW(), NOW()),
+('ESI', 'Shortness of breath', 'SHORTNESS_OF_BREATH', 'COMPLAINT', NULL, 0, 3, TRUE, NOW(), NOW()),
+('ESI', 'Abdominal pain', 'ABDOMINAL_PAIN', 'COMPLAINT', NULL, 0, 3, TRUE, NOW(), NOW()),
+('ESI', 'Head injury', 'HEAD_INJURY', 'COMPLAINT', NULL, 0, 3, TRUE, NOW(), NOW()),
+('ESI', 'Bleeding', 'BLEEDING', 'COMPLAINT', NULL, 0, 3, TRUE, NOW(), NOW()),
+('ESI', 'Allergic reaction', 'ALLERGIC_REACTION', 'COMPLAINT', NULL, 0, 3, TRUE, NOW(), NOW()),
+('ESI', 'Burn', 'BURN', 'COMPLAINT', NULL, 0, 3, TRUE, NOW(), NOW()),
+('ESI', 'Pregnancy related', 'PREGNANCY', 'COMPLAINT', NULL, 0, 3, TRUE, NOW(), NOW()),
+('ESI', 'Mental health', 'MENTAL_HEALTH', 'COMPLAINT', NULL, 0, 3, TRUE, NOW(), NOW()),
+('ESI', 'Moderate pain (4/10 or more)', NULL, 'PAIN_SCORE', 'GTE', 4, 3, TRUE, NOW(), NOW()),
+('ESI', 'Temperature 38.5 °C or more', NULL, 'TEMPERATURE', 'GTE', 38.5, 3, TRUE, NOW(), NOW()),
+('ESI', 'Minor injury', 'MINOR_INJURY', 'COMPLAINT', NULL, 0, 4, TRUE, NOW(), NOW()),
+('ESI', 'Fever', 'FEVER', 'COMPLAINT', NULL, 0, 4, TRUE, NOW(), NOW()),
+('ESI', 'Mild pain', NULL, 'PAIN_SCORE', 'GTE', 1, 4, TRUE, NOW(), NOW());
+
+-- Manchester Triage System: red (1), orange (2), yellow (3), green (4) and blue (5)
+-- from general discriminators and presentations
+INSERT INTO triage_rules (scale, name, complaint_category, parameter, operator, threshold, acuity_level, is_active, created_at, updated_at) VALUES
+('MTS', 'Cardiac arrest', 'CARDIAC_ARREST', 'COMPLAINT', NULL, 0, 1, TRUE, NOW(), NOW()),
+('MTS', 'Unresponsive child or adult', NULL, 'CONSCIOUSNESS', 'GTE', 3, 1, TRUE, NOW(), NOW()),
+('MTS', 'Very low SpO2 (below 90%)', NULL, 'OXYGEN_SATURATION', 'LT', 90, 1, TRUE, NOW(), NOW()),
+('MTS', 'Inadequate breathing (rate below 10)', NULL, 'RESPIRATORY_RATE', 'LT', 10, 1, TRUE, NOW(), NOW()),
+('MTS', 'Shock (systolic below 80 mmHg)', NULL, 'SYSTOLIC_BP', 'LT', 80, 1, TRUE, NOW(), NOW()),
+('MTS', 'Chest pain', 'CHEST_PAIN', 'COMPLAINT', NULL, 0, 2, TRUE, NOW(), NOW()),
+('MTS', 'Acute neurological deficit', 'STROKE_SYMPTOMS', 'COMPLAINT', NULL, 0, 2, TRUE, NOW(), NOW()),
+('MTS', 'Fits', 'SEIZURE', 'COMPLAINT', NULL, 0, 2, TRUE, NOW(), NOW()),
+('MTS', 'Significant mechanism of injury', 'MAJOR_TRAUMA', 'COMPLAINT', NULL, 0, 2, TRUE, NOW(), NOW()),
+('MTS', 'Altered conscious level', 'ALTERED_CONSCIOUSNESS', 'COMPLAINT', NULL, 0, 2, TRUE, NOW(), NOW()),
+('MTS', 'Overdose and poisoning', 'POISONING', 'COMPLAINT', NULL, 0, 2, TRUE, NOW(), NOW()),
+('MTS', 'Altered conscious level (ACVPU)', NULL, 'CONSCIOUSNESS', 'GTE', 1, 2, TRUE, NOW(), NOW()),
+('MTS', 'Severe pain (8/10 or more)', NULL, 'PAIN_SCORE', 'GTE', 8, 2, TRUE, NOW(), NOW()),
+('MTS', 'Low SpO2 (below 95%)', NULL, 'OXYGEN_SATURATION', 'LT', 95, 2, TRUE, NOW(), NOW()),
+('MTS', 'Very hot (41 °C or more)', NULL, 'TEMPERATURE', 'GTE', 41, 2, TRUE, NOW(), NOW()),
+('MTS', 'Shortness of breath', 'SHORTNESS_OF_BREATH', 'COMPLAINT', NULL, 0, 3, TRUE, NOW(), NOW()),
+('MTS', 'Abdominal pain', 'ABDOMINAL_PAIN', 'COMPLAINT', NULL, 0, 3, TRUE, NOW(), NOW()),
+('MTS', 'Head injury', 'HEAD_INJURY', 'COMPLAINT', NULL, 0, 3, TRUE, NOW(), NOW()),
+('MTS', 'Uncontrollable minor haemorrhage', 'BLEEDING', 'COMPLAINT', NULL, 0, 3, TRUE, NOW(), NOW()),
+('MTS', 'Allergy', 'ALLERGIC_REACTION', 'COMPLAINT', NULL, 0, 3, TRUE, NOW(), NOW()),
+('MTS', 'Burns and scalds', 'BURN', 'COMPLAINT', NULL, 0, 3, TRUE, NOW(), NOW()),
+('MTS', 'Pregnancy', 'PREGNANCY', 'COMPLAINT', NULL, 0, 3, TRUE, NOW(), NOW()),
+('MTS', 'Mental illness', 'MENTAL_HEALTH', 'COMPLAINT', NULL, 0, 3, TRUE, NOW(), NOW()),
+('MTS', 'Moderate pain (5/10 or more)', NULL, 'PAIN_SCORE', 'GTE', 5, 3, TRUE, NOW(), NOW()),
+('MTS', 'Hot (38.5 °C or more)', NULL, 'TEMPERATURE', 'GTE', 38.5, 3, TRUE, NOW(), NOW()),
+('MTS', 'Limb problems', 'MINOR_INJURY', 'COMPLAINT', NULL, 0, 4, TRUE, NOW(), NOW()),
+('MTS', 'Unwell adult or child with fever', 'FEVER', 'COMPLAINT', NULL, 0, 4, TRUE, NOW(), NOW()),
+('MTS', 'Recent mild pain', NULL, 'PAIN_SCORE', 'GTE', 1, 4, TRUE, NOW(), NOW()),
+('MTS', 'Warm (37.5 °C or more)', NULL, 'TEMPERATURE', 'GTE', 37.5, 4, TRUE, NOW(), NOW());
@@ -0,0 +1,11 @@
+ALTER TABLE visits
+    DROP INDEX idx_visits_checked_in_at,
+    DROP COLUMN discharged_at,
+    DROP COLUMN consultation_ended_at,
+    DROP COLUMN paused_seconds,
+    DROP COLUMN pause_reason,
+    DROP COLUMN paused_at,
+    DROP COLUMN consultation_started_at,
+    DROP COLUMN checked_in_at;
+
+DROP TABLE IF EXISTS visit_status_history;
@@ -0,0 +1,38 @@
+-- Create visit_status_history table (one row per visit status change)
+CREATE TABLE IF NOT EXISTS visit_status_history (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    visit_id BIGINT UNSIGNED NOT NULL,
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    reason TEXT,
+    
+    changed_at TIMESTAMP NOT NULL,
+    changed_by BIGINT UNSIGNED NOT NULL,
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    
+    -- Indexes
+    INDEX idx_visit_status_history_visit_id (visit_id, changed_at),
+    
+    -- Foreign Keys
+    FOREIGN KEY (visit_id) REFERENCES visits(id),
+    FOREIGN KEY (changed_by) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Stage timestamps on visits
+ALTER TABLE visits
+    ADD COLUMN checked_in_at TIMESTAMP NULL AFTER triaged_at,
+    ADD COLUMN consultation_started_at TIMESTAMP NULL AFTER checked_in_at,
+    ADD COLUMN paused_at TIMESTAMP NULL AFTER consultation_started_at,
+    ADD COLUMN pause_reason VARCHAR(20) NULL AFTER paused_at,
+    ADD COLUMN paused_seconds INT NOT NULL DEFAULT 0 AFTER pause_reason,
+    ADD COLUMN consultation_ended_at TIMESTAMP NULL AFTER paused_seconds,
+    ADD COLUMN discharged_at TIMESTAMP NULL AFTER consultation_ended_at;
+
+-- Existing visits were checked in when they were created
+UPDATE visits SET checked_in_at = created_at WHERE checked_in_at IS NULL;
+
+ALTER TABLE visits
+    MODIFY COLUMN checked_in_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ADD INDEX idx_visits_checked_in_at (checked_in_at);
@@ -0,0 +1,7 @@
+DELETE FROM notification_templates WHERE code = 'EARLY_WARNING_ESCALATION';
+
+DROP TABLE IF EXISTS early_warning_scores;
+
+ALTER TABLE visits
+    DROP COLUMN consciousness,
+    DROP COLUMN supplemental_oxygen;
@@ -0,0 +1,58 @@
+-- Supplemental oxygen and level of consciousness, scored by NEWS2
+ALTER TABLE visits
+    ADD COLUMN supplemental_oxygen BOOLEAN NULL AFTER bmi,
+    ADD COLUMN consciousness VARCHAR(20) NULL AFTER supplemental_oxygen;
+
+-- Create early_warning_scores table (NEWS2 trend per admission, one per nursing note)
+CREATE TABLE IF NOT EXISTS early_warning_scores (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    admission_id BIGINT UNSIGNED NOT NULL,
+    patient_id BIGINT UNSIGNED NOT NULL,
+    nursing_note_id BIGINT UNSIGNED NULL,
+    
+    recorded_at TIMESTAMP NOT NULL,
+    recorded_by BIGINT UNSIGNED NOT NULL,
+    
+    -- Points per parameter, NULL when not measured
+    respiratory_rate_points TINYINT NULL,
+    oxygen_saturation_points TINYINT NULL,
+    supplemental_oxygen_points TINYINT NULL,
+    systolic_bp_points TINYINT NULL,
+    heart_rate_points TINYINT NULL,
+    consciousness_points TINYINT NULL,
+    temperature_points TINYINT NULL,
+    
+    total TINYINT NOT NULL,
+    complete BOOLEAN NOT NULL DEFAULT FALSE,
+    red_score BOOLEAN NOT NULL DEFAULT FALSE,
+    risk VARCHAR(20) NOT NULL,
+    
+    -- Escalation
+    escalation_level VARCHAR(20) NOT NULL DEFAULT 'NONE',
+    escalated BOOLEAN NOT NULL DEFAULT FALSE,
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    
+    -- Indexes
+    INDEX idx_early_warning_scores_admission (admission_id, recorded_at),
+    INDEX idx_early_warning_scores_patient_id (patient_id),
+    UNIQUE INDEX idx_early_warning_scores_nursing_note_id (nursing_note_id),
+    
+    -- Foreign Keys
+    FOREIGN KEY (admission_id) REFERENCES admissions(id),
+    FOREIGN KEY (patient_id) REFERENCES patients(id),
+    FOREIGN KEY (nursing_note_id) REFERENCES nursing_notes(id),
+    FOREIGN KEY (recorded_by) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Alerts to the attending doctor when an inpatient's escalation level rises
+INSERT INTO notification_templates (code, channel, language, subject, body) VALUES
+('EARLY_WARNING_ESCALATION', 'SMS', 'vi', NULL,
+ '{{.FacilityName}}: Canh bao NEWS2 {{.Score}} ({{.EscalationLevel}}) - BN {{.PatientName}}, {{.AdmissionCode}}, giuong {{.Bed}} luc {{.RecordedAt}}. De nghi danh gia ngay.'),
+('EARLY_WARNING_ESCALATION', 'SMS', 'en', NULL,
+ '{{.FacilityName}}: NEWS2 alert {{.Score}} ({{.EscalationLevel}}) - {{.PatientName}}, {{.AdmissionCode}}, bed {{.Bed}} at {{.RecordedAt}}. Please review now.'),
+('EARLY_WARNING_ESCALATION', 'EMAIL', 'vi', 'Cảnh báo NEWS2 {{.Score}}: {{.PatientName}} ({{.AdmissionCode}})',
+ 'Kính gửi BS {{.DoctorName}},\n\nĐiểm NEWS2 của bệnh nhân {{.PatientName}} (mã nhập viện {{.AdmissionCode}}, giường {{.Bed}}) là {{.Score}} lúc {{.RecordedAt}}, mức nguy cơ {{.Risk}}.\nMức báo động: {{.EscalationLevel}}.\n\nĐề nghị đánh giá bệnh nhân ngay.\n\n{{.FacilityName}}'),
+('EARLY_WARNING_ESCALATION', 'EMAIL', 'en', 'NEWS2 alert {{.Score}}: {{.PatientName}} ({{.AdmissionCode}})',
+ 'Dear Dr. {{.DoctorName}},\n\nThe NEWS2 score of {{.PatientName}} (admission {{.AdmissionCode}}, bed {{.Bed}}) was {{.Score}} at {{.RecordedAt}}, {{.Risk}} clinical risk.\nEscalation: {{.EscalationLevel}}.\n\nPlease review the patient now.\n\n{{.FacilityName}}');
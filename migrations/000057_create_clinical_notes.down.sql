@@ -0,0 +1,7 @@
+-- Role permissions are removed with the role
+DELETE FROM roles WHERE code = 'RESIDENT';
+
+DROP TABLE IF EXISTS clinical_note_addenda;
+DROP TABLE IF EXISTS clinical_note_versions;
+DROP TABLE IF EXISTS clinical_notes;
+DROP TABLE IF EXISTS clinical_note_templates;
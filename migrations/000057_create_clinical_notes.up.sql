@@ -0,0 +1,135 @@
+-- Create clinical_note_templates table (default SOAP sections per note type and department)
+CREATE TABLE IF NOT EXISTS clinical_note_templates (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    code VARCHAR(50) NOT NULL,
+    name VARCHAR(200) NOT NULL,
+    department_id BIGINT UNSIGNED NULL,
+    note_type VARCHAR(20) NOT NULL,
+    
+    -- SOAP sections
+    subjective TEXT,
+    objective TEXT,
+    assessment TEXT,
+    plan TEXT,
+    
+    is_active BOOLEAN DEFAULT TRUE,
+    
+    -- Audit fields
+    created_by BIGINT UNSIGNED NOT NULL,
+    updated_by BIGINT UNSIGNED NOT NULL,
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    
+    -- Indexes
+    UNIQUE INDEX idx_clinical_note_templates_code (code),
+    INDEX idx_clinical_note_templates_department_id (department_id),
+    INDEX idx_clinical_note_templates_deleted_at (deleted_at),
+    
+    -- Foreign Keys
+    FOREIGN KEY (department_id) REFERENCES departments(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create clinical_notes table (SOAP notes per visit, locked once signed)
+CREATE TABLE IF NOT EXISTS clinical_notes (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    visit_id BIGINT UNSIGNED NOT NULL,
+    patient_id BIGINT UNSIGNED NOT NULL,
+    author_id BIGINT UNSIGNED NOT NULL,
+    department_id BIGINT UNSIGNED NULL,
+    template_id BIGINT UNSIGNED NULL,
+    
+    note_type VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'DRAFT',
+    version INT NOT NULL DEFAULT 1,
+    
+    -- SOAP sections
+    subjective TEXT,
+    objective TEXT,
+    assessment TEXT,
+    plan TEXT,
+    
+    -- Signing
+    signed_at TIMESTAMP NULL,
+    cosigner_id BIGINT UNSIGNED NULL,
+    cosigned_at TIMESTAMP NULL,
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    
+    -- Indexes
+    INDEX idx_clinical_notes_visit_id (visit_id),
+    INDEX idx_clinical_notes_patient_id (patient_id),
+    INDEX idx_clinical_notes_author_id (author_id),
+    INDEX idx_clinical_notes_department_id (department_id),
+    INDEX idx_clinical_notes_status (status),
+    INDEX idx_clinical_notes_cosigner_id (cosigner_id),
+    INDEX idx_clinical_notes_deleted_at (deleted_at),
+    
+    -- Foreign Keys
+    FOREIGN KEY (visit_id) REFERENCES visits(id),
+    FOREIGN KEY (patient_id) REFERENCES patients(id),
+    FOREIGN KEY (author_id) REFERENCES users(id),
+    FOREIGN KEY (department_id) REFERENCES departments(id),
+    FOREIGN KEY (template_id) REFERENCES clinical_note_templates(id),
+    FOREIGN KEY (cosigner_id) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create clinical_note_versions table (sections as saved at each version)
+CREATE TABLE IF NOT EXISTS clinical_note_versions (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    note_id BIGINT UNSIGNED NOT NULL,
+    version INT NOT NULL,
+    
+    -- SOAP sections
+    subjective TEXT,
+    objective TEXT,
+    assessment TEXT,
+    plan TEXT,
+    
+    edited_by BIGINT UNSIGNED NOT NULL,
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    
+    -- Indexes
+    UNIQUE INDEX idx_clinical_note_versions_version (note_id, version),
+    
+    -- Foreign Keys
+    FOREIGN KEY (note_id) REFERENCES clinical_notes(id) ON DELETE CASCADE,
+    FOREIGN KEY (edited_by) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create clinical_note_addenda table (appended to signed notes, never edited)
+CREATE TABLE IF NOT EXISTS clinical_note_addenda (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    note_id BIGINT UNSIGNED NOT NULL,
+    author_id BIGINT UNSIGNED NOT NULL,
+    content TEXT NOT NULL,
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    
+    -- Indexes
+    INDEX idx_clinical_note_addenda_note_id (note_id),
+    
+    -- Foreign Keys
+    FOREIGN KEY (note_id) REFERENCES clinical_notes(id),
+    FOREIGN KEY (author_id) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Residents work with the doctor permissions; their notes need co-signature
+INSERT INTO roles (name, code, description, is_active, created_at, updated_at) VALUES
+('Resident', 'RESIDENT', 'Doctor in training, clinical notes co-signed by a supervising doctor', true, NOW(), NOW());
+
+INSERT INTO role_permissions (role_id, permission_id, created_at)
+SELECT r.id, rp.permission_id, NOW()
+FROM roles r
+CROSS JOIN role_permissions rp
+JOIN roles d ON d.id = rp.role_id
+WHERE r.code = 'RESIDENT'
+AND d.code = 'DOCTOR';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS referral_attachments;
+DROP TABLE IF EXISTS referrals;
@@ -0,0 +1,85 @@
+-- Create referrals table (patients sent to another department, doctor or facility)
+CREATE TABLE IF NOT EXISTS referrals (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    referral_number VARCHAR(20) NOT NULL,
+    
+    -- Foreign Keys
+    visit_id BIGINT UNSIGNED NOT NULL,
+    patient_id BIGINT UNSIGNED NOT NULL,
+    referring_doctor_id BIGINT UNSIGNED NOT NULL,
+    
+    -- Target
+    referral_type VARCHAR(20) NOT NULL,
+    target_department_id BIGINT UNSIGNED NULL,
+    target_doctor_id BIGINT UNSIGNED NULL,
+    external_facility VARCHAR(200),
+    external_facility_code VARCHAR(20),
+    
+    -- Referral Details
+    urgency VARCHAR(20) NOT NULL DEFAULT 'ROUTINE',
+    reason TEXT NOT NULL,
+    clinical_summary TEXT,
+    treatment_given TEXT,
+    patient_condition TEXT,
+    treatment_direction TEXT,
+    transport VARCHAR(100),
+    escort VARCHAR(200),
+    
+    -- Status
+    status VARCHAR(20) NOT NULL DEFAULT 'SENT',
+    sent_at TIMESTAMP NOT NULL,
+    responded_by BIGINT UNSIGNED NULL,
+    responded_at TIMESTAMP NULL,
+    response_note TEXT,
+    appointment_id BIGINT UNSIGNED NULL,
+    completed_at TIMESTAMP NULL,
+    
+    -- Audit fields
+    created_by BIGINT UNSIGNED NOT NULL,
+    updated_by BIGINT UNSIGNED,
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    
+    -- Indexes
+    UNIQUE INDEX idx_referrals_referral_number (referral_number),
+    INDEX idx_referrals_visit_id (visit_id),
+    INDEX idx_referrals_patient_id (patient_id),
+    INDEX idx_referrals_referring_doctor_id (referring_doctor_id),
+    INDEX idx_referrals_target_department_id (target_department_id),
+    INDEX idx_referrals_target_doctor_id (target_doctor_id),
+    INDEX idx_referrals_status (status),
+    INDEX idx_referrals_sent_at (sent_at),
+    INDEX idx_referrals_appointment_id (appointment_id),
+    
+    -- Foreign Keys
+    FOREIGN KEY (visit_id) REFERENCES visits(id),
+    FOREIGN KEY (patient_id) REFERENCES patients(id),
+    FOREIGN KEY (referring_doctor_id) REFERENCES users(id),
+    FOREIGN KEY (target_department_id) REFERENCES departments(id),
+    FOREIGN KEY (target_doctor_id) REFERENCES users(id),
+    FOREIGN KEY (responded_by) REFERENCES users(id),
+    FOREIGN KEY (appointment_id) REFERENCES appointments(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create referral_attachments table (diagnoses and tests sent with a referral)
+CREATE TABLE IF NOT EXISTS referral_attachments (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    referral_id BIGINT UNSIGNED NOT NULL,
+    diagnosis_id BIGINT UNSIGNED NULL,
+    lab_test_request_id BIGINT UNSIGNED NULL,
+    imaging_request_id BIGINT UNSIGNED NULL,
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    
+    -- Indexes
+    INDEX idx_referral_attachments_referral_id (referral_id),
+    
+    -- Foreign Keys
+    FOREIGN KEY (referral_id) REFERENCES referrals(id) ON DELETE CASCADE,
+    FOREIGN KEY (diagnosis_id) REFERENCES diagnoses(id),
+    FOREIGN KEY (lab_test_request_id) REFERENCES lab_test_requests(id),
+    FOREIGN KEY (imaging_request_id) REFERENCES imaging_requests(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,47 @@
+-- Create follow_ups table (return visits ordered when a visit is completed)
+CREATE TABLE IF NOT EXISTS follow_ups (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    
+    -- Foreign Keys
+    visit_id BIGINT UNSIGNED NOT NULL,
+    patient_id BIGINT UNSIGNED NOT NULL,
+    doctor_id BIGINT UNSIGNED NOT NULL,
+    
+    -- Order
+    due_date DATE NOT NULL,
+    instructions TEXT,
+    
+    status VARCHAR(20) NOT NULL DEFAULT 'UNSCHEDULED',
+    appointment_id BIGINT UNSIGNED NULL,
+    confirmed_by BIGINT UNSIGNED NULL,
+    confirmed_at TIMESTAMP NULL,
+    
+    -- Outcome
+    attended_visit_id BIGINT UNSIGNED NULL,
+    resolved_at TIMESTAMP NULL,
+    cancel_reason TEXT,
+    
+    -- Audit fields
+    created_by BIGINT UNSIGNED,
+    updated_by BIGINT UNSIGNED,
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    
+    -- Indexes
+    UNIQUE INDEX idx_follow_ups_visit_id (visit_id),
+    INDEX idx_follow_ups_patient_id (patient_id),
+    INDEX idx_follow_ups_doctor_id (doctor_id),
+    INDEX idx_follow_ups_due_date (due_date),
+    INDEX idx_follow_ups_status (status),
+    INDEX idx_follow_ups_appointment_id (appointment_id),
+    
+    -- Foreign Keys
+    FOREIGN KEY (visit_id) REFERENCES visits(id),
+    FOREIGN KEY (patient_id) REFERENCES patients(id),
+    FOREIGN KEY (doctor_id) REFERENCES users(id),
+    FOREIGN KEY (appointment_id) REFERENCES appointments(id),
+    FOREIGN KEY (confirmed_by) REFERENCES users(id),
+    FOREIGN KEY (attended_visit_id) REFERENCES visits(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1 @@
+ALTER TABLE medications DROP COLUMN unit_price;
@@ -0,0 +1,2 @@
+-- Catalog price per dispensed unit, charged on the draft invoice of a completed visit
+ALTER TABLE medications ADD COLUMN unit_price DECIMAL(10,2) NOT NULL DEFAULT 0 AFTER manufacturer;